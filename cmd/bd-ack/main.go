@@ -6,6 +6,24 @@
 //	bd-ack <id> decline "reason"          Decline with reason (bounces, auto-escalates at 3)
 //	bd-ack <id> defer <other-agent>       Reassign to another agent
 //	bd-ack <id> impossible "reason"       Mark as impossible (immediate escalation)
+//	bd-ack <id> depends-on <other-id>     Record a blocking dependency (cycle-checked)
+//	bd-ack <id> requeue ["note"]          Resolve an escalation: back to the unassigned queue
+//	bd-ack <id> reassign <agent>          Resolve an escalation: hand to another agent
+//	bd-ack --enforce-deadlines            Re-queue or escalate assignments past their deadline
+//	bd-ack --batch < actions.jsonl        Apply many actions in one all-or-nothing write
+//
+// Writes hold an advisory lock on the beads directory. --expect-updated-at
+// and --expect-hash make a write conditional on the bead's version, and
+// --retries reloads and re-applies a write that raced with an unlocked writer.
+//
+// --batch reads one JSON object per line from stdin, e.g.
+//
+//	{"id": "bd-12", "action": "decline", "reason": "Outside my expertise"}
+//	{"id": "bd-13", "action": "defer", "to": "backend-agent"}
+//
+// and applies them all under one lock: if any item fails, none is written.
+// It prints one JSON result per input line, whose exit_code is the code the
+// item would have produced on its own.
 //
 // Environment:
 //
@@ -18,43 +36,89 @@
 //	1 - Usage error or invalid arguments
 //	2 - Issue not found
 //	3 - Action failed
+//	4 - Dependency would create a cycle
+//	5 - Conflict: the bead or beads file changed underneath the write
 package main
 
 import (
+	"bufio"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/Dicklesworthstone/beads_viewer/pkg/ack"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/mutate"
 )
 
 var (
-	jsonOutput bool
-	actor      string
+	jsonOutput      bool
+	actor           string
+	allowCycle      bool
+	expectUpdatedAt string
+	expectHash      string
+	retries         int
+	lockTimeout     time.Duration
+	enforce         bool
+	onExpiry        string
+	deadlineIn      time.Duration
+	batch           bool
 )
 
 func main() {
 	flag.BoolVar(&jsonOutput, "json", false, "Output in JSON format")
-	flag.StringVar(&actor, "actor", getDefaultActor(), "Actor name for audit trail")
+	flag.StringVar(&actor, "actor", mutate.DefaultActor(), "Actor name for audit trail")
+	flag.BoolVar(&allowCycle, "allow-cycle", false, "Allow depends-on to create a dependency cycle")
+	flag.StringVar(&expectUpdatedAt, "expect-updated-at", "", "Only write if the bead's updated_at still equals this RFC 3339 time")
+	flag.StringVar(&expectHash, "expect-hash", "", "Only write if the bead's content hash still equals this value")
+	flag.IntVar(&retries, "retries", 0, "Reload and re-apply up to N times if another writer changed the beads file mid-write")
+	flag.DurationVar(&lockTimeout, "lock-timeout", 0, "How long to wait for the beads write lock (default 10s)")
+	flag.BoolVar(&enforce, "enforce-deadlines", false, "Re-queue or escalate every assignment not acknowledged before its deadline")
+	flag.StringVar(&onExpiry, "on-expiry", "requeue", "What --enforce-deadlines does with a missed deadline: requeue or escalate")
+	flag.DurationVar(&deadlineIn, "deadline", 0, "With reassign: the new assignee must acknowledge within this duration")
+	flag.BoolVar(&batch, "batch", false, "Read JSON lines of {id, action, reason, to} from stdin and apply them all or none")
 	flag.Parse()
 
 	args := flag.Args()
-	if len(args) < 2 {
+	if !enforce && !batch && len(args) < 2 {
 		printUsage()
 		os.Exit(1)
 	}
 
-	issueID := args[0]
-	action := args[1]
-
 	// Get repo path (current working directory)
 	repoPath, err := os.Getwd()
 	if err != nil {
 		exitWithError("failed to get working directory", err, 3)
 	}
 
-	svc := ack.NewService(repoPath)
+	if enforce {
+		enforceDeadlines(repoPath)
+		return
+	}
+	if batch {
+		runBatch(repoPath)
+		return
+	}
+
+	issueID := args[0]
+	action := args[1]
+
+	opts := []mutate.StoreOption{mutate.WithRetries(retries), mutate.WithLockTimeout(lockTimeout)}
+	if expectUpdatedAt != "" || expectHash != "" {
+		var expect mutate.Version
+		if expectUpdatedAt != "" {
+			expect.UpdatedAt, err = time.Parse(time.RFC3339Nano, expectUpdatedAt)
+			if err != nil {
+				exitWithError("invalid --expect-updated-at", err, 1)
+			}
+		}
+		expect.ContentHash = expectHash
+		opts = append(opts, mutate.WithExpect(expect))
+	}
+	svc := ack.NewService(repoPath, opts...)
 
 	var result *ack.Result
 
@@ -83,15 +147,36 @@ func main() {
 		reason := args[2]
 		result, err = svc.Impossible(issueID, actor, reason)
 
+	case "depends-on":
+		if len(args) < 3 {
+			exitWithError("depends-on requires a target issue", nil, 1)
+		}
+		result, err = svc.DependsOn(issueID, args[2], actor, allowCycle)
+
+	case "requeue":
+		note := ""
+		if len(args) >= 3 {
+			note = args[2]
+		}
+		result, err = svc.Requeue(issueID, actor, note)
+
+	case "reassign":
+		if len(args) < 3 {
+			exitWithError("reassign requires a target agent", nil, 1)
+		}
+		var deadline *time.Time
+		if deadlineIn > 0 {
+			d := time.Now().Add(deadlineIn)
+			deadline = &d
+		}
+		result, err = svc.Reassign(issueID, actor, args[2], deadline)
+
 	default:
 		exitWithError(fmt.Sprintf("unknown action: %s", action), nil, 1)
 	}
 
 	if err != nil {
-		if err.Error() == fmt.Sprintf("issue %s not found", issueID) {
-			exitWithError(err.Error(), nil, 2)
-		}
-		exitWithError("action failed", err, 3)
+		exitWithActionError(err)
 	}
 
 	printResult(result)
@@ -102,14 +187,185 @@ func main() {
 	}
 }
 
-func getDefaultActor() string {
-	if actor := os.Getenv("BD_ACTOR"); actor != "" {
-		return actor
+// enforceDeadlines handles --enforce-deadlines and exits.
+func enforceDeadlines(repoPath string) {
+	policy, err := ack.ParseExpiryPolicy(onExpiry)
+	if err != nil {
+		exitWithError("invalid --on-expiry", err, 1)
+	}
+	svc := ack.NewService(repoPath, mutate.WithRetries(retries), mutate.WithLockTimeout(lockTimeout))
+	expired, err := svc.EnforceDeadlines(actor, time.Now(), policy)
+	if err != nil {
+		exitWithActionError(err)
 	}
-	if user := os.Getenv("USER"); user != "" {
-		return user
+
+	escalated := false
+	for _, e := range expired {
+		escalated = escalated || e.Escalated
 	}
-	return "unknown"
+	if jsonOutput {
+		data, _ := json.MarshalIndent(map[string]interface{}{
+			"success": true,
+			"expired": expired,
+		}, "", "  ")
+		fmt.Println(string(data))
+	} else if len(expired) == 0 {
+		fmt.Println("OK: no assignments past their deadline")
+	} else {
+		for _, e := range expired {
+			outcome := "re-queued"
+			if e.Escalated {
+				outcome = "ESCALATED"
+			}
+			fmt.Printf("EXPIRED: %s (%s, deadline %s, bounce %d/%d) %s\n",
+				e.IssueID, e.Agent, e.Deadline.Format(time.RFC3339), e.BounceCount, ack.MaxBounces, outcome)
+		}
+	}
+	if escalated {
+		os.Exit(10)
+	}
+}
+
+// batchLine is the JSON result printed for each --batch input line.
+type batchLine struct {
+	Line         int       `json:"line"`
+	ID           string    `json:"id,omitempty"`
+	Action       string    `json:"action,omitempty"`
+	Success      bool      `json:"success"`
+	Status       string    `json:"status"`
+	ExitCode     int       `json:"exit_code"`
+	Committed    bool      `json:"committed"`
+	Message      string    `json:"message,omitempty"`
+	Error        string    `json:"error,omitempty"`
+	Escalated    bool      `json:"escalated,omitempty"`
+	EscalateInfo string    `json:"escalate_info,omitempty"`
+	UpdatedAt    time.Time `json:"updated_at,omitzero"`
+	ContentHash  string    `json:"content_hash,omitempty"`
+}
+
+// runBatch handles --batch and exits. The process exits with the code of the
+// first failed item (nothing written), 10 if the batch was written and any
+// item escalated, or 0.
+func runBatch(repoPath string) {
+	if expectUpdatedAt != "" || expectHash != "" {
+		exitWithError("--expect-updated-at and --expect-hash apply to single actions; use expect_updated_at and expect_hash per batch line", nil, 1)
+	}
+
+	var actions []ack.Action
+	var lines []batchLine
+	invalid := false
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		text := scanner.Bytes()
+		if len(strings.TrimSpace(string(text))) == 0 {
+			continue
+		}
+		var a ack.Action
+		line := batchLine{Line: n}
+		if err := json.Unmarshal(text, &a); err != nil {
+			line.Status, line.ExitCode, line.Error = "invalid", 1, err.Error()
+			invalid = true
+		} else {
+			line.ID, line.Action = a.ID, a.Action
+			actions = append(actions, a)
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		exitWithError("failed to read batch from stdin", err, 1)
+	}
+
+	// A line we cannot even parse aborts the batch before it is applied.
+	if invalid {
+		for i := range lines {
+			if lines[i].Status == "" {
+				lines[i].Status = "skipped"
+			}
+		}
+		emitBatch(lines)
+		return
+	}
+
+	svc := ack.NewService(repoPath, mutate.WithRetries(retries), mutate.WithLockTimeout(lockTimeout))
+	results, err := svc.Batch(actor, actions)
+	committed := err == nil
+	for i, r := range results {
+		line := &lines[i]
+		line.Committed = committed
+		switch {
+		case r.Err != nil:
+			line.Status, line.ExitCode = actionErrorStatus(r.Err)
+			line.Error = r.Err.Error()
+		case err != nil && !errors.Is(err, ack.ErrBatchAborted):
+			// The items were fine but the write itself failed.
+			line.Status, line.ExitCode = actionErrorStatus(err)
+			line.Error = err.Error()
+		default:
+			line.Success = committed
+			line.Status, line.ExitCode = "ok", 0
+			if r.Result.Escalated {
+				line.Status, line.ExitCode = "escalated", 10
+			}
+			if !committed {
+				line.Status = "rolled_back"
+			}
+			line.Message = r.Result.Message
+			line.Escalated = r.Result.Escalated
+			line.EscalateInfo = r.Result.EscalateInfo
+			line.UpdatedAt = r.Result.Version.UpdatedAt
+			line.ContentHash = r.Result.Version.ContentHash
+		}
+	}
+	emitBatch(lines)
+}
+
+// emitBatch prints one JSON result per line and exits with the batch's code.
+func emitBatch(lines []batchLine) {
+	enc := json.NewEncoder(os.Stdout)
+	code := 0
+	for _, line := range lines {
+		_ = enc.Encode(line)
+		switch {
+		case line.ExitCode != 0 && line.ExitCode != 10:
+			if code == 0 || code == 10 {
+				code = line.ExitCode
+			}
+		case line.ExitCode == 10 && code == 0 && line.Committed:
+			code = 10
+		}
+	}
+	os.Exit(code)
+}
+
+// actionErrorStatus maps an action error to its batch status and exit code.
+func actionErrorStatus(err error) (string, int) {
+	switch {
+	case errors.Is(err, mutate.ErrIssueNotFound):
+		return "not_found", 2
+	case errors.Is(err, mutate.ErrDependencyCycle):
+		return "cycle", 4
+	case errors.Is(err, mutate.ErrConflict):
+		return "conflict", 5
+	case errors.Is(err, ack.ErrInvalidAction):
+		return "invalid", 1
+	}
+	return "failed", 3
+}
+
+// exitWithActionError reports a failed action with the exit code its cause
+// calls for.
+func exitWithActionError(err error) {
+	status, code := actionErrorStatus(err)
+	switch status {
+	case "not_found":
+		exitWithError(err.Error(), nil, code)
+	case "cycle":
+		exitWithError("dependency rejected", err, code)
+	case "conflict":
+		exitWithError("write conflict", err, code)
+	}
+	exitWithError("action failed", err, code)
 }
 
 func printUsage() {
@@ -120,10 +376,31 @@ Usage:
   bd-ack [options] <id> decline "reason"          Decline with reason
   bd-ack [options] <id> defer <other-agent>       Reassign to another agent
   bd-ack [options] <id> impossible "reason"       Mark as impossible
+  bd-ack [options] <id> depends-on <other-id>     Record that <id> is blocked by <other-id>
+  bd-ack [options] <id> requeue ["note"]          Return an escalated task to the queue
+  bd-ack [options] <id> reassign <agent>          Hand an escalated task to another agent
+  bd-ack [options] --enforce-deadlines            Handle assignments past their deadline
+  bd-ack [options] --batch < actions.jsonl        Apply JSON lines of actions, all or none
 
 Options:
   --json            Output in JSON format
   --actor <name>    Actor name for audit trail (default: $BD_ACTOR or $USER)
+  --allow-cycle     Let depends-on create a dependency cycle
+  --expect-updated-at <time>
+                    Only write if the bead's updated_at is still <time> (RFC 3339)
+  --expect-hash <hash>
+                    Only write if the bead's content hash is still <hash>
+                    (printed as content_hash by --json after each write)
+  --retries <n>     Reload and re-apply if another writer changed the file mid-write
+  --lock-timeout <d>
+                    Wait up to <d> for the beads write lock (default 10s)
+  --deadline <d>    With reassign: acknowledge within <d> (e.g. 24h)
+  --on-expiry <p>   With --enforce-deadlines: requeue (default; escalates
+                    at 3 bounces) or escalate
+  --batch           Read {"id", "action", "reason", "to"} objects, one per
+                    line, from stdin; print one JSON result per line. If any
+                    item fails nothing is written. Lines may also carry
+                    deadline, allow_cycle, expect_updated_at and expect_hash.
 
 Environment:
   BD_ACTOR          Agent name for actions
@@ -134,26 +411,39 @@ Exit Codes:
   1   Usage error
   2   Issue not found
   3   Action failed
+  4   Dependency would create a cycle
+  5   Conflict: the bead changed since it was read, or the file changed mid-write
   10  Success with escalation
+      (--batch: the first failed item's code, else 10 if any item escalated)
 
 Examples:
   bd-ack game1-abc123 accept
   bd-ack game1-abc123 decline "Outside my expertise"
   bd-ack game1-abc123 defer backend-agent
   bd-ack game1-abc123 impossible "Requires external API access"
+  bd-ack game1-abc123 depends-on game1-def456
+  bd-ack --expect-updated-at 2025-06-01T12:00:00Z game1-abc123 accept
+  bd-ack --deadline 24h game1-abc123 reassign frontend-agent
+  bd-ack --enforce-deadlines --on-expiry escalate
+  bd-ack --batch < actions.jsonl
 `)
 }
 
 func printResult(result *ack.Result) {
 	if jsonOutput {
 		output := map[string]interface{}{
-			"success": result.Success,
-			"message": result.Message,
+			"success":      result.Success,
+			"message":      result.Message,
+			"updated_at":   result.Version.UpdatedAt,
+			"content_hash": result.Version.ContentHash,
 		}
 		if result.Escalated {
 			output["escalated"] = true
 			output["escalate_info"] = result.EscalateInfo
 		}
+		if result.Impact != nil {
+			output["impact"] = result.Impact
+		}
 		data, _ := json.MarshalIndent(output, "", "  ")
 		fmt.Println(string(data))
 	} else {
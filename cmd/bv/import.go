@@ -0,0 +1,170 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/filelock"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/importer"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/mutate"
+)
+
+func runImport(args []string) int {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	from := fs.String("from", "", "Export format: github-json, jira-csv or linear-json")
+	prefix := fs.String("prefix", "", "ID prefix for imported issues (default: gh, or the Jira/Linear key)")
+	dryRun := fs.Bool("dry-run", false, "Show what would change without writing")
+	output := fs.String("output", "", "JSONL file to merge into (default: the project's beads file)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: bv import --from github-json|jira-csv|linear-json [--prefix p] [--dry-run] <file>")
+		fmt.Fprintln(fs.Output(), "")
+		fmt.Fprintln(fs.Output(), "Converts a GitHub Issues, Jira or Linear export file into beads issues and")
+		fmt.Fprintln(fs.Output(), "merges them into the beads JSONL file. Labels, assignees, comments, parents")
+		fmt.Fprintln(fs.Output(), "and blocking links are kept; the original issue is recorded in external_ref,")
+		fmt.Fprintln(fs.Output(), "so importing a newer export updates the same beads issues.")
+		fmt.Fprintln(fs.Output(), "")
+		fmt.Fprintln(fs.Output(), "  github-json  gh issue list --state all --json number,title,body,state,labels,")
+		fmt.Fprintln(fs.Output(), "               assignees,comments,createdAt,updatedAt,closedAt,url")
+		fmt.Fprintln(fs.Output(), "               (or a REST API dump); \"Blocked by #N\" / \"Parent: #N\" in")
+		fmt.Fprintln(fs.Output(), "               the body become dependencies")
+		fmt.Fprintln(fs.Output(), "  jira-csv     Jira \"Export Excel CSV (all fields)\"")
+		fmt.Fprintln(fs.Output(), "  linear-json  Linear GraphQL issues query output")
+		fmt.Fprintln(fs.Output(), "")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 1
+	}
+	// Allow flags after the file name too.
+	file := fs.Arg(0)
+	if fs.NArg() > 1 {
+		if err := fs.Parse(fs.Args()[1:]); err != nil {
+			return 1
+		}
+		if fs.NArg() > 0 {
+			fs.Usage()
+			return 1
+		}
+	}
+	if file == "" || *from == "" {
+		fs.Usage()
+		return 1
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	res, err := importer.Import(importer.Format(*from), f, importer.Options{
+		Prefix: *prefix,
+		Actor:  mutate.DefaultActor(),
+	})
+	f.Close()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	for _, w := range res.Warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+	}
+
+	target, err := importTarget(*output)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if !*dryRun {
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		// Hold the beads write lock from load to write, like every other writer.
+		lock, err := filelock.LockDir(filepath.Dir(target), 0)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		defer lock.Unlock()
+	}
+	var existing []model.Issue
+	if _, statErr := os.Stat(target); statErr == nil {
+		existing, err = loader.LoadIssuesFromFile(target)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+	}
+	plan, err := importer.Merge(existing, res.Issues)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if *dryRun {
+		fmt.Printf("Dry run: importing %d issue(s) from %s into %s\n", len(res.Issues), file, target)
+		printImportPlan(os.Stdout, plan)
+		return 0
+	}
+	if plan.Empty() {
+		fmt.Printf("Nothing to import: %d issue(s) in %s are up to date\n", plan.Unchanged, target)
+		return 0
+	}
+	if err := mutate.WriteIssuesAtomic(target, plan.Issues); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", target, err)
+		return 1
+	}
+	fmt.Printf("Imported into %s: %d added, %d updated, %d unchanged\n",
+		target, len(plan.Added), len(plan.Updated), plan.Unchanged)
+	return 0
+}
+
+// importTarget resolves the JSONL file to merge into. A project without a
+// beads file gets .beads/issues.jsonl.
+func importTarget(output string) (string, error) {
+	if output != "" {
+		if loader.IsSQLiteFile(output) {
+			return "", fmt.Errorf("%s is a SQLite database; import writes JSONL", output)
+		}
+		return output, nil
+	}
+	beadsDir, err := loader.GetBeadsDir("")
+	if err != nil {
+		return "", err
+	}
+	if path, err := loader.FindJSONLPath(beadsDir); err == nil {
+		return path, nil
+	}
+	return filepath.Join(beadsDir, loader.PreferredJSONLNames[0]), nil
+}
+
+// printImportPlan writes the dry-run diff: + for new issues, ~ with the
+// changed fields for updated ones.
+func printImportPlan(w io.Writer, plan importer.Plan) {
+	for _, c := range plan.Added {
+		fmt.Fprintf(w, "+ %-12s %s\n", c.ID, c.Title)
+	}
+	for _, c := range plan.Updated {
+		fmt.Fprintf(w, "~ %-12s %s\n", c.ID, c.Title)
+		for _, fc := range c.Fields {
+			fmt.Fprintf(w, "    %s: %s → %s\n", fc.Field, quoteEmpty(fc.Old), quoteEmpty(fc.New))
+		}
+	}
+	fmt.Fprintf(w, "%d to add, %d to update, %d unchanged\n", len(plan.Added), len(plan.Updated), plan.Unchanged)
+}
+
+func quoteEmpty(s string) string {
+	if s == "" {
+		return `""`
+	}
+	return s
+}
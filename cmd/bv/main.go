@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
@@ -21,12 +22,15 @@ import (
 
 	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/baseline"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/beadid"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/correlation"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/drift"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/export"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/hooks"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/mutate"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/query"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/recipe"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/search"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/ui"
@@ -38,6 +42,17 @@ import (
 )
 
 func main() {
+	// Subcommands are dispatched before global flag parsing.
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		os.Exit(runServe(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "watch-alerts" {
+		os.Exit(runWatchAlerts(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		os.Exit(runImport(os.Args[2:]))
+	}
+
 	help := flag.Bool("help", false, "Show help")
 	versionFlag := flag.Bool("version", false, "Show version")
 	// Update flags (bv-182)
@@ -46,6 +61,8 @@ func main() {
 	rollbackFlag := flag.Bool("rollback", false, "Rollback to the previous version (from backup)")
 	yesFlag := flag.Bool("yes", false, "Skip confirmation prompts (use with --update)")
 	exportFile := flag.String("export-md", "", "Export issues to a Markdown file (e.g., report.md)")
+	recipeExport := flag.String("export", "", "Export issues using the recipe's filters, sort and format (csv, json, mermaid, md; '-' for stdout)")
+	exportTemplate := flag.String("export-template", "", "Render --export through a Go text/template file")
 	robotHelp := flag.Bool("robot-help", false, "Show AI agent help")
 	robotInsights := flag.Bool("robot-insights", false, "Output graph analysis and insights as JSON for AI agents")
 	robotPlan := flag.Bool("robot-plan", false, "Output dependency-respecting execution plan as JSON for AI agents")
@@ -53,6 +70,7 @@ func main() {
 	robotTriage := flag.Bool("robot-triage", false, "Output unified triage as JSON (the mega-command for AI agents)")
 	robotTriageByTrack := flag.Bool("robot-triage-by-track", false, "Group triage recommendations by execution track (bv-87)")
 	robotTriageByLabel := flag.Bool("robot-triage-by-label", false, "Group triage recommendations by label (bv-87)")
+	robotTriageAgents := flag.Bool("robot-triage-agents", false, "Suggest who should pick up each triage recommendation, from agent ack history")
 	robotNext := flag.Bool("robot-next", false, "Output only the top pick recommendation as JSON (minimal triage)")
 	robotDiff := flag.Bool("robot-diff", false, "Output diff as JSON (use with --diff-since)")
 	robotRecipes := flag.Bool("robot-recipes", false, "Output available recipes as JSON for AI agents")
@@ -82,6 +100,7 @@ func main() {
 	robotByAssignee := flag.String("robot-by-assignee", "", "Filter robot outputs by assignee (exact match)")
 	// Label subgraph scoping (bv-122)
 	labelScope := flag.String("label", "", "Scope analysis to label's subgraph (affects --robot-insights, --robot-plan, --robot-priority)")
+	issueQuery := flag.String("query", "", "Limit output to issues matching a query, e.g. 'status:open label:api priority<=1' (triage, next, plan, priority, insights, graph, alerts, --search, exports and the TUI list)")
 	alertSeverity := flag.String("severity", "", "Filter robot alerts by severity (info|warning|critical)")
 	alertType := flag.String("alert-type", "", "Filter robot alerts by alert type (e.g., stale_issue)")
 	alertLabel := flag.String("alert-label", "", "Filter robot alerts by label match")
@@ -151,10 +170,22 @@ func main() {
 	forecastLabel := flag.String("forecast-label", "", "Filter forecast by label")
 	forecastSprint := flag.String("forecast-sprint", "", "Filter forecast by sprint ID")
 	forecastAgents := flag.Int("forecast-agents", 1, "Number of parallel agents for capacity calculation")
+	monteCarlo := flag.Bool("monte-carlo", false, "With --robot-forecast: simulate completion dates and report P50/P80/P95 confidence levels")
+	forecastTrials := flag.Int("forecast-trials", analysis.DefaultMonteCarloTrials, fmt.Sprintf("Number of Monte Carlo trials for --monte-carlo (max %d)", analysis.MaxMonteCarloTrials))
+	forecastSeed := flag.Int64("forecast-seed", 1, "Random seed for --monte-carlo (equal seeds give equal forecasts)")
 	// Capacity simulation flags (bv-160)
 	robotCapacity := flag.Bool("robot-capacity", false, "Output capacity simulation and completion projection as JSON")
 	capacityAgents := flag.Int("agents", 1, "Number of parallel agents for capacity simulation")
 	capacityLabel := flag.String("capacity-label", "", "Filter capacity simulation by label")
+	// Resource-constrained scheduling flags
+	robotSchedule := flag.Bool("robot-schedule", false, "Output a time-phased assignment of open work to agents as JSON")
+	scheduleAgents := flag.Int("schedule-agents", 0, "Minimum number of agents when .bv/roster.yaml is absent (current assignees plus generic agents)")
+	// Epic hierarchy roll-up
+	robotEpics := flag.Bool("robot-epics", false, "Output parent/child epic roll-ups (progress, blocked and stale children, forecast) as JSON")
+	// Ack escalation queue
+	robotEscalations := flag.Bool("robot-escalations", false, "Output escalated, impossible and overdue assignments with bounce history and suggested re-assignees as JSON")
+	// Agent reliability analytics
+	robotAgents := flag.Bool("robot-agents", false, "Output per-agent acceptance, bounce, cycle time, reopen, WIP and label metrics as JSON")
 	// Burndown flags (bv-159)
 	robotBurndown := flag.String("robot-burndown", "", "Output burndown data for sprint ID, or 'current' for active sprint")
 	// Action script emission flags (bv-89)
@@ -166,6 +197,10 @@ func main() {
 	feedbackIgnore := flag.String("feedback-ignore", "", "Record ignore feedback for issue ID (tunes recommendation weights)")
 	feedbackReset := flag.Bool("feedback-reset", false, "Reset all feedback data to defaults")
 	feedbackShow := flag.Bool("feedback-show", false, "Show current feedback status and weight adjustments")
+	// Cycle-guarded dependency writes
+	addDep := flag.String("add-dep", "", "Add a dependency and print its impact as JSON (format: ISSUE:DEPENDS_ON)")
+	addDepType := flag.String("dep-type", "blocks", "Dependency type for --add-dep (blocks, related, parent-child, discovered-from)")
+	allowCycle := flag.Bool("allow-cycle", false, "Let --add-dep create a dependency cycle")
 	// Priority brief export (bv-96)
 	priorityBrief := flag.String("priority-brief", "", "Export priority brief to Markdown file (e.g., brief.md)")
 	// Agent brief bundle (bv-131)
@@ -174,7 +209,7 @@ func main() {
 	exportPages := flag.String("export-pages", "", "Export static site to directory (e.g., ./bv-pages)")
 	pagesTitle := flag.String("pages-title", "", "Custom title for static site")
 	pagesIncludeClosed := flag.Bool("pages-include-closed", true, "Include closed issues in export (default: true)")
-	pagesIncludeHistory := flag.Bool("pages-include-history", true, "Include git history for time-travel (default: true)")
+	pagesIncludeHistory := flag.Bool("pages-include-history", true, "Include git history for time-travel and the bead_events/bead_commits tables (default: true)")
 	previewPages := flag.String("preview-pages", "", "Preview existing static site bundle")
 	pagesWizard := flag.Bool("pages", false, "Launch interactive Pages deployment wizard")
 	// Debug rendering flag (for diagnosing TUI issues)
@@ -241,6 +276,11 @@ func main() {
 		*robotByLabel != "" ||
 		*robotByAssignee != "" ||
 		*robotCapacity ||
+		*robotSchedule ||
+		*robotEpics ||
+		*robotEscalations ||
+		*robotAgents ||
+		*addDep != "" ||
 		// When stdout is non-TTY, --diff-since auto-enables JSON output. Mark this
 		// as robot mode early so parsers keep stdout JSON clean.
 		(*diffSince != "" && !stdoutIsTTY)
@@ -258,6 +298,9 @@ func main() {
 
 	if *help {
 		fmt.Println("Usage: bv [options]")
+		fmt.Println("       bv serve [--addr host:port]")
+		fmt.Println("       bv watch-alerts [--once]")
+		fmt.Println("       bv import --from github-json|jira-csv|linear-json [--dry-run] <file>")
 		fmt.Println("\nA TUI viewer for beads issue tracker.")
 		flag.PrintDefaults()
 		os.Exit(0)
@@ -448,9 +491,14 @@ func main() {
 		fmt.Println("        --forecast-label=X    Filter by label")
 		fmt.Println("        --forecast-sprint=Y   Filter by sprint")
 		fmt.Println("        --forecast-agents=N   Parallel agents (default: 1)")
+		fmt.Println("        --monte-carlo         Simulate cycle times from closed history; report P50/P80/P95")
+		fmt.Println("                              dates per issue, label and sprint with histograms")
+		fmt.Println("        --forecast-trials=N   Monte Carlo trials (default: 1000, max: 10000)")
+		fmt.Println("        --forecast-seed=N     Random seed; equal seeds give equal output (default: 1)")
 		fmt.Println("      Example: bv --robot-forecast bv-123")
 		fmt.Println("      Example: bv --robot-forecast all --forecast-label=backend")
 		fmt.Println("      Example: bv --robot-forecast all --forecast-agents=2")
+		fmt.Println("      Example: bv --robot-forecast all --monte-carlo --forecast-sprint=sprint-3")
 		fmt.Println("")
 		fmt.Println("  --robot-capacity [--agents=N] [--capacity-label=X]")
 		fmt.Println("      Outputs capacity simulation and completion projection as JSON.")
@@ -467,6 +515,51 @@ func main() {
 		fmt.Println("      Example: bv --robot-capacity --agents=3")
 		fmt.Println("      Example: bv --robot-capacity --capacity-label=backend")
 		fmt.Println("")
+		fmt.Println("  --robot-schedule [--schedule-agents=N]")
+		fmt.Println("      Assigns open work to named agents over time, respecting blocking deps,")
+		fmt.Println("      agent capacity and skills, and keeping the overall finish early.")
+		fmt.Println("      Agents come from .bv/roster.yaml (name, capacity, skills); without it,")
+		fmt.Println("      everyone assigned open work, plus generic agents up to --schedule-agents.")
+		fmt.Println("      Accepted assignments stay put; declined ones go to someone else.")
+		fmt.Println("      Key fields:")
+		fmt.Println("        - agents[].issues: start/end per issue, in order")
+		fmt.Println("        - makespan_minutes, end: when everything schedulable is done")
+		fmt.Println("        - unscheduled: issues no agent can take, or stuck in cycles")
+		fmt.Println("      Example: bv --robot-schedule | jq '.agents[] | {name, next: .issues[0].issue_id}'")
+		fmt.Println("")
+		fmt.Println("  --robot-epics")
+		fmt.Println("      Rolls up every epic (and any issue with parent-child children) over")
+		fmt.Println("      the issues below it.")
+		fmt.Println("      Key fields:")
+		fmt.Println("        - epics[].percent_done: closed share of leaf estimates (median if unset)")
+		fmt.Println("        - epics[].blocked_children, stale_children: open work needing attention")
+		fmt.Println("        - epics[].forecast_date: remaining minutes at recent closure velocity")
+		fmt.Println("        - ready_to_close: open epics whose children are all closed")
+		fmt.Println("      Example: bv --robot-epics | jq '.epics[] | {id, percent_done, forecast_date}'")
+		fmt.Println("")
+		fmt.Println("  --robot-escalations")
+		fmt.Println("      Lists beads waiting on a lead: marked impossible, escalated after")
+		fmt.Println("      repeated bounces, or assigned but not acknowledged by their deadline.")
+		fmt.Println("      Key fields:")
+		fmt.Println("        - items[].kind: impossible, escalated or overdue")
+		fmt.Println("        - items[].history: declines, deferrals and expiries from bd-ack comments")
+		fmt.Println("        - items[].suggested_assignees: roster agents who have not bounced it,")
+		fmt.Println("          least loaded first (.bv/roster.yaml, or everyone ever assigned)")
+		fmt.Println("      Resolve with: bd-ack <id> reassign <agent> | bd-ack <id> requeue")
+		fmt.Println("      Example: bv --robot-escalations | jq '.items[] | {id, kind, last_reason}'")
+		fmt.Println("")
+		fmt.Println("  --robot-agents")
+		fmt.Println("      Per-agent track record from ack fields, bd-ack comments and git history.")
+		fmt.Println("      Key fields:")
+		fmt.Println("        - agents[].acceptance_rate, bounce_rate: accepted vs handed back")
+		fmt.Println("          (declined, deferred, impossible, missed deadline)")
+		fmt.Println("        - agents[].median_cycle_time_hours: claim-to-close (git) or created-to-closed")
+		fmt.Println("        - agents[].reopen_rate: closed work later reopened (needs git history)")
+		fmt.Println("        - agents[].wip, capacity: open beads held vs roster capacity")
+		fmt.Println("        - agents[].labels, specialization: where the agent's work concentrates")
+		fmt.Println("      Use --robot-triage-agents to add suggested_agents to triage recommendations.")
+		fmt.Println("      Example: bv --robot-agents | jq '.agents[] | {agent, acceptance_rate, wip}'")
+		fmt.Println("")
 		fmt.Println("  --emit-script [--script-limit=N] [--script-format=bash|fish|zsh]")
 		fmt.Println("      Emits a shell script for top-N priority recommendations.")
 		fmt.Println("      Useful for agent workflows and automation.")
@@ -486,6 +579,18 @@ func main() {
 		fmt.Println("      Generates a readable status report with Mermaid.js visualizations.")
 		fmt.Println("      Runs pre-export and post-export hooks if configured in .bv/hooks.yaml")
 		fmt.Println("")
+		fmt.Println("  --export <file> [-r recipe] [--export-template tmpl]")
+		fmt.Println("      Exports the recipe's issues (filters, sort, view.max_items applied).")
+		fmt.Println("      Format comes from the recipe's export.format, else the extension:")
+		fmt.Println("        .csv      view.columns + view.metrics (e.g. pagerank, triage_score)")
+		fmt.Println("        .json     JSON lines, one issue per line with its graph metrics")
+		fmt.Println("        .mmd      Mermaid dependency graph; anything else is Markdown")
+		fmt.Println("      --export-template renders a Go text/template with .Issues (each with")
+		fmt.Println("      .Metrics and .Fields), .Triage, .Columns and .Mermaid in scope.")
+		fmt.Println("      Use '-' as the file to write to stdout. Runs export hooks too.")
+		fmt.Println("      Example: bv -r triage --export triage.csv")
+		fmt.Println("      Example: bv -r actionable --export - --export-template standup.tmpl")
+		fmt.Println("")
 		fmt.Println("  --no-hooks")
 		fmt.Println("      Skip running hooks during export. Useful for CI or quick exports.")
 		fmt.Println("")
@@ -495,6 +600,12 @@ func main() {
 		fmt.Println("      - post-export: Notifications, uploads (failure logged only)")
 		fmt.Println("      Environment variables: BV_EXPORT_PATH, BV_EXPORT_FORMAT,")
 		fmt.Println("        BV_ISSUE_COUNT, BV_TIMESTAMP")
+		fmt.Println("      Lifecycle hooks, run by bv watch-alerts once per change with a JSON")
+		fmt.Println("      event on stdin (BV_HOOK_PHASE, BV_EVENT, BV_ISSUE_ID in env):")
+		fmt.Println("      - on-load: first load (issue count, actionable IDs)")
+		fmt.Println("      - on-change: created, status_changed, unblocked")
+		fmt.Println("      - on-close: closed (with close_reason)")
+		fmt.Println("      - on-drift: new_cycle, escalated")
 		fmt.Println("")
 		fmt.Println("  --diff-since <commit|date>")
 		fmt.Println("      Shows changes since a historical point.")
@@ -627,6 +738,20 @@ func main() {
 		fmt.Println("      Includes label_scope and label_context in output with health metrics.")
 		fmt.Println("      Example: bv --robot-insights --label api")
 		fmt.Println("")
+		fmt.Println("  --query QUERY")
+		fmt.Println("      Limit --robot-triage/-next/-plan/-priority/-insights/-graph/-alerts, --search,")
+		fmt.Println("      exports and the TUI list to matching issues. Analysis still sees every issue,")
+		fmt.Println("      so a blocker outside the query keeps blocking. Other commands ignore --query.")
+		fmt.Println("      Terms are ANDed; OR, NOT / -term and ( ) combine them; a,b matches either.")
+		fmt.Println("      Operators: field:value  field=value  field!=value  < <= > >= (numbers, dates)")
+		fmt.Println("      Dates take an age (updated>14d = not updated for 14 days) or a date (2025-01-31).")
+		fmt.Println("      Fields:")
+		for _, line := range query.FieldHelp() {
+			fmt.Println("        " + line)
+		}
+		fmt.Println("      Example: bv --robot-triage --query 'status:open label:api -label:wontfix priority<=1 updated>14d'")
+		fmt.Println("      Recipes take the same syntax in filters.query; the TUI / filter bar accepts it too.")
+		fmt.Println("")
 		fmt.Println("  --robot-triage / --robot-next")
 		fmt.Println("      Unified triage (mega command) or single top pick. QuickRef includes top picks, quick_wins, blockers_to_clear.")
 		fmt.Println("")
@@ -698,6 +823,41 @@ func main() {
 		fmt.Println("      --pages-include-closed=false")
 		fmt.Println("          Exclude closed issues from export (default: include all)")
 		fmt.Println("")
+		fmt.Println("  bv serve [--addr 127.0.0.1:7777] [--poll]")
+		fmt.Println("      Long-running HTTP/JSON daemon. Keeps issues and analysis warm and")
+		fmt.Println("      reloads on file change, so repeated queries skip load + analysis.")
+		fmt.Println("      Endpoints mirror the robot flags (same JSON shapes):")
+		fmt.Println("        /triage /next /plan /insights /priority /graph /suggest")
+		fmt.Println("        /alerts /history /forecast /label-health /schedule /health")
+		fmt.Println("      Flag options become query params, e.g. /priority?label=api&max_results=5")
+		fmt.Println("      ETag is the data hash; send If-None-Match to get 304 when unchanged.")
+		fmt.Println("      Endpoints reading git history, the roster, drift config or sprints")
+		fmt.Println("      fold those into the ETag too.")
+		fmt.Println("      Example: curl -s localhost:7777/next | jq .id")
+		fmt.Println("")
+		fmt.Println("  bv watch-alerts [--once] [--poll]")
+		fmt.Println("      Re-evaluates --robot-alerts on every beads file change and delivers")
+		fmt.Println("      new alerts to the sinks in .bv/notify.yaml:")
+		fmt.Println("        webhook (JSON POST), socket (Unix socket, one JSON line per alert),")
+		fmt.Println("        file (JSON lines appended), command (shell, BV_ALERT_* env vars)")
+		fmt.Println("      Each sink gets an alert (type + issue + label) once; a failed delivery")
+		fmt.Println("      is retried on the next change. --once evaluates, delivers and exits.")
+		fmt.Println("      Also runs on-* lifecycle hooks from .bv/hooks.yaml (--no-hooks to skip).")
+		fmt.Println("")
+		fmt.Println("  bv import --from github-json|jira-csv|linear-json [--prefix p] [--dry-run] <file>")
+		fmt.Println("      Converts a tracker export file into beads issues (labels, assignees,")
+		fmt.Println("      comments, parent and blocking links) and merges it into the beads file.")
+		fmt.Println("      external_ref keeps the original, so re-importing updates in place.")
+		fmt.Println("      --dry-run prints the diff against the existing file without writing.")
+		fmt.Println("")
+		fmt.Println("  --add-dep ISSUE:DEPENDS_ON [--dep-type blocks] [--allow-cycle]")
+		fmt.Println("      Records that ISSUE depends on DEPENDS_ON and writes the beads file.")
+		fmt.Println("      A blocking dependency that would close a cycle is rejected (exit 1)")
+		fmt.Println("      with the cycle path on stderr, unless --allow-cycle is passed.")
+		fmt.Println("      Output: critical_path_depth_before/after/delta and")
+		fmt.Println("      actionable_before/after/delta, so you can see what the edge costs.")
+		fmt.Println("      Example: bv --add-dep bd-12:bd-7 | jq .actionable_delta")
+		fmt.Println("")
 		fmt.Println("  Drift Detection Configuration (.bv/drift.yaml)")
 		fmt.Println("      Customize drift detection thresholds:")
 		fmt.Println("      - density_warning_pct: 50    # Warn if density +50%")
@@ -872,6 +1032,46 @@ func main() {
 		}
 	}
 
+	// Handle --add-dep: write a dependency after checking it does not close a cycle
+	if *addDep != "" {
+		issueID, dependsOnID, ok := strings.Cut(*addDep, ":")
+		issueID, dependsOnID = strings.TrimSpace(issueID), strings.TrimSpace(dependsOnID)
+		if !ok || issueID == "" || dependsOnID == "" {
+			fmt.Fprintf(os.Stderr, "Error: --add-dep expects ISSUE:DEPENDS_ON, got %q\n", *addDep)
+			os.Exit(1)
+		}
+
+		_, impact, err := mutate.NewStore("").AddDependency(issueID, mutate.DefaultActor(), mutate.AddDependency{
+			DependsOnID: dependsOnID,
+			Type:        model.DependencyType(*addDepType),
+			AllowCycle:  *allowCycle,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error adding dependency: %v\n", err)
+			if errors.Is(err, mutate.ErrDependencyCycle) {
+				fmt.Fprintln(os.Stderr, "Pass --allow-cycle to add it anyway.")
+			}
+			os.Exit(1)
+		}
+
+		output := struct {
+			GeneratedAt string `json:"generated_at"`
+			*analysis.DependencyImpact
+			AllowedCycle bool `json:"allowed_cycle,omitempty"`
+		}{
+			GeneratedAt:      robotTimestamp(),
+			DependencyImpact: impact,
+			AllowedCycle:     impact.WouldCycle,
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(output); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding dependency impact: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	// Load recipes (needed for both --robot-recipes and --recipe)
 	recipeLoader, err := recipe.LoadDefault()
 	if err != nil {
@@ -938,6 +1138,23 @@ func main() {
 			}
 			os.Exit(1)
 		}
+		if activeRecipe.Filters.Query != "" {
+			if _, err := query.Parse(activeRecipe.Filters.Query); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: recipe '%s': %v\n", *recipeName, err)
+				os.Exit(1)
+			}
+		}
+	}
+
+	// Parse --query up front so a typo fails before loading issues
+	var preFilter *query.Query
+	if strings.TrimSpace(*issueQuery) != "" {
+		var err error
+		preFilter, err = query.Parse(*issueQuery)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
 	}
 
 	// Load issues from current directory or workspace (with timing for profile)
@@ -1011,9 +1228,10 @@ func main() {
 			fmt.Fprintln(os.Stderr, "Make sure you are in a project initialized with 'bd init'.")
 			os.Exit(1)
 		}
-		// Get beads file path for live reload (respects BEADS_DIR env var)
+		// Get beads file path for live reload (respects BEADS_DIR env var).
+		// This is the source LoadIssues chose: the JSONL file or beads.db.
 		beadsDir, _ := loader.GetBeadsDir("")
-		beadsPath, _ = loader.FindJSONLPath(beadsDir)
+		beadsPath, _ = loader.FindIssuesPath(beadsDir)
 
 		// Automatically ensure .bv/ is in .gitignore to prevent polluting git
 		// with search indexes, baselines, and other bv-specific files.
@@ -1028,7 +1246,19 @@ func main() {
 		issues = filterByRepo(issues, *repoFilter)
 	}
 
-	issuesForSearch := issues
+	// --query picks the issues commands report on. Analysis keeps every
+	// issue, so a blocker the query leaves out still blocks.
+	queryIssues := issues
+	var queryScope map[string]bool
+	if preFilter != nil {
+		queryIssues = filterByQuery(issues, preFilter)
+		queryScope = make(map[string]bool, len(queryIssues))
+		for _, issue := range queryIssues {
+			queryScope[issue.ID] = true
+		}
+	}
+
+	issuesForSearch := queryIssues
 
 	// Stable data hash for robot outputs (after repo filter but before recipes/TUI)
 	dataHash := analysis.ComputeDataHash(issues)
@@ -1064,6 +1294,15 @@ func main() {
 		}
 	}
 
+	robotOutputMeta := robotMeta{
+		DataHash:     dataHash,
+		AsOf:         *asOf,
+		AsOfCommit:   asOfResolved,
+		LabelScope:   *labelScope,
+		LabelContext: labelScopeContext,
+		Scope:        queryScope,
+	}
+
 	// Handle semantic search CLI (bv-9gf.3)
 	if *robotSearch && *semanticQuery == "" {
 		fmt.Fprintln(os.Stderr, "Error: --robot-search requires --search \"query\"")
@@ -1087,6 +1326,9 @@ func main() {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
+		if closer, ok := embedder.(io.Closer); ok {
+			defer closer.Close()
+		}
 
 		projectDir, err := os.Getwd()
 		if err != nil {
@@ -1100,9 +1342,10 @@ func main() {
 			os.Exit(1)
 		}
 
-		docs := search.DocumentsFromIssues(issuesForSearch)
+		chunks := search.ChunksFromIssues(issuesForSearch)
+		docs := search.ChunkDocuments(chunks)
 		if !*robotSearch && !loaded {
-			fmt.Fprintf(os.Stderr, "Building semantic index (%d issues)...\n", len(docs))
+			fmt.Fprintf(os.Stderr, "Building semantic index (%d issues, %d chunks)...\n", len(issuesForSearch), len(docs))
 		}
 
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -1113,6 +1356,9 @@ func main() {
 			fmt.Fprintf(os.Stderr, "Error building semantic index: %v\n", err)
 			os.Exit(1)
 		}
+		if syncStats.Rebuilt && loaded && !*robotSearch {
+			fmt.Fprintf(os.Stderr, "Embedding model changed; rebuilt semantic index (%d chunks)\n", len(docs))
+		}
 		if !loaded || syncStats.Changed() {
 			if err := idx.Save(indexPath); err != nil {
 				fmt.Fprintf(os.Stderr, "Error saving semantic index: %v\n", err)
@@ -1137,7 +1383,7 @@ func main() {
 		if searchCfg.Mode == search.SearchModeHybrid {
 			fetchLimit = search.HybridCandidateLimit(limit, len(issuesForSearch), *semanticQuery)
 		}
-		results, err := idx.SearchTopK(qvecs[0], fetchLimit)
+		results, err := idx.SearchIssuesTopK(qvecs[0], fetchLimit)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error searching index: %v\n", err)
 			os.Exit(1)
@@ -1151,6 +1397,12 @@ func main() {
 		for _, iss := range issuesForSearch {
 			titleByID[iss.ID] = iss.Title
 		}
+		chunkByID := make(map[string]search.Chunk, len(results))
+		for _, r := range results {
+			if c, ok := chunks[r.Chunk]; ok {
+				chunkByID[r.IssueID] = c
+			}
+		}
 
 		var hybridResults []search.HybridScore
 		var resolvedPreset search.PresetName
@@ -1166,7 +1418,7 @@ func main() {
 			resolvedPreset = presetName
 			resolvedWeights = &weights
 
-			cache := search.NewMetricsCache(search.NewAnalyzerMetricsLoader(issuesForSearch))
+			cache := search.NewMetricsCache(search.NewAnalyzerMetricsLoader(issues))
 			if err := cache.Refresh(); err != nil {
 				fmt.Fprintf(os.Stderr, "Error computing hybrid metrics: %v\n", err)
 				os.Exit(1)
@@ -1207,13 +1459,15 @@ func main() {
 			out.Results = make([]robotSearchResult, 0, max(len(results), len(hybridResults)))
 			if searchCfg.Mode == search.SearchModeHybrid {
 				for _, r := range hybridResults {
-					out.Results = append(out.Results, robotSearchResult{
+					res := robotSearchResult{
 						IssueID:         r.IssueID,
 						Score:           r.FinalScore,
 						TextScore:       r.TextScore,
 						Title:           titleByID[r.IssueID],
 						ComponentScores: r.ComponentScores,
-					})
+					}
+					res.setMatch(chunkByID[r.IssueID], *semanticQuery)
+					out.Results = append(out.Results, res)
 				}
 				out.UsageHints = []string{
 					"jq '.results[] | {id: .issue_id, score: .score, text: .text_score}' - Extract scores",
@@ -1222,14 +1476,17 @@ func main() {
 				}
 			} else {
 				for _, r := range results {
-					out.Results = append(out.Results, robotSearchResult{
+					res := robotSearchResult{
 						IssueID: r.IssueID,
 						Score:   r.Score,
 						Title:   titleByID[r.IssueID],
-					})
+					}
+					res.setMatch(chunkByID[r.IssueID], *semanticQuery)
+					out.Results = append(out.Results, res)
 				}
 				out.UsageHints = []string{
 					"jq '.results[] | {id: .issue_id, score: .score, title: .title}' - Extract results",
+					"jq '.results[] | {id: .issue_id, field: .field, comment: .comment_id, snippet: .snippet}' - Where each match was",
 					"jq '.index' - Index update stats (added/updated/removed/embedded)",
 				}
 			}
@@ -1259,7 +1516,7 @@ func main() {
 
 	// Handle --pages wizard (bv-10g)
 	if *pagesWizard {
-		if err := runPagesWizard(issues, beadsPath); err != nil {
+		if err := runPagesWizard(queryIssues, beadsPath); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
@@ -1278,13 +1535,13 @@ func main() {
 	// Handle --export-pages (bv-73f)
 	if *exportPages != "" {
 		fmt.Println("Exporting static site...")
-		fmt.Printf("  → Loading %d issues\n", len(issues))
+		fmt.Printf("  → Loading %d issues\n", len(queryIssues))
 
 		// Filter closed issues if not requested
-		exportIssues := issues
+		exportIssues := queryIssues
 		if !*pagesIncludeClosed {
 			var openIssues []model.Issue
-			for _, issue := range issues {
+			for _, issue := range queryIssues {
 				if issue.Status != model.StatusClosed {
 					openIssues = append(openIssues, issue)
 				}
@@ -1356,6 +1613,14 @@ func main() {
 			exporter.Config.Title = *pagesTitle
 		}
 
+		// Sprints, plus git history when it is included, for the
+		// full-fidelity tables
+		var historyReport *correlation.HistoryReport
+		if *pagesIncludeHistory {
+			historyReport = historyReportOrSkip(exportIssues, beadsPath, "→")
+		}
+		addExportDetails(exporter, historyReport, "→")
+
 		// Export SQLite database
 		fmt.Println("  → Writing database and JSON files...")
 		if err := exporter.Export(*exportPages); err != nil {
@@ -1377,19 +1642,16 @@ func main() {
 		}
 
 		// Export history data for time-travel feature (bv-z38b)
-		if *pagesIncludeHistory {
+		if historyReport != nil {
 			fmt.Println("  → Generating time-travel history data...")
-			if historyReport, err := generateHistoryForExport(issues); err == nil && historyReport != nil {
-				historyPath := filepath.Join(*exportPages, "data", "history.json")
-				if historyJSON, err := json.MarshalIndent(historyReport, "", "  "); err == nil {
-					if err := os.WriteFile(historyPath, historyJSON, 0644); err != nil {
-						fmt.Printf("  → Warning: failed to write history.json: %v\n", err)
-					} else {
-						fmt.Printf("  → history.json (%d commits)\n", len(historyReport.Commits))
-					}
+			history := generateHistoryForExport(historyReport)
+			historyPath := filepath.Join(*exportPages, "data", "history.json")
+			if historyJSON, err := json.MarshalIndent(history, "", "  "); err == nil {
+				if err := os.WriteFile(historyPath, historyJSON, 0644); err != nil {
+					fmt.Printf("  → Warning: failed to write history.json: %v\n", err)
+				} else {
+					fmt.Printf("  → history.json (%d commits)\n", len(history.Commits))
 				}
-			} else if err != nil {
-				fmt.Printf("  → Warning: failed to generate history: %v\n", err)
 			}
 		}
 
@@ -1421,27 +1683,7 @@ func main() {
 
 	// Handle --robot-label-health
 	if *robotLabelHealth {
-		cfg := analysis.DefaultLabelHealthConfig()
-		results := analysis.ComputeAllLabelHealth(issues, cfg, time.Now().UTC(), nil)
-
-		output := struct {
-			GeneratedAt    string                       `json:"generated_at"`
-			DataHash       string                       `json:"data_hash"`
-			AnalysisConfig analysis.LabelHealthConfig   `json:"analysis_config"`
-			Results        analysis.LabelAnalysisResult `json:"results"`
-			UsageHints     []string                     `json:"usage_hints"`
-		}{
-			GeneratedAt:    time.Now().UTC().Format(time.RFC3339),
-			DataHash:       dataHash,
-			AnalysisConfig: cfg,
-			Results:        results,
-			UsageHints: []string{
-				"jq '.results.summaries | sort_by(.health) | .[:3]' - Critical labels",
-				"jq '.results.labels[] | select(.health_level == \"critical\")' - Critical details",
-				"jq '.results.cross_label_flow.bottleneck_labels' - Bottleneck labels",
-				"jq '.results.attention_needed' - Labels needing attention",
-			},
-		}
+		output := buildLabelHealthOutput(dataHash, issues)
 		encoder := json.NewEncoder(os.Stdout)
 		encoder.SetIndent("", "  ")
 		if err := encoder.Encode(output); err != nil {
@@ -1590,7 +1832,7 @@ func main() {
 			DataHash: dataHash,
 		}
 
-		result, err := export.ExportGraph(issues, &stats, config)
+		result, err := export.ExportGraph(queryIssues, &stats, config)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error exporting graph: %v\n", err)
 			os.Exit(1)
@@ -1611,10 +1853,10 @@ func main() {
 		stats := analyzer.Analyze()
 
 		// Apply label filter if specified
-		exportIssues := issues
+		exportIssues := queryIssues
 		if *labelScope != "" {
 			var filtered []model.Issue
-			for _, iss := range issues {
+			for _, iss := range queryIssues {
 				for _, lbl := range iss.Labels {
 					if strings.EqualFold(lbl, *labelScope) {
 						filtered = append(filtered, iss)
@@ -1698,92 +1940,22 @@ func main() {
 
 		analyzer := analysis.NewAnalyzer(issues)
 		stats := analyzer.Analyze()
-
-		openCount, closedCount, blockedCount := 0, 0, 0
-		for _, issue := range issues {
-			switch issue.Status {
-			case model.StatusClosed:
-				closedCount++
-			case model.StatusBlocked:
-				blockedCount++
-			default:
-				openCount++
-			}
-		}
-		curStats := baseline.GraphStats{
-			NodeCount:       stats.NodeCount,
-			EdgeCount:       stats.EdgeCount,
-			Density:         stats.Density,
-			OpenCount:       openCount,
-			ClosedCount:     closedCount,
-			BlockedCount:    blockedCount,
-			CycleCount:      len(stats.Cycles()),
-			ActionableCount: len(analyzer.GetActionableIssues()),
-		}
-		bl := &baseline.Baseline{Stats: curStats}
-		cur := &baseline.Baseline{Stats: curStats, Cycles: stats.Cycles()}
-
-		calc := drift.NewCalculator(bl, cur, driftConfig)
-		calc.SetIssues(issues)
-		driftResult := calc.Calculate()
-
-		// Apply optional filters
-		filtered := driftResult.Alerts[:0]
-		for _, a := range driftResult.Alerts {
-			if *alertSeverity != "" && string(a.Severity) != *alertSeverity {
-				continue
-			}
-			if *alertType != "" && string(a.Type) != *alertType {
-				continue
-			}
-			if *alertLabel != "" {
-				found := false
-				for _, d := range a.Details {
-					if strings.Contains(strings.ToLower(d), strings.ToLower(*alertLabel)) {
-						found = true
-						break
-					}
-				}
-				if !found && a.Label != "" && !strings.Contains(strings.ToLower(a.Label), strings.ToLower(*alertLabel)) {
-					continue
+		alerts := computeDriftAlerts(issues, analyzer, &stats, driftConfig)
+		if queryScope != nil {
+			// Keep project-wide alerts and those about matching issues
+			scoped := alerts[:0]
+			for _, alert := range alerts {
+				if alert.IssueID == "" || queryScope[alert.IssueID] {
+					scoped = append(scoped, alert)
 				}
 			}
-			filtered = append(filtered, a)
-		}
-		driftResult.Alerts = filtered
-
-		output := struct {
-			GeneratedAt string        `json:"generated_at"`
-			DataHash    string        `json:"data_hash"`
-			Alerts      []drift.Alert `json:"alerts"`
-			Summary     struct {
-				Total    int `json:"total"`
-				Critical int `json:"critical"`
-				Warning  int `json:"warning"`
-				Info     int `json:"info"`
-			} `json:"summary"`
-			UsageHints []string `json:"usage_hints"`
-		}{
-			GeneratedAt: time.Now().UTC().Format(time.RFC3339),
-			DataHash:    dataHash,
-			Alerts:      driftResult.Alerts,
-			UsageHints: []string{
-				"--severity=warning --alert-type=stale_issue   # stale warnings only",
-				"--alert-type=blocking_cascade                 # high-unblock opportunities",
-				"jq '.alerts | map(.issue_id)'                # list impacted issues",
-			},
-		}
-		for _, a := range driftResult.Alerts {
-			switch a.Severity {
-			case drift.SeverityCritical:
-				output.Summary.Critical++
-			case drift.SeverityWarning:
-				output.Summary.Warning++
-			case drift.SeverityInfo:
-				output.Summary.Info++
-			}
-			output.Summary.Total++
+			alerts = scoped
 		}
+		output := buildAlertsOutput(dataHash, alerts, alertFilters{
+			Severity: *alertSeverity,
+			Type:     *alertType,
+			Label:    *alertLabel,
+		})
 
 		encoder := json.NewEncoder(os.Stdout)
 		encoder.SetIndent("", "  ")
@@ -1801,21 +1973,12 @@ func main() {
 		config.FilterBead = *suggestBead
 
 		// Parse filter type
-		switch *suggestType {
-		case "duplicate", "duplicates":
-			config.FilterType = analysis.SuggestionPotentialDuplicate
-		case "dependency", "dependencies":
-			config.FilterType = analysis.SuggestionMissingDependency
-		case "label", "labels":
-			config.FilterType = analysis.SuggestionLabelSuggestion
-		case "cycle", "cycles":
-			config.FilterType = analysis.SuggestionCycleWarning
-		case "":
-			// All types
-		default:
-			fmt.Fprintf(os.Stderr, "Invalid suggest-type: %s (use: duplicate, dependency, label, cycle)\n", *suggestType)
+		filterType, err := parseSuggestType(*suggestType)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
 			os.Exit(1)
 		}
+		config.FilterType = filterType
 
 		output := analysis.GenerateRobotSuggestOutput(issues, config, dataHash)
 
@@ -2014,151 +2177,7 @@ func main() {
 			analyzer.SetConfig(&cfg)
 		}
 		stats := analyzer.Analyze()
-		// Generate top 50 lists for summary, but full stats are included in the struct
-		insights := stats.GenerateInsights(50)
-
-		// Add project-level velocity snapshot (using dedicated helper for efficiency)
-		if v := analysis.ComputeProjectVelocity(issues, time.Now(), 8); v != nil {
-			snap := &analysis.VelocitySnapshot{
-				Closed7:   v.ClosedLast7Days,
-				Closed30:  v.ClosedLast30Days,
-				AvgDays:   v.AvgDaysToClose,
-				Estimated: v.Estimated,
-			}
-			if len(v.Weekly) > 0 {
-				snap.Weekly = make([]int, len(v.Weekly))
-				for i := range v.Weekly {
-					snap.Weekly[i] = v.Weekly[i].Closed
-				}
-			}
-			insights.Velocity = snap
-		}
-
-		// Optional cap for metric maps to avoid overload
-		limitMaps := func(m map[string]float64, limit int) map[string]float64 {
-			if limit <= 0 || limit >= len(m) {
-				return m
-			}
-			type kv struct {
-				k string
-				v float64
-			}
-			var items []kv
-			for k, v := range m {
-				items = append(items, kv{k, v})
-			}
-			sort.Slice(items, func(i, j int) bool {
-				if items[i].v == items[j].v {
-					return items[i].k < items[j].k
-				}
-				return items[i].v > items[j].v
-			})
-			trim := make(map[string]float64, limit)
-			for i := 0; i < limit; i++ {
-				trim[items[i].k] = items[i].v
-			}
-			return trim
-		}
-
-		limitMapInt := func(m map[string]int, limit int) map[string]int {
-			if limit <= 0 || len(m) <= limit {
-				return m
-			}
-			trim := make(map[string]int, limit)
-			count := 0
-			for k, v := range m {
-				trim[k] = v
-				count++
-				if count >= limit {
-					break
-				}
-			}
-			return trim
-		}
-
-		limitSlice := func(s []string, limit int) []string {
-			if limit <= 0 || len(s) <= limit {
-				return s
-			}
-			return s[:limit]
-		}
-
-		// Default cap to keep payload small; allow override via env
-		mapLimit := 200
-		if v := os.Getenv("BV_INSIGHTS_MAP_LIMIT"); v != "" {
-			if n, err := strconv.Atoi(v); err == nil && n > 0 {
-				mapLimit = n
-			}
-		}
-
-		fullStats := struct {
-			PageRank          map[string]float64 `json:"pagerank"`
-			Betweenness       map[string]float64 `json:"betweenness"`
-			Eigenvector       map[string]float64 `json:"eigenvector"`
-			Hubs              map[string]float64 `json:"hubs"`
-			Authorities       map[string]float64 `json:"authorities"`
-			CriticalPathScore map[string]float64 `json:"critical_path_score"`
-			CoreNumber        map[string]int     `json:"core_number"`
-			Slack             map[string]float64 `json:"slack"`
-			Articulation      []string           `json:"articulation_points"`
-		}{
-			PageRank:          limitMaps(stats.PageRank(), mapLimit),
-			Betweenness:       limitMaps(stats.Betweenness(), mapLimit),
-			Eigenvector:       limitMaps(stats.Eigenvector(), mapLimit),
-			Hubs:              limitMaps(stats.Hubs(), mapLimit),
-			Authorities:       limitMaps(stats.Authorities(), mapLimit),
-			CriticalPathScore: limitMaps(stats.CriticalPathScore(), mapLimit),
-			CoreNumber:        limitMapInt(stats.CoreNumber(), mapLimit),
-			Slack:             limitMaps(stats.Slack(), mapLimit),
-			Articulation:      limitSlice(stats.ArticulationPoints(), mapLimit),
-		}
-
-		// Get top what-if deltas for issues with highest downstream impact (bv-83)
-		topWhatIfs := analyzer.TopWhatIfDeltas(10)
-
-		// Generate advanced insights with canonical structure (bv-181)
-		advancedInsights := analyzer.GenerateAdvancedInsights(analysis.DefaultAdvancedInsightsConfig())
-
-		output := struct {
-			GeneratedAt    string                  `json:"generated_at"`
-			DataHash       string                  `json:"data_hash"`
-			AsOf           string                  `json:"as_of,omitempty"`        // Historical snapshot ref
-			AsOfCommit     string                  `json:"as_of_commit,omitempty"` // Resolved commit SHA
-			AnalysisConfig analysis.AnalysisConfig `json:"analysis_config"`
-			Status         analysis.MetricStatus   `json:"status"`
-			LabelScope     string                  `json:"label_scope,omitempty"`   // bv-122: Label filter applied
-			LabelContext   *analysis.LabelHealth   `json:"label_context,omitempty"` // bv-122: Health context for scoped label
-			analysis.Insights
-			FullStats        interface{}                `json:"full_stats"`
-			TopWhatIfs       []analysis.WhatIfEntry     `json:"top_what_ifs,omitempty"`      // Issues with highest downstream impact (bv-83)
-			AdvancedInsights *analysis.AdvancedInsights `json:"advanced_insights,omitempty"` // bv-181: Canonical advanced features
-			UsageHints       []string                   `json:"usage_hints"`                 // bv-84: Agent-friendly hints
-		}{
-			GeneratedAt:      time.Now().UTC().Format(time.RFC3339),
-			DataHash:         dataHash,
-			AsOf:             *asOf,
-			AsOfCommit:       asOfResolved,
-			AnalysisConfig:   stats.Config,
-			Status:           stats.Status(),
-			LabelScope:       *labelScope,
-			LabelContext:     labelScopeContext,
-			Insights:         insights,
-			FullStats:        fullStats,
-			TopWhatIfs:       topWhatIfs,
-			AdvancedInsights: advancedInsights,
-			UsageHints: []string{
-				"jq '.Bottlenecks[:5] | map(.ID)' - Top 5 bottleneck IDs",
-				"jq '.CriticalPath[:3]' - Top 3 critical path items",
-				"jq '.top_what_ifs[] | select(.delta.direct_unblocks > 2)' - High-impact items",
-				"jq '.full_stats.pagerank | to_entries | sort_by(-.value)[:5]' - Top PageRank",
-				"jq '.full_stats.core_number | to_entries | sort_by(-.value)[:5]' - Strongly embedded nodes (k-core)",
-				"jq '.full_stats.articulation_points' - Structural cut points",
-				"jq '.Slack[:5]' - Nodes with slack (good parallel work candidates)",
-				"jq '.Cycles | length' - Count of detected cycles",
-				"jq '.advanced_insights.cycle_break' - Cycle break suggestions (bv-181)",
-				"BV_INSIGHTS_MAP_LIMIT=50 bv --robot-insights - Reduce map sizes",
-			},
-		}
+		output := buildInsightsOutput(robotOutputMeta, issues, analyzer, &stats)
 
 		encoder := json.NewEncoder(os.Stdout)
 		encoder.SetIndent("", "  ")
@@ -2172,63 +2191,19 @@ func main() {
 	if *robotPlan {
 		analyzer := analysis.NewAnalyzer(issues)
 		// For --robot-plan we primarily need Phase 1 metrics (degree/topo/density).
-		// However, we still emit a stable status contract for agents. If the user
-		// explicitly asks for full analysis, honor it; otherwise, skip expensive
-		// centrality metrics and record the skip reasons deterministically.
-		cfg := analysis.ConfigForSize(len(issues), countEdges(issues))
-		if *forceFullAnalysis {
-			cfg = analysis.FullAnalysisConfig()
+		// However, we still emit a stable status contract for agents.
+		cfg := planAnalysisConfig(issues, *forceFullAnalysis)
+
+		var plan analysis.ExecutionPlan
+		if queryScope != nil {
+			plan = analyzer.GetExecutionPlanFor(robotOutputMeta.inScope)
 		} else {
-			const skipReason = "not computed for --robot-plan"
-			cfg.ComputePageRank = false
-			cfg.PageRankSkipReason = skipReason
-			cfg.ComputeBetweenness = false
-			cfg.BetweennessMode = analysis.BetweennessSkip
-			cfg.BetweennessSkipReason = skipReason
-			cfg.ComputeHITS = false
-			cfg.HITSSkipReason = skipReason
-			cfg.ComputeEigenvector = false
-			cfg.ComputeCriticalPath = false
-			cfg.ComputeCycles = false
-			cfg.CyclesSkipReason = skipReason
-		}
-
-		plan := analyzer.GetExecutionPlan()
+			plan = analyzer.GetExecutionPlan()
+		}
 
 		stats := analyzer.AnalyzeAsyncWithConfig(context.Background(), cfg)
 		stats.WaitForPhase2()
-		status := stats.Status()
-
-		// Wrap with metadata
-		output := struct {
-			GeneratedAt    string                  `json:"generated_at"`
-			DataHash       string                  `json:"data_hash"`
-			AsOf           string                  `json:"as_of,omitempty"`        // Historical snapshot ref
-			AsOfCommit     string                  `json:"as_of_commit,omitempty"` // Resolved commit SHA
-			AnalysisConfig analysis.AnalysisConfig `json:"analysis_config"`
-			Status         analysis.MetricStatus   `json:"status"`
-			LabelScope     string                  `json:"label_scope,omitempty"`   // bv-122: Label filter applied
-			LabelContext   *analysis.LabelHealth   `json:"label_context,omitempty"` // bv-122: Health context for scoped label
-			Plan           analysis.ExecutionPlan  `json:"plan"`
-			UsageHints     []string                `json:"usage_hints"` // bv-84: Agent-friendly hints
-		}{
-			GeneratedAt:    time.Now().UTC().Format(time.RFC3339),
-			DataHash:       dataHash,
-			AsOf:           *asOf,
-			AsOfCommit:     asOfResolved,
-			AnalysisConfig: cfg,
-			Status:         status,
-			LabelScope:     *labelScope,
-			LabelContext:   labelScopeContext,
-			Plan:           plan,
-			UsageHints: []string{
-				"jq '.plan.tracks | length' - Number of parallel execution tracks",
-				"jq '.plan.tracks[0].items | map(.id)' - First track item IDs",
-				"jq '.plan.tracks[].items[] | select(.unblocks | length > 0)' - Items that unblock others",
-				"jq '.plan.summary' - High-level execution summary",
-				"jq '[.plan.tracks[].items[]] | length' - Total items across all tracks",
-			},
-		}
+		output := buildPlanOutput(robotOutputMeta, cfg, stats.Status(), plan)
 
 		encoder := json.NewEncoder(os.Stdout)
 		encoder.SetIndent("", "  ")
@@ -2248,122 +2223,15 @@ func main() {
 		analyzer.SetConfig(&cfg)
 		stats := analyzer.AnalyzeAsyncWithConfig(context.Background(), cfg)
 		stats.WaitForPhase2()
-		status := stats.Status()
 
 		// Use enhanced recommendations with what-if deltas and top reasons (bv-83)
 		recommendations := analyzer.GenerateEnhancedRecommendations()
-
-		// Apply robot filters (bv-84)
-		filtered := make([]analysis.EnhancedPriorityRecommendation, 0, len(recommendations))
-		issueMap := make(map[string]model.Issue, len(issues))
-		for _, iss := range issues {
-			issueMap[iss.ID] = iss
-		}
-		for _, rec := range recommendations {
-			// Filter by minimum confidence
-			if *robotMinConf > 0 && rec.Confidence < *robotMinConf {
-				continue
-			}
-			// Filter by label
-			if *robotByLabel != "" {
-				if iss, ok := issueMap[rec.IssueID]; ok {
-					hasLabel := false
-					for _, lbl := range iss.Labels {
-						if lbl == *robotByLabel {
-							hasLabel = true
-							break
-						}
-					}
-					if !hasLabel {
-						continue
-					}
-				} else {
-					continue
-				}
-			}
-			// Filter by assignee
-			if *robotByAssignee != "" {
-				if iss, ok := issueMap[rec.IssueID]; ok {
-					if iss.Assignee != *robotByAssignee {
-						continue
-					}
-				} else {
-					continue
-				}
-			}
-			filtered = append(filtered, rec)
-		}
-		recommendations = filtered
-
-		// Apply max results limit
-		maxResults := 10 // Default cap
-		if *robotMaxResults > 0 {
-			maxResults = *robotMaxResults
-		}
-		if len(recommendations) > maxResults {
-			recommendations = recommendations[:maxResults]
-		}
-
-		// Count high confidence recommendations
-		highConfidence := 0
-		for _, rec := range recommendations {
-			if rec.Confidence >= 0.7 {
-				highConfidence++
-			}
-		}
-
-		// Build output with summary
-		output := struct {
-			GeneratedAt       string                                    `json:"generated_at"`
-			DataHash          string                                    `json:"data_hash"`
-			AsOf              string                                    `json:"as_of,omitempty"`        // Historical snapshot ref
-			AsOfCommit        string                                    `json:"as_of_commit,omitempty"` // Resolved commit SHA
-			AnalysisConfig    analysis.AnalysisConfig                   `json:"analysis_config"`
-			Status            analysis.MetricStatus                     `json:"status"`
-			LabelScope        string                                    `json:"label_scope,omitempty"`   // bv-122: Label filter applied
-			LabelContext      *analysis.LabelHealth                     `json:"label_context,omitempty"` // bv-122: Health context for scoped label
-			Recommendations   []analysis.EnhancedPriorityRecommendation `json:"recommendations"`
-			FieldDescriptions map[string]string                         `json:"field_descriptions"`
-			Filters           struct {
-				MinConfidence float64 `json:"min_confidence,omitempty"`
-				MaxResults    int     `json:"max_results"`
-				ByLabel       string  `json:"by_label,omitempty"`
-				ByAssignee    string  `json:"by_assignee,omitempty"`
-			} `json:"filters"`
-			Summary struct {
-				TotalIssues     int `json:"total_issues"`
-				Recommendations int `json:"recommendations"`
-				HighConfidence  int `json:"high_confidence"`
-			} `json:"summary"`
-			Usage []string `json:"usage_hints"` // bv-84: Agent-friendly hints
-		}{
-			GeneratedAt:       time.Now().UTC().Format(time.RFC3339),
-			DataHash:          dataHash,
-			AsOf:              *asOf,
-			AsOfCommit:        asOfResolved,
-			AnalysisConfig:    cfg,
-			Status:            status,
-			LabelScope:        *labelScope,
-			LabelContext:      labelScopeContext,
-			Recommendations:   recommendations,
-			FieldDescriptions: analysis.DefaultFieldDescriptions(),
-			Usage: []string{
-				"jq '.recommendations[] | select(.confidence > 0.7)' - Filter high confidence",
-				"jq '.recommendations[0].explanation.what_if' - Get top item's impact",
-				"jq '.recommendations | map({id: .issue_id, score: .impact_score})' - Extract IDs and scores",
-				"jq '.recommendations[] | select(.explanation.what_if.parallelization_gain > 0)' - Find items that increase parallel work capacity",
-				"--robot-min-confidence 0.6 - Pre-filter by confidence",
-				"--robot-max-results 5 - Limit to top N results",
-				"--robot-by-label bug - Filter by specific label",
-			},
-		}
-		output.Filters.MinConfidence = *robotMinConf
-		output.Filters.MaxResults = maxResults
-		output.Filters.ByLabel = *robotByLabel
-		output.Filters.ByAssignee = *robotByAssignee
-		output.Summary.TotalIssues = len(issues)
-		output.Summary.Recommendations = len(recommendations)
-		output.Summary.HighConfidence = highConfidence
+		output := buildPriorityOutput(robotOutputMeta, cfg, stats.Status(), issues, recommendations, priorityFilters{
+			MinConfidence: *robotMinConf,
+			MaxResults:    *robotMaxResults,
+			ByLabel:       *robotByLabel,
+			ByAssignee:    *robotByAssignee,
+		})
 
 		encoder := json.NewEncoder(os.Stdout)
 		encoder.SetIndent("", "  ")
@@ -2381,113 +2249,42 @@ func main() {
 			GroupByLabel:  *robotTriageByLabel,
 			WaitForPhase2: true, // Triage needs full graph metrics
 		}
-		triage := analysis.ComputeTriageWithOptions(issues, opts)
-
-		// bv-90: Load feedback data for output
-		var feedbackInfo *analysis.FeedbackJSON
-		if robotTriageBeadsDir, err := loader.GetBeadsDir(""); err == nil {
-			if feedbackData, err := analysis.LoadFeedback(robotTriageBeadsDir); err == nil && len(feedbackData.Events) > 0 {
-				info := feedbackData.ToJSON()
-				feedbackInfo = &info
-			}
+		if queryScope != nil {
+			opts.Include = robotOutputMeta.inScope
 		}
-
-		if *robotNext {
-			// Minimal output: just the top pick
-			if len(triage.QuickRef.TopPicks) == 0 {
-				output := struct {
-					GeneratedAt string `json:"generated_at"`
-					DataHash    string `json:"data_hash"`
-					AsOf        string `json:"as_of,omitempty"`
-					AsOfCommit  string `json:"as_of_commit,omitempty"`
-					Message     string `json:"message"`
-				}{
-					GeneratedAt: time.Now().UTC().Format(time.RFC3339),
-					DataHash:    dataHash,
-					AsOf:        *asOf,
-					AsOfCommit:  asOfResolved,
-					Message:     "No actionable items available",
-				}
-				encoder := json.NewEncoder(os.Stdout)
-				encoder.SetIndent("", "  ")
-				if err := encoder.Encode(output); err != nil {
-					fmt.Fprintf(os.Stderr, "Error encoding robot-next: %v\n", err)
-					os.Exit(1)
-				}
-				os.Exit(0)
-			}
-
-			top := triage.QuickRef.TopPicks[0]
-			output := struct {
-				GeneratedAt string   `json:"generated_at"`
-				DataHash    string   `json:"data_hash"`
-				AsOf        string   `json:"as_of,omitempty"`
-				AsOfCommit  string   `json:"as_of_commit,omitempty"`
-				ID          string   `json:"id"`
-				Title       string   `json:"title"`
-				Score       float64  `json:"score"`
-				Reasons     []string `json:"reasons"`
-				Unblocks    int      `json:"unblocks"`
-				ClaimCmd    string   `json:"claim_command"`
-				ShowCmd     string   `json:"show_command"`
-			}{
-				GeneratedAt: time.Now().UTC().Format(time.RFC3339),
-				DataHash:    dataHash,
-				AsOf:        *asOf,
-				AsOfCommit:  asOfResolved,
-				ID:          top.ID,
-				Title:       top.Title,
-				Score:       top.Score,
-				Reasons:     top.Reasons,
-				Unblocks:    top.Unblocks,
-				ClaimCmd:    fmt.Sprintf("bd update %s --status=in_progress", top.ID),
-				ShowCmd:     fmt.Sprintf("bd show %s", top.ID),
+		if *robotTriageAgents {
+			cwd, err := os.Getwd()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error getting current directory: %v\n", err)
+				os.Exit(1)
 			}
-
-			encoder := json.NewEncoder(os.Stdout)
-			encoder.SetIndent("", "  ")
-			if err := encoder.Encode(output); err != nil {
-				fmt.Fprintf(os.Stderr, "Error encoding robot-next: %v\n", err)
+			roster, err := analysis.LoadRoster(cwd)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				os.Exit(1)
 			}
-			os.Exit(0)
+			agents := analysis.ComputeAgentStats(issues, analysis.AgentStatsOptions{Roster: roster})
+			opts.Agents = &agents
 		}
+		triage := analysis.ComputeTriageWithOptions(issues, opts)
 
-		// Full triage output with usage hints
-		output := struct {
-			GeneratedAt string                 `json:"generated_at"`
-			DataHash    string                 `json:"data_hash"`
-			AsOf        string                 `json:"as_of,omitempty"`        // Historical snapshot ref (e.g., HEAD~30)
-			AsOfCommit  string                 `json:"as_of_commit,omitempty"` // Resolved commit SHA
-			Triage      analysis.TriageResult  `json:"triage"`
-			Feedback    *analysis.FeedbackJSON `json:"feedback,omitempty"` // bv-90: Feedback loop state
-			UsageHints  []string               `json:"usage_hints"`        // bv-84: Agent-friendly hints
-		}{
-			GeneratedAt: time.Now().UTC().Format(time.RFC3339),
-			DataHash:    dataHash,
-			AsOf:        *asOf,
-			AsOfCommit:  asOfResolved,
-			Triage:      triage,
-			Feedback:    feedbackInfo,
-			UsageHints: []string{
-				"jq '.triage.quick_ref.top_picks[:3]' - Top 3 picks for immediate work",
-				"jq '.triage.recommendations[3:10] | map({id,title,score})' - Next candidates after top picks",
-				"jq '.triage.blockers_to_clear | map(.id)' - High-impact blockers to clear",
-				"jq '.triage.recommendations[] | select(.type == \"bug\")' - Bug-focused recommendations",
-				"jq '.triage.quick_ref.top_picks[] | select(.unblocks > 2)' - High-impact picks",
-				"jq '.triage.quick_wins' - Low-effort, high-impact items",
-				"--robot-next - Get only the single top recommendation",
-				"--robot-triage-by-track - Group by execution track for multi-agent coordination",
-				"--robot-triage-by-label - Group by label for area-focused agents",
-				"jq '.triage.recommendations_by_track[].top_pick' - Top pick per track",
-				"jq '.triage.recommendations_by_label[].claim_command' - Claim commands per label",
-				"jq '.feedback.weight_adjustments' - View feedback-adjusted weights (bv-90)",
-			},
+		var output interface{}
+		if *robotNext {
+			// Minimal output: just the top pick
+			output = buildNextOutput(robotOutputMeta, triage)
+		} else {
+			// bv-90: Load feedback data for output
+			output = buildTriageOutput(robotOutputMeta, triage, loadTriageFeedback())
 		}
+
 		encoder := json.NewEncoder(os.Stdout)
 		encoder.SetIndent("", "  ")
 		if err := encoder.Encode(output); err != nil {
-			fmt.Fprintf(os.Stderr, "Error encoding robot-triage: %v\n", err)
+			if *robotNext {
+				fmt.Fprintf(os.Stderr, "Error encoding robot-next: %v\n", err)
+			} else {
+				fmt.Fprintf(os.Stderr, "Error encoding robot-triage: %v\n", err)
+			}
 			os.Exit(1)
 		}
 		os.Exit(0)
@@ -2709,64 +2506,17 @@ func main() {
 			os.Exit(1)
 		}
 
-		// Build correlator options
-		opts := correlation.CorrelatorOptions{
-			BeadID: *beadHistory,
-			Limit:  *historyLimit,
-		}
-
-		// Parse --history-since if provided
-		if *historySince != "" {
-			since, err := recipe.ParseRelativeTime(*historySince, time.Now())
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error parsing --history-since: %v\n", err)
-				os.Exit(1)
-			}
-			if !since.IsZero() {
-				opts.Since = &since
-			}
-		}
-
-		// Convert issues to BeadInfo for correlator
-		beadInfos := make([]correlation.BeadInfo, len(issues))
-		for i, issue := range issues {
-			beadInfos[i] = correlation.BeadInfo{
-				ID:     issue.ID,
-				Title:  issue.Title,
-				Status: string(issue.Status),
-			}
-		}
-
-		// Generate report with explicit beads path
-		correlator := correlation.NewCorrelator(cwd, beadsPath)
-		report, err := correlator.GenerateReport(beadInfos, opts)
+		report, err := buildHistoryReport(cwd, beadsPath, issues, historyRequest{
+			BeadID:        *beadHistory,
+			Limit:         *historyLimit,
+			Since:         *historySince,
+			MinConfidence: *minConfidence,
+		})
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error generating history report: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
 
-		// Apply confidence filter if specified
-		if *minConfidence > 0 {
-			scorer := correlation.NewScorer()
-			report.Histories = scorer.FilterHistoriesByConfidence(report.Histories, *minConfidence)
-
-			// Rebuild commit index after filtering
-			report.CommitIndex = make(correlation.CommitIndex)
-			for beadID, history := range report.Histories {
-				for _, commit := range history.Commits {
-					report.CommitIndex[commit.SHA] = append(report.CommitIndex[commit.SHA], beadID)
-				}
-			}
-
-			// Update stats
-			report.Stats.BeadsWithCommits = 0
-			for _, history := range report.Histories {
-				if len(history.Commits) > 0 {
-					report.Stats.BeadsWithCommits++
-				}
-			}
-		}
-
 		// Output JSON
 		encoder := json.NewEncoder(os.Stdout)
 		encoder.SetIndent("", "  ")
@@ -3066,8 +2816,19 @@ func main() {
 			}
 		}
 
+		issueIDs := make([]string, len(issues))
+		for i, issue := range issues {
+			issueIDs[i] = issue.ID
+		}
+		recognizer, err := beadid.ForProject(cwd, issueIDs)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
 		// Generate history report first (to get existing correlations)
 		correlator := correlation.NewCorrelator(cwd, beadsPath)
+		correlator.SetRecognizer(recognizer)
 		correlatorOpts := correlation.CorrelatorOptions{
 			Limit: *historyLimit,
 		}
@@ -3080,6 +2841,7 @@ func main() {
 
 		// Detect orphans using OrphanDetector
 		detector := correlation.NewOrphanDetector(report, cwd)
+		detector.SetRecognizer(recognizer)
 		extractOpts := correlation.ExtractOptions{
 			Limit: *historyLimit,
 		}
@@ -3799,150 +3561,38 @@ func main() {
 			os.Exit(1)
 		}
 
-		// Build graph stats for depth calculation
-		analyzer := analysis.NewAnalyzer(issues)
-		graphStats := analyzer.Analyze()
-
-		// Filter issues by label and sprint if specified
-		targetIssues := make([]model.Issue, 0, len(issues))
-		var sprintBeadIDs map[string]bool
-		if *forecastSprint != "" {
-			sprints, err := loader.LoadSprints(cwd)
-			if err == nil {
-				for _, s := range sprints {
-					if s.ID == *forecastSprint {
-						sprintBeadIDs = make(map[string]bool)
-						for _, bid := range s.BeadIDs {
-							sprintBeadIDs[bid] = true
-						}
-						break
-					}
-				}
-			}
-			if sprintBeadIDs == nil {
-				fmt.Fprintf(os.Stderr, "Sprint not found: %s\n", *forecastSprint)
-				os.Exit(1)
-			}
+		req := forecastRequest{
+			Target: *robotForecast,
+			Label:  *forecastLabel,
+			Sprint: *forecastSprint,
+			Agents: *forecastAgents,
+			Trials: *forecastTrials,
+			Seed:   *forecastSeed,
 		}
 
-		for _, iss := range issues {
-			// Filter by label
-			if *forecastLabel != "" {
-				hasLabel := false
-				for _, l := range iss.Labels {
-					if l == *forecastLabel {
-						hasLabel = true
-						break
-					}
-				}
-				if !hasLabel {
-					continue
-				}
-			}
-			// Filter by sprint
-			if sprintBeadIDs != nil && !sprintBeadIDs[iss.ID] {
-				continue
-			}
-			targetIssues = append(targetIssues, iss)
-		}
-
-		now := time.Now()
-		agents := *forecastAgents
-		if agents <= 0 {
-			agents = 1
+		var sprints []model.Sprint
+		if *forecastSprint != "" || *monteCarlo {
+			sprints, _ = loader.LoadSprints(cwd)
 		}
 
-		type ForecastSummary struct {
-			TotalMinutes  int       `json:"total_minutes"`
-			TotalDays     float64   `json:"total_days"`
-			AvgConfidence float64   `json:"avg_confidence"`
-			EarliestETA   time.Time `json:"earliest_eta"`
-			LatestETA     time.Time `json:"latest_eta"`
-		}
-		type ForecastOutput struct {
-			GeneratedAt   time.Time              `json:"generated_at"`
-			Agents        int                    `json:"agents"`
-			Filters       map[string]string      `json:"filters,omitempty"`
-			ForecastCount int                    `json:"forecast_count"`
-			Forecasts     []analysis.ETAEstimate `json:"forecasts"`
-			Summary       *ForecastSummary       `json:"summary,omitempty"`
-		}
-
-		var forecasts []analysis.ETAEstimate
-		var outputErr error
-
-		if *robotForecast == "all" {
-			// Forecast all open issues
-			for _, iss := range targetIssues {
-				if iss.Status == model.StatusClosed {
-					continue
-				}
-				eta, err := analysis.EstimateETAForIssue(issues, &graphStats, iss.ID, agents, now)
-				if err != nil {
-					continue
-				}
-				forecasts = append(forecasts, eta)
-			}
+		var output interface{}
+		if *monteCarlo {
+			output, err = buildMonteCarloForecastOutput(issues, sprints, req, time.Now())
 		} else {
-			// Single issue forecast
-			eta, err := analysis.EstimateETAForIssue(issues, &graphStats, *robotForecast, agents, now)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-				os.Exit(1)
-			}
-			forecasts = append(forecasts, eta)
-		}
-
-		// Build summary if multiple forecasts
-		var summary *ForecastSummary
-		if len(forecasts) > 1 {
-			totalMin := 0
-			totalConf := 0.0
-			earliest := forecasts[0].ETADate
-			latest := forecasts[0].ETADate
-			for _, f := range forecasts {
-				totalMin += f.EstimatedMinutes
-				totalConf += f.Confidence
-				if f.ETADate.Before(earliest) {
-					earliest = f.ETADate
-				}
-				if f.ETADate.After(latest) {
-					latest = f.ETADate
-				}
-			}
-			summary = &ForecastSummary{
-				TotalMinutes:  totalMin,
-				TotalDays:     float64(totalMin) / (60.0 * 8.0), // 8hr workday
-				AvgConfidence: totalConf / float64(len(forecasts)),
-				EarliestETA:   earliest,
-				LatestETA:     latest,
-			}
+			// Build graph stats for depth calculation
+			analyzer := analysis.NewAnalyzer(issues)
+			graphStats := analyzer.Analyze()
+			output, err = buildForecastOutput(issues, &graphStats, sprints, req, time.Now())
 		}
-
-		// Build output
-		filters := make(map[string]string)
-		if *forecastLabel != "" {
-			filters["label"] = *forecastLabel
-		}
-		if *forecastSprint != "" {
-			filters["sprint"] = *forecastSprint
-		}
-
-		output := ForecastOutput{
-			GeneratedAt:   now.UTC(),
-			Agents:        agents,
-			ForecastCount: len(forecasts),
-			Forecasts:     forecasts,
-			Summary:       summary,
-		}
-		if len(filters) > 0 {
-			output.Filters = filters
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
 		}
 
 		encoder := json.NewEncoder(os.Stdout)
 		encoder.SetIndent("", "  ")
-		if outputErr = encoder.Encode(output); outputErr != nil {
-			fmt.Fprintf(os.Stderr, "Error encoding forecast: %v\n", outputErr)
+		if err := encoder.Encode(output); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding forecast: %v\n", err)
 			os.Exit(1)
 		}
 		os.Exit(0)
@@ -4154,6 +3804,84 @@ func main() {
 		os.Exit(0)
 	}
 
+	// Handle --robot-schedule flag
+	if *robotSchedule {
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+		analyzer := analysis.NewAnalyzer(issues)
+		graphStats := analyzer.Analyze()
+
+		output, err := buildScheduleOutput(dataHash, issues, &graphStats, cwd, *scheduleAgents, time.Now())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(output); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding schedule: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// Handle --robot-epics flag
+	if *robotEpics {
+		output := buildEpicsOutput(dataHash, issues, time.Now())
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(output); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding epics: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// Handle --robot-agents flag
+	if *robotAgents {
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+		output, err := buildAgentsOutput(dataHash, issues, cwd, loadAgentHistory(cwd, "", issues, *historyLimit))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(output); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding agents: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// Handle --robot-escalations flag
+	if *robotEscalations {
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+		output, err := buildEscalationsOutput(dataHash, issues, cwd, time.Now())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(output); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding escalations: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	// Handle --diff-since flag
 	if *diffSince != "" {
 		// Auto-enable robot diff for non-interactive/agent contexts
@@ -4222,6 +3950,18 @@ func main() {
 		os.Exit(0)
 	}
 
+	if *recipeExport != "" || *exportTemplate != "" {
+		path := *recipeExport
+		if path == "" {
+			path = "-"
+		}
+		if err := runRecipeExport(queryIssues, activeRecipe, path, *exportTemplate, !*noHooks); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	// Handle --as-of flag for TUI mode (robot commands already handled above with historical data)
 	if *asOf != "" {
 		if len(issues) == 0 {
@@ -4231,6 +3971,9 @@ func main() {
 
 		// Launch TUI with historical issues (already loaded, no live reload)
 		m := ui.NewModel(issues, activeRecipe, "")
+		if preFilter != nil {
+			m.SetQuery(*issueQuery)
+		}
 		p := tea.NewProgram(m, tea.WithAltScreen()) // No mouse capture - enables native text selection
 
 		// Optional auto-quit for automated tests: set BV_TUI_AUTOCLOSE_MS
@@ -4267,7 +4010,7 @@ func main() {
 				ctx := hooks.ExportContext{
 					ExportPath:   *exportFile,
 					ExportFormat: "markdown",
-					IssueCount:   len(issues),
+					IssueCount:   len(queryIssues),
 					Timestamp:    time.Now(),
 				}
 				executor = hooks.NewExecutor(hookLoader.Config(), ctx)
@@ -4281,7 +4024,7 @@ func main() {
 		}
 
 		// Perform the export
-		if err := export.SaveMarkdownToFile(issues, *exportFile); err != nil {
+		if err := export.SaveMarkdownToFile(queryIssues, *exportFile); err != nil {
 			fmt.Printf("Error exporting: %v\n", err)
 			os.Exit(1)
 		}
@@ -4317,6 +4060,9 @@ func main() {
 	// Initial Model with live reload support
 	m := ui.NewModel(issues, activeRecipe, beadsPath)
 	defer m.Stop() // Clean up file watcher
+	if preFilter != nil {
+		m.SetQuery(*issueQuery)
+	}
 
 	// Enable workspace mode if loading from workspace config
 	if workspaceInfo != nil {
@@ -4551,6 +4297,15 @@ func applyRecipeFilters(issues []model.Issue, r *recipe.Recipe) []model.Issue {
 	f := r.Filters
 	now := time.Now()
 
+	// Query filter (validated when the recipe was selected)
+	var q *query.Query
+	var qEnv *query.Env
+	if f.Query != "" {
+		if parsed, err := query.Parse(f.Query); err == nil {
+			q, qEnv = parsed, queryEnv(issues, parsed)
+		}
+	}
+
 	// Build a set of open blocker IDs for actionable filtering
 	openBlockers := make(map[string]bool)
 	for _, issue := range issues {
@@ -4703,12 +4458,32 @@ func applyRecipeFilters(issues []model.Issue, r *recipe.Recipe) []model.Issue {
 			}
 		}
 
+		if q != nil && !q.Match(qEnv, &issue) {
+			continue
+		}
+
 		result = append(result, issue)
 	}
 
 	return result
 }
 
+// queryEnv builds the evaluation environment for q over issues, running
+// graph analysis only when q uses metrics.
+func queryEnv(issues []model.Issue, q *query.Query) *query.Env {
+	var stats *analysis.GraphStats
+	if q.NeedsMetrics() {
+		s := analysis.NewAnalyzer(issues).Analyze()
+		stats = &s
+	}
+	return query.NewEnv(issues, stats, time.Now())
+}
+
+// filterByQuery keeps the issues matching q
+func filterByQuery(issues []model.Issue, q *query.Query) []model.Issue {
+	return q.Filter(issues, queryEnv(issues, q))
+}
+
 // applyRecipeSort sorts issues based on recipe configuration
 func applyRecipeSort(issues []model.Issue, r *recipe.Recipe) []model.Issue {
 	if r == nil || r.Sort.Field == "" {
@@ -5695,6 +5470,14 @@ func runPagesWizard(issues []model.Issue, beadsPath string) error {
 		exporter.Config.Title = config.Title
 	}
 
+	// Sprints, plus git history when it is included, for the full-fidelity
+	// tables
+	var historyReport *correlation.HistoryReport
+	if config.IncludeHistory {
+		historyReport = historyReportOrSkip(exportIssues, beadsPath, "->")
+	}
+	addExportDetails(exporter, historyReport, "->")
+
 	// Export SQLite database
 	fmt.Println("  -> Writing database and JSON files...")
 	if err := exporter.Export(bundlePath); err != nil {
@@ -5737,19 +5520,16 @@ func runPagesWizard(issues []model.Issue, beadsPath string) error {
 	}
 
 	// Export history data for time-travel feature if requested
-	if config.IncludeHistory {
+	if historyReport != nil {
 		fmt.Println("  -> Generating time-travel history data...")
-		if historyReport, err := generateHistoryForExport(exportIssues); err == nil && historyReport != nil {
-			historyPath := filepath.Join(bundlePath, "data", "history.json")
-			if historyJSON, err := json.MarshalIndent(historyReport, "", "  "); err == nil {
-				if err := os.WriteFile(historyPath, historyJSON, 0644); err != nil {
-					fmt.Printf("  -> Warning: failed to write history.json: %v\n", err)
-				} else {
-					fmt.Printf("  -> history.json (%d commits)\n", len(historyReport.Commits))
-				}
+		history := generateHistoryForExport(historyReport)
+		historyPath := filepath.Join(bundlePath, "data", "history.json")
+		if historyJSON, err := json.MarshalIndent(history, "", "  "); err == nil {
+			if err := os.WriteFile(historyPath, historyJSON, 0644); err != nil {
+				fmt.Printf("  -> Warning: failed to write history.json: %v\n", err)
+			} else {
+				fmt.Printf("  -> history.json (%d commits)\n", len(history.Commits))
 			}
-		} else if err != nil {
-			fmt.Printf("  -> Warning: failed to generate history: %v\n", err)
 		}
 	}
 
@@ -6299,47 +6079,9 @@ type TimeTravelCommit struct {
 	BeadsClosed []string `json:"beads_closed,omitempty"`
 }
 
-// generateHistoryForExport creates time-travel history data from git history
-func generateHistoryForExport(issues []model.Issue) (*TimeTravelHistory, error) {
-	cwd, err := os.Getwd()
-	if err != nil {
-		return nil, err
-	}
-
-	// Check if we're in a git repository
-	if err := correlation.ValidateRepository(cwd); err != nil {
-		return nil, err
-	}
-
-	// Get beads path
-	beadsDir, err := loader.GetBeadsDir("")
-	if err != nil {
-		return nil, err
-	}
-	beadsPath, err := loader.FindJSONLPath(beadsDir)
-	if err != nil {
-		return nil, err
-	}
-
-	// Build bead info from issues
-	beadInfos := make([]correlation.BeadInfo, len(issues))
-	for i, issue := range issues {
-		beadInfos[i] = correlation.BeadInfo{
-			ID:     issue.ID,
-			Title:  issue.Title,
-			Status: string(issue.Status),
-		}
-	}
-
-	// Generate correlation report
-	correlator := correlation.NewCorrelator(cwd, beadsPath)
-	report, err := correlator.GenerateReport(beadInfos, correlation.CorrelatorOptions{
-		Limit: 500, // Reasonable limit for time-travel
-	})
-	if err != nil {
-		return nil, err
-	}
-
+// generateHistoryForExport creates time-travel history data from the
+// correlated git history.
+func generateHistoryForExport(report *correlation.HistoryReport) *TimeTravelHistory {
 	// Convert to time-travel format
 	// Group by commit date and track bead changes
 	commitMap := make(map[string]*TimeTravelCommit)
@@ -6363,12 +6105,6 @@ func generateHistoryForExport(issues []model.Issue) (*TimeTravelHistory, error)
 		}
 	}
 
-	// Check for closed beads (status = closed)
-	issueStatusMap := make(map[string]bool)
-	for _, issue := range issues {
-		issueStatusMap[issue.ID] = issue.Status == model.StatusClosed
-	}
-
 	// Convert map to sorted slice
 	var commits []TimeTravelCommit
 	for _, commit := range commitMap {
@@ -6393,5 +6129,82 @@ func generateHistoryForExport(issues []model.Issue) (*TimeTravelHistory, error)
 	return &TimeTravelHistory{
 		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
 		Commits:     commits,
-	}, nil
+	}
+}
+
+// historyReportForExport correlates the issues with git history in the
+// current repository. It fails outside a git repository. beadsPath is the file
+// the issues were loaded from; it may be beads.db, so the JSONL next to it is
+// what gets correlated.
+func historyReportForExport(issues []model.Issue, beadsPath string) (*correlation.HistoryReport, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if we're in a git repository
+	if err := correlation.ValidateRepository(cwd); err != nil {
+		return nil, err
+	}
+
+	// Get the JSONL path
+	beadsDir := filepath.Dir(beadsPath)
+	if beadsPath == "" {
+		if beadsDir, err = loader.GetBeadsDir(""); err != nil {
+			return nil, err
+		}
+	}
+	jsonlPath, err := loader.FindJSONLPath(beadsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build bead info from issues
+	beadInfos := make([]correlation.BeadInfo, len(issues))
+	for i, issue := range issues {
+		beadInfos[i] = correlation.BeadInfo{
+			ID:     issue.ID,
+			Title:  issue.Title,
+			Status: string(issue.Status),
+		}
+	}
+
+	// Generate correlation report
+	correlator := correlation.NewCorrelator(cwd, jsonlPath)
+	return correlator.GenerateReport(beadInfos, correlation.CorrelatorOptions{
+		Limit: 500, // Reasonable limit for exports
+	})
+}
+
+// historyReportOrSkip correlates the issues with git history for an export.
+// Outside a git repository, or on any other failure, it reports the skip with
+// the given progress arrow and returns nil so the export carries on without
+// history.
+func historyReportOrSkip(issues []model.Issue, beadsPath, arrow string) *correlation.HistoryReport {
+	fmt.Printf("  %s Correlating git history...\n", arrow)
+	report, err := historyReportForExport(issues, beadsPath)
+	if err != nil {
+		fmt.Printf("  %s Skipping git history: %v\n", arrow, err)
+		return nil
+	}
+	return report
+}
+
+// addExportDetails gives the SQLite exporter the project's sprints and, when
+// history is included, the correlated commit history. A nil report leaves the
+// bead_events and bead_commits tables empty. Sprint failures are reported with
+// the given progress arrow and the export carries on.
+func addExportDetails(exporter *export.SQLiteExporter, report *correlation.HistoryReport, arrow string) {
+	if beadsDir, err := loader.GetBeadsDir(""); err == nil {
+		sprints, err := loader.LoadSprintsFromFile(filepath.Join(beadsDir, loader.SprintsFileName))
+		if err != nil {
+			fmt.Printf("  %s Warning: failed to load sprints: %v\n", arrow, err)
+		} else {
+			exporter.SetSprints(sprints)
+		}
+	}
+
+	if report != nil {
+		exporter.SetHistory(report)
+	}
 }
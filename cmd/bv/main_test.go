@@ -183,6 +183,24 @@ func TestApplyRecipeFilters_DatesBlockersAndPrefix(t *testing.T) {
 	}
 }
 
+func TestApplyRecipeFilters_Query(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "Q-1", Title: "Blocker", Status: model.StatusOpen, Priority: 1, Labels: []string{"api"}},
+		{ID: "Q-2", Title: "Blocked", Status: model.StatusOpen, Priority: 1, Labels: []string{"api"},
+			Dependencies: []*model.Dependency{{DependsOnID: "Q-1", Type: model.DepBlocks}}},
+		{ID: "Q-3", Title: "Other", Status: model.StatusOpen, Priority: 3, Labels: []string{"api"}},
+	}
+	// Q-1 is not blocked itself but is what blocks Q-2.
+	r := &recipe.Recipe{Filters: recipe.FilterConfig{
+		IDPrefix: "Q-",
+		Query:    "label:api priority<=1 is:blocked",
+	}}
+	got := applyRecipeFilters(issues, r)
+	if len(got) != 1 || got[0].ID != "Q-2" {
+		t.Fatalf("expected only Q-2 to match filters.query, got %#v", got)
+	}
+}
+
 func TestApplyRecipeSort_DefaultsAndFields(t *testing.T) {
 	now := time.Now()
 	issues := []model.Issue{
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/export"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/hooks"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/recipe"
+)
+
+// runRecipeExport writes the recipe's filtered and sorted issues to path
+// ("-" for stdout) in the recipe's export format, running the pre- and
+// post-export hooks around it. Metrics and triage cover the full issue set.
+func runRecipeExport(issues []model.Issue, r *recipe.Recipe, path, templatePath string, runHooks bool) error {
+	// Progress goes to stderr when the export itself is on stdout.
+	var msg io.Writer = os.Stdout
+	if path == "-" {
+		msg = os.Stderr
+	}
+
+	exporter := &export.RecipeExporter{Recipe: r, All: issues, TemplatePath: templatePath, Now: time.Now()}
+	format, err := exporter.Format(path)
+	if err != nil {
+		return err
+	}
+	if format == export.FormatCSV {
+		if err := export.ValidateColumns(exporter.Columns()); err != nil {
+			return err
+		}
+	}
+
+	if format != export.FormatMarkdown && format != export.FormatMermaid {
+		analyzer := analysis.NewAnalyzer(issues)
+		stats := analyzer.Analyze()
+		triage := analysis.ComputeTriageFromAnalyzer(analyzer, &stats, issues,
+			analysis.TriageOptions{TopN: len(issues), WaitForPhase2: true}, exporter.Now)
+		exporter.Stats, exporter.Triage = &stats, &triage
+	}
+
+	selected := append([]model.Issue(nil), issues...)
+	if r != nil {
+		selected = applyRecipeFilters(selected, r)
+		selected = applyRecipeSort(selected, r)
+		if r.View.MaxItems > 0 && len(selected) > r.View.MaxItems {
+			selected = selected[:r.View.MaxItems]
+		}
+	}
+	exporter.Issues = selected
+
+	fmt.Fprintf(msg, "Exporting %d issue(s) as %s to %s...\n", len(selected), format, path)
+
+	var executor *hooks.Executor
+	if runHooks {
+		cwd, _ := os.Getwd()
+		hookLoader := hooks.NewLoader(hooks.WithProjectDir(cwd))
+		if err := hookLoader.Load(); err != nil {
+			fmt.Fprintf(msg, "Warning: failed to load hooks: %v\n", err)
+		} else if hookLoader.HasHooks() {
+			executor = hooks.NewExecutor(hookLoader.Config(), hooks.ExportContext{
+				ExportPath:   path,
+				ExportFormat: format,
+				IssueCount:   len(selected),
+				Timestamp:    exporter.Now,
+			})
+			if err := executor.RunPreExport(); err != nil {
+				return fmt.Errorf("pre-export hook failed: %w", err)
+			}
+		}
+	}
+
+	if path == "-" {
+		err = exporter.Write(os.Stdout, format)
+	} else {
+		err = writeExportFile(path, exporter, format)
+	}
+	if err != nil {
+		return fmt.Errorf("exporting: %w", err)
+	}
+
+	if executor != nil {
+		if err := executor.RunPostExport(); err != nil {
+			fmt.Fprintf(msg, "Warning: post-export hook failed: %v\n", err)
+		}
+		if len(executor.Results()) > 0 {
+			fmt.Fprintln(msg, executor.Summary())
+		}
+	}
+	fmt.Fprintln(msg, "Done!")
+	return nil
+}
+
+func writeExportFile(path string, exporter *export.RecipeExporter, format string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := exporter.Write(f, format); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
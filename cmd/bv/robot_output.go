@@ -0,0 +1,1165 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/baseline"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/correlation"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/drift"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/recipe"
+)
+
+// robotMeta carries the metadata stamped onto every robot payload. The CLI
+// fills it from flags; `bv serve` fills it from the loaded snapshot.
+type robotMeta struct {
+	DataHash     string
+	AsOf         string
+	AsOfCommit   string
+	LabelScope   string
+	LabelContext *analysis.LabelHealth
+
+	// Scope holds the IDs matching --query; nil means every issue. Outputs
+	// list only these, while analysis still covers every issue.
+	Scope map[string]bool
+}
+
+// inScope reports whether id matches --query
+func (m robotMeta) inScope(id string) bool {
+	return m.Scope == nil || m.Scope[id]
+}
+
+func robotTimestamp() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
+
+// ---------------------------------------------------------------------------
+// Triage / next
+// ---------------------------------------------------------------------------
+
+type robotTriageOutput struct {
+	GeneratedAt string                 `json:"generated_at"`
+	DataHash    string                 `json:"data_hash"`
+	AsOf        string                 `json:"as_of,omitempty"`        // Historical snapshot ref (e.g., HEAD~30)
+	AsOfCommit  string                 `json:"as_of_commit,omitempty"` // Resolved commit SHA
+	Triage      analysis.TriageResult  `json:"triage"`
+	Feedback    *analysis.FeedbackJSON `json:"feedback,omitempty"` // bv-90: Feedback loop state
+	UsageHints  []string               `json:"usage_hints"`        // bv-84: Agent-friendly hints
+}
+
+type robotNextEmptyOutput struct {
+	GeneratedAt string `json:"generated_at"`
+	DataHash    string `json:"data_hash"`
+	AsOf        string `json:"as_of,omitempty"`
+	AsOfCommit  string `json:"as_of_commit,omitempty"`
+	Message     string `json:"message"`
+}
+
+type robotNextOutput struct {
+	GeneratedAt string   `json:"generated_at"`
+	DataHash    string   `json:"data_hash"`
+	AsOf        string   `json:"as_of,omitempty"`
+	AsOfCommit  string   `json:"as_of_commit,omitempty"`
+	ID          string   `json:"id"`
+	Title       string   `json:"title"`
+	Score       float64  `json:"score"`
+	Reasons     []string `json:"reasons"`
+	Unblocks    int      `json:"unblocks"`
+	ClaimCmd    string   `json:"claim_command"`
+	ShowCmd     string   `json:"show_command"`
+}
+
+// loadTriageFeedback returns the feedback loop state for triage output (bv-90),
+// or nil when no feedback has been recorded.
+func loadTriageFeedback() *analysis.FeedbackJSON {
+	beadsDir, err := loader.GetBeadsDir("")
+	if err != nil {
+		return nil
+	}
+	feedbackData, err := analysis.LoadFeedback(beadsDir)
+	if err != nil || len(feedbackData.Events) == 0 {
+		return nil
+	}
+	info := feedbackData.ToJSON()
+	return &info
+}
+
+func buildTriageOutput(meta robotMeta, triage analysis.TriageResult, feedback *analysis.FeedbackJSON) robotTriageOutput {
+	return robotTriageOutput{
+		GeneratedAt: robotTimestamp(),
+		DataHash:    meta.DataHash,
+		AsOf:        meta.AsOf,
+		AsOfCommit:  meta.AsOfCommit,
+		Triage:      triage,
+		Feedback:    feedback,
+		UsageHints: []string{
+			"jq '.triage.quick_ref.top_picks[:3]' - Top 3 picks for immediate work",
+			"jq '.triage.recommendations[3:10] | map({id,title,score})' - Next candidates after top picks",
+			"jq '.triage.blockers_to_clear | map(.id)' - High-impact blockers to clear",
+			"jq '.triage.recommendations[] | select(.type == \"bug\")' - Bug-focused recommendations",
+			"jq '.triage.quick_ref.top_picks[] | select(.unblocks > 2)' - High-impact picks",
+			"jq '.triage.quick_wins' - Low-effort, high-impact items",
+			"--robot-next - Get only the single top recommendation",
+			"--robot-triage-by-track - Group by execution track for multi-agent coordination",
+			"--robot-triage-by-label - Group by label for area-focused agents",
+			"jq '.triage.recommendations_by_track[].top_pick' - Top pick per track",
+			"jq '.triage.recommendations_by_label[].claim_command' - Claim commands per label",
+			"jq '.feedback.weight_adjustments' - View feedback-adjusted weights (bv-90)",
+		},
+	}
+}
+
+// buildNextOutput returns the minimal single-pick payload for --robot-next.
+func buildNextOutput(meta robotMeta, triage analysis.TriageResult) interface{} {
+	if len(triage.QuickRef.TopPicks) == 0 {
+		return robotNextEmptyOutput{
+			GeneratedAt: robotTimestamp(),
+			DataHash:    meta.DataHash,
+			AsOf:        meta.AsOf,
+			AsOfCommit:  meta.AsOfCommit,
+			Message:     "No actionable items available",
+		}
+	}
+
+	top := triage.QuickRef.TopPicks[0]
+	return robotNextOutput{
+		GeneratedAt: robotTimestamp(),
+		DataHash:    meta.DataHash,
+		AsOf:        meta.AsOf,
+		AsOfCommit:  meta.AsOfCommit,
+		ID:          top.ID,
+		Title:       top.Title,
+		Score:       top.Score,
+		Reasons:     top.Reasons,
+		Unblocks:    top.Unblocks,
+		ClaimCmd:    fmt.Sprintf("bd update %s --status=in_progress", top.ID),
+		ShowCmd:     fmt.Sprintf("bd show %s", top.ID),
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Plan
+// ---------------------------------------------------------------------------
+
+type robotPlanOutput struct {
+	GeneratedAt    string                  `json:"generated_at"`
+	DataHash       string                  `json:"data_hash"`
+	AsOf           string                  `json:"as_of,omitempty"`        // Historical snapshot ref
+	AsOfCommit     string                  `json:"as_of_commit,omitempty"` // Resolved commit SHA
+	AnalysisConfig analysis.AnalysisConfig `json:"analysis_config"`
+	Status         analysis.MetricStatus   `json:"status"`
+	LabelScope     string                  `json:"label_scope,omitempty"`   // bv-122: Label filter applied
+	LabelContext   *analysis.LabelHealth   `json:"label_context,omitempty"` // bv-122: Health context for scoped label
+	Plan           analysis.ExecutionPlan  `json:"plan"`
+	UsageHints     []string                `json:"usage_hints"` // bv-84: Agent-friendly hints
+}
+
+// planAnalysisConfig returns the analysis config used by --robot-plan. The plan
+// itself only needs Phase 1 metrics, so centrality is skipped unless full
+// analysis is forced; skip reasons are recorded for a stable status contract.
+func planAnalysisConfig(issues []model.Issue, forceFull bool) analysis.AnalysisConfig {
+	if forceFull {
+		return analysis.FullAnalysisConfig()
+	}
+	cfg := analysis.ConfigForSize(len(issues), countEdges(issues))
+	const skipReason = "not computed for --robot-plan"
+	cfg.ComputePageRank = false
+	cfg.PageRankSkipReason = skipReason
+	cfg.ComputeBetweenness = false
+	cfg.BetweennessMode = analysis.BetweennessSkip
+	cfg.BetweennessSkipReason = skipReason
+	cfg.ComputeHITS = false
+	cfg.HITSSkipReason = skipReason
+	cfg.ComputeEigenvector = false
+	cfg.ComputeCriticalPath = false
+	cfg.ComputeCycles = false
+	cfg.CyclesSkipReason = skipReason
+	return cfg
+}
+
+func buildPlanOutput(meta robotMeta, cfg analysis.AnalysisConfig, status analysis.MetricStatus, plan analysis.ExecutionPlan) robotPlanOutput {
+	return robotPlanOutput{
+		GeneratedAt:    robotTimestamp(),
+		DataHash:       meta.DataHash,
+		AsOf:           meta.AsOf,
+		AsOfCommit:     meta.AsOfCommit,
+		AnalysisConfig: cfg,
+		Status:         status,
+		LabelScope:     meta.LabelScope,
+		LabelContext:   meta.LabelContext,
+		Plan:           plan,
+		UsageHints: []string{
+			"jq '.plan.tracks | length' - Number of parallel execution tracks",
+			"jq '.plan.tracks[0].items | map(.id)' - First track item IDs",
+			"jq '.plan.tracks[].items[] | select(.unblocks | length > 0)' - Items that unblock others",
+			"jq '.plan.summary' - High-level execution summary",
+			"jq '.plan.critical_path.chain | map(.id)' - Estimate-weighted critical chain",
+			"jq '[.plan.tracks[].items[] | select(.critical)]' - Actionable items with zero slack",
+			"jq '[.plan.tracks[].items[]] | length' - Total items across all tracks",
+		},
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Priority
+// ---------------------------------------------------------------------------
+
+// priorityFilters mirrors the --robot-min-confidence / --robot-max-results /
+// --robot-by-label / --robot-by-assignee flags (bv-84).
+type priorityFilters struct {
+	MinConfidence float64 `json:"min_confidence,omitempty"`
+	MaxResults    int     `json:"max_results"`
+	ByLabel       string  `json:"by_label,omitempty"`
+	ByAssignee    string  `json:"by_assignee,omitempty"`
+}
+
+type robotPriorityOutput struct {
+	GeneratedAt       string                                    `json:"generated_at"`
+	DataHash          string                                    `json:"data_hash"`
+	AsOf              string                                    `json:"as_of,omitempty"`        // Historical snapshot ref
+	AsOfCommit        string                                    `json:"as_of_commit,omitempty"` // Resolved commit SHA
+	AnalysisConfig    analysis.AnalysisConfig                   `json:"analysis_config"`
+	Status            analysis.MetricStatus                     `json:"status"`
+	LabelScope        string                                    `json:"label_scope,omitempty"`   // bv-122: Label filter applied
+	LabelContext      *analysis.LabelHealth                     `json:"label_context,omitempty"` // bv-122: Health context for scoped label
+	Recommendations   []analysis.EnhancedPriorityRecommendation `json:"recommendations"`
+	FieldDescriptions map[string]string                         `json:"field_descriptions"`
+	Filters           priorityFilters                           `json:"filters"`
+	Summary           struct {
+		TotalIssues     int `json:"total_issues"`
+		Recommendations int `json:"recommendations"`
+		HighConfidence  int `json:"high_confidence"`
+	} `json:"summary"`
+	Usage []string `json:"usage_hints"` // bv-84: Agent-friendly hints
+}
+
+// buildPriorityOutput filters the enhanced recommendations (bv-83) and wraps
+// them with metadata and a summary.
+func buildPriorityOutput(meta robotMeta, cfg analysis.AnalysisConfig, status analysis.MetricStatus, issues []model.Issue, recommendations []analysis.EnhancedPriorityRecommendation, filters priorityFilters) robotPriorityOutput {
+	filtered := make([]analysis.EnhancedPriorityRecommendation, 0, len(recommendations))
+	issueMap := make(map[string]model.Issue, len(issues))
+	for _, iss := range issues {
+		issueMap[iss.ID] = iss
+	}
+	for _, rec := range recommendations {
+		if !meta.inScope(rec.IssueID) {
+			continue
+		}
+		// Filter by minimum confidence
+		if filters.MinConfidence > 0 && rec.Confidence < filters.MinConfidence {
+			continue
+		}
+		// Filter by label
+		if filters.ByLabel != "" {
+			iss, ok := issueMap[rec.IssueID]
+			if !ok || !issueHasLabel(iss, filters.ByLabel) {
+				continue
+			}
+		}
+		// Filter by assignee
+		if filters.ByAssignee != "" {
+			iss, ok := issueMap[rec.IssueID]
+			if !ok || iss.Assignee != filters.ByAssignee {
+				continue
+			}
+		}
+		filtered = append(filtered, rec)
+	}
+	recommendations = filtered
+
+	// Apply max results limit
+	if filters.MaxResults <= 0 {
+		filters.MaxResults = 10 // Default cap
+	}
+	if len(recommendations) > filters.MaxResults {
+		recommendations = recommendations[:filters.MaxResults]
+	}
+
+	// Count high confidence recommendations
+	highConfidence := 0
+	for _, rec := range recommendations {
+		if rec.Confidence >= 0.7 {
+			highConfidence++
+		}
+	}
+
+	output := robotPriorityOutput{
+		GeneratedAt:       robotTimestamp(),
+		DataHash:          meta.DataHash,
+		AsOf:              meta.AsOf,
+		AsOfCommit:        meta.AsOfCommit,
+		AnalysisConfig:    cfg,
+		Status:            status,
+		LabelScope:        meta.LabelScope,
+		LabelContext:      meta.LabelContext,
+		Recommendations:   recommendations,
+		FieldDescriptions: analysis.DefaultFieldDescriptions(),
+		Filters:           filters,
+		Usage: []string{
+			"jq '.recommendations[] | select(.confidence > 0.7)' - Filter high confidence",
+			"jq '.recommendations[0].explanation.what_if' - Get top item's impact",
+			"jq '.recommendations | map({id: .issue_id, score: .impact_score})' - Extract IDs and scores",
+			"jq '.recommendations[] | select(.explanation.what_if.parallelization_gain > 0)' - Find items that increase parallel work capacity",
+			"--robot-min-confidence 0.6 - Pre-filter by confidence",
+			"--robot-max-results 5 - Limit to top N results",
+			"--robot-by-label bug - Filter by specific label",
+		},
+	}
+	output.Summary.TotalIssues = len(issues)
+	output.Summary.Recommendations = len(recommendations)
+	output.Summary.HighConfidence = highConfidence
+	return output
+}
+
+func issueHasLabel(iss model.Issue, label string) bool {
+	for _, lbl := range iss.Labels {
+		if lbl == label {
+			return true
+		}
+	}
+	return false
+}
+
+// ---------------------------------------------------------------------------
+// Insights
+// ---------------------------------------------------------------------------
+
+type robotInsightsFullStats struct {
+	PageRank          map[string]float64 `json:"pagerank"`
+	Betweenness       map[string]float64 `json:"betweenness"`
+	Eigenvector       map[string]float64 `json:"eigenvector"`
+	Hubs              map[string]float64 `json:"hubs"`
+	Authorities       map[string]float64 `json:"authorities"`
+	CriticalPathScore map[string]float64 `json:"critical_path_score"`
+	CoreNumber        map[string]int     `json:"core_number"`
+	Slack             map[string]float64 `json:"slack"`
+	Articulation      []string           `json:"articulation_points"`
+}
+
+type robotInsightsOutput struct {
+	GeneratedAt    string                  `json:"generated_at"`
+	DataHash       string                  `json:"data_hash"`
+	AsOf           string                  `json:"as_of,omitempty"`        // Historical snapshot ref
+	AsOfCommit     string                  `json:"as_of_commit,omitempty"` // Resolved commit SHA
+	AnalysisConfig analysis.AnalysisConfig `json:"analysis_config"`
+	Status         analysis.MetricStatus   `json:"status"`
+	LabelScope     string                  `json:"label_scope,omitempty"`   // bv-122: Label filter applied
+	LabelContext   *analysis.LabelHealth   `json:"label_context,omitempty"` // bv-122: Health context for scoped label
+	analysis.Insights
+	FullStats        interface{}                `json:"full_stats"`
+	TopWhatIfs       []analysis.WhatIfEntry     `json:"top_what_ifs,omitempty"`      // Issues with highest downstream impact (bv-83)
+	AdvancedInsights *analysis.AdvancedInsights `json:"advanced_insights,omitempty"` // bv-181: Canonical advanced features
+	UsageHints       []string                   `json:"usage_hints"`                 // bv-84: Agent-friendly hints
+}
+
+// insightsMapLimit caps metric maps in insights output to avoid overload.
+// Override with BV_INSIGHTS_MAP_LIMIT.
+func insightsMapLimit() int {
+	mapLimit := 200
+	if v := os.Getenv("BV_INSIGHTS_MAP_LIMIT"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			mapLimit = n
+		}
+	}
+	return mapLimit
+}
+
+func limitFloatMap(m map[string]float64, limit int) map[string]float64 {
+	if limit <= 0 || limit >= len(m) {
+		return m
+	}
+	type kv struct {
+		k string
+		v float64
+	}
+	var items []kv
+	for k, v := range m {
+		items = append(items, kv{k, v})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].v == items[j].v {
+			return items[i].k < items[j].k
+		}
+		return items[i].v > items[j].v
+	})
+	trim := make(map[string]float64, limit)
+	for i := 0; i < limit; i++ {
+		trim[items[i].k] = items[i].v
+	}
+	return trim
+}
+
+func limitIntMap(m map[string]int, limit int) map[string]int {
+	if limit <= 0 || len(m) <= limit {
+		return m
+	}
+	trim := make(map[string]int, limit)
+	count := 0
+	for k, v := range m {
+		trim[k] = v
+		count++
+		if count >= limit {
+			break
+		}
+	}
+	return trim
+}
+
+// scopeMap keeps the metric entries for issues matching --query
+func scopeMap[V any](meta robotMeta, m map[string]V) map[string]V {
+	if meta.Scope == nil {
+		return m
+	}
+	scoped := make(map[string]V, len(meta.Scope))
+	for id, v := range m {
+		if meta.Scope[id] {
+			scoped[id] = v
+		}
+	}
+	return scoped
+}
+
+// scopeIDs keeps the IDs matching --query
+func scopeIDs(meta robotMeta, ids []string) []string {
+	if meta.Scope == nil {
+		return ids
+	}
+	var scoped []string
+	for _, id := range ids {
+		if meta.Scope[id] {
+			scoped = append(scoped, id)
+		}
+	}
+	return scoped
+}
+
+func limitStrings(s []string, limit int) []string {
+	if limit <= 0 || len(s) <= limit {
+		return s
+	}
+	return s[:limit]
+}
+
+// buildInsightsOutput assembles the --robot-insights payload. stats must have
+// Phase 2 complete.
+func buildInsightsOutput(meta robotMeta, issues []model.Issue, analyzer *analysis.Analyzer, stats *analysis.GraphStats) robotInsightsOutput {
+	// Generate top 50 lists for summary, but full stats are included in the struct
+	insightsLimit := 50
+	var insights analysis.Insights
+	if meta.Scope != nil {
+		insights = stats.GenerateInsights(0)
+		insights.Restrict(meta.inScope, insightsLimit)
+	} else {
+		insights = stats.GenerateInsights(insightsLimit)
+	}
+
+	// Add project-level velocity snapshot (using dedicated helper for efficiency)
+	if v := analysis.ComputeProjectVelocity(issues, time.Now(), 8); v != nil {
+		snap := &analysis.VelocitySnapshot{
+			Closed7:   v.ClosedLast7Days,
+			Closed30:  v.ClosedLast30Days,
+			AvgDays:   v.AvgDaysToClose,
+			Estimated: v.Estimated,
+		}
+		if len(v.Weekly) > 0 {
+			snap.Weekly = make([]int, len(v.Weekly))
+			for i := range v.Weekly {
+				snap.Weekly[i] = v.Weekly[i].Closed
+			}
+		}
+		insights.Velocity = snap
+	}
+
+	insights.WeightedCriticalPath = analyzer.WeightedCriticalPath()
+
+	mapLimit := insightsMapLimit()
+	fullStats := robotInsightsFullStats{
+		PageRank:          limitFloatMap(scopeMap(meta, stats.PageRank()), mapLimit),
+		Betweenness:       limitFloatMap(scopeMap(meta, stats.Betweenness()), mapLimit),
+		Eigenvector:       limitFloatMap(scopeMap(meta, stats.Eigenvector()), mapLimit),
+		Hubs:              limitFloatMap(scopeMap(meta, stats.Hubs()), mapLimit),
+		Authorities:       limitFloatMap(scopeMap(meta, stats.Authorities()), mapLimit),
+		CriticalPathScore: limitFloatMap(scopeMap(meta, stats.CriticalPathScore()), mapLimit),
+		CoreNumber:        limitIntMap(scopeMap(meta, stats.CoreNumber()), mapLimit),
+		Slack:             limitFloatMap(scopeMap(meta, stats.Slack()), mapLimit),
+		Articulation:      limitStrings(scopeIDs(meta, stats.ArticulationPoints()), mapLimit),
+	}
+
+	var topWhatIfs []analysis.WhatIfEntry
+	if meta.Scope == nil {
+		topWhatIfs = analyzer.TopWhatIfDeltas(10)
+	} else {
+		for _, entry := range analyzer.TopWhatIfDeltas(len(issues)) {
+			if meta.inScope(entry.IssueID) && len(topWhatIfs) < 10 {
+				topWhatIfs = append(topWhatIfs, entry)
+			}
+		}
+	}
+
+	return robotInsightsOutput{
+		GeneratedAt:    robotTimestamp(),
+		DataHash:       meta.DataHash,
+		AsOf:           meta.AsOf,
+		AsOfCommit:     meta.AsOfCommit,
+		AnalysisConfig: stats.Config,
+		Status:         stats.Status(),
+		LabelScope:     meta.LabelScope,
+		LabelContext:   meta.LabelContext,
+		Insights:       insights,
+		FullStats:      fullStats,
+		// Get top what-if deltas for issues with highest downstream impact (bv-83)
+		TopWhatIfs: topWhatIfs,
+		// Generate advanced insights with canonical structure (bv-181)
+		AdvancedInsights: analyzer.GenerateAdvancedInsights(analysis.DefaultAdvancedInsightsConfig()),
+		UsageHints: []string{
+			"jq '.Bottlenecks[:5] | map(.ID)' - Top 5 bottleneck IDs",
+			"jq '.CriticalPath[:3]' - Top 3 critical path items",
+			"jq '.WeightedCriticalPath.chain | map(.id)' - Estimate-weighted critical chain",
+			"jq '.WeightedCriticalPath.total_minutes' - Minimum time to finish all open work",
+			"jq '.top_what_ifs[] | select(.delta.direct_unblocks > 2)' - High-impact items",
+			"jq '.full_stats.pagerank | to_entries | sort_by(-.value)[:5]' - Top PageRank",
+			"jq '.full_stats.core_number | to_entries | sort_by(-.value)[:5]' - Strongly embedded nodes (k-core)",
+			"jq '.full_stats.articulation_points' - Structural cut points",
+			"jq '.Slack[:5]' - Nodes with slack (good parallel work candidates)",
+			"jq '.Cycles | length' - Count of detected cycles",
+			"jq '.advanced_insights.cycle_break' - Cycle break suggestions (bv-181)",
+			"BV_INSIGHTS_MAP_LIMIT=50 bv --robot-insights - Reduce map sizes",
+		},
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Alerts
+// ---------------------------------------------------------------------------
+
+// alertFilters mirrors the --severity / --alert-type / --alert-label flags.
+type alertFilters struct {
+	Severity string
+	Type     string
+	Label    string
+}
+
+type robotAlertsOutput struct {
+	GeneratedAt string        `json:"generated_at"`
+	DataHash    string        `json:"data_hash"`
+	Alerts      []drift.Alert `json:"alerts"`
+	Summary     struct {
+		Total    int `json:"total"`
+		Critical int `json:"critical"`
+		Warning  int `json:"warning"`
+		Info     int `json:"info"`
+	} `json:"summary"`
+	UsageHints []string `json:"usage_hints"`
+}
+
+// computeDriftAlerts evaluates drift + proactive alerts against the current
+// state, using the current state as its own baseline.
+func computeDriftAlerts(issues []model.Issue, analyzer *analysis.Analyzer, stats *analysis.GraphStats, driftConfig *drift.Config) []drift.Alert {
+	openCount, closedCount, blockedCount := 0, 0, 0
+	for _, issue := range issues {
+		switch issue.Status {
+		case model.StatusClosed:
+			closedCount++
+		case model.StatusBlocked:
+			blockedCount++
+		default:
+			openCount++
+		}
+	}
+	curStats := baseline.GraphStats{
+		NodeCount:       stats.NodeCount,
+		EdgeCount:       stats.EdgeCount,
+		Density:         stats.Density,
+		OpenCount:       openCount,
+		ClosedCount:     closedCount,
+		BlockedCount:    blockedCount,
+		CycleCount:      len(stats.Cycles()),
+		ActionableCount: len(analyzer.GetActionableIssues()),
+	}
+	bl := &baseline.Baseline{Stats: curStats}
+	cur := &baseline.Baseline{Stats: curStats, Cycles: stats.Cycles()}
+
+	calc := drift.NewCalculator(bl, cur, driftConfig)
+	calc.SetIssues(issues)
+	return calc.Calculate().Alerts
+}
+
+func buildAlertsOutput(dataHash string, alerts []drift.Alert, filters alertFilters) robotAlertsOutput {
+	// Apply optional filters
+	filtered := make([]drift.Alert, 0, len(alerts))
+	for _, a := range alerts {
+		if filters.Severity != "" && string(a.Severity) != filters.Severity {
+			continue
+		}
+		if filters.Type != "" && string(a.Type) != filters.Type {
+			continue
+		}
+		if filters.Label != "" {
+			found := false
+			for _, d := range a.Details {
+				if strings.Contains(strings.ToLower(d), strings.ToLower(filters.Label)) {
+					found = true
+					break
+				}
+			}
+			if !found && a.Label != "" && !strings.Contains(strings.ToLower(a.Label), strings.ToLower(filters.Label)) {
+				continue
+			}
+		}
+		filtered = append(filtered, a)
+	}
+
+	output := robotAlertsOutput{
+		GeneratedAt: robotTimestamp(),
+		DataHash:    dataHash,
+		Alerts:      filtered,
+		UsageHints: []string{
+			"--severity=warning --alert-type=stale_issue   # stale warnings only",
+			"--alert-type=blocking_cascade                 # high-unblock opportunities",
+			"jq '.alerts | map(.issue_id)'                # list impacted issues",
+		},
+	}
+	for _, a := range filtered {
+		switch a.Severity {
+		case drift.SeverityCritical:
+			output.Summary.Critical++
+		case drift.SeverityWarning:
+			output.Summary.Warning++
+		case drift.SeverityInfo:
+			output.Summary.Info++
+		}
+		output.Summary.Total++
+	}
+	return output
+}
+
+// ---------------------------------------------------------------------------
+// Label health
+// ---------------------------------------------------------------------------
+
+type robotLabelHealthOutput struct {
+	GeneratedAt    string                       `json:"generated_at"`
+	DataHash       string                       `json:"data_hash"`
+	AnalysisConfig analysis.LabelHealthConfig   `json:"analysis_config"`
+	Results        analysis.LabelAnalysisResult `json:"results"`
+	UsageHints     []string                     `json:"usage_hints"`
+}
+
+func buildLabelHealthOutput(dataHash string, issues []model.Issue) robotLabelHealthOutput {
+	cfg := analysis.DefaultLabelHealthConfig()
+	return robotLabelHealthOutput{
+		GeneratedAt:    robotTimestamp(),
+		DataHash:       dataHash,
+		AnalysisConfig: cfg,
+		Results:        analysis.ComputeAllLabelHealth(issues, cfg, time.Now().UTC(), nil),
+		UsageHints: []string{
+			"jq '.results.summaries | sort_by(.health) | .[:3]' - Critical labels",
+			"jq '.results.labels[] | select(.health_level == \"critical\")' - Critical details",
+			"jq '.results.cross_label_flow.bottleneck_labels' - Bottleneck labels",
+			"jq '.results.attention_needed' - Labels needing attention",
+		},
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Forecast
+// ---------------------------------------------------------------------------
+
+// ForecastSummary aggregates a multi-issue forecast (bv-158).
+type ForecastSummary struct {
+	TotalMinutes  int       `json:"total_minutes"`
+	TotalDays     float64   `json:"total_days"`
+	AvgConfidence float64   `json:"avg_confidence"`
+	EarliestETA   time.Time `json:"earliest_eta"`
+	LatestETA     time.Time `json:"latest_eta"`
+}
+
+// ForecastOutput is the --robot-forecast payload (bv-158).
+type ForecastOutput struct {
+	GeneratedAt   time.Time              `json:"generated_at"`
+	Agents        int                    `json:"agents"`
+	Filters       map[string]string      `json:"filters,omitempty"`
+	ForecastCount int                    `json:"forecast_count"`
+	Forecasts     []analysis.ETAEstimate `json:"forecasts"`
+	Summary       *ForecastSummary       `json:"summary,omitempty"`
+}
+
+// forecastRequest mirrors the --robot-forecast / --forecast-* flags.
+type forecastRequest struct {
+	Target string // Issue ID or "all"
+	Label  string
+	Sprint string
+	Agents int
+
+	// Monte Carlo options (--monte-carlo).
+	Trials int
+	Seed   int64
+}
+
+// MonteCarloForecastOutput is the --robot-forecast --monte-carlo payload.
+type MonteCarloForecastOutput struct {
+	GeneratedAt time.Time         `json:"generated_at"`
+	Target      string            `json:"target"`
+	Filters     map[string]string `json:"filters,omitempty"`
+	*analysis.MonteCarloForecast
+}
+
+// filterForecastIssues applies the label and sprint filters of req. sprints is
+// only consulted when req.Sprint is set; an unknown sprint is an error.
+func filterForecastIssues(issues []model.Issue, sprints []model.Sprint, req forecastRequest) ([]model.Issue, error) {
+	var sprintBeadIDs map[string]bool
+	if req.Sprint != "" {
+		for _, s := range sprints {
+			if s.ID == req.Sprint {
+				sprintBeadIDs = make(map[string]bool)
+				for _, bid := range s.BeadIDs {
+					sprintBeadIDs[bid] = true
+				}
+				break
+			}
+		}
+		if sprintBeadIDs == nil {
+			return nil, fmt.Errorf("sprint not found: %s", req.Sprint)
+		}
+	}
+
+	targetIssues := make([]model.Issue, 0, len(issues))
+	for _, iss := range issues {
+		if req.Label != "" && !issueHasLabel(iss, req.Label) {
+			continue
+		}
+		if sprintBeadIDs != nil && !sprintBeadIDs[iss.ID] {
+			continue
+		}
+		targetIssues = append(targetIssues, iss)
+	}
+	return targetIssues, nil
+}
+
+func forecastFilters(req forecastRequest) map[string]string {
+	filters := make(map[string]string)
+	if req.Label != "" {
+		filters["label"] = req.Label
+	}
+	if req.Sprint != "" {
+		filters["sprint"] = req.Sprint
+	}
+	if len(filters) == 0 {
+		return nil
+	}
+	return filters
+}
+
+// buildMonteCarloForecastOutput runs the Monte Carlo forecaster. For a single
+// target the simulation covers that issue and its open blockers; for "all" it
+// covers the open issues matching the filters (every open issue without
+// filters). Label and sprint filters also narrow the reported groups.
+func buildMonteCarloForecastOutput(issues []model.Issue, sprints []model.Sprint, req forecastRequest, now time.Time) (MonteCarloForecastOutput, error) {
+	if req.Trials > analysis.MaxMonteCarloTrials {
+		return MonteCarloForecastOutput{}, fmt.Errorf("trials must be at most %d, got %d", analysis.MaxMonteCarloTrials, req.Trials)
+	}
+	cfg := analysis.MonteCarloConfig{
+		Trials:  req.Trials,
+		Agents:  req.Agents,
+		Seed:    req.Seed,
+		Now:     now,
+		Sprints: sprints,
+	}
+
+	if req.Target == "all" {
+		if req.Label != "" || req.Sprint != "" {
+			targets, err := filterForecastIssues(issues, sprints, req)
+			if err != nil {
+				return MonteCarloForecastOutput{}, err
+			}
+			cfg.Focus = []string{}
+			for _, iss := range targets {
+				if iss.Status != model.StatusClosed && iss.Status != model.StatusTombstone {
+					cfg.Focus = append(cfg.Focus, iss.ID)
+				}
+			}
+		}
+	} else {
+		var target *model.Issue
+		for i := range issues {
+			if issues[i].ID == req.Target {
+				target = &issues[i]
+				break
+			}
+		}
+		if target == nil {
+			return MonteCarloForecastOutput{}, fmt.Errorf("issue %q not found", req.Target)
+		}
+		if target.Status == model.StatusClosed || target.Status == model.StatusTombstone {
+			return MonteCarloForecastOutput{}, fmt.Errorf("issue %q is already %s", req.Target, target.Status)
+		}
+		cfg.Focus = []string{req.Target}
+	}
+	if req.Sprint != "" {
+		for _, s := range sprints {
+			if s.ID == req.Sprint {
+				cfg.Sprints = []model.Sprint{s}
+			}
+		}
+	}
+	simulated := issues
+	if cfg.Focus != nil && len(cfg.Focus) == 0 {
+		// The filters matched no open issues, so there is nothing to simulate.
+		simulated = nil
+	}
+
+	fc := analysis.ForecastMonteCarlo(simulated, cfg)
+	if req.Label != "" {
+		var kept []analysis.GroupForecast
+		for _, g := range fc.Labels {
+			if g.Name == req.Label {
+				kept = append(kept, g)
+			}
+		}
+		fc.Labels = kept
+	}
+
+	return MonteCarloForecastOutput{
+		GeneratedAt:        now.UTC(),
+		Target:             req.Target,
+		Filters:            forecastFilters(req),
+		MonteCarloForecast: &fc,
+	}, nil
+}
+
+// buildForecastOutput computes ETA forecasts. sprints is only consulted when
+// req.Sprint is set; an unknown sprint is an error.
+func buildForecastOutput(issues []model.Issue, graphStats *analysis.GraphStats, sprints []model.Sprint, req forecastRequest, now time.Time) (ForecastOutput, error) {
+	// Filter issues by label and sprint if specified
+	targetIssues, err := filterForecastIssues(issues, sprints, req)
+	if err != nil {
+		return ForecastOutput{}, err
+	}
+
+	agents := req.Agents
+	if agents <= 0 {
+		agents = 1
+	}
+
+	var forecasts []analysis.ETAEstimate
+	if req.Target == "all" {
+		// Forecast all open issues
+		for _, iss := range targetIssues {
+			if iss.Status == model.StatusClosed {
+				continue
+			}
+			eta, err := analysis.EstimateETAForIssue(issues, graphStats, iss.ID, agents, now)
+			if err != nil {
+				continue
+			}
+			forecasts = append(forecasts, eta)
+		}
+	} else {
+		// Single issue forecast
+		eta, err := analysis.EstimateETAForIssue(issues, graphStats, req.Target, agents, now)
+		if err != nil {
+			return ForecastOutput{}, err
+		}
+		forecasts = append(forecasts, eta)
+	}
+
+	// Build summary if multiple forecasts
+	var summary *ForecastSummary
+	if len(forecasts) > 1 {
+		totalMin := 0
+		totalConf := 0.0
+		earliest := forecasts[0].ETADate
+		latest := forecasts[0].ETADate
+		for _, f := range forecasts {
+			totalMin += f.EstimatedMinutes
+			totalConf += f.Confidence
+			if f.ETADate.Before(earliest) {
+				earliest = f.ETADate
+			}
+			if f.ETADate.After(latest) {
+				latest = f.ETADate
+			}
+		}
+		summary = &ForecastSummary{
+			TotalMinutes:  totalMin,
+			TotalDays:     float64(totalMin) / (60.0 * 8.0), // 8hr workday
+			AvgConfidence: totalConf / float64(len(forecasts)),
+			EarliestETA:   earliest,
+			LatestETA:     latest,
+		}
+	}
+
+	output := ForecastOutput{
+		GeneratedAt:   now.UTC(),
+		Agents:        agents,
+		ForecastCount: len(forecasts),
+		Forecasts:     forecasts,
+		Summary:       summary,
+		Filters:       forecastFilters(req),
+	}
+	return output, nil
+}
+
+// ---------------------------------------------------------------------------
+// Suggest / history
+// ---------------------------------------------------------------------------
+
+// parseSuggestType maps the --suggest-type values onto a suggestion filter.
+// An empty string selects all types.
+func parseSuggestType(s string) (analysis.SuggestionType, error) {
+	switch s {
+	case "duplicate", "duplicates":
+		return analysis.SuggestionPotentialDuplicate, nil
+	case "dependency", "dependencies":
+		return analysis.SuggestionMissingDependency, nil
+	case "label", "labels":
+		return analysis.SuggestionLabelSuggestion, nil
+	case "cycle", "cycles":
+		return analysis.SuggestionCycleWarning, nil
+	case "":
+		return "", nil
+	default:
+		return "", fmt.Errorf("Invalid suggest-type: %s (use: duplicate, dependency, label, cycle)", s)
+	}
+}
+
+// historyRequest mirrors the --bead-history / --history-* / --min-confidence flags.
+type historyRequest struct {
+	BeadID        string
+	Limit         int
+	Since         string
+	MinConfidence float64
+}
+
+// buildHistoryReport correlates beads with git commits for --robot-history.
+func buildHistoryReport(repoPath, beadsPath string, issues []model.Issue, req historyRequest) (*correlation.HistoryReport, error) {
+	opts := correlation.CorrelatorOptions{
+		BeadID: req.BeadID,
+		Limit:  req.Limit,
+	}
+
+	// Parse --history-since if provided
+	if req.Since != "" {
+		since, err := recipe.ParseRelativeTime(req.Since, time.Now())
+		if err != nil {
+			return nil, fmt.Errorf("parsing --history-since: %w", err)
+		}
+		if !since.IsZero() {
+			opts.Since = &since
+		}
+	}
+
+	// Convert issues to BeadInfo for correlator
+	beadInfos := make([]correlation.BeadInfo, len(issues))
+	for i, issue := range issues {
+		beadInfos[i] = correlation.BeadInfo{
+			ID:     issue.ID,
+			Title:  issue.Title,
+			Status: string(issue.Status),
+		}
+	}
+
+	// Generate report with explicit beads path
+	correlator := correlation.NewCorrelator(repoPath, beadsPath)
+	report, err := correlator.GenerateReport(beadInfos, opts)
+	if err != nil {
+		return nil, fmt.Errorf("generating history report: %w", err)
+	}
+
+	// Apply confidence filter if specified
+	if req.MinConfidence > 0 {
+		scorer := correlation.NewScorer()
+		report.Histories = scorer.FilterHistoriesByConfidence(report.Histories, req.MinConfidence)
+
+		// Rebuild commit index after filtering
+		report.CommitIndex = make(correlation.CommitIndex)
+		for beadID, history := range report.Histories {
+			for _, commit := range history.Commits {
+				report.CommitIndex[commit.SHA] = append(report.CommitIndex[commit.SHA], beadID)
+			}
+		}
+
+		// Update stats
+		report.Stats.BeadsWithCommits = 0
+		for _, history := range report.Histories {
+			if len(history.Commits) > 0 {
+				report.Stats.BeadsWithCommits++
+			}
+		}
+	}
+	return report, nil
+}
+
+// ---------------------------------------------------------------------------
+// Schedule
+// ---------------------------------------------------------------------------
+
+// ScheduleOutput is the --robot-schedule payload.
+type ScheduleOutput struct {
+	GeneratedAt  string `json:"generated_at"`
+	DataHash     string `json:"data_hash"`
+	RosterSource string `json:"roster_source"` // ".bv/roster.yaml" or "assignees"
+	analysis.Schedule
+	UsageHints []string `json:"usage_hints"`
+}
+
+// buildScheduleOutput schedules open work onto the project's roster, or onto
+// current assignees plus generic agents (at least minAgents) without one.
+func buildScheduleOutput(dataHash string, issues []model.Issue, stats *analysis.GraphStats, projectDir string, minAgents int, now time.Time) (ScheduleOutput, error) {
+	roster, err := analysis.LoadRoster(projectDir)
+	if err != nil {
+		return ScheduleOutput{}, err
+	}
+	source := ".bv/" + analysis.RosterFilename
+	if len(roster) == 0 {
+		roster = analysis.DefaultRoster(issues, minAgents)
+		source = "assignees"
+	}
+
+	return ScheduleOutput{
+		GeneratedAt:  robotTimestamp(),
+		DataHash:     dataHash,
+		RosterSource: source,
+		Schedule:     analysis.ComputeSchedule(issues, stats, analysis.ScheduleOptions{Roster: roster, Now: now}),
+		UsageHints: []string{
+			"jq '.agents[] | {name, next: .issues[0].issue_id}' - What each agent picks up first",
+			"jq '.agents[] | {name, utilization}' - Load per agent",
+			"jq '.end' - When all schedulable work is done",
+			"jq '.unscheduled' - Work nobody on the roster can take",
+		},
+	}, nil
+}
+
+// ---------------------------------------------------------------------------
+// Epics
+// ---------------------------------------------------------------------------
+
+// EpicsOutput is the --robot-epics payload.
+type EpicsOutput struct {
+	GeneratedAt string `json:"generated_at"`
+	DataHash    string `json:"data_hash"`
+	analysis.EpicReport
+	UsageHints []string `json:"usage_hints"`
+}
+
+// buildEpicsOutput rolls up progress for every epic in the parent/child
+// hierarchy.
+func buildEpicsOutput(dataHash string, issues []model.Issue, now time.Time) EpicsOutput {
+	return EpicsOutput{
+		GeneratedAt: robotTimestamp(),
+		DataHash:    dataHash,
+		EpicReport:  analysis.ComputeEpicRollups(issues, analysis.EpicOptions{Now: now}),
+		UsageHints: []string{
+			"jq '.epics[] | {id, percent_done, forecast_date}' - Progress per epic",
+			"jq '.ready_to_close' - Open epics whose children are all closed",
+			"jq '.epics[] | select(.blocked_children) | {id, blocked_children}' - Epics with blocked work",
+			"jq '.epics[] | select(.stale_children) | {id, stale_children}' - Epics with stale work",
+		},
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Escalations
+// ---------------------------------------------------------------------------
+
+// EscalationsOutput is the --robot-escalations payload.
+type EscalationsOutput struct {
+	GeneratedAt  string `json:"generated_at"`
+	DataHash     string `json:"data_hash"`
+	RosterSource string `json:"roster_source"` // ".bv/roster.yaml" or "assignees"
+	analysis.EscalationReport
+	UsageHints []string `json:"usage_hints"`
+}
+
+// buildEscalationsOutput lists the beads waiting on a lead: impossible,
+// escalated, or past their acknowledgment deadline. Re-assignees come from
+// the project's roster, or from everyone who has been assigned work.
+func buildEscalationsOutput(dataHash string, issues []model.Issue, projectDir string, now time.Time) (EscalationsOutput, error) {
+	roster, err := analysis.LoadRoster(projectDir)
+	if err != nil {
+		return EscalationsOutput{}, err
+	}
+	source := ".bv/" + analysis.RosterFilename
+	if len(roster) == 0 {
+		source = "assignees"
+	}
+
+	return EscalationsOutput{
+		GeneratedAt:      robotTimestamp(),
+		DataHash:         dataHash,
+		RosterSource:     source,
+		EscalationReport: analysis.ComputeEscalations(issues, analysis.EscalationOptions{Now: now, Roster: roster}),
+		UsageHints: []string{
+			"jq '.items[] | {id, kind, last_reason}' - What needs a decision and why",
+			"jq '.items[] | {id, to: .suggested_assignees[0].agent}' - Best re-assignee per bead",
+			"jq '.items[] | select(.kind == \"overdue\") | {id, assignee, overdue_hours}' - Missed ack deadlines",
+			"jq '.items[].history' - Decline/defer/expiry history parsed from bd-ack comments",
+		},
+	}, nil
+}
+
+// AgentsOutput is the --robot-agents payload.
+type AgentsOutput struct {
+	GeneratedAt  string `json:"generated_at"`
+	DataHash     string `json:"data_hash"`
+	RosterSource string `json:"roster_source"` // ".bv/roster.yaml" or "assignees"
+	analysis.AgentReport
+	UsageHints []string `json:"usage_hints"`
+}
+
+// buildAgentsOutput aggregates each agent's ack track record. history is
+// optional; without it reopen rates and commit counts are left out.
+func buildAgentsOutput(dataHash string, issues []model.Issue, projectDir string, history *correlation.HistoryReport) (AgentsOutput, error) {
+	roster, err := analysis.LoadRoster(projectDir)
+	if err != nil {
+		return AgentsOutput{}, err
+	}
+	source := ".bv/" + analysis.RosterFilename
+	if len(roster) == 0 {
+		source = "assignees"
+	}
+
+	return AgentsOutput{
+		GeneratedAt:  robotTimestamp(),
+		DataHash:     dataHash,
+		RosterSource: source,
+		AgentReport:  analysis.ComputeAgentStats(issues, analysis.AgentStatsOptions{Roster: roster, History: history}),
+		UsageHints: []string{
+			"jq '.agents[] | {agent, acceptance_rate, bounce_rate, wip}' - Who takes work and who hands it back",
+			"jq '.agents | sort_by(.median_cycle_time_hours) | .[] | {agent, median_cycle_time_hours}' - Fastest closers",
+			"jq '.agents[] | select(.reopen_rate > 0.2) | {agent, reopened, reopen_rate}' - Work that did not stick",
+			"jq '.agents[] | {agent, top: .labels[0].label, specialization}' - Label specialisation",
+			"bv --robot-triage --robot-triage-agents - Suggest who should pick up each recommendation",
+		},
+	}, nil
+}
+
+// loadAgentHistory builds the git history used by the agent metrics, or
+// returns nil when the project is not a git repository or has no beads file.
+// An empty beadsPath is resolved from the beads directory.
+func loadAgentHistory(repoPath, beadsPath string, issues []model.Issue, limit int) *correlation.HistoryReport {
+	if err := correlation.ValidateRepository(repoPath); err != nil {
+		return nil
+	}
+	if beadsPath == "" {
+		beadsDir, err := loader.GetBeadsDir("")
+		if err != nil {
+			return nil
+		}
+		if beadsPath, err = loader.FindJSONLPath(beadsDir); err != nil {
+			return nil
+		}
+	}
+	report, err := buildHistoryReport(repoPath, beadsPath, issues, historyRequest{Limit: limit})
+	if err != nil {
+		return nil
+	}
+	return report
+}
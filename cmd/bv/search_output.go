@@ -17,6 +17,27 @@ type robotSearchResult struct {
 	TextScore       float64            `json:"text_score,omitempty"`
 	Title           string             `json:"title,omitempty"`
 	ComponentScores map[string]float64 `json:"component_scores,omitempty"`
+
+	// Where the match was: the best chunk's field ("summary", "design",
+	// "comment", ...), comment ID and an excerpt around the query.
+	Field     string `json:"field,omitempty"`
+	CommentID int64  `json:"comment_id,omitempty"`
+	Chunk     string `json:"chunk,omitempty"`
+	Snippet   string `json:"snippet,omitempty"`
+}
+
+// robotSnippetRunes bounds robotSearchResult.Snippet
+const robotSnippetRunes = 200
+
+func (r *robotSearchResult) setMatch(c search.Chunk, query string) {
+	if c.Key == "" {
+		return
+	}
+	r.Field, r.CommentID, r.Chunk = c.Field, c.CommentID, c.Key
+	r.Snippet = search.Snippet(c.Body, query, robotSnippetRunes)
+	if r.Snippet == "" {
+		r.Snippet = r.Title
+	}
 }
 
 type robotSearchOutput struct {
@@ -0,0 +1,655 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/correlation"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/drift"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/export"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/watcher"
+)
+
+// DefaultServeAddr is the listen address for `bv serve` when --addr is not given.
+// Loopback only: the API exposes the full issue set.
+const DefaultServeAddr = "127.0.0.1:7777"
+
+// serveSnapshot is an immutable view of one load of the beads file together
+// with its warm analysis. A reload swaps in a new snapshot; in-flight requests
+// keep using the one they started with.
+type serveSnapshot struct {
+	issues   []model.Issue
+	dataHash string
+	loadedAt time.Time
+	analyzer *analysis.Analyzer
+	stats    *analysis.GraphStats
+
+	// Analyzer helpers are not safe for concurrent use, so requests that
+	// touch the analyzer serialize on this lock.
+	mu sync.Mutex
+}
+
+// robotServer keeps issues loaded and serves robot outputs over HTTP.
+type robotServer struct {
+	beadsPath  string
+	projectDir string
+	cache      *analysis.Cache
+
+	mu   sync.RWMutex
+	snap *serveSnapshot
+}
+
+func newRobotServer(beadsPath, projectDir string) (*robotServer, error) {
+	s := &robotServer{
+		beadsPath:  beadsPath,
+		projectDir: projectDir,
+		cache:      analysis.NewCache(analysis.DefaultCacheTTL),
+	}
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// reload re-reads the beads file and starts analysis in the background. If the
+// data hash is unchanged the current snapshot is kept.
+func (s *robotServer) reload() error {
+	issues, err := loader.LoadIssuesFromFileWithOptions(s.beadsPath, loader.ParseOptions{
+		WarningHandler: func(msg string) {
+			if os.Getenv("BV_ROBOT") != "1" {
+				fmt.Fprintf(os.Stderr, "Warning: %s\n", msg)
+			}
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("loading %s: %w", s.beadsPath, err)
+	}
+
+	cached := analysis.NewCachedAnalyzer(issues, s.cache)
+	s.mu.RLock()
+	unchanged := s.snap != nil && s.snap.dataHash == cached.DataHash()
+	s.mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	snap := &serveSnapshot{
+		issues:   issues,
+		dataHash: cached.DataHash(),
+		loadedAt: time.Now().UTC(),
+		analyzer: cached.Analyzer,
+		// Phase 2 runs in the background; the cache is filled when it completes.
+		stats: cached.AnalyzeAsync(context.Background()),
+	}
+
+	s.mu.Lock()
+	s.snap = snap
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *robotServer) snapshot() *serveSnapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.snap
+}
+
+// routes maps endpoint paths to handlers. Each endpoint returns the same JSON
+// as the --robot-* flag of the same name.
+func (s *robotServer) routes() map[string]func(*serveSnapshot, *http.Request) (interface{}, error) {
+	return map[string]func(*serveSnapshot, *http.Request) (interface{}, error){
+		"/triage":       s.handleTriage,
+		"/next":         s.handleNext,
+		"/plan":         s.handlePlan,
+		"/insights":     s.handleInsights,
+		"/priority":     s.handlePriority,
+		"/graph":        s.handleGraph,
+		"/suggest":      s.handleSuggest,
+		"/alerts":       s.handleAlerts,
+		"/history":      s.handleHistory,
+		"/forecast":     s.handleForecast,
+		"/label-health": s.handleLabelHealth,
+		"/schedule":     s.handleSchedule,
+		"/epics":        s.handleEpics,
+		"/escalations":  s.handleEscalations,
+		"/agents":       s.handleAgents,
+	}
+}
+
+// Handler returns the HTTP handler for the API.
+func (s *robotServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", s.handleHealth)
+	for path, fn := range s.routes() {
+		mux.HandleFunc(path, s.robotEndpoint(fn, routeInputs[path]))
+	}
+	return mux
+}
+
+// endpointInputs lists what an endpoint reads besides the issues, so its ETag
+// changes when any of them does.
+type endpointInputs struct {
+	git   bool                             // git history, tracked by HEAD
+	files []func(projectDir string) string // side files, tracked by content
+}
+
+func sprintsPath(projectDir string) string {
+	return filepath.Join(projectDir, ".beads", loader.SprintsFileName)
+}
+
+var routeInputs = map[string]endpointInputs{
+	"/history":     {git: true},
+	"/agents":      {git: true, files: []func(string) string{analysis.RosterPath}},
+	"/alerts":      {files: []func(string) string{drift.ConfigPath}},
+	"/forecast":    {files: []func(string) string{sprintsPath}},
+	"/schedule":    {files: []func(string) string{analysis.RosterPath}},
+	"/escalations": {files: []func(string) string{analysis.RosterPath}},
+}
+
+// etag is the data hash of snap, suffixed with a digest of the endpoint's
+// other inputs when it has any. A missing side file digests as empty.
+func (s *robotServer) etag(snap *serveSnapshot, in endpointInputs) string {
+	if !in.git && len(in.files) == 0 {
+		return `"` + snap.dataHash + `"`
+	}
+	h := sha256.New()
+	if in.git {
+		h.Write([]byte(gitHead(s.projectDir)))
+	}
+	for _, path := range in.files {
+		data, _ := os.ReadFile(path(s.projectDir))
+		h.Write(data)
+		h.Write([]byte{0})
+	}
+	return `"` + snap.dataHash + "-" + hex.EncodeToString(h.Sum(nil))[:16] + `"`
+}
+
+// robotEndpoint wraps a payload builder with method checks, ETag handling and
+// JSON encoding. The ETag covers the snapshot's data hash and the endpoint's
+// other inputs, so an agent that sends If-None-Match gets 304 until something
+// the response depends on actually changes.
+func (s *robotServer) robotEndpoint(build func(*serveSnapshot, *http.Request) (interface{}, error), in endpointInputs) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			writeServeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+			return
+		}
+
+		snap := s.snapshot()
+		etag := s.etag(snap, in)
+		w.Header().Set("ETag", etag)
+		w.Header().Set("X-BV-Data-Hash", snap.dataHash)
+		w.Header().Set("Cache-Control", "no-cache")
+		if etagMatches(r.Header.Get("If-None-Match"), etag) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		payload, err := build(snap, r)
+		if err != nil {
+			status := http.StatusInternalServerError
+			var badReq serveBadRequest
+			if errors.As(err, &badReq) {
+				status = http.StatusBadRequest
+			}
+			writeServeError(w, status, err)
+			return
+		}
+		writeServeJSON(w, http.StatusOK, r.Method == http.MethodHead, payload)
+	}
+}
+
+func (s *robotServer) handleHealth(w http.ResponseWriter, r *http.Request) {
+	snap := s.snapshot()
+	output := struct {
+		Status      string `json:"status"`
+		DataHash    string `json:"data_hash"`
+		LoadedAt    string `json:"loaded_at"`
+		IssueCount  int    `json:"issue_count"`
+		BeadsPath   string `json:"beads_path"`
+		Phase2Ready bool   `json:"phase2_ready"`
+	}{
+		Status:      "ok",
+		DataHash:    snap.dataHash,
+		LoadedAt:    snap.loadedAt.Format(time.RFC3339),
+		IssueCount:  len(snap.issues),
+		BeadsPath:   s.beadsPath,
+		Phase2Ready: snap.stats.IsPhase2Ready(),
+	}
+	writeServeJSON(w, http.StatusOK, r.Method == http.MethodHead, output)
+}
+
+func (s *robotServer) meta(snap *serveSnapshot) robotMeta {
+	return robotMeta{DataHash: snap.dataHash}
+}
+
+func (s *robotServer) triage(snap *serveSnapshot, r *http.Request) analysis.TriageResult {
+	snap.stats.WaitForPhase2()
+	snap.mu.Lock()
+	defer snap.mu.Unlock()
+	opts := analysis.TriageOptions{
+		GroupByTrack:  queryBool(r, "by_track"),
+		GroupByLabel:  queryBool(r, "by_label"),
+		WaitForPhase2: true,
+	}
+	return analysis.ComputeTriageFromAnalyzer(snap.analyzer, snap.stats, snap.issues, opts, time.Now())
+}
+
+func (s *robotServer) handleTriage(snap *serveSnapshot, r *http.Request) (interface{}, error) {
+	return buildTriageOutput(s.meta(snap), s.triage(snap, r), loadTriageFeedback()), nil
+}
+
+func (s *robotServer) handleNext(snap *serveSnapshot, r *http.Request) (interface{}, error) {
+	return buildNextOutput(s.meta(snap), s.triage(snap, r)), nil
+}
+
+func (s *robotServer) handlePlan(snap *serveSnapshot, r *http.Request) (interface{}, error) {
+	snap.stats.WaitForPhase2()
+	snap.mu.Lock()
+	defer snap.mu.Unlock()
+	plan := snap.analyzer.GetExecutionPlan()
+	return buildPlanOutput(s.meta(snap), snap.stats.Config, snap.stats.Status(), plan), nil
+}
+
+func (s *robotServer) handleInsights(snap *serveSnapshot, r *http.Request) (interface{}, error) {
+	snap.stats.WaitForPhase2()
+	snap.mu.Lock()
+	defer snap.mu.Unlock()
+	return buildInsightsOutput(s.meta(snap), snap.issues, snap.analyzer, snap.stats), nil
+}
+
+func (s *robotServer) handlePriority(snap *serveSnapshot, r *http.Request) (interface{}, error) {
+	minConf, err := queryFloat(r, "min_confidence")
+	if err != nil {
+		return nil, err
+	}
+	maxResults, err := queryInt(r, "max_results")
+	if err != nil {
+		return nil, err
+	}
+
+	snap.stats.WaitForPhase2()
+	snap.mu.Lock()
+	defer snap.mu.Unlock()
+	recommendations := snap.analyzer.GenerateEnhancedRecommendations()
+	return buildPriorityOutput(s.meta(snap), snap.stats.Config, snap.stats.Status(), snap.issues, recommendations, priorityFilters{
+		MinConfidence: minConf,
+		MaxResults:    maxResults,
+		ByLabel:       r.URL.Query().Get("label"),
+		ByAssignee:    r.URL.Query().Get("assignee"),
+	}), nil
+}
+
+func (s *robotServer) handleGraph(snap *serveSnapshot, r *http.Request) (interface{}, error) {
+	depth, err := queryInt(r, "depth")
+	if err != nil {
+		return nil, err
+	}
+	var format export.GraphExportFormat
+	switch strings.ToLower(r.URL.Query().Get("format")) {
+	case "dot":
+		format = export.GraphFormatDOT
+	case "mermaid":
+		format = export.GraphFormatMermaid
+	default:
+		format = export.GraphFormatJSON
+	}
+
+	snap.stats.WaitForPhase2()
+	return export.ExportGraph(snap.issues, snap.stats, export.GraphExportConfig{
+		Format:   format,
+		Label:    r.URL.Query().Get("label"),
+		Root:     r.URL.Query().Get("root"),
+		Depth:    depth,
+		DataHash: snap.dataHash,
+	})
+}
+
+func (s *robotServer) handleSuggest(snap *serveSnapshot, r *http.Request) (interface{}, error) {
+	config := analysis.DefaultSuggestAllConfig()
+	if r.URL.Query().Has("min_confidence") {
+		minConf, err := queryFloat(r, "min_confidence")
+		if err != nil {
+			return nil, err
+		}
+		config.MinConfidence = minConf
+	}
+	config.FilterBead = r.URL.Query().Get("bead")
+	filterType, err := parseSuggestType(r.URL.Query().Get("type"))
+	if err != nil {
+		return nil, serveBadRequest{err}
+	}
+	config.FilterType = filterType
+	return analysis.GenerateRobotSuggestOutput(snap.issues, config, snap.dataHash), nil
+}
+
+func (s *robotServer) handleAlerts(snap *serveSnapshot, r *http.Request) (interface{}, error) {
+	driftConfig, err := drift.LoadConfig(s.projectDir)
+	if err != nil {
+		return nil, fmt.Errorf("loading drift config: %w", err)
+	}
+
+	snap.stats.WaitForPhase2()
+	snap.mu.Lock()
+	alerts := computeDriftAlerts(snap.issues, snap.analyzer, snap.stats, driftConfig)
+	snap.mu.Unlock()
+
+	q := r.URL.Query()
+	return buildAlertsOutput(snap.dataHash, alerts, alertFilters{
+		Severity: q.Get("severity"),
+		Type:     q.Get("type"),
+		Label:    q.Get("label"),
+	}), nil
+}
+
+func (s *robotServer) handleHistory(snap *serveSnapshot, r *http.Request) (interface{}, error) {
+	limit := 500
+	if r.URL.Query().Has("limit") {
+		n, err := queryInt(r, "limit")
+		if err != nil {
+			return nil, err
+		}
+		limit = n
+	}
+	minConf, err := queryFloat(r, "min_confidence")
+	if err != nil {
+		return nil, err
+	}
+	historyPath, err := s.historyPath()
+	if err != nil {
+		return nil, err
+	}
+	return buildHistoryReport(s.projectDir, historyPath, snap.issues, historyRequest{
+		BeadID:        r.URL.Query().Get("bead"),
+		Limit:         limit,
+		Since:         r.URL.Query().Get("since"),
+		MinConfidence: minConf,
+	})
+}
+
+func (s *robotServer) handleForecast(snap *serveSnapshot, r *http.Request) (interface{}, error) {
+	q := r.URL.Query()
+	agents, err := queryInt(r, "agents")
+	if err != nil {
+		return nil, err
+	}
+	trials, err := queryInt(r, "trials")
+	if err != nil {
+		return nil, err
+	}
+	if trials > analysis.MaxMonteCarloTrials {
+		return nil, serveBadRequest{fmt.Errorf("trials must be at most %d, got %d", analysis.MaxMonteCarloTrials, trials)}
+	}
+	seed := int64(1)
+	if v := q.Get("seed"); v != "" {
+		if seed, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return nil, serveBadRequest{fmt.Errorf("invalid seed: %q", v)}
+		}
+	}
+	req := forecastRequest{
+		Target: q.Get("id"),
+		Label:  q.Get("label"),
+		Sprint: q.Get("sprint"),
+		Agents: agents,
+		Trials: trials,
+		Seed:   seed,
+	}
+	if req.Target == "" {
+		req.Target = "all"
+	}
+	monteCarlo := queryBool(r, "monte_carlo")
+
+	var sprints []model.Sprint
+	if req.Sprint != "" || monteCarlo {
+		sprints, _ = loader.LoadSprints(s.projectDir)
+	}
+
+	if monteCarlo {
+		output, err := buildMonteCarloForecastOutput(snap.issues, sprints, req, time.Now())
+		if err != nil {
+			return nil, serveBadRequest{err}
+		}
+		return output, nil
+	}
+
+	snap.stats.WaitForPhase2()
+	output, err := buildForecastOutput(snap.issues, snap.stats, sprints, req, time.Now())
+	if err != nil {
+		return nil, serveBadRequest{err}
+	}
+	return output, nil
+}
+
+func (s *robotServer) handleLabelHealth(snap *serveSnapshot, r *http.Request) (interface{}, error) {
+	return buildLabelHealthOutput(snap.dataHash, snap.issues), nil
+}
+
+func (s *robotServer) handleSchedule(snap *serveSnapshot, r *http.Request) (interface{}, error) {
+	agents, err := queryInt(r, "agents")
+	if err != nil {
+		return nil, err
+	}
+	snap.stats.WaitForPhase2()
+	return buildScheduleOutput(snap.dataHash, snap.issues, snap.stats, s.projectDir, agents, time.Now())
+}
+
+func (s *robotServer) handleEpics(snap *serveSnapshot, r *http.Request) (interface{}, error) {
+	return buildEpicsOutput(snap.dataHash, snap.issues, time.Now()), nil
+}
+
+func (s *robotServer) handleEscalations(snap *serveSnapshot, r *http.Request) (interface{}, error) {
+	return buildEscalationsOutput(snap.dataHash, snap.issues, s.projectDir, time.Now())
+}
+
+func (s *robotServer) handleAgents(snap *serveSnapshot, r *http.Request) (interface{}, error) {
+	var history *correlation.HistoryReport
+	if historyPath, err := s.historyPath(); err == nil {
+		history = loadAgentHistory(s.projectDir, historyPath, snap.issues, 500)
+	}
+	return buildAgentsOutput(snap.dataHash, snap.issues, s.projectDir, history)
+}
+
+// historyPath returns the JSONL file whose git history is correlated with
+// commits. The server may be reading beads.db, which has no such history.
+func (s *robotServer) historyPath() (string, error) {
+	return loader.FindJSONLPath(filepath.Dir(s.beadsPath))
+}
+
+// gitHead returns the SHA of HEAD in dir, or "" outside a git repository.
+func gitHead(dir string) string {
+	cmd := exec.Command("git", "rev-parse", "HEAD")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// serveBadRequest marks errors caused by the request rather than the server.
+type serveBadRequest struct{ err error }
+
+func (e serveBadRequest) Error() string { return e.err.Error() }
+func (e serveBadRequest) Unwrap() error { return e.err }
+
+func queryBool(r *http.Request, key string) bool {
+	v, _ := strconv.ParseBool(r.URL.Query().Get(key))
+	return v
+}
+
+func queryInt(r *http.Request, key string) (int, error) {
+	v := r.URL.Query().Get(key)
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, serveBadRequest{fmt.Errorf("invalid %s: %q", key, v)}
+	}
+	return n, nil
+}
+
+func queryFloat(r *http.Request, key string) (float64, error) {
+	v := r.URL.Query().Get(key)
+	if v == "" {
+		return 0, nil
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, serveBadRequest{fmt.Errorf("invalid %s: %q", key, v)}
+	}
+	return f, nil
+}
+
+// etagMatches reports whether an If-None-Match header matches etag. Weak
+// validators compare equal to strong ones, per RFC 9110 §13.1.2.
+func etagMatches(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+func writeServeJSON(w http.ResponseWriter, status int, headOnly bool, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if headOnly {
+		return
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(payload); err != nil {
+		fmt.Fprintf(os.Stderr, "Error encoding response: %v\n", err)
+	}
+}
+
+func writeServeError(w http.ResponseWriter, status int, err error) {
+	writeServeJSON(w, status, false, map[string]string{"error": err.Error()})
+}
+
+// runServe implements `bv serve`: load issues once, keep them fresh with a
+// file watcher, and answer robot queries over HTTP until interrupted.
+func runServe(args []string) int {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := fs.String("addr", DefaultServeAddr, "Listen address (host:port)")
+	forcePoll := fs.Bool("poll", false, "Poll the beads file instead of using fsnotify")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: bv serve [--addr host:port] [--poll]")
+		fmt.Fprintln(fs.Output(), "")
+		fmt.Fprintln(fs.Output(), "Serves robot outputs as JSON over HTTP, reloading on file change.")
+		fmt.Fprintln(fs.Output(), "Endpoints: /health /triage /next /plan /insights /priority /graph")
+		fmt.Fprintln(fs.Output(), "           /suggest /alerts /history /forecast /label-health /schedule /epics")
+		fmt.Fprintln(fs.Output(), "           /escalations /agents")
+		fmt.Fprintln(fs.Output(), "Send If-None-Match with the previous ETag to get 304 when unchanged.")
+		fmt.Fprintln(fs.Output(), "ETags also change with git HEAD, the roster, drift config or sprints when the endpoint reads them.")
+		fmt.Fprintln(fs.Output(), "")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 1
+	}
+
+	projectDir, err := os.Getwd()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting current directory: %v\n", err)
+		return 1
+	}
+	beadsDir, err := loader.GetBeadsDir("")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting beads directory: %v\n", err)
+		return 1
+	}
+	beadsPath, err := loader.FindIssuesPath(beadsDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error finding beads file: %v\n", err)
+		return 1
+	}
+
+	srv, err := newRobotServer(beadsPath, projectDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	w, err := watcher.NewWatcher(beadsPath,
+		watcher.WithDebounceDuration(200*time.Millisecond),
+		watcher.WithForcePoll(*forcePoll),
+		watcher.WithOnChange(func() {
+			if err := srv.reload(); err != nil {
+				fmt.Fprintf(os.Stderr, "Reload error: %v\n", err)
+			}
+		}),
+		watcher.WithOnError(func(err error) {
+			fmt.Fprintf(os.Stderr, "Watcher error: %v\n", err)
+		}),
+	)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating watcher: %v\n", err)
+		return 1
+	}
+	if err := w.Start(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error starting watcher: %v\n", err)
+		return 1
+	}
+	defer w.Stop()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	httpServer := &http.Server{
+		Addr:              *addr,
+		Handler:           srv.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- httpServer.ListenAndServe()
+	}()
+
+	fmt.Fprintf(os.Stderr, "bv serve: %s (%d issues) on http://%s\n",
+		filepath.Base(beadsPath), len(srv.snapshot().issues), *addr)
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			fmt.Fprintf(os.Stderr, "Error shutting down: %v\n", err)
+			return 1
+		}
+	}
+	return 0
+}
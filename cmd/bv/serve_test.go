@@ -0,0 +1,258 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+)
+
+func newTestRobotServer(t *testing.T, beads string) (*robotServer, string) {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "beads.jsonl")
+	if err := os.WriteFile(path, []byte(beads), 0o644); err != nil {
+		t.Fatalf("write beads: %v", err)
+	}
+	srv, err := newRobotServer(path, dir)
+	if err != nil {
+		t.Fatalf("newRobotServer: %v", err)
+	}
+	return srv, path
+}
+
+const serveFixture = `{"id":"TEST-1","title":"A","status":"open","priority":1,"issue_type":"task"}
+{"id":"TEST-2","title":"B","status":"open","priority":2,"issue_type":"task","dependencies":[{"issue_id":"TEST-2","depends_on_id":"TEST-1","type":"blocks"}]}
+`
+
+func getJSON(t *testing.T, h http.Handler, path string, header map[string]string) (*httptest.ResponseRecorder, map[string]any) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	var payload map[string]any
+	if rec.Code == http.StatusOK || rec.Code == http.StatusBadRequest {
+		if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+			t.Fatalf("%s: invalid json: %v\n%s", path, err, rec.Body.String())
+		}
+	}
+	return rec, payload
+}
+
+func TestServeEndpointsMatchRobotShapes(t *testing.T) {
+	srv, _ := newTestRobotServer(t, serveFixture)
+	h := srv.Handler()
+
+	cases := map[string][]string{
+		"/triage":       {"generated_at", "data_hash", "triage", "usage_hints"},
+		"/next":         {"generated_at", "data_hash", "id", "claim_command"},
+		"/plan":         {"data_hash", "analysis_config", "status", "plan"},
+		"/insights":     {"data_hash", "analysis_config", "status", "full_stats"},
+		"/priority":     {"data_hash", "recommendations", "filters", "summary"},
+		"/graph":        {"format", "nodes", "edges"},
+		"/alerts":       {"data_hash", "alerts", "summary"},
+		"/forecast":     {"agents", "forecast_count", "forecasts"},
+		"/label-health": {"data_hash", "results"},
+		"/schedule":     {"data_hash", "roster_source", "agents", "makespan_minutes"},
+		"/epics":        {"data_hash", "epics", "ready_to_close"},
+		"/escalations":  {"data_hash", "roster_source", "items", "overdue"},
+		"/agents":       {"data_hash", "roster_source", "agents", "history_available"},
+		"/health":       {"status", "data_hash", "issue_count"},
+	}
+	for path, keys := range cases {
+		rec, payload := getJSON(t, h, path, nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: status %d: %s", path, rec.Code, rec.Body.String())
+		}
+		for _, k := range keys {
+			if _, ok := payload[k]; !ok {
+				t.Errorf("%s: missing key %q", path, k)
+			}
+		}
+	}
+
+	_, next := getJSON(t, h, "/next", nil)
+	if next["id"] != "TEST-1" {
+		t.Errorf("/next id = %v, want TEST-1", next["id"])
+	}
+
+	_, mc := getJSON(t, h, "/forecast?monte_carlo=1&trials=50", nil)
+	for _, k := range []string{"trials", "seed", "project", "issues"} {
+		if _, ok := mc[k]; !ok {
+			t.Errorf("/forecast?monte_carlo=1: missing key %q", k)
+		}
+	}
+}
+
+func TestServeConditionalRequests(t *testing.T) {
+	srv, path := newTestRobotServer(t, serveFixture)
+	h := srv.Handler()
+
+	rec, payload := getJSON(t, h, "/triage", nil)
+	etag := rec.Header().Get("ETag")
+	if etag != `"`+payload["data_hash"].(string)+`"` {
+		t.Fatalf("ETag %q does not carry data_hash %v", etag, payload["data_hash"])
+	}
+
+	rec, _ = getJSON(t, h, "/plan", map[string]string{"If-None-Match": etag})
+	if rec.Code != http.StatusNotModified {
+		t.Fatalf("expected 304 for matching ETag, got %d", rec.Code)
+	}
+
+	// Changing the file yields a new data hash after reload.
+	changed := serveFixture + `{"id":"TEST-3","title":"C","status":"open","priority":0,"issue_type":"bug"}` + "\n"
+	if err := os.WriteFile(path, []byte(changed), 0o644); err != nil {
+		t.Fatalf("rewrite beads: %v", err)
+	}
+	if err := srv.reload(); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	rec, payload = getJSON(t, h, "/plan", map[string]string{"If-None-Match": etag})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 after change, got %d", rec.Code)
+	}
+	if rec.Header().Get("ETag") == etag {
+		t.Fatal("ETag did not change after reload")
+	}
+	if payload["data_hash"] == nil {
+		t.Fatal("missing data_hash after reload")
+	}
+}
+
+func TestServeGitBackedETagFollowsHead(t *testing.T) {
+	srv, path := newTestRobotServer(t, serveFixture)
+	h := srv.Handler()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=t", "-c", "user.email=t@example.com"}, args...)...)
+		cmd.Dir = filepath.Dir(path)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q")
+	git("add", ".")
+	git("commit", "-q", "-m", "first")
+
+	rec, _ := getJSON(t, h, "/agents", nil)
+	etag := rec.Header().Get("ETag")
+	triage, _ := getJSON(t, h, "/triage", nil)
+	if etag == triage.Header().Get("ETag") {
+		t.Fatalf("/agents ETag %s should include HEAD", etag)
+	}
+	if rec, _ = getJSON(t, h, "/agents", map[string]string{"If-None-Match": etag}); rec.Code != http.StatusNotModified {
+		t.Fatalf("expected 304 while HEAD is unchanged, got %d", rec.Code)
+	}
+
+	git("commit", "-q", "--allow-empty", "-m", "second")
+	if rec, _ = getJSON(t, h, "/agents", map[string]string{"If-None-Match": etag}); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 after a new commit, got %d", rec.Code)
+	}
+	if rec, _ = getJSON(t, h, "/triage", map[string]string{"If-None-Match": triage.Header().Get("ETag")}); rec.Code != http.StatusNotModified {
+		t.Fatalf("/triage should still be 304 after a commit, got %d", rec.Code)
+	}
+}
+
+func TestServeETagFollowsSideFiles(t *testing.T) {
+	srv, path := newTestRobotServer(t, serveFixture)
+	h := srv.Handler()
+	dir := filepath.Dir(path)
+
+	rec, _ := getJSON(t, h, "/schedule", nil)
+	etag := rec.Header().Get("ETag")
+	if err := os.MkdirAll(filepath.Join(dir, ".bv"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	roster := "agents:\n  - name: alice\n"
+	if err := os.WriteFile(analysis.RosterPath(dir), []byte(roster), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if rec, _ = getJSON(t, h, "/schedule", map[string]string{"If-None-Match": etag}); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 after editing the roster, got %d", rec.Code)
+	}
+	etag = rec.Header().Get("ETag")
+	if rec, _ = getJSON(t, h, "/schedule", map[string]string{"If-None-Match": etag}); rec.Code != http.StatusNotModified {
+		t.Fatalf("expected 304 with the roster unchanged, got %d", rec.Code)
+	}
+
+	rec, _ = getJSON(t, h, "/forecast", nil)
+	etag = rec.Header().Get("ETag")
+	if err := os.MkdirAll(filepath.Join(dir, ".beads"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(sprintsPath(dir), []byte(`{"id":"s1","name":"S1"}`+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if rec, _ = getJSON(t, h, "/forecast", map[string]string{"If-None-Match": etag}); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 after editing sprints, got %d", rec.Code)
+	}
+}
+
+func TestServeRejectsBadParams(t *testing.T) {
+	srv, _ := newTestRobotServer(t, serveFixture)
+	h := srv.Handler()
+
+	rec, payload := getJSON(t, h, "/priority?max_results=lots", nil)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rec.Code)
+	}
+	if payload["error"] == nil {
+		t.Fatal("expected error field")
+	}
+
+	rec, _ = getJSON(t, h, "/forecast?monte_carlo=1&trials=10001", nil)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for trials above the maximum, got %d", rec.Code)
+	}
+
+	rec, _ = getJSON(t, h, "/suggest?type=bogus", nil)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for bad suggest type, got %d", rec.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/triage", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405 for POST, got %d", w.Code)
+	}
+}
+
+func TestServeHistoryPathUsesJSONL(t *testing.T) {
+	dir := t.TempDir()
+	jsonl := filepath.Join(dir, "issues.jsonl")
+	if err := os.WriteFile(jsonl, []byte(serveFixture), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	srv := &robotServer{beadsPath: filepath.Join(dir, "beads.db"), projectDir: dir}
+	if got, err := srv.historyPath(); err != nil || got != jsonl {
+		t.Errorf("historyPath() = %q, %v; want %q", got, err, jsonl)
+	}
+}
+
+func TestEtagMatches(t *testing.T) {
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{"", false},
+		{`"abc"`, true},
+		{`W/"abc"`, true},
+		{`"x", "abc"`, true},
+		{`*`, true},
+		{`"abd"`, false},
+	}
+	for _, tt := range tests {
+		if got := etagMatches(tt.header, `"abc"`); got != tt.want {
+			t.Errorf("etagMatches(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/drift"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/hooks"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/notify"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/watcher"
+)
+
+// alertWatcher re-evaluates alerts for one beads file and hands them to the
+// notifier, and runs lifecycle hooks for what changed since the last load.
+// Either the notifier or the lifecycle may be nil.
+type alertWatcher struct {
+	beadsPath  string
+	projectDir string
+	notifier   *notify.Notifier
+	lifecycle  *hooks.Lifecycle
+}
+
+// evaluate loads the issues, runs lifecycle hooks, computes alerts and
+// delivers new ones.
+func (w *alertWatcher) evaluate(ctx context.Context) (notify.Report, error) {
+	issues, err := loader.LoadIssuesFromFileWithOptions(w.beadsPath, loader.ParseOptions{
+		WarningHandler: func(msg string) {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", msg)
+		},
+	})
+	if err != nil {
+		return notify.Report{}, fmt.Errorf("loading %s: %w", w.beadsPath, err)
+	}
+	// Reload drift thresholds too, so edits apply without a restart.
+	driftConfig, err := drift.LoadConfig(w.projectDir)
+	if err != nil {
+		return notify.Report{}, fmt.Errorf("loading drift config: %w", err)
+	}
+
+	analyzer := analysis.NewAnalyzer(issues)
+	stats := analyzer.Analyze()
+	if w.lifecycle != nil {
+		events, results := w.lifecycle.Observe(hooks.Snapshot{
+			Issues:     issues,
+			Actionable: analyzer.GetActionableIssues(),
+			Cycles:     stats.Cycles(),
+		})
+		logHookResults(len(events), results)
+	}
+	if w.notifier == nil {
+		return notify.Report{}, nil
+	}
+	alerts := computeDriftAlerts(issues, analyzer, &stats, driftConfig)
+	return w.notifier.Notify(ctx, alerts)
+}
+
+// logHookResults prints lifecycle hook failures, plus a summary when any
+// hook ran.
+func logHookResults(events int, results []hooks.HookResult) {
+	if len(results) == 0 {
+		return
+	}
+	failed := 0
+	for _, r := range results {
+		if !r.Success {
+			failed++
+		}
+	}
+	fmt.Fprintf(os.Stderr, "[%s] %d change event(s), %d hook run(s), %d failed\n",
+		time.Now().Format("15:04:05"), events, len(results), failed)
+	for _, r := range results {
+		if r.Success {
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "  %s hook %q: %v\n", r.Phase, r.Hook.Name, r.Error)
+		if r.Stderr != "" {
+			fmt.Fprintf(os.Stderr, "    stderr: %s\n", r.Stderr)
+		}
+	}
+}
+
+// logWatchReport prints a one-line summary plus any sink failures.
+func logWatchReport(report notify.Report) {
+	if len(report.Deliveries) == 0 {
+		return
+	}
+	parts := make([]string, 0, len(report.Deliveries))
+	for _, d := range report.Deliveries {
+		if d.Error != "" {
+			parts = append(parts, d.Sink+" failed")
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s %d", d.Sink, d.Sent))
+	}
+	fmt.Fprintf(os.Stderr, "[%s] %d alert(s), %d new delivery(ies): %s\n",
+		time.Now().Format("15:04:05"), report.Alerts, report.Sent(), strings.Join(parts, ", "))
+	for _, d := range report.Failed() {
+		fmt.Fprintf(os.Stderr, "  %s: %s (will retry)\n", d.Sink, d.Error)
+	}
+}
+
+func runWatchAlerts(args []string) int {
+	fs := flag.NewFlagSet("watch-alerts", flag.ContinueOnError)
+	once := fs.Bool("once", false, "Evaluate alerts once, deliver them and exit")
+	forcePoll := fs.Bool("poll", false, "Poll the beads file instead of using fsnotify")
+	noHooks := fs.Bool("no-hooks", false, "Do not run on-* hooks from .bv/hooks.yaml")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: bv watch-alerts [--once] [--poll] [--no-hooks]")
+		fmt.Fprintln(fs.Output(), "")
+		fmt.Fprintln(fs.Output(), "Evaluates drift and proactive alerts whenever the beads file changes and")
+		fmt.Fprintln(fs.Output(), "delivers new ones to the sinks in .bv/notify.yaml (webhook, socket, file,")
+		fmt.Fprintln(fs.Output(), "command). Each alert (type + issue + label) reaches each sink once;")
+		fmt.Fprintln(fs.Output(), "delivery state is kept in .bv/notify-state.json.")
+		fmt.Fprintln(fs.Output(), "")
+		fmt.Fprintln(fs.Output(), "It also runs the on-load, on-change, on-close and on-drift hooks from")
+		fmt.Fprintln(fs.Output(), ".bv/hooks.yaml, once per change, with a JSON event on stdin.")
+		fmt.Fprintln(fs.Output(), "")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 1
+	}
+
+	projectDir, err := os.Getwd()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting current directory: %v\n", err)
+		return 1
+	}
+	cfg, err := notify.LoadConfig(projectDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	var lifecycle *hooks.Lifecycle
+	if !*noHooks {
+		hookLoader := hooks.NewLoader(hooks.WithProjectDir(projectDir))
+		if err := hookLoader.Load(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		for _, warning := range hookLoader.Warnings() {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+		}
+		if hookLoader.HasLifecycleHooks() {
+			lifecycle = hooks.NewLifecycle(hookLoader.Config())
+		}
+	}
+	if len(cfg.Sinks) == 0 && lifecycle == nil {
+		fmt.Fprintf(os.Stderr, "Error: no sinks configured in %s and no on-* hooks in .bv/hooks.yaml\n", notify.ConfigPath(projectDir))
+		return 1
+	}
+	beadsDir, err := loader.GetBeadsDir("")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting beads directory: %v\n", err)
+		return 1
+	}
+	beadsPath, err := loader.FindIssuesPath(beadsDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error finding beads file: %v\n", err)
+		return 1
+	}
+
+	aw := &alertWatcher{beadsPath: beadsPath, projectDir: projectDir, lifecycle: lifecycle}
+	if len(cfg.Sinks) > 0 {
+		n, err := notify.New(cfg, projectDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		defer n.Close()
+		aw.notifier = n
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	report, err := aw.evaluate(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	logWatchReport(report)
+	if *once {
+		if len(report.Failed()) > 0 {
+			return 1
+		}
+		return 0
+	}
+
+	w, err := watcher.NewWatcher(beadsPath,
+		watcher.WithDebounceDuration(200*time.Millisecond),
+		watcher.WithForcePoll(*forcePoll),
+		watcher.WithOnChange(func() {
+			report, err := aw.evaluate(ctx)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Evaluate error: %v\n", err)
+				return
+			}
+			logWatchReport(report)
+		}),
+		watcher.WithOnError(func(err error) {
+			fmt.Fprintf(os.Stderr, "Watcher error: %v\n", err)
+		}),
+	)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating watcher: %v\n", err)
+		return 1
+	}
+	if err := w.Start(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error starting watcher: %v\n", err)
+		return 1
+	}
+	defer w.Stop()
+
+	sinks := 0
+	if aw.notifier != nil {
+		sinks = len(aw.notifier.Sinks())
+	}
+	hookState := "off"
+	if lifecycle != nil {
+		hookState = "on"
+	}
+	fmt.Fprintf(os.Stderr, "bv watch-alerts: watching %s with %d sink(s), lifecycle hooks %s\n",
+		filepath.Base(beadsPath), sinks, hookState)
+	<-ctx.Done()
+	return 0
+}
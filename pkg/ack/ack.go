@@ -7,18 +7,23 @@
 //   - decline: increments bounce_count, adds comment, auto-escalates at 3 bounces
 //   - defer: reassigns bead to another agent
 //   - impossible: immediate escalation with reason
+//   - depends-on: records that the bead is blocked by another, refusing
+//     additions that would close a dependency cycle
+//   - requeue / reassign: a lead's resolution of an escalated bead
+//   - EnforceDeadlines: re-queues or escalates assignments nobody
+//     acknowledged before their deadline
+//
+// Writes go through pkg/mutate, so they hold the beads write lock and can be
+// made conditional on the version of the bead the agent last saw.
 package ack
 
 import (
-	"bufio"
-	"encoding/json"
 	"fmt"
-	"os"
-	"path/filepath"
 	"time"
 
-	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/mutate"
 )
 
 // MaxBounces is the threshold after which a task auto-escalates
@@ -30,21 +35,50 @@ type Result struct {
 	Message      string
 	Escalated    bool
 	EscalateInfo string
+	// Impact is set by DependsOn
+	Impact *analysis.DependencyImpact
+	// Version is the bead's version after the write, for use as the
+	// expectation of the agent's next write.
+	Version mutate.Version
 }
 
 // Service provides task acknowledgment operations
 type Service struct {
 	repoPath string
+	filePath string
+	opts     []mutate.StoreOption
+}
+
+// NewService creates a new ack service for the given repository path. opts
+// configure every write, e.g. mutate.WithExpect or mutate.WithRetries.
+func NewService(repoPath string, opts ...mutate.StoreOption) *Service {
+	return &Service{repoPath: repoPath, opts: opts}
+}
+
+// NewFileService creates an ack service bound to an already-resolved JSONL
+// path, as used by the TUI.
+func NewFileService(jsonlPath string, opts ...mutate.StoreOption) *Service {
+	return &Service{filePath: jsonlPath, opts: opts}
 }
 
-// NewService creates a new ack service for the given repository path
-func NewService(repoPath string) *Service {
-	return &Service{repoPath: repoPath}
+func (s *Service) store() *mutate.Store {
+	if s.filePath != "" {
+		return mutate.NewFileStore(s.filePath, s.opts...)
+	}
+	return mutate.NewStore(s.repoPath, s.opts...)
 }
 
+// step is the change one ack action makes to its bead. all holds every
+// loaded issue, including the bead itself; a step must not retain it.
+type step func(issue *model.Issue, all []model.Issue) (*Result, error)
+
 // Accept marks a task as accepted by the agent
 func (s *Service) Accept(issueID, agentID string) (*Result, error) {
-	return s.modifyIssue(issueID, func(issue *model.Issue) (*Result, error) {
+	return s.modifyIssue(issueID, acceptStep(agentID))
+}
+
+func acceptStep(agentID string) step {
+	return func(issue *model.Issue, _ []model.Issue) (*Result, error) {
 		now := time.Now()
 
 		issue.Status = model.StatusInProgress
@@ -54,9 +88,9 @@ func (s *Service) Accept(issueID, agentID string) (*Result, error) {
 
 		return &Result{
 			Success: true,
-			Message: fmt.Sprintf("Task %s accepted by %s", issueID, agentID),
+			Message: fmt.Sprintf("Task %s accepted by %s", issue.ID, agentID),
 		}, nil
-	})
+	}
 }
 
 // Decline marks a task as declined and increments bounce count
@@ -64,8 +98,11 @@ func (s *Service) Decline(issueID, agentID, reason string) (*Result, error) {
 	if reason == "" {
 		return nil, fmt.Errorf("decline reason is required")
 	}
+	return s.modifyIssue(issueID, declineStep(agentID, reason))
+}
 
-	return s.modifyIssue(issueID, func(issue *model.Issue) (*Result, error) {
+func declineStep(agentID, reason string) step {
+	return func(issue *model.Issue, _ []model.Issue) (*Result, error) {
 		now := time.Now()
 
 		issue.BounceCount++
@@ -73,10 +110,10 @@ func (s *Service) Decline(issueID, agentID, reason string) (*Result, error) {
 		issue.UpdatedAt = now
 
 		// Add decline comment
-		commentID := int64(len(issue.Comments) + 1)
+		commentID := mutate.NextCommentID(issue)
 		issue.Comments = append(issue.Comments, &model.Comment{
 			ID:        commentID,
-			IssueID:   issueID,
+			IssueID:   issue.ID,
 			Author:    agentID,
 			Text:      fmt.Sprintf("[DECLINED] %s (bounce %d/%d)", reason, issue.BounceCount, MaxBounces),
 			CreatedAt: now,
@@ -84,7 +121,7 @@ func (s *Service) Decline(issueID, agentID, reason string) (*Result, error) {
 
 		result := &Result{
 			Success: true,
-			Message: fmt.Sprintf("Task %s declined by %s (bounce %d/%d)", issueID, agentID, issue.BounceCount, MaxBounces),
+			Message: fmt.Sprintf("Task %s declined by %s (bounce %d/%d)", issue.ID, agentID, issue.BounceCount, MaxBounces),
 		}
 
 		// Auto-escalate if max bounces reached
@@ -92,11 +129,11 @@ func (s *Service) Decline(issueID, agentID, reason string) (*Result, error) {
 			issue.Escalated = true
 			result.Escalated = true
 			result.EscalateInfo = fmt.Sprintf("Task bounced %d times - escalated. Last reason: %s", issue.BounceCount, reason)
-			result.Message = fmt.Sprintf("Task %s auto-escalated after %d declines", issueID, MaxBounces)
+			result.Message = fmt.Sprintf("Task %s auto-escalated after %d declines", issue.ID, MaxBounces)
 		}
 
 		return result, nil
-	})
+	}
 }
 
 // Defer reassigns a task to another agent
@@ -104,8 +141,11 @@ func (s *Service) Defer(issueID, fromAgent, toAgent string) (*Result, error) {
 	if toAgent == "" {
 		return nil, fmt.Errorf("target agent is required for defer")
 	}
+	return s.modifyIssue(issueID, deferStep(fromAgent, toAgent))
+}
 
-	return s.modifyIssue(issueID, func(issue *model.Issue) (*Result, error) {
+func deferStep(fromAgent, toAgent string) step {
+	return func(issue *model.Issue, _ []model.Issue) (*Result, error) {
 		now := time.Now()
 
 		issue.DeferredFrom = fromAgent
@@ -114,10 +154,10 @@ func (s *Service) Defer(issueID, fromAgent, toAgent string) (*Result, error) {
 		issue.UpdatedAt = now
 
 		// Add defer comment
-		commentID := int64(len(issue.Comments) + 1)
+		commentID := mutate.NextCommentID(issue)
 		issue.Comments = append(issue.Comments, &model.Comment{
 			ID:        commentID,
-			IssueID:   issueID,
+			IssueID:   issue.ID,
 			Author:    fromAgent,
 			Text:      fmt.Sprintf("[DEFERRED] Task deferred from %s to %s", fromAgent, toAgent),
 			CreatedAt: now,
@@ -125,9 +165,9 @@ func (s *Service) Defer(issueID, fromAgent, toAgent string) (*Result, error) {
 
 		return &Result{
 			Success: true,
-			Message: fmt.Sprintf("Task %s deferred from %s to %s", issueID, fromAgent, toAgent),
+			Message: fmt.Sprintf("Task %s deferred from %s to %s", issue.ID, fromAgent, toAgent),
 		}, nil
-	})
+	}
 }
 
 // Impossible marks a task as impossible and escalates immediately
@@ -135,8 +175,11 @@ func (s *Service) Impossible(issueID, agentID, reason string) (*Result, error) {
 	if reason == "" {
 		return nil, fmt.Errorf("impossible reason is required")
 	}
+	return s.modifyIssue(issueID, impossibleStep(agentID, reason))
+}
 
-	return s.modifyIssue(issueID, func(issue *model.Issue) (*Result, error) {
+func impossibleStep(agentID, reason string) step {
+	return func(issue *model.Issue, _ []model.Issue) (*Result, error) {
 		now := time.Now()
 
 		issue.AckStatus = model.AckStatusImpossible
@@ -144,10 +187,10 @@ func (s *Service) Impossible(issueID, agentID, reason string) (*Result, error) {
 		issue.UpdatedAt = now
 
 		// Add impossible comment
-		commentID := int64(len(issue.Comments) + 1)
+		commentID := mutate.NextCommentID(issue)
 		issue.Comments = append(issue.Comments, &model.Comment{
 			ID:        commentID,
-			IssueID:   issueID,
+			IssueID:   issue.ID,
 			Author:    agentID,
 			Text:      fmt.Sprintf("[IMPOSSIBLE] %s - Escalated for strategic decision", reason),
 			CreatedAt: now,
@@ -155,124 +198,51 @@ func (s *Service) Impossible(issueID, agentID, reason string) (*Result, error) {
 
 		return &Result{
 			Success:      true,
-			Message:      fmt.Sprintf("Task %s marked impossible by %s - escalated", issueID, agentID),
+			Message:      fmt.Sprintf("Task %s marked impossible by %s - escalated", issue.ID, agentID),
 			Escalated:    true,
 			EscalateInfo: reason,
 		}, nil
-	})
-}
-
-// modifyIssue loads the issue, applies the modification function, and saves it back
-func (s *Service) modifyIssue(issueID string, modifyFn func(*model.Issue) (*Result, error)) (*Result, error) {
-	beadsDir, err := loader.GetBeadsDir(s.repoPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get beads directory: %w", err)
-	}
-
-	jsonlPath, err := loader.FindJSONLPath(beadsDir)
-	if err != nil {
-		return nil, fmt.Errorf("failed to find JSONL file: %w", err)
 	}
+}
 
-	// Load all issues
-	issues, err := loader.LoadIssuesFromFile(jsonlPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to load issues: %w", err)
+// DependsOn records that issueID is blocked by dependsOnID. The addition is
+// rejected with a *mutate.CycleError if it would close a cycle, unless
+// allowCycle is set.
+func (s *Service) DependsOn(issueID, dependsOnID, agentID string, allowCycle bool) (*Result, error) {
+	if dependsOnID == "" {
+		return nil, fmt.Errorf("dependency target is required")
 	}
+	return s.modifyIssue(issueID, dependsOnStep(dependsOnID, agentID, allowCycle))
+}
 
-	// Find and modify the target issue
-	var result *Result
-	found := false
-	for i := range issues {
-		if issues[i].ID == issueID {
-			result, err = modifyFn(&issues[i])
-			if err != nil {
-				return nil, err
-			}
-			found = true
-			break
+func dependsOnStep(dependsOnID, agentID string, allowCycle bool) step {
+	return func(issue *model.Issue, all []model.Issue) (*Result, error) {
+		op := mutate.AddDependency{DependsOnID: dependsOnID, Type: model.DepBlocks, AllowCycle: allowCycle}
+		impact := analysis.ComputeDependencyImpact(all, issue.ID, dependsOnID, model.DepBlocks)
+		env := &mutate.Env{Now: time.Now(), Actor: agentID, Issues: all}
+		if err := mutate.ApplyOps(issue, env, op); err != nil {
+			return nil, err
 		}
-	}
 
-	if !found {
-		return nil, fmt.Errorf("issue %s not found", issueID)
-	}
-
-	// Write back all issues atomically
-	if err := writeIssuesAtomic(jsonlPath, issues); err != nil {
-		return nil, fmt.Errorf("failed to write issues: %w", err)
+		return &Result{
+			Success: true,
+			Message: fmt.Sprintf("Task %s now depends on %s (%s)", issue.ID, dependsOnID, impact.Summary()),
+			Impact:  &impact,
+		}, nil
 	}
-
-	return result, nil
 }
 
-// writeIssuesAtomic writes all issues to the JSONL file atomically
-func writeIssuesAtomic(filePath string, issues []model.Issue) error {
-	// Get file info to preserve permissions
-	var mode os.FileMode = 0644
-	if info, err := os.Stat(filePath); err == nil {
-		mode = info.Mode()
-	}
-
-	// Create temp file in same directory (required for atomic rename)
-	dir := filepath.Dir(filePath)
-	tmp, err := os.CreateTemp(dir, ".bd-ack-atomic-*")
+// modifyIssue loads the issue, applies the step, and saves it back
+func (s *Service) modifyIssue(issueID string, fn step) (*Result, error) {
+	var result *Result
+	updated, err := s.store().Modify(issueID, func(issue *model.Issue, all []model.Issue) error {
+		var err error
+		result, err = fn(issue, all)
+		return err
+	})
 	if err != nil {
-		return fmt.Errorf("create temp file: %w", err)
+		return nil, err
 	}
-	tmpPath := tmp.Name()
-
-	// Cleanup temp file on error
-	success := false
-	defer func() {
-		if !success {
-			os.Remove(tmpPath)
-		}
-	}()
-
-	// Write each issue as a JSON line
-	writer := bufio.NewWriter(tmp)
-	for _, issue := range issues {
-		line, err := json.Marshal(issue)
-		if err != nil {
-			tmp.Close()
-			return fmt.Errorf("marshal issue %s: %w", issue.ID, err)
-		}
-		if _, err := writer.Write(line); err != nil {
-			tmp.Close()
-			return fmt.Errorf("write issue %s: %w", issue.ID, err)
-		}
-		if _, err := writer.WriteString("\n"); err != nil {
-			tmp.Close()
-			return fmt.Errorf("write newline: %w", err)
-		}
-	}
-
-	if err := writer.Flush(); err != nil {
-		tmp.Close()
-		return fmt.Errorf("flush buffer: %w", err)
-	}
-
-	// Ensure data is flushed to disk
-	if err := tmp.Sync(); err != nil {
-		tmp.Close()
-		return fmt.Errorf("sync temp file: %w", err)
-	}
-
-	if err := tmp.Close(); err != nil {
-		return fmt.Errorf("close temp file: %w", err)
-	}
-
-	// Set permissions on temp file
-	if err := os.Chmod(tmpPath, mode); err != nil {
-		return fmt.Errorf("chmod temp file: %w", err)
-	}
-
-	// Atomic rename
-	if err := os.Rename(tmpPath, filePath); err != nil {
-		return fmt.Errorf("rename temp file: %w", err)
-	}
-
-	success = true
-	return nil
+	result.Version = mutate.VersionOf(*updated)
+	return result, nil
 }
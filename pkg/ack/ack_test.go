@@ -2,12 +2,15 @@ package ack
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/mutate"
 )
 
 func TestAccept(t *testing.T) {
@@ -240,6 +243,76 @@ func TestIssueNotFound(t *testing.T) {
 	}
 }
 
+func TestDependsOnRejectsCycle(t *testing.T) {
+	tmpDir := t.TempDir()
+	beadsDir := filepath.Join(tmpDir, ".beads")
+	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+		t.Fatalf("Failed to create beads dir: %v", err)
+	}
+
+	now := time.Now()
+	a := model.Issue{ID: "test-a", Title: "A", Status: model.StatusOpen, IssueType: model.TypeTask, CreatedAt: now, UpdatedAt: now}
+	b := model.Issue{ID: "test-b", Title: "B", Status: model.StatusOpen, IssueType: model.TypeTask, CreatedAt: now, UpdatedAt: now}
+	if err := mutate.WriteIssuesAtomic(filepath.Join(beadsDir, "issues.jsonl"), []model.Issue{a, b}); err != nil {
+		t.Fatalf("Failed to write issues: %v", err)
+	}
+
+	svc := NewService(tmpDir)
+	result, err := svc.DependsOn("test-a", "test-b", "test-agent", false)
+	if err != nil {
+		t.Fatalf("DependsOn failed: %v", err)
+	}
+	if result.Impact == nil || result.Impact.ActionableDelta != -1 {
+		t.Errorf("Expected impact with actionable delta -1, got %+v", result.Impact)
+	}
+
+	_, err = svc.DependsOn("test-b", "test-a", "test-agent", false)
+	if !errors.Is(err, mutate.ErrDependencyCycle) {
+		t.Fatalf("Expected cycle error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "test-b → test-a → test-b") {
+		t.Errorf("Expected cycle path in error, got %q", err.Error())
+	}
+
+	if _, err := svc.DependsOn("test-b", "test-a", "test-agent", true); err != nil {
+		t.Fatalf("DependsOn with allowCycle failed: %v", err)
+	}
+}
+
+func TestExpectedVersionConflict(t *testing.T) {
+	tmpDir := t.TempDir()
+	beadsDir := filepath.Join(tmpDir, ".beads")
+	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+		t.Fatalf("Failed to create beads dir: %v", err)
+	}
+
+	seen := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	issue := model.Issue{ID: "test-cas", Title: "CAS", Status: model.StatusOpen, IssueType: model.TypeTask, CreatedAt: seen, UpdatedAt: seen}
+	writeTestIssue(t, beadsDir, issue)
+
+	// Another agent accepts first, moving updated_at forward.
+	first, err := NewService(tmpDir).Accept("test-cas", "agent-a")
+	if err != nil {
+		t.Fatalf("Accept failed: %v", err)
+	}
+	if first.Version.ContentHash == "" || !first.Version.UpdatedAt.After(seen) {
+		t.Errorf("Expected the new version in the result, got %+v", first.Version)
+	}
+
+	stale := NewService(tmpDir, mutate.WithExpect(mutate.Version{UpdatedAt: seen}))
+	if _, err := stale.Defer("test-cas", "agent-b", "agent-c"); !errors.Is(err, mutate.ErrConflict) {
+		t.Fatalf("Expected conflict for stale write, got %v", err)
+	}
+	if got := loadTestIssues(t, beadsDir)[0]; got.Assignee != "agent-a" {
+		t.Errorf("Stale write was applied: assignee %s", got.Assignee)
+	}
+
+	current := NewService(tmpDir, mutate.WithExpect(first.Version))
+	if _, err := current.Defer("test-cas", "agent-a", "agent-c"); err != nil {
+		t.Fatalf("Write with current version failed: %v", err)
+	}
+}
+
 func writeTestIssue(t *testing.T, beadsDir string, issue model.Issue) {
 	t.Helper()
 	path := filepath.Join(beadsDir, "issues.jsonl")
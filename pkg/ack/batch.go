@@ -0,0 +1,181 @@
+package ack
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/mutate"
+)
+
+// Action names accepted by Batch, matching the bd-ack command line.
+const (
+	ActionAccept     = "accept"
+	ActionDecline    = "decline"
+	ActionDefer      = "defer"
+	ActionImpossible = "impossible"
+	ActionDependsOn  = "depends-on"
+	ActionRequeue    = "requeue"
+	ActionReassign   = "reassign"
+)
+
+// ErrInvalidAction is matched (via errors.Is) by the error of a batch item
+// that names an unknown action or lacks a required field.
+var ErrInvalidAction = errors.New("invalid action")
+
+// ErrBatchAborted is returned by Batch when at least one item failed, in
+// which case nothing was written.
+var ErrBatchAborted = errors.New("batch aborted; nothing was written")
+
+// Action is one item of a batch, as read from a bd-ack --batch line.
+type Action struct {
+	ID     string `json:"id"`
+	Action string `json:"action"`
+	// Reason is the reason for decline and impossible, or the note for
+	// requeue.
+	Reason string `json:"reason,omitempty"`
+	// To is the target agent for defer and reassign, or the blocking bead
+	// for depends-on.
+	To string `json:"to,omitempty"`
+	// Deadline is the acknowledgment deadline for reassign.
+	Deadline   *time.Time `json:"deadline,omitempty"`
+	AllowCycle bool       `json:"allow_cycle,omitempty"`
+	// ExpectUpdatedAt and ExpectHash make the item conditional on the
+	// version of the bead, as --expect-updated-at and --expect-hash do.
+	ExpectUpdatedAt *time.Time `json:"expect_updated_at,omitempty"`
+	ExpectHash      string     `json:"expect_hash,omitempty"`
+}
+
+// step validates the action and returns the change it makes.
+func (a Action) step(agentID string) (step, error) {
+	if a.ID == "" {
+		return nil, fmt.Errorf("%w: id is required", ErrInvalidAction)
+	}
+	required := func(field, what string) error {
+		if field == "" {
+			return fmt.Errorf("%w: %s requires %s", ErrInvalidAction, a.Action, what)
+		}
+		return nil
+	}
+
+	switch a.Action {
+	case ActionAccept:
+		return acceptStep(agentID), nil
+	case ActionDecline:
+		if err := required(a.Reason, "a reason"); err != nil {
+			return nil, err
+		}
+		return declineStep(agentID, a.Reason), nil
+	case ActionDefer:
+		if err := required(a.To, "a target agent"); err != nil {
+			return nil, err
+		}
+		return deferStep(agentID, a.To), nil
+	case ActionImpossible:
+		if err := required(a.Reason, "a reason"); err != nil {
+			return nil, err
+		}
+		return impossibleStep(agentID, a.Reason), nil
+	case ActionDependsOn:
+		if err := required(a.To, "a target issue"); err != nil {
+			return nil, err
+		}
+		return dependsOnStep(a.To, agentID, a.AllowCycle), nil
+	case ActionRequeue:
+		return requeueStep(agentID, a.Reason), nil
+	case ActionReassign:
+		if err := required(a.To, "a target agent"); err != nil {
+			return nil, err
+		}
+		return reassignStep(agentID, a.To, a.Deadline), nil
+	case "":
+		return nil, fmt.Errorf("%w: action is required", ErrInvalidAction)
+	}
+	return nil, fmt.Errorf("%w: unknown action %q", ErrInvalidAction, a.Action)
+}
+
+// expectation returns the version the action requires, if any.
+func (a Action) expectation() (mutate.Version, bool) {
+	var v mutate.Version
+	if a.ExpectUpdatedAt != nil {
+		v.UpdatedAt = *a.ExpectUpdatedAt
+	}
+	v.ContentHash = a.ExpectHash
+	return v, a.ExpectUpdatedAt != nil || a.ExpectHash != ""
+}
+
+// BatchResult is the outcome of one batch item. Exactly one of Result and
+// Err is set.
+type BatchResult struct {
+	Action Action
+	Result *Result
+	Err    error
+}
+
+// Batch applies actions in order, in one locked transaction, on behalf of
+// agentID. Each action sees the changes of the ones before it. Either every
+// action succeeds and all of them are written, or nothing is written and
+// Batch returns ErrBatchAborted; the results report every item's outcome
+// either way, so the caller can tell which ones failed. Any other error
+// comes from the store, and the results then only say what each item would
+// have done.
+func (s *Service) Batch(agentID string, actions []Action) ([]BatchResult, error) {
+	results := make([]BatchResult, len(actions))
+	changed, err := s.store().ModifyAll(func(all []model.Issue) ([]string, error) {
+		index := make(map[string]int, len(all))
+		for i := range all {
+			index[all[i].ID] = i
+		}
+
+		failed := false
+		var ids []string
+		seen := make(map[string]bool)
+		for n, a := range actions {
+			result, err := applyAction(a, agentID, all, index)
+			results[n] = BatchResult{Action: a, Result: result, Err: err}
+			if err != nil {
+				failed = true
+				continue
+			}
+			if !seen[a.ID] {
+				seen[a.ID] = true
+				ids = append(ids, a.ID)
+			}
+		}
+		if failed {
+			return nil, ErrBatchAborted
+		}
+		return ids, nil
+	})
+	if err != nil {
+		return results, err
+	}
+
+	versions := make(map[string]mutate.Version, len(changed))
+	for _, issue := range changed {
+		versions[issue.ID] = mutate.VersionOf(issue)
+	}
+	for i := range results {
+		results[i].Result.Version = versions[results[i].Action.ID]
+	}
+	return results, nil
+}
+
+// applyAction runs one batch item against the in-memory issues.
+func applyAction(a Action, agentID string, all []model.Issue, index map[string]int) (*Result, error) {
+	fn, err := a.step(agentID)
+	if err != nil {
+		return nil, err
+	}
+	idx, ok := index[a.ID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", mutate.ErrIssueNotFound, a.ID)
+	}
+	if expect, ok := a.expectation(); ok {
+		if err := mutate.CheckVersion(all[idx], expect); err != nil {
+			return nil, err
+		}
+	}
+	return fn(&all[idx], all)
+}
@@ -0,0 +1,96 @@
+package ack
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/mutate"
+)
+
+func batchIssues() []model.Issue {
+	now := time.Now().Add(-time.Hour)
+	task := func(id string, bounces int) model.Issue {
+		return model.Issue{ID: id, Title: "Task " + id, Status: model.StatusOpen, IssueType: model.TypeTask,
+			BounceCount: bounces, CreatedAt: now, UpdatedAt: now}
+	}
+	return []model.Issue{task("a", 0), task("b", MaxBounces-1), task("c", 0)}
+}
+
+func TestBatchCommitsAllActions(t *testing.T) {
+	repo, beadsDir := writeTestIssues(t, batchIssues()...)
+
+	results, err := NewService(repo).Batch("agent", []Action{
+		{ID: "a", Action: ActionAccept},
+		{ID: "b", Action: ActionDecline, Reason: "Too big"},
+		{ID: "c", Action: ActionDefer, To: "other"},
+		{ID: "c", Action: ActionDependsOn, To: "a"},
+	})
+	if err != nil {
+		t.Fatalf("Batch failed: %v", err)
+	}
+	if len(results) != 4 || !results[1].Result.Escalated || results[0].Result.Escalated {
+		t.Fatalf("results = %+v", results)
+	}
+	// Both items for c report the version that was written.
+	if results[2].Result.Version != results[3].Result.Version || results[2].Result.Version.ContentHash == "" {
+		t.Errorf("versions = %+v / %+v", results[2].Result.Version, results[3].Result.Version)
+	}
+
+	byID := map[string]model.Issue{}
+	for _, issue := range loadTestIssues(t, beadsDir) {
+		byID[issue.ID] = issue
+	}
+	if byID["a"].Status != model.StatusInProgress || byID["a"].Assignee != "agent" {
+		t.Errorf("a = %+v", byID["a"])
+	}
+	if !byID["b"].Escalated || byID["b"].BounceCount != MaxBounces {
+		t.Errorf("b = %+v", byID["b"])
+	}
+	if c := byID["c"]; c.Assignee != "other" || len(c.Dependencies) != 1 || c.Dependencies[0].DependsOnID != "a" {
+		t.Errorf("c = %+v", c)
+	}
+	if got := mutate.VersionOf(byID["c"]); got.ContentHash != results[3].Result.Version.ContentHash {
+		t.Errorf("reported version %+v, file has %+v", results[3].Result.Version, got)
+	}
+}
+
+func TestBatchIsAllOrNothing(t *testing.T) {
+	repo, beadsDir := writeTestIssues(t, batchIssues()...)
+	path := filepath.Join(beadsDir, "issues.jsonl")
+	before, _ := os.ReadFile(path)
+	stale := time.Now().Add(-48 * time.Hour)
+
+	results, err := NewService(repo).Batch("agent", []Action{
+		{ID: "a", Action: ActionAccept},
+		{ID: "missing", Action: ActionAccept},
+		{ID: "b", Action: ActionDecline},
+		{ID: "c", Action: ActionAccept, ExpectUpdatedAt: &stale},
+		{ID: "c", Action: ActionDependsOn, To: "c"},
+	})
+	if !errors.Is(err, ErrBatchAborted) {
+		t.Fatalf("expected ErrBatchAborted, got %v", err)
+	}
+	if results[0].Err != nil || results[0].Result == nil {
+		t.Errorf("a on its own is fine: %+v", results[0])
+	}
+	if !errors.Is(results[1].Err, mutate.ErrIssueNotFound) {
+		t.Errorf("missing: %v", results[1].Err)
+	}
+	if !errors.Is(results[2].Err, ErrInvalidAction) {
+		t.Errorf("decline without reason: %v", results[2].Err)
+	}
+	if !errors.Is(results[3].Err, mutate.ErrConflict) {
+		t.Errorf("stale expectation: %v", results[3].Err)
+	}
+	if results[4].Err == nil {
+		t.Errorf("self-dependency should fail")
+	}
+
+	if after, _ := os.ReadFile(path); string(after) != string(before) {
+		t.Error("an aborted batch must not write")
+	}
+}
@@ -0,0 +1,178 @@
+package ack
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/mutate"
+)
+
+// ExpiryPolicy says what EnforceDeadlines does with an assignment that was
+// not acknowledged before its deadline.
+type ExpiryPolicy string
+
+const (
+	// ExpireRequeue counts the miss as a bounce and returns the bead to the
+	// unassigned queue, escalating once it reaches MaxBounces.
+	ExpireRequeue ExpiryPolicy = "requeue"
+	// ExpireEscalate counts the miss as a bounce and escalates immediately,
+	// keeping the assignee for context.
+	ExpireEscalate ExpiryPolicy = "escalate"
+)
+
+// ParseExpiryPolicy validates a policy name; "" means ExpireRequeue.
+func ParseExpiryPolicy(s string) (ExpiryPolicy, error) {
+	switch ExpiryPolicy(s) {
+	case "", ExpireRequeue:
+		return ExpireRequeue, nil
+	case ExpireEscalate:
+		return ExpireEscalate, nil
+	}
+	return "", fmt.Errorf("unknown expiry policy %q (want requeue or escalate)", s)
+}
+
+// Expiry records one overdue assignment handled by EnforceDeadlines.
+type Expiry struct {
+	IssueID     string    `json:"id"`
+	Agent       string    `json:"agent"`
+	Deadline    time.Time `json:"deadline"`
+	BounceCount int       `json:"bounce_count"`
+	Escalated   bool      `json:"escalated"`
+}
+
+// EnforceDeadlines handles every assignment still awaiting acknowledgment
+// after its deadline, in one write. Each miss adds an [EXPIRED] comment and
+// counts as a bounce; policy decides whether the bead is re-queued or
+// escalated. Nothing is written when no assignment is overdue.
+func (s *Service) EnforceDeadlines(actor string, now time.Time, policy ExpiryPolicy) ([]Expiry, error) {
+	expired := []Expiry{}
+	_, err := s.store().ModifyAll(func(all []model.Issue) ([]string, error) {
+		expired = expired[:0]
+		var ids []string
+		for i := range all {
+			issue := &all[i]
+			if !analysis.IsOverdueAssignment(*issue, now) {
+				continue
+			}
+			e := Expiry{IssueID: issue.ID, Agent: issue.Assignee, Deadline: *issue.Deadline}
+
+			issue.BounceCount++
+			issue.UpdatedAt = now
+			issue.Comments = append(issue.Comments, &model.Comment{
+				ID:      mutate.NextCommentID(issue),
+				IssueID: issue.ID,
+				Author:  actor,
+				Text: fmt.Sprintf("[EXPIRED] %s did not acknowledge by %s (bounce %d/%d)",
+					e.Agent, e.Deadline.Format(time.RFC3339), issue.BounceCount, MaxBounces),
+				CreatedAt: now,
+			})
+
+			if policy == ExpireEscalate || issue.BounceCount >= MaxBounces {
+				issue.Escalated = true
+				e.Escalated = true
+			} else {
+				issue.Assignee = ""
+				issue.AckStatus = ""
+				issue.Deadline = nil
+			}
+			e.BounceCount = issue.BounceCount
+			expired = append(expired, e)
+			ids = append(ids, issue.ID)
+		}
+		return ids, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return expired, nil
+}
+
+// Requeue resolves an escalation by returning the bead to the unassigned
+// queue with its bounce count reset. The history stays in the comments.
+func (s *Service) Requeue(issueID, lead, note string) (*Result, error) {
+	return s.modifyIssue(issueID, requeueStep(lead, note))
+}
+
+func requeueStep(lead, note string) step {
+	if note == "" {
+		note = "Returned to the queue by " + lead
+	}
+	return func(issue *model.Issue, _ []model.Issue) (*Result, error) {
+		now := time.Now()
+
+		resetAssignment(issue)
+		issue.Assignee = ""
+		issue.AckStatus = ""
+		issue.Deadline = nil
+		issue.UpdatedAt = now
+
+		issue.Comments = append(issue.Comments, &model.Comment{
+			ID:        mutate.NextCommentID(issue),
+			IssueID:   issue.ID,
+			Author:    lead,
+			Text:      "[REQUEUED] " + note,
+			CreatedAt: now,
+		})
+
+		return &Result{
+			Success: true,
+			Message: fmt.Sprintf("Task %s returned to the queue by %s", issue.ID, lead),
+		}, nil
+	}
+}
+
+// Reassign resolves an escalation by handing the bead to another agent, who
+// must acknowledge it again (by deadline, if one is given).
+func (s *Service) Reassign(issueID, lead, toAgent string, deadline *time.Time) (*Result, error) {
+	if toAgent == "" {
+		return nil, fmt.Errorf("target agent is required for reassign")
+	}
+	return s.modifyIssue(issueID, reassignStep(lead, toAgent, deadline))
+}
+
+func reassignStep(lead, toAgent string, deadline *time.Time) step {
+	return func(issue *model.Issue, _ []model.Issue) (*Result, error) {
+		now := time.Now()
+		from := issue.Assignee
+
+		resetAssignment(issue)
+		if from != "" && from != toAgent {
+			issue.DeferredFrom = from
+		}
+		issue.Assignee = toAgent
+		issue.AckStatus = model.AckStatusPending
+		issue.Deadline = deadline
+		issue.UpdatedAt = now
+
+		text := fmt.Sprintf("[REASSIGNED] Task assigned to %s", toAgent)
+		if from != "" {
+			text = fmt.Sprintf("[REASSIGNED] Task reassigned from %s to %s", from, toAgent)
+		}
+		issue.Comments = append(issue.Comments, &model.Comment{
+			ID:        mutate.NextCommentID(issue),
+			IssueID:   issue.ID,
+			Author:    lead,
+			Text:      text,
+			CreatedAt: now,
+		})
+
+		return &Result{
+			Success: true,
+			Message: fmt.Sprintf("Task %s reassigned to %s by %s", issue.ID, toAgent, lead),
+		}, nil
+	}
+}
+
+// resetAssignment clears the escalation state of a bead that a lead has
+// resolved. Work that was under way goes back to open until the next agent
+// accepts it.
+func resetAssignment(issue *model.Issue) {
+	issue.Escalated = false
+	issue.BounceCount = 0
+	issue.DeferredFrom = ""
+	if issue.Status == model.StatusInProgress {
+		issue.Status = model.StatusOpen
+	}
+}
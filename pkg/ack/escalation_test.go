@@ -0,0 +1,145 @@
+package ack
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func writeTestIssues(t *testing.T, issues ...model.Issue) (repo, beadsDir string) {
+	t.Helper()
+	repo = t.TempDir()
+	beadsDir = filepath.Join(repo, ".beads")
+	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+		t.Fatalf("Failed to create beads dir: %v", err)
+	}
+	var data []byte
+	for _, issue := range issues {
+		line, err := json.Marshal(issue)
+		if err != nil {
+			t.Fatalf("Failed to marshal issue: %v", err)
+		}
+		data = append(append(data, line...), '\n')
+	}
+	if err := os.WriteFile(filepath.Join(beadsDir, "issues.jsonl"), data, 0644); err != nil {
+		t.Fatalf("Failed to write issues.jsonl: %v", err)
+	}
+	return repo, beadsDir
+}
+
+func TestEnforceDeadlines(t *testing.T) {
+	now := time.Now()
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+	assigned := func(id, agent string, deadline time.Time, ack model.AckStatus, bounces int) model.Issue {
+		return model.Issue{ID: id, Title: "Task " + id, Status: model.StatusOpen, IssueType: model.TypeTask,
+			Assignee: agent, AckStatus: ack, BounceCount: bounces, Deadline: &deadline,
+			CreatedAt: past, UpdatedAt: past}
+	}
+	repo, beadsDir := writeTestIssues(t,
+		assigned("late", "alice", past, model.AckStatusPending, 0),
+		assigned("last", "bob", past, model.AckStatusDeferred, MaxBounces-1),
+		assigned("early", "carol", future, model.AckStatusPending, 0),
+		assigned("taken", "dave", past, model.AckStatusAccepted, 0),
+	)
+
+	expired, err := NewService(repo).EnforceDeadlines("lead", now, ExpireRequeue)
+	if err != nil {
+		t.Fatalf("EnforceDeadlines failed: %v", err)
+	}
+	if len(expired) != 2 || expired[0].IssueID != "late" || expired[0].Escalated || !expired[1].Escalated {
+		t.Fatalf("expired = %+v", expired)
+	}
+
+	byID := map[string]model.Issue{}
+	for _, issue := range loadTestIssues(t, beadsDir) {
+		byID[issue.ID] = issue
+	}
+	if late := byID["late"]; late.Assignee != "" || late.Deadline != nil || late.BounceCount != 1 || late.Escalated {
+		t.Errorf("late should be re-queued: %+v", late)
+	}
+	if last := byID["last"]; last.Assignee != "bob" || !last.Escalated || last.BounceCount != MaxBounces {
+		t.Errorf("last should escalate at MaxBounces: %+v", last)
+	}
+	if byID["early"].BounceCount != 0 || byID["taken"].BounceCount != 0 {
+		t.Error("assignments that are not overdue must be left alone")
+	}
+
+	history := analysis.ParseAckHistory(byID["late"])
+	if len(history) != 1 || history[0].Kind != analysis.AckEventExpired || history[0].Agent != "alice" {
+		t.Errorf("history = %+v", history)
+	}
+
+	// A second sweep finds nothing and does not rewrite the file.
+	info, _ := os.Stat(filepath.Join(beadsDir, "issues.jsonl"))
+	expired, err = NewService(repo).EnforceDeadlines("lead", now, ExpireEscalate)
+	if err != nil || len(expired) != 0 {
+		t.Fatalf("second sweep = %+v, %v", expired, err)
+	}
+	if after, _ := os.Stat(filepath.Join(beadsDir, "issues.jsonl")); !after.ModTime().Equal(info.ModTime()) {
+		t.Error("an empty sweep should not write")
+	}
+}
+
+func TestResolveEscalation(t *testing.T) {
+	now := time.Now()
+	repo, beadsDir := writeTestIssues(t, model.Issue{
+		ID: "esc", Title: "Escalated", Status: model.StatusInProgress, IssueType: model.TypeTask,
+		Assignee: "alice", CreatedAt: now, UpdatedAt: now,
+	})
+	svc := NewService(repo)
+	if _, err := svc.Defer("esc", "alice", "bob"); err != nil {
+		t.Fatalf("Defer failed: %v", err)
+	}
+	if _, err := svc.Impossible("esc", "bob", "Needs prod access"); err != nil {
+		t.Fatalf("Impossible failed: %v", err)
+	}
+
+	issue := loadTestIssues(t, beadsDir)[0]
+	report := analysis.ComputeEscalations([]model.Issue{issue}, analysis.EscalationOptions{
+		Roster: []analysis.RosterAgent{{Name: "alice"}, {Name: "bob"}, {Name: "carol"}},
+	})
+	item, ok := report.Item("esc")
+	if !ok || item.Kind != analysis.EscalationImpossible || item.LastReason != "Needs prod access" {
+		t.Fatalf("item = %+v", item)
+	}
+	if len(item.Suggested) != 1 || item.Suggested[0].Agent != "carol" {
+		t.Errorf("alice deferred and bob gave up; only carol should be suggested: %+v", item.Suggested)
+	}
+
+	deadline := now.Add(24 * time.Hour).Truncate(time.Second)
+	if _, err := svc.Reassign("esc", "lead", "carol", &deadline); err != nil {
+		t.Fatalf("Reassign failed: %v", err)
+	}
+	issue = loadTestIssues(t, beadsDir)[0]
+	if issue.Assignee != "carol" || issue.Escalated || issue.AckStatus != model.AckStatusPending ||
+		issue.Status != model.StatusOpen || issue.DeferredFrom != "bob" || !issue.Deadline.Equal(deadline) {
+		t.Errorf("after reassign: %+v", issue)
+	}
+
+	if _, err := svc.Requeue("esc", "lead", ""); err != nil {
+		t.Fatalf("Requeue failed: %v", err)
+	}
+	issue = loadTestIssues(t, beadsDir)[0]
+	if issue.Assignee != "" || issue.Deadline != nil || issue.BounceCount != 0 {
+		t.Errorf("after requeue: %+v", issue)
+	}
+
+	var kinds []analysis.AckEventKind
+	for _, ev := range analysis.ParseAckHistory(issue) {
+		kinds = append(kinds, ev.Kind)
+	}
+	want := []analysis.AckEventKind{analysis.AckEventDeferred, analysis.AckEventImpossible, analysis.AckEventReassigned, analysis.AckEventRequeued}
+	if len(kinds) != len(want) {
+		t.Fatalf("history kinds = %v, want %v", kinds, want)
+	}
+	for i := range want {
+		if kinds[i] != want[i] {
+			t.Fatalf("history kinds = %v, want %v", kinds, want)
+		}
+	}
+}
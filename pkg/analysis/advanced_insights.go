@@ -125,6 +125,8 @@ type CriticalPath struct {
 	Length    int      `json:"length"`              // Number of nodes in path
 	IssueIDs  []string `json:"issue_ids"`           // Path from source to sink
 	Truncated bool     `json:"truncated,omitempty"` // True if path was capped
+
+	DurationMinutes int `json:"duration_minutes"` // Sum of estimates along the path (median when unset)
 }
 
 // ParallelCutResult represents suggestions for parallel work maximization.
@@ -727,6 +729,7 @@ func (a *Analyzer) generateKPaths(k int, pathLengthCap int) *KPathsResult {
 	})
 
 	// Reconstruct paths from top k endpoints
+	medianMinutes := a.computeMedianEstimatedMinutes()
 	var paths []CriticalPath
 	usedSources := make(map[int]bool) // Avoid returning duplicate paths (same source)
 
@@ -769,15 +772,18 @@ func (a *Analyzer) generateKPaths(k int, pathLengthCap int) *KPathsResult {
 		}
 
 		issueIDs := make([]string, len(pathIndices))
+		duration := 0
 		for i, idx := range pathIndices {
 			issueIDs[i] = nodes[idx].id
+			duration += estimateOrMedian(a.issueMap[nodes[idx].id], medianMinutes)
 		}
 
 		paths = append(paths, CriticalPath{
-			Rank:      len(paths) + 1,
-			Length:    len(issueIDs),
-			IssueIDs:  issueIDs,
-			Truncated: truncated,
+			Rank:            len(paths) + 1,
+			Length:          len(issueIDs),
+			IssueIDs:        issueIDs,
+			Truncated:       truncated,
+			DurationMinutes: duration,
 		})
 	}
 
@@ -0,0 +1,362 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/correlation"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// AgentLabel is one label an agent has worked under.
+type AgentLabel struct {
+	Label string  `json:"label"`
+	Count int     `json:"count"`
+	Share float64 `json:"share"` // of the agent's accepted beads
+}
+
+// AgentStats is the track record of one assignee, aggregated from the ack
+// fields and bd-ack comments of every bead and, when available, from git
+// history.
+type AgentStats struct {
+	Agent    string   `json:"agent"`
+	Capacity int      `json:"capacity"` // from the roster; 1 when unset
+	Skills   []string `json:"skills,omitempty"`
+
+	// Offers counts the assignments the agent decided on: the ones it
+	// accepted plus the ones it handed back. Pending assignments are not
+	// counted.
+	Offers     int `json:"offers"`
+	Accepted   int `json:"accepted"`
+	Declined   int `json:"declined"`
+	Deferred   int `json:"deferred"`
+	Impossible int `json:"impossible"`
+	Expired    int `json:"expired"`
+	// AcceptanceRate is Accepted/Offers; BounceRate is the share handed back.
+	AcceptanceRate float64 `json:"acceptance_rate"`
+	BounceRate     float64 `json:"bounce_rate"`
+
+	Closed int `json:"closed"`
+	// MedianCycleTimeHours is claim-to-close from git history when known,
+	// else created-to-closed. Nil until the agent has closed something.
+	MedianCycleTimeHours *float64 `json:"median_cycle_time_hours,omitempty"`
+	// Reopened counts closed beads that git history shows were reopened.
+	// ReopenRate is nil without git history.
+	Reopened   int      `json:"reopened"`
+	ReopenRate *float64 `json:"reopen_rate,omitempty"`
+	// Commits is the number of correlated commits the agent authored.
+	Commits int `json:"commits"`
+
+	// WIP is the number of open beads the agent holds and has not handed back.
+	WIP int `json:"wip"`
+	// Labels are the labels of the agent's accepted beads, most frequent
+	// first; Specialization is the share of the top one.
+	Labels         []AgentLabel `json:"labels"`
+	Specialization float64      `json:"specialization"`
+}
+
+// AgentReport holds the stats of every known agent, by name.
+type AgentReport struct {
+	Agents []AgentStats `json:"agents"`
+	// HistoryAvailable is set when git history contributed reopen rates,
+	// cycle times and commit counts.
+	HistoryAvailable bool `json:"history_available"`
+}
+
+// Agent returns the stats for name, if it is a known agent.
+func (r AgentReport) Agent(name string) (AgentStats, bool) {
+	for _, a := range r.Agents {
+		if a.Agent == name {
+			return a, true
+		}
+	}
+	return AgentStats{}, false
+}
+
+// AgentStatsOptions tunes ComputeAgentStats.
+type AgentStatsOptions struct {
+	// Roster lists agents to include even without a track record, with
+	// their capacity and skills.
+	Roster []RosterAgent
+	// History, if set, adds reopen rates, git cycle times and commit
+	// authorship.
+	History *correlation.HistoryReport
+}
+
+// maxAgentLabels caps AgentStats.Labels.
+const maxAgentLabels = 5
+
+// ComputeAgentStats aggregates per-agent reliability metrics. An agent is
+// anyone on the roster, anyone currently assigned a bead, and anyone named
+// in a bd-ack hand-off comment.
+func ComputeAgentStats(issues []model.Issue, opts AgentStatsOptions) AgentReport {
+	byName := make(map[string]*AgentStats)
+	agent := func(name string) *AgentStats {
+		if a, ok := byName[name]; ok {
+			return a
+		}
+		a := &AgentStats{Agent: name, Capacity: 1}
+		byName[name] = a
+		return a
+	}
+	for _, r := range opts.Roster {
+		a := agent(r.Name)
+		a.Capacity = max(1, r.Capacity)
+		a.Skills = r.Skills
+	}
+
+	labelCounts := make(map[string]map[string]int)
+	cycleHours := make(map[string][]float64)
+	closedEver := make(map[string]int)
+
+	for _, issue := range issues {
+		if issue.Status.IsTombstone() {
+			continue
+		}
+		for _, ev := range ParseAckHistory(issue) {
+			if ev.Agent == "" {
+				continue
+			}
+			switch ev.Kind {
+			case AckEventDeclined:
+				agent(ev.Agent).Declined++
+			case AckEventDeferred:
+				agent(ev.Agent).Deferred++
+			case AckEventImpossible:
+				agent(ev.Agent).Impossible++
+			case AckEventExpired:
+				agent(ev.Agent).Expired++
+			}
+		}
+
+		if issue.Assignee == "" {
+			continue
+		}
+		a := agent(issue.Assignee)
+		if ackReleased(issue.AckStatus) {
+			continue
+		}
+		if !issue.Status.IsClosed() {
+			a.WIP++
+		}
+		if issue.AckStatus == model.AckStatusPending {
+			continue
+		}
+
+		a.Accepted++
+		if labelCounts[a.Agent] == nil {
+			labelCounts[a.Agent] = make(map[string]int)
+		}
+		for _, l := range issue.Labels {
+			labelCounts[a.Agent][l]++
+		}
+
+		var history *correlation.BeadHistory
+		if opts.History != nil {
+			if h, ok := opts.History.Histories[issue.ID]; ok {
+				history = &h
+			}
+		}
+		if issue.Status.IsClosed() {
+			a.Closed++
+			if hours, ok := issueCycleHours(issue, history); ok {
+				cycleHours[a.Agent] = append(cycleHours[a.Agent], hours)
+			}
+		}
+		if history != nil && (issue.Status.IsClosed() || history.Milestones.Closed != nil) {
+			closedEver[a.Agent]++
+			if history.Milestones.Reopened != nil {
+				a.Reopened++
+			}
+		}
+	}
+
+	if opts.History != nil {
+		countAgentCommits(byName, opts.History)
+	}
+
+	report := AgentReport{Agents: make([]AgentStats, 0, len(byName)), HistoryAvailable: opts.History != nil}
+	for name, a := range byName {
+		bounced := a.Declined + a.Deferred + a.Impossible + a.Expired
+		a.Offers = a.Accepted + bounced
+		if a.Offers > 0 {
+			a.AcceptanceRate = float64(a.Accepted) / float64(a.Offers)
+			a.BounceRate = float64(bounced) / float64(a.Offers)
+		}
+		if hours := cycleHours[name]; len(hours) > 0 {
+			m := median(hours)
+			a.MedianCycleTimeHours = &m
+		}
+		if opts.History != nil {
+			rate := 0.0
+			if n := closedEver[name]; n > 0 {
+				rate = float64(a.Reopened) / float64(n)
+			}
+			a.ReopenRate = &rate
+		}
+		a.Labels, a.Specialization = agentLabels(labelCounts[name], a.Accepted)
+		report.Agents = append(report.Agents, *a)
+	}
+	sort.Slice(report.Agents, func(i, j int) bool { return report.Agents[i].Agent < report.Agents[j].Agent })
+	return report
+}
+
+// issueCycleHours is the time a closed bead took: claim to close from git
+// history when known, else creation to close.
+func issueCycleHours(issue model.Issue, history *correlation.BeadHistory) (float64, bool) {
+	if history != nil && history.CycleTime != nil && history.CycleTime.ClaimToClose != nil {
+		return history.CycleTime.ClaimToClose.Hours(), true
+	}
+	if issue.ClosedAt == nil || issue.CreatedAt.IsZero() || issue.ClosedAt.Before(issue.CreatedAt) {
+		return 0, false
+	}
+	return issue.ClosedAt.Sub(issue.CreatedAt).Hours(), true
+}
+
+// countAgentCommits credits each correlated commit to the agent whose name
+// matches the commit's author name or email (or the email's local part).
+func countAgentCommits(byName map[string]*AgentStats, history *correlation.HistoryReport) {
+	seen := make(map[string]map[string]bool)
+	for _, h := range history.Histories {
+		for _, c := range h.Commits {
+			name := commitAgent(byName, c.Author, c.AuthorEmail)
+			if name == "" {
+				continue
+			}
+			if seen[name] == nil {
+				seen[name] = make(map[string]bool)
+			}
+			if !seen[name][c.SHA] {
+				seen[name][c.SHA] = true
+				byName[name].Commits++
+			}
+		}
+	}
+}
+
+func commitAgent(byName map[string]*AgentStats, author, email string) string {
+	local, _, _ := strings.Cut(email, "@")
+	for name := range byName {
+		if strings.EqualFold(name, author) || strings.EqualFold(name, email) || (local != "" && strings.EqualFold(name, local)) {
+			return name
+		}
+	}
+	return ""
+}
+
+// agentLabels returns the most frequent labels of an agent's accepted beads
+// and the share of the top one.
+func agentLabels(counts map[string]int, accepted int) ([]AgentLabel, float64) {
+	labels := []AgentLabel{}
+	if accepted == 0 {
+		return labels, 0
+	}
+	for l, n := range counts {
+		labels = append(labels, AgentLabel{Label: l, Count: n, Share: float64(n) / float64(accepted)})
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		if labels[i].Count != labels[j].Count {
+			return labels[i].Count > labels[j].Count
+		}
+		return labels[i].Label < labels[j].Label
+	})
+	if len(labels) > maxAgentLabels {
+		labels = labels[:maxAgentLabels]
+	}
+	if len(labels) == 0 {
+		return labels, 0
+	}
+	return labels, labels[0].Share
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+// AgentRecommendation is a candidate to pick up a bead, from the agents'
+// track records.
+type AgentRecommendation struct {
+	Agent   string   `json:"agent"`
+	Score   float64  `json:"score"`
+	Reasons []string `json:"reasons"`
+}
+
+// RecommendAgents ranks who should pick up issue: agents with a record under
+// the issue's labels, a high acceptance rate, few reopens and spare
+// capacity. Agents who already handed the issue back, or whose roster skills
+// do not cover it, are left out. Issues already held by an agent get no
+// recommendations.
+func (r AgentReport) RecommendAgents(issue model.Issue, limit int) []AgentRecommendation {
+	if issue.Assignee != "" && !ackReleased(issue.AckStatus) {
+		return nil
+	}
+	excluded := map[string]bool{issue.Assignee: true}
+	for _, ev := range ParseAckHistory(issue) {
+		switch ev.Kind {
+		case AckEventDeclined, AckEventDeferred, AckEventImpossible, AckEventExpired:
+			excluded[ev.Agent] = true
+		}
+	}
+
+	var out []AgentRecommendation
+	for _, a := range r.Agents {
+		if excluded[a.Agent] || !agentHasSkill(RosterAgent{Name: a.Agent, Skills: a.Skills}, issue.Labels) {
+			continue
+		}
+		var reasons []string
+
+		// Label affinity: the share of the agent's work under the issue's labels.
+		affinity := 0.0
+		for _, l := range a.Labels {
+			if hasLabel(issue.Labels, l.Label) {
+				affinity += l.Share
+				reasons = append(reasons, "worked "+l.Label)
+			}
+		}
+		affinity = math.Min(1, affinity)
+
+		// Reliability: smoothed acceptance rate, discounted by reopens.
+		reliability := float64(a.Accepted+1) / float64(a.Offers+2)
+		if a.Offers > 0 {
+			reasons = append(reasons, percentReason(a.AcceptanceRate, "accepted"))
+		}
+		if a.ReopenRate != nil {
+			reliability *= 1 - *a.ReopenRate/2
+			if *a.ReopenRate > 0 {
+				reasons = append(reasons, percentReason(*a.ReopenRate, "reopened"))
+			}
+		}
+
+		// Capacity: agents at or over capacity rank well below free ones.
+		headroom := 1.0
+		if a.WIP >= a.Capacity {
+			headroom = 0.25
+			reasons = append(reasons, "at capacity")
+		}
+
+		score := (0.5 + 0.5*affinity) * reliability * headroom
+		out = append(out, AgentRecommendation{Agent: a.Agent, Score: math.Round(score*1000) / 1000, Reasons: reasons})
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score > out[j].Score
+		}
+		return out[i].Agent < out[j].Agent
+	})
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+func percentReason(rate float64, what string) string {
+	return fmt.Sprintf("%.0f%% %s", rate*100, what)
+}
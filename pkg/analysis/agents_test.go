@@ -0,0 +1,118 @@
+package analysis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/correlation"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func agentTestIssues() []model.Issue {
+	t0 := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+	closedAt := func(h int) *time.Time { t := t0.Add(time.Duration(h) * time.Hour); return &t }
+	declined := func(agent string) *model.Comment {
+		return &model.Comment{Author: agent, Text: "[DECLINED] Not mine (bounce 1/3)", CreatedAt: t0}
+	}
+	return []model.Issue{
+		{ID: "a1", Status: model.StatusClosed, Assignee: "alice", AckStatus: model.AckStatusAccepted, Labels: []string{"backend"}, CreatedAt: t0, ClosedAt: closedAt(2)},
+		{ID: "a2", Status: model.StatusClosed, Assignee: "alice", Labels: []string{"backend", "db"}, CreatedAt: t0, ClosedAt: closedAt(6)},
+		{ID: "a3", Status: model.StatusInProgress, Assignee: "alice", AckStatus: model.AckStatusAccepted, Labels: []string{"backend"},
+			Comments: []*model.Comment{declined("bob")}},
+		{ID: "b1", Status: model.StatusOpen, Assignee: "bob", AckStatus: model.AckStatusDeclined, Comments: []*model.Comment{declined("bob")}},
+		{ID: "b2", Status: model.StatusOpen, Assignee: "bob", AckStatus: model.AckStatusPending},
+		{ID: "new", Status: model.StatusOpen, Labels: []string{"backend"}, Comments: []*model.Comment{declined("carol")}},
+	}
+}
+
+func TestComputeAgentStats(t *testing.T) {
+	report := ComputeAgentStats(agentTestIssues(), AgentStatsOptions{
+		Roster: []RosterAgent{{Name: "dev", Capacity: 2, Skills: []string{"frontend"}}},
+	})
+	if len(report.Agents) != 4 || report.HistoryAvailable {
+		t.Fatalf("agents = %+v", report.Agents)
+	}
+
+	alice, _ := report.Agent("alice")
+	if alice.Accepted != 3 || alice.Offers != 3 || alice.AcceptanceRate != 1 || alice.Closed != 2 || alice.WIP != 1 {
+		t.Errorf("alice = %+v", alice)
+	}
+	if alice.MedianCycleTimeHours == nil || *alice.MedianCycleTimeHours != 4 {
+		t.Errorf("alice median cycle time = %v", alice.MedianCycleTimeHours)
+	}
+	if alice.ReopenRate != nil {
+		t.Errorf("reopen rate needs git history")
+	}
+	if len(alice.Labels) != 2 || alice.Labels[0].Label != "backend" || alice.Specialization != 1 {
+		t.Errorf("alice labels = %+v (%v)", alice.Labels, alice.Specialization)
+	}
+
+	// bob declined twice and has one undecided assignment.
+	bob, _ := report.Agent("bob")
+	if bob.Declined != 2 || bob.Offers != 2 || bob.BounceRate != 1 || bob.WIP != 1 {
+		t.Errorf("bob = %+v", bob)
+	}
+	if dev, ok := report.Agent("dev"); !ok || dev.Capacity != 2 || dev.Offers != 0 {
+		t.Errorf("roster agent without a record should be listed: %+v", dev)
+	}
+}
+
+func TestComputeAgentStatsWithHistory(t *testing.T) {
+	t0 := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+	claimToClose := 90 * time.Minute
+	history := &correlation.HistoryReport{Histories: map[string]correlation.BeadHistory{
+		"a1": {
+			BeadID:     "a1",
+			Milestones: correlation.BeadMilestones{Closed: &correlation.BeadEvent{}, Reopened: &correlation.BeadEvent{}},
+			CycleTime:  &correlation.CycleTime{ClaimToClose: &claimToClose},
+			Commits:    []correlation.CorrelatedCommit{{SHA: "c1", Author: "Alice", AuthorEmail: "alice@example.com"}},
+		},
+		"a2": {
+			BeadID:     "a2",
+			Milestones: correlation.BeadMilestones{Closed: &correlation.BeadEvent{Timestamp: t0}},
+			Commits: []correlation.CorrelatedCommit{
+				{SHA: "c1", Author: "Alice", AuthorEmail: "alice@example.com"},
+				{SHA: "c2", Author: "Someone", AuthorEmail: "alice@example.com"},
+			},
+		},
+	}}
+	report := ComputeAgentStats(agentTestIssues(), AgentStatsOptions{History: history})
+
+	alice, _ := report.Agent("alice")
+	if alice.Reopened != 1 || alice.ReopenRate == nil || *alice.ReopenRate != 0.5 {
+		t.Errorf("alice reopens = %d / %v", alice.Reopened, alice.ReopenRate)
+	}
+	if alice.Commits != 2 {
+		t.Errorf("alice commits = %d, want 2 (c1 counted once)", alice.Commits)
+	}
+	// a1 uses the git claim-to-close time (1.5h); a2 falls back to 6h.
+	if *alice.MedianCycleTimeHours != 3.75 {
+		t.Errorf("alice median = %v", *alice.MedianCycleTimeHours)
+	}
+}
+
+func TestRecommendAgents(t *testing.T) {
+	issues := agentTestIssues()
+	report := ComputeAgentStats(issues, AgentStatsOptions{
+		Roster: []RosterAgent{{Name: "alice", Capacity: 3}, {Name: "dev", Skills: []string{"frontend"}}, {Name: "erin"}},
+	})
+
+	// "new" is backend work that carol already declined.
+	recs := report.RecommendAgents(issues[5], 0)
+	var names []string
+	for _, r := range recs {
+		names = append(names, r.Agent)
+	}
+	if len(names) != 3 || names[0] != "alice" || names[2] != "bob" {
+		t.Fatalf("recommendations = %+v", recs)
+	}
+	for _, r := range recs {
+		if r.Agent == "carol" || r.Agent == "dev" {
+			t.Errorf("%s should not be recommended: %+v", r.Agent, recs)
+		}
+	}
+
+	if recs := report.RecommendAgents(issues[2], 3); recs != nil {
+		t.Errorf("held beads get no recommendations: %+v", recs)
+	}
+}
@@ -0,0 +1,235 @@
+package analysis
+
+import (
+	"sort"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// ScheduleTiming is the duration-weighted schedule of one open issue, in
+// minutes from "now" with unlimited parallelism. Slack is how long the
+// issue can slip without delaying the project's finish.
+type ScheduleTiming struct {
+	ID              string `json:"id"`
+	DurationMinutes int    `json:"duration_minutes"`
+	EstimateSource  string `json:"estimate_source"` // explicit | median
+	EarliestStart   int    `json:"earliest_start_minutes"`
+	EarliestFinish  int    `json:"earliest_finish_minutes"`
+	LatestStart     int    `json:"latest_start_minutes"`
+	LatestFinish    int    `json:"latest_finish_minutes"`
+	SlackMinutes    int    `json:"slack_minutes"`
+	Critical        bool   `json:"critical"`
+}
+
+// WeightedCriticalPath is the longest chain of open blocking work measured in
+// estimated minutes rather than hops (CPM forward/backward pass). Issues
+// without an estimate take the median estimate, as in ETA forecasting.
+type WeightedCriticalPath struct {
+	TotalMinutes  int              `json:"total_minutes"`  // earliest finish of the whole open graph
+	MedianMinutes int              `json:"median_minutes"` // duration used for unestimated issues
+	Estimated     int              `json:"estimated"`      // open issues with an explicit estimate
+	Unestimated   int              `json:"unestimated"`    // open issues using the median
+	Chain         []ScheduleTiming `json:"chain"`          // schedule-critical chain, first to last
+	Cyclic        []string         `json:"cyclic,omitempty"`
+
+	timings map[string]ScheduleTiming
+}
+
+// Timing returns the schedule of an open issue.
+func (w *WeightedCriticalPath) Timing(id string) (ScheduleTiming, bool) {
+	if w == nil {
+		return ScheduleTiming{}, false
+	}
+	t, ok := w.timings[id]
+	return t, ok
+}
+
+// Timings returns every scheduled issue ordered by earliest start, then ID.
+func (w *WeightedCriticalPath) Timings() []ScheduleTiming {
+	if w == nil {
+		return nil
+	}
+	out := make([]ScheduleTiming, 0, len(w.timings))
+	for _, t := range w.timings {
+		out = append(out, t)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].EarliestStart != out[j].EarliestStart {
+			return out[i].EarliestStart < out[j].EarliestStart
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+// ChainIDs returns the IDs of the schedule-critical chain.
+func (w *WeightedCriticalPath) ChainIDs() []string {
+	if w == nil {
+		return nil
+	}
+	ids := make([]string, len(w.Chain))
+	for i, t := range w.Chain {
+		ids[i] = t.ID
+	}
+	return ids
+}
+
+// WeightedCriticalPath computes the duration-weighted schedule for the
+// analyzer's issues.
+func (a *Analyzer) WeightedCriticalPath() *WeightedCriticalPath {
+	issues := make([]model.Issue, 0, len(a.issueMap))
+	for _, issue := range a.issueMap {
+		issues = append(issues, issue)
+	}
+	return ComputeWeightedCriticalPath(issues)
+}
+
+// ComputeWeightedCriticalPath runs the critical path method over open issues
+// and their blocking dependencies on other open issues. Issues caught in a
+// dependency cycle cannot be scheduled and are listed in Cyclic instead.
+func ComputeWeightedCriticalPath(issues []model.Issue) *WeightedCriticalPath {
+	median := computeMedianEstimatedMinutes(issues)
+	result := &WeightedCriticalPath{
+		MedianMinutes: median,
+		timings:       make(map[string]ScheduleTiming),
+	}
+
+	open := make(map[string]*model.Issue)
+	var ids []string
+	for i := range issues {
+		issue := &issues[i]
+		if issue.Status.IsClosed() || issue.Status.IsTombstone() {
+			continue
+		}
+		if _, dup := open[issue.ID]; dup {
+			continue
+		}
+		open[issue.ID] = issue
+		ids = append(ids, issue.ID)
+	}
+	if len(ids) == 0 {
+		return result
+	}
+	sort.Strings(ids)
+
+	// preds[x] are the open issues blocking x; succs is the reverse.
+	preds := make(map[string][]string, len(ids))
+	succs := make(map[string][]string, len(ids))
+	inDegree := make(map[string]int, len(ids))
+	for _, id := range ids {
+		seen := make(map[string]bool)
+		for _, dep := range open[id].Dependencies {
+			if dep == nil || !dep.Type.IsBlocking() || dep.DependsOnID == id || seen[dep.DependsOnID] {
+				continue
+			}
+			if _, ok := open[dep.DependsOnID]; !ok {
+				continue
+			}
+			seen[dep.DependsOnID] = true
+			preds[id] = append(preds[id], dep.DependsOnID)
+			succs[dep.DependsOnID] = append(succs[dep.DependsOnID], id)
+			inDegree[id]++
+		}
+	}
+
+	// Kahn's algorithm; ids is sorted so the order is deterministic.
+	var order []string
+	var queue []string
+	for _, id := range ids {
+		if inDegree[id] == 0 {
+			queue = append(queue, id)
+		}
+	}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		order = append(order, id)
+		for _, next := range succs[id] {
+			inDegree[next]--
+			if inDegree[next] == 0 {
+				queue = append(queue, next)
+			}
+		}
+	}
+	scheduled := make(map[string]bool, len(order))
+	for _, id := range order {
+		scheduled[id] = true
+	}
+	for _, id := range ids {
+		if !scheduled[id] {
+			result.Cyclic = append(result.Cyclic, id)
+		}
+	}
+
+	// Forward pass: earliest start/finish.
+	timings := result.timings
+	for _, id := range order {
+		t := ScheduleTiming{ID: id, DurationMinutes: estimateOrMedian(*open[id], median), EstimateSource: "median"}
+		if est := open[id].EstimatedMinutes; est != nil && *est > 0 {
+			t.EstimateSource = "explicit"
+			result.Estimated++
+		} else {
+			result.Unestimated++
+		}
+		for _, p := range preds[id] {
+			if pt, ok := timings[p]; ok && pt.EarliestFinish > t.EarliestStart {
+				t.EarliestStart = pt.EarliestFinish
+			}
+		}
+		t.EarliestFinish = t.EarliestStart + t.DurationMinutes
+		if t.EarliestFinish > result.TotalMinutes {
+			result.TotalMinutes = t.EarliestFinish
+		}
+		timings[id] = t
+	}
+
+	// Backward pass: latest start/finish and slack.
+	for i := len(order) - 1; i >= 0; i-- {
+		id := order[i]
+		t := timings[id]
+		t.LatestFinish = result.TotalMinutes
+		for _, s := range succs[id] {
+			if st, ok := timings[s]; ok && st.LatestStart < t.LatestFinish {
+				t.LatestFinish = st.LatestStart
+			}
+		}
+		t.LatestStart = t.LatestFinish - t.DurationMinutes
+		t.SlackMinutes = t.LatestStart - t.EarliestStart
+		t.Critical = t.SlackMinutes == 0
+		timings[id] = t
+	}
+
+	// The chain ends at a critical issue finishing last and walks back through
+	// critical predecessors that finish exactly when it starts.
+	var end string
+	for _, id := range order {
+		t := timings[id]
+		if t.Critical && t.EarliestFinish == result.TotalMinutes && (end == "" || id < end) {
+			end = id
+		}
+	}
+	for id := end; id != ""; {
+		t := timings[id]
+		result.Chain = append(result.Chain, t)
+		next := ""
+		for _, p := range preds[id] {
+			pt := timings[p]
+			if pt.Critical && pt.EarliestFinish == t.EarliestStart && (next == "" || p < next) {
+				next = p
+			}
+		}
+		id = next
+	}
+	for i, j := 0, len(result.Chain)-1; i < j; i, j = i+1, j-1 {
+		result.Chain[i], result.Chain[j] = result.Chain[j], result.Chain[i]
+	}
+	return result
+}
+
+// estimateOrMedian returns the issue's estimate, or median when it has none.
+func estimateOrMedian(issue model.Issue, median int) int {
+	if issue.EstimatedMinutes != nil && *issue.EstimatedMinutes > 0 {
+		return *issue.EstimatedMinutes
+	}
+	return median
+}
@@ -0,0 +1,137 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func minutes(m int) *int { return &m }
+
+func blockedBy(id string, blockers ...string) []*model.Dependency {
+	var deps []*model.Dependency
+	for _, b := range blockers {
+		deps = append(deps, &model.Dependency{IssueID: id, DependsOnID: b, Type: model.DepBlocks})
+	}
+	return deps
+}
+
+func TestWeightedCriticalPath_PrefersLongDurationOverHops(t *testing.T) {
+	// A(10m) -> B(10m) -> C(10m) is three hops but 30 minutes;
+	// D(3 days) -> C is two hops but much longer.
+	issues := []model.Issue{
+		{ID: "A", Status: model.StatusOpen, EstimatedMinutes: minutes(10)},
+		{ID: "B", Status: model.StatusOpen, EstimatedMinutes: minutes(10), Dependencies: blockedBy("B", "A")},
+		{ID: "D", Status: model.StatusOpen, EstimatedMinutes: minutes(3 * 24 * 60)},
+		{ID: "C", Status: model.StatusOpen, EstimatedMinutes: minutes(10), Dependencies: blockedBy("C", "B", "D")},
+	}
+	wcp := ComputeWeightedCriticalPath(issues)
+
+	if got := wcp.ChainIDs(); !reflect.DeepEqual(got, []string{"D", "C"}) {
+		t.Fatalf("chain = %v, want [D C]", got)
+	}
+	if wcp.TotalMinutes != 3*24*60+10 {
+		t.Errorf("TotalMinutes = %d", wcp.TotalMinutes)
+	}
+
+	c, _ := wcp.Timing("C")
+	if c.EarliestStart != 3*24*60 || c.SlackMinutes != 0 || !c.Critical {
+		t.Errorf("C timing = %+v", c)
+	}
+	a, _ := wcp.Timing("A")
+	wantSlack := 3*24*60 - 20
+	if a.SlackMinutes != wantSlack || a.Critical || a.LatestStart != wantSlack {
+		t.Errorf("A timing = %+v, want slack %d", a, wantSlack)
+	}
+}
+
+func TestWeightedCriticalPath_MedianFallbackAndClosedBlockers(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "done", Status: model.StatusClosed, EstimatedMinutes: minutes(600)},
+		{ID: "x", Status: model.StatusOpen, EstimatedMinutes: minutes(60), Dependencies: blockedBy("x", "done")},
+		{ID: "y", Status: model.StatusInProgress, EstimatedMinutes: minutes(200)},
+		{ID: "z", Status: model.StatusOpen, Dependencies: blockedBy("z", "x")},
+	}
+	wcp := ComputeWeightedCriticalPath(issues)
+
+	// Median over all estimates (60, 200, 600) is 200.
+	if wcp.MedianMinutes != 200 {
+		t.Fatalf("MedianMinutes = %d, want 200", wcp.MedianMinutes)
+	}
+	z, ok := wcp.Timing("z")
+	if !ok || z.DurationMinutes != 200 || z.EstimateSource != "median" {
+		t.Fatalf("z timing = %+v", z)
+	}
+	if _, ok := wcp.Timing("done"); ok {
+		t.Error("closed issues should not be scheduled")
+	}
+	x, _ := wcp.Timing("x")
+	if x.EarliestStart != 0 {
+		t.Errorf("closed blocker should not delay x: %+v", x)
+	}
+	if wcp.Estimated != 2 || wcp.Unestimated != 1 {
+		t.Errorf("estimated/unestimated = %d/%d", wcp.Estimated, wcp.Unestimated)
+	}
+	if got := wcp.ChainIDs(); !reflect.DeepEqual(got, []string{"x", "z"}) {
+		t.Errorf("chain = %v, want [x z]", got)
+	}
+	y, _ := wcp.Timing("y")
+	if y.SlackMinutes != 60 {
+		t.Errorf("y slack = %d, want 60", y.SlackMinutes)
+	}
+}
+
+func TestWeightedCriticalPath_CyclesAndEmpty(t *testing.T) {
+	if wcp := ComputeWeightedCriticalPath(nil); wcp.TotalMinutes != 0 || len(wcp.Chain) != 0 {
+		t.Errorf("empty input: %+v", wcp)
+	}
+
+	issues := []model.Issue{
+		{ID: "a", Status: model.StatusOpen, Dependencies: blockedBy("a", "b")},
+		{ID: "b", Status: model.StatusOpen, Dependencies: blockedBy("b", "a")},
+		{ID: "c", Status: model.StatusOpen, EstimatedMinutes: minutes(30)},
+	}
+	wcp := ComputeWeightedCriticalPath(issues)
+	if !reflect.DeepEqual(wcp.Cyclic, []string{"a", "b"}) {
+		t.Errorf("Cyclic = %v", wcp.Cyclic)
+	}
+	if got := wcp.ChainIDs(); !reflect.DeepEqual(got, []string{"c"}) {
+		t.Errorf("chain = %v, want [c]", got)
+	}
+}
+
+func TestExecutionPlanIncludesSchedule(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "short", Status: model.StatusOpen, EstimatedMinutes: minutes(30)},
+		{ID: "long", Status: model.StatusOpen, EstimatedMinutes: minutes(480)},
+		{ID: "next", Status: model.StatusOpen, EstimatedMinutes: minutes(60), Dependencies: blockedBy("next", "short", "long")},
+	}
+	plan := NewAnalyzer(issues).GetExecutionPlan()
+	if plan.CriticalPath == nil || !reflect.DeepEqual(plan.CriticalPath.ChainIDs(), []string{"long", "next"}) {
+		t.Fatalf("plan critical path = %+v", plan.CriticalPath)
+	}
+	items := map[string]PlanItem{}
+	for _, track := range plan.Tracks {
+		for _, item := range track.Items {
+			items[item.ID] = item
+		}
+	}
+	if it := items["long"]; !it.Critical || it.SlackMinutes != 0 || it.EstimatedMinutes != 480 {
+		t.Errorf("long item = %+v", it)
+	}
+	if it := items["short"]; it.Critical || it.SlackMinutes != 450 {
+		t.Errorf("short item = %+v", it)
+	}
+}
+
+func TestKPathsReportDuration(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "a", Status: model.StatusOpen, EstimatedMinutes: minutes(15)},
+		{ID: "b", Status: model.StatusOpen, EstimatedMinutes: minutes(45), Dependencies: blockedBy("b", "a")},
+	}
+	res := NewAnalyzer(issues).generateKPaths(5, 0)
+	if len(res.Paths) != 1 || res.Paths[0].DurationMinutes != 60 {
+		t.Fatalf("paths = %+v", res.Paths)
+	}
+}
@@ -0,0 +1,167 @@
+package analysis
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// WouldCreateBlockingCycle is WouldCreateCycle restricted to blocking
+// dependencies, matching the edges the analysis graph is built from.
+// Adding a non-blocking link (e.g. "related") never creates a cycle.
+func WouldCreateBlockingCycle(issues []model.Issue, fromID, toID string, depType model.DependencyType) (bool, []string) {
+	if depType == "" {
+		depType = model.DepBlocks
+	}
+	if !depType.IsBlocking() {
+		return false, nil
+	}
+	return WouldCreateCycle(blockingView(issues), fromID, toID)
+}
+
+// blockingView returns lightweight copies of issues that keep only blocking
+// dependencies.
+func blockingView(issues []model.Issue) []model.Issue {
+	view := make([]model.Issue, len(issues))
+	for i, issue := range issues {
+		view[i] = model.Issue{ID: issue.ID, Status: issue.Status}
+		for _, dep := range issue.Dependencies {
+			if dep != nil && dep.Type.IsBlocking() {
+				view[i].Dependencies = append(view[i].Dependencies, dep)
+			}
+		}
+	}
+	return view
+}
+
+// DependencyImpact describes what adding a dependency does to the graph:
+// whether it closes a cycle, and how it shifts critical-path depth and the
+// number of actionable issues.
+type DependencyImpact struct {
+	IssueID     string               `json:"issue_id"`
+	DependsOnID string               `json:"depends_on_id"`
+	Type        model.DependencyType `json:"type"`
+
+	WouldCycle bool     `json:"would_cycle"`
+	CyclePath  []string `json:"cycle_path,omitempty"`
+
+	// CriticalPathDepth is the longest chain of open issues linked by
+	// blocking dependencies, counted in issues.
+	CriticalPathDepthBefore int `json:"critical_path_depth_before"`
+	CriticalPathDepthAfter  int `json:"critical_path_depth_after"`
+	CriticalPathDepthDelta  int `json:"critical_path_depth_delta"`
+
+	ActionableBefore int `json:"actionable_before"`
+	ActionableAfter  int `json:"actionable_after"`
+	ActionableDelta  int `json:"actionable_delta"`
+}
+
+// ComputeDependencyImpact simulates adding a fromID → toID dependency to
+// issues without modifying them.
+func ComputeDependencyImpact(issues []model.Issue, fromID, toID string, depType model.DependencyType) DependencyImpact {
+	if depType == "" {
+		depType = model.DepBlocks
+	}
+	impact := DependencyImpact{IssueID: fromID, DependsOnID: toID, Type: depType}
+	impact.WouldCycle, impact.CyclePath = WouldCreateBlockingCycle(issues, fromID, toID, depType)
+
+	// A self-dependency cannot be simulated (the graph has no self edges);
+	// writers reject it anyway, so report it as changing nothing.
+	after := issues
+	if fromID != toID {
+		after = withDependency(issues, fromID, toID, depType)
+	}
+
+	impact.CriticalPathDepthBefore = CriticalPathDepth(issues)
+	impact.CriticalPathDepthAfter = CriticalPathDepth(after)
+	impact.CriticalPathDepthDelta = impact.CriticalPathDepthAfter - impact.CriticalPathDepthBefore
+
+	impact.ActionableBefore = len(NewAnalyzer(issues).GetActionableIssues())
+	impact.ActionableAfter = len(NewAnalyzer(after).GetActionableIssues())
+	impact.ActionableDelta = impact.ActionableAfter - impact.ActionableBefore
+
+	return impact
+}
+
+// Summary returns a one-line description such as
+// "critical path 3→4 (+1), actionable 5→4 (-1)".
+func (d DependencyImpact) Summary() string {
+	s := fmt.Sprintf("critical path %d→%d (%+d), actionable %d→%d (%+d)",
+		d.CriticalPathDepthBefore, d.CriticalPathDepthAfter, d.CriticalPathDepthDelta,
+		d.ActionableBefore, d.ActionableAfter, d.ActionableDelta)
+	if d.WouldCycle {
+		s += ", creates cycle " + formatCyclePath(d.CyclePath)
+	}
+	return s
+}
+
+// withDependency returns a copy of issues in which fromID also depends on
+// toID. Only the modified issue's dependency slice is copied.
+func withDependency(issues []model.Issue, fromID, toID string, depType model.DependencyType) []model.Issue {
+	out := make([]model.Issue, len(issues))
+	copy(out, issues)
+	for i := range out {
+		if out[i].ID != fromID {
+			continue
+		}
+		deps := make([]*model.Dependency, 0, len(out[i].Dependencies)+1)
+		deps = append(deps, out[i].Dependencies...)
+		out[i].Dependencies = append(deps, &model.Dependency{IssueID: fromID, DependsOnID: toID, Type: depType})
+		break
+	}
+	return out
+}
+
+// CriticalPathDepth returns the number of issues on the longest chain of
+// open issues connected by blocking dependencies. Closed issues no longer
+// gate anything and are ignored. Cycles are tolerated: an edge back into the
+// chain being explored is not followed.
+func CriticalPathDepth(issues []model.Issue) int {
+	open := make(map[string]model.Issue, len(issues))
+	for _, issue := range issues {
+		if !issue.Status.IsClosed() {
+			open[issue.ID] = issue
+		}
+	}
+
+	ids := make([]string, 0, len(open))
+	for id := range open {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	depth := make(map[string]int, len(open))
+	onStack := make(map[string]bool)
+
+	var visit func(id string) int
+	visit = func(id string) int {
+		if d, ok := depth[id]; ok {
+			return d
+		}
+		onStack[id] = true
+		longest := 0
+		for _, dep := range open[id].Dependencies {
+			if dep == nil || !dep.Type.IsBlocking() || onStack[dep.DependsOnID] {
+				continue
+			}
+			if _, ok := open[dep.DependsOnID]; !ok {
+				continue
+			}
+			if d := visit(dep.DependsOnID); d > longest {
+				longest = d
+			}
+		}
+		onStack[id] = false
+		depth[id] = longest + 1
+		return depth[id]
+	}
+
+	maxDepth := 0
+	for _, id := range ids {
+		if d := visit(id); d > maxDepth {
+			maxDepth = d
+		}
+	}
+	return maxDepth
+}
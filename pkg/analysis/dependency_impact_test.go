@@ -0,0 +1,94 @@
+package analysis
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func blocks(from, to string) *model.Dependency {
+	return &model.Dependency{IssueID: from, DependsOnID: to, Type: model.DepBlocks}
+}
+
+func TestWouldCreateBlockingCycle(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "A", Dependencies: []*model.Dependency{blocks("A", "B")}},
+		{ID: "B", Dependencies: []*model.Dependency{{IssueID: "B", DependsOnID: "C", Type: model.DepRelated}}},
+		{ID: "C", Dependencies: []*model.Dependency{blocks("C", "A")}},
+	}
+
+	// B → A closes A → B → A.
+	cycle, path := WouldCreateBlockingCycle(issues, "B", "A", model.DepBlocks)
+	if !cycle {
+		t.Fatal("expected cycle for B → A")
+	}
+	if strings.Join(path, ",") != "B,A,B" {
+		t.Errorf("path = %v, want [B A B]", path)
+	}
+
+	// C → B would only loop back via B's related link to C, which does not block.
+	if cycle, _ := WouldCreateBlockingCycle(issues, "C", "B", model.DepBlocks); cycle {
+		t.Error("related link should not count toward a cycle")
+	}
+
+	// Non-blocking additions never cycle.
+	if cycle, _ := WouldCreateBlockingCycle(issues, "B", "A", model.DepRelated); cycle {
+		t.Error("related dependency should never be reported as a cycle")
+	}
+}
+
+func TestCriticalPathDepth(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "A", Status: model.StatusOpen, Dependencies: []*model.Dependency{blocks("A", "B")}},
+		{ID: "B", Status: model.StatusOpen, Dependencies: []*model.Dependency{blocks("B", "C")}},
+		{ID: "C", Status: model.StatusOpen},
+		{ID: "D", Status: model.StatusOpen, Dependencies: []*model.Dependency{blocks("D", "X")}},
+		{ID: "X", Status: model.StatusClosed},
+	}
+	if got := CriticalPathDepth(issues); got != 3 {
+		t.Errorf("depth = %d, want 3", got)
+	}
+	if got := CriticalPathDepth(nil); got != 0 {
+		t.Errorf("empty depth = %d, want 0", got)
+	}
+
+	// A cycle must not hang or overflow.
+	issues[2].Dependencies = []*model.Dependency{blocks("C", "A")}
+	if got := CriticalPathDepth(issues); got != 3 {
+		t.Errorf("cyclic depth = %d, want 3", got)
+	}
+}
+
+func TestComputeDependencyImpact(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "A", Status: model.StatusOpen},
+		{ID: "B", Status: model.StatusOpen, Dependencies: []*model.Dependency{blocks("B", "C")}},
+		{ID: "C", Status: model.StatusOpen},
+	}
+
+	impact := ComputeDependencyImpact(issues, "A", "B", "")
+	if impact.Type != model.DepBlocks || impact.WouldCycle {
+		t.Fatalf("unexpected impact: %+v", impact)
+	}
+	if impact.CriticalPathDepthBefore != 2 || impact.CriticalPathDepthAfter != 3 || impact.CriticalPathDepthDelta != 1 {
+		t.Errorf("depth = %d→%d (%d)", impact.CriticalPathDepthBefore, impact.CriticalPathDepthAfter, impact.CriticalPathDepthDelta)
+	}
+	if impact.ActionableBefore != 2 || impact.ActionableAfter != 1 || impact.ActionableDelta != -1 {
+		t.Errorf("actionable = %d→%d (%d)", impact.ActionableBefore, impact.ActionableAfter, impact.ActionableDelta)
+	}
+	if len(issues[0].Dependencies) != 0 {
+		t.Error("input issues were modified")
+	}
+	if got := impact.Summary(); got != "critical path 2→3 (+1), actionable 2→1 (-1)" {
+		t.Errorf("Summary = %q", got)
+	}
+
+	cyclic := ComputeDependencyImpact(issues, "C", "B", model.DepBlocks)
+	if !cyclic.WouldCycle || len(cyclic.CyclePath) == 0 {
+		t.Fatalf("expected cycle, got %+v", cyclic)
+	}
+	if !strings.Contains(cyclic.Summary(), "creates cycle C → B → C") {
+		t.Errorf("Summary = %q", cyclic.Summary())
+	}
+}
@@ -0,0 +1,318 @@
+package analysis
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// AckEventKind identifies an entry in an issue's acknowledgment history.
+type AckEventKind string
+
+const (
+	AckEventDeclined   AckEventKind = "declined"
+	AckEventDeferred   AckEventKind = "deferred"
+	AckEventImpossible AckEventKind = "impossible"
+	AckEventExpired    AckEventKind = "expired"
+	AckEventReassigned AckEventKind = "reassigned"
+	AckEventRequeued   AckEventKind = "requeued"
+)
+
+// AckEvent is one hand-off recorded by bd-ack as a tagged comment.
+type AckEvent struct {
+	Kind AckEventKind `json:"kind"`
+	// Agent is the agent the event is about: who declined, deferred or
+	// missed the deadline, or the previous assignee of a reassignment.
+	Agent  string    `json:"agent,omitempty"`
+	To     string    `json:"to,omitempty"`
+	Reason string    `json:"reason,omitempty"`
+	At     time.Time `json:"at"`
+}
+
+var (
+	ackDeclinedRe   = regexp.MustCompile(`^\[DECLINED\] (.*?)(?: \(bounce \d+/\d+\))?$`)
+	ackDeferredRe   = regexp.MustCompile(`^\[DEFERRED\] Task deferred from (.*) to (.*)$`)
+	ackImpossibleRe = regexp.MustCompile(`^\[IMPOSSIBLE\] (.*?)(?: - Escalated for strategic decision)?$`)
+	ackExpiredRe    = regexp.MustCompile(`^\[EXPIRED\] (.*) did not acknowledge by (\S+)(?: \(bounce \d+/\d+\))?$`)
+	ackReassignedRe = regexp.MustCompile(`^\[REASSIGNED\] Task (?:reassigned from (.*) to|assigned to) (.*)$`)
+	ackRequeuedRe   = regexp.MustCompile(`^\[REQUEUED\] (.*)$`)
+)
+
+// ParseAckHistory extracts the acknowledgment history of an issue from the
+// [DECLINED], [DEFERRED], [IMPOSSIBLE], [EXPIRED], [REASSIGNED] and
+// [REQUEUED] comments bd-ack writes, oldest first. Other comments are
+// ignored.
+func ParseAckHistory(issue model.Issue) []AckEvent {
+	var events []AckEvent
+	for _, c := range issue.Comments {
+		if c == nil || !strings.HasPrefix(c.Text, "[") {
+			continue
+		}
+		text := strings.TrimSpace(c.Text)
+		ev := AckEvent{Agent: c.Author, At: c.CreatedAt}
+		if m := ackDeclinedRe.FindStringSubmatch(text); m != nil {
+			ev.Kind, ev.Reason = AckEventDeclined, m[1]
+		} else if m := ackDeferredRe.FindStringSubmatch(text); m != nil {
+			ev.Kind, ev.Agent, ev.To = AckEventDeferred, m[1], m[2]
+		} else if m := ackImpossibleRe.FindStringSubmatch(text); m != nil {
+			ev.Kind, ev.Reason = AckEventImpossible, m[1]
+		} else if m := ackExpiredRe.FindStringSubmatch(text); m != nil {
+			ev.Kind, ev.Agent, ev.Reason = AckEventExpired, m[1], "deadline "+m[2]
+		} else if m := ackReassignedRe.FindStringSubmatch(text); m != nil {
+			ev.Kind, ev.Agent, ev.To = AckEventReassigned, m[1], m[2]
+		} else if m := ackRequeuedRe.FindStringSubmatch(text); m != nil {
+			ev.Kind, ev.Reason = AckEventRequeued, m[1]
+		} else {
+			continue
+		}
+		events = append(events, ev)
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].At.Before(events[j].At) })
+	return events
+}
+
+// AwaitingAck reports whether issue is assigned but not yet accepted: a fresh
+// or deferred assignment that has not been escalated.
+func AwaitingAck(issue model.Issue) bool {
+	if issue.Assignee == "" || issue.Escalated || issue.Status.IsClosed() || issue.Status.IsTombstone() {
+		return false
+	}
+	return issue.AckStatus.IsPending() || issue.AckStatus == model.AckStatusDeferred
+}
+
+// IsOverdueAssignment reports whether issue is awaiting acknowledgment past
+// its deadline.
+func IsOverdueAssignment(issue model.Issue, now time.Time) bool {
+	return AwaitingAck(issue) && issue.Deadline != nil && now.After(*issue.Deadline)
+}
+
+// EscalationKind says why an issue is in the escalation queue.
+type EscalationKind string
+
+const (
+	// EscalationImpossible: an agent marked the issue impossible.
+	EscalationImpossible EscalationKind = "impossible"
+	// EscalationEscalated: escalated after too many bounces or a missed
+	// deadline.
+	EscalationEscalated EscalationKind = "escalated"
+	// EscalationOverdue: still awaiting acknowledgment past its deadline.
+	EscalationOverdue EscalationKind = "overdue"
+)
+
+func (k EscalationKind) rank() int {
+	switch k {
+	case EscalationImpossible:
+		return 0
+	case EscalationEscalated:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// AssigneeSuggestion is a candidate to take over an escalated issue.
+type AssigneeSuggestion struct {
+	Agent string `json:"agent"`
+	// Load is the number of open issues the agent holds and has not handed
+	// back; Capacity is from the roster (1 when unset).
+	Load          int      `json:"load"`
+	Capacity      int      `json:"capacity"`
+	MatchedSkills []string `json:"matched_skills,omitempty"`
+}
+
+// EscalationItem is one issue in the escalation queue.
+type EscalationItem struct {
+	ID          string          `json:"id"`
+	Title       string          `json:"title"`
+	Status      model.Status    `json:"status"`
+	Priority    int             `json:"priority"`
+	Labels      []string        `json:"labels,omitempty"`
+	Kind        EscalationKind  `json:"kind"`
+	Assignee    string          `json:"assignee,omitempty"`
+	AckStatus   model.AckStatus `json:"ack_status,omitempty"`
+	BounceCount int             `json:"bounce_count"`
+
+	Deadline     *time.Time `json:"deadline,omitempty"`
+	OverdueHours float64    `json:"overdue_hours,omitempty"`
+
+	// LastReason is the most recent decline, impossible or expiry reason.
+	LastReason string     `json:"last_reason,omitempty"`
+	History    []AckEvent `json:"history"`
+	// Suggested lists roster agents who have not already bounced the issue,
+	// least loaded first.
+	Suggested []AssigneeSuggestion `json:"suggested_assignees"`
+}
+
+// EscalationOptions tunes ComputeEscalations.
+type EscalationOptions struct {
+	Now time.Time
+	// Roster is who work can be reassigned to. When empty, everyone who has
+	// ever been assigned an issue is a candidate.
+	Roster []RosterAgent
+	// MaxSuggestions caps Suggested per item (default 3).
+	MaxSuggestions int
+}
+
+// EscalationReport is the queue of issues that need a lead's decision.
+type EscalationReport struct {
+	Items      []EscalationItem `json:"items"` // impossible, then escalated, then overdue; by priority within each
+	Impossible int              `json:"impossible"`
+	Escalated  int              `json:"escalated"`
+	Overdue    int              `json:"overdue"`
+}
+
+// Item returns the queue entry for id, if it is in the queue.
+func (r EscalationReport) Item(id string) (EscalationItem, bool) {
+	for _, it := range r.Items {
+		if it.ID == id {
+			return it, true
+		}
+	}
+	return EscalationItem{}, false
+}
+
+// ComputeEscalations collects issues marked impossible, escalated by bd-ack,
+// or awaiting acknowledgment past their deadline, with their bounce history
+// and suggested re-assignees.
+func ComputeEscalations(issues []model.Issue, opts EscalationOptions) EscalationReport {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	maxSuggestions := opts.MaxSuggestions
+	if maxSuggestions <= 0 {
+		maxSuggestions = 3
+	}
+	roster := opts.Roster
+	if len(roster) == 0 {
+		roster = assigneeRoster(issues)
+	}
+
+	load := make(map[string]int)
+	for _, issue := range issues {
+		if issue.Assignee != "" && !issue.Status.IsClosed() && !issue.Status.IsTombstone() && !ackReleased(issue.AckStatus) {
+			load[issue.Assignee]++
+		}
+	}
+
+	report := EscalationReport{Items: []EscalationItem{}}
+	for _, issue := range issues {
+		if issue.Status.IsClosed() || issue.Status.IsTombstone() {
+			continue
+		}
+		var kind EscalationKind
+		switch {
+		case issue.AckStatus.NeedsEscalation():
+			kind = EscalationImpossible
+			report.Impossible++
+		case issue.Escalated:
+			kind = EscalationEscalated
+			report.Escalated++
+		case IsOverdueAssignment(issue, now):
+			kind = EscalationOverdue
+			report.Overdue++
+		default:
+			continue
+		}
+
+		item := EscalationItem{
+			ID:          issue.ID,
+			Title:       issue.Title,
+			Status:      issue.Status,
+			Priority:    issue.Priority,
+			Labels:      issue.Labels,
+			Kind:        kind,
+			Assignee:    issue.Assignee,
+			AckStatus:   issue.AckStatus,
+			BounceCount: issue.BounceCount,
+			Deadline:    issue.Deadline,
+			History:     ParseAckHistory(issue),
+		}
+		if issue.Deadline != nil && now.After(*issue.Deadline) {
+			item.OverdueHours = now.Sub(*issue.Deadline).Hours()
+		}
+		if item.History == nil {
+			item.History = []AckEvent{}
+		}
+		for i := len(item.History) - 1; i >= 0; i-- {
+			if ev := item.History[i]; ev.Reason != "" && ev.Kind != AckEventRequeued {
+				item.LastReason = ev.Reason
+				break
+			}
+		}
+		item.Suggested = suggestAssignees(issue, item.History, roster, load, maxSuggestions)
+		report.Items = append(report.Items, item)
+	}
+
+	sort.Slice(report.Items, func(i, j int) bool {
+		a, b := report.Items[i], report.Items[j]
+		if a.Kind.rank() != b.Kind.rank() {
+			return a.Kind.rank() < b.Kind.rank()
+		}
+		if a.Priority != b.Priority {
+			return a.Priority < b.Priority
+		}
+		return a.ID < b.ID
+	})
+	return report
+}
+
+// assigneeRoster makes a roster of everyone who has been assigned an issue.
+func assigneeRoster(issues []model.Issue) []RosterAgent {
+	seen := make(map[string]bool)
+	var roster []RosterAgent
+	for _, issue := range issues {
+		if issue.Assignee != "" && !seen[issue.Assignee] {
+			seen[issue.Assignee] = true
+			roster = append(roster, RosterAgent{Name: issue.Assignee, Capacity: 1})
+		}
+	}
+	sort.Slice(roster, func(i, j int) bool { return roster[i].Name < roster[j].Name })
+	return roster
+}
+
+// suggestAssignees ranks roster agents who can take issue: not the current
+// assignee, not anyone who already declined, deferred, missed or gave up on
+// it, and holding a matching skill. Agents with the most spare capacity come
+// first.
+func suggestAssignees(issue model.Issue, history []AckEvent, roster []RosterAgent, load map[string]int, limit int) []AssigneeSuggestion {
+	excluded := map[string]bool{issue.Assignee: true}
+	for _, ev := range history {
+		switch ev.Kind {
+		case AckEventDeclined, AckEventDeferred, AckEventImpossible, AckEventExpired:
+			excluded[ev.Agent] = true
+		}
+	}
+
+	out := []AssigneeSuggestion{}
+	for _, agent := range roster {
+		if excluded[agent.Name] || !agentHasSkill(agent, issue.Labels) {
+			continue
+		}
+		s := AssigneeSuggestion{Agent: agent.Name, Load: load[agent.Name], Capacity: max(1, agent.Capacity)}
+		for _, skill := range agent.Skills {
+			if hasLabel(issue.Labels, skill) {
+				s.MatchedSkills = append(s.MatchedSkills, skill)
+			}
+		}
+		out = append(out, s)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		// Compare spare capacity as load/capacity without division.
+		li, lj := out[i].Load*out[j].Capacity, out[j].Load*out[i].Capacity
+		if li != lj {
+			return li < lj
+		}
+		if len(out[i].MatchedSkills) != len(out[j].MatchedSkills) {
+			return len(out[i].MatchedSkills) > len(out[j].MatchedSkills)
+		}
+		return out[i].Agent < out[j].Agent
+	})
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
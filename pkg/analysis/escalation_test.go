@@ -0,0 +1,88 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func TestParseAckHistory(t *testing.T) {
+	t0 := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+	comment := func(author, text string, h int) *model.Comment {
+		return &model.Comment{Author: author, Text: text, CreatedAt: t0.Add(time.Duration(h) * time.Hour)}
+	}
+	issue := model.Issue{ID: "x", Comments: []*model.Comment{
+		comment("bob", "[DEFERRED] Task deferred from bob to carol", 2),
+		comment("alice", "[DECLINED] Outside my expertise (bounce 1/3)", 1),
+		comment("alice", "Looks hard", 3),
+		comment("sweeper", "[EXPIRED] carol did not acknowledge by 2025-06-01T12:00:00Z (bounce 2/3)", 4),
+		comment("dave", "[IMPOSSIBLE] Needs prod access - Escalated for strategic decision", 5),
+	}}
+
+	got := ParseAckHistory(issue)
+	want := []AckEvent{
+		{Kind: AckEventDeclined, Agent: "alice", Reason: "Outside my expertise", At: t0.Add(time.Hour)},
+		{Kind: AckEventDeferred, Agent: "bob", To: "carol", At: t0.Add(2 * time.Hour)},
+		{Kind: AckEventExpired, Agent: "carol", Reason: "deadline 2025-06-01T12:00:00Z", At: t0.Add(4 * time.Hour)},
+		{Kind: AckEventImpossible, Agent: "dave", Reason: "Needs prod access", At: t0.Add(5 * time.Hour)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseAckHistory =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestComputeEscalations(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	past, future := now.Add(-3*time.Hour), now.Add(time.Hour)
+	issues := []model.Issue{
+		{ID: "imp", Title: "Impossible", Status: model.StatusOpen, Priority: 2, AckStatus: model.AckStatusImpossible, Escalated: true, Assignee: "alice"},
+		{ID: "esc", Title: "Bounced", Status: model.StatusOpen, Priority: 1, Escalated: true, BounceCount: 3, Labels: []string{"backend"}},
+		{ID: "late", Title: "Late", Status: model.StatusOpen, Priority: 0, Assignee: "bob", Deadline: &past},
+		{ID: "soon", Status: model.StatusOpen, Assignee: "bob", Deadline: &future},
+		{ID: "accepted", Status: model.StatusInProgress, Assignee: "carol", AckStatus: model.AckStatusAccepted, Deadline: &past},
+		{ID: "done", Status: model.StatusClosed, Escalated: true},
+	}
+	roster := []RosterAgent{
+		{Name: "alice", Capacity: 2},
+		{Name: "bob", Capacity: 2},
+		{Name: "carol", Capacity: 1},
+		{Name: "dev", Capacity: 1, Skills: []string{"frontend"}},
+	}
+	report := ComputeEscalations(issues, EscalationOptions{Now: now, Roster: roster})
+
+	var ids []string
+	for _, it := range report.Items {
+		ids = append(ids, it.ID)
+	}
+	if !reflect.DeepEqual(ids, []string{"imp", "esc", "late"}) {
+		t.Fatalf("queue = %v", ids)
+	}
+	if report.Impossible != 1 || report.Escalated != 1 || report.Overdue != 1 {
+		t.Errorf("counts = %d/%d/%d", report.Impossible, report.Escalated, report.Overdue)
+	}
+
+	late, _ := report.Item("late")
+	if late.Kind != EscalationOverdue || late.OverdueHours != 3 {
+		t.Errorf("late = %+v", late)
+	}
+	// bob holds the overdue issue and is excluded. alice gave "imp" back and
+	// dev has nothing, so both rank ahead of carol, who is at capacity. dev's
+	// skills do not matter for unlabeled work.
+	var names []string
+	for _, s := range late.Suggested {
+		names = append(names, s.Agent)
+	}
+	if !reflect.DeepEqual(names, []string{"alice", "dev", "carol"}) {
+		t.Errorf("late suggestions = %v", names)
+	}
+
+	// dev only takes frontend work.
+	esc, _ := report.Item("esc")
+	for _, s := range esc.Suggested {
+		if s.Agent == "dev" {
+			t.Errorf("dev should not be suggested for backend work: %+v", esc.Suggested)
+		}
+	}
+}
@@ -513,6 +513,13 @@ type Analyzer struct {
 	nodeToID map[int64]string
 	issueMap map[string]model.Issue
 	config   *AnalysisConfig // Optional custom config, nil means use size-based defaults
+
+	// dangling maps a missing dependency ID to the issues whose blocking
+	// dependencies point at it, so Update can link them if it appears.
+	dangling map[string][]string
+	// touched holds the IDs whose edges changed in the Update that produced
+	// this analyzer; nil for an analyzer built by NewAnalyzer.
+	touched map[string]bool
 }
 
 // SetConfig sets a custom analysis configuration.
@@ -527,6 +534,7 @@ func NewAnalyzer(issues []model.Issue) *Analyzer {
 	idToNode := make(map[string]int64, len(issues))
 	nodeToID := make(map[int64]string, len(issues))
 	issueMap := make(map[string]model.Issue, len(issues))
+	dangling := make(map[string][]string)
 
 	// 1. Add Nodes
 	for _, issue := range issues {
@@ -562,6 +570,8 @@ func NewAnalyzer(issues []model.Issue) *Analyzer {
 				// Issue (u) depends on v → edge u -> v
 				// Optimization: Use simple.Node directly to avoid internal map lookups in g.Node()
 				g.SetEdge(g.NewEdge(simple.Node(u), simple.Node(v)))
+			} else {
+				dangling[dep.DependsOnID] = append(dangling[dep.DependsOnID], issue.ID)
 			}
 		}
 	}
@@ -571,6 +581,7 @@ func NewAnalyzer(issues []model.Issue) *Analyzer {
 		idToNode: idToNode,
 		nodeToID: nodeToID,
 		issueMap: issueMap,
+		dangling: dangling,
 	}
 }
 
@@ -582,20 +593,26 @@ func NewAnalyzer(issues []model.Issue) *Analyzer {
 // If SetConfig was called, uses that config. Otherwise uses ConfigForSize() to
 // automatically select appropriate algorithms based on graph size.
 func (a *Analyzer) AnalyzeAsync(ctx context.Context) *GraphStats {
-	var config AnalysisConfig
+	return a.AnalyzeAsyncWithConfig(ctx, a.effectiveConfig())
+}
+
+// effectiveConfig returns the SetConfig configuration, or the size-based
+// default when none was set.
+func (a *Analyzer) effectiveConfig() AnalysisConfig {
 	if a.config != nil {
-		config = *a.config
-	} else {
-		nodeCount := len(a.issueMap)
-		edgeCount := a.g.Edges().Len()
-		config = ConfigForSize(nodeCount, edgeCount)
+		return *a.config
 	}
-	return a.AnalyzeAsyncWithConfig(ctx, config)
+	return ConfigForSize(len(a.issueMap), a.g.Edges().Len())
 }
 
 // AnalyzeAsyncWithConfig performs graph analysis with a custom configuration.
 // This allows callers to override the default size-based algorithm selection.
 func (a *Analyzer) AnalyzeAsyncWithConfig(ctx context.Context, config AnalysisConfig) *GraphStats {
+	return a.analyzeAsync(ctx, config, a.computePhase1)
+}
+
+// analyzeAsync runs phase1 synchronously and Phase 2 in the background.
+func (a *Analyzer) analyzeAsync(ctx context.Context, config AnalysisConfig, phase1 func(*GraphStats)) *GraphStats {
 	nodeCount := len(a.issueMap)
 	edgeCount := a.g.Edges().Len()
 
@@ -644,7 +661,7 @@ func (a *Analyzer) AnalyzeAsyncWithConfig(ctx context.Context, config AnalysisCo
 	}
 
 	// Phase 1: Fast metrics (degree centrality, topo sort, density)
-	a.computePhase1(stats)
+	phase1(stats)
 
 	// Phase 2: Expensive metrics in background goroutine
 	go a.computePhase2(ctx, stats, config)
@@ -1094,6 +1111,12 @@ func (a *Analyzer) computePhase1(stats *GraphStats) {
 		stats.OutDegree[id] = from.Len() // Issues I depend on
 	}
 
+	a.computeOrdering(stats)
+}
+
+// computeOrdering fills the Phase 1 fields derived from the whole graph
+// (topological order, density) and the degree ranks.
+func (a *Analyzer) computeOrdering(stats *GraphStats) {
 	// Topological Sort (execution order)
 	// Note: In our graph model, edge u -> v means u depends on v, so we reverse
 	// topo.Sort's output to get dependencies-first ordering.
@@ -0,0 +1,330 @@
+package analysis
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// Hierarchy is the parent/child forest described by parent-child
+// dependencies. A child's parent-child dependency points at its parent; when
+// a child names several parents, the lowest parent ID wins, and links that
+// would close a loop are ignored so the result is always a forest.
+type Hierarchy struct {
+	parent   map[string]string
+	children map[string][]string
+	roots    []string
+}
+
+// BuildHierarchy builds the parent/child forest of issues. Links to issues
+// outside the set and tombstoned issues are ignored.
+func BuildHierarchy(issues []model.Issue) *Hierarchy {
+	h := &Hierarchy{
+		parent:   make(map[string]string),
+		children: make(map[string][]string),
+	}
+
+	known := make(map[string]model.Issue, len(issues))
+	var ids []string
+	for _, issue := range issues {
+		if issue.Status.IsTombstone() {
+			continue
+		}
+		if _, dup := known[issue.ID]; dup {
+			continue
+		}
+		known[issue.ID] = issue
+		ids = append(ids, issue.ID)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		var parents []string
+		for _, dep := range known[id].Dependencies {
+			if dep == nil || dep.Type != model.DepParentChild || dep.DependsOnID == id {
+				continue
+			}
+			if _, ok := known[dep.DependsOnID]; ok {
+				parents = append(parents, dep.DependsOnID)
+			}
+		}
+		sort.Strings(parents)
+		for _, p := range parents {
+			if h.isAncestor(id, p) {
+				continue
+			}
+			h.parent[id] = p
+			h.children[p] = append(h.children[p], id)
+			break
+		}
+	}
+
+	// Roots are the top-level issues that take part in the hierarchy, plus
+	// epics that have no children yet.
+	for _, id := range ids {
+		if _, hasParent := h.parent[id]; hasParent {
+			continue
+		}
+		if len(h.children[id]) > 0 || known[id].IssueType == model.TypeEpic {
+			h.roots = append(h.roots, id)
+		}
+	}
+	return h
+}
+
+// isAncestor reports whether id is p or one of p's ancestors.
+func (h *Hierarchy) isAncestor(id, p string) bool {
+	for cur, ok := p, true; ok; cur, ok = h.parent[cur] {
+		if cur == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Roots returns the top-level issues of the forest, sorted by ID.
+func (h *Hierarchy) Roots() []string {
+	if h == nil {
+		return nil
+	}
+	return h.roots
+}
+
+// Parent returns the parent of id, if it has one.
+func (h *Hierarchy) Parent(id string) (string, bool) {
+	if h == nil {
+		return "", false
+	}
+	p, ok := h.parent[id]
+	return p, ok
+}
+
+// Children returns the direct children of id, sorted by ID.
+func (h *Hierarchy) Children(id string) []string {
+	if h == nil {
+		return nil
+	}
+	return h.children[id]
+}
+
+// Descendants returns every issue below id, depth first.
+func (h *Hierarchy) Descendants(id string) []string {
+	var out []string
+	for _, c := range h.Children(id) {
+		out = append(out, c)
+		out = append(out, h.Descendants(c)...)
+	}
+	return out
+}
+
+// Depth returns how many ancestors id has.
+func (h *Hierarchy) Depth(id string) int {
+	depth := 0
+	for p, ok := h.Parent(id); ok; p, ok = h.Parent(p) {
+		depth++
+	}
+	return depth
+}
+
+// EpicOptions tunes ComputeEpicRollups.
+type EpicOptions struct {
+	Now       time.Time
+	StaleDays int // open children not updated for this long are stale (default 14)
+}
+
+// EpicRollup summarises the progress of an epic, or of any issue with
+// children, over everything below it. Estimated minutes and percent done are
+// weighted over leaf descendants only, so a sub-epic is not counted twice;
+// unestimated leaves take the median estimate.
+type EpicRollup struct {
+	ID        string          `json:"id"`
+	Title     string          `json:"title"`
+	Status    model.Status    `json:"status"`
+	IssueType model.IssueType `json:"issue_type"`
+	Parent    string          `json:"parent,omitempty"`
+	Depth     int             `json:"depth"`
+	Children  []string        `json:"children"` // direct children
+
+	Descendants int `json:"descendants"`
+	Closed      int `json:"closed"`
+	InProgress  int `json:"in_progress"`
+	Open        int `json:"open"` // open, blocked or in progress
+
+	TotalMinutes     int     `json:"total_minutes"`
+	DoneMinutes      int     `json:"done_minutes"`
+	RemainingMinutes int     `json:"remaining_minutes"`
+	PercentDone      float64 `json:"percent_done"` // 0..100
+
+	BlockedChildren []string `json:"blocked_children,omitempty"` // open descendants waiting on open blockers
+	StaleChildren   []string `json:"stale_children,omitempty"`   // open descendants with no recent updates
+
+	ForecastDays float64    `json:"forecast_days"`
+	ForecastDate *time.Time `json:"forecast_date,omitempty"` // unset once nothing remains
+
+	// ReadyToClose flags an epic that is still open although all of its
+	// children are closed.
+	ReadyToClose bool `json:"ready_to_close"`
+}
+
+// EpicReport is the roll-up of every epic in the hierarchy.
+type EpicReport struct {
+	Epics                 []EpicRollup `json:"epics"` // tree order: parents before children
+	ReadyToClose          []string     `json:"ready_to_close"`
+	VelocityMinutesPerDay float64      `json:"velocity_minutes_per_day"`
+	StaleThresholdDays    int          `json:"stale_threshold_days"`
+	MedianMinutes         int          `json:"median_minutes"`
+}
+
+// Rollup returns the roll-up of id, if it is an epic in the report.
+func (r EpicReport) Rollup(id string) (EpicRollup, bool) {
+	for _, e := range r.Epics {
+		if e.ID == id {
+			return e, true
+		}
+	}
+	return EpicRollup{}, false
+}
+
+// ComputeEpicRollups builds the hierarchy and rolls up every issue that has
+// children or is of type epic. Forecasts divide the remaining minutes by the
+// project's recent closure velocity, as ETA forecasting does.
+func ComputeEpicRollups(issues []model.Issue, opts EpicOptions) EpicReport {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	staleDays := opts.StaleDays
+	if staleDays <= 0 {
+		staleDays = DefaultStaleThresholdDays
+	}
+
+	median := computeMedianEstimatedMinutes(issues)
+	velocity, _ := velocityMinutesPerDayForLabel(issues, "", now.Add(-30*24*time.Hour), median)
+	if velocity <= 0 {
+		// Same conservative default as EstimateETAForIssue.
+		velocity = float64(median) / 5.0
+		if velocity <= 0 {
+			velocity = 60
+		}
+	}
+
+	report := EpicReport{
+		Epics:                 []EpicRollup{},
+		ReadyToClose:          []string{},
+		VelocityMinutesPerDay: velocity,
+		StaleThresholdDays:    staleDays,
+		MedianMinutes:         median,
+	}
+
+	issueMap := make(map[string]model.Issue, len(issues))
+	for _, issue := range issues {
+		if _, dup := issueMap[issue.ID]; !dup {
+			issueMap[issue.ID] = issue
+		}
+	}
+	h := BuildHierarchy(issues)
+	staleBefore := now.Add(-time.Duration(staleDays) * 24 * time.Hour)
+
+	var walk func(id string)
+	walk = func(id string) {
+		issue := issueMap[id]
+		children := h.Children(id)
+		if len(children) > 0 || issue.IssueType == model.TypeEpic {
+			r := rollupEpic(issue, h, issueMap, median, staleBefore)
+			if r.RemainingMinutes > 0 {
+				r.ForecastDays = float64(r.RemainingMinutes) / velocity
+				date := now.Add(durationDays(r.ForecastDays))
+				r.ForecastDate = &date
+			}
+			report.Epics = append(report.Epics, r)
+			if r.ReadyToClose {
+				report.ReadyToClose = append(report.ReadyToClose, id)
+			}
+		}
+		for _, c := range children {
+			walk(c)
+		}
+	}
+	for _, root := range h.Roots() {
+		walk(root)
+	}
+	return report
+}
+
+func rollupEpic(epic model.Issue, h *Hierarchy, issueMap map[string]model.Issue, median int, staleBefore time.Time) EpicRollup {
+	r := EpicRollup{
+		ID:        epic.ID,
+		Title:     epic.Title,
+		Status:    epic.Status,
+		IssueType: epic.IssueType,
+		Depth:     h.Depth(epic.ID),
+		Children:  append([]string{}, h.Children(epic.ID)...),
+	}
+	r.Parent, _ = h.Parent(epic.ID)
+
+	for _, id := range h.Descendants(epic.ID) {
+		child := issueMap[id]
+		r.Descendants++
+		switch {
+		case child.Status.IsClosed():
+			r.Closed++
+		case child.Status == model.StatusInProgress:
+			r.InProgress++
+			r.Open++
+		default:
+			r.Open++
+		}
+
+		if len(h.Children(id)) == 0 {
+			minutes := estimateOrMedian(child, median)
+			r.TotalMinutes += minutes
+			if child.Status.IsClosed() {
+				r.DoneMinutes += minutes
+			}
+		}
+
+		if child.Status.IsClosed() {
+			continue
+		}
+		if hasOpenBlocker(child, issueMap) {
+			r.BlockedChildren = append(r.BlockedChildren, id)
+		}
+		if child.UpdatedAt.Before(staleBefore) {
+			r.StaleChildren = append(r.StaleChildren, id)
+		}
+	}
+
+	r.RemainingMinutes = r.TotalMinutes - r.DoneMinutes
+	switch {
+	case r.TotalMinutes > 0:
+		r.PercentDone = float64(r.DoneMinutes) * 100 / float64(r.TotalMinutes)
+	case epic.Status.IsClosed():
+		r.PercentDone = 100
+	}
+
+	if !epic.Status.IsClosed() && len(r.Children) > 0 {
+		r.ReadyToClose = true
+		for _, c := range r.Children {
+			if !issueMap[c].Status.IsClosed() {
+				r.ReadyToClose = false
+				break
+			}
+		}
+	}
+	return r
+}
+
+// hasOpenBlocker reports whether issue has a blocking dependency on an issue
+// that is still open.
+func hasOpenBlocker(issue model.Issue, issueMap map[string]model.Issue) bool {
+	for _, dep := range issue.Dependencies {
+		if dep == nil || !dep.Type.IsBlocking() {
+			continue
+		}
+		if blocker, ok := issueMap[dep.DependsOnID]; ok && !blocker.Status.IsClosed() && !blocker.Status.IsTombstone() {
+			return true
+		}
+	}
+	return false
+}
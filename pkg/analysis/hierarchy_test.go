@@ -0,0 +1,110 @@
+package analysis
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func childOf(id string, parents ...string) []*model.Dependency {
+	var deps []*model.Dependency
+	for _, p := range parents {
+		deps = append(deps, &model.Dependency{IssueID: id, DependsOnID: p, Type: model.DepParentChild})
+	}
+	return deps
+}
+
+func TestBuildHierarchy_ForestAndLoops(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "E", IssueType: model.TypeEpic, Status: model.StatusOpen},
+		{ID: "S", IssueType: model.TypeFeature, Status: model.StatusOpen, Dependencies: childOf("S", "E")},
+		{ID: "t1", Status: model.StatusOpen, Dependencies: childOf("t1", "S")},
+		{ID: "t2", Status: model.StatusOpen, Dependencies: childOf("t2", "S", "E")}, // lowest parent wins
+		{ID: "lone", Status: model.StatusOpen},
+		{ID: "empty", IssueType: model.TypeEpic, Status: model.StatusOpen},
+		// x and y name each other as parent; only one link survives.
+		{ID: "x", Status: model.StatusOpen, Dependencies: childOf("x", "y")},
+		{ID: "y", Status: model.StatusOpen, Dependencies: childOf("y", "x")},
+	}
+	h := BuildHierarchy(issues)
+
+	if got := h.Roots(); !reflect.DeepEqual(got, []string{"E", "empty", "y"}) {
+		t.Errorf("Roots = %v", got)
+	}
+	if got := h.Children("E"); !reflect.DeepEqual(got, []string{"S", "t2"}) {
+		t.Errorf("Children(E) = %v", got)
+	}
+	if got := h.Descendants("E"); !reflect.DeepEqual(got, []string{"S", "t1", "t2"}) {
+		t.Errorf("Descendants(E) = %v", got)
+	}
+	if p, _ := h.Parent("t1"); p != "S" || h.Depth("t1") != 2 {
+		t.Errorf("t1 parent=%q depth=%d", p, h.Depth("t1"))
+	}
+}
+
+func TestComputeEpicRollups(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	fresh, old := now.Add(-time.Hour), now.Add(-30*24*time.Hour)
+	closedAt := now.Add(-2 * 24 * time.Hour)
+	issues := []model.Issue{
+		{ID: "E", Title: "Epic", IssueType: model.TypeEpic, Status: model.StatusOpen, UpdatedAt: fresh},
+		{ID: "a", Status: model.StatusClosed, EstimatedMinutes: minutes(300), UpdatedAt: fresh, ClosedAt: &closedAt, Dependencies: childOf("a", "E")},
+		{ID: "b", Status: model.StatusInProgress, EstimatedMinutes: minutes(100), UpdatedAt: fresh, Dependencies: childOf("b", "E")},
+		{ID: "c", Status: model.StatusOpen, EstimatedMinutes: minutes(100), UpdatedAt: old,
+			Dependencies: append(childOf("c", "E"), blockedBy("c", "b")...)},
+		{ID: "D", Title: "Done epic", IssueType: model.TypeEpic, Status: model.StatusOpen, UpdatedAt: fresh},
+		{ID: "d1", Status: model.StatusClosed, EstimatedMinutes: minutes(60), UpdatedAt: fresh, ClosedAt: &closedAt, Dependencies: childOf("d1", "D")},
+	}
+	report := ComputeEpicRollups(issues, EpicOptions{Now: now})
+
+	if len(report.Epics) != 2 || report.Epics[0].ID != "D" || report.Epics[1].ID != "E" {
+		t.Fatalf("epics = %+v", report.Epics)
+	}
+	e, _ := report.Rollup("E")
+	if e.Descendants != 3 || e.Closed != 1 || e.Open != 2 || e.InProgress != 1 {
+		t.Errorf("E counts = %+v", e)
+	}
+	if e.TotalMinutes != 500 || e.DoneMinutes != 300 || e.RemainingMinutes != 200 || math.Abs(e.PercentDone-60) > 1e-9 {
+		t.Errorf("E minutes = %d/%d (%.1f%%)", e.DoneMinutes, e.TotalMinutes, e.PercentDone)
+	}
+	if !reflect.DeepEqual(e.BlockedChildren, []string{"c"}) || !reflect.DeepEqual(e.StaleChildren, []string{"c"}) {
+		t.Errorf("E blocked=%v stale=%v", e.BlockedChildren, e.StaleChildren)
+	}
+	// Velocity: 360 minutes closed in the last 30 days.
+	if report.VelocityMinutesPerDay != 12 || e.ForecastDate == nil || math.Abs(e.ForecastDays-200.0/12) > 1e-9 {
+		t.Errorf("forecast = %.2f days at %.1f min/day", e.ForecastDays, report.VelocityMinutesPerDay)
+	}
+	if e.ReadyToClose {
+		t.Error("E has open children and should not be ready to close")
+	}
+
+	d, _ := report.Rollup("D")
+	if !d.ReadyToClose || d.PercentDone != 100 || d.ForecastDate != nil {
+		t.Errorf("D = %+v", d)
+	}
+	if !reflect.DeepEqual(report.ReadyToClose, []string{"D"}) {
+		t.Errorf("ReadyToClose = %v", report.ReadyToClose)
+	}
+}
+
+func TestComputeEpicRollups_NestedWeightsLeavesOnly(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "E", IssueType: model.TypeEpic, Status: model.StatusOpen},
+		{ID: "F", IssueType: model.TypeFeature, Status: model.StatusClosed, EstimatedMinutes: minutes(9999), Dependencies: childOf("F", "E")},
+		{ID: "f1", Status: model.StatusClosed, EstimatedMinutes: minutes(30), Dependencies: childOf("f1", "F")},
+		{ID: "g", Status: model.StatusOpen, EstimatedMinutes: minutes(90), Dependencies: childOf("g", "E")},
+	}
+	report := ComputeEpicRollups(issues, EpicOptions{Now: time.Now()})
+
+	e, _ := report.Rollup("E")
+	if e.TotalMinutes != 120 || e.PercentDone != 25 {
+		t.Errorf("E = %d minutes, %.1f%% done", e.TotalMinutes, e.PercentDone)
+	}
+	f, ok := report.Rollup("F")
+	if !ok || f.Parent != "E" || f.Depth != 1 || f.ReadyToClose {
+		t.Errorf("F = %+v", f)
+	}
+}
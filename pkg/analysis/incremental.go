@@ -0,0 +1,220 @@
+package analysis
+
+import (
+	"context"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// Update returns an analyzer for issues, a later load of the issues a was
+// built from. changed lists every ID that was added, removed or modified; all
+// other issues must be unchanged. Instead of rebuilding, the graph is copied
+// and only the changed issues' nodes and edges are patched. a itself is left
+// intact, since a Phase 2 started from it may still be reading its graph.
+func (a *Analyzer) Update(issues []model.Issue, changed []string) *Analyzer {
+	next := &Analyzer{
+		g:        simple.NewDirectedGraph(),
+		idToNode: make(map[string]int64, len(issues)),
+		nodeToID: make(map[int64]string, len(issues)),
+		issueMap: make(map[string]model.Issue, len(issues)),
+		config:   a.config,
+		dangling: make(map[string][]string, len(a.dangling)),
+		touched:  make(map[string]bool, len(changed)),
+	}
+	graph.Copy(next.g, a.g)
+	for id, n := range a.idToNode {
+		next.idToNode[id] = n
+		next.nodeToID[n] = id
+	}
+	for _, issue := range issues {
+		next.issueMap[issue.ID] = issue
+	}
+
+	isChanged := make(map[string]bool, len(changed))
+	for _, id := range changed {
+		isChanged[id] = true
+	}
+	// Changed issues re-register their own dangling references below.
+	for target, sources := range a.dangling {
+		for _, src := range sources {
+			if !isChanged[src] {
+				next.dangling[target] = append(next.dangling[target], src)
+			}
+		}
+	}
+
+	// Every neighbour of a changed issue may see its degree or readiness change.
+	for _, id := range changed {
+		next.touched[id] = true
+		if u, ok := a.idToNode[id]; ok {
+			for _, n := range graph.NodesOf(a.g.To(u)) {
+				next.touched[a.nodeToID[n.ID()]] = true
+			}
+			for _, n := range graph.NodesOf(a.g.From(u)) {
+				next.touched[a.nodeToID[n.ID()]] = true
+			}
+		}
+	}
+
+	// Drop removed issues. Unchanged issues that depended on them now point
+	// at a missing issue.
+	for _, id := range changed {
+		if _, ok := next.issueMap[id]; ok {
+			continue
+		}
+		u, ok := next.idToNode[id]
+		if !ok {
+			continue
+		}
+		for _, n := range graph.NodesOf(next.g.To(u)) {
+			if src := next.nodeToID[n.ID()]; !isChanged[src] {
+				next.dangling[id] = append(next.dangling[id], src)
+			}
+		}
+		next.g.RemoveNode(u)
+		delete(next.idToNode, id)
+		delete(next.nodeToID, u)
+	}
+
+	// Add new issues, linking unchanged issues that were waiting on them.
+	for _, id := range changed {
+		if _, ok := next.issueMap[id]; !ok {
+			continue
+		}
+		if _, ok := next.idToNode[id]; ok {
+			continue
+		}
+		n := next.g.NewNode()
+		next.g.AddNode(n)
+		next.idToNode[id] = n.ID()
+		next.nodeToID[n.ID()] = id
+		for _, src := range next.dangling[id] {
+			next.g.SetEdge(next.g.NewEdge(simple.Node(next.idToNode[src]), n))
+			next.touched[src] = true
+		}
+		delete(next.dangling, id)
+	}
+
+	// Re-link the outgoing edges of added and modified issues.
+	for _, id := range changed {
+		issue, ok := next.issueMap[id]
+		if !ok {
+			continue
+		}
+		u := next.idToNode[id]
+		for _, v := range graph.NodesOf(next.g.From(u)) {
+			next.g.RemoveEdge(u, v.ID())
+		}
+		for _, dep := range issue.Dependencies {
+			if dep == nil || !dep.Type.IsBlocking() {
+				continue
+			}
+			if v, exists := next.idToNode[dep.DependsOnID]; exists {
+				next.g.SetEdge(next.g.NewEdge(simple.Node(u), simple.Node(v)))
+				next.touched[dep.DependsOnID] = true
+			} else {
+				next.dangling[dep.DependsOnID] = append(next.dangling[dep.DependsOnID], id)
+			}
+		}
+	}
+
+	return next
+}
+
+// AnalyzeIncremental is AnalyzeAsync for an analyzer produced by Update.
+// Phase 1 starts from prev, the stats of the analyzer Update was called on,
+// and recomputes degrees only for the issues the update touched; topological
+// order, density and ranks are rederived. Phase 2 runs in the background as
+// usual. Without prev, or for an analyzer not produced by Update, it is
+// equivalent to AnalyzeAsync.
+func (a *Analyzer) AnalyzeIncremental(ctx context.Context, prev *GraphStats) *GraphStats {
+	if prev == nil || a.touched == nil {
+		return a.AnalyzeAsync(ctx)
+	}
+	return a.analyzeAsync(ctx, a.effectiveConfig(), func(stats *GraphStats) {
+		for id, d := range prev.InDegree {
+			stats.InDegree[id] = d
+		}
+		for id, d := range prev.OutDegree {
+			stats.OutDegree[id] = d
+		}
+		for id := range a.touched {
+			u, ok := a.idToNode[id]
+			if !ok {
+				delete(stats.InDegree, id)
+				delete(stats.OutDegree, id)
+				continue
+			}
+			stats.InDegree[id] = a.g.To(u).Len()
+			stats.OutDegree[id] = a.g.From(u).Len()
+		}
+		a.computeOrdering(stats)
+	})
+}
+
+// Readiness records how many open blocking dependencies each non-closed issue
+// has. Issues with none are actionable, matching GetActionableIssues. After
+// Update, Readiness.Update patches it in time proportional to the change.
+type Readiness struct {
+	openBlockers map[string]int
+}
+
+// Readiness computes the open-blocker counts for every issue.
+func (a *Analyzer) Readiness() *Readiness {
+	r := &Readiness{openBlockers: make(map[string]int, len(a.issueMap))}
+	for id := range a.issueMap {
+		r.refresh(a, id)
+	}
+	return r
+}
+
+// Update returns the readiness for a, an analyzer produced by Update from the
+// analyzer r was computed on. Only issues the update touched are recounted.
+// For an analyzer not produced by Update it recomputes from scratch.
+func (r *Readiness) Update(a *Analyzer) *Readiness {
+	if r == nil || a.touched == nil {
+		return a.Readiness()
+	}
+	next := &Readiness{openBlockers: make(map[string]int, len(r.openBlockers))}
+	for id, n := range r.openBlockers {
+		next.openBlockers[id] = n
+	}
+	for id := range a.touched {
+		next.refresh(a, id)
+	}
+	return next
+}
+
+func (r *Readiness) refresh(a *Analyzer, id string) {
+	issue, ok := a.issueMap[id]
+	if !ok || issue.Status == model.StatusClosed {
+		delete(r.openBlockers, id)
+		return
+	}
+	r.openBlockers[id] = len(a.GetOpenBlockers(id))
+}
+
+// IsActionable reports whether id is a non-closed issue with no open blockers.
+func (r *Readiness) IsActionable(id string) bool {
+	n, ok := r.openBlockers[id]
+	return ok && n == 0
+}
+
+// OpenBlockers returns the number of open blocking dependencies of id.
+func (r *Readiness) OpenBlockers(id string) int {
+	return r.openBlockers[id]
+}
+
+// ActionableCount returns the number of actionable issues.
+func (r *Readiness) ActionableCount() int {
+	count := 0
+	for _, n := range r.openBlockers {
+		if n == 0 {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,165 @@
+package analysis_test
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func blocks(id, on string) *model.Dependency {
+	return &model.Dependency{IssueID: id, DependsOnID: on, Type: model.DepBlocks}
+}
+
+// assertMatchesFresh checks that an incrementally updated analyzer and its
+// stats agree with ones built from scratch.
+func assertMatchesFresh(t *testing.T, step int, issues []model.Issue, an *analysis.Analyzer, stats *analysis.GraphStats, ready *analysis.Readiness) {
+	t.Helper()
+	fresh := analysis.NewAnalyzer(issues)
+	want := fresh.AnalyzeAsync(context.Background())
+
+	if stats.NodeCount != want.NodeCount || stats.EdgeCount != want.EdgeCount {
+		t.Fatalf("step %d: nodes/edges = %d/%d, want %d/%d", step, stats.NodeCount, stats.EdgeCount, want.NodeCount, want.EdgeCount)
+	}
+	if !reflect.DeepEqual(stats.InDegree, want.InDegree) {
+		t.Fatalf("step %d: InDegree = %v, want %v", step, stats.InDegree, want.InDegree)
+	}
+	if !reflect.DeepEqual(stats.OutDegree, want.OutDegree) {
+		t.Fatalf("step %d: OutDegree = %v, want %v", step, stats.OutDegree, want.OutDegree)
+	}
+	if len(stats.TopologicalOrder) != len(want.TopologicalOrder) {
+		t.Fatalf("step %d: topological order has %d entries, want %d", step, len(stats.TopologicalOrder), len(want.TopologicalOrder))
+	}
+	if got, exp := getIDs(an.GetActionableIssues()), getIDs(fresh.GetActionableIssues()); !reflect.DeepEqual(got, exp) {
+		t.Fatalf("step %d: actionable = %v, want %v", step, got, exp)
+	}
+
+	freshReady := fresh.Readiness()
+	for _, issue := range issues {
+		if ready.IsActionable(issue.ID) != freshReady.IsActionable(issue.ID) ||
+			ready.OpenBlockers(issue.ID) != freshReady.OpenBlockers(issue.ID) {
+			t.Fatalf("step %d: readiness of %s = (%v, %d), want (%v, %d)", step, issue.ID,
+				ready.IsActionable(issue.ID), ready.OpenBlockers(issue.ID),
+				freshReady.IsActionable(issue.ID), freshReady.OpenBlockers(issue.ID))
+		}
+	}
+	if ready.ActionableCount() != len(fresh.GetActionableIssues()) {
+		t.Fatalf("step %d: ActionableCount = %d, want %d", step, ready.ActionableCount(), len(fresh.GetActionableIssues()))
+	}
+}
+
+func TestUpdateHandlesAddRemoveAndRelink(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "A", Title: "A", Status: model.StatusOpen, Dependencies: []*model.Dependency{blocks("A", "B"), blocks("A", "X")}},
+		{ID: "B", Title: "B", Status: model.StatusOpen},
+		{ID: "C", Title: "C", Status: model.StatusOpen, Dependencies: []*model.Dependency{blocks("C", "B")}},
+	}
+	an := analysis.NewAnalyzer(issues)
+	stats := an.AnalyzeAsync(context.Background())
+	ready := an.Readiness()
+
+	// X appears (A was already waiting on it), B is removed and C closes.
+	next := []model.Issue{
+		issues[0],
+		{ID: "C", Title: "C", Status: model.StatusClosed, Dependencies: []*model.Dependency{blocks("C", "B")}},
+		{ID: "X", Title: "X", Status: model.StatusOpen},
+	}
+	an = an.Update(next, []string{"B", "C", "X"})
+	stats = an.AnalyzeIncremental(context.Background(), stats)
+	ready = ready.Update(an)
+	assertMatchesFresh(t, 0, next, an, stats, ready)
+
+	if stats.OutDegree["A"] != 1 || stats.InDegree["X"] != 1 {
+		t.Errorf("expected A → X to be linked, got out(A)=%d in(X)=%d", stats.OutDegree["A"], stats.InDegree["X"])
+	}
+	if ready.IsActionable("A") || !ready.IsActionable("X") || ready.IsActionable("C") {
+		t.Errorf("unexpected readiness: A=%v X=%v C=%v", ready.IsActionable("A"), ready.IsActionable("X"), ready.IsActionable("C"))
+	}
+}
+
+func TestUpdateMatchesRebuildOnRandomEdits(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	statuses := []model.Status{model.StatusOpen, model.StatusInProgress, model.StatusBlocked, model.StatusClosed}
+	depTypes := []model.DependencyType{model.DepBlocks, model.DepBlocks, model.DepParentChild, model.DepRelated}
+
+	randomIssue := func(id string, pool int) model.Issue {
+		issue := model.Issue{ID: id, Title: id, Status: statuses[rng.Intn(len(statuses))], IssueType: model.TypeTask}
+		for d := rng.Intn(3); d > 0; d-- {
+			// Targets may not exist yet, exercising dangling references.
+			target := fmt.Sprintf("I%d", rng.Intn(pool+5))
+			if target == id {
+				continue
+			}
+			issue.Dependencies = append(issue.Dependencies, &model.Dependency{
+				IssueID: id, DependsOnID: target, Type: depTypes[rng.Intn(len(depTypes))],
+			})
+		}
+		return issue
+	}
+
+	const pool = 40
+	byID := make(map[string]model.Issue)
+	for i := 0; i < pool; i++ {
+		id := fmt.Sprintf("I%d", i)
+		byID[id] = randomIssue(id, pool)
+	}
+	snapshot := func() []model.Issue {
+		out := make([]model.Issue, 0, len(byID))
+		for _, issue := range byID {
+			out = append(out, issue)
+		}
+		sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+		return out
+	}
+
+	issues := snapshot()
+	an := analysis.NewAnalyzer(issues)
+	stats := an.AnalyzeAsync(context.Background())
+	ready := an.Readiness()
+
+	for step := 0; step < 60; step++ {
+		changed := map[string]bool{}
+		for n := 1 + rng.Intn(4); n > 0; n-- {
+			id := fmt.Sprintf("I%d", rng.Intn(pool+5))
+			changed[id] = true
+			if _, ok := byID[id]; ok && rng.Intn(4) == 0 {
+				delete(byID, id)
+			} else {
+				byID[id] = randomIssue(id, pool)
+			}
+		}
+		ids := make([]string, 0, len(changed))
+		for id := range changed {
+			ids = append(ids, id)
+		}
+
+		issues = snapshot()
+		an = an.Update(issues, ids)
+		stats = an.AnalyzeIncremental(context.Background(), stats)
+		ready = ready.Update(an)
+		assertMatchesFresh(t, step, issues, an, stats, ready)
+	}
+	stats.WaitForPhase2()
+}
+
+func TestAnalyzeIncrementalWithoutUpdateFallsBack(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "A", Title: "A", Status: model.StatusOpen, Dependencies: []*model.Dependency{blocks("A", "B")}},
+		{ID: "B", Title: "B", Status: model.StatusOpen},
+	}
+	an := analysis.NewAnalyzer(issues)
+	prev := an.AnalyzeAsync(context.Background())
+	stats := an.AnalyzeIncremental(context.Background(), prev)
+	stats.WaitForPhase2()
+	if stats == prev || stats.OutDegree["A"] != 1 {
+		t.Fatalf("expected a fresh analysis, got %+v", stats)
+	}
+	if got := (*analysis.Readiness)(nil).Update(an); !got.IsActionable("B") || got.IsActionable("A") {
+		t.Fatalf("nil Readiness.Update should recompute")
+	}
+}
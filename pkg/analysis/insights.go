@@ -25,6 +25,8 @@ type Insights struct {
 	Cycles         [][]string
 	ClusterDensity float64
 	Velocity       *VelocitySnapshot
+	// Estimate-weighted critical chain; set by callers that have the issues
+	WeightedCriticalPath *WeightedCriticalPath
 
 	// Full stats for calculation explanations
 	Stats *GraphStats
@@ -97,6 +99,55 @@ func (s *GraphStats) GenerateInsights(limit int) Insights {
 	}
 }
 
+// Restrict keeps the entries include accepts, at most limit per list
+// (limit <= 0 = no cap). A cycle is kept when any of its members is
+// accepted. Generate the insights uncapped first so restricting doesn't
+// leave the lists short.
+func (in *Insights) Restrict(include func(id string) bool, limit int) {
+	items := func(list []InsightItem) []InsightItem {
+		kept := make([]InsightItem, 0, len(list))
+		for _, item := range list {
+			if include(item.ID) {
+				kept = append(kept, item)
+			}
+		}
+		if limit > 0 && len(kept) > limit {
+			kept = kept[:limit]
+		}
+		return kept
+	}
+	ids := func(list []string) []string {
+		var kept []string
+		for _, id := range list {
+			if include(id) {
+				kept = append(kept, id)
+			}
+		}
+		return limitStrings(kept, limit)
+	}
+
+	in.Bottlenecks = items(in.Bottlenecks)
+	in.Keystones = items(in.Keystones)
+	in.Influencers = items(in.Influencers)
+	in.Hubs = items(in.Hubs)
+	in.Authorities = items(in.Authorities)
+	in.Cores = items(in.Cores)
+	in.Slack = items(in.Slack)
+	in.Articulation = ids(in.Articulation)
+	in.Orphans = ids(in.Orphans)
+
+	var cycles [][]string
+	for _, cycle := range in.Cycles {
+		for _, id := range cycle {
+			if include(id) {
+				cycles = append(cycles, cycle)
+				break
+			}
+		}
+	}
+	in.Cycles = cycles
+}
+
 func getTopItems(m map[string]float64, limit int) []InsightItem {
 	type kv struct {
 		Key   string
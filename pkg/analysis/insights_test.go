@@ -418,3 +418,22 @@ func TestInsights_EmptyFields(t *testing.T) {
 		t.Error("Expected nil Stats")
 	}
 }
+
+func TestInsightsRestrict(t *testing.T) {
+	in := Insights{
+		Bottlenecks:  []InsightItem{{ID: "A", Value: 3}, {ID: "B", Value: 2}, {ID: "C", Value: 1}},
+		Articulation: []string{"A", "B"},
+		Cycles:       [][]string{{"A", "B"}, {"B", "C"}},
+	}
+	in.Restrict(func(id string) bool { return id != "A" }, 1)
+
+	if len(in.Bottlenecks) != 1 || in.Bottlenecks[0].ID != "B" {
+		t.Errorf("Bottlenecks = %+v, want [B]", in.Bottlenecks)
+	}
+	if len(in.Articulation) != 1 || in.Articulation[0] != "B" {
+		t.Errorf("Articulation = %v, want [B]", in.Articulation)
+	}
+	if len(in.Cycles) != 2 {
+		t.Errorf("cycles with any kept member should stay: %v", in.Cycles)
+	}
+}
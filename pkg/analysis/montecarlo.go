@@ -0,0 +1,581 @@
+package analysis
+
+import (
+	"container/heap"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/correlation"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// Monte Carlo defaults.
+const (
+	DefaultMonteCarloTrials = 1000
+	// MaxMonteCarloTrials caps the trials a caller may request; memory grows
+	// with trials × issues.
+	MaxMonteCarloTrials  = 10000
+	DefaultHistogramBins = 10
+	// minLabelSamples is the number of closed issues a label needs before its
+	// own cycle times are sampled instead of the project-wide ones.
+	minLabelSamples = 5
+	// estimateWorkdayMinutes converts estimates to days when there is no
+	// history to sample.
+	estimateWorkdayMinutes = 8 * 60
+)
+
+// MonteCarloConfig controls ForecastMonteCarlo.
+type MonteCarloConfig struct {
+	Trials int   // Number of simulated runs (default DefaultMonteCarloTrials)
+	Agents int   // Issues worked in parallel (default 1)
+	Seed   int64 // Random seed; equal seeds give equal forecasts
+	Now    time.Time
+
+	// Focus, when set, limits the simulation to these issues and their
+	// transitive open blockers, as if agents worked on nothing else.
+	Focus []string
+	// Sprints are reported as groups when they have open issues in the
+	// simulation.
+	Sprints []model.Sprint
+	// HistogramBins sets the resolution of group histograms
+	// (default DefaultHistogramBins).
+	HistogramBins int
+}
+
+// ForecastQuantiles are completion dates at three confidence levels.
+type ForecastQuantiles struct {
+	P50     time.Time `json:"p50"`
+	P80     time.Time `json:"p80"`
+	P95     time.Time `json:"p95"`
+	P50Days float64   `json:"p50_days"`
+	P80Days float64   `json:"p80_days"`
+	P95Days float64   `json:"p95_days"`
+}
+
+// IssueForecast is the simulated completion of one issue.
+type IssueForecast struct {
+	IssueID string `json:"issue_id"`
+	ForecastQuantiles
+}
+
+// HistogramBin counts trials whose completion fell in [FromDays, ToDays).
+type HistogramBin struct {
+	FromDays float64 `json:"from_days"`
+	ToDays   float64 `json:"to_days"`
+	Count    int     `json:"count"`
+}
+
+// GroupForecast is the simulated completion of every open issue in a group
+// (a label, a sprint or the whole project).
+type GroupForecast struct {
+	Name       string `json:"name"`
+	OpenIssues int    `json:"open_issues"`
+	ForecastQuantiles
+	Histogram []HistogramBin `json:"histogram,omitempty"`
+}
+
+// MonteCarloForecast is the result of ForecastMonteCarlo.
+type MonteCarloForecast struct {
+	Trials           int    `json:"trials"`
+	Agents           int    `json:"agents"`
+	Seed             int64  `json:"seed"`
+	CycleTimeSamples int    `json:"cycle_time_samples"`
+	SampleSource     string `json:"sample_source"` // "history" or "estimates"
+
+	Project GroupForecast   `json:"project"`
+	Issues  []IssueForecast `json:"issues"`
+	Labels  []GroupForecast `json:"labels,omitempty"`
+	Sprints []GroupForecast `json:"sprints,omitempty"`
+
+	// Unschedulable lists open issues that can never start because they are
+	// in, or wait on, a dependency cycle.
+	Unschedulable []string `json:"unschedulable,omitempty"`
+}
+
+// Issue returns the forecast for id, if it was simulated.
+func (f *MonteCarloForecast) Issue(id string) (IssueForecast, bool) {
+	for _, fc := range f.Issues {
+		if fc.IssueID == id {
+			return fc, true
+		}
+	}
+	return IssueForecast{}, false
+}
+
+// CycleTimeSamples returns the create-to-close durations of closed issues, as
+// computed by correlation.CalculateCycleTime from CreatedAt and ClosedAt.
+func CycleTimeSamples(issues []model.Issue) []time.Duration {
+	var samples []time.Duration
+	for _, issue := range issues {
+		if d, ok := issueCycleTime(issue); ok {
+			samples = append(samples, d)
+		}
+	}
+	return samples
+}
+
+func issueCycleTime(issue model.Issue) (time.Duration, bool) {
+	if issue.Status != model.StatusClosed || issue.ClosedAt == nil || issue.CreatedAt.IsZero() {
+		return 0, false
+	}
+	ct := correlation.CalculateCycleTime(correlation.BeadMilestones{
+		Created: &correlation.BeadEvent{BeadID: issue.ID, EventType: correlation.EventCreated, Timestamp: issue.CreatedAt},
+		Closed:  &correlation.BeadEvent{BeadID: issue.ID, EventType: correlation.EventClosed, Timestamp: *issue.ClosedAt},
+	})
+	if ct == nil || ct.CreateToClose == nil || *ct.CreateToClose <= 0 {
+		return 0, false
+	}
+	return *ct.CreateToClose, true
+}
+
+// mcNode is one open issue in the simulation.
+type mcNode struct {
+	id         string
+	priority   int
+	inProgress bool
+	pool       []float64 // cycle times in days to sample from
+	fallback   float64   // estimate in days when pool is empty
+	blockers   int       // open blockers inside the simulation
+	dependents []int
+}
+
+// ForecastMonteCarlo simulates completing the open issues with cfg.Agents
+// working in parallel. Each trial samples a duration for every issue from the
+// cycle times of closed issues sharing its labels (or of all closed issues),
+// then schedules the dependency DAG: an issue starts once its blocking
+// dependencies finish and an agent is free, in-progress work first, then by
+// priority. Completion dates are reported at P50/P80/P95 per issue, per label,
+// per sprint and for the whole project.
+//
+// Without any closed history, durations come from the same complexity
+// estimate EstimateETAForIssue uses, scaled by a random factor in [0.5, 2).
+func ForecastMonteCarlo(issues []model.Issue, cfg MonteCarloConfig) MonteCarloForecast {
+	if cfg.Trials <= 0 {
+		cfg.Trials = DefaultMonteCarloTrials
+	}
+	if cfg.Agents <= 0 {
+		cfg.Agents = 1
+	}
+	if cfg.HistogramBins <= 0 {
+		cfg.HistogramBins = DefaultHistogramBins
+	}
+	if cfg.Now.IsZero() {
+		cfg.Now = time.Now()
+	}
+
+	result := MonteCarloForecast{Trials: cfg.Trials, Agents: cfg.Agents, Seed: cfg.Seed, SampleSource: "history"}
+
+	// Historical cycle times, overall and per label, in days.
+	var global []float64
+	byLabel := make(map[string][]float64)
+	for _, issue := range issues {
+		d, ok := issueCycleTime(issue)
+		if !ok {
+			continue
+		}
+		days := d.Hours() / 24
+		global = append(global, days)
+		for _, l := range issue.Labels {
+			byLabel[l] = append(byLabel[l], days)
+		}
+	}
+	result.CycleTimeSamples = len(global)
+	if len(global) == 0 {
+		result.SampleSource = "estimates"
+	}
+
+	byID := make(map[string]model.Issue, len(issues))
+	for _, issue := range issues {
+		byID[issue.ID] = issue
+	}
+	nodes, index := mcOpenNodes(issues, cfg.Focus)
+	result.Unschedulable = mcDropUnschedulable(&nodes, index)
+	medianMinutes := computeMedianEstimatedMinutes(issues)
+	for i := range nodes {
+		issue := byID[nodes[i].id]
+		nodes[i].pool = samplePool(issue.Labels, byLabel, global)
+		minutes, _ := estimateComplexityMinutes(issue, nil, medianMinutes)
+		nodes[i].fallback = float64(minutes) / estimateWorkdayMinutes
+	}
+	if len(nodes) == 0 {
+		result.Project = GroupForecast{Name: "project", ForecastQuantiles: quantilesOf(nil, cfg.Now)}
+		result.Issues = []IssueForecast{}
+		return result
+	}
+
+	// finish[i][t] is node i's completion in days from now in trial t.
+	finish := make([][]float32, len(nodes))
+	for i := range finish {
+		finish[i] = make([]float32, cfg.Trials)
+	}
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	sim := newMCSimulator(nodes, cfg.Agents)
+	for t := 0; t < cfg.Trials; t++ {
+		sim.run(rng, func(i int, days float64) { finish[i][t] = float32(days) })
+	}
+
+	// Per issue.
+	result.Issues = make([]IssueForecast, len(nodes))
+	for i, n := range nodes {
+		result.Issues[i] = IssueForecast{IssueID: n.id, ForecastQuantiles: quantilesOf(finish[i], cfg.Now)}
+	}
+	sort.Slice(result.Issues, func(i, j int) bool {
+		if result.Issues[i].P50Days != result.Issues[j].P50Days {
+			return result.Issues[i].P50Days < result.Issues[j].P50Days
+		}
+		return result.Issues[i].IssueID < result.Issues[j].IssueID
+	})
+
+	group := func(name string, members []int) GroupForecast {
+		done := make([]float32, cfg.Trials)
+		for _, i := range members {
+			for t, d := range finish[i] {
+				if d > done[t] {
+					done[t] = d
+				}
+			}
+		}
+		return GroupForecast{
+			Name:              name,
+			OpenIssues:        len(members),
+			ForecastQuantiles: quantilesOf(done, cfg.Now),
+			Histogram:         histogramOf(done, cfg.HistogramBins),
+		}
+	}
+
+	all := make([]int, len(nodes))
+	labelMembers := make(map[string][]int)
+	for i, n := range nodes {
+		all[i] = i
+		for _, l := range byID[n.id].Labels {
+			labelMembers[l] = append(labelMembers[l], i)
+		}
+	}
+	result.Project = group("project", all)
+
+	labels := make([]string, 0, len(labelMembers))
+	for l := range labelMembers {
+		labels = append(labels, l)
+	}
+	sort.Strings(labels)
+	for _, l := range labels {
+		result.Labels = append(result.Labels, group(l, labelMembers[l]))
+	}
+
+	for _, sprint := range cfg.Sprints {
+		var members []int
+		for _, id := range sprint.BeadIDs {
+			if i, ok := index[id]; ok {
+				members = append(members, i)
+			}
+		}
+		if len(members) > 0 {
+			result.Sprints = append(result.Sprints, group(sprint.ID, members))
+		}
+	}
+
+	return result
+}
+
+// mcOpenNodes collects the open issues to simulate, restricted to focus and
+// its transitive open blockers when focus is set, and links their blocking
+// dependencies.
+func mcOpenNodes(issues []model.Issue, focus []string) ([]mcNode, map[string]int) {
+	open := make(map[string]model.Issue)
+	for _, issue := range issues {
+		if issue.Status != model.StatusClosed && issue.Status != model.StatusTombstone {
+			open[issue.ID] = issue
+		}
+	}
+
+	include := make(map[string]bool, len(open))
+	if len(focus) == 0 {
+		for id := range open {
+			include[id] = true
+		}
+	} else {
+		stack := append([]string(nil), focus...)
+		for len(stack) > 0 {
+			id := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			issue, ok := open[id]
+			if !ok || include[id] {
+				continue
+			}
+			include[id] = true
+			for _, dep := range issue.Dependencies {
+				if dep != nil && dep.Type.IsBlocking() {
+					stack = append(stack, dep.DependsOnID)
+				}
+			}
+		}
+	}
+
+	ids := make([]string, 0, len(include))
+	for id := range include {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	nodes := make([]mcNode, len(ids))
+	index := make(map[string]int, len(ids))
+	for i, id := range ids {
+		issue := open[id]
+		nodes[i] = mcNode{id: id, priority: issue.Priority, inProgress: issue.Status == model.StatusInProgress}
+		index[id] = i
+	}
+	for i, id := range ids {
+		seen := make(map[int]bool)
+		for _, dep := range open[id].Dependencies {
+			if dep == nil || !dep.Type.IsBlocking() {
+				continue
+			}
+			j, ok := index[dep.DependsOnID]
+			if !ok || j == i || seen[j] {
+				continue
+			}
+			seen[j] = true
+			nodes[i].blockers++
+			nodes[j].dependents = append(nodes[j].dependents, i)
+		}
+	}
+	return nodes, index
+}
+
+// mcDropUnschedulable removes nodes that can never start (cycles and what
+// waits on them), updating index, and returns their IDs.
+func mcDropUnschedulable(nodes *[]mcNode, index map[string]int) []string {
+	ns := *nodes
+	remaining := make([]int, len(ns))
+	var queue []int
+	for i, n := range ns {
+		remaining[i] = n.blockers
+		if n.blockers == 0 {
+			queue = append(queue, i)
+		}
+	}
+	reachable := make([]bool, len(ns))
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		reachable[i] = true
+		for _, d := range ns[i].dependents {
+			remaining[d]--
+			if remaining[d] == 0 {
+				queue = append(queue, d)
+			}
+		}
+	}
+
+	// A reachable node's blockers are all reachable, so only dependents
+	// need filtering.
+	var dropped []string
+	renumber := make([]int, len(ns))
+	kept := ns[:0:0]
+	for i, n := range ns {
+		if !reachable[i] {
+			dropped = append(dropped, n.id)
+			delete(index, n.id)
+			renumber[i] = -1
+			continue
+		}
+		renumber[i] = len(kept)
+		index[n.id] = len(kept)
+		kept = append(kept, n)
+	}
+	if len(dropped) == 0 {
+		return nil
+	}
+	for i := range kept {
+		var deps []int
+		for _, d := range kept[i].dependents {
+			if renumber[d] >= 0 {
+				deps = append(deps, renumber[d])
+			}
+		}
+		kept[i].dependents = deps
+	}
+	*nodes = kept
+	return dropped
+}
+
+// samplePool returns the cycle times to sample for an issue with labels: the
+// union of its labels' samples when they are numerous enough, else global.
+func samplePool(labels []string, byLabel map[string][]float64, global []float64) []float64 {
+	var pool []float64
+	for _, l := range labels {
+		pool = append(pool, byLabel[l]...)
+	}
+	if len(pool) >= minLabelSamples {
+		return pool
+	}
+	return global
+}
+
+// mcSimulator schedules one trial at a time, reusing its buffers.
+type mcSimulator struct {
+	nodes     []mcNode
+	agents    int
+	remaining []int
+	ready     mcReadyQueue
+	running   mcRunningQueue
+}
+
+func newMCSimulator(nodes []mcNode, agents int) *mcSimulator {
+	return &mcSimulator{
+		nodes:     nodes,
+		agents:    agents,
+		remaining: make([]int, len(nodes)),
+		ready:     mcReadyQueue{nodes: nodes},
+	}
+}
+
+// run simulates one trial, calling done with each node's completion time in
+// days from now.
+func (s *mcSimulator) run(rng *rand.Rand, done func(i int, days float64)) {
+	s.ready.items = s.ready.items[:0]
+	s.running = s.running[:0]
+	for i, n := range s.nodes {
+		s.remaining[i] = n.blockers
+		if n.blockers == 0 {
+			s.ready.items = append(s.ready.items, i)
+		}
+	}
+	heap.Init(&s.ready)
+
+	now, free := 0.0, s.agents
+	for {
+		for free > 0 && s.ready.Len() > 0 {
+			i := heap.Pop(&s.ready).(int)
+			heap.Push(&s.running, mcRunning{node: i, finish: now + s.sample(rng, i)})
+			free--
+		}
+		if s.running.Len() == 0 {
+			return
+		}
+		r := heap.Pop(&s.running).(mcRunning)
+		now = r.finish
+		free++
+		done(r.node, now)
+		for _, d := range s.nodes[r.node].dependents {
+			s.remaining[d]--
+			if s.remaining[d] == 0 {
+				heap.Push(&s.ready, d)
+			}
+		}
+	}
+}
+
+func (s *mcSimulator) sample(rng *rand.Rand, i int) float64 {
+	n := &s.nodes[i]
+	if len(n.pool) > 0 {
+		return n.pool[rng.Intn(len(n.pool))]
+	}
+	return n.fallback * (0.5 + 1.5*rng.Float64())
+}
+
+// mcReadyQueue orders startable nodes: in-progress first, then priority, then ID.
+type mcReadyQueue struct {
+	nodes []mcNode
+	items []int
+}
+
+func (q mcReadyQueue) Len() int { return len(q.items) }
+func (q mcReadyQueue) Less(i, j int) bool {
+	a, b := &q.nodes[q.items[i]], &q.nodes[q.items[j]]
+	if a.inProgress != b.inProgress {
+		return a.inProgress
+	}
+	if a.priority != b.priority {
+		return a.priority < b.priority
+	}
+	return a.id < b.id
+}
+func (q mcReadyQueue) Swap(i, j int) { q.items[i], q.items[j] = q.items[j], q.items[i] }
+func (q *mcReadyQueue) Push(x any)   { q.items = append(q.items, x.(int)) }
+func (q *mcReadyQueue) Pop() any {
+	n := len(q.items)
+	x := q.items[n-1]
+	q.items = q.items[:n-1]
+	return x
+}
+
+type mcRunning struct {
+	node   int
+	finish float64
+}
+
+// mcRunningQueue orders in-flight work by finish time.
+type mcRunningQueue []mcRunning
+
+func (q mcRunningQueue) Len() int { return len(q) }
+func (q mcRunningQueue) Less(i, j int) bool {
+	if q[i].finish != q[j].finish {
+		return q[i].finish < q[j].finish
+	}
+	return q[i].node < q[j].node
+}
+func (q mcRunningQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *mcRunningQueue) Push(x any)   { *q = append(*q, x.(mcRunning)) }
+func (q *mcRunningQueue) Pop() any {
+	old := *q
+	x := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return x
+}
+
+// quantilesOf returns the P50/P80/P95 of days (nearest rank) as dates from now.
+func quantilesOf(days []float32, now time.Time) ForecastQuantiles {
+	if len(days) == 0 {
+		return ForecastQuantiles{P50: now, P80: now, P95: now}
+	}
+	sorted := append([]float32(nil), days...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	at := func(p float64) float64 {
+		i := int(math.Ceil(p*float64(len(sorted)))) - 1
+		if i < 0 {
+			i = 0
+		}
+		return roundDays(float64(sorted[i]))
+	}
+	q := ForecastQuantiles{P50Days: at(0.50), P80Days: at(0.80), P95Days: at(0.95)}
+	q.P50 = now.Add(durationDays(q.P50Days))
+	q.P80 = now.Add(durationDays(q.P80Days))
+	q.P95 = now.Add(durationDays(q.P95Days))
+	return q
+}
+
+// histogramOf buckets days into equal-width bins spanning their range.
+func histogramOf(days []float32, bins int) []HistogramBin {
+	if len(days) == 0 || bins <= 0 {
+		return nil
+	}
+	lo, hi := days[0], days[0]
+	for _, d := range days {
+		lo = min(lo, d)
+		hi = max(hi, d)
+	}
+	width := float64(hi-lo) / float64(bins)
+	if width == 0 {
+		return []HistogramBin{{FromDays: roundDays(float64(lo)), ToDays: roundDays(float64(hi)), Count: len(days)}}
+	}
+	out := make([]HistogramBin, bins)
+	for b := range out {
+		out[b].FromDays = roundDays(float64(lo) + width*float64(b))
+		out[b].ToDays = roundDays(float64(lo) + width*float64(b+1))
+	}
+	for _, d := range days {
+		b := int(float64(d-lo) / width)
+		if b >= bins {
+			b = bins - 1
+		}
+		out[b].Count++
+	}
+	return out
+}
+
+func roundDays(d float64) float64 {
+	return math.Round(d*100) / 100
+}
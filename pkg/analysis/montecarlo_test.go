@@ -0,0 +1,137 @@
+package analysis_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+var mcNow = time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+// closedAfter returns a closed issue whose cycle time is days.
+func closedAfter(id string, days float64, labels ...string) model.Issue {
+	created := mcNow.Add(-90 * 24 * time.Hour)
+	closed := created.Add(time.Duration(days * float64(24*time.Hour)))
+	return model.Issue{ID: id, Title: id, Status: model.StatusClosed, IssueType: model.TypeTask,
+		CreatedAt: created, ClosedAt: &closed, Labels: labels}
+}
+
+func TestCycleTimeSamples(t *testing.T) {
+	issues := []model.Issue{
+		closedAfter("c1", 2),
+		closedAfter("c2", 4),
+		{ID: "open", Title: "open", Status: model.StatusOpen, CreatedAt: mcNow},
+	}
+	samples := analysis.CycleTimeSamples(issues)
+	want := []time.Duration{48 * time.Hour, 96 * time.Hour}
+	if !reflect.DeepEqual(samples, want) {
+		t.Fatalf("CycleTimeSamples = %v, want %v", samples, want)
+	}
+}
+
+func TestForecastMonteCarloChainWithConstantCycleTime(t *testing.T) {
+	// Every historical issue took exactly 2 days, so the simulation is
+	// deterministic: a chain of three finishes on days 2, 4 and 6.
+	issues := []model.Issue{
+		closedAfter("h1", 2), closedAfter("h2", 2), closedAfter("h3", 2),
+		{ID: "A", Title: "A", Status: model.StatusOpen, Labels: []string{"api"}},
+		{ID: "B", Title: "B", Status: model.StatusOpen, Labels: []string{"api"},
+			Dependencies: []*model.Dependency{{IssueID: "B", DependsOnID: "A", Type: model.DepBlocks}}},
+		{ID: "C", Title: "C", Status: model.StatusOpen,
+			Dependencies: []*model.Dependency{{IssueID: "C", DependsOnID: "B", Type: model.DepBlocks}}},
+	}
+	fc := analysis.ForecastMonteCarlo(issues, analysis.MonteCarloConfig{
+		Trials: 50, Agents: 4, Now: mcNow,
+		Sprints: []model.Sprint{{ID: "s1", BeadIDs: []string{"A", "B"}}},
+	})
+
+	if fc.SampleSource != "history" || fc.CycleTimeSamples != 3 {
+		t.Fatalf("unexpected sampling: %s/%d", fc.SampleSource, fc.CycleTimeSamples)
+	}
+	for id, want := range map[string]float64{"A": 2, "B": 4, "C": 6} {
+		f, ok := fc.Issue(id)
+		if !ok {
+			t.Fatalf("no forecast for %s", id)
+		}
+		if f.P50Days != want || f.P95Days != want {
+			t.Errorf("%s: P50/P95 = %.2f/%.2f, want %.0f", id, f.P50Days, f.P95Days, want)
+		}
+	}
+	if fc.Project.P80Days != 6 || fc.Project.OpenIssues != 3 || !fc.Project.P80.Equal(mcNow.Add(6*24*time.Hour)) {
+		t.Errorf("project forecast = %+v", fc.Project)
+	}
+	if len(fc.Labels) != 1 || fc.Labels[0].Name != "api" || fc.Labels[0].P50Days != 4 {
+		t.Errorf("label forecast = %+v", fc.Labels)
+	}
+	if len(fc.Sprints) != 1 || fc.Sprints[0].P50Days != 4 || fc.Sprints[0].Histogram[0].Count != 50 {
+		t.Errorf("sprint forecast = %+v", fc.Sprints)
+	}
+}
+
+func TestForecastMonteCarloAgentsShareWork(t *testing.T) {
+	issues := []model.Issue{closedAfter("h", 1)}
+	for _, id := range []string{"A", "B", "C", "D"} {
+		issues = append(issues, model.Issue{ID: id, Title: id, Status: model.StatusOpen})
+	}
+
+	one := analysis.ForecastMonteCarlo(issues, analysis.MonteCarloConfig{Trials: 10, Agents: 1, Now: mcNow})
+	two := analysis.ForecastMonteCarlo(issues, analysis.MonteCarloConfig{Trials: 10, Agents: 2, Now: mcNow})
+	if one.Project.P50Days != 4 || two.Project.P50Days != 2 {
+		t.Fatalf("project P50 with 1/2 agents = %.2f/%.2f, want 4/2", one.Project.P50Days, two.Project.P50Days)
+	}
+}
+
+func TestForecastMonteCarloQuantilesAreOrderedAndSeeded(t *testing.T) {
+	issues := []model.Issue{
+		closedAfter("h1", 1), closedAfter("h2", 3), closedAfter("h3", 8), closedAfter("h4", 20),
+		{ID: "A", Title: "A", Status: model.StatusOpen},
+		{ID: "B", Title: "B", Status: model.StatusInProgress},
+	}
+	cfg := analysis.MonteCarloConfig{Trials: 500, Agents: 1, Seed: 7, Now: mcNow}
+	fc := analysis.ForecastMonteCarlo(issues, cfg)
+	p := fc.Project
+	if !(p.P50Days <= p.P80Days && p.P80Days <= p.P95Days) || p.P50Days == p.P95Days {
+		t.Fatalf("quantiles not spread and ordered: %+v", p)
+	}
+	total := 0
+	for _, b := range p.Histogram {
+		total += b.Count
+	}
+	if total != 500 {
+		t.Errorf("histogram counts %d trials, want 500", total)
+	}
+	if again := analysis.ForecastMonteCarlo(issues, cfg); !reflect.DeepEqual(again, fc) {
+		t.Error("same seed produced a different forecast")
+	}
+}
+
+func TestForecastMonteCarloEstimatesAndCycles(t *testing.T) {
+	est := 8 * 60
+	issues := []model.Issue{
+		{ID: "A", Title: "A", Status: model.StatusOpen, IssueType: model.TypeTask, EstimatedMinutes: &est},
+		{ID: "X", Title: "X", Status: model.StatusOpen,
+			Dependencies: []*model.Dependency{{IssueID: "X", DependsOnID: "Y", Type: model.DepBlocks}}},
+		{ID: "Y", Title: "Y", Status: model.StatusOpen,
+			Dependencies: []*model.Dependency{{IssueID: "Y", DependsOnID: "X", Type: model.DepBlocks}}},
+		{ID: "Z", Title: "Z", Status: model.StatusOpen,
+			Dependencies: []*model.Dependency{{IssueID: "Z", DependsOnID: "X", Type: model.DepBlocks}}},
+	}
+	fc := analysis.ForecastMonteCarlo(issues, analysis.MonteCarloConfig{Trials: 200, Now: mcNow, Focus: []string{"A", "Z"}})
+	if fc.SampleSource != "estimates" {
+		t.Fatalf("SampleSource = %q", fc.SampleSource)
+	}
+	if !reflect.DeepEqual(fc.Unschedulable, []string{"X", "Y", "Z"}) {
+		t.Fatalf("Unschedulable = %v", fc.Unschedulable)
+	}
+	a, ok := fc.Issue("A")
+	if !ok || len(fc.Issues) != 1 {
+		t.Fatalf("expected only A to be forecast, got %+v", fc.Issues)
+	}
+	// One 8h-day estimate scaled by [0.5, 2).
+	if a.P50Days < 0.5 || a.P95Days >= 2 {
+		t.Errorf("A quantiles outside the estimate range: %+v", a.ForecastQuantiles)
+	}
+}
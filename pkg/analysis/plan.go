@@ -13,6 +13,11 @@ type PlanItem struct {
 	Priority    int      `json:"priority"`
 	Status      string   `json:"status"`
 	UnblocksIDs []string `json:"unblocks"` // Issues that become actionable when this is done
+
+	// Duration-weighted schedule (see WeightedCriticalPath)
+	EstimatedMinutes int  `json:"estimated_minutes"`
+	SlackMinutes     int  `json:"slack_minutes"`      // how long this can wait without delaying the finish
+	Critical         bool `json:"critical,omitempty"` // zero slack: starts the critical chain
 }
 
 // ExecutionTrack represents a group of related actionable items
@@ -28,6 +33,9 @@ type ExecutionPlan struct {
 	TotalActionable int              `json:"total_actionable"`
 	TotalBlocked    int              `json:"total_blocked"`
 	Summary         PlanSummary      `json:"summary"`
+
+	// CriticalPath is the estimate-weighted critical chain through open work
+	CriticalPath *WeightedCriticalPath `json:"critical_path,omitempty"`
 }
 
 // PlanSummary provides quick insights about the plan
@@ -40,7 +48,23 @@ type PlanSummary struct {
 // GetExecutionPlan generates a dependency-respecting execution plan
 // with parallel tracks identified for concurrent work.
 func (a *Analyzer) GetExecutionPlan() ExecutionPlan {
+	return a.GetExecutionPlanFor(nil)
+}
+
+// GetExecutionPlanFor is GetExecutionPlan restricted to the issues include
+// accepts (nil = all). Blocking is still judged on the whole graph, so an
+// excluded open blocker keeps its dependents out of the plan.
+func (a *Analyzer) GetExecutionPlanFor(include func(id string) bool) ExecutionPlan {
 	actionable := a.GetActionableIssues()
+	if include != nil {
+		kept := make([]model.Issue, 0, len(actionable))
+		for _, issue := range actionable {
+			if include(issue.ID) {
+				kept = append(kept, issue)
+			}
+		}
+		actionable = kept
+	}
 
 	// Build set of actionable IDs for quick lookup
 	actionableSet := make(map[string]bool, len(actionable))
@@ -63,8 +87,8 @@ func (a *Analyzer) GetExecutionPlan() ExecutionPlan {
 
 	// Calculate totals
 	totalOpen := 0
-	for _, issue := range a.issueMap {
-		if issue.Status != model.StatusClosed {
+	for id, issue := range a.issueMap {
+		if issue.Status != model.StatusClosed && (include == nil || include(id)) {
 			totalOpen++
 		}
 	}
@@ -72,11 +96,24 @@ func (a *Analyzer) GetExecutionPlan() ExecutionPlan {
 	// Find highest impact issue
 	summary := a.computePlanSummary(actionable, unblocksMap)
 
+	schedule := a.WeightedCriticalPath()
+	for ti := range tracks {
+		for ii := range tracks[ti].Items {
+			item := &tracks[ti].Items[ii]
+			if t, ok := schedule.Timing(item.ID); ok {
+				item.EstimatedMinutes = t.DurationMinutes
+				item.SlackMinutes = t.SlackMinutes
+				item.Critical = t.Critical
+			}
+		}
+	}
+
 	return ExecutionPlan{
 		Tracks:          tracks,
 		TotalActionable: len(actionable),
 		TotalBlocked:    totalOpen - len(actionable),
 		Summary:         summary,
+		CriticalPath:    schedule,
 	}
 }
 
@@ -502,4 +502,25 @@ func TestGetExecutionPlanLegacyDependencyGrouping(t *testing.T) {
 	if len(plan.Tracks) != 1 {
 		t.Errorf("Expected 1 track (grouped via legacy dependency), got %d tracks", len(plan.Tracks))
 	}
-}
\ No newline at end of file
+}
+func TestGetExecutionPlanForKeepsOutsideBlockers(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "bd-1", Status: model.StatusOpen, Dependencies: []*model.Dependency{{IssueID: "bd-1", DependsOnID: "bd-2", Type: model.DepBlocks}}},
+		{ID: "bd-2", Status: model.StatusOpen},
+		{ID: "bd-3", Status: model.StatusOpen},
+	}
+	plan := analysis.NewAnalyzer(issues).GetExecutionPlanFor(func(id string) bool { return id != "bd-2" })
+
+	var ids []string
+	for _, track := range plan.Tracks {
+		for _, item := range track.Items {
+			ids = append(ids, item.ID)
+		}
+	}
+	if len(ids) != 1 || ids[0] != "bd-3" {
+		t.Errorf("plan items = %v, want [bd-3]", ids)
+	}
+	if plan.TotalActionable != 1 || plan.TotalBlocked != 1 {
+		t.Errorf("totals = %d actionable, %d blocked; want 1 and 1", plan.TotalActionable, plan.TotalBlocked)
+	}
+}
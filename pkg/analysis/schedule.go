@@ -0,0 +1,420 @@
+package analysis
+
+import (
+	"container/heap"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RosterFilename is the roster config under .bv/.
+const RosterFilename = "roster.yaml"
+
+// RosterAgent is someone the scheduler can hand work to.
+type RosterAgent struct {
+	Name string `yaml:"name" json:"name"`
+	// Capacity is the number of issues the agent works on at once (default 1).
+	Capacity int `yaml:"capacity,omitempty" json:"capacity"`
+	// Skills are labels the agent can take. An agent without skills takes
+	// anything; otherwise it takes unlabeled issues and issues carrying one
+	// of its skills.
+	Skills []string `yaml:"skills,omitempty" json:"skills,omitempty"`
+}
+
+// RosterPath returns the roster config path for a project.
+func RosterPath(projectDir string) string {
+	return filepath.Join(projectDir, ".bv", RosterFilename)
+}
+
+// LoadRoster reads .bv/roster.yaml. A missing file yields no agents and no
+// error.
+func LoadRoster(projectDir string) ([]RosterAgent, error) {
+	data, err := os.ReadFile(RosterPath(projectDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading roster: %w", err)
+	}
+	var cfg struct {
+		Agents []RosterAgent `yaml:"agents"`
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing roster: %w", err)
+	}
+	seen := make(map[string]bool, len(cfg.Agents))
+	for i, a := range cfg.Agents {
+		if a.Name == "" {
+			return nil, fmt.Errorf("invalid roster: agent %d has no name", i+1)
+		}
+		if seen[a.Name] {
+			return nil, fmt.Errorf("invalid roster: duplicate agent %q", a.Name)
+		}
+		if a.Capacity < 0 {
+			return nil, fmt.Errorf("invalid roster: agent %q has negative capacity", a.Name)
+		}
+		seen[a.Name] = true
+	}
+	return cfg.Agents, nil
+}
+
+// DefaultRoster builds a roster when none is configured: everyone currently
+// assigned open work, topped up with generic agents ("agent-1", ...) until
+// there are at least minAgents.
+func DefaultRoster(issues []model.Issue, minAgents int) []RosterAgent {
+	seen := make(map[string]bool)
+	var names []string
+	for _, issue := range issues {
+		if issue.Assignee == "" || seen[issue.Assignee] || issue.Status == model.StatusClosed || issue.Status == model.StatusTombstone {
+			continue
+		}
+		seen[issue.Assignee] = true
+		names = append(names, issue.Assignee)
+	}
+	sort.Strings(names)
+	for n := 1; len(names) < max(1, minAgents); n++ {
+		if name := fmt.Sprintf("agent-%d", n); !seen[name] {
+			names = append(names, name)
+		}
+	}
+
+	roster := make([]RosterAgent, len(names))
+	for i, name := range names {
+		roster[i] = RosterAgent{Name: name, Capacity: 1}
+	}
+	return roster
+}
+
+// ScheduledIssue is one issue placed on an agent's timeline. Offsets are in
+// working minutes from the schedule start; Start and End convert them to
+// dates assuming eight working hours per calendar day.
+type ScheduledIssue struct {
+	IssueID      string    `json:"issue_id"`
+	Title        string    `json:"title"`
+	Agent        string    `json:"agent"`
+	StartMinutes int       `json:"start_minutes"`
+	EndMinutes   int       `json:"end_minutes"`
+	Start        time.Time `json:"start"`
+	End          time.Time `json:"end"`
+	// Pinned issues stay with their current assignee.
+	Pinned bool `json:"pinned,omitempty"`
+	// WaitsOn lists the open blockers that had to finish first.
+	WaitsOn []string `json:"waits_on,omitempty"`
+}
+
+// AgentSchedule is one agent's share of the plan, in start order.
+type AgentSchedule struct {
+	RosterAgent
+	Issues      []ScheduledIssue `json:"issues"`
+	BusyMinutes int              `json:"busy_minutes"`
+	// Utilization is busy time over capacity × makespan.
+	Utilization float64 `json:"utilization"`
+}
+
+// UnscheduledIssue is an open issue the scheduler could not place.
+type UnscheduledIssue struct {
+	IssueID string `json:"issue_id"`
+	Reason  string `json:"reason"`
+}
+
+// Schedule is a time-phased assignment of open work to a roster.
+type Schedule struct {
+	Start           time.Time          `json:"start"`
+	End             time.Time          `json:"end"`
+	MakespanMinutes int                `json:"makespan_minutes"`
+	Agents          []AgentSchedule    `json:"agents"`
+	Unscheduled     []UnscheduledIssue `json:"unscheduled,omitempty"`
+}
+
+// Issue returns where id was scheduled.
+func (s *Schedule) Issue(id string) (ScheduledIssue, bool) {
+	for _, a := range s.Agents {
+		for _, si := range a.Issues {
+			if si.IssueID == id {
+				return si, true
+			}
+		}
+	}
+	return ScheduledIssue{}, false
+}
+
+// ScheduleOptions controls ComputeSchedule.
+type ScheduleOptions struct {
+	Roster []RosterAgent // Default: DefaultRoster(issues, 1)
+	Now    time.Time
+}
+
+// ComputeSchedule assigns every open issue to an agent and a start time so
+// that blocking dependencies finish first, no agent exceeds its capacity and
+// the overall finish (makespan) is short.
+//
+// It is a list scheduler: whenever an agent frees up, the ready issue with
+// the longest chain of estimated work still depending on it goes first
+// (in-progress work before anything else, then priority and ID break ties),
+// to the least-loaded eligible agent. Durations come from the same complexity
+// estimate EstimateETAForIssue uses; stats may be nil.
+//
+// An issue assigned to a roster agent who has not declined, deferred or
+// marked it impossible stays with that agent. Declined issues are never
+// handed back to the agent who declined them.
+func ComputeSchedule(issues []model.Issue, stats *GraphStats, opts ScheduleOptions) Schedule {
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	roster := opts.Roster
+	if len(roster) == 0 {
+		roster = DefaultRoster(issues, 1)
+	}
+
+	sched := Schedule{Start: opts.Now, End: opts.Now, Agents: make([]AgentSchedule, len(roster))}
+	agentIndex := make(map[string]int, len(roster))
+	for i, a := range roster {
+		if a.Capacity <= 0 {
+			a.Capacity = 1
+		}
+		sched.Agents[i] = AgentSchedule{RosterAgent: a, Issues: []ScheduledIssue{}}
+		agentIndex[a.Name] = i
+	}
+
+	byID := make(map[string]model.Issue, len(issues))
+	for _, issue := range issues {
+		byID[issue.ID] = issue
+	}
+	nodes, index := mcOpenNodes(issues, nil)
+	for _, id := range mcDropUnschedulable(&nodes, index) {
+		sched.Unscheduled = append(sched.Unscheduled, UnscheduledIssue{IssueID: id, Reason: "in or waiting on a dependency cycle"})
+	}
+
+	medianMinutes := computeMedianEstimatedMinutes(issues)
+	minutes := make([]int, len(nodes))
+	for i, n := range nodes {
+		minutes[i], _ = estimateComplexityMinutes(byID[n.id], stats, medianMinutes)
+	}
+	chain := scheduleChainMinutes(nodes, minutes)
+
+	// Who may take each issue.
+	candidates := make([][]int, len(nodes))
+	pinned := make([]bool, len(nodes))
+	for i, n := range nodes {
+		issue := byID[n.id]
+		if a, ok := agentIndex[issue.Assignee]; ok && !ackReleased(issue.AckStatus) {
+			candidates[i] = []int{a}
+			pinned[i] = true
+			continue
+		}
+		for a, agent := range sched.Agents {
+			if agent.Name == issue.Assignee && ackReleased(issue.AckStatus) {
+				continue
+			}
+			if agentHasSkill(agent.RosterAgent, issue.Labels) {
+				candidates[i] = append(candidates[i], a)
+			}
+		}
+	}
+
+	// Blockers inside the schedule, for WaitsOn.
+	waitsOn := make([][]string, len(nodes))
+	for _, n := range nodes {
+		for _, d := range n.dependents {
+			waitsOn[d] = append(waitsOn[d], n.id)
+		}
+	}
+
+	remaining := make([]int, len(nodes))
+	ready := &scheduleReadyQueue{nodes: nodes, chain: chain}
+	for i, n := range nodes {
+		remaining[i] = n.blockers
+		if n.blockers == 0 {
+			heap.Push(ready, i)
+		}
+	}
+	free := make([]int, len(sched.Agents))
+	for a, agent := range sched.Agents {
+		free[a] = agent.Capacity
+	}
+	running := &scheduleRunningQueue{}
+	placed := make([]bool, len(nodes))
+	now := 0
+
+	for {
+		// Hand out ready issues in priority order; issues whose agents are
+		// all busy wait for the next completion.
+		var waiting []int
+		for ready.Len() > 0 {
+			i := heap.Pop(ready).(int)
+			if len(candidates[i]) == 0 {
+				sched.Unscheduled = append(sched.Unscheduled, UnscheduledIssue{IssueID: nodes[i].id, Reason: "no roster agent can take it"})
+				continue
+			}
+			a := -1
+			for _, c := range candidates[i] {
+				if free[c] > 0 && (a < 0 || sched.Agents[c].BusyMinutes < sched.Agents[a].BusyMinutes) {
+					a = c
+				}
+			}
+			if a < 0 {
+				waiting = append(waiting, i)
+				continue
+			}
+			free[a]--
+			end := now + minutes[i]
+			agent := &sched.Agents[a]
+			agent.BusyMinutes += minutes[i]
+			agent.Issues = append(agent.Issues, ScheduledIssue{
+				IssueID:      nodes[i].id,
+				Title:        byID[nodes[i].id].Title,
+				Agent:        agent.Name,
+				StartMinutes: now,
+				EndMinutes:   end,
+				Start:        scheduleTime(opts.Now, now),
+				End:          scheduleTime(opts.Now, end),
+				Pinned:       pinned[i],
+				WaitsOn:      waitsOn[i],
+			})
+			placed[i] = true
+			heap.Push(running, scheduleRun{node: i, agent: a, end: end})
+		}
+		for _, i := range waiting {
+			heap.Push(ready, i)
+		}
+		if running.Len() == 0 {
+			break
+		}
+
+		now = (*running)[0].end
+		for running.Len() > 0 && (*running)[0].end == now {
+			r := heap.Pop(running).(scheduleRun)
+			free[r.agent]++
+			for _, d := range nodes[r.node].dependents {
+				remaining[d]--
+				if remaining[d] == 0 {
+					heap.Push(ready, d)
+				}
+			}
+		}
+	}
+
+	for i, n := range nodes {
+		if !placed[i] && remaining[i] > 0 {
+			sched.Unscheduled = append(sched.Unscheduled, UnscheduledIssue{IssueID: n.id, Reason: "waits on an unscheduled issue"})
+		}
+	}
+	sort.Slice(sched.Unscheduled, func(i, j int) bool { return sched.Unscheduled[i].IssueID < sched.Unscheduled[j].IssueID })
+
+	sched.MakespanMinutes = now
+	sched.End = scheduleTime(opts.Now, now)
+	for a := range sched.Agents {
+		agent := &sched.Agents[a]
+		if now > 0 {
+			agent.Utilization = float64(agent.BusyMinutes) / float64(agent.Capacity*now)
+		}
+	}
+	return sched
+}
+
+// ackReleased reports whether the assignee has handed the issue back.
+func ackReleased(s model.AckStatus) bool {
+	return s == model.AckStatusDeclined || s == model.AckStatusDeferred || s == model.AckStatusImpossible
+}
+
+func agentHasSkill(agent RosterAgent, labels []string) bool {
+	if len(agent.Skills) == 0 || len(labels) == 0 {
+		return true
+	}
+	for _, s := range agent.Skills {
+		if hasLabel(labels, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// scheduleTime converts working minutes to a date at eight hours a day.
+func scheduleTime(start time.Time, minutes int) time.Time {
+	return start.Add(durationDays(float64(minutes) / estimateWorkdayMinutes))
+}
+
+// scheduleChainMinutes returns, for each node, its own minutes plus the
+// longest chain of work depending on it. nodes must form a DAG.
+func scheduleChainMinutes(nodes []mcNode, minutes []int) []int {
+	chain := make([]int, len(nodes))
+	done := make([]bool, len(nodes))
+	var visit func(int) int
+	visit = func(i int) int {
+		if done[i] {
+			return chain[i]
+		}
+		longest := 0
+		for _, d := range nodes[i].dependents {
+			longest = max(longest, visit(d))
+		}
+		chain[i] = minutes[i] + longest
+		done[i] = true
+		return chain[i]
+	}
+	for i := range nodes {
+		visit(i)
+	}
+	return chain
+}
+
+// scheduleReadyQueue orders ready nodes: in-progress first, then longest
+// remaining chain, then priority, then ID.
+type scheduleReadyQueue struct {
+	nodes []mcNode
+	chain []int
+	items []int
+}
+
+func (q scheduleReadyQueue) Len() int { return len(q.items) }
+func (q scheduleReadyQueue) Less(i, j int) bool {
+	a, b := q.items[i], q.items[j]
+	na, nb := q.nodes[a], q.nodes[b]
+	if na.inProgress != nb.inProgress {
+		return na.inProgress
+	}
+	if q.chain[a] != q.chain[b] {
+		return q.chain[a] > q.chain[b]
+	}
+	if na.priority != nb.priority {
+		return na.priority < nb.priority
+	}
+	return na.id < nb.id
+}
+func (q scheduleReadyQueue) Swap(i, j int) { q.items[i], q.items[j] = q.items[j], q.items[i] }
+func (q *scheduleReadyQueue) Push(x any)   { q.items = append(q.items, x.(int)) }
+func (q *scheduleReadyQueue) Pop() any {
+	n := len(q.items)
+	x := q.items[n-1]
+	q.items = q.items[:n-1]
+	return x
+}
+
+type scheduleRun struct {
+	node, agent, end int
+}
+
+// scheduleRunningQueue orders running work by finish time.
+type scheduleRunningQueue []scheduleRun
+
+func (q scheduleRunningQueue) Len() int { return len(q) }
+func (q scheduleRunningQueue) Less(i, j int) bool {
+	if q[i].end != q[j].end {
+		return q[i].end < q[j].end
+	}
+	return q[i].node < q[j].node
+}
+func (q scheduleRunningQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *scheduleRunningQueue) Push(x any)   { *q = append(*q, x.(scheduleRun)) }
+func (q *scheduleRunningQueue) Pop() any {
+	old := *q
+	x := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return x
+}
@@ -0,0 +1,164 @@
+package analysis_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+var schedNow = time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+
+// task returns an open task taking minutes, blocked by deps.
+func task(id string, minutes int, deps ...string) model.Issue {
+	issue := model.Issue{ID: id, Title: id, Status: model.StatusOpen, IssueType: model.TypeTask, EstimatedMinutes: &minutes}
+	for _, d := range deps {
+		issue.Dependencies = append(issue.Dependencies, blocks(id, d))
+	}
+	return issue
+}
+
+func agentIssueIDs(s analysis.Schedule, agent string) []string {
+	for _, a := range s.Agents {
+		if a.Name == agent {
+			ids := []string{}
+			for _, si := range a.Issues {
+				ids = append(ids, si.IssueID)
+			}
+			return ids
+		}
+	}
+	return nil
+}
+
+func TestComputeScheduleRespectsDependenciesAndMinimisesMakespan(t *testing.T) {
+	// A (240) → B (240) is the long chain; C and D (120 each) fit beside it.
+	issues := []model.Issue{
+		task("A", 240), task("B", 240, "A"), task("C", 120), task("D", 120),
+		{ID: "Z", Title: "done", Status: model.StatusClosed},
+	}
+	s := analysis.ComputeSchedule(issues, nil, analysis.ScheduleOptions{
+		Roster: []analysis.RosterAgent{{Name: "ann"}, {Name: "bob"}},
+		Now:    schedNow,
+	})
+
+	if s.MakespanMinutes != 480 {
+		t.Fatalf("makespan = %d, want 480", s.MakespanMinutes)
+	}
+	if got := agentIssueIDs(s, "ann"); !reflect.DeepEqual(got, []string{"A", "B"}) {
+		t.Errorf("ann = %v, want the A → B chain", got)
+	}
+	if got := agentIssueIDs(s, "bob"); !reflect.DeepEqual(got, []string{"C", "D"}) {
+		t.Errorf("bob = %v, want [C D]", got)
+	}
+	b, _ := s.Issue("B")
+	if b.StartMinutes != 240 || !reflect.DeepEqual(b.WaitsOn, []string{"A"}) {
+		t.Errorf("B = %+v, want it to start after A", b)
+	}
+	if want := schedNow.Add(24 * time.Hour); !s.End.Equal(want) {
+		t.Errorf("End = %v, want one working day later (%v)", s.End, want)
+	}
+	if s.Agents[0].Utilization != 1 || s.Agents[1].Utilization != 0.5 {
+		t.Errorf("utilization = %v, %v", s.Agents[0].Utilization, s.Agents[1].Utilization)
+	}
+}
+
+func TestComputeScheduleHonoursAssigneesAndAcks(t *testing.T) {
+	kept := task("K", 60)
+	kept.Assignee = "bob"
+	kept.AckStatus = model.AckStatusAccepted
+	declined := task("X", 60)
+	declined.Assignee = "bob"
+	declined.AckStatus = model.AckStatusDeclined
+
+	s := analysis.ComputeSchedule([]model.Issue{kept, declined}, nil, analysis.ScheduleOptions{
+		Roster: []analysis.RosterAgent{{Name: "ann"}, {Name: "bob"}},
+		Now:    schedNow,
+	})
+	k, _ := s.Issue("K")
+	x, _ := s.Issue("X")
+	if k.Agent != "bob" || !k.Pinned {
+		t.Errorf("K = %+v, want it pinned to bob", k)
+	}
+	if x.Agent != "ann" || x.Pinned {
+		t.Errorf("X = %+v, want it reassigned away from bob", x)
+	}
+}
+
+func TestComputeScheduleSkillsCapacityAndCycles(t *testing.T) {
+	ui := task("UI", 60)
+	ui.Labels = []string{"frontend"}
+	db := task("DB", 60)
+	db.Labels = []string{"database"}
+	issues := []model.Issue{
+		ui, db, task("AfterDB", 60, "DB"),
+		task("P1", 60), task("P2", 60),
+		task("Y1", 60, "Y2"), task("Y2", 60, "Y1"),
+	}
+	s := analysis.ComputeSchedule(issues, nil, analysis.ScheduleOptions{
+		Roster: []analysis.RosterAgent{{Name: "fe", Capacity: 3, Skills: []string{"frontend"}}},
+		Now:    schedNow,
+	})
+
+	if s.MakespanMinutes != 60 {
+		t.Errorf("makespan = %d, want 60 with three slots", s.MakespanMinutes)
+	}
+	if got := agentIssueIDs(s, "fe"); !reflect.DeepEqual(got, []string{"P1", "P2", "UI"}) {
+		t.Errorf("fe = %v, want [P1 P2 UI]", got)
+	}
+	reasons := map[string]string{}
+	for _, u := range s.Unscheduled {
+		reasons[u.IssueID] = u.Reason
+	}
+	for id, want := range map[string]string{"DB": "no roster agent", "AfterDB": "waits on", "Y1": "cycle", "Y2": "cycle"} {
+		if !strings.Contains(reasons[id], want) {
+			t.Errorf("unscheduled[%s] = %q, want it to mention %q", id, reasons[id], want)
+		}
+	}
+}
+
+func TestLoadRosterAndDefaultRoster(t *testing.T) {
+	dir := t.TempDir()
+	if roster, err := analysis.LoadRoster(dir); err != nil || roster != nil {
+		t.Fatalf("missing roster = %v, %v; want nil, nil", roster, err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, ".bv"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(analysis.RosterPath(dir), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("agents:\n  - name: ann\n    capacity: 2\n    skills: [api]\n  - name: bob\n")
+	roster, err := analysis.LoadRoster(dir)
+	if err != nil {
+		t.Fatalf("LoadRoster: %v", err)
+	}
+	want := []analysis.RosterAgent{{Name: "ann", Capacity: 2, Skills: []string{"api"}}, {Name: "bob"}}
+	if !reflect.DeepEqual(roster, want) {
+		t.Fatalf("roster = %+v, want %+v", roster, want)
+	}
+	write("agents:\n  - name: ann\n  - name: ann\n")
+	if _, err := analysis.LoadRoster(dir); err == nil || !strings.Contains(err.Error(), "duplicate") {
+		t.Errorf("expected a duplicate-agent error, got %v", err)
+	}
+
+	assigned := task("A", 60)
+	assigned.Assignee = "zed"
+	got := analysis.DefaultRoster([]model.Issue{assigned, task("B", 60)}, 3)
+	names := []string{}
+	for _, a := range got {
+		names = append(names, a.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"zed", "agent-1", "agent-2"}) {
+		t.Errorf("DefaultRoster = %v", names)
+	}
+}
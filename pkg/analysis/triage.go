@@ -69,6 +69,8 @@ type Recommendation struct {
 	Reasons     []string       `json:"reasons"`
 	UnblocksIDs []string       `json:"unblocks_ids,omitempty"`
 	BlockedBy   []string       `json:"blocked_by,omitempty"`
+	// SuggestedAgents is set when TriageOptions.Agents is given.
+	SuggestedAgents []AgentRecommendation `json:"suggested_agents,omitempty"`
 }
 
 // QuickWin represents a low-effort, high-impact item
@@ -294,6 +296,15 @@ type TriageOptions struct {
 	// bv-87: Track/label-aware recommendation grouping for multi-agent coordination
 	GroupByTrack bool // Group recommendations by execution track (connected component)
 	GroupByLabel bool // Group recommendations by primary label
+
+	// Agents, if set, adds the best-suited agents to each recommendation.
+	Agents *AgentReport
+
+	// Include, if set, limits recommendations, quick wins and blockers to
+	// the issues it accepts. Scores still come from the whole graph, so an
+	// excluded blocker keeps blocking, and an issue waiting on one is not
+	// recommended: the work that would unblock it is out of view.
+	Include func(id string) bool
 }
 
 // TrackRecommendationGroup groups recommendations by execution track (bv-87)
@@ -376,14 +387,43 @@ func ComputeTriageFromAnalyzer(analyzer *Analyzer, stats *GraphStats, issues []m
 	// Compute enhanced triage scores (bv-147)
 	triageScores := computeTriageScoresFromImpact(impactScores, unblocksMap, analyzer, DefaultTriageScoringOptions())
 
+	// Restrict candidates after scoring so normalisation sees every issue
+	blockerCandidates := unblocksMap
+	if opts.Include != nil {
+		candidate := func(id string) bool {
+			if !opts.Include(id) {
+				return false
+			}
+			for _, blocker := range analyzer.GetOpenBlockers(id) {
+				if !opts.Include(blocker) {
+					return false
+				}
+			}
+			return true
+		}
+		triageScores = includedTriageScores(triageScores, candidate)
+		impactScores = includedImpactScores(impactScores, candidate)
+		blockerCandidates = make(map[string][]string, len(unblocksMap))
+		for id, unblocks := range unblocksMap {
+			if opts.Include(id) {
+				blockerCandidates[id] = unblocks
+			}
+		}
+	}
+
 	// Build recommendations using enhanced scores (bv-148)
 	recommendations := buildRecommendationsFromTriageScores(triageScores, analyzer, unblocksMap, opts.TopN)
+	if opts.Agents != nil {
+		for i := range recommendations {
+			recommendations[i].SuggestedAgents = opts.Agents.RecommendAgents(analyzer.issueMap[recommendations[i].ID], 3)
+		}
+	}
 
 	// Build quick wins
 	quickWins := buildQuickWins(impactScores, unblocksMap, opts.QuickWinN)
 
 	// Build blockers to clear
-	blockersToClear := buildBlockersToClear(analyzer, unblocksMap, opts.BlockerN)
+	blockersToClear := buildBlockersToClear(analyzer, blockerCandidates, opts.BlockerN)
 
 	// Build top picks for quick ref
 	topPicks := buildTopPicks(recommendations, 3)
@@ -437,6 +477,28 @@ func ComputeTriageFromAnalyzer(analyzer *Analyzer, stats *GraphStats, issues []m
 	}
 }
 
+// includedTriageScores keeps the scores whose issue include accepts
+func includedTriageScores(scores []TriageScore, include func(id string) bool) []TriageScore {
+	kept := make([]TriageScore, 0, len(scores))
+	for _, s := range scores {
+		if include(s.IssueID) {
+			kept = append(kept, s)
+		}
+	}
+	return kept
+}
+
+// includedImpactScores keeps the scores whose issue include accepts
+func includedImpactScores(scores []ImpactScore, include func(id string) bool) []ImpactScore {
+	kept := make([]ImpactScore, 0, len(scores))
+	for _, s := range scores {
+		if include(s.IssueID) {
+			kept = append(kept, s)
+		}
+	}
+	return kept
+}
+
 // buildUnblocksMap computes what each issue unblocks
 func buildUnblocksMap(analyzer *Analyzer, issues []model.Issue) map[string][]string {
 	// O(E) unblocks computation.
@@ -1276,3 +1276,26 @@ func TestComputeTriageFromAnalyzer_Empty(t *testing.T) {
 		t.Errorf("expected 0 recommendations, got %d", len(triage.Recommendations))
 	}
 }
+
+func TestComputeTriage_IncludeKeepsOutsideBlockers(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "bd-1", Title: "API endpoint", Status: model.StatusOpen, Priority: 0, Labels: []string{"api"},
+			Dependencies: []*model.Dependency{{IssueID: "bd-1", DependsOnID: "bd-2", Type: model.DepBlocks}}},
+		{ID: "bd-2", Title: "Schema migration", Status: model.StatusOpen, Priority: 2},
+		{ID: "bd-3", Title: "API docs", Status: model.StatusOpen, Priority: 3, Labels: []string{"api"}},
+	}
+	api := func(id string) bool { return id != "bd-2" }
+
+	triage := ComputeTriageWithOptions(issues, TriageOptions{Include: api})
+	if len(triage.Recommendations) != 1 || triage.Recommendations[0].ID != "bd-3" {
+		t.Errorf("recommendations = %+v, want only bd-3", triage.Recommendations)
+	}
+	if triage.ProjectHealth.Counts.Total != 3 || triage.QuickRef.BlockedCount != 1 {
+		t.Errorf("project counts should cover every issue: %+v", triage.ProjectHealth.Counts)
+	}
+	for _, b := range triage.BlockersToClear {
+		if b.ID == "bd-2" {
+			t.Errorf("excluded blocker listed: %+v", b)
+		}
+	}
+}
@@ -0,0 +1,303 @@
+// Package beadid recognises bead ID mentions in free text such as commit
+// messages and agent session transcripts.
+//
+// Recognition is driven by the ID prefixes present in the loaded issues
+// (bd-a3f8 → "bd"), so hash IDs (bd-a3f8) and hierarchical child IDs
+// (bd-a3f8.2) are found alongside classic numeric ones (bv-123). Extra
+// prefixes, regex patterns and commit trailer keys can be declared in
+// .bv/correlation.yaml.
+package beadid
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFilename is the recogniser config file inside .bv/
+const ConfigFilename = "correlation.yaml"
+
+// genericIDExpr is used when no prefixes are known: a letter-led prefix and a
+// body containing at least one digit, so plain hyphenated words don't match.
+const genericIDExpr = `[A-Za-z][A-Za-z0-9_]*-[A-Za-z0-9]*[0-9][A-Za-z0-9]*(?:\.[0-9]+)*`
+
+// Config is the contents of .bv/correlation.yaml
+type Config struct {
+	// Prefixes are recognised in addition to those found in the issues.
+	Prefixes []string `yaml:"prefixes,omitempty" json:"prefixes,omitempty"`
+	// Patterns are extra regexes. The first capture group is the ID, or the
+	// whole match when the pattern has no groups.
+	Patterns []string `yaml:"patterns,omitempty" json:"patterns,omitempty"`
+	// Trailers are commit trailer keys (e.g. "Bead", "Refs-Bead") whose
+	// values are taken as bead IDs verbatim.
+	Trailers []string `yaml:"trailers,omitempty" json:"trailers,omitempty"`
+}
+
+// ConfigPath returns the recogniser config path for a project
+func ConfigPath(projectDir string) string {
+	return filepath.Join(projectDir, ".bv", ConfigFilename)
+}
+
+// LoadConfig reads .bv/correlation.yaml. A missing file yields an empty config.
+func LoadConfig(projectDir string) (Config, error) {
+	var cfg Config
+	path := ConfigPath(projectDir)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return cfg, fmt.Errorf("reading %s: %w", path, err)
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// ForProject builds a recogniser for ids with projectDir's
+// .bv/correlation.yaml.
+func ForProject(projectDir string, ids []string) (*Recognizer, error) {
+	cfg, err := LoadConfig(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	r, err := New(ids, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ConfigPath(projectDir), err)
+	}
+	return r, nil
+}
+
+// Source says how a mention was found
+type Source string
+
+const (
+	SourceID      Source = "id"      // matched the ID shape
+	SourceTrailer Source = "trailer" // value of a configured commit trailer
+	SourcePattern Source = "pattern" // matched a configured extra pattern
+)
+
+// Match is one bead ID mention
+type Match struct {
+	ID     string // canonical ID (as loaded when known, otherwise lower-cased)
+	Raw    string // text that matched
+	Source Source
+	Start  int // byte offset in the text
+}
+
+// Recognizer finds bead ID mentions in text
+type Recognizer struct {
+	prefixes  []string
+	idExpr    string
+	idRe      *regexp.Regexp
+	patterns  []*regexp.Regexp
+	trailerRe *regexp.Regexp
+	canonical map[string]string // lower-cased ID -> ID as loaded
+}
+
+// PrefixOf returns the prefix of an ID: everything before the last hyphen
+// ("bd-a3f8.2" → "bd", "my-proj-12" → "my-proj"), or "" if there is none.
+func PrefixOf(id string) string {
+	idx := strings.LastIndex(id, "-")
+	if idx <= 0 || idx == len(id)-1 {
+		return ""
+	}
+	return id[:idx]
+}
+
+// New builds a recogniser for the given issue IDs: their prefixes plus
+// cfg.Prefixes form the ID shape, and known IDs keep their original case.
+// With no prefixes at all it falls back to a generic PREFIX-123 shape.
+func New(ids []string, cfg Config) (*Recognizer, error) {
+	r := &Recognizer{canonical: make(map[string]string, len(ids))}
+
+	seen := make(map[string]bool)
+	addPrefix := func(p string) {
+		p = strings.TrimSpace(strings.TrimSuffix(p, "-"))
+		if p == "" || seen[strings.ToLower(p)] {
+			return
+		}
+		seen[strings.ToLower(p)] = true
+		r.prefixes = append(r.prefixes, p)
+	}
+	for _, id := range ids {
+		r.canonical[strings.ToLower(id)] = id
+		addPrefix(PrefixOf(id))
+	}
+	for _, p := range cfg.Prefixes {
+		addPrefix(p)
+	}
+	// Longest first so "bd-sub" wins over "bd" in the alternation.
+	sort.Slice(r.prefixes, func(i, j int) bool {
+		if len(r.prefixes[i]) != len(r.prefixes[j]) {
+			return len(r.prefixes[i]) > len(r.prefixes[j])
+		}
+		return r.prefixes[i] < r.prefixes[j]
+	})
+
+	if len(r.prefixes) == 0 {
+		r.idExpr = genericIDExpr
+	} else {
+		quoted := make([]string, len(r.prefixes))
+		for i, p := range r.prefixes {
+			quoted[i] = regexp.QuoteMeta(p)
+		}
+		// An unknown body must contain a digit, like numeric and hash IDs
+		// do, so tool names such as bd-ack or bv-serve are not mistaken for
+		// beads. Known IDs without a digit are listed verbatim.
+		shape := `(?i:` + strings.Join(quoted, "|") + `)-[A-Za-z0-9]*[0-9][A-Za-z0-9]*`
+		var wordIDs []string
+		seenWord := make(map[string]bool)
+		for _, id := range ids {
+			base, _, _ := strings.Cut(id, ".")
+			prefix := PrefixOf(base)
+			if prefix == "" || strings.ContainsAny(base[len(prefix):], "0123456789") || seenWord[strings.ToLower(base)] {
+				continue
+			}
+			seenWord[strings.ToLower(base)] = true
+			wordIDs = append(wordIDs, regexp.QuoteMeta(base))
+		}
+		if len(wordIDs) > 0 {
+			sort.Slice(wordIDs, func(i, j int) bool { return len(wordIDs[i]) > len(wordIDs[j]) })
+			shape = `(?i:` + strings.Join(wordIDs, "|") + `)|` + shape
+		}
+		r.idExpr = `(?:` + shape + `)(?:\.[0-9]+)*`
+	}
+	r.idRe = regexp.MustCompile(`\b(` + r.idExpr + `)\b`)
+
+	for _, p := range cfg.Patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", p, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+
+	if len(cfg.Trailers) > 0 {
+		keys := make([]string, 0, len(cfg.Trailers))
+		for _, k := range cfg.Trailers {
+			k = strings.TrimSuffix(strings.TrimSpace(k), ":")
+			if k != "" {
+				keys = append(keys, regexp.QuoteMeta(k))
+			}
+		}
+		if len(keys) > 0 {
+			r.trailerRe = regexp.MustCompile(`(?im)^[ \t]*(?:` + strings.Join(keys, "|") + `)[ \t]*:[ \t]*(.+)$`)
+		}
+	}
+	return r, nil
+}
+
+// Default returns a recogniser with no known issues and no config
+func Default() *Recognizer {
+	r, _ := New(nil, Config{})
+	return r
+}
+
+// Prefixes returns the recognised prefixes, longest first
+func (r *Recognizer) Prefixes() []string {
+	return append([]string(nil), r.prefixes...)
+}
+
+// IDExpr returns the ID shape as a regex fragment without capture groups,
+// for embedding in larger patterns.
+func (r *Recognizer) IDExpr() string {
+	return r.idExpr
+}
+
+// Canonical returns id as loaded when it is a known issue, otherwise
+// lower-cased.
+func (r *Recognizer) Canonical(id string) string {
+	lower := strings.ToLower(id)
+	if c, ok := r.canonical[lower]; ok {
+		return c
+	}
+	return lower
+}
+
+// Known reports whether id belongs to one of the issues the recogniser was
+// built from.
+func (r *Recognizer) Known(id string) bool {
+	_, ok := r.canonical[strings.ToLower(id)]
+	return ok
+}
+
+// Find returns every distinct ID mentioned in text, in order of first
+// appearance.
+func (r *Recognizer) Find(text string) []Match {
+	var found []Match
+	for _, loc := range r.idRe.FindAllStringSubmatchIndex(text, -1) {
+		raw := text[loc[2]:loc[3]]
+		found = append(found, Match{ID: r.Canonical(raw), Raw: raw, Source: SourceID, Start: loc[2]})
+	}
+	for _, re := range r.patterns {
+		for _, loc := range re.FindAllStringSubmatchIndex(text, -1) {
+			start, end := loc[0], loc[1]
+			if len(loc) >= 4 && loc[2] >= 0 {
+				start, end = loc[2], loc[3]
+			}
+			if start == end {
+				continue
+			}
+			raw := text[start:end]
+			found = append(found, Match{ID: r.Canonical(raw), Raw: raw, Source: SourcePattern, Start: start})
+		}
+	}
+	if r.trailerRe != nil {
+		for _, loc := range r.trailerRe.FindAllStringSubmatchIndex(text, -1) {
+			line := text[loc[0]:loc[1]]
+			offset := loc[2]
+			for _, field := range strings.FieldsFunc(text[loc[2]:loc[3]], func(c rune) bool {
+				return c == ',' || c == ' ' || c == '\t' || c == '\r'
+			}) {
+				id := strings.Trim(field, "#[]()")
+				if id == "" {
+					continue
+				}
+				found = append(found, Match{ID: r.Canonical(id), Raw: strings.TrimSpace(line), Source: SourceTrailer, Start: offset})
+			}
+		}
+	}
+
+	// Trailers are the most explicit source, then configured patterns.
+	rank := map[Source]int{SourceTrailer: 0, SourcePattern: 1, SourceID: 2}
+	sort.SliceStable(found, func(i, j int) bool {
+		if found[i].Start != found[j].Start {
+			return found[i].Start < found[j].Start
+		}
+		return rank[found[i].Source] < rank[found[j].Source]
+	})
+	seen := make(map[string]int, len(found))
+	out := found[:0]
+	for _, m := range found {
+		if i, ok := seen[m.ID]; ok {
+			if rank[m.Source] < rank[out[i].Source] {
+				out[i].Source, out[i].Raw = m.Source, m.Raw
+			}
+			continue
+		}
+		seen[m.ID] = len(out)
+		out = append(out, m)
+	}
+	return out
+}
+
+// FindIDs returns the distinct IDs mentioned in text, in order of first
+// appearance.
+func (r *Recognizer) FindIDs(text string) []string {
+	matches := r.Find(text)
+	if len(matches) == 0 {
+		return nil
+	}
+	ids := make([]string, len(matches))
+	for i, m := range matches {
+		ids[i] = m.ID
+	}
+	return ids
+}
@@ -0,0 +1,132 @@
+package beadid
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPrefixOf(t *testing.T) {
+	for id, want := range map[string]string{
+		"bd-a3f8":    "bd",
+		"bd-a3f8.2":  "bd",
+		"my-proj-12": "my-proj",
+		"nohyphen":   "",
+		"trailing-":  "",
+	} {
+		if got := PrefixOf(id); got != want {
+			t.Errorf("PrefixOf(%q) = %q, want %q", id, got, want)
+		}
+	}
+}
+
+func TestRecognizerDerivesPrefixesFromIssues(t *testing.T) {
+	r, err := New([]string{"bd-a3f8", "bd-a3f8.2", "bd-9k2", "web-ui-7"}, Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(r.Prefixes(), ","); got != "web-ui,bd" {
+		t.Errorf("prefixes = %s", got)
+	}
+
+	text := "Closes BD-A3F8.2, follows bd-a3f8. See web-ui-7 and bd-n3w; not co-author or JIRA-12."
+	got := strings.Join(r.FindIDs(text), " ")
+	if want := "bd-a3f8.2 bd-a3f8 web-ui-7 bd-n3w"; got != want {
+		t.Errorf("FindIDs = %q, want %q", got, want)
+	}
+	if !r.Known("BD-9K2") || r.Known("bd-n3w") {
+		t.Errorf("Known: loaded IDs only")
+	}
+}
+
+func TestRecognizerIgnoresToolNames(t *testing.T) {
+	r, err := New([]string{"bd-a3f8", "bd-cafe", "bv-12"}, Config{Prefixes: []string{"bd", "bv"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := "Ran bd-ack and bv-serve with bd-style output; refs bd-cafe.1 and bv-12."
+	got := strings.Join(r.FindIDs(text), " ")
+	if want := "bd-cafe.1 bv-12"; got != want {
+		t.Errorf("FindIDs = %q, want %q", got, want)
+	}
+}
+
+func TestRecognizerGenericFallback(t *testing.T) {
+	r := Default()
+	got := strings.Join(r.FindIDs("Fix AUTH-123 and bv-a3f8.1, a well-known re-run"), " ")
+	if want := "auth-123 bv-a3f8.1"; got != want {
+		t.Errorf("FindIDs = %q, want %q", got, want)
+	}
+}
+
+func TestRecognizerPatternsAndTrailers(t *testing.T) {
+	r, err := New([]string{"bd-a3f8"}, Config{
+		Patterns: []string{`TICKET#(\d+)`},
+		Trailers: []string{"Bead", "Refs-Bead:"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := "Tidy up parser for TICKET#42\n\nMentions bd-a3f8 in passing.\n\nBead: legacy7\nRefs-Bead: bd-a3f8, #other9\n"
+	matches := r.Find(msg)
+	var got []string
+	for _, m := range matches {
+		got = append(got, m.ID+"/"+string(m.Source))
+	}
+	if want := "42/pattern bd-a3f8/trailer legacy7/trailer other9/trailer"; strings.Join(got, " ") != want {
+		t.Errorf("Find = %v, want %s", got, want)
+	}
+
+	if _, err := New(nil, Config{Patterns: []string{"("}}); err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	cfg, err := LoadConfig(dir)
+	if err != nil || len(cfg.Prefixes)+len(cfg.Patterns)+len(cfg.Trailers) != 0 {
+		t.Fatalf("missing config = %+v, %v", cfg, err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, ".bv"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := "prefixes: [ops]\npatterns: ['TICKET#(\\d+)']\ntrailers: [Bead, Refs-Bead]\n"
+	if err := os.WriteFile(ConfigPath(dir), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = LoadConfig(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Prefixes[0] != "ops" || cfg.Patterns[0] != `TICKET#(\d+)` || len(cfg.Trailers) != 2 {
+		t.Errorf("config = %+v", cfg)
+	}
+}
+
+func TestForProject(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, ".bv"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(ConfigPath(dir), []byte("prefixes: [ops]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	r, err := ForProject(dir, []string{"bv-abc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := r.FindIDs("bv-abc and ops-12 but not bv-serve")
+	if len(got) != 2 || got[0] != "bv-abc" || got[1] != "ops-12" {
+		t.Errorf("FindIDs = %v", got)
+	}
+
+	if err := os.WriteFile(ConfigPath(dir), []byte("patterns: ['(']\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ForProject(dir, nil); err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+}
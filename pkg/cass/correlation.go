@@ -3,11 +3,11 @@ package cass
 import (
 	"context"
 	"path/filepath"
-	"regexp"
 	"strings"
 	"time"
 	"unicode"
 
+	"github.com/Dicklesworthstone/beads_viewer/pkg/beadid"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
 )
 
@@ -92,6 +92,10 @@ type Correlator struct {
 	cache     *Cache
 	workspace string // Project workspace path for filtering
 
+	// recognizer finds bead ID mentions in session snippets; nil falls
+	// back to bv- prefixed IDs
+	recognizer *beadid.Recognizer
+
 	// For testing: allow overriding time
 	now func() time.Time
 }
@@ -125,6 +129,12 @@ func NewCorrelatorWithOptions(searcher *Searcher, cache *Cache, opts ...Correlat
 	return c
 }
 
+// SetRecognizer sets the bead ID recogniser, normally built from the loaded
+// issues and the project's .bv/correlation.yaml.
+func (c *Correlator) SetRecognizer(r *beadid.Recognizer) {
+	c.recognizer = r
+}
+
 // GetCached returns the cached correlation hint for a bead without triggering a new search.
 // Returns nil if not cached or expired. This is useful for status bar indicators
 // where we don't want to block on network/process calls.
@@ -265,8 +275,14 @@ func (c *Correlator) searchByKeywords(ctx context.Context, issue *model.Issue, k
 
 	scored := make([]ScoredResult, 0, len(resp.Results))
 	for _, r := range resp.Results {
-		// Score based on how well keywords match
+		// Score based on how well keywords match, unless the hit names the
+		// bead itself (e.g. an ID form the literal search missed)
 		baseScore := c.scoreKeywordMatch(r, keywords)
+		strategy := StrategyKeywords
+		if c.mentions(r.Title+"\n"+r.Snippet, issue.ID) {
+			baseScore = float64(ScoreIDMention)
+			strategy = StrategyIDMention
+		}
 		score := c.applyTimeDecay(baseScore, r.Timestamp)
 		score = c.applyWorkspaceBoost(score, r.SourcePath)
 
@@ -276,7 +292,7 @@ func (c *Correlator) searchByKeywords(ctx context.Context, issue *model.Issue, k
 				SearchResult: r,
 				FinalScore:   score,
 				BaseScore:    baseScore,
-				Strategy:     StrategyKeywords,
+				Strategy:     strategy,
 				Keywords:     matchedKeywords,
 			})
 		}
@@ -622,25 +638,37 @@ func WorkspaceFromBeadsPath(beadsPath string) string {
 	return filepath.Dir(dir)              // → /path/to/project
 }
 
-// beadIDRegex matches bead IDs like "bv-abc123"
-var beadIDRegex = regexp.MustCompile(`\b(bv-[a-z0-9]+)\b`)
+// legacyRecognizer matches bv- prefixed IDs, for callers without the issues
+var legacyRecognizer, _ = beadid.New(nil, beadid.Config{Prefixes: []string{"bv"}})
 
-// FindBeadIDMentions finds all bead ID mentions in text.
+// FindBeadIDMentions finds all bv- bead ID mentions in text. IDs without a
+// digit are only found by a recogniser that knows them; see
+// Correlator.FindBeadIDMentions.
 func FindBeadIDMentions(text string) []string {
-	matches := beadIDRegex.FindAllString(text, -1)
-	if len(matches) == 0 {
-		return nil
+	return FindBeadIDMentionsWith(legacyRecognizer, text)
+}
+
+// FindBeadIDMentions finds the bead IDs mentioned in text using the
+// correlator's recogniser.
+func (c *Correlator) FindBeadIDMentions(text string) []string {
+	if c.recognizer == nil {
+		return FindBeadIDMentions(text)
 	}
+	return FindBeadIDMentionsWith(c.recognizer, text)
+}
 
-	// Dedupe
-	seen := make(map[string]bool)
-	unique := make([]string, 0, len(matches))
-	for _, m := range matches {
-		if !seen[m] {
-			seen[m] = true
-			unique = append(unique, m)
+// mentions reports whether text mentions beadID
+func (c *Correlator) mentions(text, beadID string) bool {
+	for _, id := range c.FindBeadIDMentions(text) {
+		if strings.EqualFold(id, beadID) {
+			return true
 		}
 	}
+	return false
+}
 
-	return unique
+// FindBeadIDMentionsWith finds all bead ID mentions in text that r
+// recognises, in order of first appearance.
+func FindBeadIDMentionsWith(r *beadid.Recognizer, text string) []string {
+	return r.FindIDs(text)
 }
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Dicklesworthstone/beads_viewer/pkg/beadid"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
 )
 
@@ -132,6 +133,34 @@ func TestFindBeadIDMentions(t *testing.T) {
 	}
 }
 
+func TestFindBeadIDMentionsWith(t *testing.T) {
+	r, err := beadid.New([]string{"bd-a3f8", "bd-a3f8.1"}, beadid.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := FindBeadIDMentionsWith(r, "Picked up bd-a3f8.1 after bd-a3f8; bv-abc123 is another project")
+	if len(got) != 2 || got[0] != "bd-a3f8.1" || got[1] != "bd-a3f8" {
+		t.Errorf("FindBeadIDMentionsWith = %v", got)
+	}
+}
+
+func TestCorrelatorFindBeadIDMentions(t *testing.T) {
+	c := NewCorrelator(nil, nil, "")
+	text := "Finished bv-abc, next is bv-def456"
+	if got := c.FindBeadIDMentions(text); len(got) != 1 || got[0] != "bv-def456" {
+		t.Errorf("without issues = %v", got)
+	}
+
+	r, err := beadid.New([]string{"bv-abc", "bv-def456"}, beadid.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.SetRecognizer(r)
+	if got := c.FindBeadIDMentions(text); len(got) != 2 || got[0] != "bv-abc" {
+		t.Errorf("with issues = %v", got)
+	}
+}
+
 func TestWorkspaceFromBeadsPath(t *testing.T) {
 	tests := []struct {
 		name     string
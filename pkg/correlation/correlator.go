@@ -8,6 +8,8 @@ import (
 	"os"
 	"path/filepath"
 	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/beadid"
 )
 
 // Correlator orchestrates the extraction and correlation of bead history data
@@ -15,6 +17,7 @@ type Correlator struct {
 	repoPath    string
 	extractor   *Extractor
 	coCommitter *CoCommitExtractor
+	recognizer  *beadid.Recognizer // nil = built per report from the beads and .bv/correlation.yaml
 }
 
 // NewCorrelator creates a new correlator for the given repository.
@@ -29,6 +32,12 @@ func NewCorrelator(repoPath string, beadsFilePath ...string) *Correlator {
 	}
 }
 
+// SetRecognizer sets the bead ID recogniser used to find explicit references
+// in commit messages.
+func (c *Correlator) SetRecognizer(r *beadid.Recognizer) {
+	c.recognizer = r
+}
+
 // CorrelatorOptions controls how the history report is generated
 type CorrelatorOptions struct {
 	BeadID string     // Filter to single bead ID (empty = all)
@@ -59,6 +68,9 @@ func (c *Correlator) GenerateReport(beads []BeadInfo, opts CorrelatorOptions) (*
 		return nil, fmt.Errorf("extracting co-commits: %w", err)
 	}
 
+	// Add commits whose messages reference a bead
+	commits = append(commits, c.explicitCommits(beads, extractOpts, commits)...)
+
 	// Build bead histories
 	histories := c.buildHistories(beads, events, commits)
 
@@ -121,6 +133,48 @@ func (c *Correlator) findLatestCommitSHA(events []BeadEvent, commits []Correlate
 	return latestSHA
 }
 
+// explicitCommits returns the commits whose messages reference one of beads,
+// skipping those already correlated to the same bead. Explicit references
+// supplement the beads-file history, so a failed scan yields none.
+func (c *Correlator) explicitCommits(beads []BeadInfo, opts ExtractOptions, existing []CorrelatedCommit) []CorrelatedCommit {
+	if len(beads) == 0 {
+		return nil
+	}
+	ids := make([]string, len(beads))
+	for i, bead := range beads {
+		ids[i] = bead.ID
+	}
+
+	recognizer := c.recognizer
+	if recognizer == nil {
+		var err error
+		if recognizer, err = beadid.ForProject(c.repoPath, ids); err != nil {
+			recognizer, _ = beadid.New(ids, beadid.Config{}) // no patterns, cannot fail
+		}
+	}
+
+	matcher := NewExplicitMatcherWithRecognizer(c.repoPath, recognizer)
+	matches, err := matcher.ScanCommits(ids, opts)
+	if err != nil {
+		return nil
+	}
+
+	seen := make(map[string]bool, len(existing))
+	for _, commit := range existing {
+		seen[commit.BeadID+"\x00"+commit.SHA] = true
+	}
+	var commits []CorrelatedCommit
+	for _, match := range matches {
+		key := match.BeadID + "\x00" + match.CommitSHA
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		commits = append(commits, matcher.CreateCorrelatedCommit(match, c.coCommitter))
+	}
+	return commits
+}
+
 // BeadInfo is minimal bead information needed for correlation
 type BeadInfo struct {
 	ID     string
@@ -1,6 +1,9 @@
 package correlation
 
 import (
+	"os"
+	"os/exec"
+	"path/filepath"
 	"testing"
 	"time"
 )
@@ -390,3 +393,59 @@ func TestDescribeGitRange_Combined(t *testing.T) {
 		t.Errorf("unexpected result: %s", result)
 	}
 }
+
+func TestGenerateReport_ExplicitReferences(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	repo := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(rel, content string) {
+		t.Helper()
+		path := filepath.Join(repo, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git("init")
+	write(".beads/beads.jsonl", `{"id":"bd-abc","title":"Parser","status":"open"}`+"\n"+`{"id":"bd-a3f8","title":"Lexer","status":"open"}`+"\n")
+	write(".bv/correlation.yaml", "trailers: [Bead]\n")
+	git("add", "-A")
+	git("commit", "-m", "add beads")
+	write("parser.go", "package parser\n")
+	git("add", "-A")
+	git("commit", "-m", "wire up parser", "-m", "Refs bd-abc")
+	write("lexer.go", "package parser\n")
+	git("add", "-A")
+	git("commit", "-m", "lexer", "-m", "Bead: bd-a3f8")
+
+	beads := []BeadInfo{{ID: "bd-abc", Title: "Parser"}, {ID: "bd-a3f8", Title: "Lexer"}}
+	report, err := NewCorrelator(repo).GenerateReport(beads, CorrelatorOptions{})
+	if err != nil {
+		t.Fatalf("GenerateReport: %v", err)
+	}
+	for id, want := range map[string]string{"bd-abc": "wire up parser", "bd-a3f8": "lexer"} {
+		commits := report.Histories[id].Commits
+		if len(commits) != 1 || commits[0].Message != want || commits[0].Method != MethodExplicitID {
+			t.Errorf("%s commits = %+v, want the %q commit", id, commits, want)
+			continue
+		}
+		if len(commits[0].Files) != 1 {
+			t.Errorf("%s files = %+v", id, commits[0].Files)
+		}
+	}
+}
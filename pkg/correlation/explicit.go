@@ -9,34 +9,43 @@ import (
 	"regexp"
 	"strings"
 	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/beadid"
 )
 
 // ExplicitMatcher finds commits that explicitly reference bead IDs in messages.
 type ExplicitMatcher struct {
-	repoPath string
-	patterns []*regexp.Regexp
+	repoPath   string
+	patterns   []*regexp.Regexp
+	recognizer *beadid.Recognizer // optional; finds mentions the patterns miss
 }
 
-// DefaultPatterns returns the default set of bead ID patterns.
-func DefaultPatterns() []*regexp.Regexp {
+// PatternsFor returns the bracket and keyword patterns for IDs matching
+// idExpr, a regex fragment without capture groups.
+func PatternsFor(idExpr string) []*regexp.Regexp {
 	return []*regexp.Regexp{
 		// [ID] format - very explicit
-		regexp.MustCompile(`\[([A-Za-z]+-\d+)\]`),
+		regexp.MustCompile(`\[(` + idExpr + `)\]`),
 
 		// Closes/Fixes/Refs keywords with optional # prefix
 		// Note: Allow optional colon and whitespace after keyword
-		regexp.MustCompile(`(?i)closes?:?\s*#?([A-Za-z]+-\d+)`),
-		regexp.MustCompile(`(?i)fix(?:es|ed)?:?\s*#?([A-Za-z]+-\d+)`),
-		regexp.MustCompile(`(?i)refs?:?\s*#?([A-Za-z]+-\d+)`),
-		regexp.MustCompile(`(?i)resolves?:?\s*#?([A-Za-z]+-\d+)`),
+		regexp.MustCompile(`(?i)closes?:?\s*#?(` + idExpr + `)`),
+		regexp.MustCompile(`(?i)fix(?:es|ed)?:?\s*#?(` + idExpr + `)`),
+		regexp.MustCompile(`(?i)refs?:?\s*#?(` + idExpr + `)`),
+		regexp.MustCompile(`(?i)resolves?:?\s*#?(` + idExpr + `)`),
+	}
+}
 
+// DefaultPatterns returns the default set of bead ID patterns.
+func DefaultPatterns() []*regexp.Regexp {
+	return append(PatternsFor(`[A-Za-z]+-\d+`),
 		// beads-123 or bead-123 format (common for this project)
 		regexp.MustCompile(`(?i)beads?[-_](\d+)`),
 		regexp.MustCompile(`(?i)bv[-_](\d+)`),
 
 		// Generic ID at word boundary (PROJECT-123 style)
 		regexp.MustCompile(`\b([A-Z]{2,10}-\d+)\b`),
-	}
+	)
 }
 
 // NewExplicitMatcher creates a new explicit matcher with default patterns.
@@ -55,6 +64,17 @@ func NewExplicitMatcherWithPatterns(repoPath string, patterns []*regexp.Regexp)
 	}
 }
 
+// NewExplicitMatcherWithRecognizer creates a matcher that recognises IDs the
+// way r does: keyword and bracket forms are classified, and any other mention
+// r finds (including hash IDs, child IDs and trailers) is matched too.
+func NewExplicitMatcherWithRecognizer(repoPath string, r *beadid.Recognizer) *ExplicitMatcher {
+	return &ExplicitMatcher{
+		repoPath:   repoPath,
+		patterns:   PatternsFor(r.IDExpr()),
+		recognizer: r,
+	}
+}
+
 // AddPattern adds a custom pattern to the matcher.
 func (m *ExplicitMatcher) AddPattern(pattern *regexp.Regexp) {
 	m.patterns = append(m.patterns, pattern)
@@ -68,7 +88,7 @@ type ExplicitMatch struct {
 	Author      string
 	AuthorEmail string
 	Timestamp   time.Time
-	MatchType   string // "closes", "fixes", "refs", "bracket", "generic"
+	MatchType   string // "closes", "fixes", "refs", "bracket", "trailer", "generic"
 	Confidence  float64
 }
 
@@ -82,7 +102,7 @@ func (m *ExplicitMatcher) ExtractIDsFromMessage(message string) []IDMatch {
 		found := pattern.FindAllStringSubmatch(message, -1)
 		for _, match := range found {
 			if len(match) >= 2 {
-				id := normalizeBeadID(match[1])
+				id := m.normalize(match[1])
 				if !seen[id] {
 					seen[id] = true
 					matchType := classifyMatch(match[0])
@@ -96,9 +116,35 @@ func (m *ExplicitMatcher) ExtractIDsFromMessage(message string) []IDMatch {
 		}
 	}
 
+	if m.recognizer != nil {
+		for _, found := range m.recognizer.Find(message) {
+			if seen[found.ID] {
+				continue
+			}
+			seen[found.ID] = true
+			matchType := classifyMatch(found.Raw)
+			if found.Source == beadid.SourceTrailer {
+				matchType = "trailer"
+			}
+			matches = append(matches, IDMatch{
+				ID:        found.ID,
+				MatchType: matchType,
+				RawMatch:  found.Raw,
+			})
+		}
+	}
+
 	return matches
 }
 
+// normalize maps a matched ID to its canonical form
+func (m *ExplicitMatcher) normalize(id string) string {
+	if m.recognizer != nil {
+		return m.recognizer.Canonical(id)
+	}
+	return normalizeBeadID(id)
+}
+
 // IDMatch represents a single ID match from a message.
 type IDMatch struct {
 	ID        string
@@ -144,7 +190,7 @@ func CalculateConfidence(matchType string, totalMatches int) float64 {
 
 	// Bonus for action keywords
 	switch matchType {
-	case "closes", "fixes", "resolves":
+	case "closes", "fixes", "resolves", "trailer":
 		base += 0.05 // Strong intent signal
 	case "bracket":
 		base += 0.02 // Explicit but no action
@@ -352,6 +398,71 @@ func (m *ExplicitMatcher) CreateCorrelatedCommit(match ExplicitMatch, coCommitte
 	}
 }
 
+// ScanCommits reads the commit log once and returns every explicit reference
+// to one of beadIDs, newest commit first. Subjects and bodies are both
+// searched, so trailers are found; mentions of unknown IDs are ignored.
+func (m *ExplicitMatcher) ScanCommits(beadIDs []string, opts ExtractOptions) ([]ExplicitMatch, error) {
+	known := make(map[string]string, len(beadIDs))
+	for _, id := range beadIDs {
+		known[strings.ToLower(id)] = id
+	}
+
+	args := []string{"log", "--format=" + gitLogMessageFormat}
+	if opts.Since != nil {
+		args = append(args, fmt.Sprintf("--since=%s", opts.Since.Format(time.RFC3339)))
+	}
+	if opts.Until != nil {
+		args = append(args, fmt.Sprintf("--until=%s", opts.Until.Format(time.RFC3339)))
+	}
+	if opts.Limit > 0 {
+		args = append(args, fmt.Sprintf("-n%d", opts.Limit))
+	}
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = m.repoPath
+
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return nil, fmt.Errorf("git log failed: %s", string(exitErr.Stderr))
+		}
+		return nil, fmt.Errorf("git log failed: %w", err)
+	}
+
+	var matches []ExplicitMatch
+	for _, record := range strings.Split(string(out), "\x1e") {
+		record = strings.TrimLeft(record, "\n")
+		parts := strings.SplitN(record, "\x00", 6)
+		if len(parts) != 6 {
+			continue
+		}
+		info, err := parseCommitInfo(strings.Join(parts[:5], "\x00"))
+		if err != nil {
+			continue
+		}
+
+		idMatches := m.ExtractIDsFromMessage(info.Message + "\n\n" + parts[5])
+		for _, idMatch := range idMatches {
+			beadID, ok := known[strings.ToLower(idMatch.ID)]
+			if !ok || (opts.BeadID != "" && beadID != opts.BeadID) {
+				continue
+			}
+			matches = append(matches, ExplicitMatch{
+				BeadID:      beadID,
+				CommitSHA:   info.SHA,
+				Message:     info.Message,
+				Author:      info.Author,
+				AuthorEmail: info.AuthorEmail,
+				Timestamp:   info.Timestamp,
+				MatchType:   idMatch.MatchType,
+				Confidence:  CalculateConfidence(idMatch.MatchType, len(idMatches)),
+			})
+		}
+	}
+
+	return matches, nil
+}
+
 // FindAllExplicitMatches finds explicit references for all known bead IDs.
 func (m *ExplicitMatcher) FindAllExplicitMatches(beadIDs []string, opts ExtractOptions) (map[string][]ExplicitMatch, error) {
 	results := make(map[string][]ExplicitMatch)
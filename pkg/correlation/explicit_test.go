@@ -3,6 +3,8 @@ package correlation
 import (
 	"regexp"
 	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/beadid"
 )
 
 func TestExtractIDsFromMessage(t *testing.T) {
@@ -295,3 +297,35 @@ func TestDuplicateIDsInMessage(t *testing.T) {
 		t.Errorf("expected 1 unique match, got %d", len(matches))
 	}
 }
+
+func TestExtractIDsWithRecognizer(t *testing.T) {
+	r, err := beadid.New([]string{"bd-a3f8", "bd-a3f8.2", "bd-c01d"}, beadid.Config{Trailers: []string{"Refs-Bead"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewExplicitMatcherWithRecognizer("/tmp/test", r)
+
+	message := "Fixes bd-a3f8.2 [BD-C01D]\n\nAlso touches bd-a3f8.\n\nRefs-Bead: bd-9zz9"
+	matches := m.ExtractIDsFromMessage(message)
+
+	want := map[string]string{
+		"bd-a3f8.2": "fixes",
+		"bd-c01d":   "bracket",
+		"bd-9zz9":   "trailer",
+		"bd-a3f8":   "generic",
+	}
+	if len(matches) != len(want) {
+		t.Fatalf("matches = %+v, want %d", matches, len(want))
+	}
+	for _, match := range matches {
+		if want[match.ID] != match.MatchType {
+			t.Errorf("%s matched as %q, want %q", match.ID, match.MatchType, want[match.ID])
+		}
+	}
+	if got := m.ExtractIDsFromMessage("Fix bd-ack crash, switch to bd-style output for bv-serve"); len(got) != 0 {
+		t.Errorf("tool names matched as beads: %+v", got)
+	}
+	if c := CalculateConfidence("trailer", 1); c < 0.95 {
+		t.Errorf("trailer confidence = %v, want a strong signal", c)
+	}
+}
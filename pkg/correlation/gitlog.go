@@ -3,6 +3,10 @@ package correlation
 const (
 	gitLogHeaderFormat = "%H%x00%aI%x00%an%x00%ae%x00%s"
 
+	// gitLogMessageFormat adds the message body to the header and ends each
+	// commit with a record separator, since bodies span several lines.
+	gitLogMessageFormat = gitLogHeaderFormat + "%x00%b%x1e"
+
 	// gitLogMaxScanTokenSize matches the loader and stream limits; it prevents
 	// bufio.Scanner from failing on unusually long lines.
 	gitLogMaxScanTokenSize = 10 * 1024 * 1024 // 10MB
@@ -7,6 +7,8 @@ import (
 	"sort"
 	"strings"
 	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/beadid"
 )
 
 // OrphanSignal represents a reason why a commit might be orphaned.
@@ -37,14 +39,13 @@ var (
 		{regexp.MustCompile(`\b(add|adds|added)\b`), 5},
 		{regexp.MustCompile(`#\d+`), 15},               // Issue number reference
 		{regexp.MustCompile(`\b[a-z]{2,5}-\d+\b`), 20}, // JIRA-style ID (lowercase since message is lowercased)
-		{regexp.MustCompile(`\bbv-[a-z0-9]+\b`), 25},   // bv-xxx pattern
 		{regexp.MustCompile(`\bbeads?[-_]?\d+\b`), 25}, // bead-123 pattern
 	}
-
-	// Pattern for extracting specific bead IDs from messages
-	orphanBeadIDPattern = regexp.MustCompile(`(?i)\bbv-([a-z0-9]{4,8})\b`) // Case-insensitive
 )
 
+// orphanBeadIDWeight is the message signal weight for a recognised bead ID
+const orphanBeadIDWeight = 25
+
 // OrphanCandidate represents a commit that might be missing a bead linkage.
 type OrphanCandidate struct {
 	// Commit information
@@ -105,6 +106,7 @@ type OrphanDetector struct {
 	fileLookup  *FileLookup
 	beadWindows map[string]TemporalWindow // BeadID -> active time window
 	authorBeads map[string][]string       // Author email -> BeadIDs they worked on
+	recognizer  *beadid.Recognizer        // Finds bead ID mentions in messages
 }
 
 // NewOrphanDetector creates a detector from a history report.
@@ -127,6 +129,13 @@ func newOrphanDetector(report *HistoryReport, repoPath string) *OrphanDetector {
 		authorBeads: make(map[string][]string),
 	}
 
+	// Recognise IDs by the prefixes of the beads in the report.
+	ids := make([]string, 0, len(report.Histories))
+	for beadID := range report.Histories {
+		ids = append(ids, beadID)
+	}
+	od.recognizer, _ = beadid.New(ids, beadid.Config{}) // no patterns, cannot fail
+
 	// Build temporal windows for each bead
 	for beadID, history := range report.Histories {
 		if history.Milestones.Claimed != nil {
@@ -156,6 +165,14 @@ func newOrphanDetector(report *HistoryReport, repoPath string) *OrphanDetector {
 	return od
 }
 
+// SetRecognizer replaces the bead ID recogniser, e.g. with one that also
+// carries the patterns and trailers from .bv/correlation.yaml.
+func (od *OrphanDetector) SetRecognizer(r *beadid.Recognizer) {
+	if r != nil {
+		od.recognizer = r
+	}
+}
+
 // DetectOrphans finds orphan commits with smart detection.
 func (od *OrphanDetector) DetectOrphans(opts ExtractOptions) (*OrphanReport, error) {
 	// Get basic orphans first
@@ -359,6 +376,12 @@ func (od *OrphanDetector) checkMessage(candidate *OrphanCandidate, beadScores ma
 		}
 	}
 
+	mentions := od.recognizer.Find(candidate.Message)
+	if len(mentions) > 0 {
+		totalWeight += orphanBeadIDWeight
+		matchDetails = append(matchDetails, mentions[0].Raw)
+	}
+
 	if totalWeight > 0 {
 		candidate.Signals = append(candidate.Signals, OrphanSignalHit{
 			Signal:  SignalOrphanMessage,
@@ -367,22 +390,22 @@ func (od *OrphanDetector) checkMessage(candidate *OrphanCandidate, beadScores ma
 		})
 	}
 
-	// Try to match specific bead IDs mentioned in message (case-insensitive)
-	matches := orphanBeadIDPattern.FindAllStringSubmatch(msg, -1)
-	for _, match := range matches {
-		if len(match) >= 2 {
-			beadID := "bv-" + strings.ToLower(match[1]) // Normalize to lowercase
-			if history, ok := od.lookup.beads[beadID]; ok {
-				if _, exists := beadScores[beadID]; !exists {
-					beadScores[beadID] = &probableBeadBuilder{
-						title:  history.Title,
-						status: history.Status,
-					}
+	// Link the specific bead IDs mentioned in the message
+	for _, mention := range mentions {
+		beadID := mention.ID
+		if history, ok := od.lookup.beads[beadID]; ok {
+			if _, exists := beadScores[beadID]; !exists {
+				beadScores[beadID] = &probableBeadBuilder{
+					title:  history.Title,
+					status: history.Status,
 				}
-				beadScores[beadID].score += 35
-				beadScores[beadID].reasons = append(beadScores[beadID].reasons,
-					"bead ID mentioned in commit message")
 			}
+			beadScores[beadID].score += 35
+			reason := "bead ID mentioned in commit message"
+			if mention.Source == beadid.SourceTrailer {
+				reason = "bead ID in commit trailer"
+			}
+			beadScores[beadID].reasons = append(beadScores[beadID].reasons, reason)
 		}
 	}
 }
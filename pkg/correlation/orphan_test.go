@@ -3,6 +3,8 @@ package correlation
 import (
 	"testing"
 	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/beadid"
 )
 
 func TestNewOrphanDetector(t *testing.T) {
@@ -54,6 +56,40 @@ func TestNewOrphanDetector(t *testing.T) {
 	}
 }
 
+func TestOrphanCheckMessageRecognizesHashIDs(t *testing.T) {
+	report := &HistoryReport{
+		Histories: map[string]BeadHistory{
+			"bd-a3f8":   {Title: "Parent", Status: "open"},
+			"bd-a3f8.2": {Title: "Child", Status: "in_progress"},
+		},
+		CommitIndex: map[string][]string{},
+	}
+	od := NewOrphanDetector(report, "")
+
+	candidate := OrphanCandidate{Message: "Tighten retry loop for BD-A3F8.2"}
+	scores := make(map[string]*probableBeadBuilder)
+	od.checkMessage(&candidate, scores)
+	if scores["bd-a3f8.2"] == nil || scores["bd-a3f8"] != nil {
+		t.Fatalf("expected only the child bead to be linked, got %v", scores)
+	}
+	if len(candidate.Signals) != 1 || candidate.Signals[0].Signal != SignalOrphanMessage {
+		t.Errorf("signals = %+v", candidate.Signals)
+	}
+
+	// Trailers come from .bv/correlation.yaml via SetRecognizer.
+	r, err := beadid.New([]string{"bd-a3f8"}, beadid.Config{Trailers: []string{"Bead"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	od.SetRecognizer(r)
+	candidate = OrphanCandidate{Message: "Refactor\n\nBead: bd-a3f8"}
+	scores = make(map[string]*probableBeadBuilder)
+	od.checkMessage(&candidate, scores)
+	if b := scores["bd-a3f8"]; b == nil || b.reasons[0] != "bead ID in commit trailer" {
+		t.Errorf("trailer link = %+v", b)
+	}
+}
+
 func TestNewSmartOrphanDetector(t *testing.T) {
 	report := &HistoryReport{
 		Histories:   make(map[string]BeadHistory),
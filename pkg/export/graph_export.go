@@ -62,6 +62,11 @@ type AdjacencyNode struct {
 	Priority int      `json:"priority"`
 	Labels   []string `json:"labels,omitempty"`
 	PageRank float64  `json:"pagerank,omitempty"`
+
+	// Duration-weighted schedule (open issues only)
+	EstimatedMinutes int  `json:"estimated_minutes,omitempty"`
+	SlackMinutes     *int `json:"slack_minutes,omitempty"`
+	Critical         bool `json:"critical,omitempty"` // on the schedule-critical chain
 }
 
 // AdjacencyEdge represents an edge in the adjacency graph.
@@ -69,6 +74,8 @@ type AdjacencyEdge struct {
 	From string `json:"from"`
 	To   string `json:"to"`
 	Type string `json:"type"` // "blocks" or "related"
+
+	Critical bool `json:"critical,omitempty"` // joins consecutive issues on the critical chain
 }
 
 // ExportGraph exports the dependency graph in the specified format.
@@ -116,6 +123,8 @@ func ExportGraph(issues []model.Issue, stats *analysis.GraphStats, config GraphE
 		filtersApplied["depth"] = fmt.Sprintf("%d", config.Depth)
 	}
 
+	schedule := analysis.ComputeWeightedCriticalPath(filteredIssues)
+
 	result := &GraphExportResult{
 		Format:         string(config.Format),
 		Nodes:          len(filteredIssues),
@@ -126,7 +135,7 @@ func ExportGraph(issues []model.Issue, stats *analysis.GraphStats, config GraphE
 
 	switch config.Format {
 	case GraphFormatDOT:
-		graph := generateDOT(filteredIssues, issueIDs, stats)
+		graph := generateDOT(filteredIssues, issueIDs, stats, schedule)
 		result.Graph = graph
 		result.Explanation = GraphExplanation{
 			What:        "Dependency graph in Graphviz DOT format",
@@ -147,7 +156,7 @@ func ExportGraph(issues []model.Issue, stats *analysis.GraphStats, config GraphE
 		fallthrough
 	default:
 		result.Format = "json"
-		adjacency := generateAdjacency(filteredIssues, issueIDs, stats)
+		adjacency := generateAdjacency(filteredIssues, issueIDs, stats, schedule)
 		result.Adjacency = adjacency
 		result.Explanation = GraphExplanation{
 			What:      "Dependency graph as JSON adjacency list",
@@ -237,7 +246,7 @@ func extractSubgraph(issues []model.Issue, rootID string, maxDepth int) []model.
 }
 
 // generateDOT creates a Graphviz DOT format graph.
-func generateDOT(issues []model.Issue, issueIDs map[string]bool, stats *analysis.GraphStats) string {
+func generateDOT(issues []model.Issue, issueIDs map[string]bool, stats *analysis.GraphStats, schedule *analysis.WeightedCriticalPath) string {
 	var sb strings.Builder
 
 	sb.WriteString("digraph G {\n")
@@ -295,7 +304,8 @@ func generateDOT(issues []model.Issue, issueIDs map[string]bool, stats *analysis
 
 	sb.WriteString("\n")
 
-	// Edges
+	// Edges; the schedule-critical chain is drawn thicker
+	critical := criticalChainEdges(schedule)
 	for _, i := range sortedIssues {
 		// Sort dependencies for deterministic output
 		deps := make([]*model.Dependency, len(i.Dependencies))
@@ -322,6 +332,11 @@ func generateDOT(issues []model.Issue, issueIDs map[string]bool, stats *analysis
 				color = "#E53935" // Red for blocking
 			}
 
+			if critical[[2]string{i.ID, dep.DependsOnID}] {
+				sb.WriteString(fmt.Sprintf("    \"%s\" -> \"%s\" [style=%s, color=\"%s\", penwidth=3.0];\n",
+					sanitizeDOTID(i.ID), sanitizeDOTID(dep.DependsOnID), style, color))
+				continue
+			}
 			sb.WriteString(fmt.Sprintf("    \"%s\" -> \"%s\" [style=%s, color=\"%s\"];\n",
 				sanitizeDOTID(i.ID), sanitizeDOTID(dep.DependsOnID), style, color))
 		}
@@ -331,6 +346,17 @@ func generateDOT(issues []model.Issue, issueIDs map[string]bool, stats *analysis
 	return sb.String()
 }
 
+// criticalChainEdges returns the dependent -> blocker pairs joining
+// consecutive issues on the schedule-critical chain.
+func criticalChainEdges(schedule *analysis.WeightedCriticalPath) map[[2]string]bool {
+	edges := make(map[[2]string]bool)
+	chain := schedule.ChainIDs()
+	for k := 1; k < len(chain); k++ {
+		edges[[2]string{chain[k], chain[k-1]}] = true
+	}
+	return edges
+}
+
 // dotStatusColor returns a DOT-compatible color for a status.
 func dotStatusColor(status model.Status) string {
 	switch status {
@@ -463,7 +489,7 @@ func generateMermaid(issues []model.Issue, issueIDs map[string]bool) string {
 }
 
 // generateAdjacency creates a JSON adjacency list representation.
-func generateAdjacency(issues []model.Issue, issueIDs map[string]bool, stats *analysis.GraphStats) *AdjacencyGraph {
+func generateAdjacency(issues []model.Issue, issueIDs map[string]bool, stats *analysis.GraphStats, schedule *analysis.WeightedCriticalPath) *AdjacencyGraph {
 	// Get PageRank
 	var pageRank map[string]float64
 	if stats != nil {
@@ -477,6 +503,12 @@ func generateAdjacency(issues []model.Issue, issueIDs map[string]bool, stats *an
 		return sortedIssues[i].ID < sortedIssues[j].ID
 	})
 
+	onChain := make(map[string]bool)
+	for _, id := range schedule.ChainIDs() {
+		onChain[id] = true
+	}
+	critical := criticalChainEdges(schedule)
+
 	// Build nodes
 	nodes := make([]AdjacencyNode, 0, len(sortedIssues))
 	for _, i := range sortedIssues {
@@ -492,6 +524,12 @@ func generateAdjacency(issues []model.Issue, issueIDs map[string]bool, stats *an
 				node.PageRank = pr
 			}
 		}
+		if t, ok := schedule.Timing(i.ID); ok {
+			slack := t.SlackMinutes
+			node.EstimatedMinutes = t.DurationMinutes
+			node.SlackMinutes = &slack
+			node.Critical = onChain[i.ID]
+		}
 		nodes = append(nodes, node)
 	}
 
@@ -522,9 +560,10 @@ func generateAdjacency(issues []model.Issue, issueIDs map[string]bool, stats *an
 			}
 
 			edges = append(edges, AdjacencyEdge{
-				From: i.ID,
-				To:   dep.DependsOnID,
-				Type: edgeType,
+				From:     i.ID,
+				To:       dep.DependsOnID,
+				Type:     edgeType,
+				Critical: critical[[2]string{i.ID, dep.DependsOnID}],
 			})
 		}
 	}
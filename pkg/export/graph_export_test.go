@@ -310,3 +310,48 @@ func TestExportGraph_DeterministicOutput(t *testing.T) {
 		t.Error("DOT output should be deterministic across calls")
 	}
 }
+
+func TestExportGraph_HighlightsWeightedCriticalChain(t *testing.T) {
+	long, short := 480, 15
+	issues := []model.Issue{
+		{ID: "long", Title: "Long", Status: model.StatusOpen, EstimatedMinutes: &long},
+		{ID: "short", Title: "Short", Status: model.StatusOpen, EstimatedMinutes: &short},
+		{ID: "end", Title: "End", Status: model.StatusOpen, EstimatedMinutes: &short,
+			Dependencies: []*model.Dependency{
+				{IssueID: "end", DependsOnID: "long", Type: model.DepBlocks},
+				{IssueID: "end", DependsOnID: "short", Type: model.DepBlocks},
+			},
+		},
+	}
+	analyzer := analysis.NewAnalyzer(issues)
+	stats := analyzer.Analyze()
+
+	result, err := ExportGraph(issues, &stats, GraphExportConfig{Format: GraphFormatJSON})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range result.Adjacency.Edges {
+		if want := e.To == "long"; e.Critical != want {
+			t.Errorf("edge %s -> %s critical = %v, want %v", e.From, e.To, e.Critical, want)
+		}
+	}
+	for _, n := range result.Adjacency.Nodes {
+		if n.SlackMinutes == nil {
+			t.Fatalf("node %s missing slack", n.ID)
+		}
+		if n.ID == "short" && (n.Critical || *n.SlackMinutes != 465) {
+			t.Errorf("short node = %+v (slack %d)", n, *n.SlackMinutes)
+		}
+	}
+
+	dot, err := ExportGraph(issues, &stats, GraphExportConfig{Format: GraphFormatDOT})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(dot.Graph, `"end" -> "long" [style=bold, color="#E53935", penwidth=3.0];`) {
+		t.Errorf("critical edge not highlighted in DOT:\n%s", dot.Graph)
+	}
+	if strings.Contains(dot.Graph, `"end" -> "short" [style=bold, color="#E53935", penwidth=3.0];`) {
+		t.Errorf("non-critical edge highlighted in DOT:\n%s", dot.Graph)
+	}
+}
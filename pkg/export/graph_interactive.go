@@ -82,6 +82,11 @@ type graphNode struct {
 	IsArticulation  bool    `json:"is_articulation"`
 	PageRankRank    int     `json:"pagerank_rank"`
 	BetweennessRank int     `json:"betweenness_rank"`
+
+	// Duration-weighted schedule; SlackMinutes is nil for closed issues.
+	EstimatedMinutes int  `json:"estimated_minutes,omitempty"`
+	SlackMinutes     *int `json:"slack_minutes,omitempty"`
+	CriticalChain    bool `json:"critical_chain,omitempty"`
 }
 
 // graphLink represents an edge in the interactive graph
@@ -158,6 +163,15 @@ func GenerateInteractiveGraphHTML(opts InteractiveGraphOptions) (string, error)
 		articulationSet[id] = true
 	}
 
+	// Duration-weighted schedule: the critical chain is highlighted instead
+	// of every zero-slack node on a longest hop path.
+	schedule := analysis.ComputeWeightedCriticalPath(opts.Issues)
+	onChain := make(map[string]bool)
+	for _, id := range schedule.ChainIDs() {
+		onChain[id] = true
+	}
+	criticalEdges := criticalChainEdges(schedule)
+
 	// Build reverse dependency map (who blocks who)
 	blocksMap := make(map[string][]string)
 	for _, iss := range opts.Issues {
@@ -256,6 +270,12 @@ func GenerateInteractiveGraphHTML(opts InteractiveGraphOptions) (string, error)
 			PageRankRank:    pageRankRank[iss.ID],
 			BetweennessRank: betweennessRank[iss.ID],
 		}
+		if t, ok := schedule.Timing(iss.ID); ok {
+			slackMinutes := t.SlackMinutes
+			node.EstimatedMinutes = t.DurationMinutes
+			node.SlackMinutes = &slackMinutes
+			node.CriticalChain = onChain[iss.ID]
+		}
 		nodes = append(nodes, node)
 
 		// Build links from dependencies
@@ -263,8 +283,7 @@ func GenerateInteractiveGraphHTML(opts InteractiveGraphOptions) (string, error)
 			if dep == nil || !issueMap[dep.DependsOnID] {
 				continue
 			}
-			// Only mark as critical if we have stats AND both ends have zero slack
-			isCritical := opts.Stats != nil && slack[iss.ID] == 0 && slack[dep.DependsOnID] == 0
+			isCritical := criticalEdges[[2]string{iss.ID, dep.DependsOnID}]
 			link := graphLink{
 				Source:   iss.ID,
 				Target:   dep.DependsOnID,
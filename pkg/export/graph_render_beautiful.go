@@ -927,6 +927,13 @@ const TYPE_COLORS = { feature: '#a855f7', bug: '#ef4444', task: '#22d3ee', epic:
 // Configure marked for safe HTML rendering
 marked.setOptions({ breaks: true, gfm: true });
 
+// Schedule durations are in estimated minutes
+const fmtMinutes = m => {
+    if (m == null || !isFinite(m)) return '-';
+    if (m < 60) return m + 'm';
+    return (m / 60).toFixed(m < 600 ? 1 : 0) + 'h';
+};
+
 // Stats calculation
 let actionable = 0, blocked = 0, onCriticalPath = 0, articulationCount = 0;
 const blockerCount = {};
@@ -935,7 +942,7 @@ DATA.nodes.forEach(n => {
     n.blockerCount = blockerCount[n.id] || 0;
     if ((n.status === 'open' || n.status === 'in_progress') && n.blockerCount === 0) actionable++;
     if (n.status === 'blocked') blocked++;
-    if (n.slack === 0) onCriticalPath++;
+    if (n.critical_chain) onCriticalPath++;
     if (n.is_articulation) articulationCount++;
 });
 document.getElementById('stat-actionable').textContent = actionable;
@@ -1060,7 +1067,7 @@ const Graph = ForceGraph()(container)
         }
 
         // Critical path indicator
-        if (node.slack === 0 && isHighlighted) {
+        if (node.critical_chain && isHighlighted) {
             ctx.beginPath(); ctx.arc(x, y, size + 3, 0, 2 * Math.PI);
             ctx.fillStyle = baseColor + '30'; ctx.fill();
         }
@@ -1163,7 +1170,7 @@ function populatePanelContent(prefix, node) {
     addBadge('badge-' + node.status, node.status.replace('_', ' '));
     addBadge('', 'P' + node.priority);
     if (node.is_articulation) addBadge('badge-articulation', 'Cut Vertex');
-    if (node.slack === 0) addBadge('badge-critical', 'Critical Path');
+    if (node.critical_chain) addBadge('badge-critical', 'Critical Path');
     (node.labels || []).forEach(l => addBadge('badge-type', l));
 
     // Description
@@ -1243,7 +1250,8 @@ function populatePanelContent(prefix, node) {
     addMetric('Betweenness', fmt(node.betweenness, 4));
     addMetric('BW Rank', '#' + (node.betweenness_rank || '-'));
     addMetric('Critical Path', fmt(node.critical_path, 1));
-    addMetric('Slack', fmt(node.slack, 1));
+    addMetric('Estimate', fmtMinutes(node.estimated_minutes));
+    addMetric('Slack', fmtMinutes(node.slack_minutes));
     addMetric('In-Degree', node.in_degree ?? '-');
     addMetric('Out-Degree', node.out_degree ?? '-');
 }
@@ -1345,8 +1353,8 @@ function selectNode(node) {
     document.getElementById('m-bwrank').textContent = '#' + (node.betweenness_rank || '-');
     document.getElementById('m-critical').textContent = fmtSide(node.critical_path, 1);
     const slackEl = document.getElementById('m-slack');
-    slackEl.textContent = fmtSide(node.slack, 1);
-    slackEl.className = 'metric-value' + (node.slack === 0 ? ' highlight' : '');
+    slackEl.textContent = fmtMinutes(node.slack_minutes);
+    slackEl.className = 'metric-value' + (node.critical_chain ? ' highlight' : '');
     document.getElementById('m-indeg').textContent = node.in_degree ?? '-';
     document.getElementById('m-outdeg').textContent = node.out_degree ?? '-';
     document.getElementById('node-detail').classList.add('visible');
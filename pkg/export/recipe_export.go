@@ -0,0 +1,460 @@
+// Package export provides data export functionality for bv.
+//
+// This file implements recipe-driven exports: the recipe's filtered, sorted
+// issues rendered as Markdown, JSON lines, CSV, a Mermaid graph, or through
+// a user text/template.
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/recipe"
+)
+
+// Recipe export formats (recipe export.format)
+const (
+	FormatMarkdown = "markdown"
+	FormatJSON     = "json" // JSON lines, one issue per line
+	FormatCSV      = "csv"
+	FormatMermaid  = "mermaid"
+	FormatTemplate = "template"
+)
+
+// DefaultExportColumns are the CSV columns used when a recipe names none.
+var DefaultExportColumns = []string{"id", "title", "status", "priority", "type", "assignee", "labels", "created", "updated", "blockers"}
+
+// RecipeExporter renders issues in one of the recipe export formats.
+type RecipeExporter struct {
+	Recipe *recipe.Recipe // optional: supplies columns, metrics, format and template
+	Issues []model.Issue  // issues to export, already filtered and sorted
+	All    []model.Issue  // full issue set for blocker lookups; defaults to Issues
+	Stats  *analysis.GraphStats
+	Triage *analysis.TriageResult
+	Title  string
+	Now    time.Time
+
+	// TemplatePath overrides Recipe.Export.Template. Relative paths are
+	// resolved against the working directory.
+	TemplatePath string
+}
+
+// Format picks the export format: a template if one is configured, else the
+// recipe's export.format, else the output file extension, else markdown.
+func (e *RecipeExporter) Format(path string) (string, error) {
+	if e.templatePath() != "" {
+		return FormatTemplate, nil
+	}
+	if e.Recipe != nil && e.Recipe.Export.Format != "" {
+		switch f := strings.ToLower(e.Recipe.Export.Format); f {
+		case FormatMarkdown, FormatJSON, FormatCSV, FormatMermaid:
+			return f, nil
+		case "md":
+			return FormatMarkdown, nil
+		case "jsonl":
+			return FormatJSON, nil
+		default:
+			return "", fmt.Errorf("unknown export format %q (supported: markdown, json, csv, mermaid)", e.Recipe.Export.Format)
+		}
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".json", ".jsonl", ".ndjson":
+		return FormatJSON, nil
+	case ".mmd", ".mermaid":
+		return FormatMermaid, nil
+	}
+	return FormatMarkdown, nil
+}
+
+func (e *RecipeExporter) templatePath() string {
+	if e.TemplatePath != "" {
+		return e.TemplatePath
+	}
+	if e.Recipe != nil {
+		return e.Recipe.Export.Template
+	}
+	return ""
+}
+
+// Columns returns the recipe's view columns followed by its metrics, or
+// DefaultExportColumns.
+func (e *RecipeExporter) Columns() []string {
+	var cols []string
+	if e.Recipe != nil {
+		cols = append(cols, e.Recipe.View.Columns...)
+		cols = append(cols, e.Recipe.Metrics...)
+	}
+	if len(cols) == 0 {
+		return DefaultExportColumns
+	}
+	seen := make(map[string]bool)
+	out := cols[:0:0]
+	for _, c := range cols {
+		c = strings.ToLower(strings.TrimSpace(c))
+		if c != "" && !seen[c] {
+			seen[c] = true
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// ExportColumnNames lists the columns a recipe may name, sorted
+func ExportColumnNames() []string {
+	names := make([]string, 0, len(exportColumns))
+	for name := range exportColumns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ValidateColumns reports the first unknown column
+func ValidateColumns(columns []string) error {
+	for _, c := range columns {
+		if _, ok := exportColumns[c]; !ok {
+			return fmt.Errorf("unknown export column %q (known: %s)", c, strings.Join(ExportColumnNames(), ", "))
+		}
+	}
+	return nil
+}
+
+// Write renders the export in the given format
+func (e *RecipeExporter) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatMarkdown:
+		content, err := GenerateMarkdown(e.Issues, e.title())
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, content)
+		return err
+	case FormatMermaid:
+		_, err := io.WriteString(w, e.mermaid())
+		return err
+	case FormatCSV:
+		return e.writeCSV(w)
+	case FormatJSON:
+		return e.writeJSONLines(w)
+	case FormatTemplate:
+		return e.writeTemplate(w)
+	}
+	return fmt.Errorf("unknown export format %q", format)
+}
+
+func (e *RecipeExporter) title() string {
+	if e.Title != "" {
+		return e.Title
+	}
+	if e.Recipe != nil && e.Recipe.Name != "" {
+		return "Beads Export: " + e.Recipe.Name
+	}
+	return "Beads Export"
+}
+
+func (e *RecipeExporter) mermaid() string {
+	ids := make(map[string]bool, len(e.Issues))
+	for _, issue := range e.Issues {
+		ids[issue.ID] = true
+	}
+	return GenerateMermaidGraph(e.Issues, ids, MermaidConfig{ShowNoDependenciesNode: true})
+}
+
+func (e *RecipeExporter) writeCSV(w io.Writer) error {
+	cols := e.Columns()
+	if err := ValidateColumns(cols); err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(cols); err != nil {
+		return err
+	}
+	env := e.columnEnv()
+	row := make([]string, len(cols))
+	for i := range e.Issues {
+		for j, c := range cols {
+			row[j] = exportColumns[c](env, &e.Issues[i])
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// jsonLine is one line of the JSON lines export
+type jsonLine struct {
+	model.Issue
+	Metrics model.IssueMetrics `json:"metrics"`
+}
+
+func (e *RecipeExporter) writeJSONLines(w io.Writer) error {
+	env := e.columnEnv()
+	enc := json.NewEncoder(w)
+	for i := range e.Issues {
+		if err := enc.Encode(jsonLine{Issue: e.Issues[i], Metrics: env.metrics(&e.Issues[i])}); err != nil {
+			return fmt.Errorf("encode %s: %w", e.Issues[i].ID, err)
+		}
+	}
+	return nil
+}
+
+// TemplateData is the value a user export template is executed with
+type TemplateData struct {
+	Title       string
+	Recipe      *recipe.Recipe
+	GeneratedAt time.Time
+	Columns     []string
+	Issues      []TemplateIssue
+	Triage      *analysis.TriageResult
+
+	exporter *RecipeExporter
+}
+
+// TemplateIssue is an issue with its metrics and rendered column values
+type TemplateIssue struct {
+	model.Issue
+	Metrics  model.IssueMetrics
+	Blockers []string          // open blocking issues
+	Fields   map[string]string // every export column, by name
+}
+
+// Mermaid returns the dependency graph of the exported issues
+func (d TemplateData) Mermaid() string { return d.exporter.mermaid() }
+
+func (e *RecipeExporter) writeTemplate(w io.Writer) error {
+	path := e.templatePath()
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read template: %w", err)
+	}
+	tmpl, err := template.New(filepath.Base(path)).Funcs(templateFuncs).Parse(string(src))
+	if err != nil {
+		return fmt.Errorf("parse template: %w", err)
+	}
+
+	env := e.columnEnv()
+	data := TemplateData{
+		Title:       e.title(),
+		Recipe:      e.Recipe,
+		GeneratedAt: e.now(),
+		Columns:     e.Columns(),
+		Triage:      e.Triage,
+		exporter:    e,
+	}
+	for i := range e.Issues {
+		issue := &e.Issues[i]
+		fields := make(map[string]string, len(exportColumns))
+		for name, get := range exportColumns {
+			fields[name] = get(env, issue)
+		}
+		data.Issues = append(data.Issues, TemplateIssue{
+			Issue:    *issue,
+			Metrics:  env.metrics(issue),
+			Blockers: env.openBlockers(issue),
+			Fields:   fields,
+		})
+	}
+	if err := tmpl.Execute(w, data); err != nil {
+		return fmt.Errorf("execute template: %w", err)
+	}
+	return nil
+}
+
+var templateFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"truncate": func(n int, s string) string {
+		return truncateString(s, n)
+	},
+	"date": func(layout string, t any) string {
+		switch v := t.(type) {
+		case time.Time:
+			if v.IsZero() {
+				return ""
+			}
+			return v.Format(layout)
+		case *time.Time:
+			if v == nil || v.IsZero() {
+				return ""
+			}
+			return v.Format(layout)
+		}
+		return ""
+	},
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	"csv": func(s string) string {
+		if strings.ContainsAny(s, ",\"\n\r") {
+			return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+		}
+		return s
+	},
+}
+
+func (e *RecipeExporter) now() time.Time {
+	if e.Now.IsZero() {
+		return time.Now()
+	}
+	return e.Now
+}
+
+// columnEnv holds lookups shared by the column functions
+type columnEnv struct {
+	stats       *analysis.GraphStats
+	byID        map[string]*model.Issue
+	blocks      map[string][]string // issue -> issues it blocks
+	triageScore map[string]float64
+	hasTriage   bool
+}
+
+func (e *RecipeExporter) columnEnv() *columnEnv {
+	all := e.All
+	if all == nil {
+		all = e.Issues
+	}
+	env := &columnEnv{
+		stats:       e.Stats,
+		byID:        make(map[string]*model.Issue, len(all)),
+		blocks:      make(map[string][]string),
+		triageScore: make(map[string]float64),
+	}
+	for i := range all {
+		issue := &all[i]
+		env.byID[issue.ID] = issue
+		for _, dep := range issue.Dependencies {
+			if dep != nil && dep.Type.IsBlocking() {
+				env.blocks[dep.DependsOnID] = append(env.blocks[dep.DependsOnID], issue.ID)
+			}
+		}
+	}
+	if e.Triage != nil {
+		env.hasTriage = true
+		for _, rec := range e.Triage.Recommendations {
+			env.triageScore[rec.ID] = rec.Score
+		}
+	}
+	return env
+}
+
+func (env *columnEnv) blockedBy(issue *model.Issue) []string {
+	var ids []string
+	for _, dep := range issue.Dependencies {
+		if dep != nil && dep.Type.IsBlocking() {
+			ids = append(ids, dep.DependsOnID)
+		}
+	}
+	return ids
+}
+
+func (env *columnEnv) openBlockers(issue *model.Issue) []string {
+	var ids []string
+	for _, id := range env.blockedBy(issue) {
+		if blocker, ok := env.byID[id]; ok && blocker.Status != model.StatusClosed && blocker.Status != model.StatusTombstone {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func metricColumn(get func(*analysis.GraphStats, string) float64) func(*columnEnv, *model.Issue) string {
+	return func(env *columnEnv, issue *model.Issue) string {
+		if env.stats == nil {
+			return ""
+		}
+		return formatMetric(get(env.stats, issue.ID))
+	}
+}
+
+func triageScoreColumn(env *columnEnv, i *model.Issue) string {
+	if !env.hasTriage {
+		return ""
+	}
+	return formatMetric(env.triageScore[i.ID])
+}
+
+func (env *columnEnv) metrics(issue *model.Issue) model.IssueMetrics {
+	m := model.IssueMetrics{
+		TriageScore:    env.triageScore[issue.ID],
+		BlocksCount:    len(env.blocks[issue.ID]),
+		BlockedByCount: len(env.blockedBy(issue)),
+	}
+	if env.stats != nil {
+		m.PageRank = env.stats.GetPageRankScore(issue.ID)
+		m.Betweenness = env.stats.GetBetweennessScore(issue.ID)
+		m.CriticalPathDepth = int(env.stats.GetCriticalPathScore(issue.ID))
+	}
+	return m
+}
+
+func formatMetric(v float64) string {
+	return strconv.FormatFloat(v, 'f', 6, 64)
+}
+
+func formatExportTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// exportColumns maps column names (recipe view.columns and metrics) to
+// their CSV rendering.
+var exportColumns = map[string]func(*columnEnv, *model.Issue) string{
+	"id":          func(_ *columnEnv, i *model.Issue) string { return i.ID },
+	"title":       func(_ *columnEnv, i *model.Issue) string { return i.Title },
+	"description": func(_ *columnEnv, i *model.Issue) string { return i.Description },
+	"status":      func(_ *columnEnv, i *model.Issue) string { return string(i.Status) },
+	"priority":    func(_ *columnEnv, i *model.Issue) string { return strconv.Itoa(i.Priority) },
+	"type":        func(_ *columnEnv, i *model.Issue) string { return string(i.IssueType) },
+	"assignee":    func(_ *columnEnv, i *model.Issue) string { return i.Assignee },
+	"labels":      func(_ *columnEnv, i *model.Issue) string { return strings.Join(i.Labels, ",") },
+	"tags":        func(_ *columnEnv, i *model.Issue) string { return strings.Join(i.Labels, ",") },
+	"created":     func(_ *columnEnv, i *model.Issue) string { return formatExportTime(&i.CreatedAt) },
+	"updated":     func(_ *columnEnv, i *model.Issue) string { return formatExportTime(&i.UpdatedAt) },
+	"closed":      func(_ *columnEnv, i *model.Issue) string { return formatExportTime(i.ClosedAt) },
+	"due":         func(_ *columnEnv, i *model.Issue) string { return formatExportTime(i.DueDate) },
+	"estimate": func(_ *columnEnv, i *model.Issue) string {
+		if i.EstimatedMinutes == nil {
+			return ""
+		}
+		return strconv.Itoa(*i.EstimatedMinutes)
+	},
+	"comments": func(_ *columnEnv, i *model.Issue) string { return strconv.Itoa(len(i.Comments)) },
+	"external_ref": func(_ *columnEnv, i *model.Issue) string {
+		if i.ExternalRef == nil {
+			return ""
+		}
+		return *i.ExternalRef
+	},
+	"blockers":         func(env *columnEnv, i *model.Issue) string { return strings.Join(env.openBlockers(i), ",") },
+	"blocked_by":       func(env *columnEnv, i *model.Issue) string { return strings.Join(env.blockedBy(i), ",") },
+	"blocks":           func(env *columnEnv, i *model.Issue) string { return strings.Join(env.blocks[i.ID], ",") },
+	"blocked_by_count": func(env *columnEnv, i *model.Issue) string { return strconv.Itoa(len(env.blockedBy(i))) },
+	"blocks_count":     func(env *columnEnv, i *model.Issue) string { return strconv.Itoa(len(env.blocks[i.ID])) },
+	"triage_score":     triageScoreColumn,
+	"triage":           triageScoreColumn,
+	"pagerank":         metricColumn((*analysis.GraphStats).GetPageRankScore),
+	"betweenness":      metricColumn((*analysis.GraphStats).GetBetweennessScore),
+	"eigenvector":      metricColumn((*analysis.GraphStats).GetEigenvectorScore),
+	"hubs":             metricColumn((*analysis.GraphStats).GetHubScore),
+	"authorities":      metricColumn((*analysis.GraphStats).GetAuthorityScore),
+	"critical_path":    metricColumn((*analysis.GraphStats).GetCriticalPathScore),
+}
@@ -0,0 +1,167 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/recipe"
+)
+
+func recipeExportIssues() []model.Issue {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	return []model.Issue{
+		{ID: "A", Title: "Root, with comma", Status: model.StatusOpen, Priority: 0, IssueType: model.TypeTask, Labels: []string{"api", "db"}, CreatedAt: now, UpdatedAt: now},
+		{ID: "B", Title: "Child", Status: model.StatusOpen, Priority: 1, IssueType: model.TypeBug, CreatedAt: now, UpdatedAt: now,
+			Dependencies: []*model.Dependency{{IssueID: "B", DependsOnID: "A", Type: model.DepBlocks}}},
+	}
+}
+
+func newTestExporter(r *recipe.Recipe) *RecipeExporter {
+	issues := recipeExportIssues()
+	analyzer := analysis.NewAnalyzer(issues)
+	stats := analyzer.Analyze()
+	triage := analysis.ComputeTriageFromAnalyzer(analyzer, &stats, issues, analysis.TriageOptions{TopN: len(issues), WaitForPhase2: true}, time.Now())
+	return &RecipeExporter{Recipe: r, Issues: issues, Stats: &stats, Triage: &triage,
+		Now: time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC)}
+}
+
+func TestRecipeExporterFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		recipe   *recipe.Recipe
+		template string
+		path     string
+		want     string
+		wantErr  bool
+	}{
+		{"csv extension", nil, "", "out.csv", FormatCSV, false},
+		{"jsonl extension", nil, "", "out.jsonl", FormatJSON, false},
+		{"mermaid extension", nil, "", "graph.mmd", FormatMermaid, false},
+		{"default markdown", nil, "", "report.txt", FormatMarkdown, false},
+		{"recipe overrides extension", &recipe.Recipe{Export: recipe.ExportConfig{Format: "csv"}}, "", "out.md", FormatCSV, false},
+		{"recipe md alias", &recipe.Recipe{Export: recipe.ExportConfig{Format: "md"}}, "", "-", FormatMarkdown, false},
+		{"recipe template", &recipe.Recipe{Export: recipe.ExportConfig{Template: "x.tmpl"}}, "", "out.csv", FormatTemplate, false},
+		{"flag template", nil, "x.tmpl", "out.csv", FormatTemplate, false},
+		{"unknown recipe format", &recipe.Recipe{Export: recipe.ExportConfig{Format: "xml"}}, "", "out.csv", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &RecipeExporter{Recipe: tt.recipe, TemplatePath: tt.template}
+			got, err := e.Format(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Format(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Format(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecipeExporterColumns(t *testing.T) {
+	e := &RecipeExporter{}
+	if got := e.Columns(); strings.Join(got, ",") != strings.Join(DefaultExportColumns, ",") {
+		t.Errorf("default columns = %v", got)
+	}
+
+	e.Recipe = &recipe.Recipe{View: recipe.ViewConfig{Columns: []string{"ID", "title", "pagerank"}}, Metrics: []string{"pagerank", "triage_score"}}
+	if got := strings.Join(e.Columns(), ","); got != "id,title,pagerank,triage_score" {
+		t.Errorf("columns = %s", got)
+	}
+
+	if err := ValidateColumns([]string{"id", "nope"}); err == nil || !strings.Contains(err.Error(), `"nope"`) {
+		t.Errorf("ValidateColumns error = %v", err)
+	}
+}
+
+func TestRecipeExporterCSV(t *testing.T) {
+	e := newTestExporter(&recipe.Recipe{
+		View:    recipe.ViewConfig{Columns: []string{"id", "title", "labels", "blockers", "blocks_count"}},
+		Metrics: []string{"pagerank", "triage_score"},
+	})
+	var buf bytes.Buffer
+	if err := e.Write(&buf, FormatCSV); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v\n%s", err, buf.String())
+	}
+	if len(rows) != 3 {
+		t.Fatalf("got %d rows, want header + 2", len(rows))
+	}
+	if got := strings.Join(rows[0], ","); got != "id,title,labels,blockers,blocks_count,pagerank,triage_score" {
+		t.Errorf("header = %s", got)
+	}
+	if rows[1][1] != "Root, with comma" || rows[1][2] != "api,db" || rows[1][4] != "1" {
+		t.Errorf("row A = %v", rows[1])
+	}
+	if rows[2][3] != "A" {
+		t.Errorf("B blockers = %q, want A", rows[2][3])
+	}
+	for _, row := range rows[1:] {
+		if row[5] == "" || row[6] == "" {
+			t.Errorf("metrics missing in %v", row)
+		}
+	}
+}
+
+func TestRecipeExporterJSONLines(t *testing.T) {
+	e := newTestExporter(nil)
+	var buf bytes.Buffer
+	if err := e.Write(&buf, FormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2", len(lines))
+	}
+	var line struct {
+		ID      string             `json:"id"`
+		Metrics model.IssueMetrics `json:"metrics"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &line); err != nil {
+		t.Fatal(err)
+	}
+	if line.ID != "A" || line.Metrics.BlocksCount != 1 || line.Metrics.PageRank == 0 {
+		t.Errorf("line = %+v", line)
+	}
+}
+
+func TestRecipeExporterTemplate(t *testing.T) {
+	dir := t.TempDir()
+	tmpl := filepath.Join(dir, "report.tmpl")
+	src := `{{.Title}} {{date "2006-01-02" .GeneratedAt}}
+{{range .Issues}}{{.ID}}|{{upper (truncate 4 .Title)}}|{{join .Labels "+"}}|{{join .Blockers ","}}|{{index .Fields "blocks_count"}}
+{{end}}{{if .Triage}}top={{(index .Triage.Recommendations 0).ID}}{{end}}`
+	if err := os.WriteFile(tmpl, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	e := newTestExporter(&recipe.Recipe{Name: "weekly"})
+	e.TemplatePath = tmpl
+	var buf bytes.Buffer
+	if err := e.Write(&buf, FormatTemplate); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{"Beads Export: weekly 2025-03-02", "A|ROO…|api+db||1", "B|CHI…||A|0", "top=A"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("template output missing %q:\n%s", want, out)
+		}
+	}
+
+	if err := os.WriteFile(tmpl, []byte("{{.Nope}}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Write(&buf, FormatTemplate); err == nil {
+		t.Error("expected error executing template with unknown field")
+	}
+}
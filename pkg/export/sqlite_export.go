@@ -13,10 +13,12 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/correlation"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
 
 	_ "modernc.org/sqlite"
@@ -29,6 +31,8 @@ type SQLiteExporter struct {
 	Metrics map[string]*model.IssueMetrics
 	Stats   *analysis.GraphStats
 	Triage  *analysis.TriageResult
+	Sprints []model.Sprint
+	History *correlation.HistoryReport
 	Config  SQLiteExportConfig
 	gitHash string
 }
@@ -64,6 +68,17 @@ func (e *SQLiteExporter) SetGitHash(hash string) {
 	e.gitHash = hash
 }
 
+// SetSprints sets the sprints written to the sprints and sprint_members tables.
+func (e *SQLiteExporter) SetSprints(sprints []model.Sprint) {
+	e.Sprints = sprints
+}
+
+// SetHistory sets the git correlation report written to the bead_events and
+// bead_commits tables.
+func (e *SQLiteExporter) SetHistory(report *correlation.HistoryReport) {
+	e.History = report
+}
+
 // Export writes the SQLite database and supporting files to the output directory.
 func (e *SQLiteExporter) Export(outputDir string) error {
 	// Ensure output directory exists
@@ -104,11 +119,26 @@ func (e *SQLiteExporter) Export(outputDir string) error {
 		return fmt.Errorf("insert issues: %w", err)
 	}
 
+	// Insert labels and comments
+	if err := e.insertLabelsAndComments(db); err != nil {
+		return fmt.Errorf("insert labels and comments: %w", err)
+	}
+
 	// Insert dependencies
 	if err := e.insertDependencies(db); err != nil {
 		return fmt.Errorf("insert dependencies: %w", err)
 	}
 
+	// Insert sprints
+	if err := e.insertSprints(db); err != nil {
+		return fmt.Errorf("insert sprints: %w", err)
+	}
+
+	// Insert git history
+	if err := e.insertHistory(db); err != nil {
+		return fmt.Errorf("insert history: %w", err)
+	}
+
 	// Insert metrics
 	if err := e.insertMetrics(db); err != nil {
 		return fmt.Errorf("insert metrics: %w", err)
@@ -180,8 +210,10 @@ func (e *SQLiteExporter) insertIssues(db *sql.DB) error {
 	defer tx.Rollback()
 
 	stmt, err := tx.Prepare(`
-		INSERT INTO issues (id, title, description, status, priority, issue_type, assignee, labels, created_at, updated_at, closed_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO issues (id, title, description, design, acceptance_criteria, notes, status, priority, issue_type,
+			assignee, labels, estimated_minutes, created_at, updated_at, due_date, closed_at, close_reason,
+			external_ref, source_repo, ack_status, bounce_count, deadline, deferred_from, escalated)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`)
 	if err != nil {
 		return err
@@ -195,24 +227,31 @@ func (e *SQLiteExporter) insertIssues(db *sql.DB) error {
 			labels = string(labelsJSON)
 		}
 
-		var closedAt *string
-		if issue.ClosedAt != nil {
-			s := issue.ClosedAt.Format(time.RFC3339)
-			closedAt = &s
-		}
-
 		_, err := stmt.Exec(
 			issue.ID,
 			issue.Title,
 			issue.Description,
+			issue.Design,
+			issue.AcceptanceCriteria,
+			issue.Notes,
 			string(issue.Status),
 			issue.Priority,
 			string(issue.IssueType),
 			issue.Assignee,
 			labels,
+			issue.EstimatedMinutes,
 			issue.CreatedAt.Format(time.RFC3339),
 			issue.UpdatedAt.Format(time.RFC3339),
-			closedAt,
+			formatTimePtr(issue.DueDate),
+			formatTimePtr(issue.ClosedAt),
+			issue.CloseReason,
+			issue.ExternalRef,
+			issue.SourceRepo,
+			string(issue.AckStatus),
+			issue.BounceCount,
+			formatTimePtr(issue.Deadline),
+			issue.DeferredFrom,
+			issue.Escalated,
 		)
 		if err != nil {
 			return fmt.Errorf("insert issue %s: %w", issue.ID, err)
@@ -222,6 +261,175 @@ func (e *SQLiteExporter) insertIssues(db *sql.DB) error {
 	return tx.Commit()
 }
 
+// formatTimePtr formats an optional time as RFC3339, nil when unset.
+func formatTimePtr(t *time.Time) *string {
+	if t == nil || t.IsZero() {
+		return nil
+	}
+	s := t.Format(time.RFC3339)
+	return &s
+}
+
+// formatTimeOrNil formats a time as RFC3339, nil when zero.
+func formatTimeOrNil(t time.Time) *string {
+	return formatTimePtr(&t)
+}
+
+// insertLabelsAndComments fills the labels and comments tables.
+func (e *SQLiteExporter) insertLabelsAndComments(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	labelStmt, err := tx.Prepare(`INSERT OR IGNORE INTO labels (issue_id, label) VALUES (?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer labelStmt.Close()
+
+	commentStmt, err := tx.Prepare(`
+		INSERT INTO comments (comment_id, issue_id, author, text, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return err
+	}
+	defer commentStmt.Close()
+
+	for _, issue := range e.Issues {
+		for _, label := range issue.Labels {
+			if _, err := labelStmt.Exec(issue.ID, label); err != nil {
+				return fmt.Errorf("insert label %s/%s: %w", issue.ID, label, err)
+			}
+		}
+		for _, c := range issue.Comments {
+			if c == nil {
+				continue
+			}
+			if _, err := commentStmt.Exec(c.ID, issue.ID, c.Author, c.Text, formatTimeOrNil(c.CreatedAt)); err != nil {
+				return fmt.Errorf("insert comment %s/%d: %w", issue.ID, c.ID, err)
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// insertSprints fills the sprints and sprint_members tables.
+func (e *SQLiteExporter) insertSprints(db *sql.DB) error {
+	if len(e.Sprints) == 0 {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	sprintStmt, err := tx.Prepare(`
+		INSERT OR REPLACE INTO sprints (id, name, start_date, end_date, velocity_target, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return err
+	}
+	defer sprintStmt.Close()
+
+	memberStmt, err := tx.Prepare(`INSERT OR IGNORE INTO sprint_members (sprint_id, issue_id) VALUES (?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer memberStmt.Close()
+
+	for _, sp := range e.Sprints {
+		_, err := sprintStmt.Exec(
+			sp.ID,
+			sp.Name,
+			formatTimeOrNil(sp.StartDate),
+			formatTimeOrNil(sp.EndDate),
+			sp.VelocityTarget,
+			formatTimeOrNil(sp.CreatedAt),
+			formatTimeOrNil(sp.UpdatedAt),
+		)
+		if err != nil {
+			return fmt.Errorf("insert sprint %s: %w", sp.ID, err)
+		}
+		for _, id := range sp.BeadIDs {
+			if _, err := memberStmt.Exec(sp.ID, id); err != nil {
+				return fmt.Errorf("insert sprint member %s/%s: %w", sp.ID, id, err)
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// insertHistory fills bead_events and bead_commits from the correlation report.
+func (e *SQLiteExporter) insertHistory(db *sql.DB) error {
+	if e.History == nil || len(e.History.Histories) == 0 {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	eventStmt, err := tx.Prepare(`
+		INSERT INTO bead_events (issue_id, event_type, timestamp, commit_sha, commit_message, author, author_email)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return err
+	}
+	defer eventStmt.Close()
+
+	commitStmt, err := tx.Prepare(`
+		INSERT OR REPLACE INTO bead_commits (issue_id, sha, short_sha, message, author, author_email, timestamp, method, confidence, reason, files)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return err
+	}
+	defer commitStmt.Close()
+
+	// Sorted for a deterministic database
+	ids := make([]string, 0, len(e.History.Histories))
+	for id := range e.History.Histories {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		h := e.History.Histories[id]
+		for _, ev := range h.Events {
+			_, err := eventStmt.Exec(id, string(ev.EventType), ev.Timestamp.Format(time.RFC3339),
+				ev.CommitSHA, ev.CommitMsg, ev.Author, ev.AuthorEmail)
+			if err != nil {
+				return fmt.Errorf("insert event %s/%s: %w", id, ev.EventType, err)
+			}
+		}
+		for _, c := range h.Commits {
+			files := "[]"
+			if len(c.Files) > 0 {
+				filesJSON, _ := json.Marshal(c.Files)
+				files = string(filesJSON)
+			}
+			_, err := commitStmt.Exec(id, c.SHA, c.ShortSHA, c.Message, c.Author, c.AuthorEmail,
+				c.Timestamp.Format(time.RFC3339), string(c.Method), c.Confidence, c.Reason, files)
+			if err != nil {
+				return fmt.Errorf("insert commit %s/%s: %w", id, c.ShortSHA, err)
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
 // insertDependencies inserts all dependencies into the database.
 func (e *SQLiteExporter) insertDependencies(db *sql.DB) error {
 	tx, err := db.Begin()
@@ -405,6 +613,7 @@ func (e *SQLiteExporter) insertMeta(db *sql.DB) error {
 		"generated_at":     time.Now().UTC().Format(time.RFC3339),
 		"issue_count":      fmt.Sprintf("%d", len(e.Issues)),
 		"dependency_count": fmt.Sprintf("%d", len(e.Deps)),
+		"sprint_count":     fmt.Sprintf("%d", len(e.Sprints)),
 		"schema_version":   fmt.Sprintf("%d", SchemaVersion),
 	}
 
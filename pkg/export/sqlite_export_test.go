@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Dicklesworthstone/beads_viewer/pkg/correlation"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
 
 	_ "modernc.org/sqlite"
@@ -461,3 +462,102 @@ func TestStringSliceContains(t *testing.T) {
 		}
 	}
 }
+
+func TestExport_FullFidelityTables(t *testing.T) {
+	tmpDir := t.TempDir()
+	now := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	due := now.Add(72 * time.Hour)
+
+	issue := makeTestIssue("ff-1", "Full fidelity", model.StatusClosed, 1, model.TypeFeature)
+	issue.Labels = []string{"api", "export"}
+	issue.Design = "Use a side table"
+	issue.AcceptanceCriteria = "Tables exist"
+	issue.Notes = "BI asked for this"
+	issue.CloseReason = "shipped"
+	issue.DueDate = &due
+	issue.AckStatus = model.AckStatusAccepted
+	issue.BounceCount = 2
+	issue.Comments = []*model.Comment{
+		{ID: 7, IssueID: "ff-1", Author: "alice", Text: "The refresh token rotation needs a migration", CreatedAt: now},
+		{ID: 8, IssueID: "ff-1", Author: "bob", Text: "Agreed", CreatedAt: now.Add(time.Hour)},
+	}
+
+	exp := NewSQLiteExporter([]*model.Issue{issue}, nil, nil, nil)
+	exp.SetSprints([]model.Sprint{{ID: "sprint-1", Name: "March", StartDate: now, EndDate: now.AddDate(0, 0, 14), BeadIDs: []string{"ff-1"}}})
+	exp.SetHistory(&correlation.HistoryReport{Histories: map[string]correlation.BeadHistory{
+		"ff-1": {
+			BeadID: "ff-1",
+			Events: []correlation.BeadEvent{
+				{BeadID: "ff-1", EventType: correlation.EventCreated, Timestamp: now, CommitSHA: "aaa111", Author: "alice"},
+				{BeadID: "ff-1", EventType: correlation.EventClosed, Timestamp: now.Add(48 * time.Hour), CommitSHA: "bbb222", Author: "alice"},
+			},
+			Commits: []correlation.CorrelatedCommit{{
+				SHA: "ccc333", ShortSHA: "ccc333", Message: "Add side tables", Author: "alice", Timestamp: now.Add(24 * time.Hour),
+				Files:  []correlation.FileChange{{Path: "pkg/export/sqlite_schema.go", Action: "M", Insertions: 40}},
+				Method: correlation.MethodExplicitID, Confidence: 0.95,
+			}},
+		},
+	}})
+
+	if err := exp.Export(tmpDir); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	db, err := sql.Open("sqlite", filepath.Join(tmpDir, "beads.sqlite3"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var design, closeReason, dueDate, ackStatus string
+	var bounces int
+	err = db.QueryRow(`SELECT design, close_reason, due_date, ack_status, bounce_count FROM issues WHERE id = 'ff-1'`).
+		Scan(&design, &closeReason, &dueDate, &ackStatus, &bounces)
+	if err != nil {
+		t.Fatalf("query issue: %v", err)
+	}
+	if design != "Use a side table" || closeReason != "shipped" || dueDate != due.Format(time.RFC3339) || ackStatus != "accepted" || bounces != 2 {
+		t.Errorf("issue columns = %q %q %q %q %d", design, closeReason, dueDate, ackStatus, bounces)
+	}
+
+	counts := map[string]int{
+		`SELECT COUNT(*) FROM labels WHERE issue_id = 'ff-1'`:                          2,
+		`SELECT COUNT(*) FROM comments WHERE issue_id = 'ff-1'`:                        2,
+		`SELECT COUNT(*) FROM sprint_members WHERE sprint_id = 'sprint-1'`:             1,
+		`SELECT COUNT(*) FROM bead_events WHERE issue_id = 'ff-1'`:                     2,
+		`SELECT COUNT(*) FROM bead_commits WHERE issue_id = 'ff-1' AND sha = 'ccc333'`: 1,
+	}
+	for q, want := range counts {
+		var got int
+		if err := db.QueryRow(q).Scan(&got); err != nil {
+			t.Fatalf("%s: %v", q, err)
+		}
+		if got != want {
+			t.Errorf("%s = %d, want %d", q, got, want)
+		}
+	}
+
+	// FTS over comments joins back to the comment row
+	var author string
+	var commentID int64
+	err = db.QueryRow(`
+		SELECT c.author, c.comment_id FROM comments_fts f
+		JOIN comments c ON c.id = f.rowid
+		WHERE comments_fts MATCH 'rotation'
+	`).Scan(&author, &commentID)
+	if err != nil {
+		t.Fatalf("comments FTS query: %v", err)
+	}
+	if author != "alice" || commentID != 7 {
+		t.Errorf("FTS match = %s/%d, want alice/7", author, commentID)
+	}
+
+	var files string
+	if err := db.QueryRow(`SELECT files FROM bead_commits WHERE sha = 'ccc333'`).Scan(&files); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []correlation.FileChange
+	if err := json.Unmarshal([]byte(files), &decoded); err != nil || len(decoded) != 1 || decoded[0].Insertions != 40 {
+		t.Errorf("files = %s (%v)", files, err)
+	}
+}
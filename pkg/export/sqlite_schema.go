@@ -10,7 +10,10 @@ import (
 )
 
 // Schema version for tracking migrations
-const SchemaVersion = 1
+//
+// Version 2 adds the remaining issue fields, labels, comments, sprints and
+// git history (bead_events, bead_commits).
+const SchemaVersion = 2
 
 // CreateSchema creates all tables, indexes, and triggers in the database.
 func CreateSchema(db *sql.DB) error {
@@ -19,10 +22,18 @@ func CreateSchema(db *sql.DB) error {
 		return fmt.Errorf("create core tables: %w", err)
 	}
 
+	if err := createDetailTables(db); err != nil {
+		return fmt.Errorf("create detail tables: %w", err)
+	}
+
 	if err := createMetricsTables(db); err != nil {
 		return fmt.Errorf("create metrics tables: %w", err)
 	}
 
+	if err := createHistoryTables(db); err != nil {
+		return fmt.Errorf("create history tables: %w", err)
+	}
+
 	if err := createIndexes(db); err != nil {
 		return fmt.Errorf("create indexes: %w", err)
 	}
@@ -42,14 +53,27 @@ func createCoreTables(db *sql.DB) error {
 			id TEXT PRIMARY KEY,
 			title TEXT NOT NULL,
 			description TEXT,
+			design TEXT,
+			acceptance_criteria TEXT,
+			notes TEXT,
 			status TEXT NOT NULL,
 			priority INTEGER NOT NULL,
 			issue_type TEXT NOT NULL,
 			assignee TEXT,
 			labels TEXT,
+			estimated_minutes INTEGER,
 			created_at TEXT NOT NULL,
 			updated_at TEXT NOT NULL,
-			closed_at TEXT
+			due_date TEXT,
+			closed_at TEXT,
+			close_reason TEXT,
+			external_ref TEXT,
+			source_repo TEXT,
+			ack_status TEXT,
+			bounce_count INTEGER DEFAULT 0,
+			deadline TEXT,
+			deferred_from TEXT,
+			escalated INTEGER DEFAULT 0
 		)
 	`
 	if _, err := db.Exec(issuesSQL); err != nil {
@@ -74,6 +98,111 @@ func createCoreTables(db *sql.DB) error {
 	return nil
 }
 
+// createDetailTables creates the labels, comments and sprint tables.
+func createDetailTables(db *sql.DB) error {
+	// Labels - one row per issue/label, for joins (issues.labels keeps the JSON array)
+	labelsSQL := `
+		CREATE TABLE IF NOT EXISTS labels (
+			issue_id TEXT NOT NULL,
+			label TEXT NOT NULL,
+			PRIMARY KEY (issue_id, label),
+			FOREIGN KEY (issue_id) REFERENCES issues(id)
+		)
+	`
+	if _, err := db.Exec(labelsSQL); err != nil {
+		return fmt.Errorf("create labels table: %w", err)
+	}
+
+	// Comments - rowid backs comments_fts; comment_id is the beads comment ID
+	commentsSQL := `
+		CREATE TABLE IF NOT EXISTS comments (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			comment_id INTEGER,
+			issue_id TEXT NOT NULL,
+			author TEXT,
+			text TEXT NOT NULL,
+			created_at TEXT,
+			FOREIGN KEY (issue_id) REFERENCES issues(id)
+		)
+	`
+	if _, err := db.Exec(commentsSQL); err != nil {
+		return fmt.Errorf("create comments table: %w", err)
+	}
+
+	// Sprints and their members (members may reference issues outside the export)
+	sprintsSQL := `
+		CREATE TABLE IF NOT EXISTS sprints (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			start_date TEXT,
+			end_date TEXT,
+			velocity_target REAL DEFAULT 0,
+			created_at TEXT,
+			updated_at TEXT
+		)
+	`
+	if _, err := db.Exec(sprintsSQL); err != nil {
+		return fmt.Errorf("create sprints table: %w", err)
+	}
+
+	membersSQL := `
+		CREATE TABLE IF NOT EXISTS sprint_members (
+			sprint_id TEXT NOT NULL,
+			issue_id TEXT NOT NULL,
+			PRIMARY KEY (sprint_id, issue_id),
+			FOREIGN KEY (sprint_id) REFERENCES sprints(id)
+		)
+	`
+	if _, err := db.Exec(membersSQL); err != nil {
+		return fmt.Errorf("create sprint_members table: %w", err)
+	}
+
+	return nil
+}
+
+// createHistoryTables creates the git correlation tables (see correlation.HistoryReport).
+func createHistoryTables(db *sql.DB) error {
+	// Lifecycle events derived from beads file history
+	eventsSQL := `
+		CREATE TABLE IF NOT EXISTS bead_events (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			issue_id TEXT NOT NULL,
+			event_type TEXT NOT NULL,
+			timestamp TEXT NOT NULL,
+			commit_sha TEXT,
+			commit_message TEXT,
+			author TEXT,
+			author_email TEXT
+		)
+	`
+	if _, err := db.Exec(eventsSQL); err != nil {
+		return fmt.Errorf("create bead_events table: %w", err)
+	}
+
+	// Code commits correlated with each issue
+	commitsSQL := `
+		CREATE TABLE IF NOT EXISTS bead_commits (
+			issue_id TEXT NOT NULL,
+			sha TEXT NOT NULL,
+			short_sha TEXT,
+			message TEXT,
+			author TEXT,
+			author_email TEXT,
+			timestamp TEXT,
+			method TEXT,
+			confidence REAL DEFAULT 0,
+			reason TEXT,
+			files TEXT,
+			PRIMARY KEY (issue_id, sha)
+		)
+	`
+	if _, err := db.Exec(commitsSQL); err != nil {
+		return fmt.Errorf("create bead_commits table: %w", err)
+	}
+
+	return nil
+}
+
 // createMetricsTables creates tables for computed graph metrics.
 func createMetricsTables(db *sql.DB) error {
 	// Issue metrics - computed by bv analysis
@@ -129,6 +258,13 @@ func createIndexes(db *sql.DB) error {
 		// Metrics indexes
 		`CREATE INDEX IF NOT EXISTS idx_metrics_score ON issue_metrics(triage_score DESC)`,
 		`CREATE INDEX IF NOT EXISTS idx_metrics_pagerank ON issue_metrics(pagerank DESC)`,
+
+		// Detail and history indexes
+		`CREATE INDEX IF NOT EXISTS idx_labels_label ON labels(label)`,
+		`CREATE INDEX IF NOT EXISTS idx_comments_issue ON comments(issue_id, created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_sprint_members_issue ON sprint_members(issue_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_events_issue ON bead_events(issue_id, timestamp)`,
+		`CREATE INDEX IF NOT EXISTS idx_commits_sha ON bead_commits(sha)`,
 	}
 
 	for _, sql := range indexes {
@@ -155,8 +291,8 @@ func createMetaTable(db *sql.DB) error {
 	return nil
 }
 
-// CreateFTSIndex creates the FTS5 full-text search virtual table.
-// This must be called after issues are inserted.
+// CreateFTSIndex creates the FTS5 full-text search virtual tables over
+// issues and comments. This must be called after they are inserted.
 func CreateFTSIndex(db *sql.DB) error {
 	// Create FTS5 virtual table for full-text search
 	ftsSQL := `
@@ -183,6 +319,24 @@ func CreateFTSIndex(db *sql.DB) error {
 		return fmt.Errorf("populate FTS index: %w", err)
 	}
 
+	// Comments FTS - join back with comments.id = comments_fts.rowid
+	commentsFTSSQL := `
+		CREATE VIRTUAL TABLE IF NOT EXISTS comments_fts USING fts5(
+			issue_id UNINDEXED,
+			author,
+			text,
+			content='comments',
+			content_rowid='id',
+			tokenize='porter unicode61'
+		)
+	`
+	if _, err := db.Exec(commentsFTSSQL); err != nil {
+		return fmt.Errorf("create comments FTS5 table: %w", err)
+	}
+	if _, err := db.Exec(`INSERT INTO comments_fts(comments_fts) VALUES('rebuild')`); err != nil {
+		return fmt.Errorf("populate comments FTS index: %w", err)
+	}
+
 	return nil
 }
 
@@ -277,8 +431,9 @@ func OptimizeDatabase(db *sql.DB, pageSize int) error {
 		}
 	}
 
-	// Try to optimize FTS index if it exists (may not be available in all SQLite builds)
+	// Try to optimize FTS indexes if they exist (may not be available in all SQLite builds)
 	_, _ = db.Exec(`INSERT INTO issues_fts(issues_fts) VALUES('optimize')`)
+	_, _ = db.Exec(`INSERT INTO comments_fts(comments_fts) VALUES('optimize')`)
 
 	// VACUUM must be last and outside transaction
 	if _, err := db.Exec(`VACUUM`); err != nil {
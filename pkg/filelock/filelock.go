@@ -0,0 +1,112 @@
+// Package filelock provides the advisory lock bv takes around writes to the
+// beads directory. Every writer (bd-ack, the TUI edit forms, bv flags, bv
+// import, sprint saves) holds it for its whole load-modify-write cycle, so
+// concurrent writers are serialized instead of silently losing updates.
+//
+// The lock is advisory: tools that do not take it (such as bd itself) are not
+// blocked, which is why pkg/mutate also re-checks the file before writing.
+package filelock
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Name is the lock file created in the .bv directory next to the beads
+// directory. It is kept out of the git-tracked beads directory so a write
+// never leaves an untracked file behind there.
+const Name = "write.lock"
+
+// LooseName is the lock file used for a directory that is not a .beads
+// directory (such as the target of bv import --output). It sits next to the
+// file being written, since such a directory has no project .bv to hold it.
+const LooseName = ".bv-write.lock"
+
+// DefaultTimeout is how long Acquire waits when no timeout is given.
+const DefaultTimeout = 10 * time.Second
+
+// pollInterval is how often a contended lock is retried.
+const pollInterval = 20 * time.Millisecond
+
+// ErrTimeout is matched (via errors.Is) by the error returned when the lock
+// is still held by someone else after the timeout.
+var ErrTimeout = errors.New("timed out waiting for write lock")
+
+// Lock is a held exclusive lock. Release it with Unlock.
+type Lock struct {
+	f    *os.File
+	path string
+}
+
+// Acquire takes an exclusive lock on the file at path, creating it if needed,
+// and waits up to timeout (DefaultTimeout when timeout <= 0) for other holders
+// to release it.
+func Acquire(path string, timeout time.Duration) (*Lock, error) {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open lock file: %w", err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		ok, err := tryLock(f)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("lock %s: %w", path, err)
+		}
+		if ok {
+			return &Lock{f: f, path: path}, nil
+		}
+		if time.Now().After(deadline) {
+			f.Close()
+			return nil, fmt.Errorf("%s after %s: %w", path, timeout, ErrTimeout)
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// PathFor returns the lock file guarding the directory dir: Name in the
+// project's .bv directory when dir is a .beads directory, LooseName inside dir
+// otherwise.
+func PathFor(dir string) string {
+	dir = filepath.Clean(dir)
+	if filepath.Base(dir) == ".beads" {
+		return filepath.Join(filepath.Dir(dir), ".bv", Name)
+	}
+	return filepath.Join(dir, LooseName)
+}
+
+// LockDir takes the write lock of the directory dir, creating the .bv
+// directory that holds it if needed.
+func LockDir(dir string, timeout time.Duration) (*Lock, error) {
+	path := PathFor(dir)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("create lock directory: %w", err)
+	}
+	return Acquire(path, timeout)
+}
+
+// Path returns the lock file's path.
+func (l *Lock) Path() string {
+	return l.path
+}
+
+// Unlock releases the lock. The lock file is left in place; removing it
+// would let a waiter lock a file that a newer holder has already replaced.
+func (l *Lock) Unlock() error {
+	if l == nil || l.f == nil {
+		return nil
+	}
+	err := unlock(l.f)
+	if cerr := l.f.Close(); err == nil {
+		err = cerr
+	}
+	l.f = nil
+	return err
+}
@@ -0,0 +1,112 @@
+package filelock
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLockExcludesOtherHolders(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, ".beads")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	held, err := LockDir(dir, time.Second)
+	if err != nil {
+		t.Fatalf("LockDir: %v", err)
+	}
+	if held.Path() != filepath.Join(root, ".bv", Name) {
+		t.Errorf("Path = %s", held.Path())
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("locking left %d file(s) in the beads directory", len(entries))
+	}
+
+	if _, err := LockDir(dir, 50*time.Millisecond); !errors.Is(err, ErrTimeout) {
+		t.Fatalf("second LockDir error = %v, want ErrTimeout", err)
+	}
+
+	if err := held.Unlock(); err != nil {
+		t.Fatalf("Unlock: %v", err)
+	}
+	again, err := LockDir(dir, 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("LockDir after Unlock: %v", err)
+	}
+	again.Unlock()
+	if err := again.Unlock(); err != nil {
+		t.Errorf("second Unlock should be a no-op, got %v", err)
+	}
+}
+
+func TestPathFor(t *testing.T) {
+	tests := []struct {
+		dir  string
+		want string
+	}{
+		{"/work/proj/.beads", "/work/proj/.bv/write.lock"},
+		{"/work/proj/.beads/", "/work/proj/.bv/write.lock"},
+		{"/tmp", "/tmp/.bv-write.lock"},
+		{"/", "/.bv-write.lock"},
+		{"out", "out/.bv-write.lock"},
+	}
+	for _, tt := range tests {
+		if got := PathFor(filepath.FromSlash(tt.dir)); got != filepath.FromSlash(tt.want) {
+			t.Errorf("PathFor(%q) = %q, want %q", tt.dir, got, tt.want)
+		}
+	}
+}
+
+func TestLockDirOutsideBeadsDir(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "out")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	l, err := LockDir(dir, time.Second)
+	if err != nil {
+		t.Fatalf("LockDir: %v", err)
+	}
+	defer l.Unlock()
+	if l.Path() != filepath.Join(dir, LooseName) {
+		t.Errorf("Path = %s", l.Path())
+	}
+	if _, err := os.Stat(filepath.Join(root, ".bv")); !os.IsNotExist(err) {
+		t.Errorf("locking a non-.beads directory created %s", filepath.Join(root, ".bv"))
+	}
+}
+
+func TestLockSerializesWriters(t *testing.T) {
+	dir := t.TempDir()
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	inside, maxInside := 0, 0
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			l, err := LockDir(dir, 5*time.Second)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			mu.Lock()
+			inside++
+			maxInside = max(maxInside, inside)
+			mu.Unlock()
+			time.Sleep(5 * time.Millisecond)
+			mu.Lock()
+			inside--
+			mu.Unlock()
+			l.Unlock()
+		}()
+	}
+	wg.Wait()
+	if maxInside != 1 {
+		t.Fatalf("%d writers held the lock at once", maxInside)
+	}
+}
@@ -1,6 +1,7 @@
-// Package hooks provides a hook system for bv export automation.
+// Package hooks provides a hook system for bv automation.
 // Hooks are configured via .bv/hooks.yaml and run at specific points
-// in the export pipeline (pre-export, post-export).
+// in the export pipeline (pre-export, post-export) or, under a watcher,
+// when issues change state (on-load, on-change, on-close, on-drift).
 package hooks
 
 import (
@@ -21,6 +22,16 @@ const (
 	PreExport HookPhase = "pre-export"
 	// PostExport runs after export is written. Failure is logged but doesn't break export.
 	PostExport HookPhase = "post-export"
+	// OnLoad runs once when a watcher first loads the issues.
+	OnLoad HookPhase = "on-load"
+	// OnChange runs when an issue is created, changes status or becomes unblocked.
+	OnChange HookPhase = "on-change"
+	// OnClose runs when an issue is closed.
+	OnClose HookPhase = "on-close"
+	// OnDrift runs when a new dependency cycle appears or an issue is escalated.
+	OnDrift HookPhase = "on-drift"
+	// Notify runs command sinks configured in .bv/notify.yaml, once per alert.
+	Notify HookPhase = "notify"
 )
 
 // Hook defines a single hook configuration
@@ -41,12 +52,42 @@ type Config struct {
 type HooksByPhase struct {
 	PreExport  []Hook `yaml:"pre-export,omitempty" json:"pre-export,omitempty"`
 	PostExport []Hook `yaml:"post-export,omitempty" json:"post-export,omitempty"`
+	OnLoad     []Hook `yaml:"on-load,omitempty" json:"on-load,omitempty"`
+	OnChange   []Hook `yaml:"on-change,omitempty" json:"on-change,omitempty"`
+	OnClose    []Hook `yaml:"on-close,omitempty" json:"on-close,omitempty"`
+	OnDrift    []Hook `yaml:"on-drift,omitempty" json:"on-drift,omitempty"`
+}
+
+// LifecyclePhases are the phases fired by a watcher rather than by export
+var LifecyclePhases = []HookPhase{OnLoad, OnChange, OnClose, OnDrift}
+
+// PhaseHooks returns the hooks configured for a phase
+func (c *Config) PhaseHooks(phase HookPhase) []Hook {
+	if c == nil {
+		return nil
+	}
+	switch phase {
+	case PreExport:
+		return c.Hooks.PreExport
+	case PostExport:
+		return c.Hooks.PostExport
+	case OnLoad:
+		return c.Hooks.OnLoad
+	case OnChange:
+		return c.Hooks.OnChange
+	case OnClose:
+		return c.Hooks.OnClose
+	case OnDrift:
+		return c.Hooks.OnDrift
+	default:
+		return nil
+	}
 }
 
 // ExportContext contains information passed to hooks via environment variables
 type ExportContext struct {
 	ExportPath   string    // BV_EXPORT_PATH: Output file path
-	ExportFormat string    // BV_EXPORT_FORMAT: 'markdown' or 'json'
+	ExportFormat string    // BV_EXPORT_FORMAT: markdown, csv, json, mermaid or template
 	IssueCount   int       // BV_ISSUE_COUNT: Number of issues exported
 	Timestamp    time.Time // BV_TIMESTAMP: Export timestamp (RFC3339)
 }
@@ -126,6 +167,10 @@ func (l *Loader) Load() error {
 func (l *Loader) normalizeConfig(config *Config) {
 	config.Hooks.PreExport, l.warnings = normalizeHooks(config.Hooks.PreExport, PreExport, l.warnings)
 	config.Hooks.PostExport, l.warnings = normalizeHooks(config.Hooks.PostExport, PostExport, l.warnings)
+	config.Hooks.OnLoad, l.warnings = normalizeHooks(config.Hooks.OnLoad, OnLoad, l.warnings)
+	config.Hooks.OnChange, l.warnings = normalizeHooks(config.Hooks.OnChange, OnChange, l.warnings)
+	config.Hooks.OnClose, l.warnings = normalizeHooks(config.Hooks.OnClose, OnClose, l.warnings)
+	config.Hooks.OnDrift, l.warnings = normalizeHooks(config.Hooks.OnDrift, OnDrift, l.warnings)
 }
 
 // normalizeHooks applies defaults, drops empty commands, and accumulates warnings.
@@ -163,7 +208,7 @@ func (l *Loader) Config() *Config {
 	return l.config
 }
 
-// HasHooks returns true if any hooks are configured
+// HasHooks returns true if any export hooks are configured
 func (l *Loader) HasHooks() bool {
 	if l.config == nil {
 		return false
@@ -171,20 +216,22 @@ func (l *Loader) HasHooks() bool {
 	return len(l.config.Hooks.PreExport) > 0 || len(l.config.Hooks.PostExport) > 0
 }
 
+// HasLifecycleHooks returns true if any on-* hooks are configured
+func (l *Loader) HasLifecycleHooks() bool {
+	for _, phase := range LifecyclePhases {
+		if len(l.GetHooks(phase)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 // GetHooks returns hooks for a specific phase
 func (l *Loader) GetHooks(phase HookPhase) []Hook {
 	if l.config == nil {
 		return nil
 	}
-
-	switch phase {
-	case PreExport:
-		return l.config.Hooks.PreExport
-	case PostExport:
-		return l.config.Hooks.PostExport
-	default:
-		return nil
-	}
+	return l.config.PhaseHooks(phase)
 }
 
 // Warnings returns any warnings from loading
@@ -0,0 +1,252 @@
+package hooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// EventType names a change reported to lifecycle (on-*) hooks
+type EventType string
+
+const (
+	// EventLoaded is fired once, on the first snapshot (on-load)
+	EventLoaded EventType = "loaded"
+	// EventCreated is a new issue (on-change)
+	EventCreated EventType = "created"
+	// EventStatusChanged is a status transition other than closing (on-change)
+	EventStatusChanged EventType = "status_changed"
+	// EventUnblocked is an open issue whose last open blocker closed (on-change)
+	EventUnblocked EventType = "unblocked"
+	// EventClosed is an issue that moved to closed (on-close)
+	EventClosed EventType = "closed"
+	// EventNewCycle is a dependency cycle that was not there before (on-drift)
+	EventNewCycle EventType = "new_cycle"
+	// EventEscalated is an issue flagged for escalation by bd-ack (on-drift)
+	EventEscalated EventType = "escalated"
+)
+
+// Phase returns the hook phase an event type fires
+func (t EventType) Phase() HookPhase {
+	switch t {
+	case EventLoaded:
+		return OnLoad
+	case EventClosed:
+		return OnClose
+	case EventNewCycle, EventEscalated:
+		return OnDrift
+	default:
+		return OnChange
+	}
+}
+
+// ChangeEvent is the JSON payload written to a lifecycle hook's stdin
+type ChangeEvent struct {
+	Event       EventType    `json:"event"`
+	Phase       HookPhase    `json:"phase"`
+	Timestamp   time.Time    `json:"timestamp"`
+	IssueID     string       `json:"issue_id,omitempty"`
+	Issue       *model.Issue `json:"issue,omitempty"`
+	FromStatus  string       `json:"from_status,omitempty"`
+	ToStatus    string       `json:"to_status,omitempty"`
+	CloseReason string       `json:"close_reason,omitempty"`
+	UnblockedBy []string     `json:"unblocked_by,omitempty"` // blockers closed since the last snapshot
+	Actionable  bool         `json:"actionable,omitempty"`   // created events: ready to work on
+	Cycle       []string     `json:"cycle,omitempty"`
+
+	// Loaded events only
+	IssueCount    int      `json:"issue_count,omitempty"`
+	ActionableIDs []string `json:"actionable_ids,omitempty"`
+}
+
+// Snapshot is the watcher's view of the issues after a (re)load
+type Snapshot struct {
+	Issues     []model.Issue
+	Actionable []model.Issue // as returned by analysis.Analyzer.GetActionableIssues
+	Cycles     [][]string
+}
+
+// DiffSnapshots returns the events between two snapshots, ordered by issue
+// ID. A nil prev yields a single loaded event.
+func DiffSnapshots(prev *Snapshot, cur Snapshot, now time.Time) []ChangeEvent {
+	newEvent := func(t EventType) ChangeEvent {
+		return ChangeEvent{Event: t, Phase: t.Phase(), Timestamp: now}
+	}
+
+	curActionable := make(map[string]bool, len(cur.Actionable))
+	for _, issue := range cur.Actionable {
+		curActionable[issue.ID] = true
+	}
+
+	if prev == nil {
+		ev := newEvent(EventLoaded)
+		ev.IssueCount = len(cur.Issues)
+		for _, issue := range cur.Actionable {
+			ev.ActionableIDs = append(ev.ActionableIDs, issue.ID)
+		}
+		return []ChangeEvent{ev}
+	}
+
+	prevByID := make(map[string]model.Issue, len(prev.Issues))
+	for _, issue := range prev.Issues {
+		prevByID[issue.ID] = issue
+	}
+	curByID := make(map[string]model.Issue, len(cur.Issues))
+	for _, issue := range cur.Issues {
+		curByID[issue.ID] = issue
+	}
+	prevActionable := make(map[string]bool, len(prev.Actionable))
+	for _, issue := range prev.Actionable {
+		prevActionable[issue.ID] = true
+	}
+
+	var events []ChangeEvent
+	for i := range cur.Issues {
+		issue := cur.Issues[i]
+		ref := &cur.Issues[i]
+		old, existed := prevByID[issue.ID]
+		if !existed {
+			ev := newEvent(EventCreated)
+			ev.IssueID, ev.Issue = issue.ID, ref
+			ev.ToStatus = string(issue.Status)
+			ev.Actionable = curActionable[issue.ID]
+			events = append(events, ev)
+			continue
+		}
+
+		if old.Status != issue.Status {
+			t := EventStatusChanged
+			if issue.Status == model.StatusClosed {
+				t = EventClosed
+			}
+			ev := newEvent(t)
+			ev.IssueID, ev.Issue = issue.ID, ref
+			ev.FromStatus, ev.ToStatus = string(old.Status), string(issue.Status)
+			if t == EventClosed {
+				ev.CloseReason = issue.CloseReason
+			}
+			events = append(events, ev)
+		}
+
+		// Reopened issues are reported by their status change alone.
+		if curActionable[issue.ID] && !prevActionable[issue.ID] && old.Status != model.StatusClosed {
+			ev := newEvent(EventUnblocked)
+			ev.IssueID, ev.Issue = issue.ID, ref
+			ev.UnblockedBy = closedBlockers(old, prevByID, curByID)
+			events = append(events, ev)
+		}
+
+		if (issue.Escalated && !old.Escalated) ||
+			(issue.AckStatus.NeedsEscalation() && !old.AckStatus.NeedsEscalation()) {
+			ev := newEvent(EventEscalated)
+			ev.IssueID, ev.Issue = issue.ID, ref
+			events = append(events, ev)
+		}
+	}
+
+	seen := make(map[string]bool, len(prev.Cycles))
+	for _, c := range prev.Cycles {
+		seen[cycleKey(c)] = true
+	}
+	for _, c := range cur.Cycles {
+		if seen[cycleKey(c)] {
+			continue
+		}
+		ev := newEvent(EventNewCycle)
+		ev.Cycle = append([]string(nil), c...)
+		events = append(events, ev)
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].IssueID < events[j].IssueID
+	})
+	return events
+}
+
+// closedBlockers returns the blocking dependencies of old that were open in
+// the previous snapshot and are closed (or gone) now.
+func closedBlockers(old model.Issue, prevByID, curByID map[string]model.Issue) []string {
+	var ids []string
+	for _, dep := range old.Dependencies {
+		if dep == nil || !dep.Type.IsBlocking() {
+			continue
+		}
+		before, ok := prevByID[dep.DependsOnID]
+		if !ok || before.Status == model.StatusClosed {
+			continue
+		}
+		if after, ok := curByID[dep.DependsOnID]; !ok || after.Status == model.StatusClosed {
+			ids = append(ids, dep.DependsOnID)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// cycleKey identifies a cycle regardless of where it starts
+func cycleKey(cycle []string) string {
+	ids := append([]string(nil), cycle...)
+	sort.Strings(ids)
+	return strings.Join(ids, ",")
+}
+
+// Lifecycle diffs successive snapshots and runs the on-* hooks for each
+// change. Each hook runs once per event with the ChangeEvent as JSON on
+// stdin, plus BV_HOOK_PHASE, BV_EVENT and BV_ISSUE_ID in the environment.
+type Lifecycle struct {
+	config *Config
+	prev   *Snapshot
+	logger func(string)
+	now    func() time.Time
+}
+
+// NewLifecycle creates a lifecycle runner for the hooks in config
+func NewLifecycle(config *Config) *Lifecycle {
+	return &Lifecycle{
+		config: config,
+		logger: func(string) {},
+		now:    func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// SetLogger sets the logger function for hook execution details
+func (l *Lifecycle) SetLogger(logger func(string)) {
+	if logger == nil {
+		logger = func(string) {}
+	}
+	l.logger = logger
+}
+
+// Observe records snap, runs the hooks for every change since the previous
+// snapshot and returns the events with the hook results. The first call
+// fires on-load.
+func (l *Lifecycle) Observe(snap Snapshot) ([]ChangeEvent, []HookResult) {
+	now := l.now()
+	events := DiffSnapshots(l.prev, snap, now)
+	l.prev = &snap
+
+	executor := NewExecutor(l.config, ExportContext{IssueCount: len(snap.Issues), Timestamp: now})
+	executor.SetLogger(l.logger)
+	var results []HookResult
+	for _, ev := range events {
+		if len(l.config.PhaseHooks(ev.Phase)) == 0 {
+			continue
+		}
+		payload, err := json.Marshal(ev)
+		if err != nil {
+			results = append(results, HookResult{Phase: ev.Phase, Error: fmt.Errorf("encoding %s event: %w", ev.Event, err)})
+			continue
+		}
+		env := []string{
+			"BV_HOOK_PHASE=" + string(ev.Phase),
+			"BV_EVENT=" + string(ev.Event),
+			"BV_ISSUE_ID=" + ev.IssueID,
+		}
+		results = append(results, executor.RunPhase(ev.Phase, append(payload, '\n'), env)...)
+	}
+	return events, results
+}
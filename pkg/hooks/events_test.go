@@ -0,0 +1,169 @@
+package hooks
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func blocks(id string) []*model.Dependency {
+	return []*model.Dependency{{DependsOnID: id, Type: model.DepBlocks}}
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	now := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	a := model.Issue{ID: "A", Title: "Schema", Status: model.StatusInProgress}
+	b := model.Issue{ID: "B", Title: "API", Status: model.StatusOpen, Dependencies: blocks("A")}
+	c := model.Issue{ID: "C", Title: "Docs", Status: model.StatusOpen}
+	prev := Snapshot{Issues: []model.Issue{a, b, c}, Actionable: []model.Issue{a, c}}
+
+	loaded := DiffSnapshots(nil, prev, now)
+	if len(loaded) != 1 || loaded[0].Phase != OnLoad || loaded[0].IssueCount != 3 ||
+		strings.Join(loaded[0].ActionableIDs, ",") != "A,C" {
+		t.Fatalf("first snapshot = %+v, want one on-load event", loaded)
+	}
+
+	closedA := a
+	closedA.Status, closedA.CloseReason = model.StatusClosed, "shipped"
+	startedC := c
+	startedC.Status = model.StatusInProgress
+	escalatedC := startedC
+	escalatedC.AckStatus, escalatedC.Escalated = model.AckStatusImpossible, true
+	d := model.Issue{ID: "D", Title: "New", Status: model.StatusOpen}
+	cur := Snapshot{
+		Issues:     []model.Issue{closedA, b, escalatedC, d},
+		Actionable: []model.Issue{b, escalatedC, d},
+		Cycles:     [][]string{{"B", "D"}},
+	}
+
+	var got []string
+	for _, ev := range DiffSnapshots(&prev, cur, now) {
+		got = append(got, string(ev.Phase)+":"+string(ev.Event)+":"+ev.IssueID)
+		switch ev.Event {
+		case EventClosed:
+			if ev.CloseReason != "shipped" || ev.FromStatus != "in_progress" {
+				t.Errorf("closed event = %+v", ev)
+			}
+		case EventUnblocked:
+			if strings.Join(ev.UnblockedBy, ",") != "A" || ev.Issue.Title != "API" {
+				t.Errorf("unblocked event = %+v", ev)
+			}
+		case EventCreated:
+			if !ev.Actionable {
+				t.Errorf("created event should be actionable: %+v", ev)
+			}
+		case EventNewCycle:
+			if strings.Join(ev.Cycle, ",") != "B,D" {
+				t.Errorf("cycle event = %+v", ev)
+			}
+		}
+	}
+	want := []string{
+		"on-drift:new_cycle:",
+		"on-close:closed:A",
+		"on-change:unblocked:B",
+		"on-change:status_changed:C",
+		"on-drift:escalated:C",
+		"on-change:created:D",
+	}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("events =\n  %v\nwant\n  %v", got, want)
+	}
+
+	// Same cycle starting elsewhere is not new; unchanged issues are quiet.
+	cur2 := cur
+	cur2.Cycles = [][]string{{"D", "B"}}
+	if evs := DiffSnapshots(&cur, cur2, now); len(evs) != 0 {
+		t.Errorf("unchanged snapshot produced %+v", evs)
+	}
+}
+
+func TestLifecycleRunsHooksWithPayloadOnStdin(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	out := filepath.Join(t.TempDir(), "events.jsonl")
+	config := &Config{Hooks: HooksByPhase{
+		OnLoad:   []Hook{{Name: "load", Command: "cat >> " + out, Timeout: 5 * time.Second}},
+		OnChange: []Hook{{Name: "dispatch", Command: `test "$BV_EVENT" = unblocked && cat >> ` + out + ` || true`, Timeout: 5 * time.Second}},
+		OnClose:  []Hook{{Name: "fail", Command: "exit 2", Timeout: 5 * time.Second}},
+	}}
+	lc := NewLifecycle(config)
+
+	a := model.Issue{ID: "A", Status: model.StatusOpen}
+	b := model.Issue{ID: "B", Status: model.StatusOpen, Dependencies: blocks("A")}
+	if _, results := lc.Observe(Snapshot{Issues: []model.Issue{a, b}, Actionable: []model.Issue{a}}); len(results) != 1 || !results[0].Success {
+		t.Fatalf("on-load results = %+v", results)
+	}
+
+	a.Status = model.StatusClosed
+	events, results := lc.Observe(Snapshot{Issues: []model.Issue{a, b}, Actionable: []model.Issue{b}})
+	if len(events) != 2 || len(results) != 2 {
+		t.Fatalf("events = %+v, results = %+v", events, results)
+	}
+	if results[0].Phase != OnClose || results[0].Success {
+		t.Errorf("on-close hook should have failed: %+v", results[0])
+	}
+	if results[1].Phase != OnChange || !results[1].Success {
+		t.Errorf("on-change hook result = %+v", results[1])
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d payloads, want on-load and unblocked:\n%s", len(lines), data)
+	}
+	var ev ChangeEvent
+	if err := json.Unmarshal([]byte(lines[1]), &ev); err != nil {
+		t.Fatalf("payload %q: %v", lines[1], err)
+	}
+	if ev.Event != EventUnblocked || ev.IssueID != "B" || ev.Issue == nil || ev.UnblockedBy[0] != "A" {
+		t.Errorf("payload = %+v", ev)
+	}
+}
+
+func TestLoaderLifecyclePhases(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, ".bv"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := `
+hooks:
+  on-change:
+    - command: ./dispatch.sh
+  on-close:
+    - name: log
+      command: cat >> closed.jsonl
+      on_error: fail
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, ".bv", "hooks.yaml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loader := NewLoader(WithProjectDir(tmpDir))
+	if err := loader.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if loader.HasHooks() {
+		t.Error("lifecycle hooks should not count as export hooks")
+	}
+	if !loader.HasLifecycleHooks() {
+		t.Error("expected lifecycle hooks")
+	}
+	change := loader.GetHooks(OnChange)
+	if len(change) != 1 || change[0].Name != "on-change-1" || change[0].OnError != "continue" || change[0].Timeout != DefaultTimeout {
+		t.Errorf("on-change hooks = %+v", change)
+	}
+	if closeHooks := loader.GetHooks(OnClose); len(closeHooks) != 1 || closeHooks[0].Name != "log" {
+		t.Errorf("on-close hooks = %+v", closeHooks)
+	}
+}
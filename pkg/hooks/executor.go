@@ -59,7 +59,7 @@ func (e *Executor) RunPreExport() error {
 
 	for _, hook := range e.config.Hooks.PreExport {
 		e.logger(fmt.Sprintf("Running pre-export hook %q: %s", hook.Name, hook.Command))
-		result := e.runHook(hook, PreExport)
+		result := e.runHook(hook, PreExport, nil, nil)
 		e.results = append(e.results, result)
 
 		if !result.Success && hook.OnError == "fail" {
@@ -80,7 +80,7 @@ func (e *Executor) RunPostExport() error {
 	var firstError error
 	for _, hook := range e.config.Hooks.PostExport {
 		e.logger(fmt.Sprintf("Running post-export hook %q: %s", hook.Name, hook.Command))
-		result := e.runHook(hook, PostExport)
+		result := e.runHook(hook, PostExport, nil, nil)
 		e.results = append(e.results, result)
 
 		if !result.Success && hook.OnError == "fail" && firstError == nil {
@@ -91,6 +91,31 @@ func (e *Executor) RunPostExport() error {
 	return firstError
 }
 
+// RunWithEnv executes a single hook outside the configured phases, with
+// extraEnv (KEY=value entries, not expanded) added after the export context.
+// It is used by callers that build hooks at runtime, such as notify's
+// command sink.
+func (e *Executor) RunWithEnv(hook Hook, phase HookPhase, extraEnv []string) HookResult {
+	e.logger(fmt.Sprintf("Running %s hook %q: %s", phase, hook.Name, hook.Command))
+	result := e.runHook(hook, phase, extraEnv, nil)
+	e.results = append(e.results, result)
+	return result
+}
+
+// RunPhase executes every configured hook for phase with stdin as the
+// hook's standard input and extraEnv added after the export context. All
+// hooks run regardless of on_error; callers inspect the results.
+func (e *Executor) RunPhase(phase HookPhase, stdin []byte, extraEnv []string) []HookResult {
+	var results []HookResult
+	for _, hook := range e.config.PhaseHooks(phase) {
+		e.logger(fmt.Sprintf("Running %s hook %q: %s", phase, hook.Name, hook.Command))
+		result := e.runHook(hook, phase, extraEnv, stdin)
+		e.results = append(e.results, result)
+		results = append(results, result)
+	}
+	return results
+}
+
 // getShellCommand returns the shell and flag to use for executing commands
 func getShellCommand() (string, string) {
 	if runtime.GOOS == "windows" {
@@ -99,8 +124,9 @@ func getShellCommand() (string, string) {
 	return "sh", "-c"
 }
 
-// runHook executes a single hook with timeout and environment
-func (e *Executor) runHook(hook Hook, phase HookPhase) HookResult {
+// runHook executes a single hook with timeout and environment, feeding it
+// stdin when non-nil
+func (e *Executor) runHook(hook Hook, phase HookPhase, extraEnv []string, stdin []byte) HookResult {
 	result := HookResult{
 		Hook:  hook,
 		Phase: phase,
@@ -125,6 +151,7 @@ func (e *Executor) runHook(hook Hook, phase HookPhase) HookResult {
 
 	// Add export context variables
 	cmd.Env = append(cmd.Env, e.context.ToEnv()...)
+	cmd.Env = append(cmd.Env, extraEnv...)
 
 	// Add hook-specific env vars (with ${VAR} expansion from current env)
 	// Sort keys for deterministic environment order
@@ -141,6 +168,10 @@ func (e *Executor) runHook(hook Hook, phase HookPhase) HookResult {
 		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", key, expandedValue))
 	}
 
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+
 	// Capture output
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
@@ -361,6 +361,27 @@ func TestExecutorCustomEnvExpansion(t *testing.T) {
 	}
 }
 
+func TestExecutorRunWithEnv(t *testing.T) {
+	executor := NewExecutor(nil, ExportContext{Timestamp: time.Now()})
+	hook := Hook{
+		Name:    "alert",
+		Command: "echo \"$BV_ALERT_JSON\"",
+		Timeout: 5 * time.Second,
+	}
+
+	// Extra env is passed through verbatim, without ${VAR} expansion.
+	result := executor.RunWithEnv(hook, Notify, []string{`BV_ALERT_JSON={"msg":"costs $5"}`})
+	if !result.Success {
+		t.Fatalf("expected success, got: %v", result.Error)
+	}
+	if result.Stdout != `{"msg":"costs $5"}` {
+		t.Errorf("expected raw extra env, got %q", result.Stdout)
+	}
+	if result.Phase != Notify || len(executor.Results()) != 1 {
+		t.Errorf("expected result recorded under notify phase, got %+v", executor.Results())
+	}
+}
+
 func TestExecutorSummary(t *testing.T) {
 	config := &Config{
 		Hooks: HooksByPhase{
@@ -0,0 +1,236 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// githubIssue covers both `gh issue list --json ...` output (camelCase,
+// comments inline) and REST API dumps (snake_case, comments as a count).
+type githubIssue struct {
+	Number      int             `json:"number"`
+	Title       string          `json:"title"`
+	Body        string          `json:"body"`
+	State       string          `json:"state"`
+	StateReason string          `json:"state_reason"`
+	Labels      []githubLabel   `json:"labels"`
+	Assignee    *githubUser     `json:"assignee"`
+	Assignees   []githubUser    `json:"assignees"`
+	Comments    json.RawMessage `json:"comments"`
+	URL         string          `json:"url"`
+	HTMLURL     string          `json:"html_url"`
+	Type        *githubLabel    `json:"type"`
+	Milestone   *struct {
+		Title  string `json:"title"`
+		DueOn  string `json:"due_on"`
+		DueOn2 string `json:"dueOn"`
+	} `json:"milestone"`
+	PullRequest json.RawMessage `json:"pull_request"`
+	ParentURL   string          `json:"parent_issue_url"`
+
+	CreatedAt  string `json:"createdAt"`
+	UpdatedAt  string `json:"updatedAt"`
+	ClosedAt   string `json:"closedAt"`
+	CreatedAt2 string `json:"created_at"`
+	UpdatedAt2 string `json:"updated_at"`
+	ClosedAt2  string `json:"closed_at"`
+}
+
+type githubLabel struct {
+	Name string `json:"name"`
+}
+
+// UnmarshalJSON accepts a label object or a bare label name
+func (l *githubLabel) UnmarshalJSON(b []byte) error {
+	var name string
+	if err := json.Unmarshal(b, &name); err == nil {
+		l.Name = name
+		return nil
+	}
+	type plain githubLabel
+	return json.Unmarshal(b, (*plain)(l))
+}
+
+type githubUser struct {
+	Login string `json:"login"`
+	Name  string `json:"name"`
+}
+
+type githubComment struct {
+	Author    *githubUser `json:"author"`
+	User      *githubUser `json:"user"`
+	Body      string      `json:"body"`
+	CreatedAt string      `json:"createdAt"`
+	Created2  string      `json:"created_at"`
+}
+
+var (
+	githubBlockedBy = regexp.MustCompile(`(?im)^\W*(?:blocked[ -]by|depends[ -]on)\b[: ]*(.+)$`)
+	githubParent    = regexp.MustCompile(`(?im)^\W*(?:parent(?: issue)?|part of|sub-issue of)\b[: ]*(.+)$`)
+	githubRef       = regexp.MustCompile(`(?:#|/issues/)(\d+)\b`)
+)
+
+// parseGitHub reads a JSON array of issues, several concatenated arrays (as
+// `gh api --paginate` writes), or one issue object per line.
+func parseGitHub(r io.Reader) ([]record, error) {
+	var issues []githubIssue
+	dec := json.NewDecoder(r)
+	for {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+		if trimmed := strings.TrimSpace(string(raw)); strings.HasPrefix(trimmed, "[") {
+			var batch []githubIssue
+			if err := json.Unmarshal(raw, &batch); err != nil {
+				return nil, err
+			}
+			issues = append(issues, batch...)
+			continue
+		}
+		var one githubIssue
+		if err := json.Unmarshal(raw, &one); err != nil {
+			return nil, err
+		}
+		issues = append(issues, one)
+	}
+
+	records := make([]record, 0, len(issues))
+	for _, gi := range issues {
+		records = append(records, gi.record())
+	}
+	return records, nil
+}
+
+func (gi githubIssue) record() record {
+	key := strconv.Itoa(gi.Number)
+	rec := record{key: key, prefix: "gh", number: key}
+	if gi.Number == 0 {
+		rec.warning = fmt.Sprintf("skipping GitHub issue without a number (%q)", gi.Title)
+		return rec
+	}
+	if len(gi.PullRequest) > 0 && string(gi.PullRequest) != "null" {
+		rec.warning = fmt.Sprintf("skipping pull request #%d", gi.Number)
+		return rec
+	}
+
+	issue := model.Issue{
+		Title:       gi.Title,
+		Description: gi.Body,
+		Priority:    2,
+	}
+	ref := firstNonEmpty(gi.HTMLURL, gi.URL)
+	if ref == "" || strings.Contains(ref, "api.github.com") {
+		ref = "gh-" + key
+	}
+	issue.ExternalRef = strPtr(ref)
+
+	if t, ok := parseTime(firstNonEmpty(gi.CreatedAt, gi.CreatedAt2)); ok {
+		issue.CreatedAt = t
+	}
+	if t, ok := parseTime(firstNonEmpty(gi.UpdatedAt, gi.UpdatedAt2)); ok {
+		issue.UpdatedAt = t
+	}
+	issue.ClosedAt = timePtr(firstNonEmpty(gi.ClosedAt, gi.ClosedAt2))
+
+	issue.Status = model.StatusOpen
+	if strings.EqualFold(gi.State, "closed") {
+		issue.Status = model.StatusClosed
+		if strings.EqualFold(gi.StateReason, "not_planned") {
+			issue.CloseReason = "not planned"
+		}
+	}
+
+	if gi.Type != nil {
+		if t, ok := typeFromName(gi.Type.Name); ok {
+			issue.IssueType = t
+		}
+	}
+	var labels []string
+	for _, l := range gi.Labels {
+		if t, ok := typeFromName(l.Name); ok && issue.IssueType == "" {
+			issue.IssueType = t
+		}
+		if p, ok := priorityFromName(l.Name); ok {
+			issue.Priority = p
+			continue
+		}
+		if strings.EqualFold(l.Name, "in progress") || strings.EqualFold(l.Name, "in-progress") {
+			if issue.Status == model.StatusOpen {
+				issue.Status = model.StatusInProgress
+			}
+		}
+		labels = append(labels, l.Name)
+	}
+	issue.Labels = dedupeLabels(labels)
+
+	switch {
+	case len(gi.Assignees) > 0:
+		issue.Assignee = gi.Assignees[0].Login
+	case gi.Assignee != nil:
+		issue.Assignee = gi.Assignee.Login
+	}
+
+	if gi.Milestone != nil {
+		issue.DueDate = timePtr(firstNonEmpty(gi.Milestone.DueOn, gi.Milestone.DueOn2))
+	}
+
+	var comments []githubComment
+	if len(gi.Comments) > 0 && gi.Comments[0] == '[' {
+		_ = json.Unmarshal(gi.Comments, &comments)
+	}
+	for _, c := range comments {
+		author := ""
+		for _, u := range []*githubUser{c.Author, c.User} {
+			if u != nil && author == "" {
+				author = firstNonEmpty(u.Login, u.Name)
+			}
+		}
+		created, _ := parseTime(firstNonEmpty(c.CreatedAt, c.Created2))
+		issue.Comments = append(issue.Comments, &model.Comment{Author: author, Text: c.Body, CreatedAt: created})
+	}
+
+	// GitHub has no dependency field in these exports; links are written in
+	// the body as "Blocked by #12" or "Parent: #3".
+	for _, m := range githubBlockedBy.FindAllStringSubmatch(gi.Body, -1) {
+		rec.blockedBy = append(rec.blockedBy, githubRefs(m[1])...)
+	}
+	if m := githubParent.FindStringSubmatch(gi.Body); m != nil {
+		if refs := githubRefs(m[1]); len(refs) > 0 {
+			rec.parent = refs[0]
+		}
+	}
+	if refs := githubRefs(gi.ParentURL); len(refs) > 0 {
+		rec.parent = refs[0]
+	}
+
+	rec.issue = issue
+	return rec
+}
+
+func githubRefs(s string) []string {
+	var refs []string
+	for _, m := range githubRef.FindAllStringSubmatch(s, -1) {
+		refs = append(refs, m[1])
+	}
+	return refs
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,308 @@
+// Package importer converts issue tracker export files (GitHub Issues JSON,
+// Jira CSV, Linear JSON) into beads issues, and merges them into an existing
+// beads file so that re-importing a newer export updates issues in place.
+//
+// Everything works from local files; no tracker API is contacted. The
+// original issue is recorded in ExternalRef, which is also how a re-import
+// finds the beads issue it produced last time.
+package importer
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// Format names a supported export file format
+type Format string
+
+const (
+	FormatGitHubJSON Format = "github-json"
+	FormatJiraCSV    Format = "jira-csv"
+	FormatLinearJSON Format = "linear-json"
+)
+
+// Formats returns the supported formats
+func Formats() []Format {
+	return []Format{FormatGitHubJSON, FormatJiraCSV, FormatLinearJSON}
+}
+
+// Options controls ID assignment
+type Options struct {
+	// Prefix replaces the ID prefix derived from the source: "gh" for GitHub,
+	// the project key for Jira and the team key for Linear.
+	Prefix string
+	// Actor is recorded as CreatedBy on imported dependencies.
+	Actor string
+	// Now stamps issues that carry no timestamps. Defaults to time.Now().
+	Now time.Time
+}
+
+// Result is the outcome of parsing an export file
+type Result struct {
+	Issues []model.Issue
+	// Warnings lists records that were skipped or links that could not be
+	// resolved within the file.
+	Warnings []string
+}
+
+// Import parses an export file in the given format
+func Import(format Format, r io.Reader, opts Options) (*Result, error) {
+	var (
+		records []record
+		err     error
+	)
+	switch format {
+	case FormatGitHubJSON:
+		records, err = parseGitHub(r)
+	case FormatJiraCSV:
+		records, err = parseJira(r)
+	case FormatLinearJSON:
+		records, err = parseLinear(r)
+	default:
+		return nil, fmt.Errorf("unknown import format %q (supported: %s)", format, formatList())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", format, err)
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	return build(records, opts), nil
+}
+
+func formatList() string {
+	names := make([]string, 0, len(Formats()))
+	for _, f := range Formats() {
+		names = append(names, string(f))
+	}
+	return strings.Join(names, ", ")
+}
+
+// record is one source issue before IDs are assigned. Links refer to other
+// records by key.
+type record struct {
+	key    string // source key, e.g. "42" or "PROJ-12"
+	prefix string // default ID prefix, e.g. "gh" or "proj"
+	number string // ID suffix, e.g. "42" or "12"
+	issue  model.Issue
+
+	parent    string
+	blockedBy []string
+	blocks    []string
+	related   []string
+	warning   string // reason to skip this record, if any
+}
+
+// build assigns IDs, resolves links and validates the issues
+func build(records []record, opts Options) *Result {
+	res := &Result{}
+	ids := make(map[string]string, len(records))
+	kept := records[:0]
+	for _, rec := range records {
+		if rec.warning != "" {
+			res.Warnings = append(res.Warnings, rec.warning)
+			continue
+		}
+		if _, dup := ids[rec.key]; dup {
+			res.Warnings = append(res.Warnings, fmt.Sprintf("skipping duplicate record %s", rec.key))
+			continue
+		}
+		prefix := rec.prefix
+		if opts.Prefix != "" {
+			prefix = opts.Prefix
+		}
+		ids[rec.key] = normalizeID(prefix + "-" + rec.number)
+		kept = append(kept, rec)
+	}
+
+	// Outward links ("this blocks X") are stored on X.
+	blockedBy := make(map[string][]string)
+	for _, rec := range kept {
+		for _, key := range rec.blocks {
+			if _, ok := ids[key]; !ok {
+				res.Warnings = append(res.Warnings, fmt.Sprintf("%s: blocks link to %s is outside the export; dropped", rec.key, key))
+				continue
+			}
+			blockedBy[key] = append(blockedBy[key], rec.key)
+		}
+	}
+
+	for _, rec := range kept {
+		issue := rec.issue
+		issue.ID = ids[rec.key]
+		fillDefaults(&issue, opts.Now)
+
+		seen := make(map[string]bool)
+		addDep := func(to string, depType model.DependencyType) {
+			target, ok := ids[to]
+			if !ok {
+				res.Warnings = append(res.Warnings, fmt.Sprintf("%s: %s link to %s is outside the export; dropped", rec.key, depType, to))
+				return
+			}
+			if target == issue.ID {
+				return
+			}
+			k := target + "|" + string(depType)
+			if seen[k] {
+				return
+			}
+			seen[k] = true
+			issue.Dependencies = append(issue.Dependencies, &model.Dependency{
+				IssueID:     issue.ID,
+				DependsOnID: target,
+				Type:        depType,
+				CreatedAt:   issue.CreatedAt,
+				CreatedBy:   opts.Actor,
+			})
+		}
+		if rec.parent != "" {
+			addDep(rec.parent, model.DepParentChild)
+		}
+		for _, key := range append(rec.blockedBy, blockedBy[rec.key]...) {
+			addDep(key, model.DepBlocks)
+		}
+		for _, key := range rec.related {
+			addDep(key, model.DepRelated)
+		}
+
+		for i, c := range issue.Comments {
+			c.ID = int64(i + 1)
+			c.IssueID = issue.ID
+		}
+
+		if err := issue.Validate(); err != nil {
+			res.Warnings = append(res.Warnings, fmt.Sprintf("skipping %s: %v", rec.key, err))
+			continue
+		}
+		res.Issues = append(res.Issues, issue)
+	}
+	return res
+}
+
+var nonIDChars = regexp.MustCompile(`[^a-z0-9.-]+`)
+
+func normalizeID(id string) string {
+	return strings.Trim(nonIDChars.ReplaceAllString(strings.ToLower(id), "-"), "-")
+}
+
+// fillDefaults makes the issue valid for beads: a known status and type,
+// timestamps, and updated_at no earlier than created_at.
+func fillDefaults(issue *model.Issue, now time.Time) {
+	if issue.Status == "" {
+		issue.Status = model.StatusOpen
+	}
+	if issue.IssueType == "" {
+		issue.IssueType = model.TypeTask
+	}
+	if issue.Title == "" {
+		issue.Title = "(untitled)"
+	}
+	if issue.CreatedAt.IsZero() {
+		issue.CreatedAt = now
+	}
+	if issue.UpdatedAt.Before(issue.CreatedAt) {
+		issue.UpdatedAt = issue.CreatedAt
+	}
+	if issue.Status == model.StatusClosed && issue.ClosedAt == nil {
+		closed := issue.UpdatedAt
+		issue.ClosedAt = &closed
+	}
+	if issue.Status != model.StatusClosed {
+		issue.ClosedAt = nil
+	}
+	sort.Strings(issue.Labels)
+}
+
+// dedupeLabels trims, drops empty and duplicate labels, keeping first-seen order
+func dedupeLabels(labels []string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, l := range labels {
+		l = strings.TrimSpace(l)
+		if l == "" || seen[strings.ToLower(l)] {
+			continue
+		}
+		seen[strings.ToLower(l)] = true
+		out = append(out, l)
+	}
+	return out
+}
+
+func strPtr(s string) *string { return &s }
+
+// parseTime accepts the timestamp layouts the supported exports use
+func parseTime(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range []string{
+		time.RFC3339Nano,
+		"2006-01-02T15:04:05.000-0700", // Jira REST
+		"2006-01-02 15:04:05",
+		"2006-01-02 15:04",
+		"2006-01-02",
+		"02/Jan/06 3:04 PM", // Jira CSV default
+		"02/Jan/06 15:04",
+		"02/Jan/2006 3:04 PM",
+		"02/Jan/06",
+		"1/2/2006 15:04",
+		"1/2/2006",
+	} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC(), true
+		}
+	}
+	return time.Time{}, false
+}
+
+func timePtr(s string) *time.Time {
+	if t, ok := parseTime(s); ok {
+		return &t
+	}
+	return nil
+}
+
+// typeFromName maps a tracker's issue type or label to a beads type
+func typeFromName(name string) (model.IssueType, bool) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "bug", "defect", "incident":
+		return model.TypeBug, true
+	case "feature", "enhancement", "story", "new feature", "improvement", "feature request":
+		return model.TypeFeature, true
+	case "epic":
+		return model.TypeEpic, true
+	case "chore", "maintenance", "tech debt", "tech-debt":
+		return model.TypeChore, true
+	case "task", "sub-task", "subtask":
+		return model.TypeTask, true
+	}
+	return "", false
+}
+
+// priorityFromName maps priority names and P0-P4 labels to beads priorities
+func priorityFromName(name string) (int, bool) {
+	n := strings.ToLower(strings.TrimSpace(name))
+	n = strings.TrimPrefix(n, "priority:")
+	n = strings.TrimPrefix(n, "priority/")
+	n = strings.TrimSpace(n)
+	switch n {
+	case "p0", "highest", "blocker", "urgent":
+		return 0, true
+	case "p1", "high", "critical":
+		return 1, true
+	case "p2", "medium", "major", "normal":
+		return 2, true
+	case "p3", "low", "minor":
+		return 3, true
+	case "p4", "lowest", "trivial":
+		return 4, true
+	}
+	return 0, false
+}
@@ -0,0 +1,255 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+var importNow = time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+
+func mustImport(t *testing.T, format Format, data string, opts Options) *Result {
+	t.Helper()
+	opts.Now = importNow
+	res, err := Import(format, strings.NewReader(data), opts)
+	if err != nil {
+		t.Fatalf("Import(%s): %v", format, err)
+	}
+	return res
+}
+
+func byID(issues []model.Issue) map[string]model.Issue {
+	m := make(map[string]model.Issue, len(issues))
+	for _, issue := range issues {
+		m[issue.ID] = issue
+	}
+	return m
+}
+
+func deps(issue model.Issue) string {
+	var parts []string
+	for _, d := range issue.Dependencies {
+		parts = append(parts, string(d.Type)+":"+d.DependsOnID)
+	}
+	return strings.Join(parts, ",")
+}
+
+func TestImportGitHub(t *testing.T) {
+	// gh issue list --json output, followed by a REST page as --paginate writes it.
+	data := `[
+  {"number": 1, "title": "Epic: auth", "body": "", "state": "OPEN",
+   "labels": [{"name": "epic"}, {"name": "P1"}], "assignees": [{"login": "alice"}],
+   "createdAt": "2025-01-01T10:00:00Z", "updatedAt": "2025-01-02T10:00:00Z",
+   "url": "https://github.com/acme/app/issues/1",
+   "comments": [{"author": {"login": "bob"}, "body": "kickoff", "createdAt": "2025-01-01T11:00:00Z"}]},
+  {"number": 2, "title": "Login crash", "state": "CLOSED", "stateReason": "COMPLETED",
+   "body": "Parent: #1\nBlocked by #3, #99",
+   "labels": [{"name": "bug"}], "createdAt": "2025-01-03T10:00:00Z", "updatedAt": "2025-01-04T10:00:00Z",
+   "closedAt": "2025-01-04T10:00:00Z", "url": "https://github.com/acme/app/issues/2", "comments": []}
+]
+[{"number": 3, "title": "Token store", "state": "open", "body": null, "comments": 4,
+  "html_url": "https://github.com/acme/app/issues/3", "url": "https://api.github.com/repos/acme/app/issues/3",
+  "created_at": "2025-01-05T10:00:00Z", "updated_at": "2025-01-05T10:00:00Z",
+  "assignee": {"login": "carol"}, "labels": [{"name": "in progress"}]},
+ {"number": 4, "title": "A PR", "state": "open", "pull_request": {"url": "x"}}]`
+
+	res := mustImport(t, FormatGitHubJSON, data, Options{})
+	if len(res.Issues) != 3 {
+		t.Fatalf("got %d issues, want 3 (PR skipped)", len(res.Issues))
+	}
+	got := byID(res.Issues)
+
+	epic := got["gh-1"]
+	if epic.IssueType != model.TypeEpic || epic.Priority != 1 || epic.Assignee != "alice" {
+		t.Errorf("gh-1 = %+v", epic)
+	}
+	if strings.Join(epic.Labels, ",") != "epic" {
+		t.Errorf("gh-1 labels = %v (priority label should be consumed)", epic.Labels)
+	}
+	if len(epic.Comments) != 1 || epic.Comments[0].Author != "bob" || epic.Comments[0].IssueID != "gh-1" {
+		t.Errorf("gh-1 comments = %+v", epic.Comments)
+	}
+	if epic.ExternalRef == nil || *epic.ExternalRef != "https://github.com/acme/app/issues/1" {
+		t.Errorf("gh-1 external_ref = %v", epic.ExternalRef)
+	}
+
+	bug := got["gh-2"]
+	if bug.Status != model.StatusClosed || bug.ClosedAt == nil || bug.IssueType != model.TypeBug {
+		t.Errorf("gh-2 = %+v", bug)
+	}
+	if d := deps(bug); d != "parent-child:gh-1,blocks:gh-3" {
+		t.Errorf("gh-2 deps = %q", d)
+	}
+
+	store := got["gh-3"]
+	if store.Status != model.StatusInProgress || store.Assignee != "carol" || len(store.Comments) != 0 {
+		t.Errorf("gh-3 = %+v", store)
+	}
+	if *store.ExternalRef != "https://github.com/acme/app/issues/3" {
+		t.Errorf("gh-3 external_ref = %s", *store.ExternalRef)
+	}
+
+	warnings := strings.Join(res.Warnings, "\n")
+	if !strings.Contains(warnings, "pull request #4") || !strings.Contains(warnings, "99") {
+		t.Errorf("warnings = %v", res.Warnings)
+	}
+}
+
+func TestImportJiraCSV(t *testing.T) {
+	data := "Summary,Issue key,Issue id,Issue Type,Status,Priority,Resolution,Assignee,Created,Updated,Resolved,Due Date,Labels,Labels,Comment,Parent,Original Estimate,Inward issue link (Blocks),Outward issue link (Blocks),Description\n" +
+		"Checkout epic,SHOP-1,10001,Epic,In Progress,High,,Alice,01/Mar/25 9:00 AM,02/Mar/25 9:00 AM,,,payments,,,,,,,\n" +
+		"Card form,SHOP-2,10002,Story,Done,Medium,Done,Bob,03/Mar/25 9:00 AM,05/Mar/25 4:30 PM,05/Mar/25 4:30 PM,10/Mar/25,payments,ui,\"04/Mar/25 10:00 AM;carol;Looks good; ship it\",10001,7200,,SHOP-3,\"Multi-line\ndescription\"\n" +
+		"Receipts,SHOP-3,10003,Bug,To Do,Lowest,,,06/Mar/25 9:00 AM,06/Mar/25 9:00 AM,,,,,,SHOP-1,,,,\n"
+
+	res := mustImport(t, FormatJiraCSV, data, Options{})
+	if len(res.Warnings) != 0 {
+		t.Errorf("warnings = %v", res.Warnings)
+	}
+	got := byID(res.Issues)
+	if len(got) != 3 {
+		t.Fatalf("got %d issues: %v", len(got), res.Issues)
+	}
+
+	epic := got["shop-1"]
+	if epic.IssueType != model.TypeEpic || epic.Status != model.StatusInProgress || epic.Priority != 1 {
+		t.Errorf("shop-1 = %+v", epic)
+	}
+
+	card := got["shop-2"]
+	if card.Status != model.StatusClosed || card.CloseReason != "Done" || card.ClosedAt == nil {
+		t.Errorf("shop-2 status = %s reason %q closed %v", card.Status, card.CloseReason, card.ClosedAt)
+	}
+	if strings.Join(card.Labels, ",") != "payments,ui" || card.IssueType != model.TypeFeature {
+		t.Errorf("shop-2 labels/type = %v %s", card.Labels, card.IssueType)
+	}
+	if card.EstimatedMinutes == nil || *card.EstimatedMinutes != 120 {
+		t.Errorf("shop-2 estimate = %v", card.EstimatedMinutes)
+	}
+	if card.DueDate == nil || card.DueDate.Format("2006-01-02") != "2025-03-10" {
+		t.Errorf("shop-2 due = %v", card.DueDate)
+	}
+	if len(card.Comments) != 1 || card.Comments[0].Author != "carol" || card.Comments[0].Text != "Looks good; ship it" {
+		t.Errorf("shop-2 comments = %+v", card.Comments[0])
+	}
+	if card.Description != "Multi-line\ndescription" || *card.ExternalRef != "jira:SHOP-2" {
+		t.Errorf("shop-2 description/ref = %q %s", card.Description, *card.ExternalRef)
+	}
+	// Parent by numeric id.
+	if d := deps(card); d != "parent-child:shop-1" {
+		t.Errorf("shop-2 deps = %q", d)
+	}
+	// SHOP-2 "blocks" SHOP-3 (outward link), so SHOP-3 depends on SHOP-2.
+	if d := deps(got["shop-3"]); d != "parent-child:shop-1,blocks:shop-2" {
+		t.Errorf("shop-3 deps = %q", d)
+	}
+	if got["shop-3"].Priority != 4 || got["shop-3"].Status != model.StatusOpen {
+		t.Errorf("shop-3 = %+v", got["shop-3"])
+	}
+}
+
+func TestImportLinearJSON(t *testing.T) {
+	data := `{"data": {"issues": {"nodes": [
+  {"identifier": "ENG-10", "title": "Search revamp", "priority": 1,
+   "state": {"name": "In Progress", "type": "started"},
+   "labels": {"nodes": [{"name": "Feature"}, {"name": "search"}]},
+   "assignee": {"name": "dana", "displayName": "Dana"},
+   "createdAt": "2025-02-01T00:00:00.000Z", "updatedAt": "2025-02-02T00:00:00.000Z",
+   "url": "https://linear.app/acme/issue/ENG-10",
+   "relations": {"nodes": [{"type": "blocks", "relatedIssue": {"identifier": "ENG-11"}}]}},
+  {"identifier": "ENG-11", "title": "Index rebuild", "priority": 0,
+   "state": {"name": "Canceled", "type": "canceled"}, "canceledAt": "2025-02-05T00:00:00.000Z",
+   "parent": {"identifier": "ENG-10"}, "dueDate": "2025-03-01",
+   "comments": {"nodes": [{"body": "dup of ENG-9", "user": {"name": "erin"}, "createdAt": "2025-02-04T00:00:00.000Z"}]},
+   "inverseRelations": {"nodes": [{"type": "blocks", "issue": {"identifier": "ENG-10"}}]},
+   "createdAt": "2025-02-03T00:00:00.000Z", "updatedAt": "2025-02-05T00:00:00.000Z"}
+]}}}`
+
+	res := mustImport(t, FormatLinearJSON, data, Options{Prefix: "bd"})
+	got := byID(res.Issues)
+	search, rebuild := got["bd-10"], got["bd-11"]
+	if search.Status != model.StatusInProgress || search.Priority != 0 || search.IssueType != model.TypeFeature || search.Assignee != "Dana" {
+		t.Errorf("bd-10 = %+v", search)
+	}
+	if rebuild.Status != model.StatusClosed || rebuild.CloseReason != "canceled" || rebuild.Priority != 2 {
+		t.Errorf("bd-11 = %+v", rebuild)
+	}
+	// The blocks relation appears on both sides but yields one dependency.
+	if d := deps(rebuild); d != "parent-child:bd-10,blocks:bd-10" {
+		t.Errorf("bd-11 deps = %q", d)
+	}
+	if len(rebuild.Comments) != 1 || rebuild.Comments[0].Author != "erin" {
+		t.Errorf("bd-11 comments = %+v", rebuild.Comments)
+	}
+	if *rebuild.ExternalRef != "linear:ENG-11" || *search.ExternalRef != "https://linear.app/acme/issue/ENG-10" {
+		t.Errorf("external refs = %s, %s", *rebuild.ExternalRef, *search.ExternalRef)
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	if _, err := Import("trello", strings.NewReader(""), Options{}); err == nil {
+		t.Error("unknown format should fail")
+	}
+	if _, err := Import(FormatJiraCSV, strings.NewReader("a,b\n1,2\n"), Options{}); err == nil {
+		t.Error("CSV without Jira columns should fail")
+	}
+	if _, err := Import(FormatGitHubJSON, strings.NewReader("[{"), Options{}); err == nil {
+		t.Error("truncated JSON should fail")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	data := `[{"number": 1, "title": "One", "state": "open", "url": "https://github.com/a/b/issues/1", "createdAt": "2025-01-01T00:00:00Z"},
+	          {"number": 2, "title": "Two", "state": "open", "url": "https://github.com/a/b/issues/2", "body": "Blocked by #1", "createdAt": "2025-01-01T00:00:00Z"}]`
+	first := mustImport(t, FormatGitHubJSON, data, Options{})
+
+	// A local issue, and the first import renamed and edited in beads.
+	local := model.Issue{ID: "bd-1", Title: "Local", Status: model.StatusOpen, IssueType: model.TypeTask}
+	existing := []model.Issue{local}
+	for _, issue := range first.Issues {
+		issue = issue.Clone()
+		issue.ID = strings.Replace(issue.ID, "gh-", "bd-gh", 1)
+		issue.Notes = "kept locally"
+		issue.Dependencies = []*model.Dependency{{IssueID: issue.ID, DependsOnID: "bd-1", Type: model.DepRelated}}
+		existing = append(existing, issue)
+	}
+
+	// Re-import: no changes beyond what beads added.
+	plan, err := Merge(existing, first.Issues)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Added) != 0 || len(plan.Updated) != 1 || plan.Unchanged != 1 {
+		t.Fatalf("plan = %+v", plan)
+	}
+	// bd-gh2 gains the blocking link from the export, mapped onto beads IDs.
+	two := byID(plan.Issues)["bd-gh2"]
+	if d := deps(two); d != "blocks:bd-gh1,related:bd-1" || two.Notes != "kept locally" {
+		t.Errorf("bd-gh2 = %q notes %q", d, two.Notes)
+	}
+
+	// A newer export closes #1 and adds #3.
+	data2 := `[{"number": 1, "title": "One", "state": "closed", "url": "https://github.com/a/b/issues/1", "createdAt": "2025-01-01T00:00:00Z", "closedAt": "2025-02-01T00:00:00Z"},
+	           {"number": 3, "title": "Three", "state": "open", "url": "https://github.com/a/b/issues/3"}]`
+	second := mustImport(t, FormatGitHubJSON, data2, Options{})
+	plan, err = Merge(plan.Issues, second.Issues)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Added) != 1 || plan.Added[0].ID != "gh-3" || len(plan.Updated) != 1 || plan.Updated[0].ID != "bd-gh1" {
+		t.Fatalf("plan = %+v", plan)
+	}
+	if f := plan.Updated[0].Fields[0]; f.Field != "status" || f.Old != "open" || f.New != "closed" {
+		t.Errorf("first field change = %+v", f)
+	}
+	if len(plan.Issues) != 4 || plan.Issues[0].ID != "bd-1" {
+		t.Errorf("merged order = %v", plan.Issues)
+	}
+
+	// An imported ID that collides with an unrelated issue is refused.
+	clash := []model.Issue{{ID: "gh-3", Title: "Unrelated", Status: model.StatusOpen, IssueType: model.TypeTask}}
+	if _, err := Merge(clash, second.Issues); err == nil {
+		t.Error("expected ID collision error")
+	}
+}
@@ -0,0 +1,174 @@
+package importer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// jiraRow gives access to a Jira CSV row by header name. Jira repeats a
+// column once per value (Labels, Comment, issue links), so a name can map to
+// several columns.
+type jiraRow struct {
+	cols   map[string][]int
+	fields []string
+}
+
+func (r jiraRow) get(name string) string {
+	for _, v := range r.all(name) {
+		return v
+	}
+	return ""
+}
+
+func (r jiraRow) all(name string) []string {
+	var out []string
+	for _, i := range r.cols[strings.ToLower(name)] {
+		if i < len(r.fields) {
+			if v := strings.TrimSpace(r.fields[i]); v != "" {
+				out = append(out, v)
+			}
+		}
+	}
+	return out
+}
+
+// parseJira reads a Jira "Export Excel CSV (all fields)" file
+func parseJira(r io.Reader) ([]record, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+	header, err := cr.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, fmt.Errorf("empty CSV file")
+		}
+		return nil, err
+	}
+	cols := make(map[string][]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		cols[name] = append(cols[name], i)
+	}
+	if len(cols["issue key"]) == 0 || len(cols["summary"]) == 0 {
+		return nil, fmt.Errorf("missing \"Issue key\" or \"Summary\" column; is this a Jira CSV export?")
+	}
+
+	var records []record
+	idToKey := make(map[string]string)
+	for {
+		fields, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		row := jiraRow{cols: cols, fields: fields}
+		rec := jiraRecord(row)
+		if id := row.get("Issue id"); id != "" {
+			idToKey[id] = rec.key
+		}
+		records = append(records, rec)
+	}
+
+	// Cloud exports name the parent by numeric issue id.
+	for i := range records {
+		if key, ok := idToKey[records[i].parent]; ok {
+			records[i].parent = key
+		}
+	}
+	return records, nil
+}
+
+func jiraRecord(row jiraRow) record {
+	key := row.get("Issue key")
+	rec := record{key: key}
+	project, number, ok := strings.Cut(key, "-")
+	if !ok {
+		rec.warning = fmt.Sprintf("skipping Jira row with issue key %q", key)
+		return rec
+	}
+	rec.prefix, rec.number = project, number
+
+	issue := model.Issue{
+		Title:       row.get("Summary"),
+		Description: row.get("Description"),
+		Assignee:    row.get("Assignee"),
+		ExternalRef: strPtr("jira:" + key),
+		Priority:    2,
+		Labels:      dedupeLabels(row.all("Labels")),
+	}
+	if t, ok := typeFromName(row.get("Issue Type")); ok {
+		issue.IssueType = t
+	}
+	if p, ok := priorityFromName(row.get("Priority")); ok {
+		issue.Priority = p
+	}
+	issue.Status = jiraStatus(row.get("Status"), row.get("Status Category"), row.get("Resolved"))
+	if issue.Status == model.StatusClosed {
+		issue.ClosedAt = timePtr(row.get("Resolved"))
+		issue.CloseReason = row.get("Resolution")
+	}
+	if t, ok := parseTime(row.get("Created")); ok {
+		issue.CreatedAt = t
+	}
+	if t, ok := parseTime(row.get("Updated")); ok {
+		issue.UpdatedAt = t
+	}
+	issue.DueDate = timePtr(row.get("Due Date"))
+	if secs, err := strconv.Atoi(row.get("Original Estimate")); err == nil && secs > 0 {
+		minutes := secs / 60
+		issue.EstimatedMinutes = &minutes
+	}
+
+	// Comment cells are "date;author;text".
+	for _, cell := range row.all("Comment") {
+		comment := &model.Comment{Text: cell}
+		if parts := strings.SplitN(cell, ";", 3); len(parts) == 3 {
+			if t, ok := parseTime(parts[0]); ok {
+				comment.CreatedAt = t
+				comment.Author = parts[1]
+				comment.Text = parts[2]
+			}
+		}
+		issue.Comments = append(issue.Comments, comment)
+	}
+
+	rec.parent = firstNonEmpty(row.get("Parent"), row.get("Parent id"), row.get("Custom field (Epic Link)"))
+	// Inward "Blocks" links are the issues this one "is blocked by".
+	rec.blockedBy = row.all("Inward issue link (Blocks)")
+	rec.blocks = row.all("Outward issue link (Blocks)")
+	rec.related = append(row.all("Inward issue link (Relates)"), row.all("Outward issue link (Relates)")...)
+
+	rec.issue = issue
+	return rec
+}
+
+func jiraStatus(status, category, resolved string) model.Status {
+	switch strings.ToLower(category) {
+	case "done":
+		return model.StatusClosed
+	case "in progress":
+		if strings.EqualFold(status, "blocked") {
+			return model.StatusBlocked
+		}
+		return model.StatusInProgress
+	}
+	switch strings.ToLower(status) {
+	case "done", "closed", "resolved", "cancelled", "canceled", "won't do", "won't fix":
+		return model.StatusClosed
+	case "in progress", "in review", "in development", "review", "testing", "qa":
+		return model.StatusInProgress
+	case "blocked", "on hold":
+		return model.StatusBlocked
+	}
+	if resolved != "" {
+		return model.StatusClosed
+	}
+	return model.StatusOpen
+}
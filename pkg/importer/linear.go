@@ -0,0 +1,194 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// linearList is a GraphQL connection ({"nodes": [...]}) or a plain array
+type linearList[T any] []T
+
+func (l *linearList[T]) UnmarshalJSON(b []byte) error {
+	var items []T
+	if err := json.Unmarshal(b, &items); err == nil {
+		*l = items
+		return nil
+	}
+	var conn struct {
+		Nodes []T `json:"nodes"`
+	}
+	if err := json.Unmarshal(b, &conn); err != nil {
+		return err
+	}
+	*l = conn.Nodes
+	return nil
+}
+
+type linearRef struct {
+	Identifier string `json:"identifier"`
+}
+
+type linearUser struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+	Email       string `json:"email"`
+}
+
+type linearIssue struct {
+	Identifier  string `json:"identifier"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Priority    int    `json:"priority"`
+	URL         string `json:"url"`
+	State       struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	} `json:"state"`
+	Labels linearList[struct {
+		Name string `json:"name"`
+	}] `json:"labels"`
+	Assignee *linearUser `json:"assignee"`
+	Comments linearList[struct {
+		Body      string      `json:"body"`
+		User      *linearUser `json:"user"`
+		CreatedAt string      `json:"createdAt"`
+	}] `json:"comments"`
+	Parent    *linearRef `json:"parent"`
+	Relations linearList[struct {
+		Type         string    `json:"type"`
+		RelatedIssue linearRef `json:"relatedIssue"`
+	}] `json:"relations"`
+	InverseRelations linearList[struct {
+		Type  string    `json:"type"`
+		Issue linearRef `json:"issue"`
+	}] `json:"inverseRelations"`
+	CreatedAt   string `json:"createdAt"`
+	UpdatedAt   string `json:"updatedAt"`
+	CompletedAt string `json:"completedAt"`
+	CanceledAt  string `json:"canceledAt"`
+	DueDate     string `json:"dueDate"`
+}
+
+// parseLinear reads Linear GraphQL output: a bare array of issues, or the
+// issues connection under "issues" or "data.issues".
+func parseLinear(r io.Reader) ([]record, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var issues linearList[linearIssue]
+	if err := json.Unmarshal(b, &issues); err != nil || len(issues) == 0 {
+		var wrapped struct {
+			Issues linearList[linearIssue] `json:"issues"`
+			Data   struct {
+				Issues linearList[linearIssue] `json:"issues"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(b, &wrapped); err != nil {
+			return nil, err
+		}
+		issues = append(wrapped.Issues, wrapped.Data.Issues...)
+	}
+
+	records := make([]record, 0, len(issues))
+	for _, li := range issues {
+		records = append(records, li.record())
+	}
+	return records, nil
+}
+
+func (li linearIssue) record() record {
+	rec := record{key: li.Identifier}
+	team, number, ok := strings.Cut(li.Identifier, "-")
+	if !ok {
+		rec.warning = fmt.Sprintf("skipping Linear issue with identifier %q", li.Identifier)
+		return rec
+	}
+	rec.prefix, rec.number = team, number
+
+	ref := li.URL
+	if ref == "" {
+		ref = "linear:" + li.Identifier
+	}
+	issue := model.Issue{
+		Title:       li.Title,
+		Description: li.Description,
+		ExternalRef: strPtr(ref),
+		Priority:    linearPriority(li.Priority),
+		DueDate:     timePtr(li.DueDate),
+	}
+	if li.Assignee != nil {
+		issue.Assignee = firstNonEmpty(li.Assignee.DisplayName, li.Assignee.Name, li.Assignee.Email)
+	}
+	if t, ok := parseTime(li.CreatedAt); ok {
+		issue.CreatedAt = t
+	}
+	if t, ok := parseTime(li.UpdatedAt); ok {
+		issue.UpdatedAt = t
+	}
+
+	switch strings.ToLower(li.State.Type) {
+	case "completed":
+		issue.Status = model.StatusClosed
+		issue.ClosedAt = timePtr(li.CompletedAt)
+	case "canceled":
+		issue.Status = model.StatusClosed
+		issue.ClosedAt = timePtr(li.CanceledAt)
+		issue.CloseReason = "canceled"
+	case "started":
+		issue.Status = model.StatusInProgress
+	default:
+		issue.Status = model.StatusOpen
+	}
+
+	var labels []string
+	for _, l := range li.Labels {
+		if t, ok := typeFromName(l.Name); ok && issue.IssueType == "" {
+			issue.IssueType = t
+		}
+		labels = append(labels, l.Name)
+	}
+	issue.Labels = dedupeLabels(labels)
+
+	for _, c := range li.Comments {
+		comment := &model.Comment{Text: c.Body}
+		if c.User != nil {
+			comment.Author = firstNonEmpty(c.User.DisplayName, c.User.Name, c.User.Email)
+		}
+		comment.CreatedAt, _ = parseTime(c.CreatedAt)
+		issue.Comments = append(issue.Comments, comment)
+	}
+
+	if li.Parent != nil {
+		rec.parent = li.Parent.Identifier
+	}
+	for _, rel := range li.Relations {
+		switch rel.Type {
+		case "blocks":
+			rec.blocks = append(rec.blocks, rel.RelatedIssue.Identifier)
+		case "related":
+			rec.related = append(rec.related, rel.RelatedIssue.Identifier)
+		}
+	}
+	for _, rel := range li.InverseRelations {
+		if rel.Type == "blocks" {
+			rec.blockedBy = append(rec.blockedBy, rel.Issue.Identifier)
+		}
+	}
+
+	rec.issue = issue
+	return rec
+}
+
+// linearPriority maps Linear's 0 (none), 1 (urgent) ... 4 (low) onto beads
+// P0-P3, with "none" as the beads default of P2.
+func linearPriority(p int) int {
+	if p >= 1 && p <= 4 {
+		return p - 1
+	}
+	return 2
+}
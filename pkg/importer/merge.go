@@ -0,0 +1,206 @@
+package importer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// Plan is the result of merging imported issues into an existing beads file
+type Plan struct {
+	// Issues is the merged issue set to write: existing issues in their
+	// original order, then new ones in import order.
+	Issues    []model.Issue
+	Added     []Change
+	Updated   []Change
+	Unchanged int
+}
+
+// Change describes one added or updated issue
+type Change struct {
+	ID     string
+	Title  string
+	Fields []FieldChange // empty for added issues
+}
+
+// FieldChange is one field that an import changes
+type FieldChange struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// Empty reports whether the import changes nothing
+func (p Plan) Empty() bool { return len(p.Added) == 0 && len(p.Updated) == 0 }
+
+// Merge folds imported issues into existing ones. An imported issue replaces
+// the existing issue with the same ExternalRef (keeping its beads ID), so a
+// re-import updates rather than duplicates. Fields the source trackers don't
+// have (design, notes, acceptance criteria, ack state) and dependencies added
+// locally are kept. An imported ID that is already taken by an unrelated
+// issue is an error.
+func Merge(existing, imported []model.Issue) (Plan, error) {
+	byRef := make(map[string]int)
+	byID := make(map[string]int, len(existing))
+	for i, issue := range existing {
+		byID[issue.ID] = i
+		if issue.ExternalRef != nil && *issue.ExternalRef != "" {
+			byRef[*issue.ExternalRef] = i
+		}
+	}
+
+	// Map imported IDs onto the IDs of the issues they update.
+	rename := make(map[string]string)
+	target := make(map[string]int)
+	for _, issue := range imported {
+		if issue.ExternalRef != nil {
+			if i, ok := byRef[*issue.ExternalRef]; ok {
+				rename[issue.ID] = existing[i].ID
+				target[issue.ID] = i
+				continue
+			}
+		}
+		if i, ok := byID[issue.ID]; ok {
+			return Plan{}, fmt.Errorf("ID %s is already used by %q; choose another --prefix", issue.ID, existing[i].Title)
+		}
+	}
+
+	plan := Plan{Issues: make([]model.Issue, len(existing))}
+	for i, issue := range existing {
+		plan.Issues[i] = issue.Clone()
+	}
+	for _, issue := range imported {
+		i, matched := target[issue.ID]
+		issue = issue.Clone()
+		if id, ok := rename[issue.ID]; ok {
+			issue.ID = id
+		}
+		for _, dep := range issue.Dependencies {
+			dep.IssueID = issue.ID
+			if id, ok := rename[dep.DependsOnID]; ok {
+				dep.DependsOnID = id
+			}
+		}
+		for _, c := range issue.Comments {
+			c.IssueID = issue.ID
+		}
+
+		if !matched {
+			plan.Issues = append(plan.Issues, issue)
+			plan.Added = append(plan.Added, Change{ID: issue.ID, Title: issue.Title})
+			continue
+		}
+
+		old := plan.Issues[i]
+		merged := mergeIssue(old, issue)
+		if fields := diffIssues(old, merged); len(fields) > 0 {
+			plan.Issues[i] = merged
+			plan.Updated = append(plan.Updated, Change{ID: merged.ID, Title: merged.Title, Fields: fields})
+		} else {
+			plan.Unchanged++
+		}
+	}
+	return plan, nil
+}
+
+// mergeIssue overlays an imported issue on the existing one
+func mergeIssue(old, imported model.Issue) model.Issue {
+	merged := imported
+	merged.ContentHash = old.ContentHash
+	merged.Design = old.Design
+	merged.AcceptanceCriteria = old.AcceptanceCriteria
+	merged.Notes = old.Notes
+	merged.SourceRepo = old.SourceRepo
+	merged.AckStatus = old.AckStatus
+	merged.BounceCount = old.BounceCount
+	merged.Deadline = old.Deadline
+	merged.DeferredFrom = old.DeferredFrom
+	merged.Escalated = old.Escalated
+	if merged.EstimatedMinutes == nil {
+		merged.EstimatedMinutes = old.EstimatedMinutes
+	}
+
+	// Keep dependencies added in beads, and the original creation details of
+	// ones that are unchanged.
+	have := make(map[string]*model.Dependency)
+	for _, dep := range merged.Dependencies {
+		have[dep.DependsOnID+"|"+string(dep.Type)] = dep
+	}
+	for _, dep := range old.Dependencies {
+		if dep == nil {
+			continue
+		}
+		if cur, ok := have[dep.DependsOnID+"|"+string(dep.Type)]; ok {
+			cur.CreatedAt, cur.CreatedBy = dep.CreatedAt, dep.CreatedBy
+			continue
+		}
+		merged.Dependencies = append(merged.Dependencies, dep)
+	}
+	return merged
+}
+
+// diffIssues lists the user-visible fields that differ
+func diffIssues(a, b model.Issue) []FieldChange {
+	var out []FieldChange
+	add := func(field, old, new string) {
+		if old != new {
+			out = append(out, FieldChange{Field: field, Old: old, New: new})
+		}
+	}
+	add("title", a.Title, b.Title)
+	add("status", string(a.Status), string(b.Status))
+	add("priority", fmt.Sprintf("P%d", a.Priority), fmt.Sprintf("P%d", b.Priority))
+	add("type", string(a.IssueType), string(b.IssueType))
+	add("assignee", a.Assignee, b.Assignee)
+	add("labels", strings.Join(a.Labels, ","), strings.Join(b.Labels, ","))
+	add("due", formatTime(a.DueDate), formatTime(b.DueDate))
+	add("closed", formatTime(a.ClosedAt), formatTime(b.ClosedAt))
+	add("close_reason", a.CloseReason, b.CloseReason)
+	add("dependencies", depList(a.Dependencies), depList(b.Dependencies))
+	if len(a.Comments) != len(b.Comments) {
+		add("comments", fmt.Sprint(len(a.Comments)), fmt.Sprint(len(b.Comments)))
+	} else if !commentsEqual(a.Comments, b.Comments) {
+		out = append(out, FieldChange{Field: "comments", Old: fmt.Sprint(len(a.Comments)), New: fmt.Sprintf("%d (edited)", len(b.Comments))})
+	}
+	if a.Description != b.Description {
+		out = append(out, FieldChange{Field: "description", Old: fmt.Sprintf("%d chars", len(a.Description)), New: fmt.Sprintf("%d chars", len(b.Description))})
+	}
+	if len(out) == 0 && !a.UpdatedAt.Equal(b.UpdatedAt) {
+		add("updated", a.UpdatedAt.Format(time.RFC3339), b.UpdatedAt.Format(time.RFC3339))
+	}
+	return out
+}
+
+func commentsEqual(a, b []*model.Comment) bool {
+	for i := range a {
+		if a[i] == nil || b[i] == nil {
+			if a[i] != b[i] {
+				return false
+			}
+			continue
+		}
+		if a[i].Author != b[i].Author || a[i].Text != b[i].Text {
+			return false
+		}
+	}
+	return true
+}
+
+func depList(deps []*model.Dependency) string {
+	var parts []string
+	for _, d := range deps {
+		if d != nil {
+			parts = append(parts, string(d.Type)+":"+d.DependsOnID)
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
@@ -129,19 +129,10 @@ func FindJSONLPathWithWarnings(beadsDir string, warnFunc func(msg string)) (stri
 
 // LoadIssues reads issues from the beads directory.
 // Respects BEADS_DIR environment variable, otherwise uses .beads in repoPath.
-// Automatically finds the correct JSONL file (issues.jsonl preferred, beads.jsonl fallback).
+// Reads the JSONL file (issues.jsonl preferred, beads.jsonl fallback) or the
+// beads SQLite database, whichever is fresher; see LoadIssuesWithOptions.
 func LoadIssues(repoPath string) ([]model.Issue, error) {
-	beadsDir, err := GetBeadsDir(repoPath)
-	if err != nil {
-		return nil, err
-	}
-
-	jsonlPath, err := FindJSONLPath(beadsDir)
-	if err != nil {
-		return nil, err
-	}
-
-	return LoadIssuesFromFile(jsonlPath)
+	return LoadIssuesWithOptions(repoPath, ParseOptions{})
 }
 
 // DefaultMaxBufferSize is the default buffer size for the scanner (10MB).
@@ -160,11 +151,15 @@ type ParseOptions struct {
 }
 
 // LoadIssuesFromFileWithOptions reads issues from a file with custom options.
+// A beads SQLite database is read with LoadIssuesFromDBWithOptions.
 func LoadIssuesFromFileWithOptions(path string, opts ParseOptions) ([]model.Issue, error) {
 	// Check if file exists
 	if _, err := os.Stat(path); os.IsNotExist(err) {
 		return nil, fmt.Errorf("no beads issues found at %s", path)
 	}
+	if IsSQLiteFile(path) {
+		return LoadIssuesFromDBWithOptions(path, opts)
+	}
 
 	file, err := os.Open(path)
 	if err != nil {
@@ -188,6 +183,13 @@ func ParseIssues(r io.Reader) ([]model.Issue, error) {
 
 // ParseIssuesWithOptions parses JSONL content with custom options.
 func ParseIssuesWithOptions(r io.Reader, opts ParseOptions) ([]model.Issue, error) {
+	return parseIssues(r, opts, nil)
+}
+
+// parseIssues implements ParseIssuesWithOptions. When known is non-nil, each
+// issue's ContentHash is set to the hash of its line, and a line whose hash is
+// already in known reuses that issue instead of being decoded again.
+func parseIssues(r io.Reader, opts ParseOptions, known map[string]model.Issue) ([]model.Issue, error) {
 	var issues []model.Issue
 
 	// Determine buffer size
@@ -198,17 +200,7 @@ func ParseIssuesWithOptions(r io.Reader, opts ParseOptions) ([]model.Issue, erro
 
 	reader := bufio.NewReaderSize(r, maxCapacity)
 
-	// Default warning handler prints to stderr (suppressed in robot mode).
-	warn := opts.WarningHandler
-	if warn == nil {
-		if os.Getenv("BV_ROBOT") == "1" {
-			warn = func(string) {}
-		} else {
-			warn = func(msg string) {
-				fmt.Fprintf(os.Stderr, "Warning: %s\n", msg)
-			}
-		}
-	}
+	warn := warningHandler(opts)
 
 	lineNum := 0
 	for {
@@ -248,6 +240,15 @@ func ParseIssuesWithOptions(r io.Reader, opts ParseOptions) ([]model.Issue, erro
 			line = stripBOM(line)
 		}
 
+		var hash string
+		if known != nil {
+			hash = lineHash(line)
+			if issue, ok := known[hash]; ok {
+				issues = append(issues, issue)
+				continue
+			}
+		}
+
 		var issue model.Issue
 		if err := json.Unmarshal(line, &issue); err != nil {
 			// Skip malformed lines but warn
@@ -262,12 +263,27 @@ func ParseIssuesWithOptions(r io.Reader, opts ParseOptions) ([]model.Issue, erro
 			continue
 		}
 
+		issue.ContentHash = hash
 		issues = append(issues, issue)
 	}
 
 	return issues, nil
 }
 
+// warningHandler returns opts.WarningHandler, defaulting to printing on
+// stderr (suppressed in robot mode).
+func warningHandler(opts ParseOptions) func(string) {
+	if opts.WarningHandler != nil {
+		return opts.WarningHandler
+	}
+	if os.Getenv("BV_ROBOT") == "1" {
+		return func(string) {}
+	}
+	return func(msg string) {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", msg)
+	}
+}
+
 // stripBOM removes the UTF-8 Byte Order Mark if present
 func stripBOM(b []byte) []byte {
 	if bytes.HasPrefix(b, []byte{0xEF, 0xBB, 0xBF}) {
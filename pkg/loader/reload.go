@@ -0,0 +1,144 @@
+package loader
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// IssueDelta lists the issue IDs that differ between two loads.
+type IssueDelta struct {
+	Added    []string
+	Removed  []string
+	Modified []string
+}
+
+// Empty reports whether nothing changed.
+func (d IssueDelta) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Modified) == 0
+}
+
+// Changed returns every added, removed and modified ID, sorted.
+func (d IssueDelta) Changed() []string {
+	ids := make([]string, 0, len(d.Added)+len(d.Removed)+len(d.Modified))
+	ids = append(ids, d.Added...)
+	ids = append(ids, d.Removed...)
+	ids = append(ids, d.Modified...)
+	sort.Strings(ids)
+	return ids
+}
+
+// Reloader loads the same issue source repeatedly and reports what changed
+// between loads, keyed on Issue.ContentHash. For JSONL, a line whose hash
+// matches the previous load reuses the already-parsed issue instead of being
+// decoded again, so a reload costs a hash per line plus a parse per change.
+//
+// ContentHash is set to the hash of the issue's JSONL line, or of its JSON
+// encoding for a SQLite source. A Reloader is not safe for concurrent use.
+type Reloader struct {
+	path   string
+	byHash map[string]model.Issue // content hash -> issue, from the previous load
+	hashes map[string]string      // issue ID -> content hash, from the previous load
+	loaded bool
+}
+
+// NewReloader returns a Reloader for the issue file at path.
+func NewReloader(path string) *Reloader {
+	return &Reloader{path: path}
+}
+
+// Path returns the file the Reloader reads.
+func (r *Reloader) Path() string {
+	return r.path
+}
+
+// Loaded reports whether a previous Load succeeded, i.e. whether the next
+// delta is relative to real data rather than reporting every issue as added.
+func (r *Reloader) Loaded() bool {
+	return r.loaded
+}
+
+// Load reads the file and returns its issues with the delta from the previous
+// successful Load. The first call reports every issue as added. On error the
+// previous state is kept.
+func (r *Reloader) Load(opts ParseOptions) ([]model.Issue, IssueDelta, error) {
+	issues, err := r.read(opts)
+	if err != nil {
+		return nil, IssueDelta{}, err
+	}
+
+	byHash := make(map[string]model.Issue, len(issues))
+	hashes := make(map[string]string, len(issues))
+	for _, issue := range issues {
+		byHash[issue.ContentHash] = issue
+		hashes[issue.ID] = issue.ContentHash
+	}
+
+	var delta IssueDelta
+	for id, hash := range hashes {
+		prev, ok := r.hashes[id]
+		switch {
+		case !ok:
+			delta.Added = append(delta.Added, id)
+		case prev != hash:
+			delta.Modified = append(delta.Modified, id)
+		}
+	}
+	for id := range r.hashes {
+		if _, ok := hashes[id]; !ok {
+			delta.Removed = append(delta.Removed, id)
+		}
+	}
+	sort.Strings(delta.Added)
+	sort.Strings(delta.Removed)
+	sort.Strings(delta.Modified)
+
+	r.byHash, r.hashes, r.loaded = byHash, hashes, true
+	return issues, delta, nil
+}
+
+func (r *Reloader) read(opts ParseOptions) ([]model.Issue, error) {
+	if _, err := os.Stat(r.path); os.IsNotExist(err) {
+		return nil, fmt.Errorf("no beads issues found at %s", r.path)
+	}
+
+	if IsSQLiteFile(r.path) {
+		issues, err := LoadIssuesFromDBWithOptions(r.path, opts)
+		if err != nil {
+			return nil, err
+		}
+		// bd's content_hash column does not cover dependencies, labels or
+		// comments, so hash the whole issue instead.
+		for i := range issues {
+			data, err := json.Marshal(issues[i])
+			if err != nil {
+				return nil, fmt.Errorf("failed to hash issue %s: %w", issues[i].ID, err)
+			}
+			issues[i].ContentHash = lineHash(data)
+		}
+		return issues, nil
+	}
+
+	file, err := os.Open(r.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open issues file: %w", err)
+	}
+	defer file.Close()
+
+	known := r.byHash
+	if known == nil {
+		known = map[string]model.Issue{}
+	}
+	return parseIssues(file, opts, known)
+}
+
+// lineHash returns the hex SHA-256 of b.
+func lineHash(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
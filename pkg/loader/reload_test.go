@@ -0,0 +1,121 @@
+package loader_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+)
+
+func TestReloader_ReportsDeltaAndReusesUnchangedIssues(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "issues.jsonl")
+	write := func(lines ...string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	a := `{"id":"A","title":"A","status":"open","issue_type":"task"}`
+	b := `{"id":"B","title":"B","status":"open","issue_type":"task","labels":["x"]}`
+	c := `{"id":"C","title":"C","status":"open","issue_type":"task"}`
+
+	write(a, b)
+	r := loader.NewReloader(path)
+	if r.Loaded() {
+		t.Fatal("Loaded before the first Load")
+	}
+	issues, delta, err := r.Load(loader.ParseOptions{})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !reflect.DeepEqual(delta.Added, []string{"A", "B"}) || len(delta.Removed) != 0 || len(delta.Modified) != 0 {
+		t.Fatalf("first delta = %+v", delta)
+	}
+	if issues[0].ContentHash == "" || issues[0].ContentHash == issues[1].ContentHash {
+		t.Fatalf("content hashes not set: %q %q", issues[0].ContentHash, issues[1].ContentHash)
+	}
+	firstB := issues[1]
+
+	// A changes, B is untouched, C is new.
+	write(`{"id":"A","title":"A","status":"closed","issue_type":"task"}`, b, c)
+	issues, delta, err = r.Load(loader.ParseOptions{})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !reflect.DeepEqual(delta.Added, []string{"C"}) || !reflect.DeepEqual(delta.Modified, []string{"A"}) || len(delta.Removed) != 0 {
+		t.Fatalf("second delta = %+v", delta)
+	}
+	if &issues[1].Labels[0] != &firstB.Labels[0] {
+		t.Error("unchanged issue B was decoded again instead of reused")
+	}
+
+	write(b, c)
+	_, delta, err = r.Load(loader.ParseOptions{})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !reflect.DeepEqual(delta.Removed, []string{"A"}) || len(delta.Added) != 0 || len(delta.Modified) != 0 {
+		t.Fatalf("third delta = %+v", delta)
+	}
+	if got := delta.Changed(); !reflect.DeepEqual(got, []string{"A"}) {
+		t.Errorf("Changed = %v", got)
+	}
+
+	_, delta, _ = r.Load(loader.ParseOptions{})
+	if !delta.Empty() {
+		t.Errorf("expected empty delta for an unchanged file, got %+v", delta)
+	}
+}
+
+func TestReloader_KeepsStateOnError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "issues.jsonl")
+	if err := os.WriteFile(path, []byte(`{"id":"A","title":"A","status":"open","issue_type":"task"}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	r := loader.NewReloader(path)
+	if _, _, err := r.Load(loader.ParseOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := r.Load(loader.ParseOptions{}); err == nil {
+		t.Fatal("expected an error for a missing file")
+	}
+	if err := os.WriteFile(path, []byte(`{"id":"A","title":"A","status":"open","issue_type":"task"}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, delta, err := r.Load(loader.ParseOptions{})
+	if err != nil || !delta.Empty() {
+		t.Fatalf("expected no change after recovering, got %+v, %v", delta, err)
+	}
+}
+
+func TestReloader_SQLite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "beads.db")
+	createBeadsDB(t, path,
+		`INSERT INTO issues (id, title) VALUES ('bd-1', 'One'), ('bd-2', 'Two')`,
+	)
+	r := loader.NewReloader(path)
+	if _, _, err := r.Load(loader.ParseOptions{}); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	// A dependency changes only the dependencies table.
+	db := openDB(t, path)
+	if _, err := db.Exec(`INSERT INTO dependencies (issue_id, depends_on_id) VALUES ('bd-1', 'bd-2')`); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	_, delta, err := r.Load(loader.ParseOptions{})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !reflect.DeepEqual(delta.Modified, []string{"bd-1"}) || len(delta.Added) != 0 || len(delta.Removed) != 0 {
+		t.Fatalf("delta = %+v", delta)
+	}
+}
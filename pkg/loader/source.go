@@ -0,0 +1,192 @@
+package loader
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// SourceKind identifies the storage format of an issue source.
+type SourceKind string
+
+const (
+	SourceJSONL  SourceKind = "jsonl"
+	SourceSQLite SourceKind = "sqlite"
+)
+
+// Source is one place issues can be loaded from.
+type Source struct {
+	Kind SourceKind
+	Path string
+	// ModTime is the last write time. For SQLite it includes the -wal file,
+	// which receives writes before they are checkpointed into the database.
+	ModTime time.Time
+}
+
+// FindSources lists the issue sources in beadsDir, freshest first. At most one
+// JSONL file (chosen by FindJSONLPathWithWarnings) and one SQLite database
+// (chosen by FindDBPath) are returned.
+func FindSources(beadsDir string, warnFunc func(msg string)) ([]Source, error) {
+	var sources []Source
+
+	jsonlErr := error(nil)
+	emptyJSONL := false
+	if path, err := FindJSONLPathWithWarnings(beadsDir, warnFunc); err == nil {
+		if info, err := os.Stat(path); err == nil {
+			sources = append(sources, Source{Kind: SourceJSONL, Path: path, ModTime: info.ModTime()})
+			emptyJSONL = info.Size() == 0
+		}
+	} else {
+		jsonlErr = err
+	}
+	if path, err := FindDBPath(beadsDir); err == nil && IsSQLiteFile(path) {
+		if info, err := os.Stat(path); err == nil {
+			mod := info.ModTime()
+			if wal, err := os.Stat(path + "-wal"); err == nil && wal.ModTime().After(mod) {
+				mod = wal.ModTime()
+			}
+			sources = append(sources, Source{Kind: SourceSQLite, Path: path, ModTime: mod})
+		}
+	}
+
+	if len(sources) == 0 {
+		// Report the JSONL lookup error, which is what callers have always seen.
+		if jsonlErr != nil {
+			return nil, jsonlErr
+		}
+		return nil, fmt.Errorf("no beads issues found in %s", beadsDir)
+	}
+
+	// Freshest first; on a tie prefer JSONL, the format bd exports for git.
+	// An empty JSONL file never wins over a database.
+	sort.SliceStable(sources, func(i, j int) bool {
+		iEmpty := emptyJSONL && sources[i].Kind == SourceJSONL
+		jEmpty := emptyJSONL && sources[j].Kind == SourceJSONL
+		if iEmpty != jEmpty {
+			return jEmpty
+		}
+		return sources[i].ModTime.After(sources[j].ModTime)
+	})
+	return sources, nil
+}
+
+// FindIssuesPath returns the path of the freshest issue source in beadsDir:
+// the beads JSONL file or the beads SQLite database.
+func FindIssuesPath(beadsDir string) (string, error) {
+	sources, err := FindSources(beadsDir, nil)
+	if err != nil {
+		return "", err
+	}
+	return sources[0].Path, nil
+}
+
+// LoadIssuesWithOptions loads from the freshest source in the beads
+// directory. When both a JSONL file and a SQLite database exist, the other
+// one is loaded too and any divergence is reported through the warning
+// handler, since that usually means a bd sync is pending.
+func LoadIssuesWithOptions(repoPath string, opts ParseOptions) ([]model.Issue, error) {
+	beadsDir, err := GetBeadsDir(repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	warn := warningHandler(opts)
+	sources, err := FindSources(beadsDir, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	issues, err := LoadIssuesFromFileWithOptions(sources[0].Path, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sources) > 1 {
+		quiet := opts
+		quiet.WarningHandler = func(string) {}
+		if other, err := LoadIssuesFromFileWithOptions(sources[1].Path, quiet); err == nil {
+			if d := DiffSources(issues, other); !d.Empty() {
+				warn(fmt.Sprintf("%s and %s have diverged (%s); using %s, the more recently modified",
+					filepath.Base(sources[0].Path), filepath.Base(sources[1].Path),
+					d.Summary(filepath.Base(sources[0].Path), filepath.Base(sources[1].Path)),
+					filepath.Base(sources[0].Path)))
+			}
+		}
+	}
+
+	return issues, nil
+}
+
+// SourceDivergence lists issue IDs that differ between two sources.
+type SourceDivergence struct {
+	OnlyInPrimary   []string
+	OnlyInSecondary []string
+	Changed         []string
+}
+
+// Empty reports whether the sources agree.
+func (d SourceDivergence) Empty() bool {
+	return len(d.OnlyInPrimary) == 0 && len(d.OnlyInSecondary) == 0 && len(d.Changed) == 0
+}
+
+// Summary describes the divergence, e.g. "2 only in beads.db, 1 changed".
+func (d SourceDivergence) Summary(primary, secondary string) string {
+	var parts []string
+	if n := len(d.OnlyInPrimary); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d only in %s", n, primary))
+	}
+	if n := len(d.OnlyInSecondary); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d only in %s", n, secondary))
+	}
+	if n := len(d.Changed); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d changed", n))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// DiffSources compares two loads of the same project. Issues are matched by
+// ID and considered changed when their status, priority, title, update time
+// (to the second), or dependency, label or comment counts differ.
+func DiffSources(primary, secondary []model.Issue) SourceDivergence {
+	var d SourceDivergence
+	other := make(map[string]model.Issue, len(secondary))
+	for _, issue := range secondary {
+		other[issue.ID] = issue
+	}
+	seen := make(map[string]bool, len(primary))
+	for _, a := range primary {
+		seen[a.ID] = true
+		b, ok := other[a.ID]
+		if !ok {
+			d.OnlyInPrimary = append(d.OnlyInPrimary, a.ID)
+			continue
+		}
+		if sourceIssueChanged(a, b) {
+			d.Changed = append(d.Changed, a.ID)
+		}
+	}
+	for _, b := range secondary {
+		if !seen[b.ID] {
+			d.OnlyInSecondary = append(d.OnlyInSecondary, b.ID)
+		}
+	}
+	sort.Strings(d.OnlyInPrimary)
+	sort.Strings(d.OnlyInSecondary)
+	sort.Strings(d.Changed)
+	return d
+}
+
+func sourceIssueChanged(a, b model.Issue) bool {
+	return a.Status != b.Status ||
+		a.Priority != b.Priority ||
+		a.Title != b.Title ||
+		!a.UpdatedAt.Truncate(time.Second).Equal(b.UpdatedAt.Truncate(time.Second)) ||
+		len(a.Dependencies) != len(b.Dependencies) ||
+		len(a.Labels) != len(b.Labels) ||
+		len(a.Comments) != len(b.Comments)
+}
@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/Dicklesworthstone/beads_viewer/pkg/filelock"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
 )
 
@@ -112,13 +113,20 @@ func SaveSprints(repoPath string, sprints []model.Sprint) error {
 }
 
 // SaveSprintsToFile writes sprints to a specific file path.
-// The write is atomic (temp file + rename) to be safe with editors and watchers.
+// The write is atomic (temp file + rename) to be safe with editors and watchers,
+// and holds the beads directory's write lock.
 func SaveSprintsToFile(path string, sprints []model.Sprint) error {
 	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
 		return fmt.Errorf("failed to create directory: %w", err)
 	}
 
 	dir := filepath.Dir(path)
+	lock, err := filelock.LockDir(dir, 0)
+	if err != nil {
+		return fmt.Errorf("failed to lock %s: %w", dir, err)
+	}
+	defer lock.Unlock()
+
 	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp-*")
 	if err != nil {
 		return fmt.Errorf("failed to create temp file: %w", err)
@@ -0,0 +1,403 @@
+package loader
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+
+	_ "modernc.org/sqlite"
+)
+
+// PreferredDBNames lists beads SQLite database names in priority order.
+var PreferredDBNames = []string{"beads.db"}
+
+var sqliteMagic = []byte("SQLite format 3\x00")
+
+// IsSQLiteFile reports whether path is a SQLite database, judged by its
+// header rather than its extension.
+func IsSQLiteFile(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	header := make([]byte, len(sqliteMagic))
+	if _, err := f.Read(header); err != nil {
+		return false
+	}
+	return bytes.Equal(header, sqliteMagic)
+}
+
+// FindDBPath locates the beads SQLite database in beadsDir. beads.db is
+// preferred; otherwise the first other *.db file is used. Backups are skipped.
+func FindDBPath(beadsDir string) (string, error) {
+	entries, err := os.ReadDir(beadsDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to read beads directory: %w", err)
+	}
+
+	var candidates []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".db") || strings.Contains(name, ".backup") {
+			continue
+		}
+		candidates = append(candidates, name)
+	}
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("no beads database found in %s", beadsDir)
+	}
+
+	for _, preferred := range PreferredDBNames {
+		for _, name := range candidates {
+			if name == preferred {
+				return filepath.Join(beadsDir, name), nil
+			}
+		}
+	}
+	return filepath.Join(beadsDir, candidates[0]), nil
+}
+
+// LoadIssuesFromDB reads issues, dependencies, labels and comments from a
+// beads SQLite database.
+func LoadIssuesFromDB(path string) ([]model.Issue, error) {
+	return LoadIssuesFromDBWithOptions(path, ParseOptions{})
+}
+
+// LoadIssuesFromDBWithOptions is LoadIssuesFromDB with a custom warning
+// handler. BufferSize is ignored.
+//
+// Only columns present in the database are read, so older and newer beads
+// schemas both load. Invalid rows are skipped with a warning, matching the
+// JSONL parser.
+func LoadIssuesFromDBWithOptions(path string, opts ParseOptions) ([]model.Issue, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, fmt.Errorf("no beads database found at %s", path)
+	}
+
+	// Read-only with a busy timeout so a concurrent bd write does not fail
+	// the load outright.
+	db, err := sql.Open("sqlite", "file:"+path+"?mode=ro&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open beads database: %w", err)
+	}
+	defer db.Close()
+
+	warn := warningHandler(opts)
+
+	issues, index, err := readDBIssues(db, warn)
+	if err != nil {
+		return nil, err
+	}
+	if err := readDBDependencies(db, issues, index); err != nil {
+		return nil, err
+	}
+	if err := readDBLabels(db, issues, index); err != nil {
+		return nil, err
+	}
+	if err := readDBComments(db, issues, index); err != nil {
+		return nil, err
+	}
+
+	valid := issues[:0]
+	for _, issue := range issues {
+		if err := issue.Validate(); err != nil {
+			warn(fmt.Sprintf("skipping invalid issue %s in %s: %v", issue.ID, path, err))
+			continue
+		}
+		valid = append(valid, issue)
+	}
+	return valid, nil
+}
+
+// dbIssueColumns maps beads issues-table columns to Issue fields.
+var dbIssueColumns = []struct {
+	name string
+	set  func(*model.Issue, any)
+}{
+	{"id", func(i *model.Issue, v any) { i.ID = dbString(v) }},
+	{"content_hash", func(i *model.Issue, v any) { i.ContentHash = dbString(v) }},
+	{"title", func(i *model.Issue, v any) { i.Title = dbString(v) }},
+	{"description", func(i *model.Issue, v any) { i.Description = dbString(v) }},
+	{"design", func(i *model.Issue, v any) { i.Design = dbString(v) }},
+	{"acceptance_criteria", func(i *model.Issue, v any) { i.AcceptanceCriteria = dbString(v) }},
+	{"notes", func(i *model.Issue, v any) { i.Notes = dbString(v) }},
+	{"status", func(i *model.Issue, v any) { i.Status = model.Status(dbString(v)) }},
+	{"priority", func(i *model.Issue, v any) { i.Priority = int(dbInt(v)) }},
+	{"issue_type", func(i *model.Issue, v any) { i.IssueType = model.IssueType(dbString(v)) }},
+	{"assignee", func(i *model.Issue, v any) { i.Assignee = dbString(v) }},
+	{"estimated_minutes", func(i *model.Issue, v any) {
+		if v != nil {
+			n := int(dbInt(v))
+			i.EstimatedMinutes = &n
+		}
+	}},
+	{"created_at", func(i *model.Issue, v any) { i.CreatedAt = dbTime(v) }},
+	{"updated_at", func(i *model.Issue, v any) { i.UpdatedAt = dbTime(v) }},
+	{"due_date", func(i *model.Issue, v any) { i.DueDate = dbTimePtr(v) }},
+	{"closed_at", func(i *model.Issue, v any) { i.ClosedAt = dbTimePtr(v) }},
+	{"close_reason", func(i *model.Issue, v any) { i.CloseReason = dbString(v) }},
+	{"external_ref", func(i *model.Issue, v any) {
+		if s := dbString(v); s != "" {
+			i.ExternalRef = &s
+		}
+	}},
+	{"compaction_level", func(i *model.Issue, v any) { i.CompactionLevel = int(dbInt(v)) }},
+	{"compacted_at", func(i *model.Issue, v any) { i.CompactedAt = dbTimePtr(v) }},
+	{"compacted_at_commit", func(i *model.Issue, v any) {
+		if s := dbString(v); s != "" {
+			i.CompactedAtCommit = &s
+		}
+	}},
+	{"original_size", func(i *model.Issue, v any) { i.OriginalSize = int(dbInt(v)) }},
+	{"source_repo", func(i *model.Issue, v any) { i.SourceRepo = dbString(v) }},
+}
+
+func readDBIssues(db *sql.DB, warn func(string)) ([]model.Issue, map[string]int, error) {
+	present, err := dbTableColumns(db, "issues")
+	if err != nil {
+		return nil, nil, err
+	}
+	if !present["id"] {
+		return nil, nil, fmt.Errorf("beads database has no issues table")
+	}
+
+	var cols []string
+	var setters []func(*model.Issue, any)
+	for _, c := range dbIssueColumns {
+		if present[c.name] {
+			cols = append(cols, c.name)
+			setters = append(setters, c.set)
+		}
+	}
+
+	rows, err := db.Query("SELECT " + strings.Join(cols, ", ") + " FROM issues ORDER BY id")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query issues: %w", err)
+	}
+	defer rows.Close()
+
+	var issues []model.Issue
+	index := make(map[string]int)
+	values := make([]any, len(cols))
+	ptrs := make([]any, len(cols))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, nil, fmt.Errorf("failed to read issue row: %w", err)
+		}
+		var issue model.Issue
+		for i, set := range setters {
+			set(&issue, values[i])
+		}
+		if _, dup := index[issue.ID]; dup {
+			warn(fmt.Sprintf("skipping duplicate issue %s in beads database", issue.ID))
+			continue
+		}
+		index[issue.ID] = len(issues)
+		issues = append(issues, issue)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to read issues: %w", err)
+	}
+	return issues, index, nil
+}
+
+func readDBDependencies(db *sql.DB, issues []model.Issue, index map[string]int) error {
+	present, err := dbTableColumns(db, "dependencies")
+	if err != nil || len(present) == 0 {
+		return err
+	}
+	createdAt, createdBy := "NULL", "NULL"
+	if present["created_at"] {
+		createdAt = "created_at"
+	}
+	if present["created_by"] {
+		createdBy = "created_by"
+	}
+
+	rows, err := db.Query("SELECT issue_id, depends_on_id, type, " + createdAt + ", " + createdBy +
+		" FROM dependencies ORDER BY issue_id, depends_on_id")
+	if err != nil {
+		return fmt.Errorf("failed to query dependencies: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var issueID, dependsOnID, depType, at, by any
+		if err := rows.Scan(&issueID, &dependsOnID, &depType, &at, &by); err != nil {
+			return fmt.Errorf("failed to read dependency row: %w", err)
+		}
+		idx, ok := index[dbString(issueID)]
+		if !ok {
+			continue
+		}
+		issues[idx].Dependencies = append(issues[idx].Dependencies, &model.Dependency{
+			IssueID:     dbString(issueID),
+			DependsOnID: dbString(dependsOnID),
+			Type:        model.DependencyType(dbString(depType)),
+			CreatedAt:   dbTime(at),
+			CreatedBy:   dbString(by),
+		})
+	}
+	return rows.Err()
+}
+
+func readDBLabels(db *sql.DB, issues []model.Issue, index map[string]int) error {
+	present, err := dbTableColumns(db, "labels")
+	if err != nil || len(present) == 0 {
+		return err
+	}
+
+	rows, err := db.Query("SELECT issue_id, label FROM labels ORDER BY issue_id, label")
+	if err != nil {
+		return fmt.Errorf("failed to query labels: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var issueID, label any
+		if err := rows.Scan(&issueID, &label); err != nil {
+			return fmt.Errorf("failed to read label row: %w", err)
+		}
+		if idx, ok := index[dbString(issueID)]; ok {
+			issues[idx].Labels = append(issues[idx].Labels, dbString(label))
+		}
+	}
+	return rows.Err()
+}
+
+func readDBComments(db *sql.DB, issues []model.Issue, index map[string]int) error {
+	present, err := dbTableColumns(db, "comments")
+	if err != nil || len(present) == 0 {
+		return err
+	}
+
+	rows, err := db.Query("SELECT id, issue_id, author, text, created_at FROM comments ORDER BY issue_id, created_at, id")
+	if err != nil {
+		return fmt.Errorf("failed to query comments: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, issueID, author, text, at any
+		if err := rows.Scan(&id, &issueID, &author, &text, &at); err != nil {
+			return fmt.Errorf("failed to read comment row: %w", err)
+		}
+		if idx, ok := index[dbString(issueID)]; ok {
+			issues[idx].Comments = append(issues[idx].Comments, &model.Comment{
+				ID:        dbInt(id),
+				IssueID:   dbString(issueID),
+				Author:    dbString(author),
+				Text:      dbString(text),
+				CreatedAt: dbTime(at),
+			})
+		}
+	}
+	return rows.Err()
+}
+
+// dbTableColumns returns the column names of table, or an empty set when the
+// table does not exist.
+func dbTableColumns(db *sql.DB, table string) (map[string]bool, error) {
+	rows, err := db.Query("SELECT name FROM pragma_table_info(?)", table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect %s table: %w", table, err)
+	}
+	defer rows.Close()
+
+	cols := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to inspect %s table: %w", table, err)
+		}
+		cols[name] = true
+	}
+	return cols, rows.Err()
+}
+
+func dbString(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case []byte:
+		return string(x)
+	case int64:
+		return strconv.FormatInt(x, 10)
+	case time.Time:
+		return x.Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(x)
+	}
+}
+
+func dbInt(v any) int64 {
+	switch x := v.(type) {
+	case int64:
+		return x
+	case float64:
+		return int64(x)
+	case string:
+		n, _ := strconv.ParseInt(strings.TrimSpace(x), 10, 64)
+		return n
+	case []byte:
+		n, _ := strconv.ParseInt(strings.TrimSpace(string(x)), 10, 64)
+		return n
+	}
+	return 0
+}
+
+// dbTimeLayouts covers the formats SQLite drivers commonly store DATETIME
+// values in, including Go's time.Time.String output.
+var dbTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999 -0700 MST",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+}
+
+func dbTime(v any) time.Time {
+	switch x := v.(type) {
+	case time.Time:
+		return x
+	case int64:
+		return time.Unix(x, 0).UTC()
+	case string, []byte:
+		s := strings.TrimSpace(dbString(x))
+		if s == "" {
+			return time.Time{}
+		}
+		// time.Time.String may append a monotonic clock reading.
+		if i := strings.Index(s, " m="); i > 0 {
+			s = s[:i]
+		}
+		for _, layout := range dbTimeLayouts {
+			if t, err := time.Parse(layout, s); err == nil {
+				return t
+			}
+		}
+	}
+	return time.Time{}
+}
+
+func dbTimePtr(v any) *time.Time {
+	t := dbTime(v)
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
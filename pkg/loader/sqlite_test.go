@@ -0,0 +1,276 @@
+package loader_test
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+
+	_ "modernc.org/sqlite"
+)
+
+// beadsSchema is the subset of the bd schema the loader reads.
+const beadsSchema = `
+CREATE TABLE issues (
+	id TEXT PRIMARY KEY,
+	content_hash TEXT,
+	title TEXT NOT NULL,
+	description TEXT NOT NULL DEFAULT '',
+	status TEXT NOT NULL DEFAULT 'open',
+	priority INTEGER NOT NULL DEFAULT 2,
+	issue_type TEXT NOT NULL DEFAULT 'task',
+	assignee TEXT,
+	estimated_minutes INTEGER,
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	closed_at DATETIME,
+	external_ref TEXT
+);
+CREATE TABLE dependencies (
+	issue_id TEXT NOT NULL,
+	depends_on_id TEXT NOT NULL,
+	type TEXT NOT NULL DEFAULT 'blocks',
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	created_by TEXT NOT NULL DEFAULT '',
+	PRIMARY KEY (issue_id, depends_on_id)
+);
+CREATE TABLE labels (
+	issue_id TEXT NOT NULL,
+	label TEXT NOT NULL,
+	PRIMARY KEY (issue_id, label)
+);
+CREATE TABLE comments (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	issue_id TEXT NOT NULL,
+	author TEXT NOT NULL,
+	text TEXT NOT NULL,
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);`
+
+func openDB(t *testing.T, path string) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("open %s: %v", path, err)
+	}
+	return db
+}
+
+func createBeadsDB(t *testing.T, path string, stmts ...string) {
+	t.Helper()
+	db := openDB(t, path)
+	defer db.Close()
+	for _, stmt := range append([]string{beadsSchema}, stmts...) {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("exec %q: %v", stmt, err)
+		}
+	}
+}
+
+func TestLoadIssuesFromDB(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "beads.db")
+	createBeadsDB(t, path,
+		`INSERT INTO issues (id, title, status, priority, issue_type, assignee, estimated_minutes, created_at, updated_at)
+		 VALUES ('bd-1', 'First', 'open', 1, 'feature', 'alice', 90, '2025-01-02 03:04:05', '2025-01-03T03:04:05Z')`,
+		`INSERT INTO issues (id, title, status, priority, issue_type, created_at, updated_at, closed_at)
+		 VALUES ('bd-2', 'Second', 'closed', 2, 'bug', '2025-01-01 00:00:00', '2025-01-01 00:00:00', '2025-01-04 00:00:00')`,
+		`INSERT INTO dependencies (issue_id, depends_on_id, type, created_by) VALUES ('bd-1', 'bd-2', 'blocks', 'bob')`,
+		`INSERT INTO labels (issue_id, label) VALUES ('bd-1', 'ui'), ('bd-1', 'api')`,
+		`INSERT INTO comments (issue_id, author, text) VALUES ('bd-1', 'carol', 'looks good')`,
+	)
+
+	issues, err := loader.LoadIssuesFromDB(path)
+	if err != nil {
+		t.Fatalf("LoadIssuesFromDB: %v", err)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("expected 2 issues, got %d", len(issues))
+	}
+
+	first := issues[0]
+	if first.ID != "bd-1" || first.Title != "First" || first.Priority != 1 || first.IssueType != model.TypeFeature {
+		t.Errorf("unexpected issue: %+v", first)
+	}
+	if first.Assignee != "alice" || first.EstimatedMinutes == nil || *first.EstimatedMinutes != 90 {
+		t.Errorf("assignee/estimate not read: %+v", first)
+	}
+	if want := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC); !first.CreatedAt.Equal(want) {
+		t.Errorf("CreatedAt = %v, want %v", first.CreatedAt, want)
+	}
+	if len(first.Dependencies) != 1 || first.Dependencies[0].DependsOnID != "bd-2" || first.Dependencies[0].CreatedBy != "bob" {
+		t.Errorf("dependencies not read: %+v", first.Dependencies)
+	}
+	if strings.Join(first.Labels, ",") != "api,ui" {
+		t.Errorf("labels = %v", first.Labels)
+	}
+	if len(first.Comments) != 1 || first.Comments[0].Author != "carol" || first.Comments[0].Text != "looks good" {
+		t.Errorf("comments not read: %+v", first.Comments)
+	}
+
+	second := issues[1]
+	if second.Status != model.StatusClosed || second.ClosedAt == nil {
+		t.Errorf("closed issue not read: %+v", second)
+	}
+}
+
+func TestLoadIssuesFromDB_SkipsInvalidRows(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "beads.db")
+	createBeadsDB(t, path,
+		`INSERT INTO issues (id, title, status) VALUES ('bd-1', 'Good', 'open')`,
+		`INSERT INTO issues (id, title, status) VALUES ('bd-2', '', 'open')`,
+	)
+
+	var warnings []string
+	issues, err := loader.LoadIssuesFromDBWithOptions(path, loader.ParseOptions{
+		WarningHandler: func(msg string) { warnings = append(warnings, msg) },
+	})
+	if err != nil {
+		t.Fatalf("LoadIssuesFromDBWithOptions: %v", err)
+	}
+	if len(issues) != 1 || issues[0].ID != "bd-1" {
+		t.Fatalf("expected only bd-1, got %+v", issues)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "bd-2") {
+		t.Errorf("expected a warning about bd-2, got %v", warnings)
+	}
+}
+
+func TestLoadIssuesFromFile_DispatchesOnSQLite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "beads.db")
+	createBeadsDB(t, path, `INSERT INTO issues (id, title) VALUES ('bd-1', 'From DB')`)
+
+	if !loader.IsSQLiteFile(path) {
+		t.Fatal("IsSQLiteFile = false for a SQLite database")
+	}
+	issues, err := loader.LoadIssuesFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadIssuesFromFile: %v", err)
+	}
+	if len(issues) != 1 || issues[0].Title != "From DB" {
+		t.Fatalf("unexpected issues: %+v", issues)
+	}
+}
+
+func TestFindSources_OrdersByModTime(t *testing.T) {
+	dir := t.TempDir()
+	jsonl := filepath.Join(dir, "issues.jsonl")
+	db := filepath.Join(dir, "beads.db")
+	if err := os.WriteFile(jsonl, []byte(`{"id":"bd-1","title":"JSONL","status":"open","issue_type":"task"}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	createBeadsDB(t, db, `INSERT INTO issues (id, title) VALUES ('bd-1', 'DB')`)
+
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(jsonl, old, old); err != nil {
+		t.Fatal(err)
+	}
+	sources, err := loader.FindSources(dir, nil)
+	if err != nil {
+		t.Fatalf("FindSources: %v", err)
+	}
+	if len(sources) != 2 || sources[0].Kind != loader.SourceSQLite || sources[1].Kind != loader.SourceJSONL {
+		t.Fatalf("expected [sqlite jsonl], got %+v", sources)
+	}
+
+	if err := os.Chtimes(db, old.Add(-time.Hour), old.Add(-time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	path, err := loader.FindIssuesPath(dir)
+	if err != nil {
+		t.Fatalf("FindIssuesPath: %v", err)
+	}
+	if path != jsonl {
+		t.Errorf("FindIssuesPath = %s, want %s", path, jsonl)
+	}
+}
+
+func TestFindSources_EmptyJSONLLosesToDB(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "issues.jsonl"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	db := filepath.Join(dir, "beads.db")
+	createBeadsDB(t, db, `INSERT INTO issues (id, title) VALUES ('bd-1', 'DB')`)
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(db, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := loader.FindIssuesPath(dir)
+	if err != nil {
+		t.Fatalf("FindIssuesPath: %v", err)
+	}
+	if path != db {
+		t.Errorf("FindIssuesPath = %s, want %s", path, db)
+	}
+}
+
+func TestLoadIssuesWithOptions_WarnsOnDivergence(t *testing.T) {
+	repo := t.TempDir()
+	dir := filepath.Join(repo, ".beads")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	jsonl := filepath.Join(dir, "issues.jsonl")
+	if err := os.WriteFile(jsonl, []byte(`{"id":"bd-1","title":"Same","status":"open","issue_type":"task"}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	createBeadsDB(t, filepath.Join(dir, "beads.db"),
+		`INSERT INTO issues (id, title, status) VALUES ('bd-1', 'Same', 'closed')`,
+		`INSERT INTO issues (id, title) VALUES ('bd-2', 'Only in DB')`,
+	)
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(jsonl, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	var warnings []string
+	issues, err := loader.LoadIssuesWithOptions(repo, loader.ParseOptions{
+		WarningHandler: func(msg string) { warnings = append(warnings, msg) },
+	})
+	if err != nil {
+		t.Fatalf("LoadIssuesWithOptions: %v", err)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("expected the 2 issues from beads.db, got %d", len(issues))
+	}
+	if len(warnings) != 1 {
+		t.Fatalf("expected one divergence warning, got %v", warnings)
+	}
+	for _, want := range []string{"diverged", "1 only in beads.db", "1 changed", "using beads.db"} {
+		if !strings.Contains(warnings[0], want) {
+			t.Errorf("warning %q missing %q", warnings[0], want)
+		}
+	}
+}
+
+func TestDiffSources(t *testing.T) {
+	primary := []model.Issue{
+		{ID: "a", Title: "A", Status: model.StatusOpen},
+		{ID: "b", Title: "B", Status: model.StatusOpen},
+	}
+	secondary := []model.Issue{
+		{ID: "a", Title: "A", Status: model.StatusOpen},
+		{ID: "b", Title: "B", Status: model.StatusClosed},
+		{ID: "c", Title: "C", Status: model.StatusOpen},
+	}
+
+	d := loader.DiffSources(primary, secondary)
+	if d.Empty() {
+		t.Fatal("expected divergence")
+	}
+	if len(d.OnlyInPrimary) != 0 || strings.Join(d.OnlyInSecondary, ",") != "c" || strings.Join(d.Changed, ",") != "b" {
+		t.Errorf("unexpected divergence: %+v", d)
+	}
+	if got := d.Summary("x", "y"); got != "1 only in y, 1 changed" {
+		t.Errorf("Summary = %q", got)
+	}
+	if !loader.DiffSources(primary, primary).Empty() {
+		t.Error("identical sources should not diverge")
+	}
+}
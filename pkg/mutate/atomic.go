@@ -0,0 +1,84 @@
+package mutate
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// WriteIssuesAtomic writes all issues to the JSONL file atomically: the data
+// goes to a temp file in the same directory, is fsynced, and is renamed over
+// the original. The original file mode is preserved.
+func WriteIssuesAtomic(filePath string, issues []model.Issue) error {
+	// Get file info to preserve permissions
+	var mode os.FileMode = 0644
+	if info, err := os.Stat(filePath); err == nil {
+		mode = info.Mode()
+	}
+
+	// Create temp file in same directory (required for atomic rename)
+	dir := filepath.Dir(filePath)
+	tmp, err := os.CreateTemp(dir, ".bv-mutate-atomic-*")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+
+	// Cleanup temp file on error
+	success := false
+	defer func() {
+		if !success {
+			os.Remove(tmpPath)
+		}
+	}()
+
+	// Write each issue as a JSON line
+	writer := bufio.NewWriter(tmp)
+	for _, issue := range issues {
+		line, err := json.Marshal(issue)
+		if err != nil {
+			tmp.Close()
+			return fmt.Errorf("marshal issue %s: %w", issue.ID, err)
+		}
+		if _, err := writer.Write(line); err != nil {
+			tmp.Close()
+			return fmt.Errorf("write issue %s: %w", issue.ID, err)
+		}
+		if _, err := writer.WriteString("\n"); err != nil {
+			tmp.Close()
+			return fmt.Errorf("write newline: %w", err)
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("flush buffer: %w", err)
+	}
+
+	// Ensure data is flushed to disk
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("sync temp file: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close temp file: %w", err)
+	}
+
+	// Set permissions on temp file
+	if err := os.Chmod(tmpPath, mode); err != nil {
+		return fmt.Errorf("chmod temp file: %w", err)
+	}
+
+	// Atomic rename
+	if err := os.Rename(tmpPath, filePath); err != nil {
+		return fmt.Errorf("rename temp file: %w", err)
+	}
+
+	success = true
+	return nil
+}
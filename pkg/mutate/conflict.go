@@ -0,0 +1,77 @@
+package mutate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// ErrConflict is matched (via errors.Is) by the error returned when a write
+// is refused because the data changed underneath it.
+var ErrConflict = errors.New("write conflict")
+
+// Version identifies the state of an issue for compare-and-swap writes.
+type Version struct {
+	UpdatedAt   time.Time `json:"updated_at"`
+	ContentHash string    `json:"content_hash"` // hex SHA-256 of the issue's JSON encoding
+}
+
+// VersionOf returns the current version of issue.
+func VersionOf(issue model.Issue) Version {
+	data, _ := json.Marshal(issue)
+	sum := sha256.Sum256(data)
+	return Version{UpdatedAt: issue.UpdatedAt, ContentHash: hex.EncodeToString(sum[:])}
+}
+
+// matches reports whether actual satisfies the expectation v. Zero fields of
+// v are not checked.
+func (v Version) matches(actual Version) bool {
+	if !v.UpdatedAt.IsZero() && !v.UpdatedAt.Equal(actual.UpdatedAt) {
+		return false
+	}
+	return v.ContentHash == "" || v.ContentHash == actual.ContentHash
+}
+
+// CheckVersion returns a *ConflictError if issue no longer matches the
+// expected version, for writers that check expectations themselves (such as
+// batch writers using ModifyAll).
+func CheckVersion(issue model.Issue, expect Version) error {
+	if actual := VersionOf(issue); !expect.matches(actual) {
+		return &ConflictError{IssueID: issue.ID, Expected: expect, Actual: actual}
+	}
+	return nil
+}
+
+// ConflictError reports a refused write. Either the target issue no longer
+// matches the version the caller expected, or the beads file was rewritten by
+// a writer that does not take the lock between our read and our write.
+type ConflictError struct {
+	IssueID string
+	// FileChanged is set when the file changed during the write; Store
+	// retries these when configured with WithRetries.
+	FileChanged bool
+	Expected    Version
+	Actual      Version
+}
+
+func (e *ConflictError) Error() string {
+	if e.FileChanged {
+		return "beads file changed while writing; reload and retry"
+	}
+	return fmt.Sprintf("issue %s changed since it was read (expected updated_at %s, found %s)",
+		e.IssueID, formatVersionTime(e.Expected.UpdatedAt), formatVersionTime(e.Actual.UpdatedAt))
+}
+
+func (e *ConflictError) Is(target error) bool { return target == ErrConflict }
+
+func formatVersionTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format(time.RFC3339Nano)
+}
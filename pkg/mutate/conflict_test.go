@@ -0,0 +1,113 @@
+package mutate
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func TestModifyExpectVersion(t *testing.T) {
+	s, path := newTestStore(t, testIssue("bd-1"))
+	seen := VersionOf(loadAll(t, path)["bd-1"])
+
+	// Someone else edits bd-1 after we read it.
+	if _, err := s.Apply("bd-1", "bob", SetPriority{Priority: 0}); err != nil {
+		t.Fatal(err)
+	}
+
+	stale := NewFileStore(path, WithExpect(seen), WithRetries(3))
+	_, err := stale.Apply("bd-1", "alice", SetAssignee{Assignee: "alice"})
+	var conflict *ConflictError
+	if !errors.Is(err, ErrConflict) || !errors.As(err, &conflict) || conflict.FileChanged {
+		t.Fatalf("stale write error = %v, want issue conflict", err)
+	}
+	if got := loadAll(t, path)["bd-1"]; got.Assignee != "" || got.Priority != 0 {
+		t.Errorf("stale write was applied: %+v", got)
+	}
+
+	fresh := VersionOf(loadAll(t, path)["bd-1"])
+	if fresh.ContentHash == seen.ContentHash {
+		t.Fatal("content hash did not change with the edit")
+	}
+	// Matching on the hash alone is enough.
+	updated, err := NewFileStore(path, WithExpect(Version{ContentHash: fresh.ContentHash})).
+		Apply("bd-1", "alice", SetAssignee{Assignee: "alice"})
+	if err != nil {
+		t.Fatalf("write with current version: %v", err)
+	}
+	if updated.Assignee != "alice" {
+		t.Errorf("assignee = %q", updated.Assignee)
+	}
+}
+
+func TestModifyRetriesAfterUnlockedWrite(t *testing.T) {
+	run := func(retries int) (map[string]model.Issue, int, error) {
+		s, path := newTestStore(t, testIssue("bd-1"), testIssue("bd-2"))
+		s.retries = retries
+		calls := 0
+		_, err := s.Modify("bd-1", func(issue *model.Issue, _ []model.Issue) error {
+			calls++
+			if calls == 1 {
+				// A writer that ignores the lock rewrites the file mid-cycle.
+				other := testIssue("bd-2")
+				other.Title = "Edited by bd"
+				if err := WriteIssuesAtomic(path, []model.Issue{testIssue("bd-1"), other}); err != nil {
+					return err
+				}
+			}
+			issue.Assignee = "alice"
+			return nil
+		})
+		return loadAll(t, path), calls, err
+	}
+
+	issues, _, err := run(0)
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) || !conflict.FileChanged {
+		t.Fatalf("without retries error = %v, want file conflict", err)
+	}
+	if issues["bd-1"].Assignee != "" || issues["bd-2"].Title != "Edited by bd" {
+		t.Errorf("conflicting write clobbered the other writer: %+v", issues)
+	}
+
+	issues, calls, err := run(2)
+	if err != nil {
+		t.Fatalf("with retries: %v", err)
+	}
+	if calls != 2 || issues["bd-1"].Assignee != "alice" || issues["bd-2"].Title != "Edited by bd" {
+		t.Errorf("after retry: calls=%d issues=%+v", calls, issues)
+	}
+}
+
+func TestConcurrentWritersKeepEveryChange(t *testing.T) {
+	ids := []string{"bd-1", "bd-2", "bd-3", "bd-4", "bd-5", "bd-6"}
+	var seed []model.Issue
+	for _, id := range ids {
+		seed = append(seed, testIssue(id))
+	}
+	_, path := newTestStore(t, seed...)
+
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			store := NewFileStore(path, WithLockTimeout(10*time.Second))
+			if _, err := store.Apply(id, fmt.Sprintf("agent-%d", i), SetAssignee{Assignee: fmt.Sprintf("agent-%d", i)}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	got := loadAll(t, path)
+	for i, id := range ids {
+		if want := fmt.Sprintf("agent-%d", i); got[id].Assignee != want {
+			t.Errorf("%s assignee = %q, want %q (lost update)", id, got[id].Assignee, want)
+		}
+	}
+}
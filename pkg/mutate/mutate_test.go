@@ -0,0 +1,329 @@
+package mutate
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+var testNow = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestStore(t *testing.T, issues ...model.Issue) (*Store, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "issues.jsonl")
+	if err := WriteIssuesAtomic(path, issues); err != nil {
+		t.Fatalf("seed issues: %v", err)
+	}
+	s := NewFileStore(path)
+	s.now = func() time.Time { return testNow }
+	return s, path
+}
+
+func testIssue(id string) model.Issue {
+	created := testNow.Add(-24 * time.Hour)
+	return model.Issue{
+		ID:        id,
+		Title:     "Issue " + id,
+		Status:    model.StatusOpen,
+		IssueType: model.TypeTask,
+		Priority:  2,
+		CreatedAt: created,
+		UpdatedAt: created,
+	}
+}
+
+func loadAll(t *testing.T, path string) map[string]model.Issue {
+	t.Helper()
+	issues, err := loader.LoadIssuesFromFile(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	out := make(map[string]model.Issue, len(issues))
+	for _, iss := range issues {
+		out[iss.ID] = iss
+	}
+	return out
+}
+
+func TestApplyWritesChanges(t *testing.T) {
+	s, path := newTestStore(t, testIssue("bd-1"), testIssue("bd-2"))
+
+	updated, err := s.Apply("bd-1", "alice",
+		SetPriority{Priority: 0},
+		SetAssignee{Assignee: "bob"},
+		AddLabels{Labels: []string{"api", " api ", "", "db"}},
+		AddDependency{DependsOnID: "bd-2"},
+		AddComment{Text: "looking into it"},
+	)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if !updated.UpdatedAt.Equal(testNow) {
+		t.Errorf("UpdatedAt = %v, want %v", updated.UpdatedAt, testNow)
+	}
+
+	got := loadAll(t, path)["bd-1"]
+	if got.Priority != 0 || got.Assignee != "bob" {
+		t.Errorf("priority/assignee = %d/%q", got.Priority, got.Assignee)
+	}
+	if strings.Join(got.Labels, ",") != "api,db" {
+		t.Errorf("labels = %v, want [api db]", got.Labels)
+	}
+	if len(got.Dependencies) != 1 || got.Dependencies[0].DependsOnID != "bd-2" ||
+		got.Dependencies[0].Type != model.DepBlocks || got.Dependencies[0].CreatedBy != "alice" {
+		t.Errorf("dependencies = %+v", got.Dependencies)
+	}
+	if len(got.Comments) != 1 || got.Comments[0].Author != "alice" || got.Comments[0].ID != 1 {
+		t.Errorf("comments = %+v", got.Comments)
+	}
+}
+
+func TestApplyIsAllOrNothing(t *testing.T) {
+	s, path := newTestStore(t, testIssue("bd-1"))
+	before, _ := os.ReadFile(path)
+
+	_, err := s.Apply("bd-1", "alice",
+		SetPriority{Priority: 1},
+		AddDependency{DependsOnID: "bd-missing"},
+	)
+	if !errors.Is(err, ErrIssueNotFound) {
+		t.Fatalf("expected ErrIssueNotFound, got %v", err)
+	}
+
+	after, _ := os.ReadFile(path)
+	if string(before) != string(after) {
+		t.Error("file changed despite failed apply")
+	}
+}
+
+func TestApplyUnknownIssue(t *testing.T) {
+	s, _ := newTestStore(t, testIssue("bd-1"))
+	_, err := s.Apply("bd-9", "alice", SetPriority{Priority: 1})
+	if !errors.Is(err, ErrIssueNotFound) {
+		t.Fatalf("expected ErrIssueNotFound, got %v", err)
+	}
+	if err.Error() != "issue bd-9 not found" {
+		t.Errorf("error = %q", err.Error())
+	}
+}
+
+func TestStatusTransitions(t *testing.T) {
+	env := &Env{Now: testNow, Actor: "alice"}
+	issue := testIssue("bd-1")
+
+	if err := ApplyOps(&issue, env, Close{Reason: "done"}); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if issue.Status != model.StatusClosed || issue.ClosedAt == nil || issue.CloseReason != "done" {
+		t.Fatalf("after close: status=%s closedAt=%v reason=%q", issue.Status, issue.ClosedAt, issue.CloseReason)
+	}
+
+	if err := ApplyOps(&issue, env, SetStatus{Status: model.StatusOpen}); err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if issue.ClosedAt != nil || issue.CloseReason != "" {
+		t.Errorf("reopen should clear close metadata: closedAt=%v reason=%q", issue.ClosedAt, issue.CloseReason)
+	}
+}
+
+func TestOpValidation(t *testing.T) {
+	env := &Env{Now: testNow, Issues: []model.Issue{testIssue("bd-1"), testIssue("bd-2")}}
+
+	withDep := testIssue("bd-1")
+	withDep.Dependencies = []*model.Dependency{{IssueID: "bd-1", DependsOnID: "bd-2", Type: model.DepBlocks}}
+
+	tests := []struct {
+		name  string
+		issue model.Issue
+		op    Op
+	}{
+		{"bad status", testIssue("bd-1"), SetStatus{Status: "done"}},
+		{"priority too high", testIssue("bd-1"), SetPriority{Priority: 5}},
+		{"priority negative", testIssue("bd-1"), SetPriority{Priority: -1}},
+		{"self dependency", testIssue("bd-1"), AddDependency{DependsOnID: "bd-1"}},
+		{"bad dependency type", testIssue("bd-1"), AddDependency{DependsOnID: "bd-2", Type: "frobs"}},
+		{"duplicate dependency", withDep, AddDependency{DependsOnID: "bd-2"}},
+		{"remove missing dependency", testIssue("bd-1"), RemoveDependency{DependsOnID: "bd-2"}},
+		{"empty comment", testIssue("bd-1"), AddComment{Text: "  "}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issue := tt.issue
+			before := issue.Clone()
+			if err := ApplyOps(&issue, env, tt.op); err == nil {
+				t.Fatal("expected error")
+			}
+			if issue.Priority != before.Priority || issue.Status != before.Status ||
+				len(issue.Dependencies) != len(before.Dependencies) || len(issue.Comments) != len(before.Comments) {
+				t.Error("issue modified despite error")
+			}
+		})
+	}
+}
+
+func TestRemoveLabelsAndDependency(t *testing.T) {
+	env := &Env{Now: testNow}
+	issue := testIssue("bd-1")
+	issue.Labels = []string{"a", "b", "c"}
+	issue.Dependencies = []*model.Dependency{
+		{IssueID: "bd-1", DependsOnID: "bd-2", Type: model.DepBlocks},
+		{IssueID: "bd-1", DependsOnID: "bd-3", Type: model.DepRelated},
+	}
+
+	if err := ApplyOps(&issue, env, RemoveLabels{Labels: []string{"b", "zzz"}}, RemoveDependency{DependsOnID: "bd-2"}); err != nil {
+		t.Fatalf("ApplyOps: %v", err)
+	}
+	if strings.Join(issue.Labels, ",") != "a,c" {
+		t.Errorf("labels = %v", issue.Labels)
+	}
+	if len(issue.Dependencies) != 1 || issue.Dependencies[0].DependsOnID != "bd-3" {
+		t.Errorf("dependencies = %+v", issue.Dependencies)
+	}
+}
+
+func TestCreateMintsID(t *testing.T) {
+	s, path := newTestStore(t, testIssue("proj-1"), testIssue("proj-2"), testIssue("other-1"))
+
+	created, err := s.Create(model.Issue{Title: "New thing", Priority: 1})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if !strings.HasPrefix(created.ID, "proj-") || len(created.ID) != len("proj-")+4 {
+		t.Errorf("minted ID = %q, want proj-xxxx", created.ID)
+	}
+	if created.Status != model.StatusOpen || created.IssueType != model.TypeTask {
+		t.Errorf("defaults not applied: %+v", created)
+	}
+	if !created.CreatedAt.Equal(testNow) {
+		t.Errorf("CreatedAt = %v", created.CreatedAt)
+	}
+
+	all := loadAll(t, path)
+	if len(all) != 4 {
+		t.Fatalf("expected 4 issues on disk, got %d", len(all))
+	}
+	if _, ok := all[created.ID]; !ok {
+		t.Error("created issue not written")
+	}
+
+	if _, err := s.Create(model.Issue{ID: "proj-1", Title: "dup"}); err == nil {
+		t.Error("expected duplicate ID error")
+	}
+	if _, err := s.Create(model.Issue{}); err == nil {
+		t.Error("expected validation error for missing title")
+	}
+}
+
+func TestWriteIssuesAtomicPreservesMode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "issues.jsonl")
+	if err := os.WriteFile(path, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteIssuesAtomic(path, []model.Issue{testIssue("bd-1")}); err != nil {
+		t.Fatalf("WriteIssuesAtomic: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("temp files left behind: %d entries", len(entries))
+	}
+}
+
+func TestNextCommentID(t *testing.T) {
+	issue := testIssue("bd-1")
+	if got := NextCommentID(&issue); got != 1 {
+		t.Errorf("empty: got %d, want 1", got)
+	}
+	issue.Comments = []*model.Comment{{ID: 7}, {ID: 0}}
+	if got := NextCommentID(&issue); got != 8 {
+		t.Errorf("got %d, want 8", got)
+	}
+}
+
+func TestAddDependencyRejectsCycle(t *testing.T) {
+	a, b, c := testIssue("bd-a"), testIssue("bd-b"), testIssue("bd-c")
+	b.Dependencies = []*model.Dependency{{IssueID: "bd-b", DependsOnID: "bd-c", Type: model.DepBlocks}}
+	c.Dependencies = []*model.Dependency{{IssueID: "bd-c", DependsOnID: "bd-a", Type: model.DepBlocks}}
+	s, path := newTestStore(t, a, b, c)
+	before, _ := os.ReadFile(path)
+
+	_, impact, err := s.AddDependency("bd-a", "alice", AddDependency{DependsOnID: "bd-b"})
+	if !errors.Is(err, ErrDependencyCycle) {
+		t.Fatalf("expected ErrDependencyCycle, got %v", err)
+	}
+	var cycleErr *CycleError
+	if !errors.As(err, &cycleErr) || strings.Join(cycleErr.Path, ",") != "bd-a,bd-b,bd-c,bd-a" {
+		t.Errorf("cycle path = %v", cycleErr)
+	}
+	if impact == nil || !impact.WouldCycle {
+		t.Errorf("impact should report the cycle: %+v", impact)
+	}
+	after, _ := os.ReadFile(path)
+	if string(before) != string(after) {
+		t.Error("file changed despite rejected cycle")
+	}
+
+	// A non-blocking link along the same edge is fine.
+	if _, _, err := s.AddDependency("bd-a", "alice", AddDependency{DependsOnID: "bd-b", Type: model.DepRelated}); err != nil {
+		t.Fatalf("related dependency rejected: %v", err)
+	}
+
+	// AllowCycle overrides the guard.
+	if _, _, err := s.AddDependency("bd-a", "alice", AddDependency{DependsOnID: "bd-c", AllowCycle: true}); err != nil {
+		t.Fatalf("AllowCycle: %v", err)
+	}
+	if deps := loadAll(t, path)["bd-a"].Dependencies; len(deps) != 2 {
+		t.Errorf("bd-a dependencies = %d, want 2", len(deps))
+	}
+}
+
+func TestAddDependencyReportsImpact(t *testing.T) {
+	s, _ := newTestStore(t, testIssue("bd-a"), testIssue("bd-b"))
+
+	_, impact, err := s.AddDependency("bd-a", "alice", AddDependency{DependsOnID: "bd-b"})
+	if err != nil {
+		t.Fatalf("AddDependency: %v", err)
+	}
+	if impact.CriticalPathDepthDelta != 1 || impact.ActionableDelta != -1 {
+		t.Errorf("impact = %+v", impact)
+	}
+}
+
+func TestAddDependencyRejectsSelfAndMissingTarget(t *testing.T) {
+	s, path := newTestStore(t, testIssue("bd-a"))
+	before, _ := os.ReadFile(path)
+
+	_, impact, err := s.AddDependency("bd-a", "alice", AddDependency{DependsOnID: "bd-a"})
+	if err == nil || !strings.Contains(err.Error(), "cannot depend on itself") {
+		t.Fatalf("expected self-dependency rejection, got %v", err)
+	}
+	if impact != nil {
+		t.Errorf("impact should not be computed for a rejected target: %+v", impact)
+	}
+
+	_, impact, err = s.AddDependency("bd-a", "alice", AddDependency{DependsOnID: "bd-missing"})
+	if !errors.Is(err, ErrIssueNotFound) {
+		t.Fatalf("expected ErrIssueNotFound, got %v", err)
+	}
+	if impact != nil {
+		t.Errorf("impact should not be computed for a missing target: %+v", impact)
+	}
+
+	after, _ := os.ReadFile(path)
+	if string(before) != string(after) {
+		t.Error("file changed despite rejected dependency")
+	}
+}
@@ -0,0 +1,337 @@
+package mutate
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// Env is the context an Op is applied in.
+type Env struct {
+	Now   time.Time
+	Actor string
+	// Issues is every loaded issue (including the target, pre-mutation).
+	// Ops use it for cross-issue checks such as dependency targets.
+	Issues []model.Issue
+}
+
+func (e *Env) lookup(id string) *model.Issue {
+	for i := range e.Issues {
+		if e.Issues[i].ID == id {
+			return &e.Issues[i]
+		}
+	}
+	return nil
+}
+
+// withWorking returns Issues with the pre-mutation copy of issue replaced by
+// the in-progress one, so checks see edits made by earlier ops in the batch.
+func (e *Env) withWorking(issue *model.Issue) []model.Issue {
+	out := make([]model.Issue, len(e.Issues))
+	copy(out, e.Issues)
+	for i := range out {
+		if out[i].ID == issue.ID {
+			out[i] = *issue
+			return out
+		}
+	}
+	return append(out, *issue)
+}
+
+// Op is a typed mutation of a single issue.
+type Op interface {
+	// Apply mutates issue in place or returns an error explaining why the
+	// change is not allowed.
+	Apply(issue *model.Issue, env *Env) error
+	// Describe returns a short human-readable summary, e.g. "set status to closed".
+	Describe() string
+}
+
+// SetStatus changes the status. Moving to closed stamps ClosedAt; moving out
+// of closed clears ClosedAt and CloseReason.
+type SetStatus struct {
+	Status model.Status
+}
+
+func (o SetStatus) Apply(issue *model.Issue, env *Env) error {
+	if !o.Status.IsValid() {
+		return fmt.Errorf("invalid status: %s", o.Status)
+	}
+	wasClosed := issue.Status.IsClosed()
+	issue.Status = o.Status
+	switch {
+	case o.Status.IsClosed() && !wasClosed:
+		now := env.Now
+		issue.ClosedAt = &now
+	case !o.Status.IsClosed() && wasClosed:
+		issue.ClosedAt = nil
+		issue.CloseReason = ""
+	}
+	return nil
+}
+
+func (o SetStatus) Describe() string { return fmt.Sprintf("set status to %s", o.Status) }
+
+// Close closes the issue with a reason.
+type Close struct {
+	Reason string
+}
+
+func (o Close) Apply(issue *model.Issue, env *Env) error {
+	if err := (SetStatus{Status: model.StatusClosed}).Apply(issue, env); err != nil {
+		return err
+	}
+	issue.CloseReason = strings.TrimSpace(o.Reason)
+	return nil
+}
+
+func (o Close) Describe() string {
+	if o.Reason == "" {
+		return "close"
+	}
+	return fmt.Sprintf("close (%s)", o.Reason)
+}
+
+// MinPriority and MaxPriority bound valid priorities (P0 is most urgent).
+const (
+	MinPriority = 0
+	MaxPriority = 4
+)
+
+// SetPriority changes the priority.
+type SetPriority struct {
+	Priority int
+}
+
+func (o SetPriority) Apply(issue *model.Issue, _ *Env) error {
+	if o.Priority < MinPriority || o.Priority > MaxPriority {
+		return fmt.Errorf("priority must be between %d and %d, got %d", MinPriority, MaxPriority, o.Priority)
+	}
+	issue.Priority = o.Priority
+	return nil
+}
+
+func (o SetPriority) Describe() string { return fmt.Sprintf("set priority to P%d", o.Priority) }
+
+// SetAssignee changes the assignee. An empty assignee unassigns.
+type SetAssignee struct {
+	Assignee string
+}
+
+func (o SetAssignee) Apply(issue *model.Issue, _ *Env) error {
+	issue.Assignee = strings.TrimSpace(o.Assignee)
+	return nil
+}
+
+func (o SetAssignee) Describe() string {
+	if o.Assignee == "" {
+		return "unassign"
+	}
+	return fmt.Sprintf("assign to %s", o.Assignee)
+}
+
+// SetLabels replaces the label set. Blank and duplicate labels are dropped.
+type SetLabels struct {
+	Labels []string
+}
+
+func (o SetLabels) Apply(issue *model.Issue, _ *Env) error {
+	issue.Labels = normalizeLabels(o.Labels)
+	return nil
+}
+
+func (o SetLabels) Describe() string {
+	return fmt.Sprintf("set labels to [%s]", strings.Join(o.Labels, ", "))
+}
+
+// AddLabels adds labels not already present.
+type AddLabels struct {
+	Labels []string
+}
+
+func (o AddLabels) Apply(issue *model.Issue, _ *Env) error {
+	issue.Labels = normalizeLabels(append(append([]string{}, issue.Labels...), o.Labels...))
+	return nil
+}
+
+func (o AddLabels) Describe() string {
+	return fmt.Sprintf("add labels [%s]", strings.Join(o.Labels, ", "))
+}
+
+// RemoveLabels removes labels; labels that are not present are ignored.
+type RemoveLabels struct {
+	Labels []string
+}
+
+func (o RemoveLabels) Apply(issue *model.Issue, _ *Env) error {
+	drop := make(map[string]bool, len(o.Labels))
+	for _, l := range o.Labels {
+		drop[strings.TrimSpace(l)] = true
+	}
+	kept := issue.Labels[:0:0]
+	for _, l := range issue.Labels {
+		if !drop[l] {
+			kept = append(kept, l)
+		}
+	}
+	issue.Labels = kept
+	return nil
+}
+
+func (o RemoveLabels) Describe() string {
+	return fmt.Sprintf("remove labels [%s]", strings.Join(o.Labels, ", "))
+}
+
+func normalizeLabels(labels []string) []string {
+	seen := make(map[string]bool, len(labels))
+	out := make([]string, 0, len(labels))
+	for _, l := range labels {
+		l = strings.TrimSpace(l)
+		if l == "" || seen[l] {
+			continue
+		}
+		seen[l] = true
+		out = append(out, l)
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// ErrDependencyCycle is matched (via errors.Is) by the *CycleError returned
+// when a dependency would close a blocking cycle.
+var ErrDependencyCycle = errors.New("dependency would create a cycle")
+
+// CycleError reports the cycle a rejected dependency would have created.
+// Path starts and ends at the issue gaining the dependency.
+type CycleError struct {
+	Path []string
+}
+
+func (e *CycleError) Error() string {
+	return "would create cycle: " + strings.Join(e.Path, " → ")
+}
+
+func (e *CycleError) Is(target error) bool { return target == ErrDependencyCycle }
+
+// AddDependency records that the issue depends on DependsOnID. Type defaults
+// to blocks. A blocking dependency that would close a cycle is rejected with
+// a *CycleError unless AllowCycle is set.
+type AddDependency struct {
+	DependsOnID string
+	Type        model.DependencyType
+	AllowCycle  bool
+}
+
+func (o AddDependency) depType() model.DependencyType {
+	if o.Type == "" {
+		return model.DepBlocks
+	}
+	return o.Type
+}
+
+func (o AddDependency) Apply(issue *model.Issue, env *Env) error {
+	if err := o.checkTarget(issue.ID, env); err != nil {
+		return err
+	}
+	for _, dep := range issue.Dependencies {
+		if dep != nil && dep.DependsOnID == o.DependsOnID {
+			return fmt.Errorf("%s already depends on %s", issue.ID, o.DependsOnID)
+		}
+	}
+	if !o.AllowCycle {
+		if cycle, path := analysis.WouldCreateBlockingCycle(env.withWorking(issue), issue.ID, o.DependsOnID, o.depType()); cycle {
+			return &CycleError{Path: path}
+		}
+	}
+	issue.Dependencies = append(issue.Dependencies, &model.Dependency{
+		IssueID:     issue.ID,
+		DependsOnID: o.DependsOnID,
+		Type:        o.depType(),
+		CreatedAt:   env.Now,
+		CreatedBy:   env.Actor,
+	})
+	return nil
+}
+
+// checkTarget rejects a missing, self-referencing or unknown target and an
+// invalid type: the checks that must pass before the edge can be simulated.
+func (o AddDependency) checkTarget(issueID string, env *Env) error {
+	if o.DependsOnID == "" {
+		return fmt.Errorf("dependency target is required")
+	}
+	if o.DependsOnID == issueID {
+		return fmt.Errorf("an issue cannot depend on itself")
+	}
+	if !o.depType().IsValid() {
+		return fmt.Errorf("invalid dependency type: %s", o.Type)
+	}
+	if env.lookup(o.DependsOnID) == nil {
+		return notFoundError{id: o.DependsOnID}
+	}
+	return nil
+}
+
+func (o AddDependency) Describe() string {
+	return fmt.Sprintf("add %s dependency on %s", o.depType(), o.DependsOnID)
+}
+
+// RemoveDependency removes the dependency on DependsOnID.
+type RemoveDependency struct {
+	DependsOnID string
+}
+
+func (o RemoveDependency) Apply(issue *model.Issue, _ *Env) error {
+	for i, dep := range issue.Dependencies {
+		if dep != nil && dep.DependsOnID == o.DependsOnID {
+			issue.Dependencies = append(issue.Dependencies[:i], issue.Dependencies[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("%s does not depend on %s", issue.ID, o.DependsOnID)
+}
+
+func (o RemoveDependency) Describe() string {
+	return fmt.Sprintf("remove dependency on %s", o.DependsOnID)
+}
+
+// AddComment appends a comment authored by the acting user.
+type AddComment struct {
+	Text string
+}
+
+func (o AddComment) Apply(issue *model.Issue, env *Env) error {
+	text := strings.TrimSpace(o.Text)
+	if text == "" {
+		return fmt.Errorf("comment text is required")
+	}
+	issue.Comments = append(issue.Comments, &model.Comment{
+		ID:        NextCommentID(issue),
+		IssueID:   issue.ID,
+		Author:    env.Actor,
+		Text:      text,
+		CreatedAt: env.Now,
+	})
+	return nil
+}
+
+func (o AddComment) Describe() string { return "add comment" }
+
+// NextCommentID returns an ID greater than any existing comment ID on issue.
+func NextCommentID(issue *model.Issue) int64 {
+	var max int64
+	for _, c := range issue.Comments {
+		if c != nil && c.ID > max {
+			max = c.ID
+		}
+	}
+	if n := int64(len(issue.Comments)); n > max {
+		max = n
+	}
+	return max + 1
+}
@@ -0,0 +1,398 @@
+// Package mutate implements the write path for beads issues.
+//
+// All writers (the TUI, bd-ack, CLI flags) go through a Store, which loads the
+// beads JSONL file, applies typed operations to a single issue, validates the
+// result, and rewrites the file atomically (temp file + fsync + rename).
+//
+// The whole load-modify-write cycle runs under the beads directory's write
+// lock (pkg/filelock). Writers can additionally make a write conditional on
+// the version of the issue they last saw (WithExpect), and retry writes that
+// raced with a tool that does not take the lock (WithRetries).
+package mutate
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/filelock"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// ErrIssueNotFound is matched (via errors.Is) by the error returned when the
+// target issue does not exist.
+var ErrIssueNotFound = errors.New("issue not found")
+
+type notFoundError struct{ id string }
+
+func (e notFoundError) Error() string        { return fmt.Sprintf("issue %s not found", e.id) }
+func (e notFoundError) Is(target error) bool { return target == ErrIssueNotFound }
+
+// Store reads and writes the beads JSONL file of one repository.
+type Store struct {
+	repoPath string
+	filePath string
+	now      func() time.Time
+
+	expect      *Version
+	retries     int
+	lockTimeout time.Duration
+}
+
+// StoreOption configures a Store.
+type StoreOption func(*Store)
+
+// WithExpect makes Modify-based writes conditional: the target issue must
+// still match v (zero fields are not checked), otherwise the write fails with
+// a *ConflictError. Such conflicts are never retried, since the caller's view
+// of the issue is stale.
+func WithExpect(v Version) StoreOption {
+	return func(s *Store) { s.expect = &v }
+}
+
+// WithRetries lets a write that raced with an unlocked writer reload the file
+// and apply its change again, up to n more times.
+func WithRetries(n int) StoreOption {
+	return func(s *Store) { s.retries = max(0, n) }
+}
+
+// WithLockTimeout sets how long to wait for the write lock
+// (filelock.DefaultTimeout by default).
+func WithLockTimeout(d time.Duration) StoreOption {
+	return func(s *Store) { s.lockTimeout = d }
+}
+
+// NewStore creates a store for the repository at repoPath. BEADS_DIR is
+// honored the same way as for loading.
+func NewStore(repoPath string, opts ...StoreOption) *Store {
+	s := &Store{repoPath: repoPath, now: time.Now}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// NewFileStore creates a store bound to an already-resolved JSONL path, as
+// used by the TUI which knows the file it is watching.
+func NewFileStore(jsonlPath string, opts ...StoreOption) *Store {
+	s := &Store{filePath: jsonlPath, now: time.Now}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// DefaultActor returns the name recorded on comments and dependencies:
+// $BD_ACTOR, then $USER, then "unknown".
+func DefaultActor() string {
+	if actor := os.Getenv("BD_ACTOR"); actor != "" {
+		return actor
+	}
+	if user := os.Getenv("USER"); user != "" {
+		return user
+	}
+	return "unknown"
+}
+
+// Path resolves the JSONL file this store writes to. bd owns the SQLite
+// database, so a store pointed at one refuses to write.
+func (s *Store) Path() (string, error) {
+	if s.filePath != "" {
+		if loader.IsSQLiteFile(s.filePath) {
+			return "", fmt.Errorf("%s is a SQLite database; edits are written only to the JSONL file", s.filePath)
+		}
+		return s.filePath, nil
+	}
+	beadsDir, err := loader.GetBeadsDir(s.repoPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to get beads directory: %w", err)
+	}
+	jsonlPath, err := loader.FindJSONLPath(beadsDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to find JSONL file: %w", err)
+	}
+	return jsonlPath, nil
+}
+
+// Modify loads all issues, calls fn with a pointer to the target issue and the
+// full issue slice, and writes everything back if fn succeeds. fn must not
+// retain either argument, and may be called again after a retried conflict.
+func (s *Store) Modify(issueID string, fn func(issue *model.Issue, all []model.Issue) error) (*model.Issue, error) {
+	var updated model.Issue
+	err := s.update(func(issues []model.Issue) ([]model.Issue, error) {
+		idx := -1
+		for i := range issues {
+			if issues[i].ID == issueID {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			return nil, notFoundError{id: issueID}
+		}
+
+		if s.expect != nil {
+			if err := CheckVersion(issues[idx], *s.expect); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := fn(&issues[idx], issues); err != nil {
+			return nil, err
+		}
+		updated = issues[idx].Clone()
+		return issues, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// ModifyAll runs fn over every loaded issue in one locked transaction, for
+// writers that change several issues at once. fn edits issues in place and
+// returns the IDs it changed; the changed issues are validated and the file
+// is written only if fn succeeds and changed something. The returned slice
+// holds copies of the changed issues, in the order fn reported them. Like
+// Modify's fn, fn may be called again after a retried conflict.
+func (s *Store) ModifyAll(fn func(all []model.Issue) ([]string, error)) ([]model.Issue, error) {
+	var changed []model.Issue
+	err := s.update(func(issues []model.Issue) ([]model.Issue, error) {
+		ids, err := fn(issues)
+		if err != nil {
+			return nil, err
+		}
+		changed = changed[:0]
+		for _, id := range ids {
+			found := false
+			for i := range issues {
+				if issues[i].ID != id {
+					continue
+				}
+				if err := issues[i].Validate(); err != nil {
+					return nil, fmt.Errorf("%s: %w", id, err)
+				}
+				changed = append(changed, issues[i].Clone())
+				found = true
+				break
+			}
+			if !found {
+				return nil, notFoundError{id: id}
+			}
+		}
+		if len(changed) == 0 {
+			return nil, errNoChange
+		}
+		return issues, nil
+	})
+	if errors.Is(err, errNoChange) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return changed, nil
+}
+
+// errNoChange abandons a ModifyAll transaction that changed nothing.
+var errNoChange = errors.New("no change")
+
+// update runs one load-modify-write cycle under the write lock. change gets
+// the freshly loaded issues and returns the issues to write. If the file was
+// replaced between loading and writing, the write is abandoned with a
+// *ConflictError, and retried from a fresh load when retries remain.
+func (s *Store) update(change func(issues []model.Issue) ([]model.Issue, error)) error {
+	jsonlPath, err := s.Path()
+	if err != nil {
+		return err
+	}
+
+	lock, err := filelock.LockDir(filepath.Dir(jsonlPath), s.lockTimeout)
+	if err != nil {
+		return fmt.Errorf("failed to lock beads directory: %w", err)
+	}
+	defer lock.Unlock()
+
+	for attempt := 0; ; attempt++ {
+		err := s.updateOnce(jsonlPath, change)
+		var conflict *ConflictError
+		if errors.As(err, &conflict) && conflict.FileChanged && attempt < s.retries {
+			continue
+		}
+		return err
+	}
+}
+
+func (s *Store) updateOnce(jsonlPath string, change func(issues []model.Issue) ([]model.Issue, error)) error {
+	data, err := os.ReadFile(jsonlPath)
+	if err != nil {
+		return fmt.Errorf("failed to load issues: %w", err)
+	}
+	issues, err := loader.ParseIssues(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to load issues: %w", err)
+	}
+
+	issues, err = change(issues)
+	if err != nil {
+		return err
+	}
+
+	// Writers that hold the lock cannot interleave with us, but bd and other
+	// tools do not take it. Refuse to overwrite what they wrote.
+	current, err := os.ReadFile(jsonlPath)
+	if err != nil {
+		return fmt.Errorf("failed to re-read issues: %w", err)
+	}
+	if !bytes.Equal(current, data) {
+		return &ConflictError{FileChanged: true}
+	}
+
+	if err := WriteIssuesAtomic(jsonlPath, issues); err != nil {
+		return fmt.Errorf("failed to write issues: %w", err)
+	}
+	return nil
+}
+
+// Apply applies ops to the issue in order, bumps UpdatedAt, validates the
+// result, and writes the file. Either every op is applied or none is.
+func (s *Store) Apply(issueID, actor string, ops ...Op) (*model.Issue, error) {
+	if len(ops) == 0 {
+		return nil, fmt.Errorf("no operations given")
+	}
+	return s.Modify(issueID, func(issue *model.Issue, all []model.Issue) error {
+		env := &Env{Now: s.now(), Actor: actor, Issues: all}
+		return ApplyOps(issue, env, ops...)
+	})
+}
+
+// AddDependency applies op to issueID, returning the updated issue and how
+// the new dependency changes critical-path depth and the actionable count.
+// When the addition is rejected because it would close a cycle, the impact
+// (with the cycle path) is returned alongside the *CycleError.
+func (s *Store) AddDependency(issueID, actor string, op AddDependency) (*model.Issue, *analysis.DependencyImpact, error) {
+	var impact *analysis.DependencyImpact
+	updated, err := s.Modify(issueID, func(issue *model.Issue, all []model.Issue) error {
+		env := &Env{Now: s.now(), Actor: actor, Issues: all}
+		if err := op.checkTarget(issueID, env); err != nil {
+			return fmt.Errorf("%s: %w", op.Describe(), err)
+		}
+		computed := analysis.ComputeDependencyImpact(all, issueID, op.DependsOnID, op.depType())
+		impact = &computed
+		return ApplyOps(issue, env, op)
+	})
+	return updated, impact, err
+}
+
+// ApplyOps applies ops to issue in memory without touching disk. It is the
+// pure core of Store.Apply, exposed for previews and batch writers. On error
+// the issue is left unchanged.
+func ApplyOps(issue *model.Issue, env *Env, ops ...Op) error {
+	working := issue.Clone()
+	for _, op := range ops {
+		if err := op.Apply(&working, env); err != nil {
+			return fmt.Errorf("%s: %w", op.Describe(), err)
+		}
+	}
+	if env.Now.After(working.UpdatedAt) {
+		working.UpdatedAt = env.Now
+	}
+	if err := working.Validate(); err != nil {
+		return err
+	}
+	*issue = working
+	return nil
+}
+
+// Create appends a new issue. If issue.ID is empty an ID is minted using the
+// prefix most common among existing issues. Status, type and timestamps get
+// defaults when unset.
+func (s *Store) Create(issue model.Issue) (*model.Issue, error) {
+	var created model.Issue
+	err := s.update(func(issues []model.Issue) ([]model.Issue, error) {
+		candidate := issue
+		now := s.now()
+		if candidate.ID == "" {
+			candidate.ID = mintID(issues, candidate.Title, now)
+		}
+		for _, existing := range issues {
+			if existing.ID == candidate.ID {
+				return nil, fmt.Errorf("issue %s already exists", candidate.ID)
+			}
+		}
+		if candidate.Status == "" {
+			candidate.Status = model.StatusOpen
+		}
+		if candidate.IssueType == "" {
+			candidate.IssueType = model.TypeTask
+		}
+		if candidate.CreatedAt.IsZero() {
+			candidate.CreatedAt = now
+		}
+		if candidate.UpdatedAt.IsZero() {
+			candidate.UpdatedAt = candidate.CreatedAt
+		}
+		if err := candidate.Validate(); err != nil {
+			return nil, err
+		}
+
+		created = candidate.Clone()
+		return append(issues, candidate), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// mintID generates a hash-style ID ("bd-a3f8") using the dominant prefix of
+// existing issues, lengthening the hash until it is unique.
+func mintID(issues []model.Issue, title string, now time.Time) string {
+	prefix := dominantPrefix(issues)
+	taken := make(map[string]bool, len(issues))
+	for _, iss := range issues {
+		taken[iss.ID] = true
+	}
+
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%d", title, now.UnixNano())))
+	digest := hex.EncodeToString(sum[:])
+	for n := 4; n <= len(digest); n++ {
+		id := prefix + "-" + digest[:n]
+		if !taken[id] {
+			return id
+		}
+	}
+	return prefix + "-" + digest
+}
+
+func dominantPrefix(issues []model.Issue) string {
+	counts := make(map[string]int)
+	for _, iss := range issues {
+		if i := strings.LastIndex(iss.ID, "-"); i > 0 {
+			counts[iss.ID[:i]]++
+		}
+	}
+	if len(counts) == 0 {
+		return "bd"
+	}
+	prefixes := make([]string, 0, len(counts))
+	for p := range counts {
+		prefixes = append(prefixes, p)
+	}
+	sort.Slice(prefixes, func(i, j int) bool {
+		if counts[prefixes[i]] != counts[prefixes[j]] {
+			return counts[prefixes[i]] > counts[prefixes[j]]
+		}
+		return prefixes[i] < prefixes[j]
+	})
+	return prefixes[0]
+}
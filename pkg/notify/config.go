@@ -0,0 +1,188 @@
+// Package notify delivers drift and proactive alerts to external sinks.
+// Sinks are configured via .bv/notify.yaml; alerts already delivered to a
+// sink are remembered in .bv/notify-state.json so each is sent only once.
+package notify
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/drift"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFilename is the notify config file inside .bv/
+const ConfigFilename = "notify.yaml"
+
+// StateFilename records which alerts each sink has already received
+const StateFilename = "notify-state.json"
+
+// DefaultTimeout bounds webhook requests and command sinks
+const DefaultTimeout = 10 * time.Second
+
+// SinkType identifies a sink implementation
+type SinkType string
+
+const (
+	// SinkWebhook POSTs a JSON batch of alerts to a URL.
+	SinkWebhook SinkType = "webhook"
+	// SinkSocket publishes one JSON line per alert to clients of a Unix socket.
+	SinkSocket SinkType = "socket"
+	// SinkFile appends one JSON line per alert to a file.
+	SinkFile SinkType = "file"
+	// SinkCommand runs a shell command per alert with BV_ALERT_* variables set.
+	SinkCommand SinkType = "command"
+)
+
+// SinkConfig describes one sink in notify.yaml
+type SinkConfig struct {
+	Name string   `yaml:"name,omitempty" json:"name"`
+	Type SinkType `yaml:"type" json:"type"`
+
+	// webhook
+	URL     string            `yaml:"url,omitempty" json:"url,omitempty"`
+	Headers map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"` // values expand ${VAR}
+
+	// socket and file
+	Path string `yaml:"path,omitempty" json:"path,omitempty"` // relative paths are under the project dir
+
+	// command
+	Command string `yaml:"command,omitempty" json:"command,omitempty"`
+
+	// Timeout for webhook and command sinks (default 10s)
+	Timeout time.Duration `yaml:"-" json:"timeout,omitempty"`
+
+	// Filters: only alerts at or above MinSeverity, and of Types when set
+	MinSeverity drift.Severity `yaml:"min_severity,omitempty" json:"min_severity,omitempty"`
+	Types       []string       `yaml:"types,omitempty" json:"types,omitempty"`
+}
+
+// Config is the parsed notify.yaml
+type Config struct {
+	Sinks []SinkConfig `yaml:"sinks" json:"sinks"`
+}
+
+// ConfigPath returns the notify config path for a project
+func ConfigPath(projectDir string) string {
+	return filepath.Join(projectDir, ".bv", ConfigFilename)
+}
+
+// LoadConfig loads .bv/notify.yaml. A missing file yields an empty config.
+func LoadConfig(projectDir string) (*Config, error) {
+	data, err := os.ReadFile(ConfigPath(projectDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Config{}, nil
+		}
+		return nil, fmt.Errorf("reading notify config: %w", err)
+	}
+
+	var config Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("parsing notify config: %w", err)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid notify config: %w", err)
+	}
+	return &config, nil
+}
+
+// Validate fills in default names and timeouts and checks each sink has what
+// its type needs.
+func (c *Config) Validate() error {
+	seen := make(map[string]bool, len(c.Sinks))
+	for i := range c.Sinks {
+		s := &c.Sinks[i]
+		if s.Name == "" {
+			s.Name = fmt.Sprintf("%s-%d", s.Type, i+1)
+		}
+		if seen[s.Name] {
+			return fmt.Errorf("sink %d: duplicate name %q", i+1, s.Name)
+		}
+		seen[s.Name] = true
+		if s.Timeout <= 0 {
+			s.Timeout = DefaultTimeout
+		}
+
+		switch s.Type {
+		case SinkWebhook:
+			if !strings.HasPrefix(s.URL, "http://") && !strings.HasPrefix(s.URL, "https://") {
+				return fmt.Errorf("sink %q: webhook needs an http(s) url", s.Name)
+			}
+		case SinkSocket, SinkFile:
+			if strings.TrimSpace(s.Path) == "" {
+				return fmt.Errorf("sink %q: %s sink needs a path", s.Name, s.Type)
+			}
+		case SinkCommand:
+			if strings.TrimSpace(s.Command) == "" {
+				return fmt.Errorf("sink %q: command sink needs a command", s.Name)
+			}
+		default:
+			return fmt.Errorf("sink %q: unknown type %q (want webhook, socket, file or command)", s.Name, s.Type)
+		}
+
+		switch s.MinSeverity {
+		case "", drift.SeverityInfo, drift.SeverityWarning, drift.SeverityCritical:
+		default:
+			return fmt.Errorf("sink %q: min_severity must be info, warning or critical", s.Name)
+		}
+	}
+	return nil
+}
+
+// Accepts reports whether the sink's filters let an alert through
+func (s SinkConfig) Accepts(a drift.Alert) bool {
+	if severityRank(a.Severity) < severityRank(s.MinSeverity) {
+		return false
+	}
+	if len(s.Types) == 0 {
+		return true
+	}
+	for _, t := range s.Types {
+		if t == string(a.Type) {
+			return true
+		}
+	}
+	return false
+}
+
+func severityRank(s drift.Severity) int {
+	switch s {
+	case drift.SeverityCritical:
+		return 2
+	case drift.SeverityWarning:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// UnmarshalYAML accepts timeout as a Go duration ("30s") or a number of seconds.
+func (s *SinkConfig) UnmarshalYAML(node *yaml.Node) error {
+	type plain SinkConfig
+	var dto struct {
+		plain   `yaml:",inline"`
+		Timeout string `yaml:"timeout,omitempty"`
+	}
+	if err := node.Decode(&dto); err != nil {
+		return err
+	}
+	*s = SinkConfig(dto.plain)
+
+	if dto.Timeout != "" {
+		d, err := time.ParseDuration(dto.Timeout)
+		if err != nil {
+			var seconds float64
+			if _, scanErr := fmt.Sscanf(dto.Timeout, "%f", &seconds); scanErr != nil {
+				return fmt.Errorf("invalid timeout %q: %w", dto.Timeout, err)
+			}
+			d = time.Duration(seconds * float64(time.Second))
+		}
+		s.Timeout = d
+	}
+	return nil
+}
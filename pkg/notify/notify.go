@@ -0,0 +1,249 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/drift"
+)
+
+// AlertKey identifies an alert for deduplication: type, issue and label.
+// Cycle alerts carry no issue or label, so their key also names the cycles'
+// members, letting a different cycle notify even while another persists.
+func AlertKey(a drift.Alert) string {
+	key := string(a.Type) + "|" + a.IssueID + "|" + a.Label
+	if a.Type == drift.AlertNewCycle {
+		key += "|" + cycleIdentity(a.Details)
+	}
+	return key
+}
+
+// cycleIdentity turns "A → B → A" style cycle details into a stable string:
+// each cycle's members sorted and comma-joined, cycles sorted and
+// semicolon-joined.
+func cycleIdentity(details []string) string {
+	cycles := make([]string, 0, len(details))
+	for _, d := range details {
+		seen := make(map[string]bool)
+		var members []string
+		for _, id := range strings.Split(d, "→") {
+			id = strings.TrimSpace(id)
+			if id != "" && !seen[id] {
+				seen[id] = true
+				members = append(members, id)
+			}
+		}
+		sort.Strings(members)
+		cycles = append(cycles, strings.Join(members, ","))
+	}
+	sort.Strings(cycles)
+	return strings.Join(cycles, ";")
+}
+
+// State records, per sink, when each alert key was first delivered
+type State struct {
+	Sent map[string]map[string]time.Time `json:"sent"`
+}
+
+// StatePath returns the dedup state path for a project
+func StatePath(projectDir string) string {
+	return filepath.Join(projectDir, ".bv", StateFilename)
+}
+
+// LoadState reads the dedup state. A missing file yields an empty state.
+func LoadState(projectDir string) (*State, error) {
+	state := &State{Sent: make(map[string]map[string]time.Time)}
+	data, err := os.ReadFile(StatePath(projectDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, fmt.Errorf("reading notify state: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("parsing notify state: %w", err)
+	}
+	if state.Sent == nil {
+		state.Sent = make(map[string]map[string]time.Time)
+	}
+	return state, nil
+}
+
+// Save writes the state atomically
+func (s *State) Save(projectDir string) error {
+	path := StatePath(projectDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating state directory: %w", err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding notify state: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("writing notify state: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// Delivery is the outcome of one sink for one Notify call
+type Delivery struct {
+	Sink    string `json:"sink"`
+	Sent    int    `json:"sent"`
+	Skipped int    `json:"skipped"` // already delivered earlier
+	Error   string `json:"error,omitempty"`
+}
+
+// Report summarizes a Notify call
+type Report struct {
+	Alerts     int        `json:"alerts"`
+	Deliveries []Delivery `json:"deliveries"`
+}
+
+// Sent returns the total number of events delivered across sinks
+func (r Report) Sent() int {
+	n := 0
+	for _, d := range r.Deliveries {
+		n += d.Sent
+	}
+	return n
+}
+
+// Failed returns the deliveries that ended in an error
+func (r Report) Failed() []Delivery {
+	var failed []Delivery
+	for _, d := range r.Deliveries {
+		if d.Error != "" {
+			failed = append(failed, d)
+		}
+	}
+	return failed
+}
+
+// Notifier fans alerts out to the configured sinks, sending each alert to
+// each sink once. A failed delivery is retried on the next call, and an
+// alert that clears and later reappears is sent again.
+type Notifier struct {
+	projectDir string
+	project    string
+	configs    []SinkConfig
+	sinks      []Sink
+	state      *State
+	now        func() time.Time
+}
+
+// New opens every sink in cfg and loads the dedup state
+func New(cfg *Config, projectDir string) (*Notifier, error) {
+	state, err := LoadState(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	n := &Notifier{
+		projectDir: projectDir,
+		project:    filepath.Base(projectDir),
+		state:      state,
+		now:        func() time.Time { return time.Now().UTC() },
+	}
+	for _, sc := range cfg.Sinks {
+		sink, err := NewSink(sc, projectDir)
+		if err != nil {
+			n.Close()
+			return nil, fmt.Errorf("sink %q: %w", sc.Name, err)
+		}
+		n.configs = append(n.configs, sc)
+		n.sinks = append(n.sinks, sink)
+	}
+	return n, nil
+}
+
+// Sinks returns the open sinks in config order
+func (n *Notifier) Sinks() []Sink {
+	return n.sinks
+}
+
+// Notify delivers the alerts each sink has not yet received and persists
+// the dedup state. Sink failures are reported, not returned; the error is
+// only for state that could not be saved.
+func (n *Notifier) Notify(ctx context.Context, alerts []drift.Alert) (Report, error) {
+	now := n.now()
+	report := Report{Alerts: len(alerts)}
+
+	current := make(map[string]bool, len(alerts))
+	for _, a := range alerts {
+		current[AlertKey(a)] = true
+	}
+
+	for i, sink := range n.sinks {
+		cfg := n.configs[i]
+		sent := n.state.Sent[sink.Name()]
+		if sent == nil {
+			sent = make(map[string]time.Time)
+			n.state.Sent[sink.Name()] = sent
+		}
+		// Forget alerts that have cleared so a recurrence notifies again.
+		for key := range sent {
+			if !current[key] {
+				delete(sent, key)
+			}
+		}
+
+		delivery := Delivery{Sink: sink.Name()}
+		var events []Event
+		batch := make(map[string]bool)
+		for _, a := range alerts {
+			key := AlertKey(a)
+			if !cfg.Accepts(a) || batch[key] {
+				continue
+			}
+			batch[key] = true
+			if _, ok := sent[key]; ok {
+				delivery.Skipped++
+				continue
+			}
+			events = append(events, Event{Alert: a, Key: key, Project: n.project, SentAt: now})
+		}
+
+		if len(events) > 0 {
+			delivered := make([]string, len(events))
+			for j, ev := range events {
+				delivered[j] = ev.Key
+			}
+			if err := sink.Send(ctx, events); err != nil {
+				delivery.Error = err.Error()
+				delivered = nil
+				var partial *PartialError
+				if errors.As(err, &partial) {
+					delivered = partial.Delivered
+				}
+			}
+			delivery.Sent = len(delivered)
+			for _, key := range delivered {
+				sent[key] = now
+			}
+		}
+		report.Deliveries = append(report.Deliveries, delivery)
+	}
+
+	if err := n.state.Save(n.projectDir); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// Close closes every sink
+func (n *Notifier) Close() error {
+	var errs []error
+	for _, sink := range n.sinks {
+		if err := sink.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("closing %s: %w", sink.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,234 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/drift"
+)
+
+// recordingSink remembers what it was sent and fails while failing is set.
+// Events whose key is failKey are not delivered and make the batch partial.
+type recordingSink struct {
+	name    string
+	got     [][]Event
+	failing bool
+	failKey string
+}
+
+func (s *recordingSink) Name() string { return s.name }
+func (s *recordingSink) Close() error { return nil }
+func (s *recordingSink) Send(_ context.Context, events []Event) error {
+	if s.failing {
+		return errors.New("boom")
+	}
+	var delivered []Event
+	var keys []string
+	for _, ev := range events {
+		if ev.Key != s.failKey {
+			delivered = append(delivered, ev)
+			keys = append(keys, ev.Key)
+		}
+	}
+	s.got = append(s.got, delivered)
+	if len(delivered) < len(events) {
+		return &PartialError{Delivered: keys, Err: errors.New("boom")}
+	}
+	return nil
+}
+
+func newTestNotifier(t *testing.T, sinks ...SinkConfig) (*Notifier, []*recordingSink) {
+	t.Helper()
+	dir := t.TempDir()
+	state, err := LoadState(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := &Notifier{projectDir: dir, project: "proj", state: state, now: func() time.Time {
+		return time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	}}
+	var recs []*recordingSink
+	for _, sc := range sinks {
+		rec := &recordingSink{name: sc.Name}
+		n.configs = append(n.configs, sc)
+		n.sinks = append(n.sinks, rec)
+		recs = append(recs, rec)
+	}
+	return n, recs
+}
+
+var (
+	staleA  = drift.Alert{Type: drift.AlertStaleIssue, Severity: drift.SeverityWarning, IssueID: "A", Message: "A is stale"}
+	staleB  = drift.Alert{Type: drift.AlertStaleIssue, Severity: drift.SeverityCritical, IssueID: "B", Message: "B is stale"}
+	cycle   = drift.Alert{Type: drift.AlertNewCycle, Severity: drift.SeverityCritical, Message: "1 new cycle(s) detected", Details: []string{"B → A"}}
+	cascade = drift.Alert{Type: drift.AlertBlockingCascade, Severity: drift.SeverityInfo, IssueID: "C"}
+)
+
+func TestNotifyDeduplicatesAndRefiresAfterClearing(t *testing.T) {
+	n, recs := newTestNotifier(t, SinkConfig{Name: "all"})
+	ctx := context.Background()
+
+	report, err := n.Notify(ctx, []drift.Alert{staleA, staleB, staleA})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Sent() != 2 || len(recs[0].got) != 1 {
+		t.Fatalf("first round sent %d in %d batches, want 2 in 1", report.Sent(), len(recs[0].got))
+	}
+	if ev := recs[0].got[0][0]; ev.Key != "stale_issue|A|" || ev.Project != "proj" {
+		t.Errorf("event = %+v", ev)
+	}
+
+	report, _ = n.Notify(ctx, []drift.Alert{staleA, staleB})
+	if report.Sent() != 0 || report.Deliveries[0].Skipped != 2 {
+		t.Fatalf("repeat round = %+v, want everything skipped", report.Deliveries[0])
+	}
+
+	// B clears, then comes back: it is new again.
+	n.Notify(ctx, []drift.Alert{staleA})
+	report, _ = n.Notify(ctx, []drift.Alert{staleA, staleB})
+	if report.Sent() != 1 || recs[0].got[len(recs[0].got)-1][0].IssueID != "B" {
+		t.Fatalf("recurring B should be re-sent, report = %+v", report)
+	}
+
+	// State survives a restart.
+	reloaded, err := LoadState(n.projectDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reloaded.Sent["all"]) != 2 {
+		t.Errorf("persisted state = %v", reloaded.Sent)
+	}
+}
+
+func TestNotifyRecordsPartialDeliveries(t *testing.T) {
+	n, recs := newTestNotifier(t, SinkConfig{Name: "cmd"})
+	ctx := context.Background()
+	recs[0].failKey = AlertKey(staleB)
+
+	report, _ := n.Notify(ctx, []drift.Alert{staleA, staleB, cascade})
+	if report.Sent() != 2 || report.Deliveries[0].Error == "" {
+		t.Fatalf("first round = %+v, want 2 sent and an error", report.Deliveries[0])
+	}
+
+	recs[0].failKey = ""
+	report, _ = n.Notify(ctx, []drift.Alert{staleA, staleB, cascade})
+	last := recs[0].got[len(recs[0].got)-1]
+	if report.Sent() != 1 || len(last) != 1 || last[0].IssueID != "B" {
+		t.Fatalf("retry should resend only B, got %+v", last)
+	}
+}
+
+func TestNotifyDistinguishesCycles(t *testing.T) {
+	n, recs := newTestNotifier(t, SinkConfig{Name: "all"})
+	ctx := context.Background()
+	second := drift.Alert{Type: drift.AlertNewCycle, Severity: drift.SeverityCritical, Details: []string{"D → C → E"}}
+
+	n.Notify(ctx, []drift.Alert{cycle})
+	report, _ := n.Notify(ctx, []drift.Alert{second})
+	if report.Sent() != 1 {
+		t.Fatalf("a different cycle should be delivered, report = %+v", report)
+	}
+	if key := recs[0].got[1][0].Key; key != "new_cycle|||C,D,E" {
+		t.Errorf("key = %q", key)
+	}
+
+	// The same cycle listed from another starting point is not new.
+	rotated := second
+	rotated.Details = []string{"C → E → D → C"}
+	if report, _ = n.Notify(ctx, []drift.Alert{rotated}); report.Sent() != 0 {
+		t.Errorf("rotated cycle should be skipped, report = %+v", report)
+	}
+}
+
+func TestNotifyRetriesFailedSinksAndAppliesFilters(t *testing.T) {
+	n, recs := newTestNotifier(t,
+		SinkConfig{Name: "critical", MinSeverity: drift.SeverityCritical},
+		SinkConfig{Name: "cycles", Types: []string{"new_cycle"}},
+	)
+	ctx := context.Background()
+	alerts := []drift.Alert{staleA, staleB, cycle, cascade}
+
+	recs[1].failing = true
+	report, err := n.Notify(ctx, alerts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(recs[0].got[0]); got != 2 {
+		t.Errorf("critical sink got %d alerts, want B and the cycle", got)
+	}
+	if failed := report.Failed(); len(failed) != 1 || failed[0].Sink != "cycles" {
+		t.Fatalf("failed = %+v", failed)
+	}
+
+	recs[1].failing = false
+	report, _ = n.Notify(ctx, alerts)
+	if len(recs[1].got) != 1 || recs[1].got[0][0].Type != drift.AlertNewCycle {
+		t.Fatalf("cycles sink should receive the retried cycle alert, got %+v", recs[1].got)
+	}
+	if report.Deliveries[0].Sent != 0 {
+		t.Errorf("critical sink should not resend: %+v", report.Deliveries[0])
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	cfg, err := LoadConfig(dir)
+	if err != nil || len(cfg.Sinks) != 0 {
+		t.Fatalf("missing config = %+v, %v", cfg, err)
+	}
+
+	write := func(content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Join(dir, ".bv"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(ConfigPath(dir), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(`sinks:
+  - type: webhook
+    url: https://example.com/hook
+    timeout: 3s
+    min_severity: warning
+  - type: file
+    name: log
+    path: .bv/alerts.log
+  - type: command
+    command: echo hi
+    timeout: 5
+`)
+	cfg, err = LoadConfig(dir)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.Sinks[0].Name != "webhook-1" || cfg.Sinks[0].Timeout != 3*time.Second || cfg.Sinks[0].MinSeverity != drift.SeverityWarning {
+		t.Errorf("webhook sink = %+v", cfg.Sinks[0])
+	}
+	if cfg.Sinks[1].Name != "log" || cfg.Sinks[1].Timeout != DefaultTimeout {
+		t.Errorf("file sink = %+v", cfg.Sinks[1])
+	}
+	if cfg.Sinks[2].Timeout != 5*time.Second {
+		t.Errorf("numeric timeout = %v, want 5s", cfg.Sinks[2].Timeout)
+	}
+
+	for content, want := range map[string]string{
+		"sinks:\n  - type: pager\n":                                                        "unknown type",
+		"sinks:\n  - type: webhook\n    url: ftp://x\n":                                    "http(s) url",
+		"sinks:\n  - type: file\n":                                                         "needs a path",
+		"sinks:\n  - type: file\n    path: a\n    min_severity: loud\n":                    "min_severity",
+		"sinks:\n  - {type: file, path: a, name: x}\n  - {type: file, path: b, name: x}\n": "duplicate",
+	} {
+		write(content)
+		if _, err := LoadConfig(dir); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("config %q: error %v, want %q", content, err, want)
+		}
+	}
+}
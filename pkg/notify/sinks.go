@@ -0,0 +1,318 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/drift"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/hooks"
+)
+
+// Event is one alert as delivered to a sink
+type Event struct {
+	drift.Alert
+	Key     string    `json:"key"`
+	Project string    `json:"project"`
+	SentAt  time.Time `json:"sent_at"`
+}
+
+// Sink delivers alert events somewhere. A sink that delivers only part of a
+// batch returns a *PartialError so the delivered events are not sent again.
+type Sink interface {
+	Name() string
+	Send(ctx context.Context, events []Event) error
+	Close() error
+}
+
+// PartialError reports a batch that was only partly delivered. Delivered
+// holds the keys of the events that did go out.
+type PartialError struct {
+	Delivered []string
+	Err       error
+}
+
+func (e *PartialError) Error() string { return e.Err.Error() }
+func (e *PartialError) Unwrap() error { return e.Err }
+
+// NewSink builds the sink described by cfg. Relative paths resolve against
+// projectDir. Socket sinks start listening immediately.
+func NewSink(cfg SinkConfig, projectDir string) (Sink, error) {
+	resolve := func(path string) string {
+		if filepath.IsAbs(path) {
+			return path
+		}
+		return filepath.Join(projectDir, path)
+	}
+
+	switch cfg.Type {
+	case SinkWebhook:
+		return &WebhookSink{name: cfg.Name, url: cfg.URL, headers: cfg.Headers, client: &http.Client{Timeout: cfg.Timeout}}, nil
+	case SinkFile:
+		return &FileSink{name: cfg.Name, path: resolve(cfg.Path)}, nil
+	case SinkSocket:
+		return NewSocketSink(cfg.Name, resolve(cfg.Path))
+	case SinkCommand:
+		return &CommandSink{name: cfg.Name, command: cfg.Command, timeout: cfg.Timeout}, nil
+	}
+	return nil, fmt.Errorf("unknown sink type %q", cfg.Type)
+}
+
+// WebhookSink POSTs {"source":"bv","alerts":[...]} to a URL
+type WebhookSink struct {
+	name    string
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+// webhookPayload is the body of a webhook POST
+type webhookPayload struct {
+	Source string  `json:"source"`
+	SentAt string  `json:"sent_at"`
+	Alerts []Event `json:"alerts"`
+}
+
+// Name returns the sink name
+func (s *WebhookSink) Name() string { return s.name }
+
+// Send posts all events in one request; any non-2xx response is an error
+func (s *WebhookSink) Send(ctx context.Context, events []Event) error {
+	body, err := json.Marshal(webhookPayload{
+		Source: "bv",
+		SentAt: events[0].SentAt.Format(time.RFC3339),
+		Alerts: events,
+	})
+	if err != nil {
+		return fmt.Errorf("encoding webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "bv-notify")
+	for k, v := range s.headers {
+		req.Header.Set(k, os.ExpandEnv(v))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Close is a no-op
+func (s *WebhookSink) Close() error { return nil }
+
+// FileSink appends one JSON line per event
+type FileSink struct {
+	name string
+	path string
+}
+
+// Name returns the sink name
+func (s *FileSink) Name() string { return s.name }
+
+// Send appends events to the log file, creating it if needed
+func (s *FileSink) Send(_ context.Context, events []Event) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("creating log directory: %w", err)
+	}
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, ev := range events {
+		if err := enc.Encode(ev); err != nil {
+			f.Close()
+			return fmt.Errorf("encoding alert: %w", err)
+		}
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Close is a no-op
+func (s *FileSink) Close() error { return nil }
+
+// SocketSink listens on a Unix socket and writes one JSON line per event to
+// every connected subscriber (e.g. `nc -U .bv/alerts.sock`). Events sent
+// while nobody is connected are not replayed.
+type SocketSink struct {
+	name     string
+	path     string
+	listener net.Listener
+
+	mu    sync.Mutex
+	conns map[net.Conn]struct{}
+	wg    sync.WaitGroup
+}
+
+// NewSocketSink starts listening on path, replacing a stale socket left by a
+// previous run. It refuses to remove anything that is not a socket.
+func NewSocketSink(name, path string) (*SocketSink, error) {
+	if info, err := os.Lstat(path); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s exists and is not a socket", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("removing stale socket: %w", err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("creating socket directory: %w", err)
+	}
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &SocketSink{name: name, path: path, listener: l, conns: make(map[net.Conn]struct{})}
+	s.wg.Add(1)
+	go s.accept()
+	return s, nil
+}
+
+func (s *SocketSink) accept() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return // listener closed
+		}
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+	}
+}
+
+// Subscribers returns the number of connected clients
+func (s *SocketSink) Subscribers() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.conns)
+}
+
+// Name returns the sink name
+func (s *SocketSink) Name() string { return s.name }
+
+// Send writes events to all subscribers, dropping any that fail
+func (s *SocketSink) Send(_ context.Context, events []Event) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, ev := range events {
+		if err := enc.Encode(ev); err != nil {
+			return fmt.Errorf("encoding alert: %w", err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for conn := range s.conns {
+		_ = conn.SetWriteDeadline(time.Now().Add(2 * time.Second))
+		if _, err := conn.Write(buf.Bytes()); err != nil {
+			conn.Close()
+			delete(s.conns, conn)
+		}
+	}
+	return nil
+}
+
+// Close stops listening, disconnects subscribers and removes the socket file
+func (s *SocketSink) Close() error {
+	err := s.listener.Close()
+	s.wg.Wait()
+	s.mu.Lock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.conns = nil
+	s.mu.Unlock()
+	if rmErr := os.Remove(s.path); rmErr != nil && !errors.Is(rmErr, os.ErrNotExist) && err == nil {
+		err = rmErr
+	}
+	return err
+}
+
+// CommandSink runs a shell command once per event through hooks.Executor.
+// The alert is passed as BV_ALERT_* environment variables, with the full
+// event in BV_ALERT_JSON.
+type CommandSink struct {
+	name    string
+	command string
+	timeout time.Duration
+}
+
+// Name returns the sink name
+func (s *CommandSink) Name() string { return s.name }
+
+// Send runs the command for each event. A failure does not stop the rest;
+// if any event fails, a *PartialError lists the ones that were delivered.
+func (s *CommandSink) Send(_ context.Context, events []Event) error {
+	executor := hooks.NewExecutor(nil, hooks.ExportContext{Timestamp: events[0].SentAt})
+	hook := hooks.Hook{Name: s.name, Command: s.command, Timeout: s.timeout, OnError: "continue"}
+	var delivered []string
+	var errs []error
+	for _, ev := range events {
+		env, err := commandEnv(ev)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		result := executor.RunWithEnv(hook, hooks.Notify, env)
+		if !result.Success {
+			if result.Stderr != "" {
+				errs = append(errs, fmt.Errorf("%s: %w: %s", ev.Key, result.Error, result.Stderr))
+			} else {
+				errs = append(errs, fmt.Errorf("%s: %w", ev.Key, result.Error))
+			}
+			continue
+		}
+		delivered = append(delivered, ev.Key)
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return &PartialError{Delivered: delivered, Err: errors.Join(errs...)}
+}
+
+func commandEnv(ev Event) ([]string, error) {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return nil, fmt.Errorf("encoding alert: %w", err)
+	}
+	return []string{
+		"BV_ALERT_KEY=" + ev.Key,
+		"BV_ALERT_TYPE=" + string(ev.Type),
+		"BV_ALERT_SEVERITY=" + string(ev.Severity),
+		"BV_ALERT_MESSAGE=" + ev.Message,
+		"BV_ALERT_ISSUE_ID=" + ev.IssueID,
+		"BV_ALERT_LABEL=" + ev.Label,
+		"BV_ALERT_JSON=" + string(data),
+	}, nil
+}
+
+// Close is a no-op
+func (s *CommandSink) Close() error { return nil }
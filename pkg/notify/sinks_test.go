@@ -0,0 +1,189 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/drift"
+)
+
+func testEvents() []Event {
+	at := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	return []Event{
+		{Alert: staleA, Key: AlertKey(staleA), Project: "proj", SentAt: at},
+		{Alert: cycle, Key: AlertKey(cycle), Project: "proj", SentAt: at},
+	}
+}
+
+func TestWebhookSink(t *testing.T) {
+	t.Setenv("BV_TEST_TOKEN", "s3cret")
+	var got webhookPayload
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer srv.Close()
+
+	sink, err := NewSink(SinkConfig{Name: "hook", Type: SinkWebhook, URL: srv.URL,
+		Headers: map[string]string{"Authorization": "Bearer ${BV_TEST_TOKEN}"}, Timeout: time.Second}, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Send(context.Background(), testEvents()); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if got.Source != "bv" || len(got.Alerts) != 2 || got.Alerts[0].IssueID != "A" || got.Alerts[1].Type != drift.AlertNewCycle {
+		t.Errorf("payload = %+v", got)
+	}
+	if auth != "Bearer s3cret" {
+		t.Errorf("Authorization = %q, want expanded token", auth)
+	}
+
+	failing, _ := NewSink(SinkConfig{Name: "hook", Type: SinkWebhook, URL: srv.URL + "/fail", Timeout: time.Second}, t.TempDir())
+	if err := failing.Send(context.Background(), testEvents()); err == nil || !strings.Contains(err.Error(), "502") {
+		t.Errorf("expected a 502 error, got %v", err)
+	}
+}
+
+func TestFileSinkAppendsJSONLines(t *testing.T) {
+	dir := t.TempDir()
+	sink, err := NewSink(SinkConfig{Name: "log", Type: SinkFile, Path: ".bv/alerts.log"}, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := sink.Send(context.Background(), testEvents()); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, ".bv", "alerts.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines, want 4:\n%s", len(lines), data)
+	}
+	var ev Event
+	if err := json.Unmarshal([]byte(lines[0]), &ev); err != nil || ev.Key != "stale_issue|A|" || ev.Message != "A is stale" {
+		t.Errorf("first line = %s (%v)", lines[0], err)
+	}
+}
+
+func TestSocketSinkPublishesToSubscribers(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix sockets")
+	}
+	// Keep the path short: unix socket paths are limited to ~100 bytes.
+	dir, err := os.MkdirTemp("", "bvn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "alerts.sock")
+
+	sink, err := NewSocketSink("sock", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Nobody listening yet: sending is not an error.
+	if err := sink.Send(context.Background(), testEvents()); err != nil {
+		t.Fatalf("Send without subscribers: %v", err)
+	}
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	deadline := time.Now().Add(2 * time.Second)
+	for sink.Subscribers() == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if err := sink.Send(context.Background(), testEvents()); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	reader := bufio.NewReader(conn)
+	for _, want := range []string{"stale_issue|A|", "new_cycle|||A,B"} {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		var ev Event
+		if err := json.Unmarshal([]byte(line), &ev); err != nil || ev.Key != want {
+			t.Errorf("line = %q, want key %q", line, want)
+		}
+	}
+
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("socket file should be removed on close")
+	}
+
+	// A regular file in the way is never deleted.
+	if err := os.WriteFile(path, []byte("keep"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewSocketSink("sock", path); err == nil {
+		t.Errorf("expected an error for a non-socket path")
+	}
+}
+
+func TestCommandSinkRunsPerAlertWithEnv(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out.txt")
+	sink, err := NewSink(SinkConfig{Name: "cmd", Type: SinkCommand, Timeout: 5 * time.Second,
+		Command: `printf '%s %s %s\n' "$BV_ALERT_TYPE" "$BV_ALERT_ISSUE_ID" "$BV_ALERT_SEVERITY" >> ` + out}, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Send(context.Background(), testEvents()); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), "stale_issue A warning\nnew_cycle  critical\n"; got != want {
+		t.Errorf("command output = %q, want %q", got, want)
+	}
+
+	failing, _ := NewSink(SinkConfig{Name: "cmd", Type: SinkCommand, Timeout: 5 * time.Second, Command: "echo nope >&2; exit 3"}, dir)
+	if err := failing.Send(context.Background(), testEvents()); err == nil || !strings.Contains(err.Error(), "nope") {
+		t.Errorf("expected failure with stderr, got %v", err)
+	}
+
+	// A failure part-way through still runs the rest and names what went out.
+	partial, _ := NewSink(SinkConfig{Name: "cmd", Type: SinkCommand, Timeout: 5 * time.Second,
+		Command: `test "$BV_ALERT_TYPE" != stale_issue`}, dir)
+	err = partial.Send(context.Background(), testEvents())
+	var perr *PartialError
+	if !errors.As(err, &perr) || strings.Join(perr.Delivered, " ") != AlertKey(cycle) {
+		t.Errorf("expected a PartialError delivering only the cycle, got %v", err)
+	}
+}
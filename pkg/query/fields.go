@@ -0,0 +1,497 @@
+package query
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/recipe"
+)
+
+// Env is what a query is evaluated against besides the issue itself: the
+// whole issue set (for dependency terms) and, when the query uses them, the
+// graph metrics.
+type Env struct {
+	Now   time.Time
+	Stats *analysis.GraphStats // nil: metric terms compare against 0
+
+	byID       map[string]*model.Issue
+	blocksOpen map[string]int // open issues each issue blocks
+}
+
+// NewEnv indexes issues for evaluating queries over them. stats may be nil
+// when the query does not need metrics (see Query.NeedsMetrics).
+func NewEnv(issues []model.Issue, stats *analysis.GraphStats, now time.Time) *Env {
+	env := &Env{
+		Now:        now,
+		Stats:      stats,
+		byID:       make(map[string]*model.Issue, len(issues)),
+		blocksOpen: make(map[string]int),
+	}
+	for i := range issues {
+		env.byID[issues[i].ID] = &issues[i]
+	}
+	for i := range issues {
+		if issues[i].Status == model.StatusClosed {
+			continue
+		}
+		for _, dep := range issues[i].Dependencies {
+			if dep != nil && dep.Type.IsBlocking() {
+				env.blocksOpen[dep.DependsOnID]++
+			}
+		}
+	}
+	return env
+}
+
+// openBlockers counts the blocking dependencies of issue that are not closed
+func (env *Env) openBlockers(issue *model.Issue) int {
+	n := 0
+	for _, dep := range issue.Dependencies {
+		if dep == nil || !dep.Type.IsBlocking() {
+			continue
+		}
+		if blocker, ok := env.byID[dep.DependsOnID]; ok && blocker.Status != model.StatusClosed {
+			n++
+		}
+	}
+	return n
+}
+
+func (env *Env) isBlocked(issue *model.Issue) bool {
+	if issue.Status == model.StatusClosed {
+		return false
+	}
+	return issue.Status == model.StatusBlocked || env.openBlockers(issue) > 0
+}
+
+// isReady matches the TUI's ready filter: not closed or blocked, no open blockers
+func (env *Env) isReady(issue *model.Issue) bool {
+	if issue.Status == model.StatusClosed || issue.Status == model.StatusBlocked || issue.Status == model.StatusTombstone {
+		return false
+	}
+	return env.openBlockers(issue) == 0
+}
+
+type fieldKind int
+
+const (
+	kindEnum    fieldKind = iota // exact, case-insensitive
+	kindPattern                  // exact with * globs, case-insensitive
+	kindText                     // substring, case-insensitive
+	kindInt
+	kindFloat
+	kindDate
+	kindRef   // issue ID
+	kindState // is:
+	kindHas   // has:
+)
+
+type fieldSpec struct {
+	kind    fieldKind
+	metrics bool
+	help    string
+	// one of, by kind
+	str   func(*model.Issue) []string
+	num   func(*Env, *model.Issue) (float64, bool)
+	date  func(*model.Issue) (time.Time, bool)
+	ref   func(*Env, *model.Issue, string) bool
+	value func(*Env, *model.Issue, string) (bool, error) // is:/has:
+}
+
+// fields is the query vocabulary. Aliases share a spec.
+var fields = map[string]*fieldSpec{}
+
+func init() {
+	status := &fieldSpec{kind: kindEnum, help: "open, in_progress, blocked, closed",
+		str: func(i *model.Issue) []string { return []string{string(i.Status)} }}
+	issueType := &fieldSpec{kind: kindEnum, help: "bug, feature, task, epic, chore",
+		str: func(i *model.Issue) []string { return []string{string(i.IssueType)} }}
+	fields["status"] = status
+	fields["type"] = issueType
+	fields["issue_type"] = issueType
+	fields["label"] = &fieldSpec{kind: kindPattern, help: "any label, * globs",
+		str: func(i *model.Issue) []string { return i.Labels }}
+	fields["assignee"] = &fieldSpec{kind: kindPattern, help: `assignee, "" for unassigned`,
+		str: func(i *model.Issue) []string { return []string{i.Assignee} }}
+	fields["id"] = &fieldSpec{kind: kindPattern, help: "issue ID, * globs",
+		str: func(i *model.Issue) []string { return []string{i.ID} }}
+	fields["repo"] = &fieldSpec{kind: kindPattern, help: "source repo",
+		str: func(i *model.Issue) []string { return []string{i.SourceRepo} }}
+	fields["title"] = &fieldSpec{kind: kindText, help: "title substring",
+		str: func(i *model.Issue) []string { return []string{i.Title} }}
+	fields["text"] = &fieldSpec{kind: kindText, help: "substring of ID, title, labels, description, design, notes, acceptance criteria or comments",
+		str: issueText}
+
+	fields["priority"] = &fieldSpec{kind: kindInt, help: "0-4 or P0-P4",
+		num: func(_ *Env, i *model.Issue) (float64, bool) { return float64(i.Priority), true }}
+	fields["estimate"] = &fieldSpec{kind: kindInt, help: "estimated minutes",
+		num: func(_ *Env, i *model.Issue) (float64, bool) {
+			if i.EstimatedMinutes == nil {
+				return 0, false
+			}
+			return float64(*i.EstimatedMinutes), true
+		}}
+	fields["blocked_by_count"] = &fieldSpec{kind: kindInt, help: "open blockers",
+		num: func(env *Env, i *model.Issue) (float64, bool) { return float64(env.openBlockers(i)), true }}
+	fields["blocks_count"] = &fieldSpec{kind: kindInt, help: "open issues this one blocks",
+		num: func(env *Env, i *model.Issue) (float64, bool) { return float64(env.blocksOpen[i.ID]), true }}
+	fields["comments"] = &fieldSpec{kind: kindInt, help: "number of comments",
+		num: func(_ *Env, i *model.Issue) (float64, bool) { return float64(len(i.Comments)), true }}
+
+	metric := func(help string, get func(*analysis.GraphStats, string) float64) *fieldSpec {
+		return &fieldSpec{kind: kindFloat, metrics: true, help: help,
+			num: func(env *Env, i *model.Issue) (float64, bool) {
+				if env.Stats == nil {
+					return 0, true
+				}
+				return get(env.Stats, i.ID), true
+			}}
+	}
+	fields["pagerank"] = metric("PageRank", (*analysis.GraphStats).GetPageRankScore)
+	fields["betweenness"] = metric("betweenness centrality", (*analysis.GraphStats).GetBetweennessScore)
+	fields["eigenvector"] = metric("eigenvector centrality", (*analysis.GraphStats).GetEigenvectorScore)
+	fields["hubs"] = metric("HITS hub score", (*analysis.GraphStats).GetHubScore)
+	fields["authorities"] = metric("HITS authority score", (*analysis.GraphStats).GetAuthorityScore)
+	fields["critical_path"] = metric("critical path depth", (*analysis.GraphStats).GetCriticalPathScore)
+
+	fields["created"] = &fieldSpec{kind: kindDate, help: "created date",
+		date: func(i *model.Issue) (time.Time, bool) { return i.CreatedAt, !i.CreatedAt.IsZero() }}
+	fields["updated"] = &fieldSpec{kind: kindDate, help: "last update",
+		date: func(i *model.Issue) (time.Time, bool) { return i.UpdatedAt, !i.UpdatedAt.IsZero() }}
+	fields["closed"] = &fieldSpec{kind: kindDate, help: "close date",
+		date: func(i *model.Issue) (time.Time, bool) { return derefTime(i.ClosedAt) }}
+	fields["due"] = &fieldSpec{kind: kindDate, help: "due date",
+		date: func(i *model.Issue) (time.Time, bool) { return derefTime(i.DueDate) }}
+
+	fields["blocked_by"] = &fieldSpec{kind: kindRef, help: "has a blocking dependency on the issue",
+		ref: func(_ *Env, i *model.Issue, id string) bool {
+			for _, dep := range i.Dependencies {
+				if dep != nil && dep.Type.IsBlocking() && strings.EqualFold(dep.DependsOnID, id) {
+					return true
+				}
+			}
+			return false
+		}}
+	fields["blocks"] = &fieldSpec{kind: kindRef, help: "the issue has a blocking dependency on this one",
+		ref: func(env *Env, i *model.Issue, id string) bool {
+			target, ok := env.byID[id]
+			if !ok {
+				return false
+			}
+			for _, dep := range target.Dependencies {
+				if dep != nil && dep.Type.IsBlocking() && dep.DependsOnID == i.ID {
+					return true
+				}
+			}
+			return false
+		}}
+
+	fields["is"] = &fieldSpec{kind: kindState, help: "open, closed, blocked, ready (or actionable), overdue",
+		value: func(env *Env, i *model.Issue, v string) (bool, error) {
+			switch v {
+			case "open":
+				return i.Status != model.StatusClosed && i.Status != model.StatusTombstone, nil
+			case "closed":
+				return i.Status == model.StatusClosed, nil
+			case "blocked":
+				return env.isBlocked(i), nil
+			case "ready", "actionable", "unblocked":
+				return env.isReady(i), nil
+			case "overdue":
+				due, ok := derefTime(i.DueDate)
+				return ok && i.Status != model.StatusClosed && due.Before(env.Now), nil
+			}
+			return false, fmt.Errorf("query: unknown state is:%s (want open, closed, blocked, ready, overdue)", v)
+		}}
+	fields["has"] = &fieldSpec{kind: kindHas, help: "assignee, labels, description, design, acceptance_criteria, notes, comments, due, estimate, dependencies, blockers",
+		value: func(env *Env, i *model.Issue, v string) (bool, error) {
+			switch v {
+			case "assignee":
+				return i.Assignee != "", nil
+			case "label", "labels":
+				return len(i.Labels) > 0, nil
+			case "description":
+				return strings.TrimSpace(i.Description) != "", nil
+			case "design":
+				return strings.TrimSpace(i.Design) != "", nil
+			case "acceptance_criteria", "acceptance":
+				return strings.TrimSpace(i.AcceptanceCriteria) != "", nil
+			case "notes":
+				return strings.TrimSpace(i.Notes) != "", nil
+			case "comments":
+				return len(i.Comments) > 0, nil
+			case "due":
+				return i.DueDate != nil, nil
+			case "estimate":
+				return i.EstimatedMinutes != nil, nil
+			case "dependencies", "deps":
+				return len(i.Dependencies) > 0, nil
+			case "blockers":
+				return env.openBlockers(i) > 0, nil
+			}
+			return false, fmt.Errorf("query: unknown has:%s", v)
+		}}
+}
+
+// FieldNames returns the query field names, sorted
+func FieldNames() []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FieldHelp returns "name  description" lines for --help style listings
+func FieldHelp() []string {
+	names := FieldNames()
+	width := 0
+	for _, name := range names {
+		width = max(width, len(name))
+	}
+	lines := make([]string, len(names))
+	for i, name := range names {
+		lines[i] = fmt.Sprintf("%-*s  %s", width, name, fields[name].help)
+	}
+	return lines
+}
+
+func derefTime(t *time.Time) (time.Time, bool) {
+	if t == nil || t.IsZero() {
+		return time.Time{}, false
+	}
+	return *t, true
+}
+
+func issueText(i *model.Issue) []string {
+	texts := []string{i.ID, i.Title, i.Description, i.Design, i.AcceptanceCriteria, i.Notes}
+	texts = append(texts, i.Labels...)
+	for _, c := range i.Comments {
+		if c != nil {
+			texts = append(texts, c.Text)
+		}
+	}
+	return texts
+}
+
+// compileTerm validates a term and builds its matcher
+func compileTerm(field string, op Op, value string) (*Term, error) {
+	spec := fields[field]
+	t := &Term{Field: field, Op: op, Value: value, usesMetrics: spec.metrics}
+
+	ordered := op == OpLt || op == OpLe || op == OpGt || op == OpGe
+	if ordered && spec.kind != kindInt && spec.kind != kindFloat && spec.kind != kindDate {
+		return nil, fmt.Errorf("query: %s does not support %s", field, op)
+	}
+
+	var match func(*Env, *model.Issue) bool
+	switch spec.kind {
+	case kindEnum, kindPattern, kindText:
+		match = stringMatcher(spec, value)
+	case kindInt, kindFloat:
+		m, err := numberMatcher(field, spec, op, value)
+		if err != nil {
+			return nil, err
+		}
+		match = m
+	case kindDate:
+		m, err := dateMatcher(field, spec, op, value)
+		if err != nil {
+			return nil, err
+		}
+		match = m
+	case kindRef:
+		ids := splitValues(value)
+		match = func(env *Env, i *model.Issue) bool {
+			for _, id := range ids {
+				if spec.ref(env, i, id) {
+					return true
+				}
+			}
+			return false
+		}
+	case kindState, kindHas:
+		vals := splitValues(strings.ToLower(value))
+		// Validate now rather than on first match.
+		probe := &model.Issue{}
+		for _, v := range vals {
+			if _, err := spec.value(NewEnv(nil, nil, time.Now()), probe, v); err != nil {
+				return nil, err
+			}
+		}
+		match = func(env *Env, i *model.Issue) bool {
+			for _, v := range vals {
+				if ok, _ := spec.value(env, i, v); ok {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	if op == OpNe {
+		inner := match
+		match = func(env *Env, i *model.Issue) bool { return !inner(env, i) }
+	}
+	t.match = match
+	return t, nil
+}
+
+// splitValues splits a comma-separated any-of value
+func splitValues(value string) []string {
+	parts := strings.Split(value, ",")
+	out := parts[:0]
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	if len(out) == 0 {
+		return []string{""}
+	}
+	return out
+}
+
+func stringMatcher(spec *fieldSpec, value string) func(*Env, *model.Issue) bool {
+	var wants []string
+	if spec.kind == kindText {
+		// A phrase may contain commas.
+		wants = []string{strings.ToLower(value)}
+	} else {
+		wants = splitValues(strings.ToLower(value))
+	}
+	return func(_ *Env, i *model.Issue) bool {
+		for _, have := range spec.str(i) {
+			have = strings.ToLower(have)
+			for _, want := range wants {
+				switch spec.kind {
+				case kindText:
+					if strings.Contains(have, want) {
+						return true
+					}
+				case kindPattern:
+					if ok, _ := path.Match(want, have); ok || want == have {
+						return true
+					}
+				default:
+					if want == have {
+						return true
+					}
+				}
+			}
+		}
+		// `assignee:""` matches the unassigned
+		return spec.kind != kindText && len(spec.str(i)) == 0 && len(wants) == 1 && wants[0] == ""
+	}
+}
+
+func numberMatcher(field string, spec *fieldSpec, op Op, value string) (func(*Env, *model.Issue) bool, error) {
+	var wants []float64
+	for _, v := range splitValues(value) {
+		if field == "priority" && len(v) > 1 && (v[0] == 'P' || v[0] == 'p') {
+			v = v[1:]
+		}
+		n, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, fmt.Errorf("query: %s%s%s: not a number", field, op, value)
+		}
+		wants = append(wants, n)
+	}
+	if len(wants) > 1 && op != OpMatch && op != OpEq && op != OpNe {
+		return nil, fmt.Errorf("query: %s%s takes a single value", field, op)
+	}
+	return func(env *Env, i *model.Issue) bool {
+		have, ok := spec.num(env, i)
+		if !ok {
+			return false
+		}
+		for _, want := range wants {
+			if compare(op, have, want) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+func compare(op Op, a, b float64) bool {
+	switch op {
+	case OpLt:
+		return a < b
+	case OpLe:
+		return a <= b
+	case OpGt:
+		return a > b
+	case OpGe:
+		return a >= b
+	}
+	return a == b
+}
+
+// dateMatcher compares dates. A relative value (14d, 2w, 3m, 1y) compares the
+// age, so updated>14d is "last updated more than 14 days ago" and updated:7d
+// is "within the last 7 days". An ISO date compares the date itself, with
+// updated:2025-01-31 matching that whole day.
+func dateMatcher(field string, spec *fieldSpec, op Op, value string) (func(*Env, *model.Issue) bool, error) {
+	if _, err := recipe.ParseRelativeTime(value, time.Now()); err != nil || value == "" {
+		return nil, fmt.Errorf("query: %s%s%s: want a relative age like 14d or a date like 2025-01-31", field, op, value)
+	}
+	relative := isRelative(value)
+	dateOnly := len(value) == len("2006-01-02")
+
+	return func(env *Env, i *model.Issue) bool {
+		have, ok := spec.date(i)
+		if !ok {
+			return false
+		}
+		at, _ := recipe.ParseRelativeTime(value, env.Now)
+		if relative {
+			// Age comparisons run the other way from date comparisons.
+			switch op {
+			case OpLt:
+				return have.After(at)
+			case OpLe, OpMatch, OpEq, OpNe:
+				return !have.Before(at)
+			case OpGt:
+				return have.Before(at)
+			case OpGe:
+				return !have.After(at)
+			}
+		}
+		if dateOnly && (op == OpMatch || op == OpEq || op == OpNe) {
+			y1, m1, d1 := have.In(at.Location()).Date()
+			y2, m2, d2 := at.Date()
+			return y1 == y2 && m1 == m2 && d1 == d2
+		}
+		switch op {
+		case OpLt:
+			return have.Before(at)
+		case OpLe:
+			return !have.After(at)
+		case OpGt:
+			return have.After(at)
+		case OpGe:
+			return !have.Before(at)
+		}
+		return have.Equal(at)
+	}, nil
+}
+
+func isRelative(value string) bool {
+	if len(value) < 2 {
+		return false
+	}
+	switch value[len(value)-1] {
+	case 'd', 'w', 'm', 'y', 'D', 'W', 'M', 'Y':
+	default:
+		return false
+	}
+	_, err := strconv.Atoi(value[:len(value)-1])
+	return err == nil
+}
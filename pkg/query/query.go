@@ -0,0 +1,425 @@
+// Package query implements bv's issue query language, shared by --query, the
+// TUI filter bar and recipe filters:
+//
+//	status:open label:api -label:wontfix priority<=1 pagerank>0.02
+//	blocked_by:bd-12 updated>14d text:"oauth"
+//
+// Terms are ANDed. OR, NOT (or a leading '-') and parentheses combine them;
+// OR binds looser than AND. A term is field, operator (: = < <= > >= !=) and
+// value; a comma-separated value matches any of its parts, and a word with no
+// field is a text search.
+package query
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// Query is a parsed query
+type Query struct {
+	Source string
+	Root   Expr
+}
+
+// Expr is a node of the query AST
+type Expr interface {
+	Match(env *Env, issue *model.Issue) bool
+	String() string
+}
+
+// And matches when every term matches
+type And []Expr
+
+// Or matches when any term matches
+type Or []Expr
+
+// Not negates its term
+type Not struct{ X Expr }
+
+// Term is a single field comparison
+type Term struct {
+	Field string
+	Op    Op
+	Value string
+
+	match       func(env *Env, issue *model.Issue) bool
+	usesMetrics bool
+}
+
+// Op is a comparison operator
+type Op string
+
+const (
+	OpMatch Op = ":"
+	OpEq    Op = "="
+	OpNe    Op = "!="
+	OpLt    Op = "<"
+	OpLe    Op = "<="
+	OpGt    Op = ">"
+	OpGe    Op = ">="
+)
+
+// operators in the order they are tried, longest first
+var operators = []Op{OpLe, OpGe, OpNe, OpMatch, OpEq, OpLt, OpGt}
+
+func (a And) Match(env *Env, issue *model.Issue) bool {
+	for _, x := range a {
+		if !x.Match(env, issue) {
+			return false
+		}
+	}
+	return true
+}
+
+func (o Or) Match(env *Env, issue *model.Issue) bool {
+	for _, x := range o {
+		if x.Match(env, issue) {
+			return true
+		}
+	}
+	return false
+}
+
+func (n Not) Match(env *Env, issue *model.Issue) bool { return !n.X.Match(env, issue) }
+
+func (t *Term) Match(env *Env, issue *model.Issue) bool { return t.match(env, issue) }
+
+func (a And) String() string { return joinExprs([]Expr(a), " ") }
+func (o Or) String() string  { return "(" + joinExprs([]Expr(o), " OR ") + ")" }
+func (n Not) String() string { return "-" + n.X.String() }
+
+func (t *Term) String() string {
+	value := t.Value
+	if value == "" || strings.ContainsAny(value, " \t()\"") {
+		value = fmt.Sprintf("%q", value)
+	}
+	return t.Field + string(t.Op) + value
+}
+
+func joinExprs(xs []Expr, sep string) string {
+	parts := make([]string, len(xs))
+	for i, x := range xs {
+		if and, ok := x.(And); ok && len(xs) > 1 {
+			parts[i] = "(" + and.String() + ")"
+		} else {
+			parts[i] = x.String()
+		}
+	}
+	return strings.Join(parts, sep)
+}
+
+// Parse parses a query. An empty query matches every issue.
+func Parse(s string) (*Query, error) {
+	toks, err := lex(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.toks) {
+		return nil, fmt.Errorf("query: unexpected %q", p.toks[p.pos].text)
+	}
+	if root == nil {
+		root = And{}
+	}
+	return &Query{Source: strings.TrimSpace(s), Root: root}, nil
+}
+
+// MustParse is Parse that panics on error, for queries fixed at compile time
+func MustParse(s string) *Query {
+	q, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return q
+}
+
+// Match reports whether issue satisfies the query
+func (q *Query) Match(env *Env, issue *model.Issue) bool {
+	return q.Root.Match(env, issue)
+}
+
+// Filter returns the issues that satisfy the query, in order
+func (q *Query) Filter(issues []model.Issue, env *Env) []model.Issue {
+	var out []model.Issue
+	for i := range issues {
+		if q.Match(env, &issues[i]) {
+			out = append(out, issues[i])
+		}
+	}
+	return out
+}
+
+// String returns the query in canonical form
+func (q *Query) String() string { return q.Root.String() }
+
+// NeedsMetrics reports whether the query uses graph metrics, so callers can
+// skip graph analysis when it doesn't.
+func (q *Query) NeedsMetrics() bool { return needsMetrics(q.Root) }
+
+func needsMetrics(x Expr) bool {
+	switch x := x.(type) {
+	case And:
+		for _, y := range x {
+			if needsMetrics(y) {
+				return true
+			}
+		}
+	case Or:
+		for _, y := range x {
+			if needsMetrics(y) {
+				return true
+			}
+		}
+	case Not:
+		return needsMetrics(x.X)
+	case *Term:
+		return x.usesMetrics
+	}
+	return false
+}
+
+// LooksLikeQuery reports whether s uses query syntax (a known field with an
+// operator, or OR/NOT/parentheses) rather than being plain search text.
+func LooksLikeQuery(s string) bool {
+	toks, err := lex(s)
+	if err != nil {
+		return false
+	}
+	for _, tok := range toks {
+		switch tok.kind {
+		case tokOr, tokNot, tokLParen:
+			return true
+		case tokWord:
+			if field, _, _, ok := splitTerm(tok.text); ok {
+				if _, known := fields[field]; known {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// Lexer
+
+type tokKind int
+
+const (
+	tokWord tokKind = iota
+	tokOr
+	tokAnd
+	tokNot
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	kind   tokKind
+	text   string // word text with quotes removed
+	quoted bool   // the word contained quotes, so an empty value is intended
+}
+
+func lex(s string) ([]token, error) {
+	var toks []token
+	rs := []rune(s)
+	for i := 0; i < len(rs); {
+		switch c := rs[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			toks = append(toks, token{kind: tokLParen, text: "("})
+			i++
+		case c == ')':
+			toks = append(toks, token{kind: tokRParen, text: ")"})
+			i++
+		case c == '-' && i+1 < len(rs) && rs[i+1] != ' ' && (i == 0 || isSpaceOrParen(rs[i-1])):
+			toks = append(toks, token{kind: tokNot, text: "-"})
+			i++
+		default:
+			var b strings.Builder
+			quoted, hadQuotes := false, false
+			for ; i < len(rs); i++ {
+				c := rs[i]
+				if c == '"' {
+					quoted = !quoted
+					hadQuotes = true
+					continue
+				}
+				if !quoted && (c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '(' || c == ')') {
+					break
+				}
+				if c == '\\' && quoted && i+1 < len(rs) {
+					i++
+					c = rs[i]
+				}
+				b.WriteRune(c)
+			}
+			if quoted {
+				return nil, fmt.Errorf("query: unterminated quote")
+			}
+			word := b.String()
+			kind := tokWord
+			switch word {
+			case "OR", "||":
+				kind = tokOr
+			case "AND", "&&":
+				kind = tokAnd
+			case "NOT":
+				kind = tokNot
+			}
+			toks = append(toks, token{kind: kind, text: word, quoted: hadQuotes})
+		}
+	}
+	return toks, nil
+}
+
+func isSpaceOrParen(c rune) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '('
+}
+
+// Parser
+
+type parser struct {
+	toks []token
+	pos  int
+}
+
+func (p *parser) peek() (token, bool) {
+	if p.pos >= len(p.toks) {
+		return token{}, false
+	}
+	return p.toks[p.pos], true
+}
+
+// parseOr parses and-groups separated by OR. Returns nil for no terms.
+func (p *parser) parseOr() (Expr, error) {
+	var terms Or
+	for {
+		x, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		tok, ok := p.peek()
+		if x == nil {
+			if len(terms) > 0 || (ok && tok.kind == tokOr) {
+				return nil, fmt.Errorf("query: OR needs a term on each side")
+			}
+			return nil, nil
+		}
+		terms = append(terms, x)
+		if !ok || tok.kind != tokOr {
+			break
+		}
+		p.pos++
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return terms, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	var terms And
+	for {
+		tok, ok := p.peek()
+		if !ok || tok.kind == tokOr || tok.kind == tokRParen {
+			break
+		}
+		if tok.kind == tokAnd {
+			p.pos++
+			continue
+		}
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, x)
+	}
+	switch len(terms) {
+	case 0:
+		return nil, nil
+	case 1:
+		return terms[0], nil
+	}
+	return terms, nil
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	tok, ok := p.peek()
+	if !ok {
+		return nil, fmt.Errorf("query: unexpected end of query")
+	}
+	p.pos++
+	switch tok.kind {
+	case tokNot:
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return Not{X: x}, nil
+	case tokLParen:
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing, ok := p.peek(); !ok || closing.kind != tokRParen {
+			return nil, fmt.Errorf("query: missing )")
+		}
+		p.pos++
+		if x == nil {
+			return nil, fmt.Errorf("query: empty ()")
+		}
+		return x, nil
+	case tokWord:
+		return parseTerm(tok)
+	}
+	return nil, fmt.Errorf("query: unexpected %q", tok.text)
+}
+
+// splitTerm splits "field<op>value". ok is false for a bare word.
+func splitTerm(word string) (field string, op Op, value string, ok bool) {
+	end := 0
+	for end < len(word) && (word[end] == '_' || (word[end] >= 'a' && word[end] <= 'z') || (word[end] >= 'A' && word[end] <= 'Z')) {
+		end++
+	}
+	if end == 0 {
+		return "", "", "", false
+	}
+	rest := word[end:]
+	for _, candidate := range operators {
+		if strings.HasPrefix(rest, string(candidate)) {
+			return strings.ToLower(word[:end]), candidate, rest[len(candidate):], true
+		}
+	}
+	return "", "", "", false
+}
+
+func parseTerm(tok token) (Expr, error) {
+	word := tok.text
+	field, op, value, ok := splitTerm(word)
+	if !ok {
+		for _, candidate := range operators {
+			if !tok.quoted && strings.HasPrefix(word, string(candidate)) {
+				return nil, fmt.Errorf("query: %q needs a field before %s (quote it to search for the text)", word, candidate)
+			}
+		}
+		return compileTerm("text", OpMatch, word)
+	}
+	if value == "" && !tok.quoted {
+		return nil, fmt.Errorf("query: %s%s needs a value (use \"\" to match an empty one)", field, op)
+	}
+	if _, known := fields[field]; !known {
+		// A URL in free text is not a misspelt field.
+		if op == OpMatch && strings.HasPrefix(value, "//") {
+			return compileTerm("text", OpMatch, word)
+		}
+		return nil, fmt.Errorf("query: unknown field %q (known: %s)", field, strings.Join(FieldNames(), ", "))
+	}
+	return compileTerm(field, op, value)
+}
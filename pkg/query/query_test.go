@@ -0,0 +1,176 @@
+package query
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+var testNow = time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC)
+
+func testIssues() []model.Issue {
+	days := func(n int) time.Time { return testNow.AddDate(0, 0, -n) }
+	due := testNow.AddDate(0, 0, -1)
+	closed := days(90)
+	est := 90
+	return []model.Issue{
+		{ID: "bd-1", Title: "OAuth login", Status: model.StatusOpen, Priority: 0, IssueType: model.TypeFeature,
+			Labels: []string{"api", "auth"}, Assignee: "alice", CreatedAt: days(60), UpdatedAt: days(30),
+			Comments: []*model.Comment{{ID: 1, Text: "needs a refresh token flow"}}},
+		{ID: "bd-2", Title: "Fix crash", Status: model.StatusInProgress, Priority: 1, IssueType: model.TypeBug,
+			Labels: []string{"api", "wontfix"}, CreatedAt: days(20), UpdatedAt: days(2), DueDate: &due,
+			Dependencies: []*model.Dependency{{IssueID: "bd-2", DependsOnID: "bd-1", Type: model.DepBlocks}}},
+		{ID: "bd-3", Title: "Write docs", Status: model.StatusOpen, Priority: 3, IssueType: model.TypeTask,
+			Labels: []string{"docs"}, CreatedAt: days(5), UpdatedAt: days(1), EstimatedMinutes: &est,
+			Dependencies: []*model.Dependency{{IssueID: "bd-3", DependsOnID: "bd-4", Type: model.DepBlocks}}},
+		{ID: "bd-4", Title: "Old cleanup", Status: model.StatusClosed, Priority: 2, IssueType: model.TypeChore,
+			CreatedAt: days(100), UpdatedAt: days(90), ClosedAt: &closed, Description: "Remove the legacy OAuth shim"},
+	}
+}
+
+func matchIDs(t *testing.T, q string, stats *analysis.GraphStats) string {
+	t.Helper()
+	parsed, err := Parse(q)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", q, err)
+	}
+	issues := testIssues()
+	var ids []string
+	for _, issue := range parsed.Filter(issues, NewEnv(issues, stats, testNow)) {
+		ids = append(ids, issue.ID)
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestQueryMatching(t *testing.T) {
+	tests := []struct {
+		query, want string
+	}{
+		{"", "bd-1,bd-2,bd-3,bd-4"},
+		{"status:open", "bd-1,bd-3"},
+		{"status:open,in_progress", "bd-1,bd-2,bd-3"},
+		{"status!=closed", "bd-1,bd-2,bd-3"},
+		{"type:bug", "bd-2"},
+		{"label:api -label:wontfix", "bd-1"},
+		{"label:API", "bd-1,bd-2"},
+		{"label:a*", "bd-1,bd-2"},
+		{"priority<=1", "bd-1,bd-2"},
+		{"priority:P3", "bd-3"},
+		{"priority:0,2", "bd-1,bd-4"},
+		{"assignee:alice", "bd-1"},
+		{`assignee:""`, "bd-2,bd-3,bd-4"},
+		{"id:bd-1", "bd-1"},
+		{"blocked_by:bd-1", "bd-2"},
+		{"blocks:bd-2", "bd-1"},
+		{"updated>14d", "bd-1,bd-4"},
+		{"updated<14d", "bd-2,bd-3"},
+		{"created:7d", "bd-3"},
+		{"created>=2025-06-01", "bd-2,bd-3"},
+		{"updated:2025-06-29", "bd-3"},
+		{"closed<1y", "bd-4"},
+		{`text:"refresh token"`, "bd-1"},
+		{"oauth", "bd-1,bd-4"},
+		{"oauth status:open", "bd-1"},
+		{"is:blocked", "bd-2"},
+		{"is:ready", "bd-1,bd-3"},
+		{"is:overdue", "bd-2"},
+		{"has:estimate", "bd-3"},
+		{"-has:labels", "bd-4"},
+		{"blocks_count>0", "bd-1,bd-4"},
+		{"blocked_by_count:1", "bd-2"},
+		{"estimate>60", "bd-3"},
+		{"type:bug OR type:task", "bd-2,bd-3"},
+		{"label:api (priority:0 OR is:overdue)", "bd-1,bd-2"},
+		{"NOT (status:closed OR label:api)", "bd-3"},
+		{"status:open AND label:docs", "bd-3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := matchIDs(t, tt.query, nil); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryMetrics(t *testing.T) {
+	issues := testIssues()
+	stats := analysis.NewAnalyzer(issues).Analyze()
+
+	q := MustParse("pagerank>0 status:open")
+	if !q.NeedsMetrics() {
+		t.Error("pagerank term should need metrics")
+	}
+	if MustParse("status:open -label:x").NeedsMetrics() {
+		t.Error("plain terms should not need metrics")
+	}
+
+	// bd-1 blocks bd-2, so it outranks the issues nothing depends on.
+	top := stats.GetPageRankScore("bd-1")
+	got := matchIDs(t, "pagerank>="+strconv.FormatFloat(top, 'g', -1, 64), &stats)
+	if !strings.Contains(got, "bd-1") || strings.Contains(got, "bd-2") {
+		t.Errorf("pagerank filter = %q", got)
+	}
+	if got := matchIDs(t, "pagerank>0", nil); got != "" {
+		t.Errorf("metric terms without stats should compare against 0, got %q", got)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, q := range []string{
+		`text:"unterminated`,
+		"(status:open",
+		"status:open)",
+		"OR status:open",
+		"status:open OR",
+		"priority<=high",
+		"label>2",
+		"updated>soon",
+		"is:sleeping",
+		"has:feelings",
+		"colour:red",
+		"()",
+		"status:",
+		"blocked_by:",
+		"priority<=",
+		":",
+		":open",
+	} {
+		if _, err := Parse(q); err == nil {
+			t.Errorf("Parse(%q) should fail", q)
+		}
+	}
+}
+
+func TestQueryString(t *testing.T) {
+	q := MustParse(`status:open  -label:wontfix (type:bug OR priority<=1) text:"oauth flow"`)
+	want := `status:open -label:wontfix (type:bug OR priority<=1) text:"oauth flow"`
+	if got := q.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if again := MustParse(q.String()).String(); again != want {
+		t.Errorf("round trip = %q", again)
+	}
+}
+
+func TestLooksLikeQuery(t *testing.T) {
+	for s, want := range map[string]bool{
+		"login bug":           false,
+		"bd-12":               false,
+		"status:open":         true,
+		"priority<=1":         true,
+		"-label:wontfix":      true,
+		"auth OR login":       true,
+		"note: check this":    false,
+		"http://example.com":  false,
+		`text:"refresh flow"`: true,
+	} {
+		if got := LooksLikeQuery(s); got != want {
+			t.Errorf("LooksLikeQuery(%q) = %v, want %v", s, got, want)
+		}
+	}
+}
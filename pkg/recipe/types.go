@@ -32,6 +32,7 @@ type FilterConfig struct {
 	Actionable    *bool    `yaml:"actionable,omitempty" json:"actionable,omitempty"`         // true = no open blockers
 	TitleContains string   `yaml:"title_contains,omitempty" json:"title_contains,omitempty"` // Substring match
 	IDPrefix      string   `yaml:"id_prefix,omitempty" json:"id_prefix,omitempty"`           // e.g., "bv-" for project filtering
+	Query         string   `yaml:"query,omitempty" json:"query,omitempty"`                   // bv query language, e.g. "label:api priority<=1"
 }
 
 // SortConfig defines how to order issues
@@ -0,0 +1,272 @@
+package search
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// Chunked documents
+//
+// Each issue is indexed as several chunks so long design notes and comment
+// threads don't dilute one vector, and a match can say where it was. The
+// summary chunk (IssueDocument) is keyed by the bare issue ID; the others are
+// keyed "<issue>#<field>", "<issue>#comment-<id>", with ".2", ".3", ... for
+// the later windows of long text. Issue scores are the max over their chunks.
+
+// ChunkSep separates the issue ID from the chunk name in index keys
+const ChunkSep = "#"
+
+// Chunk fields
+const (
+	FieldSummary            = "summary" // ID, title, labels and description
+	FieldDescription        = "description"
+	FieldDesign             = "design"
+	FieldAcceptanceCriteria = "acceptance_criteria"
+	FieldNotes              = "notes"
+	FieldComment            = "comment"
+)
+
+// maxChunkRunes bounds a chunk body; longer text is split on paragraphs
+const maxChunkRunes = 1500
+
+// Chunk is one indexed piece of an issue
+type Chunk struct {
+	Key       string // index key
+	IssueID   string
+	Field     string
+	CommentID int64  // comment chunks only
+	Body      string // the issue text this chunk covers
+	Text      string // what gets embedded (Body with the issue title for context)
+}
+
+// IssueChunks splits an issue into its summary, description (when too long
+// for the summary alone), design, acceptance criteria, notes and comment
+// chunks.
+func IssueChunks(issue model.Issue) []Chunk {
+	id := strings.TrimSpace(issue.ID)
+	if id == "" {
+		return nil
+	}
+	title := strings.TrimSpace(issue.Title)
+	summary := Chunk{Key: id, IssueID: id, Field: FieldSummary, Text: IssueDocument(issue)}
+	if parts := splitChunkText(issue.Description); len(parts) > 0 {
+		summary.Body = parts[0]
+	}
+	chunks := []Chunk{summary}
+
+	add := func(name, field string, commentID int64, text string) {
+		for i, body := range splitChunkText(text) {
+			key := id + ChunkSep + name
+			if i > 0 {
+				key += "." + strconv.Itoa(i+1)
+			}
+			chunks = append(chunks, Chunk{
+				Key:       key,
+				IssueID:   id,
+				Field:     field,
+				CommentID: commentID,
+				Body:      body,
+				Text:      strings.TrimSpace(title + "\n" + body),
+			})
+		}
+	}
+
+	if len([]rune(strings.TrimSpace(issue.Description))) > maxChunkRunes {
+		add(FieldDescription, FieldDescription, 0, issue.Description)
+	}
+	add(FieldDesign, FieldDesign, 0, issue.Design)
+	add(FieldAcceptanceCriteria, FieldAcceptanceCriteria, 0, issue.AcceptanceCriteria)
+	add(FieldNotes, FieldNotes, 0, issue.Notes)
+	for i, c := range issue.Comments {
+		if c == nil {
+			continue
+		}
+		cid := c.ID
+		if cid == 0 {
+			cid = int64(i + 1)
+		}
+		add(fmt.Sprintf("%s-%d", FieldComment, cid), FieldComment, cid, c.Text)
+	}
+	return chunks
+}
+
+// ChunksFromIssues returns the chunks of every issue, keyed by index key.
+func ChunksFromIssues(issues []model.Issue) map[string]Chunk {
+	chunks := make(map[string]Chunk, len(issues))
+	for _, issue := range issues {
+		for _, c := range IssueChunks(issue) {
+			chunks[c.Key] = c
+		}
+	}
+	return chunks
+}
+
+// ChunkDocuments returns the key->text map for SyncVectorIndex. The summary
+// chunks are keyed by issue ID, so it is a superset of DocumentsFromIssues.
+func ChunkDocuments(chunks map[string]Chunk) map[string]string {
+	docs := make(map[string]string, len(chunks))
+	for key, c := range chunks {
+		docs[key] = c.Text
+	}
+	return docs
+}
+
+// ChunkIssueID returns the issue an index key belongs to
+func ChunkIssueID(key string) string {
+	id, _, _ := strings.Cut(key, ChunkSep)
+	return id
+}
+
+// splitChunkText packs paragraphs into bodies of at most maxChunkRunes,
+// hard-splitting paragraphs that are longer on whitespace.
+func splitChunkText(text string) []string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+	var out []string
+	var cur []rune
+	flush := func() {
+		if s := strings.TrimSpace(string(cur)); s != "" {
+			out = append(out, s)
+		}
+		cur = cur[:0]
+	}
+	for _, para := range strings.Split(text, "\n\n") {
+		p := []rune(strings.TrimSpace(para))
+		if len(p) == 0 {
+			continue
+		}
+		if len(cur) > 0 && len(cur)+2+len(p) > maxChunkRunes {
+			flush()
+		}
+		for len(p) > maxChunkRunes {
+			cut := maxChunkRunes
+			for i := maxChunkRunes; i > maxChunkRunes/2; i-- {
+				if unicode.IsSpace(p[i]) {
+					cut = i
+					break
+				}
+			}
+			cur = append(cur, p[:cut]...)
+			flush()
+			p = []rune(strings.TrimSpace(string(p[cut:])))
+		}
+		if len(cur) > 0 {
+			cur = append(cur, '\n', '\n')
+		}
+		cur = append(cur, p...)
+	}
+	flush()
+	return out
+}
+
+// SearchIssuesTopK searches the chunk vectors and returns the k best issues,
+// each scored by its best chunk (max pooling) with SearchResult.Chunk set to
+// that chunk's key.
+func (idx *VectorIndex) SearchIssuesTopK(query []float32, k int) ([]SearchResult, error) {
+	if k <= 0 {
+		return nil, nil
+	}
+	size := idx.Size()
+	fetch := min(k*4, size)
+	for {
+		chunks, err := idx.SearchTopK(query, fetch)
+		if err != nil {
+			return nil, err
+		}
+		pooled := PoolChunkResults(chunks)
+		if len(pooled) >= k || fetch >= size {
+			if len(pooled) > k {
+				pooled = pooled[:k]
+			}
+			return pooled, nil
+		}
+		fetch = min(fetch*2, size)
+	}
+}
+
+// PoolChunkResults folds chunk results into one result per issue, keeping the
+// best-scoring chunk, ordered by score then issue ID.
+func PoolChunkResults(chunks []SearchResult) []SearchResult {
+	best := make(map[string]int, len(chunks))
+	var pooled []SearchResult
+	for _, r := range chunks {
+		issueID := ChunkIssueID(r.IssueID)
+		pr := SearchResult{IssueID: issueID, Score: r.Score, Chunk: r.IssueID}
+		if i, ok := best[issueID]; ok {
+			if r.Score > pooled[i].Score {
+				pooled[i] = pr
+			}
+			continue
+		}
+		best[issueID] = len(pooled)
+		pooled = append(pooled, pr)
+	}
+	sort.SliceStable(pooled, func(i, j int) bool {
+		if pooled[i].Score != pooled[j].Score {
+			return pooled[i].Score > pooled[j].Score
+		}
+		return pooled[i].IssueID < pooled[j].IssueID
+	})
+	return pooled
+}
+
+// IssueScore returns the best chunk score of one issue against query.
+func (idx *VectorIndex) IssueScore(issueID string, query []float32) (SearchResult, bool) {
+	ids := idx.sortedIDs()
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	var best SearchResult
+	found := false
+	consider := func(key string) {
+		entry, ok := idx.entries[key]
+		if !ok {
+			return
+		}
+		if s := dotFloat32(query, entry.Vector); !found || s > best.Score {
+			best, found = SearchResult{IssueID: issueID, Score: s, Chunk: key}, true
+		}
+	}
+	consider(issueID)
+	// Chunk keys share the "<id>#" prefix, so they are contiguous when sorted.
+	prefix := issueID + ChunkSep
+	for i := sort.SearchStrings(ids, prefix); i < len(ids) && strings.HasPrefix(ids[i], prefix); i++ {
+		consider(ids[i])
+	}
+	return best, found
+}
+
+// Snippet returns a short single-line excerpt of body around the first query
+// term it contains, or its start when none match.
+func Snippet(body, query string, maxRunes int) string {
+	text := []rune(strings.Join(strings.Fields(body), " "))
+	if len(text) <= maxRunes {
+		return string(text)
+	}
+	lower := strings.ToLower(string(text))
+	start := 0
+	for _, term := range strings.Fields(strings.ToLower(query)) {
+		if i := strings.Index(lower, term); i >= 0 {
+			start = max(len([]rune(lower[:i]))-maxRunes/4, 0)
+			break
+		}
+	}
+	end := min(start+maxRunes, len(text))
+	start = max(end-maxRunes, 0)
+	out := string(text[start:end])
+	if start > 0 {
+		out = "…" + out
+	}
+	if end < len(text) {
+		out += "…"
+	}
+	return out
+}
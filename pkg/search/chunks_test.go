@@ -0,0 +1,99 @@
+package search
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func TestIssueChunks(t *testing.T) {
+	long := strings.Repeat("word ", 400) + "\n\n" + strings.Repeat("more ", 200)
+	issue := model.Issue{
+		ID:                 "bd-a3f8",
+		Title:              "Cache layer",
+		Description:        long,
+		Design:             "Use an LRU keyed by content hash.",
+		AcceptanceCriteria: "Hit rate above 90%",
+		Comments: []*model.Comment{
+			{ID: 17, Text: "What about eviction?"},
+			nil,
+			{Text: "No ID on this one"},
+		},
+	}
+
+	var got []string
+	for _, c := range IssueChunks(issue) {
+		got = append(got, c.Key+"/"+c.Field)
+		if c.Field != FieldSummary && !strings.HasPrefix(c.Text, "Cache layer\n") {
+			t.Errorf("chunk %s text should lead with the title: %q", c.Key, c.Text[:20])
+		}
+		if len([]rune(c.Body)) > maxChunkRunes {
+			t.Errorf("chunk %s body has %d runes", c.Key, len([]rune(c.Body)))
+		}
+	}
+	want := "bd-a3f8/summary bd-a3f8#description/description bd-a3f8#description.2/description " +
+		"bd-a3f8#design/design bd-a3f8#acceptance_criteria/acceptance_criteria " +
+		"bd-a3f8#comment-17/comment bd-a3f8#comment-3/comment"
+	if strings.Join(got, " ") != want {
+		t.Errorf("chunks =\n  %s\nwant\n  %s", strings.Join(got, " "), want)
+	}
+
+	short := IssueChunks(model.Issue{ID: "bd-1", Title: "t", Description: "short"})
+	if len(short) != 1 || short[0].Text != IssueDocument(model.Issue{ID: "bd-1", Title: "t", Description: "short"}) {
+		t.Errorf("short issue chunks = %+v, want the summary only", short)
+	}
+	if ChunkIssueID("bd-a3f8#comment-17") != "bd-a3f8" || ChunkIssueID("bd-a3f8") != "bd-a3f8" {
+		t.Error("ChunkIssueID")
+	}
+}
+
+func TestSearchIssuesTopKPoolsChunks(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "A", Title: "Login page", Description: "Fix the login form",
+			Comments: []*model.Comment{{ID: 5, Text: "zeppelin zeppelin zeppelin zeppelin"}}},
+		{ID: "B", Title: "Docs", Description: "Readme updates", Design: "zeppelin airship zeppelin"},
+		{ID: "C", Title: "Unrelated", Description: "Nothing here"},
+	}
+	embedder := NewHashEmbedder(256)
+	idx := NewVectorIndex(embedder.Dim())
+	chunks := ChunksFromIssues(issues)
+	if _, err := SyncVectorIndex(context.Background(), idx, embedder, ChunkDocuments(chunks), 0); err != nil {
+		t.Fatal(err)
+	}
+	if idx.Size() != 5 {
+		t.Fatalf("index size = %d, want 3 summaries + 1 comment + 1 design", idx.Size())
+	}
+
+	q, _ := embedder.Embed(context.Background(), []string{"zeppelin"})
+	results, err := idx.SearchIssuesTopK(q[0], 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || results[0].IssueID != "A" || results[0].Chunk != "A#comment-5" || results[1].Chunk != "B#design" {
+		t.Fatalf("results = %+v", results)
+	}
+	if c := chunks[results[0].Chunk]; c.Field != FieldComment || c.CommentID != 5 {
+		t.Errorf("chunk = %+v", c)
+	}
+
+	best, ok := idx.IssueScore("A", q[0])
+	if !ok || best.Chunk != "A#comment-5" || best.Score != results[0].Score {
+		t.Errorf("IssueScore = %+v, %v", best, ok)
+	}
+	if _, ok := idx.IssueScore("missing", q[0]); ok {
+		t.Error("IssueScore for a missing issue")
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	body := strings.Repeat("alpha ", 50) + "the needle is here " + strings.Repeat("omega ", 50)
+	s := Snippet(body, "Needle", 60)
+	if !strings.Contains(s, "needle") || !strings.HasPrefix(s, "…") || !strings.HasSuffix(s, "…") {
+		t.Errorf("snippet = %q", s)
+	}
+	if s := Snippet("short\n  text", "x", 60); s != "short text" {
+		t.Errorf("short snippet = %q", s)
+	}
+}
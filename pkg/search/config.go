@@ -6,6 +6,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // EmbeddingConfigFromEnv reads semantic embedding configuration from environment variables.
@@ -14,17 +15,33 @@ import (
 //   - BV_SEMANTIC_EMBEDDER: embedding provider (default: "hash")
 //   - BV_SEMANTIC_MODEL: model identifier (provider-specific, optional)
 //   - BV_SEMANTIC_DIM: embedding dimension (default: DefaultEmbeddingDim)
+//   - BV_SEMANTIC_COMMAND: external provider, command run as a stdio model server
+//   - BV_SEMANTIC_SOCKET: external provider, Unix socket of a running model server
+//   - BV_SEMANTIC_TIMEOUT: external provider, per-request timeout ("30s" or seconds)
 func EmbeddingConfigFromEnv() EmbeddingConfig {
 	provider := strings.ToLower(strings.TrimSpace(os.Getenv(EnvSemanticEmbedder)))
 	cfg := EmbeddingConfig{
 		Provider: Provider(provider),
 		Model:    strings.TrimSpace(os.Getenv(EnvSemanticModel)),
+		Command:  strings.TrimSpace(os.Getenv(EnvSemanticCommand)),
+		Socket:   strings.TrimSpace(os.Getenv(EnvSemanticSocket)),
 	}
 	if dimStr := os.Getenv(EnvSemanticDim); dimStr != "" {
 		if dim, err := strconv.Atoi(dimStr); err == nil {
 			cfg.Dim = dim
 		}
 	}
+	if raw := strings.TrimSpace(os.Getenv(EnvSemanticTimeout)); raw != "" {
+		if d, err := time.ParseDuration(raw); err == nil {
+			cfg.Timeout = d
+		} else if secs, err := strconv.ParseFloat(raw, 64); err == nil {
+			cfg.Timeout = time.Duration(secs * float64(time.Second))
+		}
+	}
+	// A command or socket on its own is enough to select the external provider.
+	if cfg.Provider == "" && (cfg.Command != "" || cfg.Socket != "") {
+		cfg.Provider = ProviderExternal
+	}
 	if cfg.Provider == "" {
 		cfg.Provider = ProviderHash
 	}
@@ -37,12 +54,14 @@ func NewEmbedderFromConfig(cfg EmbeddingConfig) (Embedder, error) {
 	switch cfg.Provider {
 	case "", ProviderHash:
 		return NewHashEmbedder(cfg.Dim), nil
+	case ProviderExternal:
+		return NewExternalEmbedder(cfg)
 	case ProviderPythonSentenceTransformers:
-		return nil, fmt.Errorf("semantic embedder %q not implemented (mvp placeholder); set %s=%q for deterministic fallback", cfg.Provider, EnvSemanticEmbedder, ProviderHash)
+		return nil, fmt.Errorf("semantic embedder %q not implemented (mvp placeholder); set %s=%q (with %s) for a local model server, or %q for deterministic fallback", cfg.Provider, EnvSemanticEmbedder, ProviderExternal, EnvSemanticCommand, ProviderHash)
 	case ProviderOpenAI:
-		return nil, fmt.Errorf("semantic embedder %q not implemented (placeholder); set %s=%q for deterministic fallback", cfg.Provider, EnvSemanticEmbedder, ProviderHash)
+		return nil, fmt.Errorf("semantic embedder %q not implemented (placeholder); set %s=%q (with %s) for a local model server, or %q for deterministic fallback", cfg.Provider, EnvSemanticEmbedder, ProviderExternal, EnvSemanticCommand, ProviderHash)
 	default:
-		return nil, fmt.Errorf("unknown semantic embedder %q; expected %q", cfg.Provider, ProviderHash)
+		return nil, fmt.Errorf("unknown semantic embedder %q; expected %q or %q", cfg.Provider, ProviderHash, ProviderExternal)
 	}
 }
 
@@ -1,6 +1,9 @@
 package search
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // Provider identifies an embedding backend.
 type Provider string
@@ -16,6 +19,11 @@ const (
 
 	// ProviderOpenAI uses a hosted embedding API.
 	ProviderOpenAI Provider = "openai"
+
+	// ProviderExternal talks to a local model server (ONNX, GGUF, ...) over the
+	// line-delimited JSON protocol in external_embedder.go, either as a stdio
+	// subprocess or through a Unix socket.
+	ProviderExternal Provider = "external"
 )
 
 const DefaultEmbeddingDim = 384
@@ -24,6 +32,11 @@ const (
 	EnvSemanticEmbedder = "BV_SEMANTIC_EMBEDDER"
 	EnvSemanticModel    = "BV_SEMANTIC_MODEL"
 	EnvSemanticDim      = "BV_SEMANTIC_DIM"
+
+	// External embedder transport (exactly one of command/socket) and per-request timeout.
+	EnvSemanticCommand = "BV_SEMANTIC_COMMAND"
+	EnvSemanticSocket  = "BV_SEMANTIC_SOCKET"
+	EnvSemanticTimeout = "BV_SEMANTIC_TIMEOUT"
 )
 
 // EmbeddingConfig captures embedder selection/configuration.
@@ -32,6 +45,12 @@ type EmbeddingConfig struct {
 	Provider Provider
 	Model    string
 	Dim      int
+
+	// External provider only: a shell command speaking the protocol on
+	// stdin/stdout, or the path of a Unix socket served by a running process.
+	Command string
+	Socket  string
+	Timeout time.Duration
 }
 
 func (c EmbeddingConfig) Normalized() EmbeddingConfig {
@@ -47,3 +66,17 @@ type Embedder interface {
 	Dim() int
 	Embed(ctx context.Context, texts []string) ([][]float32, error)
 }
+
+// ModelReporter is implemented by embedders whose vectors depend on a named
+// model. The vector index records the model so switching it forces a rebuild.
+type ModelReporter interface {
+	Model() string
+}
+
+// EmbedderModel returns the model name of e, or "" if it doesn't report one.
+func EmbedderModel(e Embedder) string {
+	if m, ok := e.(ModelReporter); ok {
+		return m.Model()
+	}
+	return ""
+}
@@ -0,0 +1,298 @@
+package search
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// External embedder protocol
+//
+// bv talks to a local model server with one JSON object per line in each
+// direction. Each request carries the texts of one SyncVectorIndex batch:
+//
+//	→ {"id":1,"model":"all-MiniLM-L6-v2","dim":384,"texts":["Fix login","..."]}
+//	← {"id":1,"model":"all-MiniLM-L6-v2","vectors":[[0.01,...],[...]]}
+//
+// or, on failure, {"id":1,"error":"text too long"}. Responses must echo the
+// request id and return one dim-sized vector per text, in order. The model in
+// the request is BV_SEMANTIC_MODEL and may be ignored by single-model servers.
+//
+// With BV_SEMANTIC_COMMAND the server is started as a subprocess and spoken to
+// over its stdin/stdout; with BV_SEMANTIC_SOCKET bv connects to a Unix socket.
+// A request that times out or breaks the connection is retried on a fresh
+// process/connection; an "error" reply is returned as-is.
+
+const (
+	// DefaultExternalTimeout bounds a single embed request
+	DefaultExternalTimeout = 30 * time.Second
+	// DefaultExternalRetries is how many times a failed request is retried
+	DefaultExternalRetries = 2
+
+	// stderrTailSize is how much subprocess stderr is kept for error messages
+	stderrTailSize = 2048
+)
+
+type embedRequest struct {
+	ID    uint64   `json:"id"`
+	Model string   `json:"model,omitempty"`
+	Dim   int      `json:"dim"`
+	Texts []string `json:"texts"`
+}
+
+type embedResponse struct {
+	ID      uint64      `json:"id"`
+	Model   string      `json:"model,omitempty"`
+	Vectors [][]float32 `json:"vectors"`
+	Error   string      `json:"error,omitempty"`
+}
+
+// serverError is an error reported by the model server; it is not retried.
+type serverError struct{ msg string }
+
+func (e *serverError) Error() string { return "external embedder: " + e.msg }
+
+// ExternalEmbedder produces vectors by calling a local model server.
+// It is safe for concurrent use; requests are serialized on one connection.
+type ExternalEmbedder struct {
+	cfg     EmbeddingConfig
+	retries int
+
+	mu     sync.Mutex
+	conn   *externalConn
+	nextID uint64
+}
+
+// externalConn is one live subprocess or socket connection
+type externalConn struct {
+	w      io.Writer
+	r      *bufio.Reader
+	close  func() error
+	stderr *tailBuffer // subprocess only
+}
+
+// NewExternalEmbedder validates cfg. The server is started (or dialled)
+// lazily on the first Embed call.
+func NewExternalEmbedder(cfg EmbeddingConfig) (*ExternalEmbedder, error) {
+	cfg = cfg.Normalized()
+	cfg.Provider = ProviderExternal
+	switch {
+	case cfg.Command == "" && cfg.Socket == "":
+		return nil, fmt.Errorf("semantic embedder %q needs %s or %s", ProviderExternal, EnvSemanticCommand, EnvSemanticSocket)
+	case cfg.Command != "" && cfg.Socket != "":
+		return nil, fmt.Errorf("semantic embedder %q: set only one of %s and %s", ProviderExternal, EnvSemanticCommand, EnvSemanticSocket)
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultExternalTimeout
+	}
+	return &ExternalEmbedder{cfg: cfg, retries: DefaultExternalRetries}, nil
+}
+
+func (*ExternalEmbedder) Provider() Provider { return ProviderExternal }
+func (e *ExternalEmbedder) Dim() int         { return e.cfg.Dim }
+func (e *ExternalEmbedder) Model() string    { return e.cfg.Model }
+
+// Close stops the subprocess or closes the socket connection.
+func (e *ExternalEmbedder) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.dropLocked()
+}
+
+func (e *ExternalEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return [][]float32{}, nil
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var lastErr error
+	for attempt := 0; attempt <= e.retries; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		vecs, err := e.requestLocked(ctx, texts)
+		if err == nil {
+			return vecs, nil
+		}
+		var se *serverError
+		if errors.As(err, &se) {
+			return nil, err
+		}
+		// Transport failure or timeout: start over on a fresh connection.
+		_ = e.dropLocked()
+		lastErr = err
+	}
+	return nil, fmt.Errorf("external embedder failed after %d attempts: %w", e.retries+1, lastErr)
+}
+
+// requestLocked sends one request and waits for its response.
+func (e *ExternalEmbedder) requestLocked(ctx context.Context, texts []string) ([][]float32, error) {
+	if e.conn == nil {
+		conn, err := e.connect()
+		if err != nil {
+			return nil, err
+		}
+		e.conn = conn
+	}
+	conn := e.conn
+
+	e.nextID++
+	req := embedRequest{ID: e.nextID, Model: e.cfg.Model, Dim: e.cfg.Dim, Texts: texts}
+	line, err := json.Marshal(req)
+	if err != nil {
+		return nil, &serverError{msg: fmt.Sprintf("encoding request: %v", err)}
+	}
+	line = append(line, '\n')
+
+	type result struct {
+		resp embedResponse
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		if _, err := conn.w.Write(line); err != nil {
+			done <- result{err: fmt.Errorf("write request: %w", err)}
+			return
+		}
+		raw, err := conn.r.ReadBytes('\n')
+		if err != nil {
+			done <- result{err: fmt.Errorf("read response: %w", err)}
+			return
+		}
+		var resp embedResponse
+		if err := json.Unmarshal(raw, &resp); err != nil {
+			done <- result{err: fmt.Errorf("decode response: %w", err)}
+			return
+		}
+		done <- result{resp: resp}
+	}()
+
+	timer := time.NewTimer(e.cfg.Timeout)
+	defer timer.Stop()
+
+	var res result
+	select {
+	case res = <-done:
+	case <-timer.C:
+		return nil, fmt.Errorf("request timed out after %s", e.cfg.Timeout)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if res.err != nil {
+		if conn.stderr != nil {
+			if tail := strings.TrimSpace(conn.stderr.String()); tail != "" {
+				return nil, fmt.Errorf("%w (stderr: %s)", res.err, tail)
+			}
+		}
+		return nil, res.err
+	}
+
+	resp := res.resp
+	if resp.ID != req.ID {
+		return nil, fmt.Errorf("response id %d does not match request id %d", resp.ID, req.ID)
+	}
+	if resp.Error != "" {
+		return nil, &serverError{msg: resp.Error}
+	}
+	if len(resp.Vectors) != len(texts) {
+		return nil, &serverError{msg: fmt.Sprintf("returned %d vectors for %d texts", len(resp.Vectors), len(texts))}
+	}
+	for i, vec := range resp.Vectors {
+		if len(vec) != e.cfg.Dim {
+			return nil, &serverError{msg: fmt.Sprintf("returned %d-dim vectors, expected %d (set %s)", len(vec), e.cfg.Dim, EnvSemanticDim)}
+		}
+		// Unit length so index scores are cosine similarities, as with the hash embedder.
+		normalizeL2(resp.Vectors[i])
+	}
+	return resp.Vectors, nil
+}
+
+// connect starts the subprocess or dials the socket.
+func (e *ExternalEmbedder) connect() (*externalConn, error) {
+	if e.cfg.Socket != "" {
+		c, err := net.DialTimeout("unix", e.cfg.Socket, e.cfg.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("connect to %s: %w", e.cfg.Socket, err)
+		}
+		return &externalConn{w: c, r: bufio.NewReader(c), close: c.Close}, nil
+	}
+
+	shell, flag := "sh", "-c"
+	if runtime.GOOS == "windows" {
+		shell, flag = "cmd", "/C"
+	}
+	cmd := exec.Command(shell, flag, e.cfg.Command)
+	cmd.Env = os.Environ()
+	stderr := &tailBuffer{max: stderrTailSize}
+	cmd.Stderr = stderr
+	// Don't let a grandchild holding stderr open stall Close.
+	cmd.WaitDelay = time.Second
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("start %q: %w", e.cfg.Command, err)
+	}
+	return &externalConn{
+		w:      stdin,
+		r:      bufio.NewReader(stdout),
+		stderr: stderr,
+		close: func() error {
+			_ = stdin.Close()
+			if cmd.Process != nil {
+				_ = cmd.Process.Kill()
+			}
+			_ = cmd.Wait()
+			return nil
+		},
+	}, nil
+}
+
+func (e *ExternalEmbedder) dropLocked() error {
+	if e.conn == nil {
+		return nil
+	}
+	err := e.conn.close()
+	e.conn = nil
+	return err
+}
+
+// tailBuffer keeps the last max bytes written to it
+type tailBuffer struct {
+	mu  sync.Mutex
+	max int
+	buf []byte
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf = append(b.buf, p...)
+	if over := len(b.buf) - b.max; over > 0 {
+		b.buf = b.buf[over:]
+	}
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return string(b.buf)
+}
@@ -0,0 +1,207 @@
+package search
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// serveEmbedProtocol answers embed requests with [len(text), 1, 0, ...]
+// vectors. Texts starting with "fail" get an error reply and texts starting
+// with "wide" get a vector one longer than asked for.
+func serveEmbedProtocol(r *bufio.Reader, w *bufio.Writer, onRequest func()) {
+	for {
+		line, err := r.ReadBytes('\n')
+		if err != nil {
+			return
+		}
+		if onRequest != nil {
+			onRequest()
+		}
+		var req embedRequest
+		_ = json.Unmarshal(line, &req)
+		resp := embedResponse{ID: req.ID, Model: req.Model}
+		for _, text := range req.Texts {
+			if strings.HasPrefix(text, "fail") {
+				resp = embedResponse{ID: req.ID, Error: "cannot embed " + text}
+				break
+			}
+			n := req.Dim
+			if strings.HasPrefix(text, "wide") {
+				n++
+			}
+			vec := make([]float32, n)
+			vec[0], vec[1] = float32(len(text)), 1
+			resp.Vectors = append(resp.Vectors, vec)
+		}
+		out, _ := json.Marshal(resp)
+		_, _ = w.Write(append(out, '\n'))
+		_ = w.Flush()
+	}
+}
+
+// TestExternalEmbedderHelperProcess is the model server started by the
+// stdio tests. With BV_TEST_EMBED_HANG_ONCE set to a path, the first process
+// to see no file there creates it and never answers.
+func TestExternalEmbedderHelperProcess(t *testing.T) {
+	if os.Getenv("BV_TEST_EMBED_SERVER") != "1" {
+		t.Skip("helper process")
+	}
+	var onRequest func()
+	if marker := os.Getenv("BV_TEST_EMBED_HANG_ONCE"); marker != "" {
+		onRequest = func() {
+			if _, err := os.Stat(marker); os.IsNotExist(err) {
+				_ = os.WriteFile(marker, nil, 0o644)
+				time.Sleep(time.Minute)
+			}
+		}
+	}
+	serveEmbedProtocol(bufio.NewReader(os.Stdin), bufio.NewWriter(os.Stdout), onRequest)
+	os.Exit(0)
+}
+
+func helperCommand(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	t.Setenv("BV_TEST_EMBED_SERVER", "1")
+	return fmt.Sprintf("exec '%s' -test.run='^TestExternalEmbedderHelperProcess$'", os.Args[0])
+}
+
+func TestExternalEmbedderStdio(t *testing.T) {
+	e, err := NewEmbedderFromConfig(EmbeddingConfig{Provider: ProviderExternal, Model: "mini", Dim: 4, Command: helperCommand(t)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.(*ExternalEmbedder).Close()
+
+	vecs, err := e.Embed(context.Background(), []string{"abc", "abcd"})
+	if err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+	if len(vecs) != 2 || len(vecs[0]) != 4 {
+		t.Fatalf("vectors = %v", vecs)
+	}
+	// [3,1,0,0] normalised
+	if got := vecs[0][0]*vecs[0][0] + vecs[0][1]*vecs[0][1]; got < 0.999 || got > 1.001 || vecs[0][0] < vecs[0][1] {
+		t.Errorf("vector not unit length: %v", vecs[0])
+	}
+
+	// Server errors are reported, not retried, and the server keeps running.
+	if _, err := e.Embed(context.Background(), []string{"fail me"}); err == nil || !strings.Contains(err.Error(), "cannot embed fail me") {
+		t.Errorf("error reply = %v", err)
+	}
+	if _, err := e.Embed(context.Background(), []string{"ok"}); err != nil {
+		t.Errorf("after error reply: %v", err)
+	}
+}
+
+func TestExternalEmbedderRetriesAfterTimeout(t *testing.T) {
+	command := helperCommand(t)
+	marker := filepath.Join(t.TempDir(), "hung")
+	t.Setenv("BV_TEST_EMBED_HANG_ONCE", marker)
+
+	e, err := NewExternalEmbedder(EmbeddingConfig{Dim: 4, Command: command, Timeout: 2 * time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+
+	vecs, err := e.Embed(context.Background(), []string{"abc"})
+	if err != nil {
+		t.Fatalf("Embed should succeed on a restarted server: %v", err)
+	}
+	if _, statErr := os.Stat(marker); statErr != nil || len(vecs) != 1 {
+		t.Errorf("first server should have hung: %v, %v", statErr, vecs)
+	}
+}
+
+func TestExternalEmbedderSocket(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix sockets")
+	}
+	dir, err := os.MkdirTemp("", "bvsock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	sock := filepath.Join(dir, "embed.sock")
+	ln, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer c.Close()
+				serveEmbedProtocol(bufio.NewReader(c), bufio.NewWriter(c), nil)
+			}()
+		}
+	}()
+
+	e, err := NewExternalEmbedder(EmbeddingConfig{Dim: 8, Socket: sock})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+
+	idx := NewVectorIndex(8)
+	docs := map[string]string{"A": "short", "B": "a much longer text"}
+	stats, err := SyncVectorIndex(context.Background(), idx, e, docs, 1)
+	if err != nil {
+		t.Fatalf("SyncVectorIndex: %v", err)
+	}
+	if stats.Embedded != 2 || idx.Provider != ProviderExternal {
+		t.Errorf("stats = %+v, provider = %q", stats, idx.Provider)
+	}
+
+	// A server returning the wrong dim is an error, not a silent mismatch.
+	if _, err := e.Embed(context.Background(), []string{"wide"}); err == nil || !strings.Contains(err.Error(), "9-dim vectors, expected 8") {
+		t.Errorf("wrong dim: %v", err)
+	}
+}
+
+func TestNewExternalEmbedderValidation(t *testing.T) {
+	if _, err := NewExternalEmbedder(EmbeddingConfig{}); err == nil || !strings.Contains(err.Error(), EnvSemanticCommand) {
+		t.Errorf("missing transport: %v", err)
+	}
+	if _, err := NewExternalEmbedder(EmbeddingConfig{Command: "x", Socket: "y"}); err == nil {
+		t.Error("expected an error for both command and socket")
+	}
+	e, err := NewExternalEmbedder(EmbeddingConfig{Socket: "/nonexistent/bv.sock", Timeout: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.Embed(context.Background(), []string{"x"}); err == nil || !strings.Contains(err.Error(), "3 attempts") {
+		t.Errorf("unreachable socket: %v", err)
+	}
+}
+
+func TestEmbeddingConfigFromEnvExternal(t *testing.T) {
+	t.Setenv(EnvSemanticEmbedder, "")
+	t.Setenv(EnvSemanticSocket, "")
+	t.Setenv(EnvSemanticCommand, "model-server --stdio")
+	t.Setenv(EnvSemanticTimeout, "5")
+	cfg := EmbeddingConfigFromEnv()
+	if cfg.Provider != ProviderExternal || cfg.Command != "model-server --stdio" || cfg.Timeout != 5*time.Second {
+		t.Errorf("cfg = %+v", cfg)
+	}
+	t.Setenv(EnvSemanticTimeout, "1500ms")
+	if cfg := EmbeddingConfigFromEnv(); cfg.Timeout != 1500*time.Millisecond {
+		t.Errorf("timeout = %v", cfg.Timeout)
+	}
+}
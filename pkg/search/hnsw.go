@@ -0,0 +1,277 @@
+package search
+
+import (
+	"container/heap"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// hnswGraph is a Hierarchical Navigable Small World graph (Malkov & Yashunin)
+// over the index vectors, scored by dot product like the flat scan.
+//
+// Nodes are never unlinked: a removed or re-embedded issue leaves a tombstone
+// that is still traversed but never returned. The index compacts the graph by
+// rebuilding it once tombstones make up a quarter of the nodes.
+const (
+	hnswM              = 16 // links per node above layer 0 (2*M on layer 0)
+	hnswEfConstruction = 96
+	hnswEfSearch       = 64
+	hnswSeed           = 1 // fixed so builds are reproducible
+)
+
+type hnswNode struct {
+	id      string
+	vec     []float32
+	links   [][]int32 // neighbours per layer, len = node level + 1
+	deleted bool
+}
+
+type hnswGraph struct {
+	nodes    []hnswNode
+	byID     map[string]int32 // live node for each issue
+	entry    int32            // -1 when empty
+	maxLevel int
+	deleted  int
+	rng      *rand.Rand
+	levelMul float64
+}
+
+func newHNSW() *hnswGraph {
+	return &hnswGraph{
+		byID:     make(map[string]int32),
+		entry:    -1,
+		rng:      rand.New(rand.NewSource(hnswSeed)),
+		levelMul: 1 / math.Log(hnswM),
+	}
+}
+
+func (g *hnswGraph) live() int { return len(g.nodes) - g.deleted }
+
+// needsCompaction reports whether tombstones make up a quarter of the graph
+func (g *hnswGraph) needsCompaction() bool {
+	return g.deleted > 0 && g.deleted*4 >= len(g.nodes)
+}
+
+func (g *hnswGraph) maxLinks(level int) int {
+	if level == 0 {
+		return 2 * hnswM
+	}
+	return hnswM
+}
+
+func (g *hnswGraph) randomLevel() int {
+	return int(math.Floor(-math.Log(1-g.rng.Float64()) * g.levelMul))
+}
+
+func (g *hnswGraph) remove(id string) {
+	n, ok := g.byID[id]
+	if !ok {
+		return
+	}
+	delete(g.byID, id)
+	g.nodes[n].deleted = true
+	g.deleted++
+}
+
+// insert adds vec for id, tombstoning any previous vector for it.
+func (g *hnswGraph) insert(id string, vec []float32) {
+	g.remove(id)
+
+	level := g.randomLevel()
+	n := int32(len(g.nodes))
+	g.nodes = append(g.nodes, hnswNode{id: id, vec: vec, links: make([][]int32, level+1)})
+	g.byID[id] = n
+	if g.entry < 0 {
+		g.entry, g.maxLevel = n, level
+		return
+	}
+
+	ep := g.entry
+	for l := g.maxLevel; l > level; l-- {
+		ep = g.greedy(vec, ep, l)
+	}
+	for l := min(level, g.maxLevel); l >= 0; l-- {
+		cands := g.searchLayer(vec, ep, hnswEfConstruction, l, true)
+		if len(cands) == 0 {
+			continue
+		}
+		neighbours := g.selectNeighbours(cands, g.maxLinks(l))
+		g.nodes[n].links[l] = neighbours
+		for _, nb := range neighbours {
+			g.link(nb, n, l)
+		}
+		ep = cands[0].node
+	}
+	if level > g.maxLevel {
+		g.entry, g.maxLevel = n, level
+	}
+}
+
+// link adds to as a neighbour of from on layer l, pruning from's list if full.
+func (g *hnswGraph) link(from, to int32, l int) {
+	links := append(g.nodes[from].links[l], to)
+	if len(links) > g.maxLinks(l) {
+		vec := g.nodes[from].vec
+		cands := make([]hnswScored, len(links))
+		for i, nb := range links {
+			cands[i] = hnswScored{node: nb, sim: dotFloat32(vec, g.nodes[nb].vec)}
+		}
+		sortScored(cands)
+		links = g.selectNeighbours(cands, g.maxLinks(l))
+	}
+	g.nodes[from].links[l] = links
+}
+
+// selectNeighbours picks up to m of cands (sorted best first), preferring
+// candidates closer to the query than to any already picked, which keeps
+// links spread across clusters. Remaining slots go to the closest skipped.
+func (g *hnswGraph) selectNeighbours(cands []hnswScored, m int) []int32 {
+	selected := make([]int32, 0, m)
+	var skipped []int32
+	for _, c := range cands {
+		if len(selected) >= m {
+			break
+		}
+		diverse := true
+		for _, s := range selected {
+			if dotFloat32(g.nodes[c.node].vec, g.nodes[s].vec) > c.sim {
+				diverse = false
+				break
+			}
+		}
+		if diverse {
+			selected = append(selected, c.node)
+		} else {
+			skipped = append(skipped, c.node)
+		}
+	}
+	for _, s := range skipped {
+		if len(selected) >= m {
+			break
+		}
+		selected = append(selected, s)
+	}
+	return selected
+}
+
+// greedy walks layer l towards q and returns the best node reached
+func (g *hnswGraph) greedy(q []float32, ep int32, l int) int32 {
+	best := dotFloat32(q, g.nodes[ep].vec)
+	for changed := true; changed; {
+		changed = false
+		for _, nb := range g.nodes[ep].links[l] {
+			if s := dotFloat32(q, g.nodes[nb].vec); s > best {
+				best, ep, changed = s, nb, true
+			}
+		}
+	}
+	return ep
+}
+
+// searchLayer returns up to ef nodes of layer l closest to q, best first.
+// Tombstones are traversed but only returned when withDeleted is set.
+func (g *hnswGraph) searchLayer(q []float32, ep int32, ef int, l int, withDeleted bool) []hnswScored {
+	visited := make(map[int32]struct{}, ef*4)
+	visited[ep] = struct{}{}
+	start := hnswScored{node: ep, sim: dotFloat32(q, g.nodes[ep].vec)}
+
+	candidates := &scoredMaxHeap{start}
+	results := &scoredMinHeap{}
+	if withDeleted || !g.nodes[ep].deleted {
+		heap.Push(results, start)
+	}
+
+	for candidates.Len() > 0 {
+		c := heap.Pop(candidates).(hnswScored)
+		if results.Len() >= ef && c.sim < (*results)[0].sim {
+			break
+		}
+		for _, nb := range g.nodes[c.node].links[l] {
+			if _, seen := visited[nb]; seen {
+				continue
+			}
+			visited[nb] = struct{}{}
+			s := hnswScored{node: nb, sim: dotFloat32(q, g.nodes[nb].vec)}
+			if results.Len() < ef || s.sim > (*results)[0].sim {
+				heap.Push(candidates, s)
+				if withDeleted || !g.nodes[nb].deleted {
+					heap.Push(results, s)
+					if results.Len() > ef {
+						heap.Pop(results)
+					}
+				}
+			}
+		}
+	}
+
+	out := []hnswScored(*results)
+	sortScored(out)
+	return out
+}
+
+func (g *hnswGraph) search(q []float32, k int) []SearchResult {
+	if g.entry < 0 || g.live() == 0 {
+		return nil
+	}
+	ep := g.entry
+	for l := g.maxLevel; l > 0; l-- {
+		ep = g.greedy(q, ep, l)
+	}
+	found := g.searchLayer(q, ep, max(hnswEfSearch, k), 0, false)
+
+	results := make([]SearchResult, len(found))
+	for i, f := range found {
+		results[i] = SearchResult{IssueID: g.nodes[f.node].id, Score: f.sim}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].IssueID < results[j].IssueID
+	})
+	if len(results) > k {
+		results = results[:k]
+	}
+	return results
+}
+
+type hnswScored struct {
+	node int32
+	sim  float64
+}
+
+func sortScored(s []hnswScored) {
+	sort.Slice(s, func(i, j int) bool {
+		if s[i].sim != s[j].sim {
+			return s[i].sim > s[j].sim
+		}
+		return s[i].node < s[j].node
+	})
+}
+
+type scoredMaxHeap []hnswScored
+
+func (h scoredMaxHeap) Len() int           { return len(h) }
+func (h scoredMaxHeap) Less(i, j int) bool { return h[i].sim > h[j].sim }
+func (h scoredMaxHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *scoredMaxHeap) Push(x any)        { *h = append(*h, x.(hnswScored)) }
+func (h *scoredMaxHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+type scoredMinHeap []hnswScored
+
+func (h scoredMinHeap) Len() int           { return len(h) }
+func (h scoredMinHeap) Less(i, j int) bool { return h[i].sim < h[j].sim }
+func (h scoredMinHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *scoredMinHeap) Push(x any)        { *h = append(*h, x.(hnswScored)) }
+func (h *scoredMinHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
@@ -0,0 +1,192 @@
+package search
+
+import (
+	"fmt"
+	"math/rand"
+	"path/filepath"
+	"testing"
+)
+
+// clusteredVectors returns n unit vectors around a few random centres, which
+// is closer to real embeddings than uniform noise.
+func clusteredVectors(n, dim int, seed int64) [][]float32 {
+	rng := rand.New(rand.NewSource(seed))
+	centres := make([][]float32, 32)
+	for i := range centres {
+		centres[i] = make([]float32, dim)
+		for j := range centres[i] {
+			centres[i][j] = float32(rng.NormFloat64())
+		}
+	}
+	out := make([][]float32, n)
+	for i := range out {
+		c := centres[rng.Intn(len(centres))]
+		vec := make([]float32, dim)
+		for j := range vec {
+			vec[j] = c[j] + 0.6*float32(rng.NormFloat64())
+		}
+		normalizeL2(vec)
+		out[i] = vec
+	}
+	return out
+}
+
+// buildANNIndex fills an index with vecs and builds its graph
+func buildANNIndex(tb testing.TB, vecs [][]float32, threshold int) *VectorIndex {
+	tb.Helper()
+	idx := NewVectorIndex(len(vecs[0]))
+	idx.ANNThreshold = threshold
+	for i, vec := range vecs {
+		id := fmt.Sprintf("bd-%05d", i)
+		if err := idx.Upsert(id, ComputeContentHash(id), vec); err != nil {
+			tb.Fatal(err)
+		}
+	}
+	idx.RefreshANN()
+	return idx
+}
+
+// annRecall is the fraction of the flat top-k that the graph also returns
+func annRecall(tb testing.TB, idx *VectorIndex, queries [][]float32, k int) float64 {
+	tb.Helper()
+	threshold := idx.ANNThreshold
+	defer func() { idx.ANNThreshold = threshold }()
+
+	var hits, total int
+	for _, q := range queries {
+		idx.ANNThreshold = threshold
+		approx, err := idx.SearchTopK(q, k)
+		if err != nil {
+			tb.Fatal(err)
+		}
+		idx.ANNThreshold = -1
+		exact, err := idx.SearchTopK(q, k)
+		if err != nil {
+			tb.Fatal(err)
+		}
+		found := make(map[string]bool, len(approx))
+		for _, r := range approx {
+			found[r.IssueID] = true
+		}
+		for _, r := range exact {
+			if found[r.IssueID] {
+				hits++
+			}
+			total++
+		}
+	}
+	return float64(hits) / float64(total)
+}
+
+func TestVectorIndex_ANNRecall(t *testing.T) {
+	vecs := clusteredVectors(3100, 32, 7)
+	vecs, queries := vecs[:3000], vecs[3000:]
+	idx := buildANNIndex(t, vecs, 1000)
+	if !idx.ANNActive() {
+		t.Fatal("expected the graph to be used above the threshold")
+	}
+	if recall := annRecall(t, idx, queries, 10); recall < 0.95 {
+		t.Errorf("recall@10 = %.3f, want >= 0.95", recall)
+	}
+
+	small := buildANNIndex(t, vecs[:500], 1000)
+	if small.ANNActive() {
+		t.Error("expected a flat scan below the threshold")
+	}
+}
+
+func TestVectorIndex_ANNIncrementalAndPersistence(t *testing.T) {
+	vecs := clusteredVectors(1250, 16, 3)
+	vecs, queries := vecs[:1200], vecs[1200:]
+	idx := buildANNIndex(t, vecs, 1000)
+
+	// Re-embed one issue towards a far-away point and remove another.
+	target := make([]float32, 16)
+	target[0] = 1
+	if err := idx.Upsert("bd-00010", ComputeContentHash("moved"), target); err != nil {
+		t.Fatal(err)
+	}
+	idx.Remove("bd-00011")
+	if !idx.ANNActive() {
+		t.Fatal("graph should stay current across upsert and remove")
+	}
+
+	results, err := idx.SearchTopK(target, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].IssueID != "bd-00010" || results[0].Score < 0.999 {
+		t.Errorf("top result = %+v, want the re-embedded issue", results[0])
+	}
+	for _, q := range [][]float32{vecs[11], target} {
+		results, _ := idx.SearchTopK(q, 20)
+		for _, r := range results {
+			if r.IssueID == "bd-00011" {
+				t.Fatal("removed issue returned")
+			}
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "index.bvvi")
+	if err := idx.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadVectorIndex(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded.ANNThreshold = 1000
+	if !loaded.ANNActive() || loaded.ann.deleted != 0 {
+		t.Fatalf("loaded graph: active=%v", loaded.ANNActive())
+	}
+	if loaded.RefreshANN() {
+		t.Error("a loaded graph should not be rebuilt")
+	}
+	results, _ = loaded.SearchTopK(target, 1)
+	if results[0].IssueID != "bd-00010" {
+		t.Errorf("loaded top result = %+v", results[0])
+	}
+	if recall := annRecall(t, loaded, queries, 10); recall < 0.95 {
+		t.Errorf("loaded recall@10 = %.3f", recall)
+	}
+
+	// Enough removals compact the graph; dropping below the threshold drops it.
+	for i := 0; i < 320; i++ {
+		loaded.Remove(fmt.Sprintf("bd-%05d", 100+i))
+	}
+	loaded.ANNThreshold = 800
+	if !loaded.RefreshANN() || loaded.ann.deleted != 0 {
+		t.Error("expected compaction once a quarter of nodes are tombstones")
+	}
+	loaded.ANNThreshold = 5000
+	if loaded.RefreshANN() || loaded.ANNActive() {
+		t.Error("graph should be dropped below the threshold")
+	}
+}
+
+func BenchmarkVectorIndexSearchTopK(b *testing.B) {
+	const n, dim, k = 20000, 64, 10
+	vecs := clusteredVectors(n+200, dim, 11)
+	vecs, queries := vecs[:n], vecs[n:]
+	idx := buildANNIndex(b, vecs, 1)
+
+	for _, mode := range []struct {
+		name      string
+		threshold int
+	}{{"flat", -1}, {"hnsw", 1}} {
+		b.Run(mode.name, func(b *testing.B) {
+			idx.ANNThreshold = mode.threshold
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := idx.SearchTopK(queries[i%len(queries)], k); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.StopTimer()
+			if mode.threshold > 0 {
+				b.ReportMetric(annRecall(b, idx, queries, k), "recall@10")
+			}
+		})
+	}
+}
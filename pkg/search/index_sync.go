@@ -29,10 +29,16 @@ type IndexSyncStats struct {
 	Removed  int `json:"removed"`
 	Skipped  int `json:"skipped"`
 	Embedded int `json:"embedded"`
+	// Rebuilt is set when the index was built by a different provider, model
+	// or dim and its vectors were discarded.
+	Rebuilt bool `json:"rebuilt"`
+	// ANNBuilt is set when the approximate-nearest-neighbour graph was built
+	// or compacted (see (*VectorIndex).RefreshANN).
+	ANNBuilt bool `json:"ann_built"`
 }
 
 func (s IndexSyncStats) Changed() bool {
-	return s.Added+s.Updated+s.Removed > 0
+	return s.Rebuilt || s.ANNBuilt || s.Added+s.Updated+s.Removed > 0
 }
 
 // LoadOrNewVectorIndex loads an existing vector index if present, otherwise creates a new one.
@@ -65,8 +71,9 @@ func LoadOrNewVectorIndex(path string, dim int) (*VectorIndex, bool, error) {
 
 // SyncVectorIndex updates idx to match docs using embedder, incrementally embedding only changed items.
 //
-// This is intended for offline, deterministic embedding providers. Callers should persist idx
-// with (*VectorIndex).Save when desired.
+// The index is stamped with the embedder's provider and model. If it was built by a different
+// provider, model or dim, its vectors are discarded and everything is re-embedded rather than
+// mixing embedding spaces. Callers should persist idx with (*VectorIndex).Save when desired.
 func SyncVectorIndex(ctx context.Context, idx *VectorIndex, embedder Embedder, docs map[string]string, batchSize int) (IndexSyncStats, error) {
 	var stats IndexSyncStats
 	if idx == nil {
@@ -75,9 +82,12 @@ func SyncVectorIndex(ctx context.Context, idx *VectorIndex, embedder Embedder, d
 	if embedder == nil {
 		return stats, fmt.Errorf("embedder cannot be nil")
 	}
-	if idx.Dim != embedder.Dim() {
-		return stats, fmt.Errorf("index dim %d does not match embedder dim %d", idx.Dim, embedder.Dim())
+	provider, model := embedder.Provider(), EmbedderModel(embedder)
+	if idx.Dim != embedder.Dim() || (idx.Provider != "" && (idx.Provider != provider || idx.Model != model)) {
+		idx.Reset(embedder.Dim())
+		stats.Rebuilt = true
 	}
+	idx.Provider, idx.Model = provider, model
 	if batchSize <= 0 {
 		batchSize = 32
 	}
@@ -152,5 +162,6 @@ func SyncVectorIndex(ctx context.Context, idx *VectorIndex, embedder Embedder, d
 		}
 	}
 
+	stats.ANNBuilt = idx.RefreshANN()
 	return stats, nil
 }
@@ -94,3 +94,53 @@ func TestLoadOrNewVectorIndex(t *testing.T) {
 		t.Fatalf("expected 1 entry, got %d", loadedIdx.Size())
 	}
 }
+
+// modelEmbedder is a hash embedder that reports a model name
+type modelEmbedder struct {
+	*HashEmbedder
+	model string
+}
+
+func (m modelEmbedder) Model() string { return m.model }
+
+func TestSyncVectorIndex_RebuildsOnModelSwitch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.bvvi")
+	docs := map[string]string{"A": "Fix login flow", "B": "Update docs"}
+
+	idx := NewVectorIndex(8)
+	if _, err := SyncVectorIndex(context.Background(), idx, modelEmbedder{NewHashEmbedder(8), "mini"}, docs, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadVectorIndex(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Provider != ProviderHash || loaded.Model != "mini" {
+		t.Fatalf("header = %q/%q, want hash/mini", loaded.Provider, loaded.Model)
+	}
+
+	// Same model: nothing to do.
+	stats, err := SyncVectorIndex(context.Background(), loaded, modelEmbedder{NewHashEmbedder(8), "mini"}, docs, 0)
+	if err != nil || stats.Rebuilt || stats.Embedded != 0 {
+		t.Fatalf("same model stats = %+v, %v", stats, err)
+	}
+
+	// Different model: every vector is re-embedded.
+	stats, err = SyncVectorIndex(context.Background(), loaded, modelEmbedder{NewHashEmbedder(8), "large"}, docs, 0)
+	if err != nil || !stats.Rebuilt || stats.Embedded != 2 || !stats.Changed() {
+		t.Fatalf("model switch stats = %+v, %v", stats, err)
+	}
+	if loaded.Model != "large" {
+		t.Errorf("model = %q", loaded.Model)
+	}
+
+	// Different dim: rebuilt at the new dim instead of failing.
+	stats, err = SyncVectorIndex(context.Background(), loaded, NewHashEmbedder(16), docs, 0)
+	if err != nil || !stats.Rebuilt || loaded.Dim != 16 || loaded.Model != "" {
+		t.Fatalf("dim switch stats = %+v, dim = %d, %v", stats, loaded.Dim, err)
+	}
+}
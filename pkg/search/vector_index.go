@@ -15,11 +15,19 @@ import (
 	"sync"
 )
 
+// Index file layout: magic, version, reserved, dim, count, then (version 2+)
+// the provider and model as length-prefixed strings, then the entries, then
+// (version 3+) the optional HNSW graph with nodes numbered in entry order.
+// Older files still load; a missing graph is rebuilt by SyncVectorIndex.
 const (
 	vectorIndexMagic   = "BVVI"
-	vectorIndexVersion = uint16(1)
+	vectorIndexVersion = uint16(3)
 )
 
+// DefaultANNThreshold is the index size from which SearchTopK uses the
+// approximate (HNSW) graph instead of scanning every vector.
+const DefaultANNThreshold = 4096
+
 type ContentHash [32]byte
 
 func ComputeContentHash(text string) ContentHash {
@@ -50,11 +58,18 @@ type VectorEntry struct {
 
 type VectorIndex struct {
 	Dim int
+	// Provider and Model identify the embedding space the vectors live in.
+	// Empty when unknown (a fresh index or a version 1 file).
+	Provider Provider
+	Model    string
+	// ANNThreshold overrides DefaultANNThreshold; negative disables the graph.
+	ANNThreshold int
 
 	mu       sync.RWMutex
 	entries  map[string]VectorEntry
 	idsCache []string
 	idsDirty bool
+	ann      *hnswGraph // nil until the index reaches the ANN threshold
 }
 
 func NewVectorIndex(dim int) *VectorIndex {
@@ -89,7 +104,7 @@ func LoadVectorIndex(path string) (*VectorIndex, error) {
 	if err := binary.Read(r, binary.LittleEndian, &version); err != nil {
 		return nil, fmt.Errorf("read version: %w", err)
 	}
-	if version != vectorIndexVersion {
+	if version == 0 || version > vectorIndexVersion {
 		return nil, fmt.Errorf("unsupported version %d", version)
 	}
 
@@ -112,6 +127,18 @@ func LoadVectorIndex(path string) (*VectorIndex, error) {
 	}
 
 	idx := NewVectorIndex(int(dimU32))
+	order := make([]string, 0, count)
+	if version >= 2 {
+		provider, err := readIndexString(r)
+		if err != nil {
+			return nil, fmt.Errorf("read provider: %w", err)
+		}
+		model, err := readIndexString(r)
+		if err != nil {
+			return nil, fmt.Errorf("read model: %w", err)
+		}
+		idx.Provider, idx.Model = Provider(provider), model
+	}
 	for i := uint32(0); i < count; i++ {
 		var idLen uint16
 		if err := binary.Read(r, binary.LittleEndian, &idLen); err != nil {
@@ -144,11 +171,108 @@ func LoadVectorIndex(path string) (*VectorIndex, error) {
 		if err := idx.Upsert(issueID, ch, vec); err != nil {
 			return nil, err
 		}
+		order = append(order, issueID)
+	}
+
+	if version >= 3 {
+		ann, err := readHNSW(r, order, idx.entries)
+		if err != nil {
+			return nil, fmt.Errorf("read ann graph: %w", err)
+		}
+		idx.ann = ann
 	}
 
 	return idx, nil
 }
 
+// readHNSW reads the graph section: a presence byte, then the level and the
+// per-layer neighbour ordinals of every entry, in entry order.
+func readHNSW(r io.Reader, order []string, entries map[string]VectorEntry) (*hnswGraph, error) {
+	var present uint8
+	if err := binary.Read(r, binary.LittleEndian, &present); err != nil {
+		return nil, err
+	}
+	if present == 0 {
+		return nil, nil
+	}
+	g := newHNSW()
+	g.nodes = make([]hnswNode, len(order))
+	for i, id := range order {
+		var level uint8
+		if err := binary.Read(r, binary.LittleEndian, &level); err != nil {
+			return nil, err
+		}
+		node := hnswNode{id: id, vec: entries[id].Vector, links: make([][]int32, int(level)+1)}
+		for l := range node.links {
+			var n uint16
+			if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+				return nil, err
+			}
+			links := make([]int32, n)
+			for j := range links {
+				var ord uint32
+				if err := binary.Read(r, binary.LittleEndian, &ord); err != nil {
+					return nil, err
+				}
+				if int(ord) >= len(order) {
+					return nil, fmt.Errorf("neighbour %d out of range", ord)
+				}
+				links[j] = int32(ord)
+			}
+			node.links[l] = links
+		}
+		g.nodes[i] = node
+		g.byID[id] = int32(i)
+		if int(level) > g.maxLevel || g.entry < 0 {
+			g.entry, g.maxLevel = int32(i), int(level)
+		}
+	}
+	return g, nil
+}
+
+// writeHNSW writes the graph section for the entries in ids. Tombstones are
+// dropped; their links are filtered out and the entry point is re-picked.
+func writeHNSW(w io.Writer, g *hnswGraph, ids []string) error {
+	if g == nil || g.live() != len(ids) {
+		return binary.Write(w, binary.LittleEndian, uint8(0))
+	}
+	ordinal := make(map[int32]uint32, len(ids))
+	for i, id := range ids {
+		n, ok := g.byID[id]
+		if !ok {
+			return binary.Write(w, binary.LittleEndian, uint8(0))
+		}
+		ordinal[n] = uint32(i)
+	}
+	if err := binary.Write(w, binary.LittleEndian, uint8(1)); err != nil {
+		return err
+	}
+	for _, id := range ids {
+		node := g.nodes[g.byID[id]]
+		if len(node.links) > math.MaxUint8 {
+			return fmt.Errorf("ann level too high: %d", len(node.links)-1)
+		}
+		if err := binary.Write(w, binary.LittleEndian, uint8(len(node.links)-1)); err != nil {
+			return err
+		}
+		for _, links := range node.links {
+			live := make([]uint32, 0, len(links))
+			for _, nb := range links {
+				if ord, ok := ordinal[nb]; ok {
+					live = append(live, ord)
+				}
+			}
+			if err := binary.Write(w, binary.LittleEndian, uint16(len(live))); err != nil {
+				return err
+			}
+			if err := binary.Write(w, binary.LittleEndian, live); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func (idx *VectorIndex) Save(path string) error {
 	// Acquire sorted IDs before locking to avoid deadlock (sortedIDs needs Write lock if dirty)
 	ids := idx.sortedIDs()
@@ -189,6 +313,12 @@ func (idx *VectorIndex) Save(path string) error {
 	if err := binary.Write(w, binary.LittleEndian, uint32(len(ids))); err != nil {
 		return fmt.Errorf("write count: %w", err)
 	}
+	if err := writeIndexString(w, string(idx.Provider)); err != nil {
+		return fmt.Errorf("write provider: %w", err)
+	}
+	if err := writeIndexString(w, idx.Model); err != nil {
+		return fmt.Errorf("write model: %w", err)
+	}
 
 	for _, issueID := range ids {
 		entry, ok := idx.entries[issueID]
@@ -218,6 +348,10 @@ func (idx *VectorIndex) Save(path string) error {
 		}
 	}
 
+	if err := writeHNSW(w, idx.ann, ids); err != nil {
+		return fmt.Errorf("write ann graph: %w", err)
+	}
+
 	if err := w.Flush(); err != nil {
 		return fmt.Errorf("flush: %w", err)
 	}
@@ -245,6 +379,29 @@ func (idx *VectorIndex) Save(path string) error {
 	return nil
 }
 
+func readIndexString(r io.Reader) (string, error) {
+	var n uint16
+	if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+		return "", err
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func writeIndexString(w io.Writer, s string) error {
+	if len(s) > math.MaxUint16 {
+		return fmt.Errorf("string too long: %d", len(s))
+	}
+	if err := binary.Write(w, binary.LittleEndian, uint16(len(s))); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, s)
+	return err
+}
+
 func (idx *VectorIndex) Upsert(issueID string, hash ContentHash, vec []float32) error {
 	if issueID == "" {
 		return fmt.Errorf("issue id cannot be empty")
@@ -266,6 +423,9 @@ func (idx *VectorIndex) Upsert(issueID string, hash ContentHash, vec []float32)
 	if !exists {
 		idx.idsDirty = true
 	}
+	if idx.ann != nil {
+		idx.ann.insert(issueID, cp)
+	}
 	return nil
 }
 
@@ -278,6 +438,67 @@ func (idx *VectorIndex) Remove(issueID string) {
 	}
 	delete(idx.entries, issueID)
 	idx.idsDirty = true
+	if idx.ann != nil {
+		idx.ann.remove(issueID)
+	}
+}
+
+// Reset drops every entry and switches the index to dim.
+func (idx *VectorIndex) Reset(dim int) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.Dim = dim
+	idx.entries = make(map[string]VectorEntry)
+	idx.idsCache = nil
+	idx.idsDirty = true
+	idx.ann = nil
+}
+
+func (idx *VectorIndex) annThreshold() int {
+	if idx.ANNThreshold == 0 {
+		return DefaultANNThreshold
+	}
+	return idx.ANNThreshold
+}
+
+// ANNActive reports whether SearchTopK is answered from the HNSW graph.
+func (idx *VectorIndex) ANNActive() bool {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.annActiveLocked()
+}
+
+func (idx *VectorIndex) annActiveLocked() bool {
+	t := idx.annThreshold()
+	return t > 0 && idx.ann != nil && len(idx.entries) >= t && idx.ann.live() == len(idx.entries)
+}
+
+// RefreshANN builds the HNSW graph once the index reaches the ANN threshold
+// and rebuilds it when tombstones from updates and removals pile up. Upserts
+// and removals keep an existing graph current in between. It reports whether
+// the graph was (re)built.
+func (idx *VectorIndex) RefreshANN() bool {
+	ids := idx.sortedIDs()
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	t := idx.annThreshold()
+	if t <= 0 || len(idx.entries) < t {
+		idx.ann = nil
+		return false
+	}
+	if idx.ann != nil && !idx.ann.needsCompaction() && idx.ann.live() == len(idx.entries) {
+		return false
+	}
+	g := newHNSW()
+	for _, id := range ids {
+		if entry, ok := idx.entries[id]; ok {
+			g.insert(id, entry.Vector)
+		}
+	}
+	idx.ann = g
+	return true
 }
 
 func (idx *VectorIndex) Get(issueID string) (VectorEntry, bool) {
@@ -322,6 +543,9 @@ func (idx *VectorIndex) sortedIDs() []string {
 type SearchResult struct {
 	IssueID string  `json:"issue_id"`
 	Score   float64 `json:"score"`
+	// Chunk is the key of the best-matching chunk for issue-level results
+	// (see SearchIssuesTopK); empty for raw index results.
+	Chunk string `json:"chunk,omitempty"`
 }
 
 func (idx *VectorIndex) SearchTopK(query []float32, k int) ([]SearchResult, error) {
@@ -332,6 +556,13 @@ func (idx *VectorIndex) SearchTopK(query []float32, k int) ([]SearchResult, erro
 		return nil, fmt.Errorf("query dim mismatch: %d != %d", len(query), idx.Dim)
 	}
 
+	idx.mu.RLock()
+	if idx.annActiveLocked() {
+		defer idx.mu.RUnlock()
+		return idx.ann.search(query, k), nil
+	}
+	idx.mu.RUnlock()
+
 	// sortedIDs now handles its own locking safely
 	ids := idx.sortedIDs()
 
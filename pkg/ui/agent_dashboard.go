@@ -0,0 +1,329 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// agentSortMode orders the agent dashboard.
+type agentSortMode int
+
+const (
+	agentSortName agentSortMode = iota
+	agentSortWIP
+	agentSortAcceptance
+	agentSortBounce
+	agentSortCycleTime
+	agentSortModeCount
+)
+
+func (s agentSortMode) String() string {
+	switch s {
+	case agentSortWIP:
+		return "wip"
+	case agentSortAcceptance:
+		return "acceptance"
+	case agentSortBounce:
+		return "bounce rate"
+	case agentSortCycleTime:
+		return "cycle time"
+	default:
+		return "name"
+	}
+}
+
+// AgentDashboardModel shows each agent's track record: how often it accepts
+// or hands back work, how fast it closes, how much of it is reopened, what it
+// holds now and which labels it specialises in.
+type AgentDashboardModel struct {
+	report       analysis.AgentReport
+	agents       []analysis.AgentStats // report.Agents in display order
+	issues       []model.Issue
+	rosterSource string
+	sortMode     agentSortMode
+	selected     int
+	scrollOffset int
+	width        int
+	height       int
+	theme        Theme
+}
+
+// NewAgentDashboardModel creates an agent dashboard view
+func NewAgentDashboardModel(report analysis.AgentReport, issues []model.Issue, rosterSource string, theme Theme) AgentDashboardModel {
+	m := AgentDashboardModel{rosterSource: rosterSource, theme: theme}
+	m.SetData(report, issues)
+	return m
+}
+
+// SetData replaces the stats after a reload, keeping the selected agent
+// selected.
+func (m *AgentDashboardModel) SetData(report analysis.AgentReport, issues []model.Issue) {
+	selected := m.SelectedAgentName()
+	m.report = report
+	m.issues = issues
+	m.resort(selected)
+}
+
+// SetSize updates the view dimensions
+func (m *AgentDashboardModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// CycleSort switches to the next sort order
+func (m *AgentDashboardModel) CycleSort() {
+	m.sortMode = (m.sortMode + 1) % agentSortModeCount
+	m.resort(m.SelectedAgentName())
+}
+
+func (m *AgentDashboardModel) resort(selected string) {
+	m.agents = append([]analysis.AgentStats(nil), m.report.Agents...)
+	less := func(a, b analysis.AgentStats) bool { return false }
+	switch m.sortMode {
+	case agentSortWIP:
+		less = func(a, b analysis.AgentStats) bool { return a.WIP > b.WIP }
+	case agentSortAcceptance:
+		less = func(a, b analysis.AgentStats) bool { return a.AcceptanceRate > b.AcceptanceRate }
+	case agentSortBounce:
+		less = func(a, b analysis.AgentStats) bool { return a.BounceRate > b.BounceRate }
+	case agentSortCycleTime:
+		// Agents that have not closed anything go last.
+		less = func(a, b analysis.AgentStats) bool {
+			if a.MedianCycleTimeHours == nil || b.MedianCycleTimeHours == nil {
+				return a.MedianCycleTimeHours != nil && b.MedianCycleTimeHours == nil
+			}
+			return *a.MedianCycleTimeHours < *b.MedianCycleTimeHours
+		}
+	}
+	sort.SliceStable(m.agents, func(i, j int) bool {
+		if less(m.agents[i], m.agents[j]) {
+			return true
+		}
+		if less(m.agents[j], m.agents[i]) {
+			return false
+		}
+		return m.agents[i].Agent < m.agents[j].Agent
+	})
+
+	m.selected = 0
+	for i, a := range m.agents {
+		if a.Agent == selected {
+			m.selected = i
+			break
+		}
+	}
+	m.ensureVisible()
+}
+
+// MoveUp moves selection up
+func (m *AgentDashboardModel) MoveUp() {
+	if m.selected > 0 {
+		m.selected--
+	}
+	m.ensureVisible()
+}
+
+// MoveDown moves selection down
+func (m *AgentDashboardModel) MoveDown() {
+	if m.selected < len(m.agents)-1 {
+		m.selected++
+	}
+	m.ensureVisible()
+}
+
+// SelectedAgent returns the stats of the agent under the cursor
+func (m *AgentDashboardModel) SelectedAgent() (analysis.AgentStats, bool) {
+	if m.selected >= len(m.agents) {
+		return analysis.AgentStats{}, false
+	}
+	return m.agents[m.selected], true
+}
+
+// SelectedAgentName returns the name of the agent under the cursor
+func (m *AgentDashboardModel) SelectedAgentName() string {
+	a, _ := m.SelectedAgent()
+	return a.Agent
+}
+
+// listRows is how many agent rows fit above the detail pane.
+func (m *AgentDashboardModel) listRows() int {
+	// Header, hint line and column header take three rows; the table gets
+	// half of what remains.
+	return max(1, (m.height-3)/2)
+}
+
+func (m *AgentDashboardModel) ensureVisible() {
+	if m.selected < m.scrollOffset {
+		m.scrollOffset = m.selected
+	}
+	if m.selected >= m.scrollOffset+m.listRows() {
+		m.scrollOffset = m.selected - m.listRows() + 1
+	}
+}
+
+// Render renders the agent dashboard
+func (m *AgentDashboardModel) Render() string {
+	if m.width == 0 || m.height == 0 {
+		return ""
+	}
+	t := m.theme
+	var lines []string
+
+	headerStyle := t.Renderer.NewStyle().
+		Bold(true).
+		Foreground(t.Base.GetForeground()).
+		Background(t.Primary).
+		Padding(0, 2).
+		Width(m.width - 4)
+	history := "ack history only"
+	if m.report.HistoryAvailable {
+		history = "ack + git history"
+	}
+	lines = append(lines, headerStyle.Render(fmt.Sprintf("🤖 AGENTS  │  %d agents • %s • roster from %s",
+		len(m.agents), history, m.rosterSource)))
+
+	mutedStyle := t.Renderer.NewStyle().Foreground(t.Subtext).Italic(true)
+	lines = append(lines, mutedStyle.Render("s: sort (by "+m.sortMode.String()+") • j/k: select • esc: back"))
+
+	if len(m.agents) == 0 {
+		emptyStyle := t.Renderer.NewStyle().
+			Foreground(t.Subtext).
+			Italic(true).
+			Padding(2, 4).
+			Width(m.width - 4).
+			Align(lipgloss.Center)
+		lines = append(lines, emptyStyle.Render("No agents yet. Assign beads, or list agents in .bv/roster.yaml."))
+		return strings.Join(lines, "\n")
+	}
+
+	// Columns: agent | wip | offers | accept | bounce | cycle | reopen | commits | labels
+	const agentWidth, wipWidth, numWidth, cycleWidth = 16, 7, 8, 9
+	labelWidth := max(10, m.width-agentWidth-wipWidth-5*numWidth-cycleWidth-12)
+	columnStyle := t.Renderer.NewStyle().Foreground(t.Secondary).Bold(true)
+	lines = append(lines, columnStyle.Render(
+		padRight("AGENT", agentWidth)+" "+padRight("WIP", wipWidth)+" "+padRight("OFFERS", numWidth)+" "+
+			padRight("ACCEPT", numWidth)+" "+padRight("BOUNCE", numWidth)+" "+padRight("CYCLE", cycleWidth)+" "+
+			padRight("REOPEN", numWidth)+" "+padRight("COMMITS", numWidth)+" LABELS"))
+
+	overStyle := t.Renderer.NewStyle().Foreground(t.Blocked).Bold(true)
+	end := min(len(m.agents), m.scrollOffset+m.listRows())
+	for i := m.scrollOffset; i < end; i++ {
+		a := m.agents[i]
+		wip := padRight(fmt.Sprintf("%d/%d", a.WIP, a.Capacity), wipWidth)
+		if a.WIP > a.Capacity {
+			wip = overStyle.Render(wip)
+		}
+		line := padRight(truncateRunesHelper(a.Agent, agentWidth, "…"), agentWidth) + " " +
+			wip + " " +
+			padRight(fmt.Sprintf("%d", a.Offers), numWidth) + " " +
+			padRight(agentRate(a.AcceptanceRate, a.Offers > 0), numWidth) + " " +
+			padRight(agentRate(a.BounceRate, a.Offers > 0), numWidth) + " " +
+			padRight(agentCycleTime(a.MedianCycleTimeHours), cycleWidth) + " " +
+			padRight(agentReopenRate(a.ReopenRate), numWidth) + " " +
+			padRight(agentCommits(a, m.report.HistoryAvailable), numWidth) + " " +
+			mutedStyle.Render(truncateRunesHelper(agentLabelSummary(a.Labels), labelWidth, "…"))
+
+		lineStyle := t.Renderer.NewStyle().Width(m.width - 2)
+		if i == m.selected {
+			lineStyle = lineStyle.Background(t.Highlight).Bold(true)
+		}
+		lines = append(lines, lineStyle.Render(line))
+	}
+
+	lines = append(lines, mutedStyle.Render(strings.Repeat("─", max(0, m.width-2))))
+	lines = append(lines, m.renderDetail(mutedStyle)...)
+
+	if len(lines) > m.height {
+		lines = lines[:m.height]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderDetail shows how the selected agent hands work back, what it holds
+// now and where its work concentrates.
+func (m *AgentDashboardModel) renderDetail(mutedStyle lipgloss.Style) []string {
+	t := m.theme
+	a, ok := m.SelectedAgent()
+	if !ok {
+		return nil
+	}
+	labelStyle := t.Renderer.NewStyle().Foreground(t.Primary).Bold(true)
+
+	var lines []string
+	lines = append(lines, labelStyle.Render(a.Agent)+mutedStyle.Render(fmt.Sprintf(
+		"  accepted %d • declined %d • deferred %d • impossible %d • missed deadline %d • closed %d",
+		a.Accepted, a.Declined, a.Deferred, a.Impossible, a.Expired, a.Closed)))
+	if len(a.Skills) > 0 {
+		lines = append(lines, labelStyle.Render("Skills: ")+strings.Join(a.Skills, ", "))
+	}
+	if len(a.Labels) > 0 {
+		lines = append(lines, labelStyle.Render("Specialisation: ")+
+			fmt.Sprintf("%.0f%% %s", a.Specialization*100, a.Labels[0].Label))
+	}
+
+	lines = append(lines, labelStyle.Render("Holding"))
+	held := 0
+	for _, issue := range m.issues {
+		if issue.Assignee != a.Agent || issue.Status.IsClosed() || issue.Status.IsTombstone() {
+			continue
+		}
+		switch issue.AckStatus {
+		case model.AckStatusDeclined, model.AckStatusDeferred, model.AckStatusImpossible:
+			continue
+		}
+		ack := string(issue.AckStatus)
+		if ack == "" {
+			ack = "-"
+		}
+		text := fmt.Sprintf("  %-12s %-12s %-9s %s", issue.ID, issue.Status, ack, issue.Title)
+		lines = append(lines, truncateRunesHelper(text, max(10, m.width-2), "…"))
+		held++
+	}
+	if held == 0 {
+		lines = append(lines, mutedStyle.Render("  nothing open"))
+	}
+	return lines
+}
+
+func agentRate(rate float64, known bool) string {
+	if !known {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f%%", rate*100)
+}
+
+func agentReopenRate(rate *float64) string {
+	if rate == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f%%", *rate*100)
+}
+
+func agentCycleTime(hours *float64) string {
+	if hours == nil {
+		return "-"
+	}
+	return formatDuration(time.Duration(*hours * float64(time.Hour)))
+}
+
+func agentCommits(a analysis.AgentStats, historyAvailable bool) string {
+	if !historyAvailable {
+		return "-"
+	}
+	return fmt.Sprintf("%d", a.Commits)
+}
+
+func agentLabelSummary(labels []analysis.AgentLabel) string {
+	parts := make([]string, 0, len(labels))
+	for _, l := range labels {
+		parts = append(parts, fmt.Sprintf("%s %.0f%%", l.Label, l.Share*100))
+	}
+	return strings.Join(parts, ", ")
+}
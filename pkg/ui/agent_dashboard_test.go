@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func agentDashboardIssues() []model.Issue {
+	created := time.Now().Add(-10 * time.Hour)
+	closed := created.Add(4 * time.Hour)
+	return []model.Issue{
+		{ID: "done", Title: "Shipped", Status: model.StatusClosed, IssueType: model.TypeTask, Assignee: "alice",
+			AckStatus: model.AckStatusAccepted, Labels: []string{"backend"}, CreatedAt: created, ClosedAt: &closed},
+		{ID: "wip", Title: "In flight", Status: model.StatusInProgress, IssueType: model.TypeTask, Assignee: "alice",
+			AckStatus: model.AckStatusAccepted, Labels: []string{"backend"}, CreatedAt: created},
+		{ID: "nope", Title: "Bounced", Status: model.StatusOpen, IssueType: model.TypeTask, Assignee: "bob",
+			AckStatus: model.AckStatusDeclined, CreatedAt: created,
+			Comments: []*model.Comment{{Author: "bob", Text: "[DECLINED] Too big (bounce 1/3)", CreatedAt: created}}},
+	}
+}
+
+func TestAgentDashboardRender(t *testing.T) {
+	issues := agentDashboardIssues()
+	report := analysis.ComputeAgentStats(issues, analysis.AgentStatsOptions{})
+	m := NewAgentDashboardModel(report, issues, "assignees", newTestTheme())
+	m.SetSize(160, 30)
+
+	out := m.Render()
+	for _, want := range []string{"2 agents", "ack history only", "alice", "1/1", "100%", "4h", "backend 100%", "In flight"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("render missing %q:\n%s", want, out)
+		}
+	}
+
+	// Bounce-rate order puts bob first, and the selection follows alice.
+	m.CycleSort()
+	m.CycleSort()
+	m.CycleSort()
+	if got := m.SelectedAgentName(); got != "alice" {
+		t.Errorf("selection should stay on alice, got %q", got)
+	}
+	m.MoveUp()
+	if got := m.SelectedAgentName(); got != "bob" {
+		t.Errorf("bob has the highest bounce rate, got %q first", got)
+	}
+}
+
+func TestAgentViewToggle(t *testing.T) {
+	m := NewModel(agentDashboardIssues(), nil, "")
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("I")})
+	m = updated.(Model)
+	if !m.isAgentView || m.focused != focusAgents {
+		t.Fatalf("I should open the agent dashboard (view=%v focus=%v)", m.isAgentView, m.focused)
+	}
+	if !strings.Contains(m.View(), "AGENTS") {
+		t.Errorf("agent dashboard not rendered")
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if m.isAgentView || m.focused != focusList {
+		t.Fatalf("Esc should close the agent dashboard")
+	}
+}
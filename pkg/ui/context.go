@@ -28,6 +28,11 @@ const (
 	ContextGraph          Context = "graph"
 	ContextBoard          Context = "board"
 	ContextActionable     Context = "actionable"
+	ContextSchedule       Context = "schedule"
+	ContextTimeline       Context = "timeline"
+	ContextEpicTree       Context = "epic-tree"
+	ContextEscalations    Context = "escalations"
+	ContextAgents         Context = "agents"
 	ContextHistory        Context = "history"
 	ContextSprint         Context = "sprint"
 	ContextLabelDashboard Context = "label-dashboard"
@@ -147,6 +152,31 @@ func (m Model) CurrentContext() Context {
 		return ContextActionable
 	}
 
+	// Schedule view
+	if m.isScheduleView {
+		return ContextSchedule
+	}
+
+	// Timeline view
+	if m.isTimelineView {
+		return ContextTimeline
+	}
+
+	// Epic tree view
+	if m.isEpicTreeView {
+		return ContextEpicTree
+	}
+
+	// Escalation queue
+	if m.isEscalationView {
+		return ContextEscalations
+	}
+
+	// Agent dashboard
+	if m.isAgentView {
+		return ContextAgents
+	}
+
 	// History view
 	if m.isHistoryView {
 		return ContextHistory
@@ -206,6 +236,11 @@ func (c Context) Description() string {
 		ContextGraph:              "Dependency graph",
 		ContextBoard:              "Kanban board",
 		ContextActionable:         "Actionable view",
+		ContextSchedule:           "Agent schedule",
+		ContextTimeline:           "Timeline view",
+		ContextEpicTree:           "Epic tree",
+		ContextEscalations:        "Escalation queue",
+		ContextAgents:             "Agent dashboard",
 		ContextHistory:            "History view",
 		ContextSprint:             "Sprint view",
 		ContextLabelDashboard:     "Label dashboard",
@@ -238,8 +273,8 @@ func (c Context) IsOverlay() bool {
 func (c Context) IsView() bool {
 	switch c {
 	case ContextInsights, ContextFlowMatrix, ContextGraph, ContextBoard,
-		ContextActionable, ContextHistory, ContextSprint, ContextLabelDashboard,
-		ContextAttention, ContextSplit, ContextDetail, ContextTimeTravel:
+		ContextActionable, ContextSchedule, ContextTimeline, ContextEpicTree, ContextEscalations, ContextAgents, ContextHistory, ContextSprint,
+		ContextLabelDashboard, ContextAttention, ContextSplit, ContextDetail, ContextTimeTravel:
 		return true
 	}
 	return false
@@ -259,6 +294,11 @@ func (c Context) TutorialPages() []int {
 		ContextInsights:           {7},           // Insights
 		ContextHistory:            {8},           // History View
 		ContextActionable:         {9},           // Actionable View
+		ContextSchedule:           {9},           // Actionable View (scheduling builds on it)
+		ContextTimeline:           {9},           // Actionable View (scheduling builds on it)
+		ContextEpicTree:           {9},           // Actionable View
+		ContextEscalations:        {9},           // Actionable View
+		ContextAgents:             {9},           // Actionable View
 		ContextTimeTravel:         {10},          // Time-Travel
 		ContextLabelDashboard:     {11},          // Labels
 		ContextFlowMatrix:         {11, 12},      // Labels, Advanced
@@ -15,6 +15,11 @@ var ContextHelpContent = map[Context]string{
 	ContextBoard:          contextHelpBoard,
 	ContextInsights:       contextHelpInsights,
 	ContextHistory:        contextHelpHistory,
+	ContextSchedule:       contextHelpSchedule,
+	ContextTimeline:       contextHelpTimeline,
+	ContextEpicTree:       contextHelpEpicTree,
+	ContextEscalations:    contextHelpEscalations,
+	ContextAgents:         contextHelpAgents,
 	ContextDetail:         contextHelpDetail,
 	ContextSplit:          contextHelpSplit,
 	ContextFilter:         contextHelpFilter,
@@ -100,7 +105,7 @@ const contextHelpList = `## List View
   c         Closed issues only
   r         Ready (no blockers)
   a         All issues
-  /         Fuzzy search
+  /         Fuzzy search or query (status:open label:api)
   Ctrl+S    Semantic search (AI)
   H         Hybrid ranking
   Alt+H     Hybrid preset
@@ -113,7 +118,89 @@ const contextHelpList = `## List View
 
 **Actions**
   U         Self-update bv
-  V         Preview cass sessions`
+  V         Preview cass sessions
+  E/P/A     Edit status/priority/assignee
+  + / M     Edit labels / add comment
+  D / -     Add / remove blocker
+  X / N     Close / new issue`
+
+const contextHelpSchedule = `## Agent Schedule
+
+Open work assigned to agents over time. One row
+per issue; bars span its estimated working time
+(8h per day, ┬ marks each day). Accepted
+assignments stay put (highlighted bars).
+
+**Navigation**
+  j/k       Move between issues
+  Enter     View issue details
+  Esc / W   Back to list
+
+**Roster**
+  Agents come from .bv/roster.yaml
+  (name, capacity, skills). Without it,
+  current assignees are scheduled.`
+
+const contextHelpTimeline = `## Timeline
+
+One bar per open issue: ░ from creation until
+work starts, █ until the forecast finish.
+◆ due date, ◈ deadline, ┊ today. Orange bars
+are the critical path, red bars finish late.
+Arrows show what the selected issue waits on.
+
+**Navigation**
+  j/k       Move between issues
+  h/l       Scroll earlier / later
+  z         Zoom: day → week → month
+  Enter     View issue details
+  Esc / Y   Back to list`
+
+const contextHelpEpicTree = `## Epic Tree
+
+Parent-child hierarchy with a roll-up per epic:
+progress weighted by estimate, closed/total
+children, blocked (⛔) and stale (⏳) work, and
+the forecast finish. "ready to close" marks
+open epics whose children are all closed.
+
+**Navigation**
+  j/k       Move between issues
+  Space     Fold / unfold
+  h/l       Fold / unfold (or go to parent)
+  Enter     View issue details
+  Esc / B   Back to list`
+
+const contextHelpEscalations = `## Escalation Queue
+
+Beads waiting on a lead: marked impossible,
+escalated after 3 bounces, or assigned but not
+acknowledged by their deadline. The lower pane
+shows the selected bead's hand-off history and
+who could take it (least loaded first).
+
+**Resolve**
+  r         Return to the unassigned queue
+  1-3       Reassign to that suggestion
+  e         Handle all missed deadlines
+
+**Navigation**
+  j/k       Move between beads
+  Enter     View issue details
+  Esc / Q   Back to list`
+
+const contextHelpAgents = `## Agent Dashboard
+
+Each agent's track record from bd-ack history:
+how often it accepts or hands work back, median
+cycle time, reopen rate and commits (once git
+history has loaded), what it holds against its
+roster capacity, and its top labels.
+
+**Navigation**
+  j/k       Move between agents
+  s         Cycle sort order
+  Esc / I   Back to list`
 
 const contextHelpGraph = `## Graph View
 
@@ -124,12 +211,24 @@ const contextHelpGraph = `## Graph View
   f         Focus on subgraph
   Esc       Exit to list
 
+**Full DAG (d)**
+  j/k       Move between layers
+  h/l       Move within a layer
+  H/L       Pan left/right
+  PgUp/Dn   Pan up/down
+  z         Zoom: IDs / IDs + titles
+  s         Scope to the bead's labels
+  /         Jump to a bead ID
+
 **Understanding the Graph**
 • Arrows point TO what's blocked
   (A → B means A blocks B)
 • Node size = priority
 • Color = status
-  Green=closed, Blue=in_progress`
+  Green=closed, Blue=in_progress
+• Full DAG: heavy boxes and orange
+  edges mark the critical path,
+  red edges close a cycle`
 
 const contextHelpBoard = `## Board View
 
@@ -254,7 +353,7 @@ const contextHelpFilter = `## Filter Mode
   a         All (clear filter)
 
 **Search**
-  /         Start fuzzy search
+  /         Fuzzy search or query (priority<=1)
   Ctrl+S    Semantic search (AI)
   H         Hybrid ranking
   Alt+H     Hybrid preset
@@ -139,6 +139,31 @@ func TestCurrentContext_Views(t *testing.T) {
 			setup:    func(m *Model) { m.isActionableView = true },
 			expected: ContextActionable,
 		},
+		{
+			name:     "schedule view",
+			setup:    func(m *Model) { m.isScheduleView = true },
+			expected: ContextSchedule,
+		},
+		{
+			name:     "timeline view",
+			setup:    func(m *Model) { m.isTimelineView = true },
+			expected: ContextTimeline,
+		},
+		{
+			name:     "epic tree view",
+			setup:    func(m *Model) { m.isEpicTreeView = true },
+			expected: ContextEpicTree,
+		},
+		{
+			name:     "escalation queue",
+			setup:    func(m *Model) { m.isEscalationView = true },
+			expected: ContextEscalations,
+		},
+		{
+			name:     "agent dashboard",
+			setup:    func(m *Model) { m.isAgentView = true },
+			expected: ContextAgents,
+		},
 		{
 			name:     "history view",
 			setup:    func(m *Model) { m.isHistoryView = true },
@@ -280,8 +305,8 @@ func TestContext_IsOverlay(t *testing.T) {
 func TestContext_IsView(t *testing.T) {
 	views := []Context{
 		ContextInsights, ContextFlowMatrix, ContextGraph, ContextBoard,
-		ContextActionable, ContextHistory, ContextSprint, ContextLabelDashboard,
-		ContextAttention, ContextSplit, ContextDetail, ContextTimeTravel,
+		ContextActionable, ContextSchedule, ContextTimeline, ContextEpicTree, ContextEscalations, ContextAgents, ContextHistory, ContextSprint,
+		ContextLabelDashboard, ContextAttention, ContextSplit, ContextDetail, ContextTimeTravel,
 	}
 
 	for _, c := range views {
@@ -0,0 +1,387 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/mutate"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// EditKind identifies which edit form the modal shows.
+type EditKind int
+
+const (
+	EditStatus EditKind = iota
+	EditPriority
+	EditAssignee
+	EditLabels
+	EditAddDependency
+	EditRemoveDependency
+	EditComment
+	EditClose
+	EditCreate
+)
+
+// editValues holds the form bindings. huh binds to pointers, so these live on
+// the heap and survive the value copies bubbletea makes of the modal.
+type editValues struct {
+	status      string
+	priority    int
+	text        string
+	labels      []string
+	newLabels   string
+	target      string
+	issueType   string
+	description string
+}
+
+// EditModal wraps a huh form that edits one field of an issue (or creates a
+// new one). When the form completes, Ops/NewIssue describe the change; the
+// Model applies it through pkg/mutate.
+type EditModal struct {
+	kind   EditKind
+	issue  model.Issue
+	form   *huh.Form
+	values *editValues
+	theme  Theme
+	width  int
+}
+
+// NewEditModal builds the form for kind. issues supplies choices for
+// assignee, label and dependency pickers.
+func NewEditModal(kind EditKind, issue model.Issue, issues []model.Issue, theme Theme) EditModal {
+	v := &editValues{
+		status:   string(issue.Status),
+		priority: issue.Priority,
+		text:     issue.Assignee,
+		labels:   append([]string{}, issue.Labels...),
+	}
+	if kind == EditCreate {
+		v.status = string(model.StatusOpen)
+		v.priority = 2
+		v.text = ""
+		v.issueType = string(model.TypeTask)
+	}
+
+	m := EditModal{kind: kind, issue: issue, values: v, theme: theme, width: 60}
+	m.form = huh.NewForm(huh.NewGroup(m.fields(issues)...)).
+		WithShowHelp(true).
+		WithWidth(m.width).
+		WithKeyMap(editKeyMap())
+	// The modal is embedded in the main program; completion is detected via
+	// form.State, so the form must not emit tea.Quit.
+	m.form.SubmitCmd = nil
+	m.form.CancelCmd = nil
+	return m
+}
+
+func editKeyMap() *huh.KeyMap {
+	km := huh.NewDefaultKeyMap()
+	km.Quit = key.NewBinding(key.WithKeys("esc", "ctrl+c"), key.WithHelp("esc", "cancel"))
+	return km
+}
+
+func (m EditModal) fields(issues []model.Issue) []huh.Field {
+	v := m.values
+	switch m.kind {
+	case EditStatus:
+		return []huh.Field{
+			huh.NewSelect[string]().
+				Title("Status of " + m.issue.ID).
+				Options(huh.NewOptions(
+					string(model.StatusOpen),
+					string(model.StatusInProgress),
+					string(model.StatusBlocked),
+					string(model.StatusClosed),
+				)...).
+				Value(&v.status),
+		}
+	case EditPriority:
+		return []huh.Field{priorityField(&v.priority, "Priority of "+m.issue.ID)}
+	case EditAssignee:
+		return []huh.Field{
+			huh.NewInput().
+				Title("Assignee of " + m.issue.ID).
+				Placeholder("empty to unassign").
+				Suggestions(knownAssignees(issues)).
+				Value(&v.text),
+		}
+	case EditLabels:
+		fields := []huh.Field{}
+		if known := knownLabels(issues); len(known) > 0 {
+			fields = append(fields, huh.NewMultiSelect[string]().
+				Title("Labels of "+m.issue.ID).
+				Options(huh.NewOptions(known...)...).
+				Filterable(true).
+				Height(10).
+				Value(&v.labels))
+		}
+		return append(fields, huh.NewInput().
+			Title("New labels").
+			Placeholder("comma-separated").
+			Value(&v.newLabels))
+	case EditAddDependency:
+		opts := make([]huh.Option[string], 0, len(issues))
+		for _, iss := range issues {
+			if iss.ID == m.issue.ID || iss.Status == model.StatusTombstone {
+				continue
+			}
+			opts = append(opts, huh.NewOption(iss.ID+"  "+truncateString(iss.Title, 40), iss.ID))
+		}
+		return []huh.Field{
+			huh.NewSelect[string]().
+				Title(m.issue.ID + " is blocked by…").
+				Options(opts...).
+				Filtering(true).
+				Height(12).
+				Value(&v.target),
+		}
+	case EditRemoveDependency:
+		opts := make([]huh.Option[string], 0, len(m.issue.Dependencies))
+		for _, dep := range m.issue.Dependencies {
+			if dep == nil {
+				continue
+			}
+			label := fmt.Sprintf("%s (%s)", dep.DependsOnID, dep.Type)
+			opts = append(opts, huh.NewOption(label, dep.DependsOnID))
+		}
+		return []huh.Field{
+			huh.NewSelect[string]().
+				Title("Remove dependency of " + m.issue.ID).
+				Options(opts...).
+				Value(&v.target),
+		}
+	case EditComment:
+		return []huh.Field{
+			huh.NewText().
+				Title("Comment on " + m.issue.ID).
+				Lines(5).
+				Validate(requireText("comment")).
+				Value(&v.text),
+		}
+	case EditClose:
+		return []huh.Field{
+			huh.NewInput().
+				Title("Close " + m.issue.ID).
+				Placeholder("reason (optional)").
+				Value(&v.text),
+		}
+	case EditCreate:
+		return []huh.Field{
+			huh.NewInput().
+				Title("Title").
+				Validate(requireText("title")).
+				Value(&v.text),
+			huh.NewSelect[string]().
+				Title("Type").
+				Options(huh.NewOptions(
+					string(model.TypeTask),
+					string(model.TypeBug),
+					string(model.TypeFeature),
+					string(model.TypeEpic),
+					string(model.TypeChore),
+				)...).
+				Value(&v.issueType),
+			priorityField(&v.priority, "Priority"),
+			huh.NewText().
+				Title("Description").
+				Lines(4).
+				Value(&v.description),
+		}
+	}
+	return nil
+}
+
+func priorityField(value *int, title string) huh.Field {
+	opts := make([]huh.Option[int], 0, mutate.MaxPriority-mutate.MinPriority+1)
+	for p := mutate.MinPriority; p <= mutate.MaxPriority; p++ {
+		opts = append(opts, huh.NewOption("P"+strconv.Itoa(p), p))
+	}
+	return huh.NewSelect[int]().Title(title).Options(opts...).Value(value)
+}
+
+func requireText(what string) func(string) error {
+	return func(s string) error {
+		if strings.TrimSpace(s) == "" {
+			return fmt.Errorf("%s is required", what)
+		}
+		return nil
+	}
+}
+
+func knownAssignees(issues []model.Issue) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, iss := range issues {
+		if iss.Assignee != "" && !seen[iss.Assignee] {
+			seen[iss.Assignee] = true
+			out = append(out, iss.Assignee)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+func knownLabels(issues []model.Issue) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, iss := range issues {
+		for _, l := range iss.Labels {
+			if !seen[l] {
+				seen[l] = true
+				out = append(out, l)
+			}
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Init starts the form.
+func (m EditModal) Init() tea.Cmd {
+	return m.form.Init()
+}
+
+// Update forwards messages to the form.
+func (m EditModal) Update(msg tea.Msg) (EditModal, tea.Cmd) {
+	updated, cmd := m.form.Update(msg)
+	if f, ok := updated.(*huh.Form); ok {
+		m.form = f
+	}
+	return m, cmd
+}
+
+// Done reports whether the form was submitted.
+func (m EditModal) Done() bool { return m.form.State == huh.StateCompleted }
+
+// Aborted reports whether the form was cancelled.
+func (m EditModal) Aborted() bool { return m.form.State == huh.StateAborted }
+
+// Kind returns the edit kind.
+func (m EditModal) Kind() EditKind { return m.kind }
+
+// IssueID returns the ID of the issue being edited (empty for EditCreate).
+func (m EditModal) IssueID() string { return m.issue.ID }
+
+// Ops converts the submitted form into mutations. It returns nil when the
+// submission is a no-op.
+func (m EditModal) Ops() []mutate.Op {
+	v := m.values
+	switch m.kind {
+	case EditStatus:
+		if model.Status(v.status) == m.issue.Status {
+			return nil
+		}
+		return []mutate.Op{mutate.SetStatus{Status: model.Status(v.status)}}
+	case EditPriority:
+		if v.priority == m.issue.Priority {
+			return nil
+		}
+		return []mutate.Op{mutate.SetPriority{Priority: v.priority}}
+	case EditAssignee:
+		if strings.TrimSpace(v.text) == m.issue.Assignee {
+			return nil
+		}
+		return []mutate.Op{mutate.SetAssignee{Assignee: v.text}}
+	case EditLabels:
+		labels := append([]string{}, v.labels...)
+		for _, l := range strings.Split(v.newLabels, ",") {
+			labels = append(labels, l)
+		}
+		return []mutate.Op{mutate.SetLabels{Labels: labels}}
+	case EditAddDependency:
+		if v.target == "" {
+			return nil
+		}
+		return []mutate.Op{mutate.AddDependency{DependsOnID: v.target, Type: model.DepBlocks}}
+	case EditRemoveDependency:
+		if v.target == "" {
+			return nil
+		}
+		return []mutate.Op{mutate.RemoveDependency{DependsOnID: v.target}}
+	case EditComment:
+		return []mutate.Op{mutate.AddComment{Text: v.text}}
+	case EditClose:
+		return []mutate.Op{mutate.Close{Reason: v.text}}
+	}
+	return nil
+}
+
+// NewIssue returns the issue described by a submitted EditCreate form.
+func (m EditModal) NewIssue() model.Issue {
+	v := m.values
+	return model.Issue{
+		Title:       strings.TrimSpace(v.text),
+		Description: strings.TrimSpace(v.description),
+		Status:      model.StatusOpen,
+		Priority:    v.priority,
+		IssueType:   model.IssueType(v.issueType),
+	}
+}
+
+// View renders the form inside a bordered box.
+func (m EditModal) View() string {
+	box := m.theme.Renderer.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(m.theme.Primary).
+		Padding(1, 2)
+	return box.Render(m.form.View())
+}
+
+// CenterModal returns the modal view centered in the given dimensions.
+func (m EditModal) CenterModal(termWidth, termHeight int) string {
+	return lipgloss.Place(termWidth, termHeight, lipgloss.Center, lipgloss.Center, m.View())
+}
+
+// EditResultMsg reports the outcome of an edit written through pkg/mutate.
+type EditResultMsg struct {
+	IssueID string
+	Summary string
+	Err     error
+}
+
+// ApplyEditCmd writes ops to the beads file in the background. The file
+// watcher picks up the change and reloads the view.
+func ApplyEditCmd(beadsPath, issueID string, ops []mutate.Op) tea.Cmd {
+	return func() tea.Msg {
+		_, err := mutate.NewFileStore(beadsPath).Apply(issueID, mutate.DefaultActor(), ops...)
+		parts := make([]string, len(ops))
+		for i, op := range ops {
+			parts[i] = op.Describe()
+		}
+		return EditResultMsg{IssueID: issueID, Summary: strings.Join(parts, ", "), Err: err}
+	}
+}
+
+// AddDependencyCmd adds a dependency in the background. Additions that would
+// close a cycle are rejected with the cycle path; accepted ones report their
+// effect on critical-path depth and the actionable count.
+func AddDependencyCmd(beadsPath, issueID string, op mutate.AddDependency) tea.Cmd {
+	return func() tea.Msg {
+		_, impact, err := mutate.NewFileStore(beadsPath).AddDependency(issueID, mutate.DefaultActor(), op)
+		summary := op.Describe()
+		if err == nil && impact != nil {
+			summary += " (" + impact.Summary() + ")"
+		}
+		return EditResultMsg{IssueID: issueID, Summary: summary, Err: err}
+	}
+}
+
+// CreateIssueCmd appends a new issue to the beads file in the background.
+func CreateIssueCmd(beadsPath string, issue model.Issue) tea.Cmd {
+	return func() tea.Msg {
+		created, err := mutate.NewFileStore(beadsPath).Create(issue)
+		if err != nil {
+			return EditResultMsg{Summary: "create", Err: err}
+		}
+		return EditResultMsg{IssueID: created.ID, Summary: "created"}
+	}
+}
@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/mutate"
+)
+
+func TestEditModalOps(t *testing.T) {
+	issue := model.Issue{ID: "bd-1", Title: "A", Status: model.StatusOpen, Priority: 2, Assignee: "alice"}
+	theme := DefaultTheme(nil)
+
+	m := NewEditModal(EditPriority, issue, nil, theme)
+	if ops := m.Ops(); ops != nil {
+		t.Errorf("unchanged priority should yield no ops, got %v", ops)
+	}
+	m.values.priority = 0
+	ops := m.Ops()
+	if len(ops) != 1 || ops[0] != (mutate.SetPriority{Priority: 0}) {
+		t.Errorf("ops = %#v", ops)
+	}
+
+	m = NewEditModal(EditLabels, issue, []model.Issue{{Labels: []string{"api"}}}, theme)
+	m.values.labels = []string{"api"}
+	m.values.newLabels = "db, ui"
+	ops = m.Ops()
+	set, ok := ops[0].(mutate.SetLabels)
+	if !ok || len(ops) != 1 {
+		t.Fatalf("ops = %#v", ops)
+	}
+	target := issue
+	if err := set.Apply(&target, &mutate.Env{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(target.Labels) != 3 {
+		t.Errorf("labels = %v, want [api db ui]", target.Labels)
+	}
+
+	m = NewEditModal(EditCreate, model.Issue{}, nil, theme)
+	m.values.text = "  New issue  "
+	if got := m.NewIssue(); got.Title != "New issue" || got.IssueType != model.TypeTask || got.Priority != 2 {
+		t.Errorf("NewIssue = %+v", got)
+	}
+}
+
+func TestOpenEditModalRefusedInWorkspaceMode(t *testing.T) {
+	m := Model{workspaceMode: true, beadsPath: "issues.jsonl"}
+	m.openEditModal(EditStatus)
+	if m.showEditModal || !m.statusIsError {
+		t.Error("expected edit to be refused in workspace mode")
+	}
+}
@@ -0,0 +1,269 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// epicTreeRow is one visible line of the epic tree.
+type epicTreeRow struct {
+	id          string
+	depth       int
+	hasChildren bool
+}
+
+// EpicTreeModel renders the parent/child hierarchy as a collapsible tree,
+// with a progress bar and roll-up flags on every epic.
+type EpicTreeModel struct {
+	issues       map[string]model.Issue
+	hierarchy    *analysis.Hierarchy
+	report       analysis.EpicReport
+	rollups      map[string]analysis.EpicRollup
+	collapsed    map[string]bool
+	rows         []epicTreeRow
+	selected     int
+	scrollOffset int
+	width        int
+	height       int
+	theme        Theme
+}
+
+// NewEpicTreeModel creates an epic tree view. Closed epics start collapsed.
+func NewEpicTreeModel(issues []model.Issue, report analysis.EpicReport, theme Theme) EpicTreeModel {
+	m := EpicTreeModel{
+		issues:    make(map[string]model.Issue, len(issues)),
+		hierarchy: analysis.BuildHierarchy(issues),
+		report:    report,
+		rollups:   make(map[string]analysis.EpicRollup, len(report.Epics)),
+		collapsed: make(map[string]bool),
+		theme:     theme,
+	}
+	for _, issue := range issues {
+		m.issues[issue.ID] = issue
+	}
+	for _, e := range report.Epics {
+		m.rollups[e.ID] = e
+		if e.Status.IsClosed() {
+			m.collapsed[e.ID] = true
+		}
+	}
+	m.rebuild()
+	return m
+}
+
+// rebuild flattens the expanded part of the tree into rows.
+func (m *EpicTreeModel) rebuild() {
+	m.rows = m.rows[:0]
+	var walk func(id string, depth int)
+	walk = func(id string, depth int) {
+		children := m.hierarchy.Children(id)
+		m.rows = append(m.rows, epicTreeRow{id: id, depth: depth, hasChildren: len(children) > 0})
+		if m.collapsed[id] {
+			return
+		}
+		for _, c := range children {
+			walk(c, depth+1)
+		}
+	}
+	for _, root := range m.hierarchy.Roots() {
+		walk(root, 0)
+	}
+	if m.selected >= len(m.rows) {
+		m.selected = max(0, len(m.rows)-1)
+	}
+	m.ensureVisible()
+}
+
+// SetSize updates the view dimensions
+func (m *EpicTreeModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// MoveUp moves selection up
+func (m *EpicTreeModel) MoveUp() {
+	if m.selected > 0 {
+		m.selected--
+	}
+	m.ensureVisible()
+}
+
+// MoveDown moves selection down
+func (m *EpicTreeModel) MoveDown() {
+	if m.selected < len(m.rows)-1 {
+		m.selected++
+	}
+	m.ensureVisible()
+}
+
+// Toggle collapses or expands the selected node.
+func (m *EpicTreeModel) Toggle() {
+	if m.selected >= len(m.rows) || !m.rows[m.selected].hasChildren {
+		return
+	}
+	id := m.rows[m.selected].id
+	m.collapsed[id] = !m.collapsed[id]
+	m.rebuild()
+}
+
+// Expand opens the selected node, or moves to its first child when it is
+// already open.
+func (m *EpicTreeModel) Expand() {
+	if m.selected >= len(m.rows) || !m.rows[m.selected].hasChildren {
+		return
+	}
+	id := m.rows[m.selected].id
+	if m.collapsed[id] {
+		m.collapsed[id] = false
+		m.rebuild()
+		return
+	}
+	m.MoveDown()
+}
+
+// Collapse closes the selected node, or moves to its parent when it is
+// already closed or has no children.
+func (m *EpicTreeModel) Collapse() {
+	if m.selected >= len(m.rows) {
+		return
+	}
+	row := m.rows[m.selected]
+	if row.hasChildren && !m.collapsed[row.id] {
+		m.collapsed[row.id] = true
+		m.rebuild()
+		return
+	}
+	parent, ok := m.hierarchy.Parent(row.id)
+	if !ok {
+		return
+	}
+	for i, r := range m.rows {
+		if r.id == parent {
+			m.selected = i
+			break
+		}
+	}
+	m.ensureVisible()
+}
+
+// SelectedIssueID returns the ID of the currently selected issue
+func (m *EpicTreeModel) SelectedIssueID() string {
+	if m.selected >= len(m.rows) {
+		return ""
+	}
+	return m.rows[m.selected].id
+}
+
+func (m *EpicTreeModel) visibleRows() int {
+	// Header, summary and the blank line after it.
+	return max(1, m.height-3)
+}
+
+func (m *EpicTreeModel) ensureVisible() {
+	if m.selected < m.scrollOffset {
+		m.scrollOffset = m.selected
+	}
+	if m.selected >= m.scrollOffset+m.visibleRows() {
+		m.scrollOffset = m.selected - m.visibleRows() + 1
+	}
+}
+
+// Render renders the epic tree
+func (m *EpicTreeModel) Render() string {
+	if m.width == 0 || m.height == 0 {
+		return ""
+	}
+	t := m.theme
+	var lines []string
+
+	headerStyle := t.Renderer.NewStyle().
+		Bold(true).
+		Foreground(t.Base.GetForeground()).
+		Background(t.Primary).
+		Padding(0, 2).
+		Width(m.width - 4)
+	lines = append(lines, headerStyle.Render(fmt.Sprintf("🌳 EPICS  │  %d epics • %d ready to close", len(m.report.Epics), len(m.report.ReadyToClose))))
+
+	mutedStyle := t.Renderer.NewStyle().Foreground(t.Subtext).Italic(true)
+	lines = append(lines, mutedStyle.Render(fmt.Sprintf("Progress weighted by estimate • forecasts at %.0f min/day • stale after %d days • space: fold",
+		m.report.VelocityMinutesPerDay, m.report.StaleThresholdDays)))
+	lines = append(lines, "")
+
+	if len(m.rows) == 0 {
+		emptyStyle := t.Renderer.NewStyle().
+			Foreground(t.Subtext).
+			Italic(true).
+			Padding(2, 4).
+			Width(m.width - 4).
+			Align(lipgloss.Center)
+		lines = append(lines, emptyStyle.Render("No parent-child hierarchy. Link children to an epic with a parent-child dependency."))
+		return strings.Join(lines, "\n")
+	}
+
+	// Columns: tree (marker, status, id, title) | progress | flags
+	const barWidth, flagsWidth = 10, 30
+	treeWidth := max(20, m.width-barWidth-flagsWidth-10)
+
+	idStyle := t.Renderer.NewStyle().Foreground(t.Secondary)
+	doneStyle := t.Renderer.NewStyle().Foreground(t.Closed)
+	todoStyle := t.Renderer.NewStyle().Foreground(t.Subtext)
+	warnStyle := t.Renderer.NewStyle().Foreground(t.Blocked)
+	readyStyle := t.Renderer.NewStyle().Foreground(t.Feature).Bold(true)
+
+	end := min(len(m.rows), m.scrollOffset+m.visibleRows())
+	for i := m.scrollOffset; i < end; i++ {
+		row := m.rows[i]
+		issue := m.issues[row.id]
+
+		marker := "  "
+		if row.hasChildren {
+			marker = "▾ "
+			if m.collapsed[row.id] {
+				marker = "▸ "
+			}
+		}
+		prefix := strings.Repeat("  ", row.depth) + marker
+		statusStyle := t.Renderer.NewStyle().Foreground(getStatusColor(issue.Status, t))
+		titleWidth := max(0, treeWidth-len([]rune(prefix))-len([]rune(row.id))-3)
+		tree := prefix + statusStyle.Render("●") + " " + idStyle.Render(row.id) + " " +
+			padRight(truncateRunesHelper(issue.Title, titleWidth, "…"), titleWidth)
+
+		line := tree
+		if r, ok := m.rollups[row.id]; ok {
+			filled := min(barWidth, int(r.PercentDone*float64(barWidth)/100+0.5))
+			bar := doneStyle.Render(strings.Repeat("█", filled)) + todoStyle.Render(strings.Repeat("░", barWidth-filled))
+			line += "  " + bar + fmt.Sprintf(" %3.0f%%", r.PercentDone)
+
+			var flags []string
+			if r.ReadyToClose {
+				flags = append(flags, readyStyle.Render("✓ ready to close"))
+			}
+			if n := len(r.BlockedChildren); n > 0 {
+				flags = append(flags, warnStyle.Render(fmt.Sprintf("⛔%d blocked", n)))
+			}
+			if n := len(r.StaleChildren); n > 0 {
+				flags = append(flags, warnStyle.Render(fmt.Sprintf("⏳%d stale", n)))
+			}
+			if r.ForecastDate != nil {
+				flags = append(flags, mutedStyle.Render("→ "+r.ForecastDate.Format("Jan 2")))
+			}
+			line += "  " + fmt.Sprintf("%d/%d ", r.Closed, r.Descendants) + strings.Join(flags, " ")
+		}
+
+		lineStyle := t.Renderer.NewStyle().Width(m.width - 2)
+		if i == m.selected {
+			lineStyle = lineStyle.Background(t.Highlight).Bold(true)
+		}
+		lines = append(lines, lineStyle.Render(line))
+	}
+
+	if len(lines) > m.height {
+		lines = lines[:m.height]
+	}
+	return strings.Join(lines, "\n")
+}
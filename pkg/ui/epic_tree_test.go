@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func epicTreeIssues() []model.Issue {
+	child := func(id, parent string, status model.Status) model.Issue {
+		return model.Issue{ID: id, Title: "Task " + id, Status: status, IssueType: model.TypeTask, UpdatedAt: time.Now(),
+			Dependencies: []*model.Dependency{{IssueID: id, DependsOnID: parent, Type: model.DepParentChild}}}
+	}
+	return []model.Issue{
+		{ID: "E1", Title: "Checkout", Status: model.StatusOpen, IssueType: model.TypeEpic, UpdatedAt: time.Now()},
+		child("t1", "E1", model.StatusClosed),
+		child("t2", "E1", model.StatusOpen),
+		{ID: "E2", Title: "Billing", Status: model.StatusOpen, IssueType: model.TypeEpic, UpdatedAt: time.Now()},
+		child("t3", "E2", model.StatusClosed),
+	}
+}
+
+func TestEpicTreeRenderAndFold(t *testing.T) {
+	issues := epicTreeIssues()
+	report := analysis.ComputeEpicRollups(issues, analysis.EpicOptions{Now: time.Now()})
+	m := NewEpicTreeModel(issues, report, newTestTheme())
+	m.SetSize(140, 30)
+
+	out := m.Render()
+	for _, want := range []string{"2 epics • 1 ready to close", "Checkout", "Task t2", "50%", "1/2", "ready to close"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("render missing %q:\n%s", want, out)
+		}
+	}
+
+	if got := m.SelectedIssueID(); got != "E1" {
+		t.Fatalf("initial selection = %s, want E1", got)
+	}
+	m.Collapse()
+	m.MoveDown()
+	if got := m.SelectedIssueID(); got != "E2" {
+		t.Fatalf("selection after folding E1 = %s, want E2", got)
+	}
+	m.Expand()
+	m.Expand()
+	if got := m.SelectedIssueID(); got != "t3" {
+		t.Fatalf("Expand on an open epic should step into it, got %s", got)
+	}
+	m.Collapse()
+	if got := m.SelectedIssueID(); got != "E2" {
+		t.Fatalf("Collapse on a leaf should select its parent, got %s", got)
+	}
+	m.Toggle()
+	if strings.Contains(m.Render(), "Task t3") {
+		t.Error("Toggle should fold E2")
+	}
+}
+
+func TestEpicTreeRenderEmpty(t *testing.T) {
+	m := NewEpicTreeModel(nil, analysis.ComputeEpicRollups(nil, analysis.EpicOptions{}), newTestTheme())
+	m.SetSize(100, 20)
+	if out := m.Render(); !strings.Contains(out, "No parent-child hierarchy") {
+		t.Fatalf("expected empty state message, got:\n%s", out)
+	}
+}
+
+func TestEpicTreeViewToggle(t *testing.T) {
+	m := NewModel(epicTreeIssues(), nil, "")
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("B")})
+	m = updated.(Model)
+	if !m.isEpicTreeView || m.focused != focusEpicTree {
+		t.Fatalf("B should open the epic tree (view=%v focus=%v)", m.isEpicTreeView, m.focused)
+	}
+	if !strings.Contains(m.View(), "EPICS") {
+		t.Errorf("epic tree not rendered")
+	}
+	// h folds the tree instead of opening the history view.
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("h")})
+	m = updated.(Model)
+	if m.isHistoryView || !m.epicTreeView.collapsed["E1"] {
+		t.Fatalf("h should fold the selected epic (history=%v)", m.isHistoryView)
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if m.isEpicTreeView || m.focused != focusList {
+		t.Fatalf("Esc should close the epic tree")
+	}
+}
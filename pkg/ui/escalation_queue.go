@@ -0,0 +1,272 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/ack"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/mutate"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// EscalationQueueModel lists the beads waiting on a lead (impossible,
+// escalated, or past their acknowledgment deadline), with the bounce history
+// and suggested re-assignees of the selected one.
+type EscalationQueueModel struct {
+	report       analysis.EscalationReport
+	rosterSource string
+	selected     int
+	scrollOffset int
+	width        int
+	height       int
+	theme        Theme
+}
+
+// NewEscalationQueueModel creates an escalation queue view
+func NewEscalationQueueModel(report analysis.EscalationReport, rosterSource string, theme Theme) EscalationQueueModel {
+	return EscalationQueueModel{report: report, rosterSource: rosterSource, theme: theme}
+}
+
+// SetReport replaces the queue after a reload, keeping the selected bead
+// selected while it is still queued.
+func (m *EscalationQueueModel) SetReport(report analysis.EscalationReport) {
+	selectedID := m.SelectedIssueID()
+	m.report = report
+	m.selected = 0
+	for i, it := range report.Items {
+		if it.ID == selectedID {
+			m.selected = i
+			break
+		}
+	}
+	if m.selected >= len(report.Items) {
+		m.selected = max(0, len(report.Items)-1)
+	}
+	m.ensureVisible()
+}
+
+// SetSize updates the view dimensions
+func (m *EscalationQueueModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// MoveUp moves selection up
+func (m *EscalationQueueModel) MoveUp() {
+	if m.selected > 0 {
+		m.selected--
+	}
+	m.ensureVisible()
+}
+
+// MoveDown moves selection down
+func (m *EscalationQueueModel) MoveDown() {
+	if m.selected < len(m.report.Items)-1 {
+		m.selected++
+	}
+	m.ensureVisible()
+}
+
+// SelectedItem returns the queue entry under the cursor
+func (m *EscalationQueueModel) SelectedItem() (analysis.EscalationItem, bool) {
+	if m.selected >= len(m.report.Items) {
+		return analysis.EscalationItem{}, false
+	}
+	return m.report.Items[m.selected], true
+}
+
+// SelectedIssueID returns the ID of the currently selected issue
+func (m *EscalationQueueModel) SelectedIssueID() string {
+	item, _ := m.SelectedItem()
+	return item.ID
+}
+
+// Suggestion returns the n-th (1-based) suggested re-assignee of the
+// selected bead.
+func (m *EscalationQueueModel) Suggestion(n int) (string, bool) {
+	item, ok := m.SelectedItem()
+	if !ok || n < 1 || n > len(item.Suggested) {
+		return "", false
+	}
+	return item.Suggested[n-1].Agent, true
+}
+
+// listRows is how many queue rows fit above the detail pane.
+func (m *EscalationQueueModel) listRows() int {
+	// Header, hint line and the blank line after it take three rows; the
+	// queue gets half of what remains.
+	return max(1, (m.height-3)/2)
+}
+
+func (m *EscalationQueueModel) ensureVisible() {
+	if m.selected < m.scrollOffset {
+		m.scrollOffset = m.selected
+	}
+	if m.selected >= m.scrollOffset+m.listRows() {
+		m.scrollOffset = m.selected - m.listRows() + 1
+	}
+}
+
+// Render renders the escalation queue
+func (m *EscalationQueueModel) Render() string {
+	if m.width == 0 || m.height == 0 {
+		return ""
+	}
+	t := m.theme
+	r := m.report
+	var lines []string
+
+	headerStyle := t.Renderer.NewStyle().
+		Bold(true).
+		Foreground(t.Base.GetForeground()).
+		Background(t.Primary).
+		Padding(0, 2).
+		Width(m.width - 4)
+	lines = append(lines, headerStyle.Render(fmt.Sprintf("🚨 ESCALATIONS  │  %d impossible • %d escalated • %d overdue",
+		r.Impossible, r.Escalated, r.Overdue)))
+
+	mutedStyle := t.Renderer.NewStyle().Foreground(t.Subtext).Italic(true)
+	lines = append(lines, mutedStyle.Render("r: requeue • 1-3: reassign to suggestion • e: expire missed deadlines • enter: open • re-assignees from "+m.rosterSource))
+	lines = append(lines, "")
+
+	if len(r.Items) == 0 {
+		emptyStyle := t.Renderer.NewStyle().
+			Foreground(t.Subtext).
+			Italic(true).
+			Padding(2, 4).
+			Width(m.width - 4).
+			Align(lipgloss.Center)
+		lines = append(lines, emptyStyle.Render("Nothing to escalate. Beads marked impossible, bounced too often, or past their ack deadline show up here."))
+		return strings.Join(lines, "\n")
+	}
+
+	idStyle := t.Renderer.NewStyle().Foreground(t.Secondary)
+	kindStyles := map[analysis.EscalationKind]lipgloss.Style{
+		analysis.EscalationImpossible: t.Renderer.NewStyle().Foreground(t.Blocked).Bold(true),
+		analysis.EscalationEscalated:  t.Renderer.NewStyle().Foreground(t.Feature).Bold(true),
+		analysis.EscalationOverdue:    t.Renderer.NewStyle().Foreground(t.InProgress).Bold(true),
+	}
+
+	// Columns: kind | id | title | assignee | bounces | overdue
+	const kindWidth, assigneeWidth, bounceWidth, overdueWidth = 12, 14, 8, 12
+	idWidth := 0
+	for _, it := range r.Items {
+		idWidth = max(idWidth, len([]rune(it.ID)))
+	}
+	titleWidth := max(10, m.width-kindWidth-idWidth-assigneeWidth-bounceWidth-overdueWidth-10)
+
+	end := min(len(r.Items), m.scrollOffset+m.listRows())
+	for i := m.scrollOffset; i < end; i++ {
+		it := r.Items[i]
+		overdue := ""
+		if it.OverdueHours > 0 {
+			overdue = "late " + formatDuration(time.Duration(it.OverdueHours*float64(time.Hour)))
+		}
+		assignee := it.Assignee
+		if assignee == "" {
+			assignee = "-"
+		}
+		line := kindStyles[it.Kind].Render(padRight(strings.ToUpper(string(it.Kind)), kindWidth)) + " " +
+			idStyle.Render(padRight(it.ID, idWidth)) + " " +
+			padRight(truncateRunesHelper(it.Title, titleWidth, "…"), titleWidth) + " " +
+			padRight(truncateRunesHelper(assignee, assigneeWidth, "…"), assigneeWidth) + " " +
+			padRight(fmt.Sprintf("↩%d", it.BounceCount), bounceWidth) + " " +
+			mutedStyle.Render(overdue)
+
+		lineStyle := t.Renderer.NewStyle().Width(m.width - 2)
+		if i == m.selected {
+			lineStyle = lineStyle.Background(t.Highlight).Bold(true)
+		}
+		lines = append(lines, lineStyle.Render(line))
+	}
+
+	lines = append(lines, mutedStyle.Render(strings.Repeat("─", max(0, m.width-2))))
+	lines = append(lines, m.renderDetail(mutedStyle)...)
+
+	if len(lines) > m.height {
+		lines = lines[:m.height]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderDetail shows why the selected bead is queued, its hand-off history
+// and who could take it.
+func (m *EscalationQueueModel) renderDetail(mutedStyle lipgloss.Style) []string {
+	t := m.theme
+	it, ok := m.SelectedItem()
+	if !ok {
+		return nil
+	}
+	labelStyle := t.Renderer.NewStyle().Foreground(t.Primary).Bold(true)
+
+	var lines []string
+	if it.LastReason != "" {
+		lines = append(lines, labelStyle.Render("Reason: ")+truncateRunesHelper(it.LastReason, max(10, m.width-12), "…"))
+	}
+
+	lines = append(lines, labelStyle.Render("History"))
+	if len(it.History) == 0 {
+		lines = append(lines, mutedStyle.Render("  no bd-ack hand-offs recorded"))
+	}
+	for _, ev := range it.History {
+		text := fmt.Sprintf("  %-9s %-10s %s", relativeTime(ev.At), ev.Kind, ev.Agent)
+		if ev.To != "" {
+			text += " → " + ev.To
+		}
+		if ev.Reason != "" {
+			text += ": " + ev.Reason
+		}
+		lines = append(lines, truncateRunesHelper(text, max(10, m.width-2), "…"))
+	}
+
+	lines = append(lines, labelStyle.Render("Suggested re-assignees"))
+	if len(it.Suggested) == 0 {
+		lines = append(lines, mutedStyle.Render("  nobody on the roster who has not already bounced it"))
+	}
+	for n, s := range it.Suggested {
+		text := fmt.Sprintf("  [%d] %-16s load %d/%d", n+1, s.Agent, s.Load, s.Capacity)
+		if len(s.MatchedSkills) > 0 {
+			text += "  skills: " + strings.Join(s.MatchedSkills, ", ")
+		}
+		lines = append(lines, text)
+	}
+	return lines
+}
+
+// RequeueCmd returns an escalated bead to the unassigned queue in the
+// background.
+func RequeueCmd(beadsPath, issueID string) tea.Cmd {
+	return func() tea.Msg {
+		_, err := ack.NewFileService(beadsPath).Requeue(issueID, mutate.DefaultActor(), "")
+		return EditResultMsg{IssueID: issueID, Summary: "returned to the queue", Err: err}
+	}
+}
+
+// ReassignCmd hands an escalated bead to agent in the background.
+func ReassignCmd(beadsPath, issueID, agent string) tea.Cmd {
+	return func() tea.Msg {
+		_, err := ack.NewFileService(beadsPath).Reassign(issueID, mutate.DefaultActor(), agent, nil)
+		return EditResultMsg{IssueID: issueID, Summary: "reassigned to " + agent, Err: err}
+	}
+}
+
+// EnforceDeadlinesCmd re-queues (or, at the bounce limit, escalates) every
+// assignment not acknowledged before its deadline.
+func EnforceDeadlinesCmd(beadsPath string) tea.Cmd {
+	return func() tea.Msg {
+		expired, err := ack.NewFileService(beadsPath).EnforceDeadlines(mutate.DefaultActor(), time.Now(), ack.ExpireRequeue)
+		ids := make([]string, len(expired))
+		for i, e := range expired {
+			ids[i] = e.IssueID
+		}
+		summary := "no missed deadlines"
+		if len(ids) > 0 {
+			summary = fmt.Sprintf("%d missed deadlines handled (%s)", len(ids), strings.Join(ids, ", "))
+		}
+		return EditResultMsg{IssueID: "deadlines", Summary: summary, Err: err}
+	}
+}
@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func escalationIssues() []model.Issue {
+	past := time.Now().Add(-5 * time.Hour)
+	return []model.Issue{
+		{ID: "imp", Title: "Needs prod", Status: model.StatusOpen, IssueType: model.TypeTask, Assignee: "bob",
+			AckStatus: model.AckStatusImpossible, Escalated: true,
+			Comments: []*model.Comment{{Author: "bob", Text: "[IMPOSSIBLE] No prod access - Escalated for strategic decision", CreatedAt: past}}},
+		{ID: "late", Title: "Slow ack", Status: model.StatusOpen, IssueType: model.TypeTask, Assignee: "carol", Deadline: &past},
+		{ID: "ok", Title: "Fine", Status: model.StatusInProgress, IssueType: model.TypeTask, Assignee: "alice", AckStatus: model.AckStatusAccepted},
+	}
+}
+
+func TestEscalationQueueRender(t *testing.T) {
+	report := analysis.ComputeEscalations(escalationIssues(), analysis.EscalationOptions{})
+	m := NewEscalationQueueModel(report, "assignees", newTestTheme())
+	m.SetSize(140, 30)
+
+	out := m.Render()
+	for _, want := range []string{"1 impossible • 0 escalated • 1 overdue", "IMPOSSIBLE", "Needs prod", "No prod access", "[1] alice", "late 5h"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("render missing %q:\n%s", want, out)
+		}
+	}
+	if agent, ok := m.Suggestion(1); !ok || agent != "alice" {
+		t.Errorf("Suggestion(1) = %q, %v", agent, ok)
+	}
+
+	m.MoveDown()
+	report = analysis.ComputeEscalations(escalationIssues()[1:], analysis.EscalationOptions{})
+	m.SetReport(report)
+	if got := m.SelectedIssueID(); got != "late" {
+		t.Errorf("SetReport should keep %q selected, got %q", "late", got)
+	}
+}
+
+func TestEscalationViewToggle(t *testing.T) {
+	m := NewModel(escalationIssues(), nil, "")
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("Q")})
+	m = updated.(Model)
+	if !m.isEscalationView || m.focused != focusEscalations {
+		t.Fatalf("Q should open the escalation queue (view=%v focus=%v)", m.isEscalationView, m.focused)
+	}
+	if !strings.Contains(m.View(), "ESCALATIONS") {
+		t.Errorf("escalation queue not rendered")
+	}
+	// Without a beads file there is nothing to write to.
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	m = updated.(Model)
+	if cmd != nil {
+		t.Errorf("requeue without a beads path should not issue a write")
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if m.isEscalationView || m.focused != focusList {
+		t.Fatalf("Esc should close the escalation queue")
+	}
+}
@@ -38,6 +38,16 @@ type GraphModel struct {
 	rankCriticalPath map[string]int
 	rankInDegree     map[string]int
 	rankOutDegree    map[string]int
+
+	// Layered full-DAG view (graph_dag.go)
+	layered    bool
+	zoom       graphZoom
+	labelScope string
+	layout     *dagLayout // nil when stale
+	panX, panY int
+	follow     bool // pan to the selection on the next render
+	jumping    bool
+	jumpQuery  string
 }
 
 // NewGraphModel creates a new graph view from issues
@@ -61,6 +71,7 @@ func (g *GraphModel) SetIssues(issues []model.Issue, insights *analysis.Insights
 
 	g.issues = issues
 	g.insights = insights
+	g.layout = nil
 	g.rebuildGraph()
 
 	// Restore selection
@@ -83,6 +94,7 @@ func (g *GraphModel) SetIssues(issues []model.Issue, insights *analysis.Insights
 			}
 		}
 	}
+	g.refreshLayout()
 }
 
 func (g *GraphModel) rebuildGraph() {
@@ -205,6 +217,10 @@ func (g *GraphModel) computeRankings() {
 
 // Navigation
 func (g *GraphModel) MoveUp() {
+	if g.layered {
+		g.moveLayer(-1)
+		return
+	}
 	if g.selectedIdx > 0 {
 		g.selectedIdx--
 		g.ensureVisible()
@@ -212,16 +228,41 @@ func (g *GraphModel) MoveUp() {
 }
 
 func (g *GraphModel) MoveDown() {
+	if g.layered {
+		g.moveLayer(1)
+		return
+	}
 	if g.selectedIdx < len(g.sortedIDs)-1 {
 		g.selectedIdx++
 		g.ensureVisible()
 	}
 }
 
-func (g *GraphModel) MoveLeft()  { g.MoveUp() }
-func (g *GraphModel) MoveRight() { g.MoveDown() }
+func (g *GraphModel) MoveLeft() {
+	if g.layered {
+		g.moveSideways(-1)
+		return
+	}
+	g.MoveUp()
+}
+
+func (g *GraphModel) MoveRight() {
+	if g.layered {
+		g.moveSideways(1)
+		return
+	}
+	g.MoveDown()
+}
 
+// PageUp moves the selection up ten beads; the layered view pans up half a
+// screen instead.
 func (g *GraphModel) PageUp() {
+	if g.layered {
+		_, vh := g.dagViewport()
+		g.follow = false
+		g.pan(0, -max(1, vh/2))
+		return
+	}
 	g.selectedIdx -= 10
 	if g.selectedIdx < 0 {
 		g.selectedIdx = 0
@@ -229,7 +270,15 @@ func (g *GraphModel) PageUp() {
 	g.ensureVisible()
 }
 
+// PageDown moves the selection down ten beads; the layered view pans down
+// half a screen instead.
 func (g *GraphModel) PageDown() {
+	if g.layered {
+		_, vh := g.dagViewport()
+		g.follow = false
+		g.pan(0, max(1, vh/2))
+		return
+	}
 	if len(g.sortedIDs) == 0 {
 		return
 	}
@@ -240,10 +289,46 @@ func (g *GraphModel) PageDown() {
 	g.ensureVisible()
 }
 
-func (g *GraphModel) ScrollLeft()  {}
-func (g *GraphModel) ScrollRight() {}
+// ScrollLeft pans the layered view left. The ego view always fits.
+func (g *GraphModel) ScrollLeft() {
+	if g.layered {
+		g.follow = false
+		g.pan(-dagPanStep, 0)
+	}
+}
+
+// ScrollRight pans the layered view right.
+func (g *GraphModel) ScrollRight() {
+	if g.layered {
+		g.follow = false
+		g.pan(dagPanStep, 0)
+	}
+}
+
+// ensureVisible brings the selection into view. The ego view's node list
+// scrolls itself while rendering; the layered view pans now if its size is
+// known, and on its next render otherwise.
+func (g *GraphModel) ensureVisible() {
+	if !g.layered {
+		return
+	}
+	if g.width > 0 && g.height > 0 {
+		g.ensureDAGVisible()
+		g.follow = false
+		return
+	}
+	g.follow = true
+}
 
-func (g *GraphModel) ensureVisible() {}
+// SetSize records the size View will be given, so the layered view can pan
+// while handling keys rather than only while rendering.
+func (g *GraphModel) SetSize(width, height int) {
+	g.width = width
+	g.height = height
+	if g.layered {
+		g.pan(0, 0)
+	}
+}
 
 func (g *GraphModel) SelectedIssue() *model.Issue {
 	if len(g.sortedIDs) == 0 {
@@ -253,16 +338,19 @@ func (g *GraphModel) SelectedIssue() *model.Issue {
 	return g.issueMap[id]
 }
 
-// SelectByID selects an issue by its ID (bv-xf4p)
+// SelectByID selects an issue by its ID (bv-xf4p). A bead outside the
+// layered view's label scope widens the scope to the whole graph.
 func (g *GraphModel) SelectByID(id string) bool {
-	for i, sortedID := range g.sortedIDs {
-		if sortedID == id {
-			g.selectedIdx = i
-			g.ensureVisible()
-			return true
-		}
+	if !g.selectID(id) {
+		return false
 	}
-	return false
+	if g.labelScope != "" && !g.inScope(id) {
+		g.labelScope = ""
+		g.layout = nil
+		g.refreshLayout()
+	}
+	g.ensureVisible()
+	return true
 }
 
 func (g *GraphModel) TotalCount() int {
@@ -284,6 +372,10 @@ func (g *GraphModel) View(width, height int) string {
 			Render("No issues to display")
 	}
 
+	if g.layered {
+		return g.renderLayered(width, height)
+	}
+
 	selectedID := g.sortedIDs[g.selectedIdx]
 	selectedIssue := g.issueMap[selectedID]
 	if selectedIssue == nil {
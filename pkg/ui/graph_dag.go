@@ -0,0 +1,716 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+)
+
+// graphZoom is how much of each bead the layered graph shows.
+type graphZoom int
+
+const (
+	graphZoomID    graphZoom = iota // ID only
+	graphZoomTitle                  // ID and title
+)
+
+const (
+	dagMaxIDWidth    = 16 // ID column cap at graphZoomID
+	dagTitleWidth    = 24 // text width at graphZoomTitle
+	dagPanStep       = 8  // columns per H/L
+	dagHeaderRows    = 1
+	dagFooterRows    = 2
+	dagJumpMaxLength = 64
+)
+
+// ToggleLayered switches between the ego view and the layered full-DAG view.
+func (g *GraphModel) ToggleLayered() {
+	g.layered = !g.layered
+	g.jumping = false
+	g.refreshLayout()
+	g.ensureVisible()
+}
+
+// IsLayered reports whether the layered full-DAG view is active.
+func (g *GraphModel) IsLayered() bool {
+	return g.layered
+}
+
+// CycleZoom switches the layered view between IDs only and IDs with titles.
+func (g *GraphModel) CycleZoom() {
+	if g.zoom == graphZoomID {
+		g.zoom = graphZoomTitle
+	} else {
+		g.zoom = graphZoomID
+	}
+	g.layout = nil
+	g.refreshLayout()
+	g.ensureVisible()
+}
+
+// CycleLabelScope narrows the layered view to the next label of the selected
+// bead, and back to the whole graph after the last one. It returns the new
+// scope, empty for the whole graph.
+func (g *GraphModel) CycleLabelScope() string {
+	issue := g.SelectedIssue()
+	if issue == nil {
+		return g.labelScope
+	}
+	labels := append([]string(nil), issue.Labels...)
+	sort.Strings(labels)
+	next := ""
+	if g.labelScope == "" {
+		if len(labels) > 0 {
+			next = labels[0]
+		}
+	} else {
+		for i, l := range labels {
+			if l == g.labelScope && i+1 < len(labels) {
+				next = labels[i+1]
+			}
+		}
+	}
+	g.labelScope = next
+	g.layout = nil
+	g.refreshLayout()
+	g.ensureVisible()
+	return next
+}
+
+// LabelScope returns the label the layered view is narrowed to, if any.
+func (g *GraphModel) LabelScope() string {
+	return g.labelScope
+}
+
+// StartJump opens the jump-to-bead prompt.
+func (g *GraphModel) StartJump() {
+	g.jumping = true
+	g.jumpQuery = ""
+}
+
+// Jumping reports whether the jump prompt is taking keys.
+func (g *GraphModel) Jumping() bool {
+	return g.jumping
+}
+
+// JumpKey feeds a key to the jump prompt. Enter selects the first bead whose
+// ID matches the query (exactly, then by prefix, then anywhere) and Esc
+// closes the prompt. It returns a status message when the jump fails.
+func (g *GraphModel) JumpKey(key string) string {
+	switch key {
+	case "esc":
+		g.jumping = false
+	case "enter":
+		g.jumping = false
+		if g.jumpQuery == "" {
+			return ""
+		}
+		if id := g.matchID(g.jumpQuery); id != "" {
+			g.SelectByID(id)
+			return ""
+		}
+		return fmt.Sprintf("No bead matches %q", g.jumpQuery)
+	case "backspace":
+		if _, size := utf8.DecodeLastRuneInString(g.jumpQuery); size > 0 {
+			g.jumpQuery = g.jumpQuery[:len(g.jumpQuery)-size]
+		}
+	default:
+		if utf8.RuneCountInString(key) == 1 && utf8.RuneCountInString(g.jumpQuery) < dagJumpMaxLength {
+			g.jumpQuery += key
+		}
+	}
+	return ""
+}
+
+func (g *GraphModel) matchID(query string) string {
+	q := strings.ToLower(query)
+	for _, match := range []func(id string) bool{
+		func(id string) bool { return id == q },
+		func(id string) bool { return strings.HasPrefix(id, q) },
+		func(id string) bool { return strings.Contains(id, q) },
+	} {
+		for _, id := range g.sortedIDs {
+			if match(strings.ToLower(id)) {
+				return id
+			}
+		}
+	}
+	return ""
+}
+
+// scopeIDs returns the beads the layered view lays out.
+func (g *GraphModel) scopeIDs() []string {
+	if g.labelScope == "" {
+		return g.sortedIDs
+	}
+	var ids []string
+	for _, id := range g.sortedIDs {
+		if issue := g.issueMap[id]; issue != nil && slices.Contains(issue.Labels, g.labelScope) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func (g *GraphModel) inScope(id string) bool {
+	if g.labelScope == "" {
+		return g.issueMap[id] != nil
+	}
+	issue := g.issueMap[id]
+	return issue != nil && slices.Contains(issue.Labels, g.labelScope)
+}
+
+// dagBoxWidth is the width of a bead's box at the current zoom.
+func (g *GraphModel) dagBoxWidth(ids []string) int {
+	if g.zoom == graphZoomTitle {
+		return dagTitleWidth + 4
+	}
+	widest := 1
+	for _, id := range ids {
+		widest = max(widest, runewidth.StringWidth(id))
+	}
+	return min(widest, dagMaxIDWidth) + 4
+}
+
+// dagBoxHeight is the height of a bead's box at the current zoom.
+func (g *GraphModel) dagBoxHeight() int {
+	if g.zoom == graphZoomTitle {
+		return 4
+	}
+	return 3
+}
+
+// refreshLayout builds the layered layout while the layered view is active.
+// It runs from the methods that change what the layout shows, on the live
+// model, because View renders a copy and anything it caches is thrown away.
+func (g *GraphModel) refreshLayout() {
+	if g.layered {
+		g.ensureLayout()
+	}
+}
+
+// ensureLayout builds the layered layout if it is stale, and keeps the
+// selection inside it.
+func (g *GraphModel) ensureLayout() *dagLayout {
+	if g.layout == nil {
+		ids := g.scopeIDs()
+		boxWidth := g.dagBoxWidth(ids)
+		g.layout = layoutDAG(ids, g.blockers, func(string) int { return boxWidth }, g.dagBoxHeight())
+	}
+	if len(g.sortedIDs) > 0 && len(g.layout.layers) > 0 {
+		if _, ok := g.layout.index[g.sortedIDs[g.selectedIdx]]; !ok {
+			g.selectID(g.layout.nodes[g.layout.layers[0][0]].id)
+		}
+	}
+	return g.layout
+}
+
+func (g *GraphModel) selectID(id string) bool {
+	for i, sortedID := range g.sortedIDs {
+		if sortedID == id {
+			g.selectedIdx = i
+			return true
+		}
+	}
+	return false
+}
+
+// selectedNode returns the layout node of the selection.
+func (g *GraphModel) selectedNode() (*dagLayout, int, bool) {
+	l := g.ensureLayout()
+	if len(g.sortedIDs) == 0 {
+		return l, 0, false
+	}
+	v, ok := l.index[g.sortedIDs[g.selectedIdx]]
+	return l, v, ok
+}
+
+// moveLayer selects a bead in the layer above (dir < 0) or below, preferring
+// the selection's direct blockers or dependents, then the nearest by column.
+func (g *GraphModel) moveLayer(dir int) {
+	l, v, ok := g.selectedNode()
+	if !ok {
+		return
+	}
+	cur := l.nodes[v]
+	target := cur.layer + dir
+	if target < 0 || target >= len(l.layers) {
+		return
+	}
+	direct := make(map[string]bool)
+	related := g.dependents[cur.id]
+	if dir < 0 {
+		related = g.blockers[cur.id]
+	}
+	for _, id := range related {
+		direct[id] = true
+	}
+	best, bestDirect, bestDist := -1, false, 0
+	for _, w := range l.layers[target] {
+		n := l.nodes[w]
+		if n.dummy() {
+			continue
+		}
+		dist := n.center() - cur.center()
+		if dist < 0 {
+			dist = -dist
+		}
+		isDirect := direct[n.id]
+		if best < 0 || (isDirect && !bestDirect) || (isDirect == bestDirect && dist < bestDist) {
+			best, bestDirect, bestDist = w, isDirect, dist
+		}
+	}
+	if best >= 0 {
+		g.selectID(l.nodes[best].id)
+		g.ensureVisible()
+	}
+}
+
+// moveSideways selects the next bead to the left (dir < 0) or right in the
+// selection's layer.
+func (g *GraphModel) moveSideways(dir int) {
+	l, v, ok := g.selectedNode()
+	if !ok {
+		return
+	}
+	row := l.layers[l.nodes[v].layer]
+	at := 0
+	for i, w := range row {
+		if w == v {
+			at = i
+		}
+	}
+	for i := at + dir; i >= 0 && i < len(row); i += dir {
+		if n := l.nodes[row[i]]; !n.dummy() {
+			g.selectID(n.id)
+			g.ensureVisible()
+			return
+		}
+	}
+}
+
+// dagViewport is the size of the canvas area of the layered view.
+func (g *GraphModel) dagViewport() (int, int) {
+	return max(1, g.width), max(1, g.height-dagHeaderRows-dagFooterRows)
+}
+
+// pan moves the layered view by dx columns and dy rows, within the canvas.
+func (g *GraphModel) pan(dx, dy int) {
+	l := g.ensureLayout()
+	vw, vh := g.dagViewport()
+	g.panX = max(0, min(g.panX+dx, l.width-vw))
+	g.panY = max(0, min(g.panY+dy, l.height-vh))
+}
+
+// ensureDAGVisible pans the layered view so the selected box is on screen,
+// centring it when it was off screen.
+func (g *GraphModel) ensureDAGVisible() {
+	l, v, ok := g.selectedNode()
+	if !ok {
+		return
+	}
+	n := l.nodes[v]
+	vw, vh := g.dagViewport()
+	boxHeight := l.boxHeight
+	top := l.tops[n.layer]
+	if n.x < g.panX || n.x+n.width > g.panX+vw {
+		g.panX = n.center() - vw/2
+	}
+	if top < g.panY || top+boxHeight > g.panY+vh {
+		g.panY = top + boxHeight/2 - vh/2
+	}
+	g.pan(0, 0)
+}
+
+// Canvas cells. Edge cells carry a mask of the directions they connect;
+// text and box cells carry a rune.
+const (
+	dagUp uint8 = 1 << iota
+	dagDown
+	dagLeft
+	dagRight
+)
+
+// dagStyle is the colour class of a canvas cell. Edge classes are ordered by
+// precedence: where edges overlap the higher one wins.
+type dagStyle uint8
+
+const (
+	dagStyleNone dagStyle = iota
+	dagStyleEdge
+	dagStyleCritical
+	dagStyleCycle
+	dagStyleSelected
+	dagStyleOpen
+	dagStyleInProgress
+	dagStyleBlocked
+	dagStyleClosed
+	dagStyleOther
+)
+
+type dagCell struct {
+	r     rune
+	mask  uint8
+	cont  bool // right half of a wide rune
+	style dagStyle
+}
+
+// dagCanvas is the visible w×h window at (x0, y0) of the layout; drawing
+// outside it is dropped, so large graphs only pay for what is on screen.
+type dagCanvas struct {
+	x0, y0 int
+	w, h   int
+	cells  [][]dagCell
+}
+
+func newDAGCanvas(x0, y0, w, h int) *dagCanvas {
+	c := &dagCanvas{x0: x0, y0: y0, w: w, h: h, cells: make([][]dagCell, h)}
+	for y := range c.cells {
+		c.cells[y] = make([]dagCell, w)
+	}
+	return c
+}
+
+func (c *dagCanvas) in(x, y int) bool {
+	return x >= c.x0 && y >= c.y0 && x < c.x0+c.w && y < c.y0+c.h
+}
+
+// hline and vline add a run of edge cells, clipped to the window.
+func (c *dagCanvas) hline(from, to, y int, style dagStyle) {
+	for x := max(from, c.x0); x <= min(to, c.x0+c.w-1); x++ {
+		c.edge(x, y, dagLeft|dagRight, style)
+	}
+}
+
+func (c *dagCanvas) vline(x, from, to int, style dagStyle) {
+	for y := max(from, c.y0); y <= min(to, c.y0+c.h-1); y++ {
+		c.edge(x, y, dagUp|dagDown, style)
+	}
+}
+
+// edge adds directions to an edge cell.
+func (c *dagCanvas) edge(x, y int, mask uint8, style dagStyle) {
+	if !c.in(x, y) {
+		return
+	}
+	cell := &c.cells[y-c.y0][x-c.x0]
+	cell.mask |= mask
+	if style > cell.style {
+		cell.style = style
+	}
+}
+
+func (c *dagCanvas) set(x, y int, r rune, style dagStyle) {
+	if c.in(x, y) {
+		c.cells[y-c.y0][x-c.x0] = dagCell{r: r, style: style}
+	}
+}
+
+// text writes s from column x, wide runes taking two cells.
+func (c *dagCanvas) text(x, y int, s string, style dagStyle) {
+	for _, r := range s {
+		w := runewidth.RuneWidth(r)
+		if w == 0 {
+			continue
+		}
+		c.set(x, y, r, style)
+		if w == 2 && c.in(x+1, y) {
+			c.cells[y-c.y0][x+1-c.x0] = dagCell{cont: true, style: style}
+		}
+		x += w
+	}
+}
+
+func dagMaskRune(mask uint8) rune {
+	switch mask {
+	case dagUp, dagDown, dagUp | dagDown:
+		return '│'
+	case dagLeft, dagRight, dagLeft | dagRight:
+		return '─'
+	case dagDown | dagRight:
+		return '╭'
+	case dagDown | dagLeft:
+		return '╮'
+	case dagUp | dagRight:
+		return '╰'
+	case dagUp | dagLeft:
+		return '╯'
+	case dagUp | dagDown | dagRight:
+		return '├'
+	case dagUp | dagDown | dagLeft:
+		return '┤'
+	case dagLeft | dagRight | dagDown:
+		return '┬'
+	case dagLeft | dagRight | dagUp:
+		return '┴'
+	default:
+		return '┼'
+	}
+}
+
+// dagBorder is a box outline: corners, sides and the junction used where
+// edges leave the bottom of the box.
+type dagBorder struct {
+	tl, tr, bl, br, h, v, junction rune
+}
+
+var (
+	dagRoundedBorder = dagBorder{'╭', '╮', '╰', '╯', '─', '│', '┬'}
+	dagHeavyBorder   = dagBorder{'┏', '┓', '┗', '┛', '━', '┃', '┯'}
+	dagDoubleBorder  = dagBorder{'╔', '╗', '╚', '╝', '═', '║', '╤'}
+)
+
+func dagStatusStyle(status model.Status) dagStyle {
+	switch status {
+	case model.StatusOpen:
+		return dagStyleOpen
+	case model.StatusInProgress:
+		return dagStyleInProgress
+	case model.StatusBlocked:
+		return dagStyleBlocked
+	case model.StatusClosed:
+		return dagStyleClosed
+	default:
+		return dagStyleOther
+	}
+}
+
+// drawDAG paints the w×h window at (x0, y0) of the layout: boxes for beads,
+// vertical runs for dummies and edges routed through the rows between
+// layers.
+func (g *GraphModel) drawDAG(l *dagLayout, x0, y0, w, h int) *dagCanvas {
+	boxHeight := l.boxHeight
+	c := newDAGCanvas(x0, y0, max(0, min(w, l.width-x0)), max(0, min(h, l.height-y0)))
+	selectedID := ""
+	if len(g.sortedIDs) > 0 {
+		selectedID = g.sortedIDs[g.selectedIdx]
+	}
+
+	for _, s := range l.segments {
+		style := dagStyleEdge
+		if s.critical {
+			style = dagStyleCritical
+		}
+		if s.cycle {
+			style = dagStyleCycle
+		}
+		from, to := l.nodes[s.from], l.nodes[s.to]
+		fx, tx := from.center(), to.center()
+		first := l.tops[from.layer] + boxHeight // stem row
+		last := l.tops[to.layer] - 1            // arrow row
+		bend := first + 1 + s.track
+
+		if last < c.y0 || first >= c.y0+c.h {
+			continue
+		}
+
+		if fx == tx {
+			c.vline(fx, first, last, style)
+		} else {
+			c.vline(fx, first, bend-1, style)
+			toward, back := dagRight, dagLeft
+			if tx < fx {
+				toward, back = dagLeft, dagRight
+			}
+			c.edge(fx, bend, dagUp|toward, style)
+			c.edge(tx, bend, dagDown|back, style)
+			c.hline(min(fx, tx)+1, max(fx, tx)-1, bend, style)
+			c.vline(tx, bend+1, last, style)
+		}
+		if to.dummy() {
+			c.vline(tx, l.tops[to.layer], l.tops[to.layer]+boxHeight-1, style)
+		}
+		if s.arrowDown {
+			c.set(tx, last, '▼', style)
+		}
+		if s.arrowUp {
+			c.set(fx, first, '▲', style)
+		}
+	}
+
+	for _, n := range l.nodes {
+		top := l.tops[n.layer]
+		if n.dummy() || n.x >= c.x0+c.w || n.x+n.width <= c.x0 || top >= c.y0+c.h || top+boxHeight <= c.y0 {
+			continue
+		}
+		issue := g.issueMap[n.id]
+		textStyle, borderStyle, border := dagStyleOther, dagStyleOther, dagRoundedBorder
+		if issue != nil {
+			textStyle = dagStatusStyle(issue.Status)
+			borderStyle = textStyle
+		}
+		if l.critical[n.id] {
+			borderStyle, border = dagStyleCritical, dagHeavyBorder
+		}
+		if n.id == selectedID {
+			textStyle, borderStyle, border = dagStyleSelected, dagStyleSelected, dagDoubleBorder
+		}
+
+		bottom := top + boxHeight - 1
+		right := n.x + n.width - 1
+		c.set(n.x, top, border.tl, borderStyle)
+		c.set(right, top, border.tr, borderStyle)
+		c.set(n.x, bottom, border.bl, borderStyle)
+		c.set(right, bottom, border.br, borderStyle)
+		for x := n.x + 1; x < right; x++ {
+			c.set(x, top, border.h, borderStyle)
+			c.set(x, bottom, border.h, borderStyle)
+		}
+		for y := top + 1; y < bottom; y++ {
+			c.set(n.x, y, border.v, borderStyle)
+			c.set(right, y, border.v, borderStyle)
+		}
+		if len(n.down) > 0 {
+			c.set(n.center(), bottom, border.junction, borderStyle)
+		}
+
+		inner := n.width - 4
+		lines := []string{smartTruncateID(n.id, inner)}
+		if g.zoom == graphZoomTitle {
+			title := ""
+			if issue != nil {
+				title = truncateRunesHelper(issue.Title, inner, "…")
+			}
+			lines = append(lines, title)
+		}
+		for i, line := range lines {
+			pad := (inner - runewidth.StringWidth(line)) / 2
+			c.text(n.x+2+pad, top+1+i, line, textStyle)
+		}
+	}
+	return c
+}
+
+// render returns the canvas as styled lines.
+func (c *dagCanvas) render(styles map[dagStyle]lipgloss.Style) []string {
+	lines := make([]string, 0, c.h)
+	for y := 0; y < c.h; y++ {
+		var b strings.Builder
+		var run strings.Builder
+		runStyle := dagStyleNone
+		flush := func() {
+			if run.Len() == 0 {
+				return
+			}
+			if style, ok := styles[runStyle]; ok {
+				b.WriteString(style.Render(run.String()))
+			} else {
+				b.WriteString(run.String())
+			}
+			run.Reset()
+		}
+		for x := 0; x < c.w; x++ {
+			cell := c.cells[y][x]
+			r := cell.r
+			switch {
+			case cell.cont:
+				if x > 0 {
+					continue
+				}
+				r = ' '
+			case r == 0 && cell.mask != 0:
+				r = dagMaskRune(cell.mask)
+			case r == 0:
+				r = ' '
+			case runewidth.RuneWidth(r) == 2 && x+1 >= c.w:
+				r = ' '
+			}
+			style := cell.style
+			if r == ' ' {
+				style = dagStyleNone
+			}
+			if style != runStyle {
+				flush()
+				runStyle = style
+			}
+			run.WriteRune(r)
+		}
+		flush()
+		lines = append(lines, b.String())
+	}
+	return lines
+}
+
+// renderLayered renders the layered full-DAG view: a header, the panned
+// canvas, the selected bead and the key hints (or the jump prompt).
+func (g *GraphModel) renderLayered(width, height int) string {
+	t := g.theme
+	l := g.layout
+	if l == nil {
+		l = g.ensureLayout()
+	}
+	vw, vh := g.dagViewport()
+	if g.follow {
+		g.ensureDAGVisible()
+		g.follow = false
+	}
+	g.pan(0, 0)
+
+	scope := "all beads"
+	if g.labelScope != "" {
+		scope = "label " + g.labelScope
+	}
+	zoom := "ids"
+	if g.zoom == graphZoomTitle {
+		zoom = "ids + titles"
+	}
+	headerStyle := t.Renderer.NewStyle().Bold(true).Foreground(t.Primary)
+	mutedStyle := t.Renderer.NewStyle().Foreground(t.Secondary).Italic(true)
+	header := headerStyle.Render("🕸 DEPENDENCY DAG") + mutedStyle.Render(fmt.Sprintf(
+		"  %d beads • %d layers • %s • zoom: %s", len(l.index), len(l.layers), scope, zoom))
+	if l.cycleEdges > 0 {
+		header += t.Renderer.NewStyle().Foreground(t.Blocked).Bold(true).
+			Render(fmt.Sprintf("  ⚠ %d cycle edges", l.cycleEdges))
+	}
+
+	var lines []string
+	lines = append(lines, header)
+	if len(l.layers) == 0 {
+		lines = append(lines, mutedStyle.Render("No beads carry label "+g.labelScope+" (s: change scope)"))
+	} else {
+		styles := map[dagStyle]lipgloss.Style{
+			dagStyleEdge:       t.Renderer.NewStyle().Foreground(t.Secondary),
+			dagStyleCritical:   t.Renderer.NewStyle().Foreground(t.Feature).Bold(true),
+			dagStyleCycle:      t.Renderer.NewStyle().Foreground(t.Blocked).Bold(true),
+			dagStyleSelected:   t.Renderer.NewStyle().Foreground(t.Primary).Background(t.Highlight).Bold(true),
+			dagStyleOpen:       t.Renderer.NewStyle().Foreground(t.Open),
+			dagStyleInProgress: t.Renderer.NewStyle().Foreground(t.InProgress),
+			dagStyleBlocked:    t.Renderer.NewStyle().Foreground(t.Blocked),
+			dagStyleClosed:     t.Renderer.NewStyle().Foreground(t.Closed),
+			dagStyleOther:      t.Renderer.NewStyle().Foreground(t.Secondary),
+		}
+		lines = append(lines, g.drawDAG(l, g.panX, g.panY, vw, vh).render(styles)...)
+	}
+	for len(lines) < dagHeaderRows+vh {
+		lines = append(lines, "")
+	}
+
+	if issue := g.SelectedIssue(); issue != nil {
+		info := fmt.Sprintf("▶ %s  %s  ⬆%d ⬇%d  %s", issue.ID, issue.Status,
+			len(g.blockers[issue.ID]), len(g.dependents[issue.ID]), issue.Title)
+		if l.critical[issue.ID] {
+			info += "  • on critical path"
+		}
+		lines = append(lines, truncateRunesHelper(info, max(1, width), "…"))
+	} else {
+		lines = append(lines, "")
+	}
+	if g.jumping {
+		lines = append(lines, headerStyle.Render("Jump to: ")+g.jumpQuery+"█"+mutedStyle.Render("  (enter: go • esc: cancel)"))
+	} else {
+		hint := "hjkl: move • H/L PgUp/PgDn: pan • z: zoom • s: scope • /: jump • d: ego view • enter: details"
+		lines = append(lines, mutedStyle.Render(truncateRunesHelper(hint, max(1, width), "…")))
+	}
+
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,474 @@
+package ui
+
+import "sort"
+
+// dagNode is one slot of the layered layout: an issue, or a dummy that
+// carries a long edge through an intermediate layer.
+type dagNode struct {
+	id    string // empty for dummies
+	layer int
+	x     int // leftmost column
+	width int
+	up    []int // connected nodes one layer up
+	down  []int // connected nodes one layer down
+}
+
+func (n dagNode) dummy() bool { return n.id == "" }
+func (n dagNode) center() int { return n.x + n.width/2 }
+
+// dagSegment is one hop of an edge between adjacent layers; from is always
+// the upper node.
+type dagSegment struct {
+	from, to int
+	// arrowDown marks the last hop into the blocked issue. Cycle edges that
+	// had to be laid out upwards get arrowUp on their first hop instead.
+	arrowDown, arrowUp bool
+	cycle              bool // the edge is part of a dependency cycle
+	critical           bool // the edge lies on a longest dependency chain
+	track              int  // row of the horizontal run within the channel
+}
+
+// dagLayout is a Sugiyama-style layered drawing of a dependency graph.
+// Blockers sit above the issues they block.
+type dagLayout struct {
+	nodes    []dagNode
+	layers   [][]int        // node indices per layer, left to right
+	index    map[string]int // issue ID -> node index
+	segments []dagSegment
+	width    int // columns spanned by the widest layer
+
+	boxHeight int
+	tops      []int // first row of each layer
+	height    int   // rows spanned by all layers
+
+	critical   map[string]bool // issues on a longest dependency chain
+	cycleEdges int
+}
+
+// dagGap is the number of blank columns between neighbouring slots.
+const dagGap = 2
+
+// dagSweeps is the number of barycenter sweep pairs used to untangle layers.
+const dagSweeps = 4
+
+// A channel between two layers has a row for the stems leaving the upper
+// boxes, one row per track of horizontal runs, and a row for the arrows.
+const dagChannelRows = 2
+
+// layoutDAG lays out ids and the blocking edges among them. Cycles are broken
+// by reversing DFS back edges, every issue goes one layer below its deepest
+// blocker, long edges get a dummy node per intermediate layer, barycenter
+// sweeps reduce crossings and nodes are then pulled towards their
+// neighbours. ids seed every ordering, so the layout is deterministic.
+func layoutDAG(ids []string, blockers map[string][]string, nodeWidth func(id string) int, boxHeight int) *dagLayout {
+	n := len(ids)
+	l := &dagLayout{index: make(map[string]int, n), critical: make(map[string]bool), boxHeight: boxHeight}
+	for i, id := range ids {
+		l.index[id] = i
+	}
+
+	// out[u] lists the issues u blocks.
+	out := make([][]int, n)
+	seen := make(map[[2]int]bool)
+	for v, id := range ids {
+		for _, b := range blockers[id] {
+			u, ok := l.index[b]
+			if !ok || u == v || seen[[2]int{u, v}] {
+				continue
+			}
+			seen[[2]int{u, v}] = true
+			out[u] = append(out[u], v)
+		}
+	}
+	for _, o := range out {
+		sort.Ints(o)
+	}
+
+	comp := dagComponents(out)
+	reversed := dagBackEdges(out)
+
+	// Acyclic view of the graph: back edges point the other way.
+	type edge struct {
+		from, to       int
+		cycle, reverse bool
+	}
+	var edges []edge
+	dagOut := make([][]int, n)
+	indeg := make([]int, n)
+	for u := range out {
+		for _, v := range out[u] {
+			e := edge{from: u, to: v, cycle: comp[u] == comp[v]}
+			if reversed[[2]int{u, v}] {
+				e = edge{from: v, to: u, cycle: true, reverse: true}
+			}
+			if e.cycle {
+				l.cycleEdges++
+			}
+			edges = append(edges, e)
+			dagOut[e.from] = append(dagOut[e.from], e.to)
+			indeg[e.to]++
+		}
+	}
+
+	// Longest-path layering in topological order.
+	layer := make([]int, n)
+	topo := make([]int, 0, n)
+	for v := range ids {
+		if indeg[v] == 0 {
+			topo = append(topo, v)
+		}
+	}
+	for i := 0; i < len(topo); i++ {
+		u := topo[i]
+		for _, v := range dagOut[u] {
+			layer[v] = max(layer[v], layer[u]+1)
+			if indeg[v]--; indeg[v] == 0 {
+				topo = append(topo, v)
+			}
+		}
+	}
+
+	// Critical path: nodes whose longest chain above plus below is the
+	// longest chain overall.
+	below := make([]int, n)
+	for i := len(topo) - 1; i >= 0; i-- {
+		u := topo[i]
+		for _, v := range dagOut[u] {
+			below[u] = max(below[u], below[v]+1)
+		}
+	}
+	longest := 0
+	for v := range ids {
+		longest = max(longest, layer[v]+below[v])
+	}
+	onPath := func(v int) bool { return longest > 0 && layer[v]+below[v] == longest }
+	for v, id := range ids {
+		if onPath(v) {
+			l.critical[id] = true
+		}
+	}
+
+	// Real nodes first, in topological order, then one dummy per
+	// intermediate layer of each long edge.
+	if n > 0 {
+		depth := 0
+		for _, ly := range layer {
+			depth = max(depth, ly)
+		}
+		l.layers = make([][]int, depth+1)
+	}
+	l.nodes = make([]dagNode, n, 2*n)
+	for v, id := range ids {
+		l.nodes[v] = dagNode{id: id, layer: layer[v], width: nodeWidth(id)}
+	}
+	for _, v := range topo {
+		l.layers[layer[v]] = append(l.layers[layer[v]], v)
+	}
+	rank := make([]int, n)
+	for i, v := range topo {
+		rank[v] = i
+	}
+	sort.SliceStable(edges, func(i, j int) bool { return rank[edges[i].from] < rank[edges[j].from] })
+	for _, e := range edges {
+		critical := onPath(e.from) && onPath(e.to) && layer[e.to] == layer[e.from]+1 && below[e.from] == below[e.to]+1
+		prev := e.from
+		for ly := layer[e.from] + 1; ly <= layer[e.to]; ly++ {
+			next := e.to
+			if ly < layer[e.to] {
+				next = len(l.nodes)
+				l.nodes = append(l.nodes, dagNode{layer: ly, width: 1})
+				l.layers[ly] = append(l.layers[ly], next)
+			}
+			l.nodes[prev].down = append(l.nodes[prev].down, next)
+			l.nodes[next].up = append(l.nodes[next].up, prev)
+			l.segments = append(l.segments, dagSegment{
+				from:      prev,
+				to:        next,
+				arrowDown: !e.reverse && next == e.to,
+				arrowUp:   e.reverse && prev == e.from,
+				cycle:     e.cycle,
+				critical:  critical,
+			})
+			prev = next
+		}
+	}
+
+	l.orderLayers()
+	l.assignColumns()
+	l.assignTracks()
+	return l
+}
+
+// dagComponents returns the strongly connected component of every node
+// (Tarjan).
+func dagComponents(out [][]int) []int {
+	n := len(out)
+	index := make([]int, n)
+	low := make([]int, n)
+	onStack := make([]bool, n)
+	comp := make([]int, n)
+	for i := range index {
+		index[i] = -1
+	}
+	var stack []int
+	next, count := 0, 0
+	var visit func(v int)
+	visit = func(v int) {
+		index[v], low[v] = next, next
+		next++
+		stack = append(stack, v)
+		onStack[v] = true
+		for _, w := range out[v] {
+			if index[w] < 0 {
+				visit(w)
+				low[v] = min(low[v], low[w])
+			} else if onStack[w] {
+				low[v] = min(low[v], index[w])
+			}
+		}
+		if low[v] == index[v] {
+			for {
+				w := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[w] = false
+				comp[w] = count
+				if w == v {
+					break
+				}
+			}
+			count++
+		}
+	}
+	for v := range out {
+		if index[v] < 0 {
+			visit(v)
+		}
+	}
+	return comp
+}
+
+// dagBackEdges returns the edges that close a cycle in a depth-first walk;
+// reversing them makes the graph acyclic.
+func dagBackEdges(out [][]int) map[[2]int]bool {
+	const (
+		unvisited = iota
+		active
+		done
+	)
+	state := make([]int, len(out))
+	back := make(map[[2]int]bool)
+	var visit func(u int)
+	visit = func(u int) {
+		state[u] = active
+		for _, v := range out[u] {
+			switch state[v] {
+			case unvisited:
+				visit(v)
+			case active:
+				back[[2]int{u, v}] = true
+			}
+		}
+		state[u] = done
+	}
+	for u := range out {
+		if state[u] == unvisited {
+			visit(u)
+		}
+	}
+	return back
+}
+
+// orderLayers reorders each layer by the barycenter of its neighbours,
+// sweeping down then up, and keeps the ordering with the fewest crossings.
+func (l *dagLayout) orderLayers() {
+	best := l.cloneLayers()
+	bestCrossings := l.crossings()
+	for sweep := 0; sweep < dagSweeps && bestCrossings > 0; sweep++ {
+		for ly := 1; ly < len(l.layers); ly++ {
+			l.sortByBarycenter(ly, func(n dagNode) []int { return n.up })
+		}
+		for ly := len(l.layers) - 2; ly >= 0; ly-- {
+			l.sortByBarycenter(ly, func(n dagNode) []int { return n.down })
+		}
+		if c := l.crossings(); c < bestCrossings {
+			best, bestCrossings = l.cloneLayers(), c
+		}
+	}
+	l.layers = best
+}
+
+func (l *dagLayout) cloneLayers() [][]int {
+	out := make([][]int, len(l.layers))
+	for i, ly := range l.layers {
+		out[i] = append([]int(nil), ly...)
+	}
+	return out
+}
+
+// positions maps every node to its index within its layer.
+func (l *dagLayout) positions() []int {
+	pos := make([]int, len(l.nodes))
+	for _, ly := range l.layers {
+		for i, v := range ly {
+			pos[v] = i
+		}
+	}
+	return pos
+}
+
+func (l *dagLayout) sortByBarycenter(ly int, neighbours func(dagNode) []int) {
+	pos := l.positions()
+	bary := make(map[int]float64, len(l.layers[ly]))
+	for _, v := range l.layers[ly] {
+		adj := neighbours(l.nodes[v])
+		if len(adj) == 0 {
+			bary[v] = float64(pos[v])
+			continue
+		}
+		sum := 0
+		for _, w := range adj {
+			sum += pos[w]
+		}
+		bary[v] = float64(sum) / float64(len(adj))
+	}
+	sort.SliceStable(l.layers[ly], func(i, j int) bool {
+		return bary[l.layers[ly][i]] < bary[l.layers[ly][j]]
+	})
+}
+
+// crossings counts pairs of segments that cross between adjacent layers.
+func (l *dagLayout) crossings() int {
+	pos := l.positions()
+	byLayer := make([][]dagSegment, len(l.layers))
+	for _, s := range l.segments {
+		ly := l.nodes[s.from].layer
+		byLayer[ly] = append(byLayer[ly], s)
+	}
+	total := 0
+	for _, segs := range byLayer {
+		for i := range segs {
+			for j := i + 1; j < len(segs); j++ {
+				a, b := segs[i], segs[j]
+				if (pos[a.from]-pos[b.from])*(pos[a.to]-pos[b.to]) < 0 {
+					total++
+				}
+			}
+		}
+	}
+	return total
+}
+
+// assignColumns packs each layer left to right, then pulls every node
+// towards the mean centre of its neighbours, first from above and then from
+// below, without letting nodes overlap.
+func (l *dagLayout) assignColumns() {
+	for _, ly := range l.layers {
+		x := 0
+		for _, v := range ly {
+			l.nodes[v].x = x
+			x += l.nodes[v].width + dagGap
+		}
+	}
+	for pass := 0; pass < 2; pass++ {
+		for ly := 1; ly < len(l.layers); ly++ {
+			l.alignLayer(ly, func(n dagNode) []int { return n.up })
+		}
+		for ly := len(l.layers) - 2; ly >= 0; ly-- {
+			l.alignLayer(ly, func(n dagNode) []int { return n.down })
+		}
+	}
+
+	minX := 0
+	for i, n := range l.nodes {
+		if i == 0 || n.x < minX {
+			minX = n.x
+		}
+	}
+	l.width = 0
+	for i := range l.nodes {
+		l.nodes[i].x -= minX
+		l.width = max(l.width, l.nodes[i].x+l.nodes[i].width)
+	}
+}
+
+func (l *dagLayout) alignLayer(ly int, neighbours func(dagNode) []int) {
+	next := 0
+	for _, v := range l.layers[ly] {
+		n := &l.nodes[v]
+		want := n.x
+		if adj := neighbours(*n); len(adj) > 0 {
+			sum := 0
+			for _, w := range adj {
+				sum += l.nodes[w].center()
+			}
+			want = sum/len(adj) - n.width/2
+		}
+		n.x = max(want, next)
+		next = n.x + n.width + dagGap
+	}
+}
+
+// assignTracks routes the horizontal run of every bending segment on a row
+// of the channel below its layer. Segments share a row only when they share
+// a source or a target, or do not overlap, so separate edges never merge.
+// It then stacks the layers and their channels.
+func (l *dagLayout) assignTracks() {
+	byLayer := make([][]int, len(l.layers))
+	for i, s := range l.segments {
+		ly := l.nodes[s.from].layer
+		byLayer[ly] = append(byLayer[ly], i)
+	}
+	span := func(s dagSegment) (int, int) {
+		a, b := l.nodes[s.from].center(), l.nodes[s.to].center()
+		return min(a, b), max(a, b)
+	}
+
+	tracks := make([]int, len(l.layers))
+	for ly, segs := range byLayer {
+		sort.SliceStable(segs, func(i, j int) bool {
+			a, _ := span(l.segments[segs[i]])
+			b, _ := span(l.segments[segs[j]])
+			return a < b
+		})
+		var rows [][]int
+		for _, si := range segs {
+			s := &l.segments[si]
+			lo, hi := span(*s)
+			if lo == hi {
+				continue
+			}
+			s.track = -1
+			for k, row := range rows {
+				fits := true
+				for _, ti := range row {
+					t := l.segments[ti]
+					tlo, thi := span(t)
+					if t.from != s.from && t.to != s.to && lo <= thi+1 && tlo <= hi+1 {
+						fits = false
+						break
+					}
+				}
+				if fits {
+					s.track = k
+					rows[k] = append(row, si)
+					break
+				}
+			}
+			if s.track < 0 {
+				s.track = len(rows)
+				rows = append(rows, []int{si})
+			}
+		}
+		tracks[ly] = max(1, len(rows))
+	}
+
+	l.tops = make([]int, len(l.layers))
+	l.height = 0
+	for ly := range l.layers {
+		l.tops[ly] = l.height
+		l.height += l.boxHeight
+		if ly < len(l.layers)-1 {
+			l.height += tracks[ly] + dagChannelRows
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func testLayout(ids []string, blockers map[string][]string) *dagLayout {
+	return layoutDAG(ids, blockers, func(id string) int { return len(id) + 4 }, 3)
+}
+
+func TestLayoutDAGLayersAndDummies(t *testing.T) {
+	// a blocks b, b blocks c, and a blocks c directly.
+	l := testLayout([]string{"a", "b", "c"}, map[string][]string{"b": {"a"}, "c": {"b", "a"}})
+
+	for id, want := range map[string]int{"a": 0, "b": 1, "c": 2} {
+		if got := l.nodes[l.index[id]].layer; got != want {
+			t.Errorf("%s layer = %d, want %d", id, got, want)
+		}
+	}
+	// a -> c skips a layer, so it runs through one dummy.
+	if len(l.nodes) != 4 || !l.nodes[3].dummy() || l.nodes[3].layer != 1 {
+		t.Fatalf("expected one dummy in layer 1: %+v", l.nodes)
+	}
+	if len(l.segments) != 4 || l.cycleEdges != 0 {
+		t.Errorf("segments = %+v, cycle edges = %d", l.segments, l.cycleEdges)
+	}
+	for _, id := range []string{"a", "b", "c"} {
+		if !l.critical[id] {
+			t.Errorf("%s should be on the critical path", id)
+		}
+	}
+}
+
+func TestLayoutDAGCrossingsAndOverlap(t *testing.T) {
+	// Seeded so that a naive layer order crosses: p1 blocks c2, p2 blocks c1.
+	ids := []string{"p1", "p2", "c1", "c2"}
+	l := testLayout(ids, map[string][]string{"c1": {"p2"}, "c2": {"p1"}})
+	if c := l.crossings(); c != 0 {
+		t.Errorf("crossings = %d, want 0", c)
+	}
+	for _, layer := range l.layers {
+		for i := 1; i < len(layer); i++ {
+			prev, cur := l.nodes[layer[i-1]], l.nodes[layer[i]]
+			if cur.x < prev.x+prev.width+dagGap {
+				t.Errorf("nodes overlap: %+v and %+v", prev, cur)
+			}
+		}
+	}
+}
+
+func TestLayoutDAGCycles(t *testing.T) {
+	// x -> y -> z -> x, plus z blocks w.
+	l := testLayout([]string{"w", "x", "y", "z"}, map[string][]string{"y": {"x"}, "z": {"y"}, "x": {"z"}, "w": {"z"}})
+	if l.cycleEdges != 3 {
+		t.Errorf("cycle edges = %d, want 3", l.cycleEdges)
+	}
+	arrowsUp := 0
+	for _, s := range l.segments {
+		if s.arrowUp {
+			arrowsUp++
+		}
+		if l.nodes[s.to].layer != l.nodes[s.from].layer+1 {
+			t.Errorf("segment %+v does not join adjacent layers", s)
+		}
+	}
+	if arrowsUp != 1 {
+		t.Errorf("one reversed cycle edge should point up, got %d", arrowsUp)
+	}
+	if len(l.index) != 4 {
+		t.Errorf("every bead should be placed: %v", l.index)
+	}
+}
+
+func TestLayoutDAGTracksKeepEdgesApart(t *testing.T) {
+	// Edges between the same two layers whose horizontal runs overlap get
+	// separate rows unless they share an end.
+	ids := []string{"p1", "p2", "p3", "c1", "c2"}
+	l := testLayout(ids, map[string][]string{"c1": {"p1", "p3"}, "c2": {"p2", "p1"}})
+	for i, a := range l.segments {
+		for _, b := range l.segments[i+1:] {
+			if a.from == b.from || a.to == b.to || a.track != b.track {
+				continue
+			}
+			alo, ahi := min(l.nodes[a.from].center(), l.nodes[a.to].center()), max(l.nodes[a.from].center(), l.nodes[a.to].center())
+			blo, bhi := min(l.nodes[b.from].center(), l.nodes[b.to].center()), max(l.nodes[b.from].center(), l.nodes[b.to].center())
+			if alo != ahi && blo != bhi && alo <= bhi && blo <= ahi {
+				t.Errorf("segments %+v and %+v share a track and overlap", a, b)
+			}
+		}
+	}
+}
+
+func TestGraphViewLayeredKeys(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "base", Title: "Base", Status: model.StatusOpen},
+		{ID: "top", Title: "Top", Status: model.StatusOpen, Dependencies: []*model.Dependency{{DependsOnID: "base", Type: model.DepBlocks}}},
+	}
+	m := NewModel(issues, nil, "")
+	key := func(s string) {
+		var msg tea.KeyMsg
+		switch s {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+		}
+		updated, _ := m.Update(msg)
+		m = updated.(Model)
+	}
+
+	key("g")
+	key("d")
+	if m.focused != focusGraph || !m.graphView.IsLayered() {
+		t.Fatalf("g then d should open the layered graph (focus=%v)", m.focused)
+	}
+	if !strings.Contains(m.View(), "DEPENDENCY DAG") {
+		t.Errorf("layered graph not rendered")
+	}
+
+	// The jump prompt takes esc and q instead of closing the view.
+	key("/")
+	key("q")
+	key("esc")
+	if !m.isGraphView || m.graphView.Jumping() {
+		t.Fatalf("esc should only close the jump prompt")
+	}
+	key("/")
+	for _, r := range "top" {
+		key(string(r))
+	}
+	key("enter")
+	if sel := m.graphView.SelectedIssue(); sel == nil || sel.ID != "top" {
+		t.Errorf("jump should select top, got %+v", sel)
+	}
+
+	// h/l move within a layer instead of opening history or the label picker.
+	key("h")
+	key("l")
+	if m.isHistoryView || m.showLabelPicker || m.focused != focusGraph {
+		t.Errorf("h/l should stay in the layered graph")
+	}
+}
+
+func TestGraphViewLayoutBuiltOutsideView(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "base", Title: "Base", Status: model.StatusOpen, Labels: []string{"core"}},
+		{ID: "top", Title: "Top", Status: model.StatusOpen, Dependencies: []*model.Dependency{{DependsOnID: "base", Type: model.DepBlocks}}},
+	}
+	m := NewModel(issues, nil, "")
+	for _, msg := range []tea.Msg{
+		tea.WindowSizeMsg{Width: 100, Height: 30},
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")},
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")},
+	} {
+		updated, _ := m.Update(msg)
+		m = updated.(Model)
+	}
+	if !m.graphView.IsLayered() {
+		t.Fatal("g then d should open the layered graph")
+	}
+	built := m.graphView.layout
+	if built == nil {
+		t.Fatal("ToggleLayered should build the layout")
+	}
+	if m.graphView.width != 100 {
+		t.Errorf("graph width = %d, want the window width", m.graphView.width)
+	}
+	m.View()
+	if m.graphView.layout != built {
+		t.Error("rendering should reuse the cached layout")
+	}
+
+	m.graphView.CycleZoom()
+	if m.graphView.layout == nil || m.graphView.layout == built {
+		t.Error("CycleZoom should rebuild the layout")
+	}
+	m.graphView.SelectByID("base")
+	m.graphView.CycleLabelScope()
+	if l := m.graphView.layout; l == nil || len(l.index) != 1 {
+		t.Errorf("CycleLabelScope should build the scoped layout, got %+v", l)
+	}
+	m.graphView.SetIssues(issues, nil)
+	if m.graphView.layout == nil {
+		t.Error("SetIssues should rebuild the layout while layered")
+	}
+}
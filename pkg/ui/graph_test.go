@@ -2,6 +2,7 @@ package ui_test
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
@@ -885,3 +886,129 @@ func TestGraphModelReferenceToSelf(t *testing.T) {
 		t.Error("Expected non-empty view")
 	}
 }
+
+// layeredTestIssues is a diamond (a blocks b and c, which block d) plus a
+// two-bead cycle.
+func layeredTestIssues() []model.Issue {
+	blocks := func(id string) *model.Dependency {
+		return &model.Dependency{DependsOnID: id, Type: model.DepBlocks}
+	}
+	return []model.Issue{
+		{ID: "a", Title: "Schema", Labels: []string{"db"}},
+		{ID: "b", Title: "API", Labels: []string{"api", "db"}, Dependencies: []*model.Dependency{blocks("a")}},
+		{ID: "c", Title: "Docs", Dependencies: []*model.Dependency{blocks("a")}},
+		{ID: "d", Title: "Release", Dependencies: []*model.Dependency{blocks("b"), blocks("c")}},
+		{ID: "x", Title: "Loop one", Dependencies: []*model.Dependency{blocks("y")}},
+		{ID: "y", Title: "Loop two", Dependencies: []*model.Dependency{blocks("x")}},
+	}
+}
+
+// TestGraphModelLayeredView verifies the full-DAG layout, zoom and panning
+func TestGraphModelLayeredView(t *testing.T) {
+	g := ui.NewGraphModel(layeredTestIssues(), nil, createTheme())
+	g.ToggleLayered()
+	if !g.IsLayered() {
+		t.Fatal("Expected layered view")
+	}
+
+	out := g.View(120, 30)
+	for _, want := range []string{"DEPENDENCY DAG", "6 beads", "3 layers", "2 cycle edges", "▼", "▲", "╔"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Layered view missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Release") {
+		t.Errorf("Titles should be hidden at the ID zoom level:\n%s", out)
+	}
+
+	g.CycleZoom()
+	if out := g.View(120, 30); !strings.Contains(out, "Release") || !strings.Contains(out, "Loop two") {
+		t.Errorf("Titles should show at the title zoom level:\n%s", out)
+	}
+
+	// Panning is clamped to the canvas.
+	g.ScrollLeft()
+	narrow := g.View(30, 30)
+	for i := 0; i < 50; i++ {
+		g.ScrollRight()
+	}
+	if g.View(30, 30) == narrow {
+		t.Error("Expected ScrollRight to pan a wide layout")
+	}
+	for i := 0; i < 50; i++ {
+		g.ScrollLeft()
+	}
+	if g.View(30, 30) != narrow {
+		t.Error("Expected ScrollLeft to pan back to the left edge")
+	}
+
+	g.ToggleLayered()
+	if out := g.View(120, 30); strings.Contains(out, "DEPENDENCY DAG") {
+		t.Error("Expected the ego view after toggling back")
+	}
+}
+
+// TestGraphModelLayeredNavigation verifies moving between and within layers
+func TestGraphModelLayeredNavigation(t *testing.T) {
+	g := ui.NewGraphModel(layeredTestIssues(), nil, createTheme())
+	g.ToggleLayered()
+	if !g.SelectByID("a") {
+		t.Fatal("Expected to select a")
+	}
+	g.MoveDown()
+	first := g.SelectedIssue().ID
+	if first != "b" && first != "c" {
+		t.Fatalf("MoveDown from a should reach one of its dependents, got %s", first)
+	}
+	g.MoveDown()
+	if got := g.SelectedIssue().ID; got != "d" {
+		t.Errorf("MoveDown should reach d, got %s", got)
+	}
+	g.MoveUp()
+	g.MoveLeft()
+	g.MoveRight()
+	g.MoveRight()
+	if got := g.SelectedIssue().ID; got == "a" || got == "d" {
+		t.Errorf("Sideways moves should stay in the middle layer, got %s", got)
+	}
+}
+
+// TestGraphModelLabelScopeAndJump verifies label scoping and jump-to-node
+func TestGraphModelLabelScopeAndJump(t *testing.T) {
+	g := ui.NewGraphModel(layeredTestIssues(), nil, createTheme())
+	g.ToggleLayered()
+	g.SelectByID("b")
+
+	if scope := g.CycleLabelScope(); scope != "api" {
+		t.Fatalf("Expected scope api, got %q", scope)
+	}
+	if out := g.View(120, 30); !strings.Contains(out, "1 beads") || !strings.Contains(out, "label api") {
+		t.Errorf("Expected a one-bead api subgraph:\n%s", out)
+	}
+	if scope := g.CycleLabelScope(); scope != "db" {
+		t.Fatalf("Expected scope db, got %q", scope)
+	}
+	if out := g.View(120, 30); !strings.Contains(out, "2 beads") {
+		t.Errorf("Expected a two-bead db subgraph:\n%s", out)
+	}
+
+	// Jumping outside the scope widens it back to the whole graph.
+	g.StartJump()
+	for _, key := range []string{"Y", "enter"} {
+		if status := g.JumpKey(key); status != "" {
+			t.Fatalf("Unexpected jump status %q", status)
+		}
+	}
+	if g.Jumping() || g.SelectedIssue().ID != "y" || g.LabelScope() != "" {
+		t.Errorf("Expected y selected in the whole graph, got %s (scope %q)", g.SelectedIssue().ID, g.LabelScope())
+	}
+
+	g.StartJump()
+	g.JumpKey("q")
+	if status := g.JumpKey("enter"); status == "" {
+		t.Error("Expected a status message for an unmatched jump")
+	}
+	if g.SelectedIssue().ID != "y" {
+		t.Error("A failed jump should keep the selection")
+	}
+}
@@ -1226,6 +1226,11 @@ func (h *HistoryModel) GetHistoryForBead(beadID string) *correlation.BeadHistory
 	return &hist
 }
 
+// Report returns the loaded history data, or nil before it has loaded
+func (h *HistoryModel) Report() *correlation.HistoryReport {
+	return h.report
+}
+
 // HasReport returns true if history data is loaded
 func (h *HistoryModel) HasReport() bool {
 	return h.report != nil
@@ -1,6 +1,8 @@
 package ui
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -2796,3 +2798,17 @@ func TestFileTree_JumpToFile(t *testing.T) {
 		t.Errorf("Expected at least 4 .go files in test data, got %d", goFileCount)
 	}
 }
+
+func TestHistorySourcePathPrefersJSONL(t *testing.T) {
+	dir := t.TempDir()
+	jsonl := filepath.Join(dir, "issues.jsonl")
+	if err := os.WriteFile(jsonl, []byte("{}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := historySourcePath(filepath.Join(dir, "beads.db")); got != jsonl {
+		t.Errorf("historySourcePath(beads.db) = %q, want %q", got, jsonl)
+	}
+	if got := historySourcePath(""); got != "" {
+		t.Errorf("historySourcePath(\"\") = %q, want empty", got)
+	}
+}
@@ -14,12 +14,15 @@ import (
 	"github.com/Dicklesworthstone/beads_viewer/pkg/agents"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/baseline"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/beadid"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/cass"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/correlation"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/drift"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/export"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/mutate"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/query"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/recipe"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/search"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/updater"
@@ -65,6 +68,12 @@ const (
 	focusTutorial    // Interactive tutorial (bv-8y31)
 	focusCassModal   // Cass session preview modal (bv-5bqh)
 	focusUpdateModal // Self-update modal (bv-182)
+	focusEditModal   // Edit/create form modal
+	focusSchedule    // Agent schedule (Gantt) view
+	focusTimeline    // Issue timeline (Gantt) view
+	focusEpicTree    // Epic hierarchy roll-up view
+	focusEscalations // Ack escalation queue view
+	focusAgents      // Agent reliability dashboard
 )
 
 // SortMode represents the current list sorting mode (bv-3ita)
@@ -201,6 +210,19 @@ func CheckAgentFileCmd(workDir string) tea.Cmd {
 	}
 }
 
+// historySourcePath returns the file whose git history is correlated. The
+// issues may have been read from beads.db, which git history cannot be parsed
+// from, so the JSONL next to it is preferred.
+func historySourcePath(beadsPath string) string {
+	if beadsPath == "" {
+		return ""
+	}
+	if jsonlPath, err := loader.FindJSONLPath(filepath.Dir(beadsPath)); err == nil {
+		return jsonlPath
+	}
+	return beadsPath
+}
+
 // LoadHistoryCmd returns a command that loads history data in the background
 func LoadHistoryCmd(issues []model.Issue, beadsPath string) tea.Cmd {
 	return func() tea.Msg {
@@ -240,7 +262,7 @@ func LoadHistoryCmd(issues []model.Issue, beadsPath string) tea.Cmd {
 			}
 		}
 
-		correlator := correlation.NewCorrelator(repoPath, beadsPath)
+		correlator := correlation.NewCorrelator(repoPath, historySourcePath(beadsPath))
 		opts := correlation.CorrelatorOptions{
 			Limit: 500, // Reasonable limit for TUI performance
 		}
@@ -257,8 +279,10 @@ type Model struct {
 	issueMap  map[string]*model.Issue
 	analyzer  *analysis.Analyzer
 	analysis  *analysis.GraphStats
-	beadsPath string           // Path to beads.jsonl for reloading
-	watcher   *watcher.Watcher // File watcher for live reload
+	readiness *analysis.Readiness // Open-blocker counts, patched on reload
+	beadsPath string              // Path to beads.jsonl for reloading
+	watcher   *watcher.Watcher    // File watcher for live reload
+	reloader  *loader.Reloader    // Diffs successive loads of beadsPath
 
 	// UI Components
 	list               list.Model
@@ -286,6 +310,11 @@ type Model struct {
 	isGraphView              bool
 	isActionableView         bool
 	isHistoryView            bool
+	isScheduleView           bool
+	isTimelineView           bool
+	isEpicTreeView           bool
+	isEscalationView         bool
+	isAgentView              bool
 	showDetails              bool
 	showHelp                 bool
 	helpScroll               int // Scroll offset for help overlay
@@ -312,6 +341,21 @@ type Model struct {
 	// Actionable view
 	actionableView ActionableModel
 
+	// Schedule view
+	scheduleView ScheduleModel
+
+	// Timeline view
+	timelineView TimelineModel
+
+	// Epic tree view
+	epicTreeView EpicTreeModel
+
+	// Escalation queue view
+	escalationView EscalationQueueModel
+
+	// Agent dashboard view
+	agentView AgentDashboardModel
+
 	// History view
 	historyView       HistoryModel
 	historyLoading    bool // True while history is being loaded in background
@@ -323,6 +367,7 @@ type Model struct {
 	semanticSearchEnabled  bool
 	semanticIndexBuilding  bool
 	semanticSearch         *SemanticSearch
+	queryFilter            *QueryFilter
 	semanticHybridEnabled  bool
 	semanticHybridPreset   search.PresetName
 	semanticHybridBuilding bool
@@ -396,6 +441,7 @@ type Model struct {
 	selectedSprint *model.Sprint
 	isSprintView   bool
 	sprintViewText string
+	sprintForecast *analysis.MonteCarloForecast // for selectedSprint; nil until computed
 
 	// AGENTS.md integration (bv-i8dk)
 	showAgentPrompt  bool
@@ -414,6 +460,10 @@ type Model struct {
 	// Self-update modal (bv-182)
 	showUpdateModal bool
 	updateModal     UpdateModal
+
+	// Edit/create form modal (writes through pkg/mutate)
+	showEditModal bool
+	editModal     EditModal
 }
 
 // labelCount is a simple label->count pair for display
@@ -511,7 +561,11 @@ type WorkspaceInfo struct {
 	RepoPrefixes []string
 }
 
+// updateSemanticIDs keeps the query and semantic filters in step with the list items
 func (m *Model) updateSemanticIDs(items []list.Item) {
+	if m.queryFilter != nil {
+		m.queryFilter.SetItems(items, m.issues, m.analysis)
+	}
 	if m.semanticSearch == nil {
 		return
 	}
@@ -672,35 +726,8 @@ func NewModel(issues []model.Issue, activeRecipe *recipe.Recipe, beadsPath strin
 	}
 
 	// Compute stats
-	cOpen, cReady, cBlocked, cClosed := 0, 0, 0, 0
-	for i := range issues {
-		issue := &issues[i]
-		if issue.Status == model.StatusClosed {
-			cClosed++
-			continue
-		}
-
-		cOpen++
-		if issue.Status == model.StatusBlocked {
-			cBlocked++
-			continue
-		}
-
-		// Check if blocked by open dependencies
-		isBlocked := false
-		for _, dep := range issue.Dependencies {
-			if dep == nil || !dep.Type.IsBlocking() {
-				continue
-			}
-			if blocker, exists := issueMap[dep.DependsOnID]; exists && blocker.Status != model.StatusClosed {
-				isBlocked = true
-				break
-			}
-		}
-		if !isBlocked {
-			cReady++
-		}
-	}
+	readiness := analyzer.Readiness()
+	cOpen, cReady, cBlocked, cClosed := issueCounts(issues, readiness)
 
 	// Theme
 	theme := DefaultTheme(lipgloss.NewRenderer(os.Stdout))
@@ -818,7 +845,9 @@ func NewModel(issues []model.Issue, activeRecipe *recipe.Recipe, beadsPath strin
 	// Initialize file watcher for live reload
 	var fileWatcher *watcher.Watcher
 	var watcherErr error
+	var reloader *loader.Reloader
 	if beadsPath != "" {
+		reloader = loader.NewReloader(beadsPath)
 		w, err := watcher.NewWatcher(beadsPath,
 			watcher.WithDebounceDuration(200*time.Millisecond),
 		)
@@ -841,6 +870,11 @@ func NewModel(issues []model.Issue, activeRecipe *recipe.Recipe, beadsPath strin
 	}
 	semanticSearch.SetIDs(semanticIDs)
 
+	// Query syntax in the filter bar ("status:open label:api")
+	queryFilter := NewQueryFilter()
+	queryFilter.SetItems(items, issues, graphStats)
+	l.Filter = queryFilter.Wrap(list.DefaultFilter)
+
 	// Build initial status message if watcher failed
 	var initialStatus string
 	var initialStatusErr bool
@@ -866,8 +900,10 @@ func NewModel(issues []model.Issue, activeRecipe *recipe.Recipe, beadsPath strin
 		issueMap:               issueMap,
 		analyzer:               analyzer,
 		analysis:               graphStats,
+		readiness:              readiness,
 		beadsPath:              beadsPath,
 		watcher:                fileWatcher,
+		reloader:               reloader,
 		list:                   l,
 		viewport:               vp,
 		renderer:               renderer,
@@ -880,6 +916,7 @@ func NewModel(issues []model.Issue, activeRecipe *recipe.Recipe, beadsPath strin
 		theme:                  theme,
 		currentFilter:          "all",
 		semanticSearch:         semanticSearch,
+		queryFilter:            queryFilter,
 		semanticHybridEnabled:  false,
 		semanticHybridPreset:   search.PresetDefault,
 		semanticHybridBuilding: false,
@@ -958,7 +995,25 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	var cmds []tea.Cmd
 
+	// huh drives field focus and validation with its own internal messages,
+	// so every non-key message is forwarded while the edit form is open.
+	if _, isKey := msg.(tea.KeyMsg); m.showEditModal && !isKey {
+		m.editModal, cmd = m.editModal.Update(msg)
+		cmds = append(cmds, cmd)
+		m, cmd = m.finishEditModal()
+		cmds = append(cmds, cmd)
+	}
+
 	switch msg := msg.(type) {
+	case EditResultMsg:
+		if msg.Err != nil {
+			m.statusMsg = "Edit failed: " + msg.Err.Error()
+			m.statusIsError = true
+		} else {
+			m.statusMsg = fmt.Sprintf("✓ %s: %s", msg.IssueID, msg.Summary)
+			m.statusIsError = false
+		}
+
 	case UpdateMsg:
 		m.updateAvailable = true
 		m.updateTag = msg.TagName
@@ -997,7 +1052,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if msg.Error != nil {
 			// If indexing fails, revert to fuzzy mode for predictable behavior.
 			m.semanticSearchEnabled = false
-			m.list.Filter = list.DefaultFilter
+			m.list.Filter = m.queryFilter.Wrap(list.DefaultFilter)
 			m.statusMsg = fmt.Sprintf("Semantic search unavailable: %v", msg.Error)
 			m.statusIsError = true
 			break
@@ -1168,6 +1223,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.isSplitView || m.showDetails {
 				m.updateViewportContent()
 			}
+			// Git history adds reopen rates and commit counts
+			if m.isAgentView {
+				report, _ := m.projectAgentStats()
+				m.agentView.SetData(report, m.issues)
+			}
 		}
 
 	case AgentFileCheckMsg:
@@ -1201,10 +1261,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.modifiedIssueIDs = nil
 		}
 
-		// Reload issues from disk
+		// Reload issues from disk. After the first reload the reloader only
+		// decodes changed lines and reports which issues changed, so the
+		// analyzer can be patched instead of rebuilt.
 		// Use custom warning handler to prevent stderr pollution during TUI render (bv-fix)
+		if m.reloader == nil || m.reloader.Path() != m.beadsPath {
+			m.reloader = loader.NewReloader(m.beadsPath)
+		}
+		incremental := m.reloader.Loaded() && m.analyzer != nil
 		var reloadWarnings []string
-		newIssues, err := loader.LoadIssuesFromFileWithOptions(m.beadsPath, loader.ParseOptions{
+		newIssues, delta, err := m.reloader.Load(loader.ParseOptions{
 			WarningHandler: func(msg string) {
 				reloadWarnings = append(reloadWarnings, msg)
 			},
@@ -1240,12 +1306,22 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return newIssues[i].CreatedAt.After(newIssues[j].CreatedAt)
 		})
 
-		// Recompute analysis (async Phase 1/Phase 2) with caching
+		// Recompute analysis. Incremental reloads patch the graph and the
+		// Phase 1 metrics for the changed issues; a full reload goes through
+		// the cache. Either way Phase 2 runs in the background.
 		m.issues = newIssues
-		cachedAnalyzer := analysis.NewCachedAnalyzer(newIssues, nil)
-		m.analyzer = cachedAnalyzer.Analyzer
-		m.analysis = cachedAnalyzer.AnalyzeAsync(context.Background())
-		cacheHit := cachedAnalyzer.WasCacheHit()
+		cacheHit := false
+		if incremental {
+			m.analyzer = m.analyzer.Update(newIssues, delta.Changed())
+			m.analysis = m.analyzer.AnalyzeIncremental(context.Background(), m.analysis)
+			m.readiness = m.readiness.Update(m.analyzer)
+		} else {
+			cachedAnalyzer := analysis.NewCachedAnalyzer(newIssues, nil)
+			m.analyzer = cachedAnalyzer.Analyzer
+			m.analysis = cachedAnalyzer.AnalyzeAsync(context.Background())
+			m.readiness = m.analyzer.Readiness()
+			cacheHit = cachedAnalyzer.WasCacheHit()
+		}
 		m.labelHealthCached = false
 		m.attentionCached = false
 
@@ -1259,32 +1335,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.priorityHints = make(map[string]*analysis.PriorityRecommendation)
 
 		// Recompute stats
-		m.countOpen, m.countReady, m.countBlocked, m.countClosed = 0, 0, 0, 0
-		for i := range m.issues {
-			issue := &m.issues[i]
-			if issue.Status == model.StatusClosed {
-				m.countClosed++
-				continue
-			}
-			m.countOpen++
-			if issue.Status == model.StatusBlocked {
-				m.countBlocked++
-				continue
-			}
-			isBlocked := false
-			for _, dep := range issue.Dependencies {
-				if dep == nil || !dep.Type.IsBlocking() {
-					continue
-				}
-				if blocker, exists := m.issueMap[dep.DependsOnID]; exists && blocker.Status != model.StatusClosed {
-					isBlocked = true
-					break
-				}
-			}
-			if !isBlocked {
-				m.countReady++
-			}
-		}
+		m.countOpen, m.countReady, m.countBlocked, m.countClosed = issueCounts(m.issues, m.readiness)
 
 		// Recompute alerts for refreshed dataset
 		m.alerts, m.alertsCritical, m.alertsWarning, m.alertsInfo = computeAlerts(m.issues, m.analysis, m.analyzer)
@@ -1338,6 +1389,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Generate priority recommendations now that Phase 2 is ready
 		m.board = NewBoardModel(m.issues, m.theme)
 
+		// Resolving an escalation rewrites the beads file; show the result.
+		if m.isEscalationView {
+			report, _ := m.projectEscalations(time.Now())
+			m.escalationView.SetReport(report)
+		}
+		if m.isAgentView {
+			report, _ := m.projectAgentStats()
+			m.agentView.SetData(report, m.issues)
+		}
+
 		// Re-apply recipe filter if active
 		if m.activeRecipe != nil {
 			m.applyRecipe(m.activeRecipe)
@@ -1354,6 +1415,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					for i := range m.sprints {
 						if m.sprints[i].ID == m.selectedSprint.ID {
 							m.selectedSprint = &m.sprints[i]
+							m.refreshSprintForecast(time.Now())
 							m.sprintViewText = m.renderSprintDashboard()
 							found = true
 							break
@@ -1361,6 +1423,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 					if !found {
 						m.selectedSprint = nil
+						m.sprintForecast = nil
 						m.sprintViewText = "Sprint not found"
 					}
 				}
@@ -1375,6 +1438,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		if cacheHit {
 			m.statusMsg = fmt.Sprintf("Reloaded %d issues (cached)", len(newIssues))
+		} else if incremental {
+			m.statusMsg = fmt.Sprintf("Reloaded %d issues (+%d −%d ~%d)", len(newIssues),
+				len(delta.Added), len(delta.Removed), len(delta.Modified))
 		} else {
 			m.statusMsg = fmt.Sprintf("Reloaded %d issues", len(newIssues))
 		}
@@ -1447,6 +1513,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Batch(cmds...)
 		}
 
+		// Handle edit/create form modal
+		if m.showEditModal {
+			m.editModal, cmd = m.editModal.Update(msg)
+			cmds = append(cmds, cmd)
+			m, cmd = m.finishEditModal()
+			cmds = append(cmds, cmd)
+			return m, tea.Batch(cmds...)
+		}
+
 		// Handle self-update modal (bv-182)
 		if m.showUpdateModal {
 			m.updateModal, cmd = m.updateModal.Update(msg)
@@ -1662,6 +1737,18 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		// Handle the graph's jump prompt before global keys (esc/q/?/etc.)
+		if m.focused == focusGraph && m.graphView.Jumping() {
+			if msg.String() == "ctrl+c" {
+				return m, tea.Quit
+			}
+			if status := m.graphView.JumpKey(msg.String()); status != "" {
+				m.statusMsg = status
+				m.statusIsError = true
+			}
+			return m, nil
+		}
+
 		// Handle quit confirmation first
 		if m.showQuitConfirm {
 			switch msg.String() {
@@ -1787,7 +1874,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.semanticSearchEnabled = !m.semanticSearchEnabled
 			if m.semanticSearchEnabled {
 				if m.semanticSearch != nil {
-					m.list.Filter = m.semanticSearch.Filter
+					m.list.Filter = m.queryFilter.Wrap(m.semanticSearch.Filter)
 					if !m.semanticSearch.Snapshot().Ready && !m.semanticIndexBuilding {
 						m.semanticIndexBuilding = true
 						m.statusMsg = "Semantic search: building index…"
@@ -1799,7 +1886,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 				} else {
 					m.semanticSearchEnabled = false
-					m.list.Filter = list.DefaultFilter
+					m.list.Filter = m.queryFilter.Wrap(list.DefaultFilter)
 					m.statusMsg = "Semantic search unavailable"
 					m.statusIsError = true
 				}
@@ -1808,7 +1895,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					cmds = append(cmds, BuildHybridMetricsCmd(m.issues))
 				}
 			} else {
-				m.list.Filter = list.DefaultFilter
+				m.list.Filter = m.queryFilter.Wrap(list.DefaultFilter)
 				m.statusMsg = "Fuzzy search enabled"
 				m.clearSemanticScores()
 			}
@@ -1856,6 +1943,32 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		// The timeline pans with h/l like the board, so route those before the
+		// global history (h) and label picker (l) shortcuts claim them.
+		if m.focused == focusTimeline {
+			switch msg.String() {
+			case "h", "l":
+				m = m.handleTimelineKeys(msg)
+				return m, nil
+			}
+		}
+		// The epic tree folds with h/l for the same reason.
+		if m.focused == focusEpicTree {
+			switch msg.String() {
+			case "h", "l":
+				m = m.handleEpicTreeKeys(msg)
+				return m, nil
+			}
+		}
+		// So does the layered graph, which moves along a layer with h/l.
+		if m.focused == focusGraph && m.graphView.IsLayered() {
+			switch msg.String() {
+			case "h", "l":
+				m = m.handleGraphKeys(msg)
+				return m, nil
+			}
+		}
+
 		// Handle keys when not filtering
 		if m.list.FilterState() != list.Filtering {
 			switch msg.String() {
@@ -1927,6 +2040,31 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.focused = focusList
 					return m, nil
 				}
+				if m.isScheduleView {
+					m.isScheduleView = false
+					m.focused = focusList
+					return m, nil
+				}
+				if m.isTimelineView {
+					m.isTimelineView = false
+					m.focused = focusList
+					return m, nil
+				}
+				if m.isEpicTreeView {
+					m.isEpicTreeView = false
+					m.focused = focusList
+					return m, nil
+				}
+				if m.isEscalationView {
+					m.isEscalationView = false
+					m.focused = focusList
+					return m, nil
+				}
+				if m.isAgentView {
+					m.isAgentView = false
+					m.focused = focusList
+					return m, nil
+				}
 				if m.isHistoryView {
 					m.isHistoryView = false
 					m.focused = focusList
@@ -1967,6 +2105,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.isBoardView = !m.isBoardView
 				m.isGraphView = false
 				m.isActionableView = false
+				m.isScheduleView = false
+				m.isTimelineView = false
+				m.isEpicTreeView = false
+				m.isEscalationView = false
+				m.isAgentView = false
 				m.isHistoryView = false
 				if m.isBoardView {
 					m.focused = focusBoard
@@ -1981,6 +2124,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.isGraphView = !m.isGraphView
 				m.isBoardView = false
 				m.isActionableView = false
+				m.isScheduleView = false
+				m.isTimelineView = false
+				m.isEpicTreeView = false
+				m.isEscalationView = false
+				m.isAgentView = false
 				m.isHistoryView = false
 				if m.isGraphView {
 					m.focused = focusGraph
@@ -1995,6 +2143,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.isActionableView = !m.isActionableView
 				m.isGraphView = false
 				m.isBoardView = false
+				m.isScheduleView = false
+				m.isTimelineView = false
+				m.isEpicTreeView = false
+				m.isEscalationView = false
+				m.isAgentView = false
 				m.isHistoryView = false
 				if m.isActionableView {
 					// Build execution plan
@@ -2008,6 +2161,101 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				return m, nil
 
+			case "W":
+				// Toggle agent schedule view
+				m.clearAttentionOverlay()
+				m.isScheduleView = !m.isScheduleView
+				m.isGraphView = false
+				m.isBoardView = false
+				m.isActionableView = false
+				m.isTimelineView = false
+				m.isEpicTreeView = false
+				m.isEscalationView = false
+				m.isAgentView = false
+				m.isHistoryView = false
+				if m.isScheduleView {
+					m.openScheduleView()
+				} else {
+					m.focused = focusList
+				}
+				return m, nil
+
+			case "Y":
+				// Toggle timeline (Gantt) view
+				m.clearAttentionOverlay()
+				m.isTimelineView = !m.isTimelineView
+				m.isGraphView = false
+				m.isBoardView = false
+				m.isActionableView = false
+				m.isScheduleView = false
+				m.isHistoryView = false
+				m.isEpicTreeView = false
+				m.isEscalationView = false
+				m.isAgentView = false
+				if m.isTimelineView {
+					m.openTimelineView()
+				} else {
+					m.focused = focusList
+				}
+				return m, nil
+
+			case "B":
+				// Toggle epic breakdown (hierarchy roll-up) view
+				m.clearAttentionOverlay()
+				m.isEpicTreeView = !m.isEpicTreeView
+				m.isGraphView = false
+				m.isBoardView = false
+				m.isActionableView = false
+				m.isScheduleView = false
+				m.isTimelineView = false
+				m.isHistoryView = false
+				m.isEscalationView = false
+				m.isAgentView = false
+				if m.isEpicTreeView {
+					m.openEpicTreeView()
+				} else {
+					m.focused = focusList
+				}
+				return m, nil
+
+			case "Q":
+				// Toggle the ack escalation queue
+				m.clearAttentionOverlay()
+				m.isEscalationView = !m.isEscalationView
+				m.isAgentView = false
+				m.isGraphView = false
+				m.isBoardView = false
+				m.isActionableView = false
+				m.isScheduleView = false
+				m.isTimelineView = false
+				m.isEpicTreeView = false
+				m.isHistoryView = false
+				if m.isEscalationView {
+					m.openEscalationView()
+				} else {
+					m.focused = focusList
+				}
+				return m, nil
+
+			case "I":
+				// Toggle the agent reliability dashboard
+				m.clearAttentionOverlay()
+				m.isAgentView = !m.isAgentView
+				m.isGraphView = false
+				m.isBoardView = false
+				m.isActionableView = false
+				m.isScheduleView = false
+				m.isTimelineView = false
+				m.isEpicTreeView = false
+				m.isEscalationView = false
+				m.isHistoryView = false
+				if m.isAgentView {
+					m.openAgentView()
+				} else {
+					m.focused = focusList
+				}
+				return m, nil
+
 			case "i":
 				m.clearAttentionOverlay()
 				if m.focused == focusInsights {
@@ -2016,6 +2264,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.isGraphView = false
 					m.isBoardView = false
 					m.isActionableView = false
+					m.isScheduleView = false
+					m.isTimelineView = false
+					m.isEpicTreeView = false
+					m.isEscalationView = false
+					m.isAgentView = false
 					m.isHistoryView = false
 					m.focused = focusInsights
 					// Refresh insights using latest analysis snapshot
@@ -2062,6 +2315,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.isGraphView = false
 				m.isBoardView = false
 				m.isActionableView = false
+				m.isScheduleView = false
+				m.isTimelineView = false
+				m.isEpicTreeView = false
+				m.isEscalationView = false
+				m.isAgentView = false
 				if m.isHistoryView {
 					// Ensure history model has latest sizing
 					bodyHeight := m.height - 1
@@ -2081,6 +2339,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.isGraphView = false
 				m.isBoardView = false
 				m.isActionableView = false
+				m.isScheduleView = false
+				m.isTimelineView = false
+				m.isEpicTreeView = false
+				m.isEscalationView = false
+				m.isAgentView = false
 				m.isHistoryView = false
 				m.focused = focusLabelDashboard
 				// Compute label health (fast; phase1 metrics only needed) with caching
@@ -2106,6 +2369,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.isGraphView = false
 				m.isBoardView = false
 				m.isActionableView = false
+				m.isScheduleView = false
+				m.isTimelineView = false
+				m.isEpicTreeView = false
+				m.isEscalationView = false
+				m.isAgentView = false
 				m.isHistoryView = false
 				m.focused = focusInsights
 				m.showAttentionView = true
@@ -2127,6 +2395,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.isGraphView = false
 				m.isBoardView = false
 				m.isActionableView = false
+				m.isScheduleView = false
+				m.isTimelineView = false
+				m.isEpicTreeView = false
+				m.isEscalationView = false
+				m.isAgentView = false
 				m.isHistoryView = false
 				m.focused = focusFlowMatrix
 				m.flowMatrix = NewFlowMatrixModel(m.theme)
@@ -2262,6 +2535,22 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case focusActionable:
 				m = m.handleActionableKeys(msg)
 
+			case focusSchedule:
+				m = m.handleScheduleKeys(msg)
+
+			case focusTimeline:
+				m = m.handleTimelineKeys(msg)
+
+			case focusEpicTree:
+				m = m.handleEpicTreeKeys(msg)
+
+			case focusEscalations:
+				m, cmd = m.handleEscalationKeys(msg)
+				cmds = append(cmds, cmd)
+
+			case focusAgents:
+				m = m.handleAgentKeys(msg)
+
 			case focusHistory:
 				m = m.handleHistoryKeys(msg)
 
@@ -2273,6 +2562,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 			case focusList:
 				m = m.handleListKeys(msg)
+				if m.showEditModal {
+					cmds = append(cmds, m.editModal.Init())
+				}
 
 			case focusDetail:
 				m.viewport, cmd = m.viewport.Update(msg)
@@ -2304,6 +2596,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.graphView.PageUp()
 			case focusActionable:
 				m.actionableView.MoveUp()
+			case focusSchedule:
+				m.scheduleView.MoveUp()
+			case focusTimeline:
+				m.timelineView.MoveUp()
+			case focusEpicTree:
+				m.epicTreeView.MoveUp()
+			case focusEscalations:
+				m.escalationView.MoveUp()
+			case focusAgents:
+				m.agentView.MoveUp()
 			case focusHistory:
 				m.historyView.MoveUp()
 			case focusFlowMatrix:
@@ -2331,6 +2633,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.graphView.PageDown()
 			case focusActionable:
 				m.actionableView.MoveDown()
+			case focusSchedule:
+				m.scheduleView.MoveDown()
+			case focusTimeline:
+				m.timelineView.MoveDown()
+			case focusEpicTree:
+				m.epicTreeView.MoveDown()
+			case focusEscalations:
+				m.escalationView.MoveDown()
+			case focusAgents:
+				m.agentView.MoveDown()
 			case focusHistory:
 				m.historyView.MoveDown()
 			case focusFlowMatrix:
@@ -2388,6 +2700,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.labelDashboard.SetSize(m.width, bodyHeight)
 
 		m.insightsPanel.SetSize(m.width, bodyHeight)
+		m.graphView.SetSize(m.width, m.height-1)
 		m.updateViewportContent()
 	}
 
@@ -2651,6 +2964,25 @@ func (m Model) handleGraphKeys(msg tea.KeyMsg) Model {
 		m.graphView.ScrollLeft()
 	case "L":
 		m.graphView.ScrollRight()
+	case "d":
+		m.graphView.ToggleLayered()
+	case "z":
+		if m.graphView.IsLayered() {
+			m.graphView.CycleZoom()
+		}
+	case "s":
+		if m.graphView.IsLayered() {
+			m.statusIsError = false
+			if label := m.graphView.CycleLabelScope(); label != "" {
+				m.statusMsg = "Graph scope: label " + label
+			} else {
+				m.statusMsg = "Graph scope: all beads"
+			}
+		}
+	case "/":
+		if m.graphView.IsLayered() {
+			m.graphView.StartJump()
+		}
 	case "enter":
 		if selected := m.graphView.SelectedIssue(); selected != nil {
 			// Find and select in list
@@ -2707,6 +3039,284 @@ func (m Model) handleActionableKeys(msg tea.KeyMsg) Model {
 	return m
 }
 
+// projectSchedule schedules the open issues onto the project roster, or onto
+// the current assignees when there is none, and reports which it used.
+func (m *Model) projectSchedule(now time.Time) (analysis.Schedule, string) {
+	projectDir := m.workDir
+	if projectDir == "" {
+		projectDir = "."
+	}
+	source := ".bv/" + analysis.RosterFilename
+	roster, err := analysis.LoadRoster(projectDir)
+	if err != nil {
+		m.statusMsg = fmt.Sprintf("Roster: %v (scheduling current assignees instead)", err)
+		m.statusIsError = true
+	}
+	if len(roster) == 0 {
+		roster = analysis.DefaultRoster(m.issues, 1)
+		source = "assignees"
+	}
+	return analysis.ComputeSchedule(m.issues, m.analysis, analysis.ScheduleOptions{Roster: roster, Now: now}), source
+}
+
+// openScheduleView schedules the open issues and focuses the schedule view.
+func (m *Model) openScheduleView() {
+	sched, source := m.projectSchedule(time.Now())
+	m.scheduleView = NewScheduleModel(sched, source, m.theme)
+	m.scheduleView.SetSize(m.width, m.height-2)
+	m.focused = focusSchedule
+}
+
+// openTimelineView lays out the open issues against their scheduled finish
+// dates and focuses the timeline view, keeping the previous zoom level.
+func (m *Model) openTimelineView() {
+	now := time.Now()
+	sched, _ := m.projectSchedule(now)
+	zoom := m.timelineView.Zoom()
+	m.timelineView = NewTimelineModel(m.issues, m.analysis, sched, now, m.theme)
+	m.timelineView.SetZoom(zoom)
+	m.timelineView.SetSize(m.width, m.height-2)
+	m.focused = focusTimeline
+}
+
+// openEpicTreeView rolls up the parent/child hierarchy and focuses the epic
+// tree view.
+func (m *Model) openEpicTreeView() {
+	report := analysis.ComputeEpicRollups(m.issues, analysis.EpicOptions{Now: time.Now()})
+	m.epicTreeView = NewEpicTreeModel(m.issues, report, m.theme)
+	m.epicTreeView.SetSize(m.width, m.height-2)
+	m.focused = focusEpicTree
+}
+
+// projectEscalations builds the escalation queue, suggesting re-assignees
+// from the project roster or, without one, from everyone ever assigned work.
+func (m *Model) projectEscalations(now time.Time) (analysis.EscalationReport, string) {
+	projectDir := m.workDir
+	if projectDir == "" {
+		projectDir = "."
+	}
+	source := ".bv/" + analysis.RosterFilename
+	roster, err := analysis.LoadRoster(projectDir)
+	if err != nil {
+		m.statusMsg = fmt.Sprintf("Roster: %v (suggesting current assignees instead)", err)
+		m.statusIsError = true
+	}
+	if len(roster) == 0 {
+		source = "assignees"
+	}
+	return analysis.ComputeEscalations(m.issues, analysis.EscalationOptions{Now: now, Roster: roster}), source
+}
+
+// projectAgentStats builds the agent track records from the project roster
+// and, once it has loaded, the git history.
+func (m *Model) projectAgentStats() (analysis.AgentReport, string) {
+	projectDir := m.workDir
+	if projectDir == "" {
+		projectDir = "."
+	}
+	source := ".bv/" + analysis.RosterFilename
+	roster, err := analysis.LoadRoster(projectDir)
+	if err != nil {
+		m.statusMsg = fmt.Sprintf("Roster: %v (listing current assignees instead)", err)
+		m.statusIsError = true
+	}
+	if len(roster) == 0 {
+		source = "assignees"
+	}
+	opts := analysis.AgentStatsOptions{Roster: roster, History: m.historyView.Report()}
+	return analysis.ComputeAgentStats(m.issues, opts), source
+}
+
+// openAgentView builds the agent dashboard and focuses it.
+func (m *Model) openAgentView() {
+	report, source := m.projectAgentStats()
+	m.agentView = NewAgentDashboardModel(report, m.issues, source, m.theme)
+	m.agentView.SetSize(m.width, m.height-2)
+	m.focused = focusAgents
+}
+
+// openEscalationView builds the escalation queue and focuses it.
+func (m *Model) openEscalationView() {
+	report, source := m.projectEscalations(time.Now())
+	m.escalationView = NewEscalationQueueModel(report, source, m.theme)
+	m.escalationView.SetSize(m.width, m.height-2)
+	m.focused = focusEscalations
+}
+
+// handleScheduleKeys handles keyboard input when the schedule view is focused
+func (m Model) handleScheduleKeys(msg tea.KeyMsg) Model {
+	switch msg.String() {
+	case "j", "down":
+		m.scheduleView.MoveDown()
+	case "k", "up":
+		m.scheduleView.MoveUp()
+	case "enter":
+		// Jump to selected issue in list view
+		selectedID := m.scheduleView.SelectedIssueID()
+		if selectedID == "" {
+			break
+		}
+		for i, item := range m.list.Items() {
+			if issueItem, ok := item.(IssueItem); ok && issueItem.Issue.ID == selectedID {
+				m.list.Select(i)
+				break
+			}
+		}
+		m.isScheduleView = false
+		if m.isSplitView {
+			m.focused = focusDetail
+		} else {
+			m.showDetails = true
+			m.focused = focusDetail
+			m.viewport.GotoTop()
+		}
+		m.updateViewportContent()
+	}
+	return m
+}
+
+// handleTimelineKeys handles keyboard input when the timeline view is focused
+func (m Model) handleTimelineKeys(msg tea.KeyMsg) Model {
+	switch msg.String() {
+	case "j", "down":
+		m.timelineView.MoveDown()
+	case "k", "up":
+		m.timelineView.MoveUp()
+	case "h", "left":
+		m.timelineView.ScrollLeft()
+	case "l", "right":
+		m.timelineView.ScrollRight()
+	case "z":
+		m.timelineView.CycleZoom()
+	case "enter":
+		// Jump to selected issue in list view
+		selectedID := m.timelineView.SelectedIssueID()
+		if selectedID == "" {
+			break
+		}
+		for i, item := range m.list.Items() {
+			if issueItem, ok := item.(IssueItem); ok && issueItem.Issue.ID == selectedID {
+				m.list.Select(i)
+				break
+			}
+		}
+		m.isTimelineView = false
+		if m.isSplitView {
+			m.focused = focusDetail
+		} else {
+			m.showDetails = true
+			m.focused = focusDetail
+			m.viewport.GotoTop()
+		}
+		m.updateViewportContent()
+	}
+	return m
+}
+
+// handleEpicTreeKeys handles keyboard input when the epic tree is focused
+func (m Model) handleEpicTreeKeys(msg tea.KeyMsg) Model {
+	switch msg.String() {
+	case "j", "down":
+		m.epicTreeView.MoveDown()
+	case "k", "up":
+		m.epicTreeView.MoveUp()
+	case " ":
+		m.epicTreeView.Toggle()
+	case "l", "right":
+		m.epicTreeView.Expand()
+	case "h", "left":
+		m.epicTreeView.Collapse()
+	case "enter":
+		// Jump to selected issue in list view
+		selectedID := m.epicTreeView.SelectedIssueID()
+		if selectedID == "" {
+			break
+		}
+		for i, item := range m.list.Items() {
+			if issueItem, ok := item.(IssueItem); ok && issueItem.Issue.ID == selectedID {
+				m.list.Select(i)
+				break
+			}
+		}
+		m.isEpicTreeView = false
+		if m.isSplitView {
+			m.focused = focusDetail
+		} else {
+			m.showDetails = true
+			m.focused = focusDetail
+			m.viewport.GotoTop()
+		}
+		m.updateViewportContent()
+	}
+	return m
+}
+
+// handleAgentKeys handles keyboard input when the agent dashboard is focused
+func (m Model) handleAgentKeys(msg tea.KeyMsg) Model {
+	switch msg.String() {
+	case "j", "down":
+		m.agentView.MoveDown()
+	case "k", "up":
+		m.agentView.MoveUp()
+	case "s":
+		m.agentView.CycleSort()
+	}
+	return m
+}
+
+// handleEscalationKeys handles keyboard input when the escalation queue is
+// focused. Resolutions are written through bd-ack's service in the
+// background; the file watcher then reloads the queue.
+func (m Model) handleEscalationKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch key := msg.String(); key {
+	case "j", "down":
+		m.escalationView.MoveDown()
+	case "k", "up":
+		m.escalationView.MoveUp()
+	case "r":
+		if id := m.escalationView.SelectedIssueID(); id != "" && m.beadsPath != "" {
+			return m, RequeueCmd(m.beadsPath, id)
+		}
+	case "1", "2", "3":
+		n := int(key[0] - '0')
+		agent, ok := m.escalationView.Suggestion(n)
+		if !ok {
+			m.statusMsg = fmt.Sprintf("No suggestion %d for this bead", n)
+			m.statusIsError = false
+			break
+		}
+		if m.beadsPath != "" {
+			return m, ReassignCmd(m.beadsPath, m.escalationView.SelectedIssueID(), agent)
+		}
+	case "e":
+		if m.beadsPath != "" {
+			return m, EnforceDeadlinesCmd(m.beadsPath)
+		}
+	case "enter":
+		// Jump to selected issue in list view
+		selectedID := m.escalationView.SelectedIssueID()
+		if selectedID == "" {
+			break
+		}
+		for i, item := range m.list.Items() {
+			if issueItem, ok := item.(IssueItem); ok && issueItem.Issue.ID == selectedID {
+				m.list.Select(i)
+				break
+			}
+		}
+		m.isEscalationView = false
+		if m.isSplitView {
+			m.focused = focusDetail
+		} else {
+			m.showDetails = true
+			m.focused = focusDetail
+			m.viewport.GotoTop()
+		}
+		m.updateViewportContent()
+	}
+	return m, nil
+}
+
 // handleHistoryKeys handles keyboard input when history view is focused
 func (m Model) handleHistoryKeys(msg tea.KeyMsg) Model {
 	// Handle search input when active (bv-nkrj)
@@ -3329,6 +3939,24 @@ func (m Model) handleListKeys(msg tea.KeyMsg) Model {
 	case "U":
 		// Show self-update modal (bv-182)
 		m.showSelfUpdateModal()
+	case "E":
+		m.openEditModal(EditStatus)
+	case "P":
+		m.openEditModal(EditPriority)
+	case "A":
+		m.openEditModal(EditAssignee)
+	case "+":
+		m.openEditModal(EditLabels)
+	case "D":
+		m.openEditModal(EditAddDependency)
+	case "-":
+		m.openEditModal(EditRemoveDependency)
+	case "M":
+		m.openEditModal(EditComment)
+	case "X":
+		m.openEditModal(EditClose)
+	case "N":
+		m.openEditModal(EditCreate)
 	}
 	return m
 }
@@ -3376,6 +4004,21 @@ func (m Model) restoreFocusFromHelp() focus {
 	if m.isActionableView {
 		return focusActionable
 	}
+	if m.isScheduleView {
+		return focusSchedule
+	}
+	if m.isTimelineView {
+		return focusTimeline
+	}
+	if m.isEpicTreeView {
+		return focusEpicTree
+	}
+	if m.isEscalationView {
+		return focusEscalations
+	}
+	if m.isAgentView {
+		return focusAgents
+	}
 	if m.isHistoryView {
 		return focusHistory
 	}
@@ -3466,6 +4109,9 @@ func (m Model) View() string {
 	} else if m.showUpdateModal {
 		// Self-update modal (bv-182)
 		body = m.updateModal.CenterModal(m.width, m.height-1)
+	} else if m.showEditModal {
+		// Edit/create form modal
+		body = m.editModal.CenterModal(m.width, m.height-1)
 	} else if m.showLabelHealthDetail && m.labelHealthDetail != nil {
 		body = m.renderLabelHealthDetail(*m.labelHealthDetail)
 	} else if m.showLabelGraphAnalysis && m.labelGraphAnalysisResult != nil {
@@ -3500,6 +4146,21 @@ func (m Model) View() string {
 	} else if m.isActionableView {
 		m.actionableView.SetSize(m.width, m.height-2)
 		body = m.actionableView.Render()
+	} else if m.isScheduleView {
+		m.scheduleView.SetSize(m.width, m.height-2)
+		body = m.scheduleView.Render()
+	} else if m.isTimelineView {
+		m.timelineView.SetSize(m.width, m.height-2)
+		body = m.timelineView.Render()
+	} else if m.isEpicTreeView {
+		m.epicTreeView.SetSize(m.width, m.height-2)
+		body = m.epicTreeView.Render()
+	} else if m.isEscalationView {
+		m.escalationView.SetSize(m.width, m.height-2)
+		body = m.escalationView.Render()
+	} else if m.isAgentView {
+		m.agentView.SetSize(m.width, m.height-2)
+		body = m.agentView.Render()
 	} else if m.isHistoryView {
 		m.historyView.SetSize(m.width, m.height-1)
 		body = m.historyView.View()
@@ -3827,6 +4488,11 @@ func (m *Model) renderHelpOverlay() string {
 		{"i", "Insights"},
 		{"h", "History view"},
 		{"a", "Actionable"},
+		{"W", "Agent schedule"},
+		{"Y", "Timeline"},
+		{"B", "Epic breakdown"},
+		{"Q", "Escalation queue"},
+		{"I", "Agent dashboard"},
 		{"f", "Flow matrix"},
 		{"[", "Label dashboard"},
 		{"]", "Attention view"},
@@ -3843,7 +4509,7 @@ func (m *Model) renderHelpOverlay() string {
 	}
 
 	filterSection := []struct{ key, desc string }{
-		{"/", "Fuzzy search"},
+		{"/", "Search or query"},
 		{"Ctrl+S", "Semantic search"},
 		{"H", "Hybrid ranking"},
 		{"Alt+H", "Hybrid preset"},
@@ -3859,6 +4525,10 @@ func (m *Model) renderHelpOverlay() string {
 		{"hjkl", "Navigate nodes"},
 		{"H/L", "Scroll left/right"},
 		{"PgUp/Dn", "Scroll up/down"},
+		{"d", "Full DAG / ego view"},
+		{"z", "Zoom: IDs / titles"},
+		{"s", "Scope to a label"},
+		{"/", "Jump to bead ID"},
 		{"Enter", "Jump to issue"},
 	}
 
@@ -4724,8 +5394,10 @@ func (m *Model) renderFooter() string {
 		keyHints = append(keyHints, keyStyle.Render("A")+" attention", keyStyle.Render("F")+" flow")
 	} else if m.focused == focusFlowMatrix {
 		keyHints = append(keyHints, keyStyle.Render("j/k")+" nav", keyStyle.Render("tab")+" panel", keyStyle.Render("⏎")+" drill", keyStyle.Render("esc")+" back", keyStyle.Render("f")+" close")
+	} else if m.isGraphView && m.graphView.IsLayered() {
+		keyHints = append(keyHints, keyStyle.Render("hjkl")+" nav", keyStyle.Render("H/L")+" pan", keyStyle.Render("z")+" zoom", keyStyle.Render("/")+" jump", keyStyle.Render("d")+" ego")
 	} else if m.isGraphView {
-		keyHints = append(keyHints, keyStyle.Render("hjkl")+" nav", keyStyle.Render("H/L")+" scroll", keyStyle.Render("⏎")+" view", keyStyle.Render("g")+" list")
+		keyHints = append(keyHints, keyStyle.Render("hjkl")+" nav", keyStyle.Render("H/L")+" scroll", keyStyle.Render("d")+" dag", keyStyle.Render("⏎")+" view", keyStyle.Render("g")+" list")
 	} else if m.isBoardView {
 		keyHints = append(keyHints, keyStyle.Render("hjkl")+" nav", keyStyle.Render("G")+" bottom", keyStyle.Render("⏎")+" view", keyStyle.Render("b")+" list")
 	} else if m.isActionableView {
@@ -5046,6 +5718,18 @@ func (m *Model) applyRecipe(r *recipe.Recipe) {
 	var filteredItems []list.Item
 	var filteredIssues []model.Issue
 
+	var recipeQuery *query.Query
+	var queryEnv *query.Env
+	if r.Filters.Query != "" {
+		q, err := query.Parse(r.Filters.Query)
+		if err != nil {
+			m.statusMsg = fmt.Sprintf("Recipe %s: %v", r.Name, err)
+			m.statusIsError = true
+		} else {
+			recipeQuery, queryEnv = q, query.NewEnv(m.issues, m.analysis, time.Now())
+		}
+	}
+
 	for _, issue := range m.issues {
 		include := true
 
@@ -5110,6 +5794,10 @@ func (m *Model) applyRecipe(r *recipe.Recipe) {
 			include = !isBlocked
 		}
 
+		if include && recipeQuery != nil {
+			include = recipeQuery.Match(queryEnv, &issue)
+		}
+
 		if include {
 			item := IssueItem{
 				Issue:      issue,
@@ -5499,6 +6187,13 @@ func (m *Model) SetFilter(f string) {
 	m.applyFilter()
 }
 
+// SetQuery starts the list with q applied in the filter bar, as --query
+// does. Only the list narrows; analysis keeps every issue.
+func (m *Model) SetQuery(q string) {
+	m.list.SetFilterText(q)
+	m.updateViewportContent()
+}
+
 // FilteredIssues returns the currently visible issues (exposed for testing)
 func (m Model) FilteredIssues() []model.Issue {
 	items := m.list.Items()
@@ -5554,7 +6249,7 @@ func (m *Model) enterHistoryView() {
 	}
 
 	// Load correlation data
-	correlator := correlation.NewCorrelator(cwd, m.beadsPath)
+	correlator := correlation.NewCorrelator(cwd, historySourcePath(m.beadsPath))
 	opts := correlation.CorrelatorOptions{
 		Limit: 500, // Reasonable limit for TUI performance
 	}
@@ -5861,6 +6556,15 @@ func (m *Model) showCassSessionModal() {
 		m.cassCorrelator = cass.NewCorrelator(searcher, cache, m.workDir)
 	}
 
+	// Recognise IDs the way the loaded issues and .bv/correlation.yaml do
+	ids := make([]string, len(m.issues))
+	for i, iss := range m.issues {
+		ids[i] = iss.ID
+	}
+	if recognizer, err := beadid.ForProject(m.workDir, ids); err == nil {
+		m.cassCorrelator.SetRecognizer(recognizer)
+	}
+
 	// Run correlation
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
@@ -5881,6 +6585,76 @@ func (m *Model) showCassSessionModal() {
 	m.focused = focusCassModal
 }
 
+// openEditModal opens an edit form for the selected issue, or the create form
+// for EditCreate. Edits are refused when there is no single writable beads
+// file behind the view.
+func (m *Model) openEditModal(kind EditKind) {
+	switch {
+	case m.workspaceMode:
+		m.statusMsg = "Editing is not available in workspace mode"
+		m.statusIsError = true
+		return
+	case m.timeTravelMode:
+		m.statusMsg = "Editing is not available in time-travel mode"
+		m.statusIsError = true
+		return
+	case m.beadsPath == "":
+		m.statusMsg = "Editing requires a beads file"
+		m.statusIsError = true
+		return
+	case loader.IsSQLiteFile(m.beadsPath):
+		m.statusMsg = "Editing is not available while reading beads.db; run 'bd export' to refresh the JSONL file"
+		m.statusIsError = true
+		return
+	}
+
+	var issue model.Issue
+	if kind != EditCreate {
+		item, ok := m.list.SelectedItem().(IssueItem)
+		if !ok {
+			return
+		}
+		issue = item.Issue
+		if kind == EditRemoveDependency && len(issue.Dependencies) == 0 {
+			m.statusMsg = issue.ID + " has no dependencies"
+			m.statusIsError = false
+			return
+		}
+	}
+
+	m.editModal = NewEditModal(kind, issue, m.issues, m.theme)
+	m.showEditModal = true
+	m.focused = focusEditModal
+}
+
+// finishEditModal closes the edit modal once its form is submitted or
+// cancelled, returning the command that writes the change.
+func (m Model) finishEditModal() (Model, tea.Cmd) {
+	if !m.showEditModal {
+		return m, nil
+	}
+	if !m.editModal.Done() && !m.editModal.Aborted() {
+		return m, nil
+	}
+	m.showEditModal = false
+	m.focused = focusList
+	if m.editModal.Aborted() {
+		return m, nil
+	}
+	if m.editModal.Kind() == EditCreate {
+		return m, CreateIssueCmd(m.beadsPath, m.editModal.NewIssue())
+	}
+	ops := m.editModal.Ops()
+	if len(ops) == 0 {
+		m.statusMsg = "No changes"
+		return m, nil
+	}
+	if dep, ok := ops[0].(mutate.AddDependency); ok && len(ops) == 1 {
+		return m, AddDependencyCmd(m.beadsPath, m.editModal.IssueID(), dep)
+	}
+	return m, ApplyEditCmd(m.beadsPath, m.editModal.IssueID(), ops)
+}
+
 // showSelfUpdateModal shows the self-update modal (bv-182)
 func (m *Model) showSelfUpdateModal() {
 	// Check if an update is available
@@ -6025,6 +6799,27 @@ func (m *Model) clearAttentionOverlay() {
 // ALERTS PANEL (bv-168)
 // ════════════════════════════════════════════════════════════════════════════
 
+// issueCounts tallies the header counters. Issues with status blocked count
+// as blocked; other open issues count as ready when nothing blocks them.
+func issueCounts(issues []model.Issue, readiness *analysis.Readiness) (open, ready, blocked, closed int) {
+	for i := range issues {
+		issue := &issues[i]
+		if issue.Status == model.StatusClosed {
+			closed++
+			continue
+		}
+		open++
+		if issue.Status == model.StatusBlocked {
+			blocked++
+			continue
+		}
+		if readiness.IsActionable(issue.ID) {
+			ready++
+		}
+	}
+	return open, ready, blocked, closed
+}
+
 // computeAlerts calculates drift alerts for the current issues using the
 // already-computed graph stats/analyzer to avoid redundant work.
 func computeAlerts(issues []model.Issue, stats *analysis.GraphStats, analyzer *analysis.Analyzer) ([]drift.Alert, int, int, int) {
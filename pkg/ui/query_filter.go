@@ -0,0 +1,92 @@
+package ui
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/query"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// QueryFilter lets the list filter bar take bv queries such as
+// "status:open label:api priority<=1". Input that looks like a query is
+// evaluated against the listed issues; anything else, including a query that
+// doesn't parse yet because it is still being typed, goes to the wrapped
+// fuzzy or semantic filter.
+type QueryFilter struct {
+	mu     sync.RWMutex
+	issues []model.Issue // list items, in list order
+	env    *query.Env
+
+	lastTerm  string
+	lastQuery *query.Query
+}
+
+// NewQueryFilter creates an empty query filter
+func NewQueryFilter() *QueryFilter {
+	return &QueryFilter{env: query.NewEnv(nil, nil, time.Now())}
+}
+
+// SetItems records the list items being filtered. all is the full issue set,
+// so dependency terms can see issues outside the current view.
+func (f *QueryFilter) SetItems(items []list.Item, all []model.Issue, stats *analysis.GraphStats) {
+	issues := make([]model.Issue, 0, len(items))
+	for _, it := range items {
+		if issueItem, ok := it.(IssueItem); ok {
+			issues = append(issues, issueItem.Issue)
+		}
+	}
+	env := query.NewEnv(all, stats, time.Now())
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.issues = issues
+	f.env = env
+}
+
+// Wrap returns a list.FilterFunc that handles queries and delegates
+// everything else to inner.
+func (f *QueryFilter) Wrap(inner list.FilterFunc) list.FilterFunc {
+	return func(term string, targets []string) []list.Rank {
+		q := f.parse(term)
+		if q == nil {
+			return inner(term, targets)
+		}
+
+		f.mu.RLock()
+		issues := f.issues
+		env := *f.env
+		f.mu.RUnlock()
+		if len(issues) != len(targets) {
+			return inner(term, targets)
+		}
+
+		env.Now = time.Now()
+		var ranks []list.Rank
+		for i := range issues {
+			if q.Match(&env, &issues[i]) {
+				ranks = append(ranks, list.Rank{Index: i})
+			}
+		}
+		return ranks
+	}
+}
+
+// parse returns the query for term, or nil when term is not a query
+func (f *QueryFilter) parse(term string) *query.Query {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if term == f.lastTerm {
+		return f.lastQuery
+	}
+	f.lastTerm, f.lastQuery = term, nil
+	if query.LooksLikeQuery(term) {
+		if q, err := query.Parse(term); err == nil {
+			f.lastQuery = q
+		}
+	}
+	return f.lastQuery
+}
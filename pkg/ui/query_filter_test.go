@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+func TestQueryFilter(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "bd-1", Title: "Login page", Status: model.StatusOpen, Priority: 1, Labels: []string{"api"}},
+		{ID: "bd-2", Title: "Logout", Status: model.StatusClosed, Priority: 2, Labels: []string{"api"}},
+		{ID: "bd-3", Title: "Docs", Status: model.StatusOpen, Priority: 0,
+			Dependencies: []*model.Dependency{{IssueID: "bd-3", DependsOnID: "bd-1", Type: model.DepBlocks}}},
+	}
+	// The list shows bd-3 and bd-2 only; bd-1 is still visible to dependency terms.
+	items := []list.Item{IssueItem{Issue: issues[2]}, IssueItem{Issue: issues[1]}}
+	targets := []string{"bd-3 Docs", "bd-2 Logout"}
+
+	f := NewQueryFilter()
+	f.SetItems(items, issues, nil)
+
+	fuzzyCalls := 0
+	filter := f.Wrap(func(term string, targets []string) []list.Rank {
+		fuzzyCalls++
+		return list.DefaultFilter(term, targets)
+	})
+
+	rankIDs := func(ranks []list.Rank) []int {
+		var out []int
+		for _, r := range ranks {
+			out = append(out, r.Index)
+		}
+		return out
+	}
+
+	if got := rankIDs(filter("is:blocked", targets)); len(got) != 1 || got[0] != 0 {
+		t.Errorf("is:blocked ranks = %v, want [0]", got)
+	}
+	if got := rankIDs(filter("label:api -status:open", targets)); len(got) != 1 || got[0] != 1 {
+		t.Errorf("label query ranks = %v, want [1]", got)
+	}
+	if fuzzyCalls != 0 {
+		t.Errorf("queries should not reach the fuzzy filter")
+	}
+
+	// Plain text and half-typed queries fall back to the wrapped filter.
+	filter("logout", targets)
+	filter("(status:open", targets)
+	if fuzzyCalls != 2 {
+		t.Errorf("fuzzy calls = %d, want 2", fuzzyCalls)
+	}
+}
+
+func TestModelSetQuery(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "bd-1", Title: "Login page", Status: model.StatusOpen, Labels: []string{"api"},
+			Dependencies: []*model.Dependency{{IssueID: "bd-1", DependsOnID: "bd-2", Type: model.DepBlocks}}},
+		{ID: "bd-2", Title: "Schema", Status: model.StatusOpen},
+	}
+	m := NewModel(issues, nil, "")
+	m.SetQuery("label:api")
+
+	visible := m.list.VisibleItems()
+	if len(visible) != 1 || visible[0].(IssueItem).Issue.ID != "bd-1" {
+		t.Fatalf("visible = %v, want only bd-1", visible)
+	}
+	if len(m.issues) != 2 {
+		t.Errorf("SetQuery should not drop issues from analysis, have %d", len(m.issues))
+	}
+}
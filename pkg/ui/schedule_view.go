@@ -0,0 +1,183 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ScheduleModel renders an analysis.Schedule as a Gantt chart with one lane
+// group per agent and one row per scheduled issue.
+type ScheduleModel struct {
+	schedule     analysis.Schedule
+	rosterSource string
+	rows         []analysis.ScheduledIssue // agent order, then start order
+	selected     int
+	scrollOffset int
+	width        int
+	height       int
+	theme        Theme
+}
+
+// NewScheduleModel creates a schedule view. rosterSource says where the agents
+// came from (".bv/roster.yaml" or "assignees").
+func NewScheduleModel(schedule analysis.Schedule, rosterSource string, theme Theme) ScheduleModel {
+	m := ScheduleModel{schedule: schedule, rosterSource: rosterSource, theme: theme}
+	for _, a := range schedule.Agents {
+		m.rows = append(m.rows, a.Issues...)
+	}
+	return m
+}
+
+// SetSize updates the view dimensions
+func (m *ScheduleModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// MoveUp moves selection up
+func (m *ScheduleModel) MoveUp() {
+	if m.selected > 0 {
+		m.selected--
+	}
+	m.ensureVisible()
+}
+
+// MoveDown moves selection down
+func (m *ScheduleModel) MoveDown() {
+	if m.selected < len(m.rows)-1 {
+		m.selected++
+	}
+	m.ensureVisible()
+}
+
+// SelectedIssueID returns the ID of the currently selected issue
+func (m *ScheduleModel) SelectedIssueID() string {
+	if m.selected >= len(m.rows) {
+		return ""
+	}
+	return m.rows[m.selected].IssueID
+}
+
+func (m *ScheduleModel) visibleRows() int {
+	// Header, summary, axis and the blank line after it.
+	return max(1, m.height-4)
+}
+
+func (m *ScheduleModel) ensureVisible() {
+	if m.selected < m.scrollOffset {
+		m.scrollOffset = m.selected
+	}
+	if m.selected >= m.scrollOffset+m.visibleRows() {
+		m.scrollOffset = m.selected - m.visibleRows() + 1
+	}
+}
+
+// Render renders the schedule view
+func (m *ScheduleModel) Render() string {
+	if m.width == 0 || m.height == 0 {
+		return ""
+	}
+	t := m.theme
+	s := m.schedule
+	var lines []string
+
+	headerStyle := t.Renderer.NewStyle().
+		Bold(true).
+		Foreground(t.Base.GetForeground()).
+		Background(t.Primary).
+		Padding(0, 2).
+		Width(m.width - 4)
+	lines = append(lines, headerStyle.Render(fmt.Sprintf("🗓 SCHEDULE  │  %d issues across %d agents", len(m.rows), len(s.Agents))))
+
+	mutedStyle := t.Renderer.NewStyle().Foreground(t.Subtext).Italic(true)
+	summary := fmt.Sprintf("Done by %s (%.1f working days) • agents from %s",
+		s.End.Format("Mon Jan 2"), float64(s.MakespanMinutes)/(8*60), m.rosterSource)
+	if len(s.Unscheduled) > 0 {
+		summary += fmt.Sprintf(" • %d unscheduled", len(s.Unscheduled))
+	}
+	lines = append(lines, mutedStyle.Render(summary))
+
+	if len(m.rows) == 0 {
+		emptyStyle := t.Renderer.NewStyle().
+			Foreground(t.Subtext).
+			Italic(true).
+			Padding(2, 4).
+			Width(m.width - 4).
+			Align(lipgloss.Center)
+		lines = append(lines, emptyStyle.Render("✓ Nothing to schedule."))
+		return strings.Join(lines, "\n")
+	}
+
+	// Columns: agent | id | bar | title
+	const agentWidth, idWidth = 12, 12
+	barWidth := max(10, (m.width-agentWidth-idWidth-8)*3/5)
+	titleWidth := max(0, m.width-agentWidth-idWidth-barWidth-8)
+	span := max(1, s.MakespanMinutes)
+	col := func(minutes int) int {
+		return min(barWidth, minutes*barWidth/span)
+	}
+
+	// Axis with a tick per working day.
+	axis := []rune(strings.Repeat("─", barWidth))
+	for day := 0; day*480 <= span; day++ {
+		if c := col(day * 480); c < barWidth {
+			axis[c] = '┬'
+		}
+	}
+	lines = append(lines, strings.Repeat(" ", agentWidth+idWidth+2)+mutedStyle.Render(string(axis)))
+	lines = append(lines, "")
+
+	barStyle := t.Renderer.NewStyle().Foreground(t.Primary)
+	pinnedStyle := t.Renderer.NewStyle().Foreground(t.Feature)
+	agentStyle := t.Renderer.NewStyle().Foreground(t.Secondary).Bold(true)
+	idStyle := t.Renderer.NewStyle().Foreground(t.Secondary)
+
+	end := min(len(m.rows), m.scrollOffset+m.visibleRows())
+	for i := m.scrollOffset; i < end; i++ {
+		row := m.rows[i]
+		agent := ""
+		if i == m.scrollOffset || m.rows[i-1].Agent != row.Agent {
+			agent = row.Agent
+		}
+
+		start, stop := col(row.StartMinutes), col(row.EndMinutes)
+		if stop <= start {
+			stop = min(barWidth, start+1)
+		}
+		style := barStyle
+		if row.Pinned {
+			style = pinnedStyle
+		}
+		bar := strings.Repeat(" ", start) + style.Render(strings.Repeat("█", stop-start)) + strings.Repeat(" ", barWidth-stop)
+
+		line := agentStyle.Render(padRight(truncateRunesHelper(agent, agentWidth-1, "…"), agentWidth)) +
+			idStyle.Render(padRight(truncateRunesHelper(row.IssueID, idWidth-1, "…"), idWidth)) +
+			"  " + bar + "  " + truncateRunesHelper(row.Title, titleWidth, "…")
+
+		lineStyle := t.Renderer.NewStyle().Width(m.width - 2)
+		if i == m.selected {
+			lineStyle = lineStyle.Background(t.Highlight).Bold(true)
+		}
+		lines = append(lines, lineStyle.Render(line))
+	}
+
+	if end == len(m.rows) && len(s.Unscheduled) > 0 {
+		lines = append(lines, "")
+		warnStyle := t.Renderer.NewStyle().Foreground(t.Blocked)
+		for _, u := range s.Unscheduled {
+			if len(lines) >= m.height {
+				break
+			}
+			lines = append(lines, warnStyle.Render(fmt.Sprintf("  ⚠ %s: %s", u.IssueID, u.Reason)))
+		}
+	}
+
+	if len(lines) > m.height {
+		lines = lines[:m.height]
+	}
+	return strings.Join(lines, "\n")
+}
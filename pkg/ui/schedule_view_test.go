@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestScheduleRenderEmpty(t *testing.T) {
+	m := NewScheduleModel(analysis.Schedule{}, "assignees", newTestTheme())
+	m.SetSize(100, 20)
+
+	if out := m.Render(); !strings.Contains(out, "Nothing to schedule") {
+		t.Fatalf("expected empty state message, got:\n%s", out)
+	}
+}
+
+func TestScheduleRenderAndNavigate(t *testing.T) {
+	minutes := 240
+	issues := []model.Issue{
+		{ID: "A", Title: "Build API", Status: model.StatusOpen, IssueType: model.TypeTask, EstimatedMinutes: &minutes},
+		{ID: "B", Title: "Wire UI", Status: model.StatusOpen, IssueType: model.TypeTask, EstimatedMinutes: &minutes,
+			Dependencies: []*model.Dependency{{IssueID: "B", DependsOnID: "A", Type: model.DepBlocks}}},
+		{ID: "C", Title: "Docs", Status: model.StatusOpen, IssueType: model.TypeTask, EstimatedMinutes: &minutes},
+		{ID: "D", Title: "Loop", Status: model.StatusOpen, IssueType: model.TypeTask,
+			Dependencies: []*model.Dependency{{IssueID: "D", DependsOnID: "D2", Type: model.DepBlocks}}},
+		{ID: "D2", Title: "Loop", Status: model.StatusOpen, IssueType: model.TypeTask,
+			Dependencies: []*model.Dependency{{IssueID: "D2", DependsOnID: "D", Type: model.DepBlocks}}},
+	}
+	sched := analysis.ComputeSchedule(issues, nil, analysis.ScheduleOptions{
+		Roster: []analysis.RosterAgent{{Name: "ann"}, {Name: "bob"}},
+		Now:    time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC),
+	})
+
+	m := NewScheduleModel(sched, ".bv/roster.yaml", newTestTheme())
+	m.SetSize(120, 30)
+	out := m.Render()
+	for _, want := range []string{"3 issues across 2 agents", "ann", "bob", "Build API", "█", "D: in or waiting on a dependency cycle"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("render missing %q:\n%s", want, out)
+		}
+	}
+
+	if got := m.SelectedIssueID(); got != "A" {
+		t.Fatalf("initial selection = %s, want A", got)
+	}
+	m.MoveDown()
+	m.MoveDown()
+	m.MoveDown()
+	if got := m.SelectedIssueID(); got != "C" {
+		t.Fatalf("selection after moving past the end = %s, want C", got)
+	}
+	m.MoveUp()
+	if got := m.SelectedIssueID(); got != "B" {
+		t.Fatalf("selection after MoveUp = %s, want B", got)
+	}
+}
+
+func TestScheduleViewToggle(t *testing.T) {
+	m := NewModel([]model.Issue{{ID: "A", Title: "A", Status: model.StatusOpen, IssueType: model.TypeTask}}, nil, "")
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("W")})
+	m = updated.(Model)
+	if !m.isScheduleView || m.focused != focusSchedule {
+		t.Fatalf("W should open the schedule view (view=%v focus=%v)", m.isScheduleView, m.focused)
+	}
+	if !strings.Contains(m.View(), "SCHEDULE") {
+		t.Errorf("schedule view not rendered")
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if m.isScheduleView || m.focused != focusList {
+		t.Fatalf("Esc should close the schedule view")
+	}
+}
@@ -326,7 +326,7 @@ func (s *SemanticSearch) ComputeSemanticResults(term string) []list.Rank {
 	scoredItems := make([]scored, len(snap.IDs))
 	scoreMap := make(map[string]SemanticScore, len(snap.IDs))
 	for i, id := range snap.IDs {
-		entry, ok := snap.Index.Get(id)
+		best, ok := snap.Index.IssueScore(id, q)
 		textScore := 0.0
 		score := 0.0
 		if !ok {
@@ -335,7 +335,7 @@ func (s *SemanticSearch) ComputeSemanticResults(term string) []list.Rank {
 			score = -2.0
 			textScore = score
 		} else {
-			textScore = dotFloat32(q, entry.Vector)
+			textScore = best.Score
 			if doc, ok := snap.Docs[id]; ok {
 				textScore += search.ShortQueryLexicalBoost(term, doc)
 			}
@@ -499,7 +499,7 @@ func BuildSemanticIndexCmd(issues []model.Issue) tea.Cmd {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
-		docs := search.DocumentsFromIssues(issues)
+		docs := search.ChunkDocuments(search.ChunksFromIssues(issues))
 		stats, err := search.SyncVectorIndex(ctx, idx, embedder, docs, 64)
 		if err != nil {
 			return SemanticIndexReadyMsg{Error: err}
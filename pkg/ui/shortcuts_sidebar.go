@@ -108,6 +108,11 @@ func (s *ShortcutsSidebar) allSections() []shortcutSection {
 				{"g", "Graph"},
 				{"h", "History"},
 				{"i", "Insights"},
+				{"W", "Schedule"},
+				{"Y", "Timeline"},
+				{"B", "Epics"},
+				{"Q", "Escalations"},
+				{"I", "Agents"},
 				{"?", "Help"},
 				{";", "This sidebar"},
 				{"p", "Priority ↑↓"},
@@ -120,6 +125,10 @@ func (s *ShortcutsSidebar) allSections() []shortcutSection {
 				{"hjkl", "Navigate"},
 				{"H/L", "Scroll ←/→"},
 				{"PgUp/Dn", "Scroll ↑/↓"},
+				{"d", "Full DAG"},
+				{"z", "Zoom"},
+				{"s", "Label scope"},
+				{"/", "Jump to ID"},
 				{"Enter", "Jump to issue"},
 			},
 		},
@@ -186,6 +195,18 @@ func (s *ShortcutsSidebar) allSections() []shortcutSection {
 				{"V", "Cass sessions"},
 			},
 		},
+		{
+			title:    "Edit",
+			contexts: []string{"list", "split"},
+			items: []shortcutItem{
+				{"E/P/A", "Status/prio/assignee"},
+				{"+", "Labels"},
+				{"D/-", "Add/remove blocker"},
+				{"M", "Comment"},
+				{"X", "Close"},
+				{"N", "New issue"},
+			},
+		},
 	}
 }
 
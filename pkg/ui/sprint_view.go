@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -172,6 +173,12 @@ func (m Model) renderSprintDashboard() string {
 		sb.WriteString("\n\n")
 	}
 
+	// Monte Carlo completion forecast for the sprint's open beads
+	sb.WriteString(labelStyle.Render("Forecast:"))
+	sb.WriteString("\n")
+	sb.WriteString(m.renderSprintForecast(*sprint))
+	sb.WriteString("\n")
+
 	// At-risk items (in_progress for more than X days without update)
 	sb.WriteString(labelStyle.Render("At Risk:"))
 	sb.WriteString("\n")
@@ -250,6 +257,93 @@ func (m Model) renderSprintDashboard() string {
 	)
 }
 
+// sprintForecastTrials keeps the synchronous sprint-view simulation cheap.
+const sprintForecastTrials = 500
+
+// refreshSprintForecast simulates the completion of the selected sprint's
+// open beads. It runs when the sprint or the issues change, so rendering only
+// reads the cached result.
+func (m *Model) refreshSprintForecast(now time.Time) {
+	m.sprintForecast = nil
+	if m.selectedSprint == nil {
+		return
+	}
+	sprint := *m.selectedSprint
+	fc := analysis.ForecastMonteCarlo(m.issues, analysis.MonteCarloConfig{
+		Trials:  sprintForecastTrials,
+		Seed:    1,
+		Now:     now,
+		Focus:   sprint.BeadIDs,
+		Sprints: []model.Sprint{sprint},
+	})
+	m.sprintForecast = &fc
+}
+
+// renderSprintForecast renders the cached forecast of the sprint's open beads:
+// the P50/P80/P95 dates with a one-line histogram of finish times.
+func (m Model) renderSprintForecast(sprint model.Sprint) string {
+	t := m.theme
+	valStyle := t.Renderer.NewStyle().Foreground(t.Base.GetForeground())
+	mutedStyle := t.Renderer.NewStyle().Foreground(t.Muted).Italic(true)
+
+	if m.sprintForecast == nil {
+		return mutedStyle.Render("  (forecast not computed)") + "\n"
+	}
+	fc := *m.sprintForecast
+	if len(fc.Sprints) == 0 {
+		return valStyle.Render("  (no open beads)") + "\n"
+	}
+	g := fc.Sprints[0]
+
+	var sb strings.Builder
+	for _, q := range []struct {
+		label string
+		date  time.Time
+	}{{"P50", g.P50}, {"P80", g.P80}, {"P95", g.P95}} {
+		style := valStyle
+		if !sprint.EndDate.IsZero() && q.date.After(sprint.EndDate) {
+			style = t.Renderer.NewStyle().Foreground(t.Blocked)
+		}
+		sb.WriteString(style.Render(fmt.Sprintf("  %s %s ", q.label, q.date.Format("Jan 2"))))
+	}
+	sb.WriteString("\n")
+
+	if len(g.Histogram) > 0 {
+		levels := []rune("▁▂▃▄▅▆▇█")
+		peak := 0
+		for _, bin := range g.Histogram {
+			peak = max(peak, bin.Count)
+		}
+		var bars strings.Builder
+		for _, bin := range g.Histogram {
+			level := 0
+			if peak > 0 {
+				level = bin.Count * (len(levels) - 1) / peak
+			}
+			bars.WriteRune(levels[level])
+		}
+		sb.WriteString("  ")
+		sb.WriteString(t.Renderer.NewStyle().Foreground(t.Primary).Render(bars.String()))
+		sb.WriteString(mutedStyle.Render(fmt.Sprintf(" %.1fd … %.1fd", g.Histogram[0].FromDays, g.Histogram[len(g.Histogram)-1].ToDays)))
+		sb.WriteString("\n")
+	}
+	if len(fc.Unschedulable) > 0 {
+		sb.WriteString(t.Renderer.NewStyle().Foreground(t.Feature).Render(
+			fmt.Sprintf("  ⚠ %d bead(s) blocked by a cycle", len(fc.Unschedulable))))
+		sb.WriteString("\n")
+	}
+	sb.WriteString(mutedStyle.Render(fmt.Sprintf("  %d trials, %s", fc.Trials, forecastSourceLabel(fc))))
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+func forecastSourceLabel(fc analysis.MonteCarloForecast) string {
+	if fc.SampleSource == "history" {
+		return fmt.Sprintf("%d closed beads sampled", fc.CycleTimeSamples)
+	}
+	return "from estimates (no closed history)"
+}
+
 // truncateStrSprint truncates a string to maxLen runes, adding ellipsis if needed.
 // Uses rune-based counting to safely handle UTF-8 multi-byte characters.
 func truncateStrSprint(s string, maxLen int) string {
@@ -276,6 +370,7 @@ func (m Model) handleSprintKeys(msg tea.KeyMsg) Model {
 			for i, s := range m.sprints {
 				if s.ID == m.selectedSprint.ID && i < len(m.sprints)-1 {
 					m.selectedSprint = &m.sprints[i+1]
+					m.refreshSprintForecast(time.Now())
 					m.sprintViewText = m.renderSprintDashboard()
 					break
 				}
@@ -287,6 +382,7 @@ func (m Model) handleSprintKeys(msg tea.KeyMsg) Model {
 			for i, s := range m.sprints {
 				if s.ID == m.selectedSprint.ID && i > 0 {
 					m.selectedSprint = &m.sprints[i-1]
+					m.refreshSprintForecast(time.Now())
 					m.sprintViewText = m.renderSprintDashboard()
 					break
 				}
@@ -415,6 +415,39 @@ func TestRenderSprintDashboard_InsufficientData(t *testing.T) {
 	}
 }
 
+func TestRenderSprintForecast(t *testing.T) {
+	now := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+	sprint := model.Sprint{ID: "s1", Name: "Sprint 1", EndDate: now.AddDate(0, 0, 1), BeadIDs: []string{"A", "B", "C"}}
+	estimate := 960 // two workdays
+	m := Model{
+		theme: DefaultTheme(lipgloss.NewRenderer(nil)),
+		issues: []model.Issue{
+			{ID: "A", Title: "A", Status: model.StatusOpen, IssueType: model.TypeTask, EstimatedMinutes: &estimate},
+			{ID: "B", Title: "B", Status: model.StatusOpen, IssueType: model.TypeTask, EstimatedMinutes: &estimate,
+				Dependencies: []*model.Dependency{{IssueID: "B", DependsOnID: "A", Type: model.DepBlocks}}},
+			{ID: "C", Title: "C", Status: model.StatusClosed, IssueType: model.TypeTask},
+		},
+	}
+
+	if got := m.renderSprintForecast(sprint); !containsStr(got, "not computed") {
+		t.Errorf("expected a placeholder before the forecast is computed, got %q", got)
+	}
+	m.selectedSprint = &sprint
+	m.refreshSprintForecast(now)
+	got := m.renderSprintForecast(sprint)
+	for _, want := range []string{"P50", "P80", "P95", "500 trials", "from estimates"} {
+		if !containsStr(got, want) {
+			t.Errorf("forecast missing %q:\n%s", want, got)
+		}
+	}
+
+	sprint.BeadIDs = []string{"C"}
+	m.refreshSprintForecast(now)
+	if got := m.renderSprintForecast(sprint); !containsStr(got, "no open beads") {
+		t.Errorf("expected a note for a sprint without open beads, got %q", got)
+	}
+}
+
 func TestHandleSprintKeys_EscExit(t *testing.T) {
 	m := Model{
 		isSprintView: true,
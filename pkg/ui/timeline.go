@@ -0,0 +1,623 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// TimelineZoom selects how much time one column of the timeline covers.
+type TimelineZoom int
+
+const (
+	TimelineZoomDay TimelineZoom = iota
+	TimelineZoomWeek
+	TimelineZoomMonth
+)
+
+// String returns the zoom level name shown in the header
+func (z TimelineZoom) String() string {
+	switch z {
+	case TimelineZoomWeek:
+		return "week"
+	case TimelineZoomMonth:
+		return "month"
+	default:
+		return "day"
+	}
+}
+
+// cell is the span of time covered by one column.
+func (z TimelineZoom) cell() time.Duration {
+	switch z {
+	case TimelineZoomWeek:
+		return 7 * 24 * time.Hour
+	case TimelineZoomMonth:
+		return 30 * 24 * time.Hour
+	default:
+		return 24 * time.Hour
+	}
+}
+
+// labelEvery is the number of columns between axis labels; it must leave
+// room for labelFormat plus a space.
+func (z TimelineZoom) labelEvery() int {
+	switch z {
+	case TimelineZoomWeek:
+		return 8
+	case TimelineZoomMonth:
+		return 12
+	default:
+		return 7
+	}
+}
+
+func (z TimelineZoom) labelFormat() string {
+	if z == TimelineZoomMonth {
+		return "Jan 2006"
+	}
+	return "Jan 2"
+}
+
+// timelineRow is one open issue laid out on the time axis.
+type timelineRow struct {
+	issue     model.Issue
+	created   time.Time
+	workStart time.Time // scheduled start; zero when the issue could not be scheduled
+	eta       time.Time // forecast finish; zero when the issue could not be scheduled
+	overdue   bool      // forecast (or today, without one) is past the due date or deadline
+	critical  bool
+	blockers  []int // rows of open issues this one waits on
+}
+
+// Cell kinds used while painting a row before it is styled.
+const (
+	tlEmpty = iota
+	tlToday
+	tlWait
+	tlWork
+	tlCritical
+	tlOverdue
+	tlMarker
+	tlArrow
+)
+
+// TimelineModel renders open issues as bars on a calendar axis, from creation
+// to forecast completion, with due-date markers and dependency arrows for the
+// selected issue.
+type TimelineModel struct {
+	rows         []timelineRow
+	selected     int
+	scrollOffset int
+	zoom         TimelineZoom
+	pan          int // columns the view is scrolled past today's default position
+	now          time.Time
+	width        int
+	height       int
+	theme        Theme
+}
+
+// NewTimelineModel lays out the open issues using the finish times from
+// schedule. stats supplies CriticalPathScore for the critical path highlight
+// and may be nil.
+func NewTimelineModel(issues []model.Issue, stats *analysis.GraphStats, schedule analysis.Schedule, now time.Time, theme Theme) TimelineModel {
+	m := TimelineModel{now: now, theme: theme}
+
+	for _, issue := range issues {
+		if issue.Status.IsClosed() || issue.Status.IsTombstone() {
+			continue
+		}
+		row := timelineRow{issue: issue, created: issue.CreatedAt}
+		if si, ok := schedule.Issue(issue.ID); ok {
+			row.workStart, row.eta = si.Start, si.End
+		}
+		if row.created.IsZero() || row.created.After(now) {
+			row.created = now
+		}
+		if target := timelineTarget(issue); target != nil {
+			finish := row.eta
+			if finish.IsZero() {
+				finish = now
+			}
+			row.overdue = finish.After(*target)
+		}
+		m.rows = append(m.rows, row)
+	}
+
+	// Gantt order: by scheduled start, then finish; unscheduled work sinks to the bottom.
+	sort.SliceStable(m.rows, func(i, j int) bool {
+		a, b := m.rows[i], m.rows[j]
+		if a.workStart.IsZero() != b.workStart.IsZero() {
+			return !a.workStart.IsZero()
+		}
+		if !a.workStart.Equal(b.workStart) {
+			return a.workStart.Before(b.workStart)
+		}
+		if !a.eta.Equal(b.eta) {
+			return a.eta.Before(b.eta)
+		}
+		return a.issue.ID < b.issue.ID
+	})
+
+	index := make(map[string]int, len(m.rows))
+	for i, row := range m.rows {
+		index[row.issue.ID] = i
+	}
+	for i := range m.rows {
+		for _, dep := range m.rows[i].issue.Dependencies {
+			if dep == nil || !dep.Type.IsBlocking() {
+				continue
+			}
+			if j, ok := index[dep.DependsOnID]; ok && j != i {
+				m.rows[i].blockers = append(m.rows[i].blockers, j)
+			}
+		}
+	}
+	m.markCriticalPath(stats)
+	return m
+}
+
+// timelineTarget returns the earlier of an issue's due date and deadline.
+func timelineTarget(issue model.Issue) *time.Time {
+	target := issue.DueDate
+	if issue.Deadline != nil && (target == nil || issue.Deadline.Before(*target)) {
+		target = issue.Deadline
+	}
+	return target
+}
+
+// markCriticalPath flags the longest chain of open work. CriticalPathScore is
+// the height of the dependent chain above an issue, so the chain starts at the
+// highest-scoring issue and follows its highest-scoring dependent.
+func (m *TimelineModel) markCriticalPath(stats *analysis.GraphStats) {
+	if stats == nil || len(m.rows) == 0 {
+		return
+	}
+	scores := stats.CriticalPathScore()
+	dependents := make([][]int, len(m.rows))
+	for i, row := range m.rows {
+		for _, b := range row.blockers {
+			dependents[b] = append(dependents[b], i)
+		}
+	}
+	better := func(a, b int) bool {
+		sa, sb := scores[m.rows[a].issue.ID], scores[m.rows[b].issue.ID]
+		if sa != sb {
+			return sa > sb
+		}
+		return m.rows[a].issue.ID < m.rows[b].issue.ID
+	}
+
+	cur := 0
+	for i := range m.rows {
+		if better(i, cur) {
+			cur = i
+		}
+	}
+	// A lone issue is not a path.
+	if scores[m.rows[cur].issue.ID] < 2 {
+		return
+	}
+	for {
+		m.rows[cur].critical = true
+		next := -1
+		for _, d := range dependents[cur] {
+			if !m.rows[d].critical && (next < 0 || better(d, next)) {
+				next = d
+			}
+		}
+		if next < 0 {
+			return
+		}
+		cur = next
+	}
+}
+
+// SetSize updates the view dimensions
+func (m *TimelineModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// Zoom returns the current zoom level
+func (m *TimelineModel) Zoom() TimelineZoom {
+	return m.zoom
+}
+
+// SetZoom changes the zoom level and recentres the axis on today
+func (m *TimelineModel) SetZoom(z TimelineZoom) {
+	m.zoom = z
+	m.pan = 0
+}
+
+// CycleZoom steps day → week → month → day
+func (m *TimelineModel) CycleZoom() {
+	m.SetZoom((m.zoom + 1) % 3)
+}
+
+// MoveUp moves selection up
+func (m *TimelineModel) MoveUp() {
+	if m.selected > 0 {
+		m.selected--
+	}
+	m.ensureVisible()
+}
+
+// MoveDown moves selection down
+func (m *TimelineModel) MoveDown() {
+	if m.selected < len(m.rows)-1 {
+		m.selected++
+	}
+	m.ensureVisible()
+}
+
+// ScrollLeft moves the visible window earlier in time
+func (m *TimelineModel) ScrollLeft() {
+	m.pan -= m.panStep()
+}
+
+// ScrollRight moves the visible window later in time
+func (m *TimelineModel) ScrollRight() {
+	m.pan += m.panStep()
+}
+
+func (m *TimelineModel) panStep() int {
+	return max(1, m.barWidth()/4)
+}
+
+// SelectedIssueID returns the ID of the currently selected issue
+func (m *TimelineModel) SelectedIssueID() string {
+	if m.selected >= len(m.rows) {
+		return ""
+	}
+	return m.rows[m.selected].issue.ID
+}
+
+func (m *TimelineModel) visibleRows() int {
+	// Header, two axis lines, blank line, detail line and legend.
+	return max(1, m.height-6)
+}
+
+func (m *TimelineModel) ensureVisible() {
+	if m.selected < m.scrollOffset {
+		m.scrollOffset = m.selected
+	}
+	if m.selected >= m.scrollOffset+m.visibleRows() {
+		m.scrollOffset = m.selected - m.visibleRows() + 1
+	}
+}
+
+// Column layout: id | title | bars
+const timelineIDWidth = 12
+
+func (m *TimelineModel) titleWidth() int {
+	if m.width >= 100 {
+		return 24
+	}
+	return 0
+}
+
+func (m *TimelineModel) barWidth() int {
+	return max(10, m.width-timelineIDWidth-m.titleWidth()-4)
+}
+
+// anchor is the start of the column that contains now.
+func (m *TimelineModel) anchor() time.Time {
+	y, mo, d := m.now.Date()
+	day := time.Date(y, mo, d, 0, 0, 0, 0, m.now.Location())
+	switch m.zoom {
+	case TimelineZoomWeek:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7)) // back to Monday
+	case TimelineZoomMonth:
+		return time.Date(y, mo, 1, 0, 0, 0, 0, m.now.Location())
+	}
+	return day
+}
+
+// todayCol is the column of now; by default a quarter of the way in, so
+// some history stays visible.
+func (m *TimelineModel) todayCol() int {
+	return m.barWidth()/4 - m.pan
+}
+
+// col maps a time to its (possibly off-screen) column.
+func (m *TimelineModel) col(t time.Time) int {
+	cells := float64(t.Sub(m.anchor())) / float64(m.zoom.cell())
+	return m.todayCol() + int(math.Floor(cells))
+}
+
+// timeAt is the start of column c.
+func (m *TimelineModel) timeAt(c int) time.Time {
+	return m.anchor().Add(time.Duration(c-m.todayCol()) * m.zoom.cell())
+}
+
+// paintRow lays out one row's bar, markers included, before styling.
+func (m *TimelineModel) paintRow(row timelineRow, runes []rune, kinds []int) {
+	w := len(runes)
+	fill := func(from, to int, r rune, kind int) {
+		for c := max(0, from); c < min(w, to); c++ {
+			runes[c], kinds[c] = r, kind
+		}
+	}
+	set := func(c int, r rune, kind int) {
+		if c >= 0 && c < w {
+			runes[c], kinds[c] = r, kind
+		}
+	}
+
+	fill(0, w, ' ', tlEmpty)
+	set(m.todayCol(), '┊', tlToday)
+
+	if row.eta.IsZero() {
+		// No forecast: waiting since creation, finish unknown.
+		fill(m.col(row.created), m.col(m.now)+1, '░', tlWait)
+		set(m.col(m.now)+1, '?', tlOverdue)
+	} else {
+		start := m.col(row.workStart)
+		end := max(start+1, m.col(row.eta)+1)
+		fill(m.col(row.created), start, '░', tlWait)
+		kind := tlWork
+		switch {
+		case row.overdue:
+			kind = tlOverdue
+		case row.critical:
+			kind = tlCritical
+		}
+		fill(start, end, '█', kind)
+	}
+
+	if row.issue.DueDate != nil {
+		set(m.col(*row.issue.DueDate), '◆', tlMarker)
+	}
+	if row.issue.Deadline != nil {
+		set(m.col(*row.issue.Deadline), '◈', tlMarker)
+	}
+}
+
+// paintArrows draws connectors from each blocker's finish to the selected
+// issue's start. painted holds the visible rows, indexed from scrollOffset.
+func (m *TimelineModel) paintArrows(painted [][]rune, kinds [][]int) {
+	if m.selected >= len(m.rows) {
+		return
+	}
+	sel := m.rows[m.selected]
+	selStart := m.col(sel.workStart)
+	if sel.eta.IsZero() {
+		selStart = m.col(sel.created)
+	}
+	set := func(row, c int, r rune) {
+		i := row - m.scrollOffset
+		if i < 0 || i >= len(painted) || c < 0 || c >= len(painted[i]) {
+			return
+		}
+		painted[i][c], kinds[i][c] = r, tlArrow
+	}
+	blank := func(row, c int) bool {
+		i := row - m.scrollOffset
+		if i < 0 || i >= len(painted) || c < 0 || c >= len(painted[i]) {
+			return false
+		}
+		return kinds[i][c] == tlEmpty || kinds[i][c] == tlToday || kinds[i][c] == tlWait
+	}
+
+	for _, b := range sel.blockers {
+		blocker := m.rows[b]
+		x := m.col(blocker.created) + 1
+		if !blocker.eta.IsZero() {
+			x = m.col(blocker.eta) + 1
+		}
+		if x >= selStart {
+			// Overlapping estimates: just point at the start.
+			set(m.selected, selStart-1, '▶')
+			continue
+		}
+		down := b < m.selected
+		if down {
+			set(b, x, '┐')
+		} else {
+			set(b, x, '┘')
+		}
+		for r := min(b, m.selected) + 1; r < max(b, m.selected); r++ {
+			if blank(r, x) {
+				set(r, x, '│')
+			}
+		}
+		if down {
+			set(m.selected, x, '└')
+		} else {
+			set(m.selected, x, '┌')
+		}
+		for c := x + 1; c < selStart-1; c++ {
+			set(m.selected, c, '─')
+		}
+		set(m.selected, selStart-1, '▶')
+	}
+}
+
+// Render renders the timeline view
+func (m *TimelineModel) Render() string {
+	if m.width == 0 || m.height == 0 {
+		return ""
+	}
+	t := m.theme
+	var lines []string
+
+	headerStyle := t.Renderer.NewStyle().
+		Bold(true).
+		Foreground(t.Base.GetForeground()).
+		Background(t.Primary).
+		Padding(0, 2).
+		Width(m.width - 4)
+	critical := 0
+	overdue := 0
+	for _, row := range m.rows {
+		if row.critical {
+			critical++
+		}
+		if row.overdue {
+			overdue++
+		}
+	}
+	header := fmt.Sprintf("📅 TIMELINE  │  %d open  │  zoom: %s", len(m.rows), m.zoom)
+	if critical > 0 {
+		header += fmt.Sprintf("  │  %d on critical path", critical)
+	}
+	if overdue > 0 {
+		header += fmt.Sprintf("  │  %d overdue", overdue)
+	}
+	lines = append(lines, headerStyle.Render(header))
+
+	if len(m.rows) == 0 {
+		emptyStyle := t.Renderer.NewStyle().
+			Foreground(t.Subtext).
+			Italic(true).
+			Padding(2, 4).
+			Width(m.width - 4).
+			Align(lipgloss.Center)
+		lines = append(lines, emptyStyle.Render("✓ No open issues to plot."))
+		return strings.Join(lines, "\n")
+	}
+
+	mutedStyle := t.Renderer.NewStyle().Foreground(t.Subtext)
+	barWidth := m.barWidth()
+	indent := strings.Repeat(" ", timelineIDWidth+m.titleWidth()+2)
+
+	// Axis: date labels above ticks, aligned to today's column.
+	labels := []rune(strings.Repeat(" ", barWidth))
+	ticks := []rune(strings.Repeat("─", barWidth))
+	every := m.zoom.labelEvery()
+	nextFree := 0
+	for c := 0; c < barWidth; c++ {
+		if (c-m.todayCol())%every != 0 {
+			continue
+		}
+		ticks[c] = '┬'
+		text := []rune(m.timeAt(c).Format(m.zoom.labelFormat()))
+		if c >= nextFree && c+len(text) <= barWidth {
+			copy(labels[c:], text)
+			nextFree = c + len(text) + 1
+		}
+	}
+	if c := m.todayCol(); c >= 0 && c < barWidth {
+		ticks[c] = '▼'
+	}
+	lines = append(lines, indent+mutedStyle.Render(string(labels)))
+	lines = append(lines, indent+mutedStyle.Render(string(ticks)))
+
+	end := min(len(m.rows), m.scrollOffset+m.visibleRows())
+	painted := make([][]rune, 0, end-m.scrollOffset)
+	kinds := make([][]int, 0, end-m.scrollOffset)
+	for i := m.scrollOffset; i < end; i++ {
+		runes, k := make([]rune, barWidth), make([]int, barWidth)
+		m.paintRow(m.rows[i], runes, k)
+		painted = append(painted, runes)
+		kinds = append(kinds, k)
+	}
+	m.paintArrows(painted, kinds)
+
+	styles := map[int]lipgloss.Style{
+		tlEmpty:    t.Renderer.NewStyle(),
+		tlToday:    t.Renderer.NewStyle().Foreground(t.InProgress),
+		tlWait:     t.Renderer.NewStyle().Foreground(t.Secondary),
+		tlWork:     t.Renderer.NewStyle().Foreground(t.Primary),
+		tlCritical: t.Renderer.NewStyle().Foreground(t.Feature).Bold(true),
+		tlOverdue:  t.Renderer.NewStyle().Foreground(t.Blocked).Bold(true),
+		tlMarker:   t.Renderer.NewStyle().Foreground(t.Open).Bold(true),
+		tlArrow:    t.Renderer.NewStyle().Foreground(t.InProgress).Bold(true),
+	}
+	idStyle := t.Renderer.NewStyle().Foreground(t.Secondary)
+	criticalIDStyle := t.Renderer.NewStyle().Foreground(t.Feature).Bold(true)
+
+	for i := m.scrollOffset; i < end; i++ {
+		row := m.rows[i]
+		runes, k := painted[i-m.scrollOffset], kinds[i-m.scrollOffset]
+
+		var bar strings.Builder
+		for c := 0; c < barWidth; {
+			next := c
+			for next < barWidth && k[next] == k[c] {
+				next++
+			}
+			bar.WriteString(styles[k[c]].Render(string(runes[c:next])))
+			c = next
+		}
+
+		ids := idStyle
+		if row.critical {
+			ids = criticalIDStyle
+		}
+		line := ids.Render(padRight(truncateRunesHelper(row.issue.ID, timelineIDWidth-1, "…"), timelineIDWidth))
+		if tw := m.titleWidth(); tw > 0 {
+			line += padRight(truncateRunesHelper(row.issue.Title, tw-1, "…"), tw)
+		}
+		line += "  " + bar.String()
+
+		lineStyle := t.Renderer.NewStyle().Width(m.width - 2)
+		if i == m.selected {
+			lineStyle = lineStyle.Background(t.Highlight).Bold(true)
+		}
+		lines = append(lines, lineStyle.Render(line))
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, m.renderSelectedDetail())
+	lines = append(lines, mutedStyle.Render("█ work  ░ waiting  ◆ due  ◈ deadline  ┊ today  ")+
+		styles[tlCritical].Render("█ critical path")+mutedStyle.Render("  ")+
+		styles[tlOverdue].Render("█ overdue"))
+
+	if len(lines) > m.height {
+		lines = lines[:m.height]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderSelectedDetail summarises the selected issue's dates and blockers.
+func (m *TimelineModel) renderSelectedDetail() string {
+	t := m.theme
+	if m.selected >= len(m.rows) {
+		return ""
+	}
+	row := m.rows[m.selected]
+	const day = "Jan 2"
+
+	parts := []string{row.issue.ID, "created " + row.created.Format(day)}
+	if row.eta.IsZero() {
+		parts = append(parts, "no forecast (dependency cycle or no eligible agent)")
+	} else {
+		parts = append(parts, "starts "+row.workStart.Format(day), "ETA "+row.eta.Format(day))
+	}
+	if target := timelineTarget(row.issue); target != nil {
+		due := "due " + target.Format(day)
+		if row.overdue {
+			finish := row.eta
+			if finish.IsZero() {
+				finish = m.now
+			}
+			due += fmt.Sprintf(" (%dd late)", int(math.Ceil(finish.Sub(*target).Hours()/24)))
+		}
+		parts = append(parts, due)
+	}
+	if row.critical {
+		parts = append(parts, "critical path")
+	}
+	if len(row.blockers) > 0 {
+		ids := make([]string, 0, len(row.blockers))
+		for _, b := range row.blockers {
+			ids = append(ids, m.rows[b].issue.ID)
+		}
+		parts = append(parts, "waits on "+strings.Join(ids, ", "))
+	}
+
+	style := t.Renderer.NewStyle().Foreground(t.Base.GetForeground())
+	if row.overdue {
+		style = style.Foreground(t.Blocked)
+	}
+	return style.Render(truncateRunesHelper(strings.Join(parts, " · "), m.width-2, "…"))
+}
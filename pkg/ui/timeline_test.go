@@ -0,0 +1,150 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var timelineNow = time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+
+// timelineFixture is the chain A → B → C (C due before its forecast) plus an
+// independent D, scheduled onto a single agent.
+func timelineFixture(t *testing.T) TimelineModel {
+	t.Helper()
+	minutes, short := 240, 60
+	due := time.Date(2025, 6, 3, 9, 0, 0, 0, time.UTC)
+	created := timelineNow.AddDate(0, 0, -7)
+	blocks := func(from, to string) []*model.Dependency {
+		return []*model.Dependency{{IssueID: from, DependsOnID: to, Type: model.DepBlocks}}
+	}
+	issues := []model.Issue{
+		{ID: "A", Title: "Schema", Status: model.StatusOpen, IssueType: model.TypeTask, EstimatedMinutes: &minutes, CreatedAt: created},
+		{ID: "B", Title: "API", Status: model.StatusOpen, IssueType: model.TypeTask, EstimatedMinutes: &minutes, CreatedAt: created, Dependencies: blocks("B", "A")},
+		{ID: "C", Title: "UI", Status: model.StatusOpen, IssueType: model.TypeTask, EstimatedMinutes: &minutes, CreatedAt: created, DueDate: &due, Dependencies: blocks("C", "B")},
+		{ID: "D", Title: "Docs", Status: model.StatusOpen, IssueType: model.TypeTask, EstimatedMinutes: &short, CreatedAt: created},
+		{ID: "Z", Title: "Done", Status: model.StatusClosed, IssueType: model.TypeTask, CreatedAt: created},
+	}
+	stats := analysis.NewAnalyzer(issues).Analyze()
+	sched := analysis.ComputeSchedule(issues, &stats, analysis.ScheduleOptions{
+		Roster: []analysis.RosterAgent{{Name: "ann"}},
+		Now:    timelineNow,
+	})
+	m := NewTimelineModel(issues, &stats, sched, timelineNow, newTestTheme())
+	m.SetSize(120, 20)
+	return m
+}
+
+func TestTimelineRowsCriticalPathAndOverdue(t *testing.T) {
+	m := timelineFixture(t)
+
+	if len(m.rows) != 4 {
+		t.Fatalf("expected 4 open rows, got %d", len(m.rows))
+	}
+	want := map[string]struct{ critical, overdue bool }{
+		"A": {true, false}, "B": {true, false}, "C": {true, true}, "D": {false, false},
+	}
+	for _, row := range m.rows {
+		w := want[row.issue.ID]
+		if row.critical != w.critical || row.overdue != w.overdue {
+			t.Errorf("%s: critical=%v overdue=%v, want %v/%v", row.issue.ID, row.critical, row.overdue, w.critical, w.overdue)
+		}
+		if row.eta.IsZero() {
+			t.Errorf("%s: expected a forecast finish", row.issue.ID)
+		}
+	}
+	if got := m.SelectedIssueID(); got != "A" {
+		t.Errorf("first row = %s, want A (earliest start)", got)
+	}
+}
+
+func TestTimelineRender(t *testing.T) {
+	m := timelineFixture(t)
+	out := m.Render()
+	for _, want := range []string{"TIMELINE", "4 open", "zoom: day", "3 on critical path", "1 overdue", "Jun 2", "▼", "█", "░", "◆", "Schema"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("render missing %q:\n%s", want, out)
+		}
+	}
+
+	// Selecting C shows its lateness and draws an arrow from its blocker.
+	for m.SelectedIssueID() != "C" {
+		m.MoveDown()
+	}
+	out = m.Render()
+	for _, want := range []string{"due Jun 3 (1d late)", "waits on B", "▶"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("render with C selected missing %q:\n%s", want, out)
+		}
+	}
+
+	empty := NewTimelineModel(nil, nil, analysis.Schedule{}, timelineNow, newTestTheme())
+	empty.SetSize(80, 10)
+	if !strings.Contains(empty.Render(), "No open issues") {
+		t.Errorf("expected empty state")
+	}
+}
+
+func TestTimelineZoomAndPan(t *testing.T) {
+	m := timelineFixture(t)
+	today := m.todayCol()
+
+	m.ScrollRight()
+	if m.todayCol() >= today {
+		t.Errorf("ScrollRight should move today left: %d → %d", today, m.todayCol())
+	}
+	m.ScrollLeft()
+	m.ScrollLeft()
+	if m.todayCol() <= today {
+		t.Errorf("ScrollLeft should move today right: %d → %d", today, m.todayCol())
+	}
+
+	m.CycleZoom()
+	if m.Zoom() != TimelineZoomWeek || m.todayCol() != today {
+		t.Fatalf("zoom = %s today=%d; want week, recentred at %d", m.Zoom(), m.todayCol(), today)
+	}
+	if a := m.anchor(); a.Weekday() != time.Monday {
+		t.Errorf("week anchor = %v, want a Monday", a)
+	}
+	m.CycleZoom()
+	if !strings.Contains(m.Render(), "zoom: month") {
+		t.Errorf("expected month zoom in header")
+	}
+	m.CycleZoom()
+	if m.Zoom() != TimelineZoomDay {
+		t.Errorf("zoom should wrap back to day, got %s", m.Zoom())
+	}
+}
+
+func TestTimelineViewKeys(t *testing.T) {
+	m := NewModel([]model.Issue{{ID: "A", Title: "A", Status: model.StatusOpen, IssueType: model.TypeTask, CreatedAt: timelineNow}}, nil, "")
+	press := func(key tea.KeyMsg) {
+		updated, _ := m.Update(key)
+		m = updated.(Model)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("Y")})
+	if !m.isTimelineView || m.focused != focusTimeline {
+		t.Fatalf("Y should open the timeline view")
+	}
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("h")})
+	if m.isHistoryView || m.focused != focusTimeline || m.timelineView.pan >= 0 {
+		t.Fatalf("h should pan the timeline, not open history (history=%v pan=%d)", m.isHistoryView, m.timelineView.pan)
+	}
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("z")})
+	if m.timelineView.Zoom() != TimelineZoomWeek {
+		t.Errorf("z should zoom out to weeks")
+	}
+	if ctx := m.CurrentContext(); ctx != ContextTimeline {
+		t.Errorf("context = %s, want timeline", ctx)
+	}
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.isTimelineView || m.focused != focusList {
+		t.Fatalf("Esc should close the timeline view")
+	}
+}
@@ -3,6 +3,7 @@ package ui
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
@@ -106,3 +107,41 @@ func TestUpdateFileChangedReloadsSelection(t *testing.T) {
 		t.Fatalf("expected successful reload, got error %q", m2.statusMsg)
 	}
 }
+
+func TestUpdateFileChangedReloadsIncrementally(t *testing.T) {
+	tmp := t.TempDir()
+	beads := filepath.Join(tmp, "beads.jsonl")
+	write := func(data string) {
+		t.Helper()
+		if err := os.WriteFile(beads, []byte(data), 0644); err != nil {
+			t.Fatalf("write beads: %v", err)
+		}
+	}
+	write(`{"id":"A","title":"A","status":"open","issue_type":"task"}
+{"id":"B","title":"B","status":"open","issue_type":"task","dependencies":[{"issue_id":"B","depends_on_id":"A","type":"blocks"}]}
+`)
+	m := NewModel(nil, nil, beads)
+
+	// The first reload establishes the baseline with a full rebuild.
+	updated, _ := m.Update(FileChangedMsg{})
+	m = updated.(Model)
+	if m.countReady != 1 || m.analysis.OutDegree["B"] != 1 {
+		t.Fatalf("after full reload: ready=%d out(B)=%d", m.countReady, m.analysis.OutDegree["B"])
+	}
+
+	write(`{"id":"A","title":"A","status":"closed","issue_type":"task"}
+{"id":"B","title":"B","status":"open","issue_type":"task","dependencies":[{"issue_id":"B","depends_on_id":"A","type":"blocks"}]}
+{"id":"C","title":"C","status":"open","issue_type":"task"}
+`)
+	updated, _ = m.Update(FileChangedMsg{})
+	m = updated.(Model)
+	if m.statusIsError || !strings.Contains(m.statusMsg, "(+1 −0 ~1)") {
+		t.Fatalf("expected incremental reload status, got %q", m.statusMsg)
+	}
+	if m.countOpen != 2 || m.countReady != 2 || m.countClosed != 1 {
+		t.Fatalf("counts open=%d ready=%d closed=%d, want 2/2/1", m.countOpen, m.countReady, m.countClosed)
+	}
+	if m.analysis.InDegree["A"] != 1 || m.analysis.NodeCount != 3 {
+		t.Fatalf("graph not patched: in(A)=%d nodes=%d", m.analysis.InDegree["A"], m.analysis.NodeCount)
+	}
+}
@@ -0,0 +1,69 @@
+package main_test
+
+import (
+	"encoding/json"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestAddDep_ReportsImpactAndRejectsCycles(t *testing.T) {
+	bv := buildBvBinary(t)
+	env := t.TempDir()
+
+	writeBeads(t, env, `{"id":"A","title":"A","status":"open","priority":1,"issue_type":"task"}
+{"id":"B","title":"B","status":"open","priority":1,"issue_type":"task","dependencies":[{"issue_id":"B","depends_on_id":"C","type":"blocks"}]}
+{"id":"C","title":"C","status":"open","priority":1,"issue_type":"task"}`)
+
+	run := func(args ...string) ([]byte, error) {
+		cmd := exec.Command(bv, args...)
+		cmd.Dir = env
+		return cmd.Output()
+	}
+
+	out, err := run("--add-dep", "A:B")
+	if err != nil {
+		t.Fatalf("--add-dep A:B failed: %v\n%s", err, out)
+	}
+	var impact struct {
+		GeneratedAt             string `json:"generated_at"`
+		IssueID                 string `json:"issue_id"`
+		DependsOnID             string `json:"depends_on_id"`
+		Type                    string `json:"type"`
+		CriticalPathDepthBefore int    `json:"critical_path_depth_before"`
+		CriticalPathDepthAfter  int    `json:"critical_path_depth_after"`
+		ActionableDelta         int    `json:"actionable_delta"`
+	}
+	if err := json.Unmarshal(out, &impact); err != nil {
+		t.Fatalf("json decode: %v\nout=%s", err, out)
+	}
+	if impact.GeneratedAt == "" || impact.IssueID != "A" || impact.DependsOnID != "B" || impact.Type != "blocks" {
+		t.Fatalf("unexpected payload: %+v", impact)
+	}
+	if impact.CriticalPathDepthBefore != 2 || impact.CriticalPathDepthAfter != 3 || impact.ActionableDelta != -1 {
+		t.Fatalf("unexpected impact: %+v", impact)
+	}
+
+	// C → A would close A → B → C → A.
+	cmd := exec.Command(bv, "--add-dep", "C:A")
+	cmd.Dir = env
+	combined, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected cycle rejection, got success:\n%s", combined)
+	}
+	if !strings.Contains(string(combined), "C → A → B → C") {
+		t.Fatalf("expected cycle path in error, got:\n%s", combined)
+	}
+
+	if out, err := run("--add-dep", "C:A", "--allow-cycle"); err != nil {
+		t.Fatalf("--allow-cycle failed: %v\n%s", err, out)
+	}
+
+	if out, err := run("--add-dep", "A:A"); err == nil {
+		t.Fatalf("expected self-dependency to fail, got:\n%s", out)
+	}
+
+	if out, err := run("--add-dep", "nonsense"); err == nil {
+		t.Fatalf("expected malformed --add-dep to fail, got:\n%s", out)
+	}
+}
@@ -891,6 +891,22 @@ func TestExportPages_ExcludeHistory(t *testing.T) {
 		t.Error("history.json should NOT exist when --pages-include-history=false")
 	}
 
+	// Nor should the commit tables carry any git history
+	db, err := openSQLiteDB(filepath.Join(exportDir, "beads.sqlite3"))
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	defer db.Close()
+	for _, table := range []string{"bead_commits", "bead_events"} {
+		var n int
+		if err := db.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&n); err != nil {
+			t.Fatalf("count %s: %v", table, err)
+		}
+		if n != 0 {
+			t.Errorf("%s has %d rows, want 0 when --pages-include-history=false", table, n)
+		}
+	}
+
 	// Verify other core files still exist
 	for _, p := range []string{
 		filepath.Join(exportDir, "index.html"),
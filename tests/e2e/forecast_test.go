@@ -158,6 +158,74 @@ func TestRobotForecast_AllAndLabelFilter(t *testing.T) {
 	}
 }
 
+func TestRobotForecast_MonteCarlo(t *testing.T) {
+	bv := buildBvBinary(t)
+	repoDir, _ := createForecastRepo(t)
+
+	run := func(args ...string) []byte {
+		cmd := exec.Command(bv, append([]string{"--robot-forecast"}, args...)...)
+		cmd.Dir = repoDir
+		out, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("--robot-forecast %v failed: %v\n%s", args, err, out)
+		}
+		return out
+	}
+
+	out := run("all", "--monte-carlo", "--forecast-trials", "200", "--forecast-label", "backend")
+	var mc struct {
+		Trials  int `json:"trials"`
+		Project struct {
+			OpenIssues int     `json:"open_issues"`
+			P50Days    float64 `json:"p50_days"`
+			P80Days    float64 `json:"p80_days"`
+			P95Days    float64 `json:"p95_days"`
+		} `json:"project"`
+		Issues []struct {
+			IssueID string `json:"issue_id"`
+		} `json:"issues"`
+		Labels []struct {
+			Name      string `json:"name"`
+			Histogram []any  `json:"histogram"`
+		} `json:"labels"`
+	}
+	if err := json.Unmarshal(out, &mc); err != nil {
+		t.Fatalf("json decode: %v\nout=%s", err, out)
+	}
+	if mc.Trials != 200 || mc.Project.OpenIssues != 1 {
+		t.Fatalf("unexpected trials/open issues: %+v", mc)
+	}
+	if len(mc.Issues) != 1 || mc.Issues[0].IssueID != "OPEN-1" {
+		t.Fatalf("expected only OPEN-1 to be simulated, got %+v", mc.Issues)
+	}
+	if mc.Project.P50Days > mc.Project.P80Days || mc.Project.P80Days > mc.Project.P95Days {
+		t.Fatalf("quantiles out of order: %+v", mc.Project)
+	}
+	if len(mc.Labels) != 1 || mc.Labels[0].Name != "backend" || len(mc.Labels[0].Histogram) == 0 {
+		t.Fatalf("expected a backend label group with a histogram, got %+v", mc.Labels)
+	}
+
+	// Equal seeds give equal forecasts; only the absolute dates move with the clock.
+	project := func(b []byte) string {
+		var m struct {
+			Project struct {
+				P50Days   float64 `json:"p50_days"`
+				P95Days   float64 `json:"p95_days"`
+				Histogram []any   `json:"histogram"`
+			} `json:"project"`
+		}
+		if err := json.Unmarshal(b, &m); err != nil {
+			t.Fatalf("json decode: %v", err)
+		}
+		return fmt.Sprint(m.Project)
+	}
+	a := project(run("OPEN-2", "--monte-carlo", "--forecast-seed", "7"))
+	b := project(run("OPEN-2", "--monte-carlo", "--forecast-seed", "7"))
+	if a != b {
+		t.Fatalf("same seed produced different forecasts:\n%s\n%s", a, b)
+	}
+}
+
 func mustParseRFC3339(t *testing.T, s string) time.Time {
 	t.Helper()
 	parsed, err := time.Parse(time.RFC3339, s)
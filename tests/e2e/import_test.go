@@ -0,0 +1,71 @@
+package main_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestImport_DryRunThenMergeIntoBeadsFile(t *testing.T) {
+	bv := buildBvBinary(t)
+	env := t.TempDir()
+
+	writeBeads(t, env, `{"id":"bd-1","title":"Existing","status":"open","priority":1,"issue_type":"task"}`)
+	export := filepath.Join(env, "issues.json")
+	if err := os.WriteFile(export, []byte(`[
+{"number":1,"title":"Parent","state":"OPEN","labels":[{"name":"epic"}],"url":"https://github.com/a/b/issues/1","createdAt":"2025-01-01T00:00:00Z"},
+{"number":2,"title":"Child","state":"OPEN","body":"Parent: #1\nBlocked by #1","url":"https://github.com/a/b/issues/2","createdAt":"2025-01-02T00:00:00Z",
+ "comments":[{"author":{"login":"alice"},"body":"hi","createdAt":"2025-01-02T01:00:00Z"}]}
+]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	beadsFile := filepath.Join(env, ".beads", "beads.jsonl")
+	before, _ := os.ReadFile(beadsFile)
+
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command(bv, args...)
+		cmd.Dir = env
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("bv %s failed: %v\n%s", strings.Join(args, " "), err, out)
+		}
+		return string(out)
+	}
+
+	out := run("import", "--from", "github-json", "--dry-run", export)
+	if !strings.Contains(out, "+ gh-1") || !strings.Contains(out, "+ gh-2") || !strings.Contains(out, "2 to add") {
+		t.Fatalf("dry run output:\n%s", out)
+	}
+	if after, _ := os.ReadFile(beadsFile); string(after) != string(before) {
+		t.Fatal("dry run modified the beads file")
+	}
+
+	// Flags may follow the file name.
+	out = run("import", export, "--from", "github-json")
+	if !strings.Contains(out, "2 added") {
+		t.Fatalf("import output:\n%s", out)
+	}
+	data, _ := os.ReadFile(beadsFile)
+	if lines := strings.Split(strings.TrimSpace(string(data)), "\n"); len(lines) != 3 {
+		t.Fatalf("beads file has %d lines:\n%s", len(lines), data)
+	}
+	if !strings.Contains(string(data), `"depends_on_id":"gh-1","type":"parent-child"`) ||
+		!strings.Contains(string(data), `"external_ref":"https://github.com/a/b/issues/2"`) {
+		t.Fatalf("beads file missing dependency or external_ref:\n%s", data)
+	}
+
+	// The imported issues load and analyze like any other.
+	out = run("--robot-triage")
+	if !strings.Contains(out, "gh-1") {
+		t.Fatalf("robot-triage does not see imported issues:\n%s", out)
+	}
+
+	// Importing the same export again changes nothing.
+	out = run("import", "--from", "github-json", export)
+	if !strings.Contains(out, "Nothing to import") {
+		t.Fatalf("re-import output:\n%s", out)
+	}
+}
@@ -0,0 +1,107 @@
+package main_test
+
+import (
+	"encoding/csv"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecipeExport_CSVAppliesRecipeAndRunsHooks(t *testing.T) {
+	bv := buildBvBinary(t)
+	env := t.TempDir()
+
+	writeBeads(t, env, `{"id":"bd-1","title":"Root","status":"open","priority":0,"issue_type":"task","labels":["api"]}
+{"id":"bd-2","title":"Child","status":"open","priority":2,"issue_type":"task","labels":["api"],"dependencies":[{"issue_id":"bd-2","depends_on_id":"bd-1","type":"blocks"}]}
+{"id":"bd-3","title":"Other","status":"open","priority":1,"issue_type":"task","labels":["ui"]}
+{"id":"bd-4","title":"Done","status":"closed","priority":0,"issue_type":"task","labels":["api"]}`)
+
+	if err := os.MkdirAll(filepath.Join(env, ".bv"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	recipes := `recipes:
+  api-report:
+    description: Open API work
+    filters:
+      status: [open]
+      tags: [api]
+    sort:
+      field: priority
+      direction: desc
+    view:
+      columns: [id, title, priority, blockers]
+    metrics: [pagerank, triage_score]
+`
+	hooksYAML := `hooks:
+  pre-export:
+    - name: pre
+      command: 'echo "$BV_EXPORT_FORMAT $BV_ISSUE_COUNT" > pre-hook.txt'
+  post-export:
+    - name: post
+      command: 'test -s "$BV_EXPORT_PATH" && echo ok > post-hook.txt'
+`
+	if err := os.WriteFile(filepath.Join(env, ".bv", "recipes.yaml"), []byte(recipes), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(env, ".bv", "hooks.yaml"), []byte(hooksYAML), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(bv, "-r", "api-report", "--export", "out.csv")
+	cmd.Dir = env
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("bv --export failed: %v\n%s", err, out)
+	}
+
+	f, err := os.Open(filepath.Join(env, "out.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatalf("read csv: %v", err)
+	}
+	if got := strings.Join(rows[0], ","); got != "id,title,priority,blockers,pagerank,triage_score" {
+		t.Fatalf("header = %s", got)
+	}
+	// Open api issues only, sorted by priority descending.
+	if len(rows) != 3 || rows[1][0] != "bd-2" || rows[2][0] != "bd-1" {
+		t.Fatalf("rows = %v", rows)
+	}
+	if rows[1][3] != "bd-1" || rows[1][4] == "" || rows[1][5] == "" {
+		t.Fatalf("bd-2 row = %v", rows[1])
+	}
+
+	pre, _ := os.ReadFile(filepath.Join(env, "pre-hook.txt"))
+	if strings.TrimSpace(string(pre)) != "csv 2" {
+		t.Fatalf("pre-export hook saw %q", pre)
+	}
+	if _, err := os.Stat(filepath.Join(env, "post-hook.txt")); err != nil {
+		t.Fatalf("post-export hook did not run: %v", err)
+	}
+}
+
+func TestRecipeExport_TemplateToStdout(t *testing.T) {
+	bv := buildBvBinary(t)
+	env := t.TempDir()
+	writeBeads(t, env, `{"id":"bd-1","title":"Root","status":"open","priority":0,"issue_type":"task"}
+{"id":"bd-2","title":"Child","status":"open","priority":1,"issue_type":"task","dependencies":[{"issue_id":"bd-2","depends_on_id":"bd-1","type":"blocks"}]}`)
+	tmpl := filepath.Join(env, "standup.tmpl")
+	if err := os.WriteFile(tmpl, []byte(`{{range .Issues}}{{.ID}} blocks={{.Metrics.BlocksCount}}
+{{end}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(bv, "--export", "-", "--export-template", tmpl, "--no-hooks")
+	cmd.Dir = env
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("bv --export-template failed: %v", err)
+	}
+	if got := string(out); got != "bd-1 blocks=1\nbd-2 blocks=0\n" {
+		t.Fatalf("stdout = %q", got)
+	}
+}
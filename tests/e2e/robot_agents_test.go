@@ -0,0 +1,86 @@
+package main_test
+
+import (
+	"encoding/json"
+	"os/exec"
+	"testing"
+)
+
+func TestRobotAgents_MetricsAndTriageSuggestions(t *testing.T) {
+	bv := buildBvBinary(t)
+	env := t.TempDir()
+
+	writeBeads(t, env, `{"id":"a","title":"Done","status":"closed","priority":1,"issue_type":"task","assignee":"alice","ack_status":"accepted","labels":["backend"],"created_at":"2025-01-01T00:00:00Z","updated_at":"2025-01-01T06:00:00Z","closed_at":"2025-01-01T06:00:00Z"}
+{"id":"b","title":"Declined","status":"open","priority":1,"issue_type":"task","assignee":"bob","ack_status":"declined","comments":[{"id":1,"issue_id":"b","author":"bob","text":"[DECLINED] Too big (bounce 1/3)","created_at":"2025-01-02T00:00:00Z"}]}
+{"id":"c","title":"Backend work","status":"open","priority":0,"issue_type":"task","labels":["backend"]}`)
+
+	cmd := exec.Command(bv, "--robot-agents")
+	cmd.Dir = env
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("--robot-agents failed: %v\n%s", err, out)
+	}
+	var payload struct {
+		DataHash         string `json:"data_hash"`
+		RosterSource     string `json:"roster_source"`
+		HistoryAvailable bool   `json:"history_available"`
+		Agents           []struct {
+			Agent                string   `json:"agent"`
+			Offers               int      `json:"offers"`
+			AcceptanceRate       float64  `json:"acceptance_rate"`
+			BounceRate           float64  `json:"bounce_rate"`
+			MedianCycleTimeHours *float64 `json:"median_cycle_time_hours"`
+			Labels               []struct {
+				Label string `json:"label"`
+			} `json:"labels"`
+		} `json:"agents"`
+		UsageHints []string `json:"usage_hints"`
+	}
+	if err := json.Unmarshal(out, &payload); err != nil {
+		t.Fatalf("json decode: %v\nout=%s", err, out)
+	}
+	if payload.DataHash == "" || len(payload.UsageHints) == 0 || payload.RosterSource != "assignees" || payload.HistoryAvailable {
+		t.Fatalf("unexpected metadata: %s", out)
+	}
+	if len(payload.Agents) != 2 {
+		t.Fatalf("agents = %+v", payload.Agents)
+	}
+	alice, bob := payload.Agents[0], payload.Agents[1]
+	if alice.Agent != "alice" || alice.AcceptanceRate != 1 || alice.MedianCycleTimeHours == nil || *alice.MedianCycleTimeHours != 6 ||
+		len(alice.Labels) != 1 || alice.Labels[0].Label != "backend" {
+		t.Errorf("alice = %+v", alice)
+	}
+	if bob.Agent != "bob" || bob.Offers != 1 || bob.BounceRate != 1 {
+		t.Errorf("bob = %+v", bob)
+	}
+
+	cmd = exec.Command(bv, "--robot-triage", "--robot-triage-agents")
+	cmd.Dir = env
+	out, err = cmd.Output()
+	if err != nil {
+		t.Fatalf("--robot-triage --robot-triage-agents failed: %v\n%s", err, out)
+	}
+	var triage struct {
+		Triage struct {
+			Recommendations []struct {
+				ID              string `json:"id"`
+				SuggestedAgents []struct {
+					Agent string `json:"agent"`
+				} `json:"suggested_agents"`
+			} `json:"recommendations"`
+		} `json:"triage"`
+	}
+	if err := json.Unmarshal(out, &triage); err != nil {
+		t.Fatalf("json decode: %v\nout=%s", err, out)
+	}
+	for _, rec := range triage.Triage.Recommendations {
+		if rec.ID != "c" {
+			continue
+		}
+		if len(rec.SuggestedAgents) == 0 || rec.SuggestedAgents[0].Agent != "alice" {
+			t.Errorf("c should go to alice first: %+v", rec.SuggestedAgents)
+		}
+		return
+	}
+	t.Fatalf("c not recommended: %s", out)
+}
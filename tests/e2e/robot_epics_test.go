@@ -0,0 +1,59 @@
+package main_test
+
+import (
+	"encoding/json"
+	"os/exec"
+	"testing"
+)
+
+func TestRobotEpics_RollsUpHierarchy(t *testing.T) {
+	bv := buildBvBinary(t)
+	env := t.TempDir()
+
+	writeBeads(t, env, `{"id":"E","title":"Epic","status":"open","priority":1,"issue_type":"epic"}
+{"id":"E.1","title":"Done","status":"closed","priority":1,"issue_type":"task","estimated_minutes":180,"dependencies":[{"issue_id":"E.1","depends_on_id":"E","type":"parent-child"}]}
+{"id":"E.2","title":"Waiting","status":"open","priority":1,"issue_type":"task","estimated_minutes":60,"dependencies":[{"issue_id":"E.2","depends_on_id":"E","type":"parent-child"},{"issue_id":"E.2","depends_on_id":"X","type":"blocks"}]}
+{"id":"X","title":"Blocker","status":"open","priority":0,"issue_type":"task"}
+{"id":"F","title":"Finished epic","status":"open","priority":2,"issue_type":"epic"}
+{"id":"F.1","title":"Only child","status":"closed","priority":2,"issue_type":"task","dependencies":[{"issue_id":"F.1","depends_on_id":"F","type":"parent-child"}]}`)
+
+	cmd := exec.Command(bv, "--robot-epics")
+	cmd.Dir = env
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("--robot-epics failed: %v\n%s", err, out)
+	}
+	var payload struct {
+		DataHash string `json:"data_hash"`
+		Epics    []struct {
+			ID              string   `json:"id"`
+			Descendants     int      `json:"descendants"`
+			Closed          int      `json:"closed"`
+			PercentDone     float64  `json:"percent_done"`
+			BlockedChildren []string `json:"blocked_children"`
+			ForecastDate    *string  `json:"forecast_date"`
+			ReadyToClose    bool     `json:"ready_to_close"`
+		} `json:"epics"`
+		ReadyToClose []string `json:"ready_to_close"`
+		UsageHints   []string `json:"usage_hints"`
+	}
+	if err := json.Unmarshal(out, &payload); err != nil {
+		t.Fatalf("json decode: %v\nout=%s", err, out)
+	}
+	if payload.DataHash == "" || len(payload.UsageHints) == 0 {
+		t.Fatalf("missing metadata: %s", out)
+	}
+	if len(payload.Epics) != 2 || payload.Epics[0].ID != "E" || payload.Epics[1].ID != "F" {
+		t.Fatalf("epics = %+v", payload.Epics)
+	}
+	e := payload.Epics[0]
+	if e.Descendants != 2 || e.Closed != 1 || e.PercentDone != 75 || e.ForecastDate == nil || e.ReadyToClose {
+		t.Errorf("E = %+v", e)
+	}
+	if len(e.BlockedChildren) != 1 || e.BlockedChildren[0] != "E.2" {
+		t.Errorf("E blocked children = %v", e.BlockedChildren)
+	}
+	if len(payload.ReadyToClose) != 1 || payload.ReadyToClose[0] != "F" {
+		t.Errorf("ready_to_close = %v", payload.ReadyToClose)
+	}
+}
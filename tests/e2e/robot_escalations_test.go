@@ -0,0 +1,73 @@
+package main_test
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestRobotEscalations_QueueHistoryAndRoster(t *testing.T) {
+	bv := buildBvBinary(t)
+	env := t.TempDir()
+
+	writeBeads(t, env, `{"id":"a","title":"Impossible","status":"open","priority":1,"issue_type":"task","assignee":"bob","ack_status":"impossible","escalated":true,"labels":["backend"],"comments":[{"id":1,"issue_id":"a","author":"alice","text":"[DECLINED] Too big (bounce 1/3)","created_at":"2025-01-01T00:00:00Z"},{"id":2,"issue_id":"a","author":"bob","text":"[IMPOSSIBLE] Needs prod access - Escalated for strategic decision","created_at":"2025-01-02T00:00:00Z"}]}
+{"id":"b","title":"Late","status":"open","priority":0,"issue_type":"task","assignee":"carol","deadline":"2025-01-01T00:00:00Z"}
+{"id":"c","title":"Accepted","status":"in_progress","priority":0,"issue_type":"task","assignee":"carol","ack_status":"accepted","deadline":"2025-01-01T00:00:00Z"}`)
+
+	if err := os.MkdirAll(filepath.Join(env, ".bv"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	roster := "agents:\n  - name: alice\n  - name: dan\n    skills: [backend]\n  - name: erin\n    skills: [frontend]\n"
+	if err := os.WriteFile(filepath.Join(env, ".bv", "roster.yaml"), []byte(roster), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(bv, "--robot-escalations")
+	cmd.Dir = env
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("--robot-escalations failed: %v\n%s", err, out)
+	}
+	var payload struct {
+		DataHash     string `json:"data_hash"`
+		RosterSource string `json:"roster_source"`
+		Items        []struct {
+			ID         string `json:"id"`
+			Kind       string `json:"kind"`
+			LastReason string `json:"last_reason"`
+			History    []struct {
+				Kind  string `json:"kind"`
+				Agent string `json:"agent"`
+			} `json:"history"`
+			Suggested []struct {
+				Agent string `json:"agent"`
+			} `json:"suggested_assignees"`
+		} `json:"items"`
+		Impossible int      `json:"impossible"`
+		Overdue    int      `json:"overdue"`
+		UsageHints []string `json:"usage_hints"`
+	}
+	if err := json.Unmarshal(out, &payload); err != nil {
+		t.Fatalf("json decode: %v\nout=%s", err, out)
+	}
+	if payload.DataHash == "" || len(payload.UsageHints) == 0 || payload.RosterSource != ".bv/roster.yaml" {
+		t.Fatalf("missing metadata: %s", out)
+	}
+	if len(payload.Items) != 2 || payload.Impossible != 1 || payload.Overdue != 1 {
+		t.Fatalf("items = %+v", payload.Items)
+	}
+
+	a := payload.Items[0]
+	if a.ID != "a" || a.Kind != "impossible" || a.LastReason != "Needs prod access" || len(a.History) != 2 {
+		t.Errorf("a = %+v", a)
+	}
+	// alice declined, bob gave up, erin lacks the skill.
+	if len(a.Suggested) != 1 || a.Suggested[0].Agent != "dan" {
+		t.Errorf("a suggestions = %+v", a.Suggested)
+	}
+	if b := payload.Items[1]; b.ID != "b" || b.Kind != "overdue" {
+		t.Errorf("b = %+v", b)
+	}
+}
@@ -0,0 +1,109 @@
+package main_test
+
+import (
+	"encoding/json"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestRobotQueryPreFilter(t *testing.T) {
+	bv := buildBvBinary(t)
+	env := t.TempDir()
+	writeBeads(t, env, `{"id":"A","title":"Root api","status":"open","priority":1,"issue_type":"task","labels":["api"]}
+{"id":"B","title":"Mid","status":"open","priority":2,"issue_type":"task","labels":["api","wontfix"],"dependencies":[{"issue_id":"B","depends_on_id":"A","type":"blocks"}]}
+{"id":"C","title":"Leaf","status":"closed","priority":3,"issue_type":"task","dependencies":[{"issue_id":"C","depends_on_id":"B","type":"blocks"}]}`)
+
+	graphIDs := func(q string) []string {
+		t.Helper()
+		cmd := exec.Command(bv, "--robot-graph", "--graph-format=json", "--query", q)
+		cmd.Dir = env
+		out, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("--query %q failed: %v\n%s", q, err, out)
+		}
+		var payload struct {
+			Adjacency struct {
+				Nodes []struct {
+					ID string `json:"id"`
+				} `json:"nodes"`
+			} `json:"adjacency"`
+		}
+		if err := json.Unmarshal(out, &payload); err != nil {
+			t.Fatalf("decode: %v\n%s", err, out)
+		}
+		var ids []string
+		for _, n := range payload.Adjacency.Nodes {
+			ids = append(ids, n.ID)
+		}
+		return ids
+	}
+
+	if got := strings.Join(graphIDs("label:api -label:wontfix"), ","); got != "A" {
+		t.Errorf("label query nodes = %q, want A", got)
+	}
+	if got := strings.Join(graphIDs("is:blocked OR status:closed"), ","); got != "B,C" {
+		t.Errorf("state query nodes = %q, want B,C", got)
+	}
+	if got := strings.Join(graphIDs("pagerank>0 is:open priority<=1"), ","); got != "A" {
+		t.Errorf("metric query nodes = %q, want A", got)
+	}
+
+	cmd := exec.Command(bv, "--robot-triage", "--query", "label>2")
+	cmd.Dir = env
+	out, err := cmd.CombinedOutput()
+	if err == nil || !strings.Contains(string(out), "label does not support >") {
+		t.Errorf("invalid query should fail with a parse error, got err=%v out=%s", err, out)
+	}
+}
+
+func TestRobotQueryKeepsOutsideBlockers(t *testing.T) {
+	bv := buildBvBinary(t)
+	env := t.TempDir()
+	writeBeads(t, env, `{"id":"bd-1","title":"API endpoint","status":"open","priority":0,"issue_type":"task","labels":["api"],"dependencies":[{"issue_id":"bd-1","depends_on_id":"bd-2","type":"blocks"}]}
+{"id":"bd-2","title":"Schema migration","status":"open","priority":2,"issue_type":"task"}
+{"id":"bd-3","title":"API docs","status":"open","priority":3,"issue_type":"task","labels":["api"]}`)
+
+	run := func(args ...string) []byte {
+		t.Helper()
+		cmd := exec.Command(bv, args...)
+		cmd.Dir = env
+		out, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("%v failed: %v\n%s", args, err, out)
+		}
+		return out
+	}
+
+	var next struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(run("--robot-next", "--query", "label:api"), &next); err != nil {
+		t.Fatal(err)
+	}
+	if next.ID != "bd-3" {
+		t.Errorf("--robot-next picked %q, want bd-3 (bd-1 is blocked by bd-2)", next.ID)
+	}
+
+	var plan struct {
+		Plan struct {
+			Tracks []struct {
+				Items []struct {
+					ID string `json:"id"`
+				} `json:"items"`
+			} `json:"tracks"`
+		} `json:"plan"`
+	}
+	if err := json.Unmarshal(run("--robot-plan", "--query", "label:api"), &plan); err != nil {
+		t.Fatal(err)
+	}
+	var planned []string
+	for _, track := range plan.Plan.Tracks {
+		for _, item := range track.Items {
+			planned = append(planned, item.ID)
+		}
+	}
+	if got := strings.Join(planned, ","); got != "bd-3" {
+		t.Errorf("--robot-plan items = %q, want bd-3", got)
+	}
+}
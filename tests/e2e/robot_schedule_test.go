@@ -0,0 +1,93 @@
+package main_test
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+type scheduleOutput struct {
+	RosterSource    string `json:"roster_source"`
+	MakespanMinutes int    `json:"makespan_minutes"`
+	Agents          []struct {
+		Name   string `json:"name"`
+		Issues []struct {
+			IssueID      string `json:"issue_id"`
+			StartMinutes int    `json:"start_minutes"`
+			EndMinutes   int    `json:"end_minutes"`
+		} `json:"issues"`
+	} `json:"agents"`
+	Unscheduled []struct {
+		IssueID string `json:"issue_id"`
+		Reason  string `json:"reason"`
+	} `json:"unscheduled"`
+}
+
+func TestRobotSchedule_UsesRosterAndDependencies(t *testing.T) {
+	bv := buildBvBinary(t)
+	env := t.TempDir()
+
+	writeBeads(t, env, `{"id":"A","title":"A","status":"open","priority":1,"issue_type":"task","estimated_minutes":240}
+{"id":"B","title":"B","status":"open","priority":1,"issue_type":"task","estimated_minutes":240,"dependencies":[{"issue_id":"B","depends_on_id":"A","type":"blocks"}]}
+{"id":"C","title":"C","status":"open","priority":2,"issue_type":"task","estimated_minutes":120,"labels":["frontend"]}
+{"id":"D","title":"D","status":"open","priority":2,"issue_type":"task","estimated_minutes":120,"labels":["database"]}`)
+
+	run := func() scheduleOutput {
+		t.Helper()
+		cmd := exec.Command(bv, "--robot-schedule")
+		cmd.Dir = env
+		out, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("--robot-schedule failed: %v\n%s", err, out)
+		}
+		var payload scheduleOutput
+		if err := json.Unmarshal(out, &payload); err != nil {
+			t.Fatalf("json decode: %v\nout=%s", err, out)
+		}
+		return payload
+	}
+
+	// No roster: a single generic agent works through everything in order.
+	fallback := run()
+	if fallback.RosterSource != "assignees" || len(fallback.Agents) != 1 {
+		t.Fatalf("fallback roster = %q with %d agents", fallback.RosterSource, len(fallback.Agents))
+	}
+	// Graph factors can stretch estimates, never shrink them below the sum.
+	if fallback.MakespanMinutes < 720 {
+		t.Fatalf("fallback makespan = %d, want at least 720", fallback.MakespanMinutes)
+	}
+
+	if err := os.MkdirAll(filepath.Join(env, ".bv"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	roster := "agents:\n  - name: ann\n  - name: fe\n    skills: [frontend]\n"
+	if err := os.WriteFile(filepath.Join(env, ".bv", "roster.yaml"), []byte(roster), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := run()
+	if got.RosterSource != ".bv/roster.yaml" || len(got.Agents) != 2 {
+		t.Fatalf("roster = %q with %d agents", got.RosterSource, len(got.Agents))
+	}
+	where := map[string]string{}
+	ends := map[string]int{}
+	starts := map[string]int{}
+	for _, a := range got.Agents {
+		for _, si := range a.Issues {
+			where[si.IssueID] = a.Name
+			starts[si.IssueID] = si.StartMinutes
+			ends[si.IssueID] = si.EndMinutes
+		}
+	}
+	if where["C"] != "fe" {
+		t.Errorf("C scheduled on %q, want the frontend agent", where["C"])
+	}
+	if starts["B"] < ends["A"] {
+		t.Errorf("B starts at %d before A ends at %d", starts["B"], ends["A"])
+	}
+	if got.MakespanMinutes >= fallback.MakespanMinutes {
+		t.Errorf("two agents should finish sooner: %d vs %d", got.MakespanMinutes, fallback.MakespanMinutes)
+	}
+}
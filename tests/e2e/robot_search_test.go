@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"os/exec"
+	"strings"
 	"testing"
 )
 
@@ -81,3 +82,47 @@ func TestRobotSearchContract(t *testing.T) {
 		t.Fatalf("expected usage_hints")
 	}
 }
+
+func TestRobotSearchReportsMatchedChunk(t *testing.T) {
+	bv := buildBvBinary(t)
+	env := t.TempDir()
+	writeBeads(t, env, `{"id":"A","title":"Checkout flow","description":"Payment page polish","status":"open","priority":1,"issue_type":"task","comments":[{"id":42,"issue_id":"A","author":"sam","text":"The quasarbeacon quasarbeacon quasarbeacon retry path double-charges","created_at":"2025-01-01T00:00:00Z"}]}
+{"id":"B","title":"Docs","description":"readme changelog docs","design":"Split the readme into guides","status":"open","priority":2,"issue_type":"task"}`)
+
+	cmd := exec.Command(bv, "--search", "quasarbeacon", "--robot-search")
+	cmd.Dir = env
+	cmd.Env = append(os.Environ(), "BV_SEMANTIC_EMBEDDER=hash", "BV_SEMANTIC_DIM=2048")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("robot-search failed: %v\n%s", err, out)
+	}
+
+	var payload struct {
+		Index struct {
+			Total int `json:"total"`
+		} `json:"index"`
+		Results []struct {
+			IssueID   string `json:"issue_id"`
+			Field     string `json:"field"`
+			CommentID int64  `json:"comment_id"`
+			Chunk     string `json:"chunk"`
+			Snippet   string `json:"snippet"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(out, &payload); err != nil {
+		t.Fatalf("robot-search json decode: %v\nout=%s", err, out)
+	}
+	if payload.Index.Total != 4 {
+		t.Errorf("indexed %d chunks, want 2 summaries + comment + design", payload.Index.Total)
+	}
+	if len(payload.Results) == 0 {
+		t.Fatalf("expected results")
+	}
+	top := payload.Results[0]
+	if top.IssueID != "A" || top.Field != "comment" || top.CommentID != 42 || top.Chunk != "A#comment-42" {
+		t.Fatalf("top result = %+v, want A's comment 42", top)
+	}
+	if top.Snippet == "" || !strings.Contains(top.Snippet, "double-charges") {
+		t.Errorf("snippet = %q", top.Snippet)
+	}
+}
@@ -0,0 +1,125 @@
+package main_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWatchAlertsOnce_DeliversEachAlertOnce(t *testing.T) {
+	bv := buildBvBinary(t)
+	env := t.TempDir()
+
+	now := time.Now().UTC()
+	stale := now.AddDate(0, 0, -20).Format(time.RFC3339)
+	fresh := now.AddDate(0, 0, -1).Format(time.RFC3339)
+	issues := fmt.Sprintf(
+		`{"id":"FRESH","title":"Fresh","status":"open","priority":1,"issue_type":"task","created_at":"%s","updated_at":"%s"}
+{"id":"STALE","title":"Stale issue","status":"open","priority":3,"issue_type":"task","created_at":"%s","updated_at":"%s"}`,
+		fresh, fresh, stale, stale)
+	writeBeads(t, env, issues)
+
+	if err := os.MkdirAll(filepath.Join(env, ".bv"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	config := "sinks:\n  - name: log\n    type: file\n    path: .bv/alerts.log\n    types: [stale_issue]\n"
+	if err := os.WriteFile(filepath.Join(env, ".bv", "notify.yaml"), []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func() {
+		t.Helper()
+		cmd := exec.Command(bv, "watch-alerts", "--once")
+		cmd.Dir = env
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("watch-alerts --once failed: %v\n%s", err, out)
+		}
+	}
+	readLog := func() []map[string]any {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(env, ".bv", "alerts.log"))
+		if err != nil {
+			t.Fatalf("reading alert log: %v", err)
+		}
+		var events []map[string]any
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			var ev map[string]any
+			if err := json.Unmarshal([]byte(line), &ev); err != nil {
+				t.Fatalf("bad log line %q: %v", line, err)
+			}
+			events = append(events, ev)
+		}
+		return events
+	}
+
+	run()
+	events := readLog()
+	if len(events) != 1 || events[0]["issue_id"] != "STALE" || events[0]["key"] != "stale_issue|STALE|" {
+		t.Fatalf("first run logged %v, want one stale_issue alert for STALE", events)
+	}
+
+	// Same data: nothing new to send.
+	run()
+	if events := readLog(); len(events) != 1 {
+		t.Fatalf("second run re-sent alerts: %v", events)
+	}
+	if _, err := os.Stat(filepath.Join(env, ".bv", "notify-state.json")); err != nil {
+		t.Fatalf("expected dedup state file: %v", err)
+	}
+}
+
+func TestWatchAlerts_RequiresSinks(t *testing.T) {
+	bv := buildBvBinary(t)
+	env := t.TempDir()
+	writeBeads(t, env, `{"id":"A","title":"A","status":"open","priority":1,"issue_type":"task"}`)
+
+	cmd := exec.Command(bv, "watch-alerts", "--once")
+	cmd.Dir = env
+	out, err := cmd.CombinedOutput()
+	if err == nil || !strings.Contains(string(out), "no sinks configured") {
+		t.Fatalf("expected a missing-sinks error, got err=%v\n%s", err, out)
+	}
+}
+
+func TestWatchAlertsOnce_RunsOnLoadHookWithoutSinks(t *testing.T) {
+	bv := buildBvBinary(t)
+	env := t.TempDir()
+	writeBeads(t, env, `{"id":"A","title":"A","status":"open","priority":1,"issue_type":"task"}
+{"id":"B","title":"B","status":"open","priority":1,"issue_type":"task","dependencies":[{"issue_id":"B","depends_on_id":"A","type":"blocks"}]}`)
+
+	if err := os.MkdirAll(filepath.Join(env, ".bv"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	hooksYAML := "hooks:\n  on-load:\n    - name: capture\n      command: cat > .bv/loaded.json\n"
+	if err := os.WriteFile(filepath.Join(env, ".bv", "hooks.yaml"), []byte(hooksYAML), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(bv, "watch-alerts", "--once")
+	cmd.Dir = env
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("watch-alerts --once failed: %v\n%s", err, out)
+	}
+
+	data, err := os.ReadFile(filepath.Join(env, ".bv", "loaded.json"))
+	if err != nil {
+		t.Fatalf("on-load hook did not run: %v", err)
+	}
+	var ev struct {
+		Event         string   `json:"event"`
+		Phase         string   `json:"phase"`
+		IssueCount    int      `json:"issue_count"`
+		ActionableIDs []string `json:"actionable_ids"`
+	}
+	if err := json.Unmarshal(data, &ev); err != nil {
+		t.Fatalf("bad payload %q: %v", data, err)
+	}
+	if ev.Event != "loaded" || ev.Phase != "on-load" || ev.IssueCount != 2 || strings.Join(ev.ActionableIDs, ",") != "A" {
+		t.Errorf("payload = %+v", ev)
+	}
+}